@@ -1,51 +1,272 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
+	"path/filepath"
 	"regexp"
 	"strings"
 
 	"github.com/spf13/cobra"
 
+	"brigade/internal/config"
+	"brigade/internal/cost"
 	"brigade/internal/prd"
+	"brigade/internal/util"
 )
 
 var analyzeCmd = &cobra.Command{
 	Use:   "analyze <prd.json>",
-	Short: "Show task analysis with complexity suggestions",
-	Args:  cobra.ExactArgs(1),
+	Short: "Deep PRD analysis: graph, tiers, verification, lint, risk, cost",
+	Long: `Analyzes a PRD before execution. Combines validation lint, risk and cost
+estimates with dependency graph metrics: depth, width, bottleneck tasks,
+critical path, and the ideal parallel speedup.`,
+	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return cmdAnalyze(args[0])
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+		return cmdAnalyze(args[0], jsonOutput)
 	},
 }
 
-func cmdAnalyze(prdPath string) error {
-	p, err := prd.Load(prdPath)
-	if err != nil {
-		return err
+func init() {
+	analyzeCmd.Flags().Bool("json", false, "output as JSON")
+}
+
+// taskMinutesBasis describes where each complexity's expected task time
+// comes from: earlier runs once there are enough of them, otherwise the
+// built-in guess.
+func taskMinutesBasis(stats *cost.Stats) []string {
+	var basis []string
+	for _, c := range []prd.Complexity{prd.ComplexityJunior, prd.ComplexitySenior} {
+		task := &prd.Task{Complexity: c}
+		if g := stats.Complexity[c]; g != nil && g.Minutes.Count >= cost.MinStatSamples {
+			basis = append(basis, fmt.Sprintf("%s ~%.0f min (p50 of %d earlier tasks)", c, g.Minutes.P50, g.Minutes.Count))
+		} else {
+			basis = append(basis, fmt.Sprintf("%s ~%.0f min (default)", c, cost.TaskMinutes(task)))
+		}
 	}
+	return basis
+}
+
+// verificationTypes are the heatmap columns, in display order.
+var verificationTypes = []prd.VerificationType{
+	prd.VerificationPattern,
+	prd.VerificationUnit,
+	prd.VerificationIntegration,
+	prd.VerificationSmoke,
+	prd.VerificationBrowser,
+}
 
-	fmt.Printf("%sTask Analysis:%s\n\n", colorBold, colorReset)
+// prdAnalysis is the full analysis report.
+type prdAnalysis struct {
+	PRD         string             `json:"prd"`
+	FeatureName string             `json:"featureName"`
+	TotalTasks  int                `json:"totalTasks"`
+	Graph       *prd.GraphMetrics  `json:"graph,omitempty"`
+	TaskMinutes []string           `json:"taskMinutes"` // What the graph's task weights are based on
+	Tiers       map[string]int     `json:"tiers"`
+	Coverage    []taskCoverage     `json:"verificationCoverage"`
+	Uncovered   []uncoveredTask    `json:"uncoveredCriteria"`
+	Errors      []string           `json:"errors"`
+	Warnings    []string           `json:"warnings"`
+	Risk        riskReport         `json:"risk"`
+	Cost        costEstimate       `json:"cost"`
+	Suggestions []complexityChange `json:"complexitySuggestions"`
+}
+
+// taskCoverage counts verification commands by type for one task.
+type taskCoverage struct {
+	TaskID string         `json:"taskId"`
+	Counts map[string]int `json:"counts"`
+	Manual bool           `json:"manual,omitempty"`
+}
+
+// uncoveredTask lists a task's acceptance criteria that nothing verifies.
+type uncoveredTask struct {
+	TaskID   string               `json:"taskId"`
+	Criteria []prd.CriterionTrace `json:"criteria"`
+}
+
+// complexityChange is a suggested tier for an auto-complexity task.
+type complexityChange struct {
+	TaskID    string `json:"taskId"`
+	Suggested string `json:"suggested"`
+}
+
+func analyzePRD(p *prd.PRD, cfg *config.Config) *prdAnalysis {
+	stats := cost.LoadStats(filepath.Dir(p.Path()), p.Path())
+	a := &prdAnalysis{
+		PRD:         p.Prefix(),
+		FeatureName: p.FeatureName,
+		TotalTasks:  len(p.Tasks),
+		Graph:       p.AnalyzeGraph(stats.TaskMinutes),
+		TaskMinutes: taskMinutesBasis(stats),
+		Tiers:       map[string]int{},
+		Uncovered:   []uncoveredTask{},
+		Errors:      []string{},
+		Warnings:    []string{},
+		Risk:        computeRisk(p),
+		Cost:        computeCost(p, cfg),
+	}
 
 	for i := range p.Tasks {
 		task := &p.Tasks[i]
-		suggested := suggestComplexity(task)
 		complexity := string(task.Complexity)
 		if complexity == "" {
-			complexity = "auto"
+			complexity = string(prd.ComplexityAuto)
 		}
+		a.Tiers[complexity]++
 
-		fmt.Printf("  %s: %s\n", task.ID, task.Title)
-		if complexity == "auto" {
-			fmt.Printf("    %sCriteria: %d | Suggested: %s%s%s\n",
-				colorDim, len(task.AcceptanceCriteria), colorCyan, suggested, colorReset)
-		} else {
-			fmt.Printf("    %sCriteria: %d | Assigned: %s%s%s\n",
-				colorDim, len(task.AcceptanceCriteria), colorCyan, complexity, colorReset)
+		if task.Complexity == prd.ComplexityAuto || task.Complexity == "" {
+			a.Suggestions = append(a.Suggestions, complexityChange{TaskID: task.ID, Suggested: suggestComplexity(task)})
+		}
+
+		cov := taskCoverage{TaskID: task.ID, Counts: map[string]int{}, Manual: task.ManualVerification}
+		for _, v := range task.Verification {
+			vt := v.Type
+			if vt == "" {
+				vt = prd.VerificationPattern
+			}
+			cov.Counts[string(vt)]++
+		}
+		a.Coverage = append(a.Coverage, cov)
+
+		var uncovered []prd.CriterionTrace
+		for _, trace := range task.Trace() {
+			if !trace.Covered() {
+				uncovered = append(uncovered, trace)
+			}
+		}
+		if len(uncovered) > 0 {
+			a.Uncovered = append(a.Uncovered, uncoveredTask{TaskID: task.ID, Criteria: uncovered})
+		}
+	}
+
+	result := p.ValidateFull(prd.ValidationOptions{
+		LintCriteria:           true,
+		CheckVerificationTypes: true,
+		WarnGrepOnly:           cfg.VerificationWarnGrepOnly,
+	})
+	for _, e := range result.Errors {
+		a.Errors = append(a.Errors, e.Error())
+	}
+	for _, w := range result.Warnings {
+		a.Warnings = append(a.Warnings, w.Error())
+	}
+
+	return a
+}
+
+func cmdAnalyze(prdPath string, jsonOutput bool) error {
+	p, err := prd.Load(prdPath)
+	if err != nil {
+		return err
+	}
+	cfg, err := config.Load(cfgFile)
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+
+	a := analyzePRD(p, cfg)
+
+	if jsonOutput {
+		data, _ := json.MarshalIndent(a, "", "  ")
+		fmt.Println(string(data))
+		return nil
+	}
+
+	fmt.Printf("%s=== Analysis: %s ===%s\n\n", colorBold, a.FeatureName, colorReset)
+
+	// Graph
+	fmt.Printf("%sDependency Graph:%s\n", colorBold, colorReset)
+	if a.Graph == nil {
+		fmt.Printf("  %s✗ Circular dependency - graph metrics unavailable%s\n", colorRed, colorReset)
+	} else {
+		fmt.Printf("  Depth: %d levels | Width: %d | Tasks: %d\n", a.Graph.Depth, a.Graph.Width, a.TotalTasks)
+		fmt.Printf("  Critical path: %s (~%.0f min)\n", strings.Join(a.Graph.CriticalPath, " → "), a.Graph.CriticalWeight)
+		fmt.Printf("  Parallel speedup: %.1fx ideal (%.0f min sequential)\n", a.Graph.ParallelSpeedup, a.Graph.TotalWeight)
+		fmt.Printf("  %sTask times: %s%s\n", colorDim, strings.Join(a.TaskMinutes, "; "), colorReset)
+		if len(a.Graph.Bottlenecks) > 0 {
+			fmt.Println("  Bottlenecks:")
+			for _, b := range a.Graph.Bottlenecks {
+				fmt.Printf("    %s gates %d downstream task(s)\n", b.TaskID, b.Downstream)
+			}
 		}
 	}
+	fmt.Println()
 
+	// Tiers
+	fmt.Printf("%sTier Distribution:%s\n", colorBold, colorReset)
+	for _, c := range []prd.Complexity{prd.ComplexityJunior, prd.ComplexitySenior, prd.ComplexityAuto} {
+		if n := a.Tiers[string(c)]; n > 0 {
+			fmt.Printf("  %-7s %s %d\n", c, strings.Repeat("█", n), n)
+		}
+	}
+	for _, s := range a.Suggestions {
+		fmt.Printf("  %s%s (auto) → suggested %s%s\n", colorDim, s.TaskID, s.Suggested, colorReset)
+	}
 	fmt.Println()
+
+	// Verification heatmap
+	fmt.Printf("%sVerification Coverage:%s\n", colorBold, colorReset)
+	fmt.Printf("  %-10s", "")
+	for _, vt := range verificationTypes {
+		fmt.Printf(" %-11s", vt)
+	}
+	fmt.Println()
+	for _, cov := range a.Coverage {
+		fmt.Printf("  %-10s", cov.TaskID)
+		for _, vt := range verificationTypes {
+			n := cov.Counts[string(vt)]
+			cell := colorDim + "·" + colorReset
+			if n > 0 {
+				cell = colorGreen + strings.Repeat("■", min(n, 3)) + colorReset
+			}
+			fmt.Printf(" %s%s", cell, strings.Repeat(" ", 11-min(max(n, 1), 3)))
+		}
+		if cov.Manual {
+			fmt.Printf("%s(manual)%s", colorDim, colorReset)
+		}
+		fmt.Println()
+	}
+	fmt.Println()
+
+	// Traceability
+	fmt.Printf("%sCriteria Traceability:%s\n", colorBold, colorReset)
+	if len(a.Uncovered) == 0 {
+		fmt.Printf("  %s✓%s Every criterion has a verification command or manual check\n", colorGreen, colorReset)
+	}
+	for _, u := range a.Uncovered {
+		for _, c := range u.Criteria {
+			fmt.Printf("  %s○%s %s #%d %s\n", colorYellow, colorReset, u.TaskID, c.Index, c.Criterion)
+		}
+	}
+	fmt.Println()
+
+	// Lint
+	fmt.Printf("%sLint:%s\n", colorBold, colorReset)
+	if len(a.Errors) == 0 && len(a.Warnings) == 0 {
+		fmt.Printf("  %s✓%s No findings\n", colorGreen, colorReset)
+	}
+	for _, e := range a.Errors {
+		fmt.Printf("  %s✗%s %s\n", colorRed, colorReset, e)
+	}
+	for _, w := range a.Warnings {
+		fmt.Printf("  %s⚠%s %s\n", colorYellow, colorReset, w)
+	}
+	fmt.Println()
+
+	// Risk and cost
+	fmt.Printf("%sRisk:%s %s (score: %d)\n", colorBold, colorReset, a.Risk.Level, a.Risk.Score)
+	for _, issue := range a.Risk.Issues {
+		fmt.Printf("  - %s\n", issue)
+	}
+	fmt.Printf("%sEstimated cost:%s %s", colorBold, colorReset, util.FormatCost(a.Cost.Total))
+	if a.Cost.OverThreshold {
+		fmt.Printf(" %s(exceeds %s threshold)%s", colorYellow, util.FormatCost(a.Cost.Threshold), colorReset)
+	}
+	fmt.Println()
+
 	return nil
 }
 
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"brigade/internal/chef"
+	"brigade/internal/config"
+)
+
+var chefCmd = &cobra.Command{
+	Use:   "chef",
+	Short: "Check the chef prompts workers are given",
+}
+
+var chefLintCmd = &cobra.Command{
+	Use:   "lint [chef-dir]",
+	Short: "Check chef prompts for missing files, signals and config contradictions",
+	Long: `Checks the chef prompt of each tier (line.md, sous.md, executive.md):
+
+  - the file exists and isn't empty
+  - it teaches the signals the orchestrator parses (<promise>COMPLETE</promise>,
+    <promise>BLOCKED</promise>, and for line and sous cooks <approach> and
+    <learning>), and no signal it doesn't recognize
+  - it fits in CHEF_PROMPT_MAX_TOKENS (estimated at four characters a token)
+  - it doesn't contradict the config, such as claiming verification won't
+    run while VERIFICATION_ENABLED is on
+
+The directory defaults to chef/. A service refuses to start when a prompt is
+missing and logs the other errors.
+
+Examples:
+  ./brigade-go chef lint
+  ./brigade-go chef lint brigade/chef`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load(cfgFile)
+		if err != nil {
+			return fmt.Errorf("loading config: %w", err)
+		}
+		dir := "chef"
+		if len(args) == 1 {
+			dir = args[0]
+		}
+		return cmdChefLint(dir, cfg)
+	},
+}
+
+func init() {
+	chefCmd.AddCommand(chefLintCmd)
+}
+
+// cmdChefLint prints the problems with a directory's chef prompts.
+func cmdChefLint(dir string, cfg *config.Config) error {
+	findings := chef.Lint(dir, cfg)
+	for _, f := range findings {
+		marker := colorYellow + "⚠" + colorReset
+		if f.Severity == chef.SeverityError {
+			marker = colorRed + "✗" + colorReset
+		}
+		fmt.Printf("  %s %s\n", marker, f)
+	}
+
+	n := chef.Errors(findings)
+	switch {
+	case n > 0:
+		return fmt.Errorf("chef prompts in %s: %s", dir, countNoun(n, "error"))
+	case len(findings) > 0:
+		fmt.Printf("\n%s✓ No errors%s (%s)\n", colorGreen, colorReset, countNoun(len(findings), "warning"))
+	default:
+		fmt.Printf("%s✓ Chef prompts in %s look good%s\n", colorGreen, dir, colorReset)
+	}
+	return nil
+}
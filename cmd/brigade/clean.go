@@ -0,0 +1,245 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"brigade/internal/config"
+	"brigade/internal/prd"
+	"brigade/internal/services"
+	"brigade/internal/state"
+)
+
+var cleanCmd = &cobra.Command{
+	Use:   "clean [prd.json...]",
+	Short: "Remove stale locks, state, logs and supervisor files",
+	Long: `Removes what Brigade leaves behind for a PRD, or for every PRD in
+brigade/tasks/ when none is given:
+
+  --locks   Stale service and state locks (the default)
+  --state   State, result and nudge files, and supervisor status, events
+            and command files
+  --logs    Worker logs and attempt artifacts; without a PRD also
+            ACTIVITY_LOG, WORKER_LOG_DIR and service logs
+  --all     All of the above
+
+A PRD whose service is still running is left alone. PRDs, config, notes and
+templates are never removed. Use --dry-run to list what would go.
+
+Examples:
+  ./brigade-go clean --dry-run
+  ./brigade-go clean --all brigade/tasks/prd-auth.json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load(cfgFile)
+		if err != nil {
+			return fmt.Errorf("loading config: %w", err)
+		}
+		var kinds cleanKinds
+		kinds.locks, _ = cmd.Flags().GetBool("locks")
+		kinds.state, _ = cmd.Flags().GetBool("state")
+		kinds.logs, _ = cmd.Flags().GetBool("logs")
+		if all, _ := cmd.Flags().GetBool("all"); all {
+			kinds = cleanKinds{locks: true, state: true, logs: true}
+		}
+		if kinds == (cleanKinds{}) {
+			kinds.locks = true
+		}
+		yes, _ := cmd.Flags().GetBool("yes")
+		return cmdClean(args, kinds, cfg, dryRun, yes)
+	},
+}
+
+func init() {
+	cleanCmd.Flags().Bool("locks", false, "remove stale service and state locks")
+	cleanCmd.Flags().Bool("state", false, "remove state, result, nudge and supervisor files")
+	cleanCmd.Flags().Bool("logs", false, "remove worker logs and artifacts")
+	cleanCmd.Flags().Bool("all", false, "remove locks, state and logs")
+	cleanCmd.Flags().BoolP("yes", "y", false, "remove without confirming")
+}
+
+// cleanKinds selects what clean removes.
+type cleanKinds struct {
+	locks, state, logs bool
+}
+
+// cleanTarget is a file or directory clean removes.
+type cleanTarget struct {
+	path string
+	what string
+}
+
+func cmdClean(prdPaths []string, kinds cleanKinds, cfg *config.Config, dryRun, yes bool) error {
+	whole := len(prdPaths) == 0
+	if whole {
+		prdPaths = listPRDPaths()
+		// A lock can outlive its PRD
+		locks, _ := state.FindServiceLocks("brigade/tasks", state.WithHeartbeatInterval(cfg.LockHeartbeatInterval))
+		for _, l := range locks {
+			if !containsString(prdPaths, l.PRDPath) {
+				prdPaths = append(prdPaths, l.PRDPath)
+			}
+		}
+		sort.Strings(prdPaths)
+	}
+
+	var targets []cleanTarget
+	running := false
+	for _, prdPath := range prdPaths {
+		lock := state.NewServiceLock(prdPath, state.WithHeartbeatInterval(cfg.LockHeartbeatInterval))
+		if lock.Exists() && !lock.IsStale() {
+			running = true
+			holder := "a live service"
+			if h, err := lock.Holder(); err == nil {
+				holder = h.String()
+			}
+			fmt.Printf("%s⚠%s Skipping %s: held by %s\n", colorYellow, colorReset, prdPath, holder)
+			continue
+		}
+		targets = append(targets, prdCleanTargets(prdPath, kinds, cfg)...)
+	}
+
+	if whole {
+		if running {
+			fmt.Printf("%s⚠%s Keeping shared logs and supervisor files while a service runs\n", colorYellow, colorReset)
+		} else {
+			targets = append(targets, sharedCleanTargets(kinds, cfg)...)
+		}
+	}
+
+	targets = existingTargets(targets)
+	if len(targets) == 0 {
+		fmt.Printf("%s✓%s Nothing to clean\n", colorGreen, colorReset)
+		return nil
+	}
+	for _, t := range targets {
+		fmt.Printf("  %-18s %s\n", t.what, t.path)
+	}
+	if dryRun {
+		fmt.Printf("\nDry run: %d paths would be removed.\n", len(targets))
+		return nil
+	}
+	if !yes && !confirmPrompt(fmt.Sprintf("\nRemove %d paths? (y/N) ", len(targets)), false) {
+		fmt.Println("Aborted.")
+		return nil
+	}
+
+	removed := 0
+	for _, t := range targets {
+		if err := os.RemoveAll(t.path); err != nil {
+			fmt.Printf("%s✗%s %s: %v\n", colorRed, colorReset, t.path, err)
+			continue
+		}
+		removed++
+	}
+	fmt.Printf("%s✓%s Removed %d paths\n", colorGreen, colorReset, removed)
+	return nil
+}
+
+// prdCleanTargets returns what clean may remove for one PRD, whether or not
+// it exists.
+func prdCleanTargets(prdPath string, kinds cleanKinds, cfg *config.Config) []cleanTarget {
+	p := &prd.PRD{}
+	p.SetPath(prdPath)
+	statePath := p.StatePath()
+	var targets []cleanTarget
+
+	if kinds.locks {
+		targets = append(targets, cleanTarget{state.NewServiceLock(prdPath).Path(), "service lock"})
+		if lock := state.ForPath(statePath); lock.IsStale() {
+			targets = append(targets, cleanTarget{lock.Path(), "state lock"})
+		}
+	}
+
+	if kinds.state {
+		targets = append(targets,
+			cleanTarget{statePath, "state"},
+			cleanTarget{p.ResultPath(), "result"},
+			cleanTarget{p.NudgesPath(), "nudges"},
+		)
+		if cfg.SupervisorPRDScoped {
+			targets = append(targets, supervisorCleanTargets(cfg, p.Prefix())...)
+		}
+	}
+
+	if kinds.logs {
+		targets = append(targets, cleanTarget{filepath.Join("brigade", "artifacts", p.Prefix()), "artifacts"})
+		logs, _ := filepath.Glob(filepath.Join("brigade", "logs", "ticket-"+p.Prefix()+"-*.log"))
+		for _, log := range logs {
+			targets = append(targets, cleanTarget{log, "worker log"})
+		}
+	}
+	return targets
+}
+
+// sharedCleanTargets returns the files every PRD's runs share.
+func sharedCleanTargets(kinds cleanKinds, cfg *config.Config) []cleanTarget {
+	var targets []cleanTarget
+	if kinds.state && !cfg.SupervisorPRDScoped {
+		targets = append(targets, supervisorCleanTargets(cfg, "")...)
+	}
+	if kinds.logs {
+		targets = append(targets,
+			cleanTarget{filepath.Join("brigade", "logs"), "worker logs"},
+			cleanTarget{filepath.Join("brigade", "artifacts"), "artifacts"},
+			cleanTarget{services.LogDir, "service logs"},
+		)
+		if cfg.WorkerLogDir != "" {
+			targets = append(targets, cleanTarget{cfg.WorkerLogDir, "worker logs"})
+		}
+		if cfg.ActivityLog != "" {
+			targets = append(targets, cleanTarget{cfg.ActivityLog, "activity log"})
+		}
+	}
+	return targets
+}
+
+// supervisorCleanTargets returns the configured supervisor files for a PRD
+// prefix.
+func supervisorCleanTargets(cfg *config.Config, prefix string) []cleanTarget {
+	sup := mcpSupervisor(cfg, prefix)
+	var targets []cleanTarget
+	if cfg.SupervisorStatusFile != "" {
+		targets = append(targets, cleanTarget{sup.Status().Path(), "supervisor status"})
+	}
+	if cfg.SupervisorEventsFile != "" {
+		targets = append(targets, cleanTarget{sup.Events().Path(), "supervisor events"})
+	}
+	if cfg.SupervisorCmdFile != "" {
+		targets = append(targets, cleanTarget{sup.Commands().Path(), "supervisor cmds"})
+	}
+	return targets
+}
+
+// existingTargets drops targets that don't exist or sit inside another
+// target, so each path is listed once.
+func existingTargets(targets []cleanTarget) []cleanTarget {
+	var kept []cleanTarget
+	for _, t := range targets {
+		if _, err := os.Lstat(t.path); err != nil {
+			continue
+		}
+		covered := false
+		for _, other := range targets {
+			if other.path != t.path && strings.HasPrefix(t.path, other.path+string(filepath.Separator)) {
+				covered = true
+				break
+			}
+		}
+		duplicate := false
+		for _, k := range kept {
+			if k.path == t.path {
+				duplicate = true
+				break
+			}
+		}
+		if !covered && !duplicate {
+			kept = append(kept, t)
+		}
+	}
+	return kept
+}
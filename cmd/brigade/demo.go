@@ -1,32 +1,45 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"brigade/internal/config"
+	"brigade/internal/module"
+	"brigade/internal/orchestrator"
 	"brigade/internal/prd"
+	"brigade/internal/state"
+	"brigade/internal/worker"
 )
 
 var demoCmd = &cobra.Command{
 	Use:   "demo",
-	Short: "Shows what Brigade does without executing",
-	Long:  `Demonstrates Brigade's capabilities using a demo PRD in dry-run mode.`,
+	Short: "Watch Brigade cook a demo PRD with simulated workers",
+	Long: `Demonstrates Brigade's capabilities by running a demo PRD with mock workers.
+
+The mock kitchen runs the real orchestrator (retries, an escalation, a failed
+review, events, status, and state) without invoking any model, so it costs
+nothing. Everything is written to a temporary directory.
+
+Use --dry-run to only list the execution plan.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		cfg, err := config.Load(cfgFile)
 		if err != nil {
 			// Config is optional for demo
 			cfg = config.Default()
 		}
-		return cmdDemo(cfg)
+		return cmdDemo(cmd.Context(), cfg)
 	},
 }
 
-func cmdDemo(cfg *config.Config) error {
+func cmdDemo(ctx context.Context, cfg *config.Config) error {
 	fmt.Println()
 	fmt.Printf("%sBrigade Kitchen Demo%s\n\n", colorBold, colorReset)
 	fmt.Println("Let's see how Brigade would cook up a feature!")
@@ -80,10 +93,12 @@ func cmdDemo(cfg *config.Config) error {
 	fmt.Println("  If a chef struggles, the task escalates to a more senior chef.")
 	fmt.Println()
 
-	fmt.Printf("%sRunning in dry-run mode...%s\n\n", colorBold, colorReset)
-
-	// Run dry-run
-	if err := previewExecution(examplePRD, cfg); err != nil {
+	if dryRun {
+		fmt.Printf("%sRunning in dry-run mode...%s\n\n", colorBold, colorReset)
+		if err := previewExecution(examplePRD, cfg); err != nil {
+			return err
+		}
+	} else if err := runMockKitchen(ctx, examplePRD, cfg); err != nil {
 		return err
 	}
 
@@ -156,3 +171,132 @@ func createDemoPRD() (string, error) {
 
 	return prdPath, nil
 }
+
+// runMockKitchen runs a copy of the PRD through the orchestrator with mock
+// workers, streaming events as they happen and ending with status and summary.
+func runMockKitchen(ctx context.Context, prdPath string, cfg *config.Config) error {
+	dir, err := os.MkdirTemp("", "brigade-demo-")
+	if err != nil {
+		return err
+	}
+
+	p, err := prd.Load(prdPath)
+	if err != nil {
+		return err
+	}
+	for i := range p.Tasks {
+		p.Tasks[i].Passes = false
+	}
+	demoPath := filepath.Join(dir, "prd-demo.json")
+	if err := p.Save(demoPath); err != nil {
+		return err
+	}
+
+	// Keep everything inside the demo directory and make the run easy to follow
+	demoCfg := *cfg
+	demoCfg.SupervisorStatusFile = filepath.Join(dir, "status.json")
+	demoCfg.SupervisorEventsFile = filepath.Join(dir, "events.jsonl")
+	demoCfg.SupervisorCmdFile = ""
+	demoCfg.SupervisorPRDScoped = false
+	demoCfg.ActivityLog = ""
+	demoCfg.LearningsFile = filepath.Join(dir, "learnings.md")
+	demoCfg.BacklogFile = filepath.Join(dir, "backlog.md")
+	demoCfg.Modules = nil
+	demoCfg.MaxParallel = 1
+	demoCfg.EscalationEnabled = true
+	demoCfg.EscalationAfter = 2
+	demoCfg.ReviewEnabled = true
+	demoCfg.ReviewJuniorOnly = true
+	demoCfg.VerificationEnabled = false
+	demoCfg.WalkawayMode = true
+
+	script := worker.NewMockScript(800 * time.Millisecond)
+	script.FailureRate = 0.1
+	var junior []string
+	for _, task := range p.Tasks {
+		if task.Complexity != prd.ComplexitySenior {
+			junior = append(junior, task.ID)
+		}
+	}
+	if len(junior) > 0 {
+		script.ReviewFailTask = junior[0]
+		script.EscalateTask = junior[len(junior)-1]
+	}
+
+	chefDir, err := demoChefDir(dir)
+	if err != nil {
+		return err
+	}
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelWarn}))
+	orch, err := orchestrator.New(orchestrator.Options{
+		Config:  &demoCfg,
+		PRDPath: demoPath,
+		Logger:  logger,
+		Workers: worker.NewMockFactory(script),
+		ChefDir: chefDir,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%sFiring up the mock kitchen (no tokens spent)...%s\n", colorBold, colorReset)
+	fmt.Printf("%sWorking directory: %s%s\n\n", colorDim, dir, colorReset)
+
+	tailCtx, stopTail := context.WithCancel(ctx)
+	events := make(chan *module.Event, 16)
+	go tailEvents(tailCtx, demoCfg.SupervisorEventsFile, events)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for e := range events {
+			printEvent(e)
+		}
+	}()
+
+	runErr := orch.Run(ctx)
+
+	// Let the tail catch the final events before stopping it
+	time.Sleep(700 * time.Millisecond)
+	stopTail()
+	close(events)
+	<-done
+
+	if runErr != nil {
+		fmt.Printf("\n%s⚠ Demo run stopped: %v%s\n", colorYellow, runErr, colorReset)
+	}
+
+	if info, err := getStatus(demoPath); err == nil {
+		fmt.Print(info.Format())
+	}
+	if st, err := state.ForPRD(demoPath).Load(); err == nil {
+		if final, err := prd.Load(demoPath); err == nil {
+			fmt.Println()
+			fmt.Print(generateSummary(final, st))
+		}
+	}
+
+	fmt.Printf("\n%sState, events, and status files are in %s%s\n", colorDim, dir, colorReset)
+	return ctx.Err()
+}
+
+// demoChefDir returns a chef prompt directory, writing placeholder prompts
+// when Brigade's own are not installed here (mock workers ignore them).
+func demoChefDir(dir string) (string, error) {
+	for _, candidate := range []string{"chef", "brigade/chef"} {
+		if fileExists(filepath.Join(candidate, "line.md")) {
+			return candidate, nil
+		}
+	}
+
+	chefDir := filepath.Join(dir, "chef")
+	if err := os.MkdirAll(chefDir, 0755); err != nil {
+		return "", err
+	}
+	for _, name := range []string{"line.md", "sous.md", "executive.md"} {
+		if err := os.WriteFile(filepath.Join(chefDir, name), []byte("# Demo chef\n\nOutput <promise>COMPLETE</promise> when done, or <promise>BLOCKED</promise> if stuck.\n"), 0644); err != nil {
+			return "", err
+		}
+	}
+	return chefDir, nil
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"brigade/internal/config"
+	"brigade/internal/deps"
+)
+
+var planDepsCmd = &cobra.Command{
+	Use:   "deps",
+	Short: "Generate a PRD that upgrades outdated dependencies",
+	Long: `Finds outdated direct dependencies in go.mod, package.json and
+requirements.txt and drafts a PRD to upgrade them.
+
+Each major upgrade gets its own senior task whose criteria cover the
+changelog's breaking changes. Minor and patch updates are batched into one
+junior task that runs first. Every task is verified by the version check and
+the test suite (TEST_CMD, or the ecosystem default).
+
+Go modules only report updates within the current major version; a new Go
+major version is a new module path and has to be planned by hand.
+
+When nothing is outdated, no PRD is written and the command exits 0, so it
+can run unattended on a nightly schedule:
+
+  out=brigade/tasks/prd-deps-$(date +%F).json
+  ./brigade-go plan deps --force --output "$out" && [ -f "$out" ] && ./brigade-go service "$out"
+
+Examples:
+  ./brigade-go plan deps
+  ./brigade-go plan deps --output brigade/tasks/prd-upgrades.json --force`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load(cfgFile)
+		if err != nil {
+			return fmt.Errorf("loading config: %w", err)
+		}
+		output, _ := cmd.Flags().GetString("output")
+		force, _ := cmd.Flags().GetBool("force")
+		return cmdPlanDeps(cmd.Context(), output, force, cfg)
+	},
+}
+
+func init() {
+	planDepsCmd.Flags().String("output", "", "PRD path to write (default brigade/tasks/prd-deps-<date>.json)")
+	planDepsCmd.Flags().Bool("force", false, "overwrite an existing PRD without asking")
+	planCmd.AddCommand(planDepsCmd)
+}
+
+// cmdPlanDeps lists outdated dependencies and writes an upgrade PRD.
+func cmdPlanDeps(ctx context.Context, output string, force bool, cfg *config.Config) error {
+	if cfg.Offline {
+		return fmt.Errorf("plan deps asks package registries for new versions, which OFFLINE doesn't allow")
+	}
+	ecosystems := deps.Detect(".")
+	if len(ecosystems) == 0 {
+		return fmt.Errorf("no go.mod, package.json or requirements.txt found")
+	}
+
+	var outdated []deps.Dependency
+	for _, eco := range ecosystems {
+		fmt.Printf("%sChecking %s dependencies...%s\n", colorDim, eco, colorReset)
+		found, err := deps.Outdated(ctx, ".", eco)
+		if err != nil {
+			return fmt.Errorf("checking %s dependencies: %w", eco, err)
+		}
+		outdated = append(outdated, found...)
+	}
+
+	if len(outdated) == 0 {
+		fmt.Printf("%s✓ All dependencies are up to date%s\n", colorGreen, colorReset)
+		return nil
+	}
+
+	fmt.Printf("\n%d outdated dependencies:\n", len(outdated))
+	for _, d := range outdated {
+		kind := "minor"
+		color := colorDim
+		if d.Major() {
+			kind, color = "major", colorYellow
+		}
+		fmt.Printf("  %s %s → %s %s(%s)%s\n", d.Name, d.Current, d.Latest, color, kind, colorReset)
+	}
+
+	today := time.Now().Format("2006-01-02")
+	if output == "" {
+		output = fmt.Sprintf("brigade/tasks/prd-deps-%s.json", today)
+	}
+
+	p := deps.UpgradePRD(outdated, cfg.TestCmd, today)
+	fmt.Printf("\n%d tasks:\n", len(p.Tasks))
+	printTriageTasks(p)
+
+	return writeDraftPRD(p, output, force)
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"errors"
+
+	"brigade/internal/orchestrator"
+	"brigade/internal/prd"
+	"brigade/internal/state"
+	"brigade/internal/worker"
+)
+
+// Exit codes for automation around Brigade. Anything not listed is 1.
+const (
+	exitError       = 1
+	exitValidation  = 2   // PRD failed validation
+	exitLockHeld    = 3   // Another service holds the PRD lock
+	exitBlocked     = 4   // A task failed, nothing is ready to run, or PHASE_GATE stopped a chain
+	exitBudget      = 5   // Cost budget exceeded
+	exitTimeout     = 6   // Service idle or worker timeout
+	exitSessionEnd  = 7   // Walkaway session reached WALKAWAY_MAX_DURATION
+	exitInterrupted = 130 // Ctrl-C or SIGTERM
+)
+
+// exitCode maps an error returned by a command to the process exit code.
+func exitCode(err error) int {
+	var invalid *prd.InvalidError
+	var lockHeld *state.LockHeldError
+	var blocked *orchestrator.BlockedError
+	var gate *orchestrator.GateError
+	var budget *orchestrator.BudgetError
+	var timeout *orchestrator.TimeoutError
+	var sessionEnd *orchestrator.SessionLimitError
+
+	switch {
+	case errors.As(err, &invalid):
+		return exitValidation
+	case errors.As(err, &lockHeld):
+		return exitLockHeld
+	case errors.As(err, &blocked), errors.As(err, &gate):
+		return exitBlocked
+	case errors.As(err, &budget):
+		return exitBudget
+	case errors.As(err, &timeout), errors.Is(err, worker.ErrTimeout):
+		return exitTimeout
+	case errors.As(err, &sessionEnd):
+		return exitSessionEnd
+	case errors.Is(err, context.Canceled):
+		return exitInterrupted
+	default:
+		return exitError
+	}
+}
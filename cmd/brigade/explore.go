@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
 	"regexp"
 	"strings"
 	"time"
@@ -11,6 +12,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"brigade/internal/config"
+	"brigade/internal/orchestrator"
 	"brigade/internal/state"
 	"brigade/internal/util"
 	"brigade/internal/worker"
@@ -21,20 +23,34 @@ var exploreCmd = &cobra.Command{
 	Short: "Research questions about the codebase",
 	Long: `Invokes the researcher to explore a question about the codebase.
 
-Example:
-  ./brigade-go explore "could we add real-time sync with websockets?"`,
+With --to-prd, the findings are handed to the planner to draft a PRD from the
+recommended approach. Passing an existing exploration report instead of a
+question converts that report directly.
+
+Examples:
+  ./brigade-go explore "could we add real-time sync with websockets?"
+  ./brigade-go explore --to-prd "could we add real-time sync with websockets?"
+  ./brigade-go explore --to-prd brigade/explorations/2025-01-10-realtime-sync.md`,
 	Args: cobra.MinimumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		cfg, err := config.Load(cfgFile)
 		if err != nil {
 			return fmt.Errorf("loading config: %w", err)
 		}
+		toPRD, _ := cmd.Flags().GetBool("to-prd")
 		question := strings.Join(args, " ")
-		return cmdExplore(question, cfg)
+		if toPRD && len(args) == 1 && strings.HasSuffix(args[0], ".md") && fileExists(args[0]) {
+			return cmdExplorationToPRD(cmd.Context(), args[0], cfg)
+		}
+		return cmdExplore(cmd.Context(), question, toPRD, cfg)
 	},
 }
 
-func cmdExplore(question string, cfg *config.Config) error {
+func init() {
+	exploreCmd.Flags().Bool("to-prd", false, "generate a draft PRD from the exploration findings")
+}
+
+func cmdExplore(ctx context.Context, question string, toPRD bool, cfg *config.Config) error {
 	// Ensure explorations directory exists
 	if err := os.MkdirAll("brigade/explorations", 0755); err != nil {
 		return err
@@ -94,17 +110,22 @@ BEGIN RESEARCH:`, question, outputPath, time.Now().Format("2006-01-02"), outputP
 	start := time.Now()
 
 	// Create worker for Executive Chef (researcher uses same model)
+	env, err := orchestrator.WorkerEnv(cfg, state.TierExecutive)
+	if err != nil {
+		return err
+	}
 	workerCfg := &worker.Config{
 		Command:    cfg.ExecutiveCmd,
 		Tier:       state.TierExecutive,
 		Timeout:    cfg.TaskTimeoutExecutive,
 		WorkingDir: "",
 		Quiet:      false,
+		Env:        env,
 	}
 	exec := worker.NewCLIWorker(workerCfg)
 
 	// Execute
-	result, err := exec.Execute(context.Background(), prompt)
+	result, err := exec.Execute(ctx, prompt)
 	if err != nil {
 		return fmt.Errorf("executing explore: %w", err)
 	}
@@ -121,30 +142,62 @@ BEGIN RESEARCH:`, question, outputPath, time.Now().Format("2006-01-02"), outputP
 		}
 	}
 
+	reportPath := ""
 	if resultFile != "" && fileExists(resultFile) {
-		fmt.Println()
-		fmt.Printf("%s╔═══════════════════════════════════════════════════════════╗%s\n", colorGreen, colorReset)
-		fmt.Printf("%s║  EXPLORATION COMPLETE: %s%s\n", colorGreen, resultFile, colorReset)
-		fmt.Printf("%s╚═══════════════════════════════════════════════════════════╝%s\n\n", colorGreen, colorReset)
-
-		fmt.Printf("%sNext steps:%s\n", colorBold, colorReset)
-		fmt.Printf("  View report:    %scat %s%s\n", colorCyan, resultFile, colorReset)
-		fmt.Printf("  Plan feature:   %s./brigade.sh plan \"[feature description]\"%s\n", colorCyan, colorReset)
+		reportPath = resultFile
 	} else if fileExists(outputPath) {
 		// File exists but no signal
-		fmt.Println()
-		fmt.Printf("%s╔═══════════════════════════════════════════════════════════╗%s\n", colorGreen, colorReset)
-		fmt.Printf("%s║  EXPLORATION COMPLETE: %s%s\n", colorGreen, outputPath, colorReset)
-		fmt.Printf("%s╚═══════════════════════════════════════════════════════════╝%s\n\n", colorGreen, colorReset)
+		reportPath = outputPath
+	}
 
-		fmt.Printf("%sNext steps:%s\n", colorBold, colorReset)
-		fmt.Printf("  View report:    %scat %s%s\n", colorCyan, outputPath, colorReset)
-		fmt.Printf("  Plan feature:   %s./brigade.sh plan \"[feature description]\"%s\n", colorCyan, colorReset)
-	} else {
+	if reportPath == "" {
 		fmt.Println()
 		fmt.Printf("%sExploration output:%s\n", colorYellow, colorReset)
 		fmt.Printf("%s(No output file generated - see above for results)%s\n", colorDim, colorReset)
+		return nil
 	}
 
+	fmt.Println()
+	fmt.Printf("%s╔═══════════════════════════════════════════════════════════╗%s\n", colorGreen, colorReset)
+	fmt.Printf("%s║  EXPLORATION COMPLETE: %s%s\n", colorGreen, reportPath, colorReset)
+	fmt.Printf("%s╚═══════════════════════════════════════════════════════════╝%s\n\n", colorGreen, colorReset)
+
+	if !toPRD && util.IsTerminal(os.Stdin) {
+		toPRD = confirmPrompt("Draft a PRD from these findings? (y/N) ", false)
+	}
+	if toPRD {
+		return cmdExplorationToPRD(ctx, reportPath, cfg)
+	}
+
+	fmt.Printf("%sNext steps:%s\n", colorBold, colorReset)
+	fmt.Printf("  View report:    %scat %s%s\n", colorCyan, reportPath, colorReset)
+	fmt.Printf("  Draft PRD:      %s./brigade-go explore --to-prd %s%s\n", colorCyan, reportPath, colorReset)
+	fmt.Printf("  Plan feature:   %s./brigade.sh plan \"[feature description]\"%s\n", colorCyan, colorReset)
+
 	return nil
 }
+
+// cmdExplorationToPRD feeds an exploration report to the planner.
+func cmdExplorationToPRD(ctx context.Context, reportPath string, cfg *config.Config) error {
+	return cmdPlanWithContext(ctx, explorationTitle(reportPath), reportPath, cfg)
+}
+
+// explorationTitle derives a feature description from an exploration report:
+// its first markdown heading, or the filename without the date prefix.
+func explorationTitle(reportPath string) string {
+	if content, err := os.ReadFile(reportPath); err == nil {
+		for _, line := range strings.Split(string(content), "\n") {
+			if strings.HasPrefix(line, "# ") {
+				title := strings.TrimSpace(strings.TrimPrefix(line, "# "))
+				title = strings.TrimSpace(strings.TrimPrefix(title, "Exploration:"))
+				if title != "" {
+					return title
+				}
+			}
+		}
+	}
+
+	name := strings.TrimSuffix(filepath.Base(reportPath), ".md")
+	name = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}-`).ReplaceAllString(name, "")
+	return strings.ReplaceAll(name, "-", " ")
+}
@@ -0,0 +1,298 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"brigade/internal/config"
+	"brigade/internal/prd"
+	"brigade/internal/state"
+)
+
+var fsckCmd = &cobra.Command{
+	Use:   "fsck <prd.json>",
+	Short: "Check a PRD and its state for mismatches and repair them",
+	Long: `Cross-checks a PRD against its state file for the inconsistencies manual
+edits and crashes leave behind:
+
+  - state records for tasks that aren't in the PRD
+  - tasks passing in the PRD with no completion in the state, and the reverse
+  - a current task that is unknown, already complete, or whose attempt never
+    finished
+  - tasks absorbed by a task that isn't complete
+
+Each problem comes with one or more repairs to choose from. With --yes the
+first repair of each is applied; with --dry-run problems are only listed.
+Repairs are refused while a service is running on the PRD.
+
+Examples:
+  ./brigade-go fsck brigade/tasks/prd-auth.json
+  ./brigade-go fsck --yes brigade/tasks/prd-auth.json
+  ./brigade-go --dry-run fsck brigade/tasks/prd-auth.json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load(cfgFile)
+		if err != nil {
+			return fmt.Errorf("loading config: %w", err)
+		}
+		yes, _ := cmd.Flags().GetBool("yes")
+		return cmdFsck(args[0], yes, dryRun, cfg)
+	},
+}
+
+func init() {
+	fsckCmd.Flags().BoolP("yes", "y", false, "apply the first repair of every problem without asking")
+}
+
+// fsckInterruptedError is recorded for an attempt a crash left unfinished,
+// as a service does when it recovers one on start.
+const fsckInterruptedError = "attempt interrupted: brigade exited while the worker was running"
+
+// fsckProblem is a mismatch between a PRD and its state.
+type fsckProblem struct {
+	Kind    string
+	TaskID  string
+	Problem string
+	Repairs []fsckRepair
+}
+
+// fsckRepair is one way to fix a problem.
+type fsckRepair struct {
+	Label string
+	Apply func(p *prd.PRD, st *state.State)
+}
+
+// cmdFsck checks a PRD against its state and walks through repairs.
+func cmdFsck(path string, yes, dryRun bool, cfg *config.Config) error {
+	p, err := prd.Load(path)
+	if err != nil {
+		return err
+	}
+	store := state.ForPRD(path)
+	if !store.Exists() {
+		fmt.Printf("%s✓%s No state file for %s - nothing to check\n", colorGreen, colorReset, path)
+		return nil
+	}
+	st, err := store.Load()
+	if err != nil {
+		return err
+	}
+
+	lock := state.NewServiceLock(path, state.WithHeartbeatInterval(cfg.LockHeartbeatInterval))
+	running := lock.Exists() && !lock.IsStale()
+
+	problems := fsckCheck(p, st, running)
+	fmt.Printf("%s=== fsck: %s ===%s\n", colorBold, p.FeatureName, colorReset)
+	if len(problems) == 0 {
+		fmt.Printf("\n%s✓ PRD and state agree%s (%d tasks, %d in state)\n", colorGreen, colorReset, len(p.Tasks), len(st.TaskIDs()))
+		return nil
+	}
+
+	fmt.Printf("\n%s✗ %s%s\n", colorRed, countNoun(len(problems), "problem"), colorReset)
+	for _, pr := range problems {
+		fmt.Printf("  %s%-20s%s %s\n", colorYellow, pr.Kind, colorReset, pr.Problem)
+	}
+
+	switch {
+	case dryRun:
+		return fmt.Errorf("%s found", countNoun(len(problems), "problem"))
+	case running:
+		fmt.Printf("\n%sA service is running on %s; stop it before repairing.%s\n", colorYellow, path, colorReset)
+		return fmt.Errorf("%s found", countNoun(len(problems), "problem"))
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	repaired := 0
+	for _, pr := range problems {
+		choice := 0
+		if !yes {
+			fmt.Printf("\n%s%s%s\n", colorBold, pr.Problem, colorReset)
+			choice = chooseRepair(reader, pr.Repairs)
+		}
+		if choice < 0 {
+			continue
+		}
+		pr.Repairs[choice].Apply(p, st)
+		fmt.Printf("  %s✓ %s%s\n", colorGreen, pr.Repairs[choice].Label, colorReset)
+		repaired++
+	}
+	if repaired == 0 {
+		return fmt.Errorf("%s left unrepaired", countNoun(len(problems), "problem"))
+	}
+
+	if err := store.Save(st); err != nil {
+		return err
+	}
+	if err := p.Save(""); err != nil {
+		return err
+	}
+	fmt.Printf("\n%sRepaired %d of %s%s\n", colorGreen, repaired, countNoun(len(problems), "problem"), colorReset)
+	if left := len(problems) - repaired; left > 0 {
+		return fmt.Errorf("%s left unrepaired", countNoun(left, "problem"))
+	}
+	return nil
+}
+
+// chooseRepair asks which repair to apply, returning its index or -1 to
+// leave the problem alone. The first repair is the default.
+func chooseRepair(reader *bufio.Reader, repairs []fsckRepair) int {
+	for i, r := range repairs {
+		fmt.Printf("  %d) %s\n", i+1, r.Label)
+	}
+	fmt.Printf("  s) skip\n")
+	fmt.Printf("Repair [1-%d/s] (1): ", len(repairs))
+	response, _ := reader.ReadString('\n')
+	response = strings.TrimSpace(strings.ToLower(response))
+	if response == "" {
+		return 0
+	}
+	if n, err := strconv.Atoi(response); err == nil && n >= 1 && n <= len(repairs) {
+		return n - 1
+	}
+	return -1
+}
+
+// fsckCheck finds the mismatches between a PRD and its state. The current
+// task isn't checked while a service is running, since it's in use.
+func fsckCheck(p *prd.PRD, st *state.State, running bool) []fsckProblem {
+	var problems []fsckProblem
+	completed := st.CompletedTaskIDs()
+
+	// State records for tasks the PRD no longer has
+	for _, id := range st.TaskIDs() {
+		if p.TaskByID(id) != nil {
+			continue
+		}
+		problems = append(problems, fsckProblem{
+			Kind:    "unknown task",
+			TaskID:  id,
+			Problem: fmt.Sprintf("state has records for %s, which isn't in the PRD", id),
+			Repairs: []fsckRepair{{
+				Label: fmt.Sprintf("drop %s's state records", id),
+				Apply: func(_ *prd.PRD, st *state.State) { st.ForgetTask(id) },
+			}},
+		})
+	}
+
+	for _, task := range p.Tasks {
+		id := task.ID
+		switch {
+		case task.Passes && !completed[id]:
+			tier := state.TierLine
+			if task.IsSenior() {
+				tier = state.TierSous
+			}
+			problems = append(problems, fsckProblem{
+				Kind:    "unrecorded pass",
+				TaskID:  id,
+				Problem: fmt.Sprintf("%s passes in the PRD but the state has no completion for it", id),
+				Repairs: []fsckRepair{
+					{
+						Label: fmt.Sprintf("record %s as complete in the state", id),
+						Apply: func(_ *prd.PRD, st *state.State) {
+							st.AddTaskHistory(state.TaskHistory{TaskID: id, Worker: tier, Status: state.StatusComplete, Approach: "recorded by brigade fsck"})
+						},
+					},
+					{
+						Label: fmt.Sprintf("mark %s pending in the PRD so it runs again", id),
+						Apply: func(p *prd.PRD, _ *state.State) { p.TaskByID(id).Passes = false },
+					},
+				},
+			})
+		case !task.Passes && completed[id] && !absorbedIntoIncomplete(st, id, completed):
+			problems = append(problems, fsckProblem{
+				Kind:    "unpersisted pass",
+				TaskID:  id,
+				Problem: fmt.Sprintf("the state has %s complete but it's pending in the PRD", id),
+				Repairs: []fsckRepair{
+					{
+						Label: fmt.Sprintf("mark %s passing in the PRD", id),
+						Apply: func(p *prd.PRD, _ *state.State) { p.MarkTaskComplete(id) },
+					},
+					{
+						Label: fmt.Sprintf("forget %s's completion so it runs again", id),
+						Apply: func(_ *prd.PRD, st *state.State) { st.ForgetCompletion(id) },
+					},
+				},
+			})
+		}
+	}
+
+	// Absorbed tasks are only done if what absorbed them is
+	for _, a := range st.Absorptions {
+		if completed[a.AbsorbedBy] && p.TaskByID(a.AbsorbedBy) != nil {
+			continue
+		}
+		if p.TaskByID(a.TaskID) == nil {
+			continue // Reported as an unknown task
+		}
+		id, by := a.TaskID, a.AbsorbedBy
+		problems = append(problems, fsckProblem{
+			Kind:    "absorber incomplete",
+			TaskID:  id,
+			Problem: fmt.Sprintf("%s was absorbed by %s, which isn't complete", id, by),
+			Repairs: []fsckRepair{{
+				Label: fmt.Sprintf("return %s to pending", id),
+				Apply: func(p *prd.PRD, st *state.State) {
+					st.ForgetCompletion(id)
+					p.TaskByID(id).Passes = false
+				},
+			}},
+		})
+	}
+
+	// A current task left behind by a crash
+	if current := st.CurrentTask; current != "" && !running {
+		var reason string
+		switch {
+		case p.TaskByID(current) == nil:
+			reason = "isn't in the PRD"
+		case completed[current]:
+			reason = "is already complete"
+		case st.OrphanedTask() != "":
+			reason = "has an attempt that never finished"
+		}
+		if reason != "" {
+			orphaned := st.OrphanedTask() == current
+			problems = append(problems, fsckProblem{
+				Kind:    "orphaned current",
+				TaskID:  current,
+				Problem: fmt.Sprintf("current task %s %s, and no service is running", current, reason),
+				Repairs: []fsckRepair{{
+					Label: "clear the current task, recording an unfinished attempt as failed",
+					Apply: func(p *prd.PRD, st *state.State) {
+						if orphaned && p.TaskByID(current) != nil && !st.ResolveAttempt(current, state.StatusFailed, fsckInterruptedError, "") {
+							st.AddTaskHistory(state.TaskHistory{TaskID: current, Worker: st.CurrentTier(current, state.TierLine), Status: state.StatusFailed, Error: fsckInterruptedError})
+						}
+						st.ClearCurrentTask()
+					},
+				}},
+			})
+		}
+	}
+	return problems
+}
+
+// absorbedIntoIncomplete reports whether a task's completion comes only from
+// being absorbed by a task that isn't complete.
+func absorbedIntoIncomplete(st *state.State, taskID string, completed map[string]bool) bool {
+	for _, a := range st.Absorptions {
+		if a.TaskID == taskID && !completed[a.AbsorbedBy] {
+			return true
+		}
+	}
+	return false
+}
+
+// countNoun formats a count with its noun, pluralized with an s.
+func countNoun(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
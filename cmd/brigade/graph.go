@@ -0,0 +1,195 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"brigade/internal/prd"
+	"brigade/internal/state"
+)
+
+var graphCmd = &cobra.Command{
+	Use:   "graph",
+	Short: "Show and edit a PRD's task dependencies",
+}
+
+var graphShowCmd = &cobra.Command{
+	Use:   "show <prd.json>",
+	Short: "Show the dependency graph level by level",
+	Long: `Shows a PRD's tasks grouped by the earliest level they can run at, with
+what each depends on and what depends on it. Tasks on the same level can run
+in parallel.
+
+Examples:
+  ./brigade-go graph show brigade/tasks/prd-auth.json
+  ./brigade-go graph show --json brigade/tasks/prd-auth.json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+		return cmdGraphShow(args[0], jsonOutput)
+	},
+}
+
+var graphAddDepCmd = &cobra.Command{
+	Use:   "add-dep <prd.json> <task> <dep>",
+	Short: "Make a task depend on another",
+	Long: `Adds dep to task's dependsOn and saves the PRD. Refuses unknown task IDs
+(suggesting the closest one), duplicates, and dependencies that would create
+a cycle, naming the chain that closes it. The PRD is revalidated before it
+is saved; a change that introduces a validation error isn't saved.
+
+Examples:
+  ./brigade-go graph add-dep brigade/tasks/prd-auth.json US-004 US-002
+  ./brigade-go --dry-run graph add-dep brigade/tasks/prd-auth.json US-004 US-002`,
+	Args: cobra.ExactArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmdGraphEdit(args[0], args[1], args[2], true, dryRun)
+	},
+}
+
+var graphRemoveDepCmd = &cobra.Command{
+	Use:   "remove-dep <prd.json> <task> <dep>",
+	Short: "Drop a task's dependency",
+	Long: `Removes dep from task's dependsOn and saves the PRD. Works for a dependency
+on a task that doesn't exist, so a typo can be removed.
+
+Examples:
+  ./brigade-go graph remove-dep brigade/tasks/prd-auth.json US-004 US-002`,
+	Args: cobra.ExactArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmdGraphEdit(args[0], args[1], args[2], false, dryRun)
+	},
+}
+
+func init() {
+	graphShowCmd.Flags().Bool("json", false, "output as JSON")
+	graphCmd.AddCommand(graphShowCmd, graphAddDepCmd, graphRemoveDepCmd)
+}
+
+// graphTask is one task in `graph show --json`.
+type graphTask struct {
+	ID         string   `json:"id"`
+	Title      string   `json:"title"`
+	Level      int      `json:"level"` // 1-based; 0 if the task is in a cycle
+	Complete   bool     `json:"complete"`
+	DependsOn  []string `json:"dependsOn"`
+	Dependents []string `json:"dependents"`
+}
+
+// cmdGraphShow prints a PRD's dependency graph.
+func cmdGraphShow(path string, jsonOutput bool) error {
+	p, err := prd.Load(path)
+	if err != nil {
+		return err
+	}
+	completed := make(map[string]bool)
+	if st, err := state.ForPRD(path).Load(); err == nil {
+		completed = st.CompletedTaskIDs()
+	}
+
+	var levels [][]string
+	if m := p.AnalyzeGraph(nil); m != nil {
+		levels = m.Levels
+	}
+	level := make(map[string]int)
+	for i, ids := range levels {
+		for _, id := range ids {
+			level[id] = i + 1
+		}
+	}
+	dependents := p.DependencyGraph()
+
+	if jsonOutput {
+		tasks := make([]graphTask, 0, len(p.Tasks))
+		for _, task := range p.Tasks {
+			tasks = append(tasks, graphTask{
+				ID:         task.ID,
+				Title:      task.Title,
+				Level:      level[task.ID],
+				Complete:   task.Passes || completed[task.ID],
+				DependsOn:  append([]string{}, task.DependsOn...),
+				Dependents: append([]string{}, dependents[task.ID]...),
+			})
+		}
+		data, _ := json.MarshalIndent(tasks, "", "  ")
+		fmt.Println(string(data))
+		return nil
+	}
+
+	fmt.Printf("%s=== Graph: %s ===%s\n", colorBold, p.FeatureName, colorReset)
+	if levels == nil {
+		fmt.Printf("\n%s✗ Circular dependency - tasks can't be ordered%s\n", colorRed, colorReset)
+		levels = [][]string{p.AllTaskIDs()}
+	}
+	for i, ids := range levels {
+		fmt.Printf("\n%sLevel %d%s\n", colorBold, i+1, colorReset)
+		for _, id := range ids {
+			task := p.TaskByID(id)
+			marker := "○"
+			if task.Passes || completed[id] {
+				marker = colorGreen + "✓" + colorReset
+			}
+			line := fmt.Sprintf("  %s %s: %s", marker, id, task.Title)
+			if len(task.DependsOn) > 0 {
+				line += fmt.Sprintf(" %s← %s%s", colorDim, strings.Join(task.DependsOn, ", "), colorReset)
+			}
+			if n := len(dependents[id]); n > 0 {
+				line += fmt.Sprintf(" %s→ %s%s", colorDim, strings.Join(dependents[id], ", "), colorReset)
+			}
+			fmt.Println(line)
+		}
+	}
+	return nil
+}
+
+// cmdGraphEdit adds or removes a dependency and saves the PRD, unless that
+// would leave it with a validation error it didn't have.
+func cmdGraphEdit(path, taskID, dep string, add bool, dryRun bool) error {
+	p, err := prd.Load(path)
+	if err != nil {
+		return err
+	}
+	before := make(map[string]bool)
+	for _, e := range p.ValidateQuick().Errors {
+		before[e.Error()] = true
+	}
+
+	verb := "now depends on"
+	if add {
+		err = p.AddDependency(taskID, dep)
+	} else {
+		verb = "no longer depends on"
+		err = p.RemoveDependency(taskID, dep)
+	}
+	if err != nil {
+		return err
+	}
+
+	var introduced []prd.ValidationError
+	for _, e := range p.ValidateQuick().Errors {
+		if !before[e.Error()] {
+			introduced = append(introduced, e)
+		}
+	}
+	if len(introduced) > 0 {
+		fmt.Println("Errors:")
+		for _, e := range introduced {
+			fmt.Printf("  ✗ %s\n", e)
+		}
+		return &prd.InvalidError{Path: path, Errors: introduced}
+	}
+
+	if dryRun {
+		fmt.Printf("Would save: %s %s %s\n", taskID, verb, dep)
+		return nil
+	}
+	if err := p.Save(""); err != nil {
+		return err
+	}
+	recordPRDRevision(p, prd.SourceGraph)
+	fmt.Printf("%s✓ %s %s %s%s\n", colorGreen, taskID, verb, dep, colorReset)
+	return nil
+}
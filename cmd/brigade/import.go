@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"brigade/internal/prd"
+	"brigade/internal/triage"
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Draft PRDs from outside sources",
+	Long:  `Turns feedback from outside Brigade into a draft PRD of remediation tasks.`,
+}
+
+var importPRCommentsCmd = &cobra.Command{
+	Use:   "pr-comments",
+	Short: "Draft a PRD from a pull request's unresolved review comments",
+	Long: `Reads the unresolved review threads on a GitHub pull request and drafts
+a PRD with one remediation task per thread. Each task names the file and
+line commented on, quotes the code around it and the whole thread, and
+links the comment. When a PRD in brigade/tasks built the pull request's
+branch, the draft is linked to it as its parent for context.
+
+Needs the GitHub CLI (gh), logged in, and is run in the repository the pull
+request is on.
+
+Examples:
+  ./brigade-go import pr-comments --pr 123
+  ./brigade-go import pr-comments --pr 123 --output brigade/tasks/prd-review-fixes.json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		number, _ := cmd.Flags().GetInt("pr")
+		if number <= 0 {
+			return fmt.Errorf("--pr is required")
+		}
+		output, _ := cmd.Flags().GetString("output")
+		if output == "" {
+			output = fmt.Sprintf("brigade/tasks/prd-pr-%d-review.json", number)
+		}
+		return cmdImportPRComments(number, output)
+	},
+}
+
+func init() {
+	importPRCommentsCmd.Flags().Int("pr", 0, "pull request number")
+	importPRCommentsCmd.Flags().String("output", "", "PRD path to write (default brigade/tasks/prd-pr-<n>-review.json)")
+	importCmd.AddCommand(importPRCommentsCmd)
+}
+
+// cmdImportPRComments writes a remediation PRD for a pull request's
+// unresolved review threads.
+func cmdImportPRComments(number int, output string) error {
+	if _, err := exec.LookPath("gh"); err != nil {
+		return fmt.Errorf("the GitHub CLI (gh) is needed: https://cli.github.com")
+	}
+	fmt.Printf("%sFetching review threads on #%d...%s\n", colorDim, number, colorReset)
+	data, err := exec.Command("gh", "api", "graphql",
+		"-F", "owner={owner}", "-F", "name={repo}", "-F", "number="+strconv.Itoa(number),
+		"-f", "query="+triage.ReviewThreadsQuery).Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+			return fmt.Errorf("gh api: %s", strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return fmt.Errorf("gh api: %w", err)
+	}
+	pr, err := triage.ParsePullRequest(data, number)
+	if err != nil {
+		return err
+	}
+	if len(pr.Threads) == 0 {
+		fmt.Printf("%s✓ No unresolved review threads%s on #%d - nothing to import\n", colorGreen, colorReset, number)
+		return nil
+	}
+
+	p := triage.PRCommentsPRD(pr)
+	if parent := prdForBranch(pr.Branch); parent != "" {
+		p.ParentPRD = parent
+		fmt.Printf("%sBranch %s was built by %s%s\n", colorDim, pr.Branch, parent, colorReset)
+	}
+	fmt.Printf("\n%d unresolved threads on %q:\n", len(pr.Threads), pr.Title)
+	printTriageTasks(p)
+
+	if dryRun {
+		fmt.Printf("\nDry run: would write %s\n", output)
+		return nil
+	}
+	return writeDraftPRD(p, output, false)
+}
+
+// prdForBranch returns the PRD in brigade/tasks that works on branch, or ""
+// if none does.
+func prdForBranch(branch string) string {
+	if branch == "" {
+		return ""
+	}
+	paths, _ := filepath.Glob("brigade/tasks/prd-*.json")
+	for _, path := range paths {
+		if strings.Count(filepath.Base(path), ".") > 1 { // State, result and other files
+			continue
+		}
+		if p, err := prd.Load(path); err == nil && p.BranchName == branch {
+			return path
+		}
+	}
+	return ""
+}
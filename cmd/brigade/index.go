@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"brigade/internal/config"
+	"brigade/internal/orchestrator"
+	"brigade/internal/retrieval"
+)
+
+var indexCmd = &cobra.Command{
+	Use:   "index",
+	Short: "Build the code index used to add related code to prompts",
+	Long: `Builds a local embedding index of the repo's source files in
+brigade/index.json. Workers then get the chunks most related to their task
+in their prompt, alongside the codebase map.
+
+Only files changed since the last run are re-embedded. Use --rebuild to
+start over, e.g. after changing EMBEDDING_PROVIDER or EMBEDDING_MODEL.
+
+Providers (EMBEDDING_PROVIDER):
+  hash     Built-in keyword hashing. Offline and free (default)
+  ollama   A local Ollama server (EMBEDDING_MODEL default nomic-embed-text)
+  openai   The OpenAI API, using OPENAI_API_KEY (default text-embedding-3-small)
+
+Examples:
+  ./brigade-go index
+  ./brigade-go index --rebuild`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load(cfgFile)
+		if err != nil {
+			return fmt.Errorf("loading config: %w", err)
+		}
+		rebuild, _ := cmd.Flags().GetBool("rebuild")
+		return cmdIndex(cmd.Context(), rebuild, cfg)
+	},
+}
+
+func init() {
+	indexCmd.Flags().Bool("rebuild", false, "re-embed every file instead of only changed ones")
+}
+
+// cmdIndex builds or updates the code index.
+func cmdIndex(ctx context.Context, rebuild bool, cfg *config.Config) error {
+	if err := orchestrator.CheckOfflineEmbeddings(cfg); err != nil {
+		return fmt.Errorf("%w; EMBEDDING_PROVIDER=hash works offline", err)
+	}
+	emb, err := retrieval.NewEmbedder(cfg.EmbeddingProvider, cfg.EmbeddingModel, cfg.EmbeddingURL)
+	if err != nil {
+		return err
+	}
+
+	var prev *retrieval.Index
+	if !rebuild {
+		prev, err = retrieval.LoadIndex(retrieval.IndexPath)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			fmt.Printf("%sIgnoring unreadable index: %v%s\n", colorYellow, err, colorReset)
+		}
+	}
+
+	fmt.Printf("%sIndexing with %s...%s\n", colorDim, emb.Name(), colorReset)
+	start := time.Now()
+	index, stats, err := retrieval.BuildIndex(ctx, ".", emb, prev)
+	if err != nil {
+		return fmt.Errorf("building index: %w", err)
+	}
+	if err := index.Save(retrieval.IndexPath); err != nil {
+		return fmt.Errorf("saving index: %w", err)
+	}
+
+	fmt.Printf("%s✓ Indexed %d files (%d chunks) in %s%s\n",
+		colorGreen, stats.Files, len(index.Chunks), time.Since(start).Round(time.Millisecond), colorReset)
+	if stats.Reused > 0 {
+		fmt.Printf("  %d unchanged files reused, %d chunks embedded\n", stats.Reused, stats.Embedded)
+	}
+	fmt.Printf("  Saved to %s\n", retrieval.IndexPath)
+	if cfg.IndexTopK <= 0 {
+		fmt.Printf("%s  INDEX_TOP_K is 0, so prompts won't use the index%s\n", colorYellow, colorReset)
+	}
+	return nil
+}
@@ -2,26 +2,151 @@ package main
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/spf13/cobra"
 
+	"brigade/internal/config"
+	"brigade/internal/prd"
 	"brigade/internal/util"
 )
 
 var initCmd = &cobra.Command{
 	Use:   "init",
 	Short: "Interactive setup wizard",
-	Long:  `Prepares a project for Brigade by creating configuration and directories.`,
+	Long: `Prepares a project for Brigade by creating configuration and directories.
+
+The config is tailored to the detected stack (test and build commands) and a
+profile: solo-cheap, team-quality, or ci. Init asks for both when not given.
+
+Example:
+  ./brigade-go init --profile team-quality --prd "Add user login"`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return cmdInit()
+		profile, _ := cmd.Flags().GetString("profile")
+		description, _ := cmd.Flags().GetString("prd")
+		if profile != "" && findInitProfile(profile) == nil {
+			return fmt.Errorf("unknown profile %q (want %s)", profile, strings.Join(initProfileNames(), ", "))
+		}
+		return cmdInit(cmd.Context(), profile, description)
+	},
+}
+
+func init() {
+	initCmd.Flags().String("profile", "", "config profile: "+strings.Join(initProfileNames(), ", "))
+	initCmd.Flags().String("prd", "", "plan a starter PRD from this description after setup")
+}
+
+// initProfile is a starting point for brigade.config.
+type initProfile struct {
+	name    string
+	summary string
+	config  string
+}
+
+var initProfiles = []initProfile{
+	{
+		name:    "solo-cheap",
+		summary: "one developer watching costs: reviews line cook work only, no executive escalation",
+		config: `# Executive review: only check the line cook's work
+REVIEW_ENABLED=true
+REVIEW_JUNIOR_ONLY=true
+
+# Escalation: promote to the sous chef on failure, never the executive chef
+ESCALATION_ENABLED=true
+ESCALATION_AFTER=3
+ESCALATION_TO_EXEC=false
+
+# Ask before starting a run projected over $5
+COST_WARN_THRESHOLD=5
+`,
 	},
+	{
+		name:    "team-quality",
+		summary: "shared repos: review every task and phase, run the tests after each task",
+		config: `# Executive review: every task, and each phase as it finishes
+REVIEW_ENABLED=true
+REVIEW_JUNIOR_ONLY=false
+PHASE_REVIEW_ENABLED=true
+
+# Escalation: promote tasks to higher tiers on failure
+ESCALATION_ENABLED=true
+ESCALATION_AFTER=3
+ESCALATION_TO_EXEC=true
+`,
+	},
+	{
+		name:    "ci",
+		summary: "unattended runs: quiet workers, walkaway decisions, a time limit",
+		config: `# Quiet mode: suppress worker conversation output
+QUIET_WORKERS=true
+
+# Walkaway: decide instead of waiting for a human, stop after 2 hours
+WALKAWAY_MODE=true
+WALKAWAY_MAX_DURATION=2h
+
+# Executive review: have Opus review completed work
+REVIEW_ENABLED=true
+
+# Escalation: promote tasks to higher tiers on failure
+ESCALATION_ENABLED=true
+ESCALATION_AFTER=3
+`,
+	},
+}
+
+// profileTestGates is the TEST_GATE each profile uses when there's a TEST_CMD.
+var profileTestGates = map[string]string{
+	"team-quality": "task",
+	"ci":           "phase",
+}
+
+func findInitProfile(name string) *initProfile {
+	for i := range initProfiles {
+		if initProfiles[i].name == name {
+			return &initProfiles[i]
+		}
+	}
+	return nil
+}
+
+func initProfileNames() []string {
+	names := make([]string, len(initProfiles))
+	for i, p := range initProfiles {
+		names[i] = p.name
+	}
+	return names
+}
+
+// chooseInitProfile asks which profile to use, defaulting to the first.
+func chooseInitProfile() *initProfile {
+	for i, p := range initProfiles {
+		fmt.Printf("  %d) %s%s%s - %s\n", i+1, colorBold, p.name, colorReset, p.summary)
+	}
+	for {
+		fmt.Printf("  Profile? (1-%d, default 1) ", len(initProfiles))
+		response, err := stdin.ReadString('\n')
+		response = strings.TrimSpace(response)
+		if response == "" {
+			return &initProfiles[0]
+		}
+		if n, convErr := strconv.Atoi(response); convErr == nil && n >= 1 && n <= len(initProfiles) {
+			return &initProfiles[n-1]
+		}
+		if p := findInitProfile(response); p != nil {
+			return p
+		}
+		if err != nil {
+			return &initProfiles[0]
+		}
+	}
 }
 
-func cmdInit() error {
+func cmdInit(ctx context.Context, profileName, description string) error {
 	fmt.Println()
 	fmt.Printf("%sWelcome to Brigade Kitchen Setup!%s\n\n", colorBold, colorReset)
 	fmt.Println("Let's get your kitchen ready for cooking.")
@@ -58,8 +183,26 @@ func cmdInit() error {
 		return fmt.Errorf("no AI tools found")
 	}
 
-	// Step 2: Create config file
-	fmt.Printf("%sStep 2: Creating configuration...%s\n", colorBold, colorReset)
+	// Step 2: Detect the stack and pick a profile
+	fmt.Printf("%sStep 2: Choosing a profile...%s\n", colorBold, colorReset)
+	stack := prd.DetectProjectStack(".")
+	testCmd, buildCmd := prd.StackCommands(".", stack)
+	if stack == "unknown" {
+		fmt.Printf("  %s○%s Stack not detected; set TEST_CMD and BUILD_CMD yourself\n", colorYellow, colorReset)
+	} else {
+		fmt.Printf("  %s✓%s Detected %s project\n", colorGreen, colorReset, stack)
+	}
+
+	profile := findInitProfile(profileName)
+	if profile == nil {
+		profile = chooseInitProfile()
+	}
+	fmt.Printf("  %s✓%s Using the %s profile\n", colorGreen, colorReset, profile.name)
+	content := initConfig(profile, testCmd, buildCmd, opencodeFound)
+
+	// Step 3: Create config file
+	fmt.Println()
+	fmt.Printf("%sStep 3: Creating configuration...%s\n", colorBold, colorReset)
 
 	configPath := "brigade/brigade.config"
 	// If we can find where brigade.sh is, use that directory
@@ -72,19 +215,22 @@ func cmdInit() error {
 		if !confirmPrompt("  Overwrite? (y/N) ", false) {
 			fmt.Printf("  %sKeeping existing config.%s\n", colorDim, colorReset)
 		} else {
-			if err := createDefaultConfig(configPath); err != nil {
+			if err := createDefaultConfig(configPath, content); err != nil {
 				return err
 			}
 		}
 	} else {
-		if err := createDefaultConfig(configPath); err != nil {
+		if err := createDefaultConfig(configPath, content); err != nil {
 			return err
 		}
 	}
+	if suggestions := prd.SuggestVerification(&prd.Task{}, stack); len(suggestions) > 0 {
+		fmt.Printf("  %sSuggested task verification: %s%s\n", colorDim, suggestions[0].Cmd, colorReset)
+	}
 
-	// Step 3: Create directories
+	// Step 4: Create directories
 	fmt.Println()
-	fmt.Printf("%sStep 3: Setting up directories...%s\n", colorBold, colorReset)
+	fmt.Printf("%sStep 4: Setting up directories...%s\n", colorBold, colorReset)
 
 	dirs := []string{"brigade/tasks", "brigade/notes", "brigade/logs"}
 	for _, dir := range dirs {
@@ -94,14 +240,32 @@ func cmdInit() error {
 		fmt.Printf("  %s✓%s Created %s/\n", colorGreen, colorReset, dir)
 	}
 
-	// Step 4: Check/update .gitignore
+	// Step 5: Check/update .gitignore
 	fmt.Println()
-	fmt.Printf("%sStep 4: Checking .gitignore...%s\n", colorBold, colorReset)
+	fmt.Printf("%sStep 5: Checking .gitignore...%s\n", colorBold, colorReset)
 
 	if err := updateGitignore(); err != nil {
 		return err
 	}
 
+	// Step 6: Optionally plan a starter PRD
+	fmt.Println()
+	fmt.Printf("%sStep 6: Planning a starter PRD...%s\n", colorBold, colorReset)
+	if description == "" {
+		description = readLine("  Describe a first feature to plan (blank to skip): ")
+	}
+	if description == "" {
+		fmt.Printf("  %sSkipped.%s\n", colorDim, colorReset)
+	} else {
+		cfg, err := config.Load(configPath)
+		if err != nil {
+			return fmt.Errorf("loading config: %w", err)
+		}
+		if err := cmdPlan(ctx, description, cfg); err != nil {
+			return fmt.Errorf("planning starter PRD: %w", err)
+		}
+	}
+
 	// Final message
 	fmt.Println()
 	fmt.Printf("%s╔═══════════════════════════════════════════════════════════╗%s\n", colorGreen, colorReset)
@@ -112,6 +276,9 @@ func cmdInit() error {
 	fmt.Println()
 	fmt.Printf("  Try a demo:     %s./brigade.sh demo%s\n", colorCyan, colorReset)
 	fmt.Printf("  Plan a feature: %s./brigade.sh plan \"Add user login\"%s\n", colorCyan, colorReset)
+	if profile.name == "ci" {
+		fmt.Printf("  Run in CI:      %s./brigade-go service --ci github brigade/tasks/prd-*.json%s\n", colorCyan, colorReset)
+	}
 	fmt.Println()
 
 	return nil
@@ -131,25 +298,40 @@ func findBrigadeScriptDir() string {
 	return ""
 }
 
-func createDefaultConfig(path string) error {
+// initConfig renders brigade.config for a profile and the detected stack's
+// commands.
+func initConfig(profile *initProfile, testCmd, buildCmd string, opencode bool) string {
+	var sb strings.Builder
+	sb.WriteString("# Brigade Kitchen Configuration\n")
+	sb.WriteString("# See brigade.config.example for all options\n")
+	sb.WriteString(fmt.Sprintf("# Profile: %s\n\n", profile.name))
+
+	if opencode && profile.name == "solo-cheap" {
+		sb.WriteString("# OpenCode line cook for cost savings\nUSE_OPENCODE=true\n\n")
+	}
+	sb.WriteString(profile.config)
+
+	if testCmd != "" || buildCmd != "" {
+		sb.WriteString("\n# Testing: commands for the detected stack\n")
+		if testCmd != "" {
+			sb.WriteString(fmt.Sprintf("TEST_CMD=%q\n", testCmd))
+			if gate := profileTestGates[profile.name]; gate != "" {
+				sb.WriteString("TEST_GATE=" + gate + "\n")
+			}
+		}
+		if buildCmd != "" {
+			sb.WriteString(fmt.Sprintf("BUILD_CMD=%q\n", buildCmd))
+		}
+	}
+	return sb.String()
+}
+
+func createDefaultConfig(path, content string) error {
 	// Ensure directory exists
 	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
 		return err
 	}
 
-	content := `# Brigade Kitchen Configuration
-# See brigade.config.example for all options
-
-# Quiet mode: suppress worker conversation output
-QUIET_WORKERS=false
-
-# Executive review: have Opus review completed work
-REVIEW_ENABLED=true
-
-# Escalation: promote tasks to higher tiers on failure
-ESCALATION_ENABLED=true
-ESCALATION_AFTER=3
-`
 	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
 		return err
 	}
@@ -219,11 +401,19 @@ func updateGitignore() error {
 	return nil
 }
 
-func confirmPrompt(prompt string, defaultYes bool) bool {
-	reader := bufio.NewReader(os.Stdin)
+// stdin is shared by the prompts so input read ahead for one answer isn't
+// lost to the next.
+var stdin = bufio.NewReader(os.Stdin)
+
+// readLine prints prompt and returns the trimmed line typed in reply.
+func readLine(prompt string) string {
 	fmt.Print(prompt)
-	response, _ := reader.ReadString('\n')
-	response = strings.TrimSpace(strings.ToLower(response))
+	response, _ := stdin.ReadString('\n')
+	return strings.TrimSpace(response)
+}
+
+func confirmPrompt(prompt string, defaultYes bool) bool {
+	response := strings.ToLower(readLine(prompt))
 
 	if response == "" {
 		return defaultYes
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -32,11 +33,11 @@ Example:
 			return fmt.Errorf("loading config: %w", err)
 		}
 		description := strings.Join(args, " ")
-		return cmdIterate(description, cfg)
+		return cmdIterate(cmd.Context(), description, cfg)
 	},
 }
 
-func cmdIterate(description string, cfg *config.Config) error {
+func cmdIterate(ctx context.Context, description string, cfg *config.Config) error {
 	// Find most recently completed PRD
 	parentPRD := findCompletedPRD()
 	if parentPRD == "" {
@@ -83,6 +84,9 @@ func cmdIterate(description string, cfg *config.Config) error {
 	iterPRD := prd.PRD{
 		FeatureName: fmt.Sprintf("Iteration: %s", description),
 		BranchName:  parentP.BranchName,
+		CreatedAt:   time.Now().Format(time.RFC3339),
+		Description: fmt.Sprintf("Iteration on %s", parentP.FeatureName),
+		ParentPRD:   parentPRD,
 		Tasks: []prd.Task{
 			{
 				ID:                 "ITER-001",
@@ -106,10 +110,7 @@ func cmdIterate(description string, cfg *config.Config) error {
 
 	fmt.Printf("%s✓%s Created iteration PRD: %s\n\n", colorGreen, colorReset, iterPRDPath)
 
-	// Set parent context environment variable
-	os.Setenv("ITERATION_PARENT_PRD", parentPRD)
-
-	// Execute the iteration task using orchestrator
+	// Execute the iteration task; the orchestrator loads parent context from ParentPRD
 	orch, err := orchestrator.New(orchestrator.Options{
 		Config:    cfg,
 		PRDPath:   iterPRDPath,
@@ -119,7 +120,7 @@ func cmdIterate(description string, cfg *config.Config) error {
 		return err
 	}
 
-	if err := orch.Run(nil); err != nil {
+	if err := orch.Run(ctx); err != nil {
 		fmt.Println()
 		fmt.Printf("%sIteration task did not complete successfully.%s\n", colorYellow, colorReset)
 		fmt.Printf("%sPRD preserved: %s%s\n", colorDim, iterPRDPath, colorReset)
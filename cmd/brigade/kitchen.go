@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"brigade/internal/state"
+	"brigade/internal/util"
+)
+
+// maxReportErrors is how many error categories the kitchen report lists.
+const maxReportErrors = 3
+
+// formatKitchenReport renders the summary printed when a service run ends.
+func formatKitchenReport(r *state.RunReport) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("\n%sKitchen report%s\n", colorBold, colorReset))
+	sb.WriteString(fmt.Sprintf("  Wall time:    %s\n", util.FormatDuration(time.Duration(r.DurationSeconds)*time.Second)))
+
+	worked := 0
+	for _, seconds := range r.TierSeconds {
+		worked += seconds
+	}
+	if worked > 0 {
+		var shares []string
+		for _, tier := range []state.WorkerTier{state.TierLine, state.TierSous, state.TierExecutive} {
+			if seconds := r.TierSeconds[tier]; seconds > 0 {
+				shares = append(shares, fmt.Sprintf("%s %s", tier, util.FormatPercent(float64(seconds), float64(worked))))
+			}
+		}
+		sb.WriteString(fmt.Sprintf("  Worker time:  %s (%s)\n",
+			util.FormatDuration(time.Duration(worked)*time.Second), strings.Join(shares, ", ")))
+	}
+
+	sb.WriteString(fmt.Sprintf("  Iterations:   %d for %d completed task(s), %d escalation(s)\n", r.Iterations, r.Completed, r.Escalations))
+	if reviews := r.ReviewsPassed + r.ReviewsFailed; reviews > 0 {
+		line := fmt.Sprintf("%d (%s%d passed%s, %s%d failed%s)",
+			reviews, colorGreen, r.ReviewsPassed, colorReset, colorRed, r.ReviewsFailed, colorReset)
+		if r.ReviewsReused > 0 {
+			line += fmt.Sprintf(", %d reused for repeated changes", r.ReviewsReused)
+		}
+		sb.WriteString("  Reviews:      " + line + "\n")
+	}
+	sb.WriteString(fmt.Sprintf("  Cost:         ~%s\n", util.FormatCost(r.EstimatedCost)))
+
+	if len(r.ErrorCategories) > 0 {
+		categories := make([]string, 0, len(r.ErrorCategories))
+		for category := range r.ErrorCategories {
+			categories = append(categories, category)
+		}
+		sort.Slice(categories, func(i, j int) bool {
+			a, b := r.ErrorCategories[categories[i]], r.ErrorCategories[categories[j]]
+			if a != b {
+				return a > b
+			}
+			return categories[i] < categories[j]
+		})
+		var top []string
+		for _, category := range categories[:min(len(categories), maxReportErrors)] {
+			top = append(top, fmt.Sprintf("%s %d", category, r.ErrorCategories[category]))
+		}
+		sb.WriteString(fmt.Sprintf("  Top errors:   %s\n", strings.Join(top, ", ")))
+	}
+	return sb.String()
+}
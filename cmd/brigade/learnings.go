@@ -0,0 +1,135 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"brigade/internal/config"
+	"brigade/internal/learnings"
+)
+
+var learningsCmd = &cobra.Command{
+	Use:   "learnings",
+	Short: "Share learnings and retry strategies between repos",
+	Long: `Moves institutional knowledge between repos as a portable bundle.
+
+A bundle holds the entries of LEARNINGS_FILE, the custom error patterns of
+SMART_RETRY_CUSTOM_PATTERNS, and the per-category suggestions of
+SMART_RETRY_STRATEGIES_FILE. Export one from a repo whose Brigade has
+learned its pitfalls and import it into a new repo to start from there.`,
+}
+
+var learningsExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Write learnings and retry strategies to a bundle",
+	Long: `Writes this repo's learnings and retry strategies to a JSON bundle.
+
+Duplicate learnings are dropped. Use --match to curate: only learnings
+containing one of the given terms are exported.
+
+Examples:
+  ./brigade-go learnings export
+  ./brigade-go learnings export -o ~/team/brigade-knowledge.json --match postgres --match migration`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load(cfgFile)
+		if err != nil {
+			return fmt.Errorf("loading config: %w", err)
+		}
+		output, _ := cmd.Flags().GetString("output")
+		match, _ := cmd.Flags().GetStringArray("match")
+		return cmdLearningsExport(cfg, output, match)
+	},
+}
+
+var learningsImportCmd = &cobra.Command{
+	Use:   "import <bundle.json>",
+	Short: "Merge a bundle into this repo's learnings and retry strategies",
+	Long: `Merges a bundle written by 'learnings export' into this repo.
+
+New learnings are appended to LEARNINGS_FILE; ones already there are
+skipped. Strategy suggestions are added to SMART_RETRY_STRATEGIES_FILE
+(brigade/retry-strategies.json if unset) for categories that don't have
+one yet; existing suggestions are kept. Custom error patterns live in
+brigade.config, so the merged SMART_RETRY_CUSTOM_PATTERNS line is printed
+for you to paste.
+
+Examples:
+  ./brigade-go learnings import ~/team/brigade-knowledge.json --dry-run
+  ./brigade-go learnings import ~/team/brigade-knowledge.json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load(cfgFile)
+		if err != nil {
+			return fmt.Errorf("loading config: %w", err)
+		}
+		return cmdLearningsImport(cfg, args[0], dryRun)
+	},
+}
+
+func init() {
+	learningsExportCmd.Flags().StringP("output", "o", "brigade-knowledge.json", "bundle to write")
+	learningsExportCmd.Flags().StringArray("match", nil, "only export learnings containing this term (repeatable)")
+	learningsCmd.AddCommand(learningsExportCmd)
+	learningsCmd.AddCommand(learningsImportCmd)
+}
+
+func cmdLearningsExport(cfg *config.Config, output string, match []string) error {
+	b, err := learnings.Export(cfg, match)
+	if err != nil {
+		return fmt.Errorf("exporting learnings: %w", err)
+	}
+	if err := b.Write(output); err != nil {
+		return fmt.Errorf("writing bundle: %w", err)
+	}
+
+	fmt.Printf("%s✓%s Wrote %s\n", colorGreen, colorReset, output)
+	fmt.Printf("  Learnings:       %d\n", len(b.Learnings))
+	fmt.Printf("  Error patterns:  %d\n", len(b.Patterns))
+	fmt.Printf("  Strategies:      %d\n", len(b.Strategies))
+	return nil
+}
+
+func cmdLearningsImport(cfg *config.Config, path string, dryRun bool) error {
+	b, err := learnings.Load(path)
+	if err != nil {
+		return fmt.Errorf("loading bundle: %w", err)
+	}
+	result, err := learnings.Import(b, cfg, dryRun)
+	if err != nil {
+		return fmt.Errorf("importing bundle: %w", err)
+	}
+
+	verb := "Imported"
+	if dryRun {
+		verb = "Would import"
+	}
+	source := ""
+	if b.Source != "" {
+		source = fmt.Sprintf(" (from %s, %s)", b.Source, b.Exported.Format("2006-01-02"))
+	}
+	fmt.Printf("%s%s%s%s\n", colorBold, verb, colorReset, source)
+	fmt.Printf("  Learnings:       %d new → %s", result.Learnings, cfg.LearningsFile)
+	if result.DuplicateLearnings > 0 {
+		fmt.Printf(" (%d already known)", result.DuplicateLearnings)
+	}
+	fmt.Println()
+	if len(result.Strategies) > 0 {
+		fmt.Printf("  Strategies:      %s → %s\n", strings.Join(result.Strategies, ", "), result.StrategiesFile)
+	}
+	if len(result.KeptStrategies) > 0 {
+		fmt.Printf("  Kept local:      %s\n", strings.Join(result.KeptStrategies, ", "))
+	}
+
+	if len(result.Strategies) > 0 && cfg.SmartRetryStrategiesFile == "" {
+		fmt.Printf("\n%s⚠%s Set SMART_RETRY_STRATEGIES_FILE=\"%s\" in brigade.config to use the imported strategies.\n",
+			colorYellow, colorReset, result.StrategiesFile)
+	}
+	if result.Patterns > 0 {
+		fmt.Printf("\n%d new error patterns. Update brigade.config:\n", result.Patterns)
+		fmt.Printf("  SMART_RETRY_CUSTOM_PATTERNS=\"%s\"\n", result.PatternsValue)
+	}
+	return nil
+}
@@ -4,18 +4,31 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
+	"os/signal"
+	"path"
+	"path/filepath"
+	"sort"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/spf13/cobra"
 
+	"brigade/internal/ci"
 	"brigade/internal/config"
+	"brigade/internal/cost"
+	"brigade/internal/module"
 	"brigade/internal/orchestrator"
+	"brigade/internal/policy"
 	"brigade/internal/prd"
 	"brigade/internal/state"
+	"brigade/internal/supervisor"
+	"brigade/internal/util"
+	"brigade/internal/workspace"
 )
 
 var (
@@ -30,16 +43,35 @@ var (
 	autoContinue bool
 	forceFlag    bool
 
+	// Output: --no-color, whether stdout is a terminal (not a pipe, file or
+	// theme), and the theme's flush before exiting
+	noColor        bool
+	stdoutTerminal bool
+	flushOutput    = func() {}
+
 	// Partial execution flags
 	onlyTasks  []string
 	skipTasks  []string
 	fromTask   string
 	untilTask  string
+	onlyTags   []string
 )
 
 func main() {
-	if err := rootCmd.Execute(); err != nil {
-		os.Exit(1)
+	// Escape codes are noise when output isn't a terminal, or unwanted
+	// (https://no-color.org)
+	stdoutTerminal = util.IsTerminal(os.Stdout)
+	if !stdoutTerminal || os.Getenv("NO_COLOR") != "" {
+		disableColors()
+	}
+
+	// One context for the whole run: Ctrl-C or SIGTERM cancels it
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	err := rootCmd.ExecuteContext(ctx)
+	stop()
+	flushOutput()
+	if err != nil {
+		os.Exit(exitCode(err))
 	}
 }
 
@@ -55,6 +87,12 @@ It uses a kitchen metaphor:
 
 For more information: https://github.com/anthropics/brigade`,
 	Version: Version,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		// The MCP server speaks JSON-RPC on stdout
+		if cmd != mcpCmd {
+			applyTheme()
+		}
+	},
 }
 
 func init() {
@@ -65,15 +103,18 @@ func init() {
 	rootCmd.PersistentFlags().BoolVar(&walkawayMode, "walkaway", false, "autonomous execution mode")
 	rootCmd.PersistentFlags().BoolVar(&autoContinue, "auto-continue", false, "chain multiple PRDs")
 	rootCmd.PersistentFlags().BoolVar(&forceFlag, "force", false, "override existing service lock")
+	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "plain output: no colors or emoji (BRIGADE_THEME=plain)")
 
 	// Partial execution flags
 	rootCmd.PersistentFlags().StringSliceVar(&onlyTasks, "only", nil, "run specific tasks only")
 	rootCmd.PersistentFlags().StringSliceVar(&skipTasks, "skip", nil, "skip specific tasks")
 	rootCmd.PersistentFlags().StringVar(&fromTask, "from", "", "start from task (inclusive)")
 	rootCmd.PersistentFlags().StringVar(&untilTask, "until", "", "run until task (inclusive)")
+	rootCmd.PersistentFlags().StringSliceVar(&onlyTags, "tags", nil, "run or report only tasks with these tags")
 
 	// Add commands
 	rootCmd.AddCommand(serviceCmd)
+	rootCmd.AddCommand(serveCmd)
 	rootCmd.AddCommand(validateCmd)
 	rootCmd.AddCommand(statusCmd)
 	rootCmd.AddCommand(summaryCmd)
@@ -81,6 +122,14 @@ func init() {
 	rootCmd.AddCommand(ticketCmd)
 	rootCmd.AddCommand(costCmd)
 	rootCmd.AddCommand(riskCmd)
+	rootCmd.AddCommand(statsCmd)
+	rootCmd.AddCommand(unlockCmd)
+	rootCmd.AddCommand(cleanCmd)
+	rootCmd.AddCommand(nudgeCmd)
+	rootCmd.AddCommand(stateCmd)
+	rootCmd.AddCommand(fsckCmd)
+	rootCmd.AddCommand(reviewCmd)
+	rootCmd.AddCommand(showCmd)
 
 	// Phase 2: New user flow commands
 	rootCmd.AddCommand(initCmd)
@@ -89,14 +138,24 @@ func init() {
 
 	// Phase 3: Convenience commands
 	rootCmd.AddCommand(templateCmd)
+	rootCmd.AddCommand(chefCmd)
 	rootCmd.AddCommand(iterateCmd)
 	rootCmd.AddCommand(mapCmd)
+	rootCmd.AddCommand(indexCmd)
 	rootCmd.AddCommand(exploreCmd)
+	rootCmd.AddCommand(triageCmd)
+	rootCmd.AddCommand(importCmd)
+	rootCmd.AddCommand(learningsCmd)
 
 	// Phase 4: Reference commands
 	rootCmd.AddCommand(superviseCmd)
 	rootCmd.AddCommand(analyzeCmd)
+	rootCmd.AddCommand(graphCmd)
+	rootCmd.AddCommand(prdCmd)
+	rootCmd.AddCommand(replanCmd)
 	rootCmd.AddCommand(opencodeModelsCmd)
+	rootCmd.AddCommand(mcpCmd)
+	rootCmd.AddCommand(moduleCmd)
 }
 
 // serviceCmd runs the Brigade service.
@@ -120,19 +179,89 @@ var serviceCmd = &cobra.Command{
 		if forceFlag {
 			cfg.ForceOverrideLock = true
 		}
+		if autoContinue {
+			cfg.AutoContinue = true
+		}
+		if record, _ := cmd.Flags().GetString("record"); record != "" {
+			cfg.RecordFile = record
+		}
+		if replay, _ := cmd.Flags().GetString("replay"); replay != "" {
+			cfg.ReplayFile = replay
+		}
+		if deterministic, _ := cmd.Flags().GetBool("deterministic"); deterministic {
+			cfg.Deterministic = true
+		}
+		if cmd.Flags().Changed("seed") {
+			cfg.DeterministicSeed, _ = cmd.Flags().GetInt("seed")
+			if cfg.DeterministicSeed == 0 {
+				return fmt.Errorf("--seed can't be 0")
+			}
+		}
+		ciMode, _ := cmd.Flags().GetString("ci")
+		if ciMode != "" && ciMode != "github" {
+			return fmt.Errorf("unknown --ci mode %q (supported: github)", ciMode)
+		}
+		if ciMode != "" {
+			disableColors()
+		}
 
 		// Set up logger
 		logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
 			Level: slog.LevelInfo,
 		}))
 
-		for _, prdPath := range args {
+		// Run prerequisite PRDs first when chaining
+		if cfg.AutoContinue && len(args) > 1 {
+			if args, err = prd.OrderByPrerequisites(args); err != nil {
+				return err
+			}
+		}
+
+		sessionStart := time.Now()
+		var handoffs []string
+		for i, prdPath := range args {
 			fmt.Printf("Processing %s...\n", prdPath)
 
 			if dryRun {
 				return previewExecution(prdPath, cfg)
 			}
 
+			acceptRisk, _ := cmd.Flags().GetBool("accept-risk")
+			if err := checkRiskThreshold(prdPath, cfg, acceptRisk); err != nil {
+				return err
+			}
+
+			p, err := prd.Load(prdPath)
+			if err != nil {
+				return err
+			}
+			useWorktree, _ := cmd.Flags().GetBool("worktree")
+			useWorktree = useWorktree || cfg.PRDWorktree
+			chefDir := ""
+			leave := func() {}
+			if useWorktree {
+				prdPath, chefDir, leave, err = enterWorktree(cfg, p, prdPath)
+				if err != nil {
+					return err
+				}
+			}
+			var gh *ci.GitHub
+			var onEvent func(*module.Event)
+			if ciMode == "github" {
+				gh = ci.NewGitHub(os.Stdout, p)
+				onEvent = gh.HandleEvent
+			}
+
+			acceptCost, _ := cmd.Flags().GetBool("accept-cost")
+			allowProtected, _ := cmd.Flags().GetBool("allow-protected")
+			var confirmCost func(estimate, threshold float64) bool
+			if util.IsTerminal(os.Stdin) {
+				confirmCost = func(estimate, threshold float64) bool {
+					fmt.Print(estimateCost(p, cfg))
+					return confirmPrompt(fmt.Sprintf("Projected cost %s exceeds COST_WARN_THRESHOLD %s. Start anyway? (y/N) ", util.FormatCost(estimate), util.FormatCost(threshold)), false)
+				}
+			}
+
 			orch, err := orchestrator.New(orchestrator.Options{
 				Config:        cfg,
 				PRDPath:       prdPath,
@@ -140,20 +269,66 @@ var serviceCmd = &cobra.Command{
 				DryRun:        dryRun,
 				Sequential:    sequential,
 				WalkawayMode:  walkawayMode,
+				SessionStart:  sessionStart,
 				OnlyTasks:     onlyTasks,
 				SkipTasks:     skipTasks,
 				FromTask:      fromTask,
 				UntilTask:     untilTask,
+				Tags:          onlyTags,
+				OnEvent:       onEvent,
+				AcceptCost:    acceptCost,
+				ConfirmCost:   confirmCost,
+				AllowProtected: allowProtected,
+				ChefDir:       chefDir,
+				Worktree:      useWorktree,
+				Handoffs:      handoffs,
 			})
 			if err != nil {
+				leave()
 				return err
 			}
 
-			if err := orch.Run(context.Background()); err != nil {
+			err = orch.Run(cmd.Context())
+			if result := orch.Result(); result != nil && result.Report != nil {
+				fmt.Print(formatKitchenReport(result.Report))
+			}
+
+			// PHASE_GATE decides whether the chain goes on
+			var gateErr error
+			if err == nil && cfg.AutoContinue && i < len(args)-1 {
+				var confirmGate func(question string) bool
+				if util.IsTerminal(os.Stdin) {
+					confirmGate = func(question string) bool {
+						return confirmPrompt(question, true)
+					}
+				}
+				gateErr = orch.Gate(cmd.Context(), args[i+1], confirmGate)
+			}
+			if handoff, absErr := filepath.Abs(orch.HandoffPath()); absErr == nil {
+				handoffs = []string{handoff}
+			}
+			leave()
+			if gh != nil {
+				if err != nil {
+					gh.Error(err)
+				}
+				if summaryErr := ci.WriteSummary(os.Getenv("GITHUB_STEP_SUMMARY"), orch.Result()); summaryErr != nil {
+					logger.Warn("failed to write job summary", "error", summaryErr)
+				}
+			}
+			if err != nil {
 				return err
 			}
+			if errors.Is(gateErr, orchestrator.ErrChainPaused) {
+				fmt.Printf("\nPaused before %s (PHASE_GATE=pause). Continue with:\n  ./brigade-go service --auto-continue %s\n",
+					args[i+1], strings.Join(args[i+1:], " "))
+				return nil
+			}
+			if gateErr != nil {
+				return gateErr
+			}
 
-			if !autoContinue {
+			if !cfg.AutoContinue {
 				break
 			}
 		}
@@ -162,6 +337,18 @@ var serviceCmd = &cobra.Command{
 	},
 }
 
+func init() {
+	serviceCmd.Flags().String("record", "", "record worker prompts and responses to this file")
+	serviceCmd.Flags().String("replay", "", "serve worker responses from a recording instead of running workers")
+	serviceCmd.Flags().String("ci", "", "CI output mode (github: groups, annotations, job summary)")
+	serviceCmd.Flags().Bool("deterministic", false, "pin worker sampling, run tasks in order and reuse nothing, for benchmarking")
+	serviceCmd.Flags().Int("seed", 1, "seed for --deterministic")
+	serviceCmd.Flags().Bool("accept-risk", false, "start even if the PRD's risk level meets RISK_WARN_THRESHOLD")
+	serviceCmd.Flags().Bool("accept-cost", false, "start even if the projected cost exceeds COST_WARN_THRESHOLD")
+	serviceCmd.Flags().Bool("allow-protected", false, "let tasks change PROTECTED_PATHS")
+	serviceCmd.Flags().Bool("worktree", false, "run the PRD in its own worktree on its branch, rebased onto the default branch when done")
+}
+
 // validateCmd validates a PRD file.
 var validateCmd = &cobra.Command{
 	Use:   "validate <prd.json>",
@@ -174,14 +361,8 @@ var validateCmd = &cobra.Command{
 		}
 
 		cfg, _ := config.Load(cfgFile)
-		opts := prd.ValidationOptions{
-			LintCriteria:           cfg.CriteriaLintEnabled,
-			CheckVerificationTypes: true,
-			WarnGrepOnly:           cfg.VerificationWarnGrepOnly,
-			WalkawayMode:           cfg.WalkawayMode,
-		}
-
-		result := p.ValidateFull(opts)
+		result := p.ValidateFull(validationOptions(cfg))
+		checkWorkspaces(p, result)
 
 		// Print errors
 		if len(result.Errors) > 0 {
@@ -199,15 +380,71 @@ var validateCmd = &cobra.Command{
 			}
 		}
 
+		// Suggest verification for tasks that have none
+		if cfg.VerificationScaffoldEnabled {
+			printVerificationSuggestions(p)
+		}
+
 		if result.IsValid() {
 			fmt.Printf("✓ PRD is valid: %d tasks\n", len(p.Tasks))
 			return nil
 		}
 
-		return fmt.Errorf("validation failed with %d errors", len(result.Errors))
+		return result.Err(args[0])
 	},
 }
 
+// validationOptions returns the checks `validate` runs under cfg.
+func validationOptions(cfg *config.Config) prd.ValidationOptions {
+	return prd.ValidationOptions{
+		LintCriteria:           cfg.CriteriaLintEnabled,
+		CheckVerificationTypes: true,
+		WarnGrepOnly:           cfg.VerificationWarnGrepOnly,
+		CheckTraceability:      cfg.CriteriaLintEnabled,
+		WalkawayMode:           cfg.WalkawayMode,
+	}
+}
+
+// printVerificationSuggestions lists suggested verification commands for
+// tasks without any, targeting each task's files when it names them.
+func printVerificationSuggestions(p *prd.PRD) {
+	stack := prd.DetectProjectStack(".")
+	printed := false
+	for i := range p.Tasks {
+		task := &p.Tasks[i]
+		if len(task.Verification) > 0 || task.ManualVerification {
+			continue
+		}
+		suggestions := prd.SuggestVerification(task, stack)
+		if len(suggestions) == 0 {
+			continue
+		}
+		if !printed {
+			fmt.Println("Suggested verification:")
+			printed = true
+		}
+		for _, v := range suggestions {
+			fmt.Printf("  %s [%s] %s\n", task.ID, v.Type, v.Cmd)
+		}
+	}
+}
+
+// checkWorkspaces warns about task workspaces that don't exist or aren't
+// members of the detected monorepo workspace.
+func checkWorkspaces(p *prd.PRD, result *prd.ValidationResult) {
+	ws := workspace.Detect(".")
+	for _, task := range p.Tasks {
+		if task.Workspace == "" {
+			continue
+		}
+		if info, err := os.Stat(task.Workspace); err != nil || !info.IsDir() {
+			result.AddWarning(task.ID, "workspace", fmt.Sprintf("directory %s not found", task.Workspace))
+		} else if ws != nil && !ws.Contains(path.Clean(task.Workspace)) {
+			result.AddWarning(task.ID, "workspace", fmt.Sprintf("%s is not a %s workspace member", task.Workspace, ws.Kind))
+		}
+	}
+}
+
 // statusCmd shows execution status.
 var statusCmd = &cobra.Command{
 	Use:   "status [prd.json]",
@@ -216,6 +453,15 @@ var statusCmd = &cobra.Command{
 		jsonOutput, _ := cmd.Flags().GetBool("json")
 		briefOutput, _ := cmd.Flags().GetBool("brief")
 		watchMode, _ := cmd.Flags().GetBool("watch")
+		allHosts, _ := cmd.Flags().GetBool("all-hosts")
+
+		if allHosts {
+			dir := "brigade/tasks"
+			if len(args) > 0 {
+				dir = filepath.Dir(args[0])
+			}
+			return showAllHosts(dir, jsonOutput)
+		}
 
 		// Find PRD if not specified
 		var prdPath string
@@ -229,32 +475,34 @@ var statusCmd = &cobra.Command{
 			}
 		}
 
-		for {
+		render := func() (string, error) {
 			status, err := getStatus(prdPath)
 			if err != nil {
-				return err
+				return "", err
 			}
-
-			if jsonOutput || briefOutput {
-				if briefOutput {
-					fmt.Println(status.Brief())
-				} else {
-					fmt.Println(status.JSON())
-				}
-			} else {
-				fmt.Print(status.Format())
+			switch {
+			case briefOutput:
+				return status.Brief() + "\n", nil
+			case jsonOutput:
+				return status.JSON() + "\n", nil
 			}
+			return status.Format(), nil
+		}
 
-			if !watchMode {
-				break
+		if !watchMode {
+			output, err := render()
+			if err != nil {
+				return err
 			}
-
-			cfg, _ := config.Load(cfgFile)
-			time.Sleep(cfg.StatusWatchInterval)
-			fmt.Print("\033[H\033[2J") // Clear screen
+			fmt.Print(output)
+			return nil
 		}
 
-		return nil
+		cfg, err := config.Load(cfgFile)
+		if err != nil {
+			cfg = config.Default()
+		}
+		return watch(cmd.Context(), os.Stdout, stdoutTerminal, jsonOutput || briefOutput, cfg.StatusWatchInterval, render)
 	},
 }
 
@@ -263,6 +511,63 @@ func init() {
 	statusCmd.Flags().Bool("brief", false, "ultra-compact JSON")
 	statusCmd.Flags().BoolP("watch", "w", false, "auto-refresh")
 	statusCmd.Flags().Bool("all", false, "show all escalations")
+	statusCmd.Flags().Bool("all-hosts", false, "list services running on any host sharing this workspace")
+}
+
+// showAllHosts lists every service lock in dir, including those held by
+// other machines sharing the workspace.
+func showAllHosts(dir string, jsonOutput bool) error {
+	cfg, err := config.Load(cfgFile)
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+
+	locks, err := state.FindServiceLocks(dir, state.WithHeartbeatInterval(cfg.LockHeartbeatInterval))
+	if err != nil {
+		return fmt.Errorf("scanning locks: %w", err)
+	}
+
+	if jsonOutput {
+		data, _ := json.MarshalIndent(locks, "", "  ")
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(locks) == 0 {
+		fmt.Printf("No running services found in %s\n", dir)
+		return nil
+	}
+
+	fmt.Printf("%sServices in %s%s\n\n", colorBold, dir, colorReset)
+	for _, l := range locks {
+		marker := colorGreen + "●" + colorReset
+		if l.Stale {
+			marker = colorYellow + "○" + colorReset
+		}
+		fmt.Printf("  %s %s\n", marker, filepath.Base(l.PRDPath))
+		if l.Holder == nil {
+			fmt.Printf("      %sholder unknown%s\n", colorDim, colorReset)
+			continue
+		}
+		host := l.Holder.Host
+		if host == "" {
+			host = "unknown host"
+		}
+		if l.Holder.IsLocal() {
+			host += " (this host)"
+		}
+		fmt.Printf("      %s, PID %d\n", host, l.Holder.PID)
+		if !l.Holder.StartedAt.IsZero() {
+			fmt.Printf("      started %s\n", l.Holder.StartedAt.Format("2006-01-02 15:04:05"))
+		}
+		if !l.Holder.Heartbeat.IsZero() {
+			fmt.Printf("      heartbeat %s ago\n", time.Since(l.Holder.Heartbeat).Round(time.Second))
+		}
+		if l.Stale {
+			fmt.Printf("      %sstale - remove with: ./brigade-go unlock %s%s\n", colorYellow, l.PRDPath, colorReset)
+		}
+	}
+	return nil
 }
 
 // summaryCmd generates a summary report.
@@ -282,6 +587,7 @@ var summaryCmd = &cobra.Command{
 			return err
 		}
 
+		applyTagFilter(p)
 		fmt.Println(generateSummary(p, st))
 		return nil
 	},
@@ -324,17 +630,23 @@ var resumeCmd = &cobra.Command{
 		}
 
 		if st.CurrentTask != "" && action == "" {
-			fmt.Printf("Task %s was in progress. Use 'retry' or 'skip' to continue.\n", st.CurrentTask)
-			return nil
+			// An attempt cut off by a crash is retried with what it left behind
+			if st.OrphanedTask() == "" {
+				fmt.Printf("Task %s was in progress. Use 'retry' or 'skip' to continue.\n", st.CurrentTask)
+				return nil
+			}
+			fmt.Printf("Task %s was interrupted mid-attempt. Retrying with the working tree changes as context.\n", st.CurrentTask)
 		}
 
 		if action == "skip" && st.CurrentTask != "" {
 			// Mark current task as skipped
-			st.AddTaskHistory(state.TaskHistory{
-				TaskID: st.CurrentTask,
-				Worker: state.TierLine,
-				Status: state.StatusSkipped,
-			})
+			if !st.ResolveAttempt(st.CurrentTask, state.StatusSkipped, "", "") {
+				st.AddTaskHistory(state.TaskHistory{
+					TaskID: st.CurrentTask,
+					Worker: state.TierLine,
+					Status: state.StatusSkipped,
+				})
+			}
 			st.ClearCurrentTask()
 			if err := store.Save(st); err != nil {
 				return err
@@ -350,7 +662,7 @@ var resumeCmd = &cobra.Command{
 			return err
 		}
 
-		return orch.Run(context.Background())
+		return orch.Run(cmd.Context())
 	},
 }
 
@@ -358,29 +670,74 @@ var resumeCmd = &cobra.Command{
 var ticketCmd = &cobra.Command{
 	Use:   "ticket <prd.json> <task-id>",
 	Short: "Run a single task",
-	Args:  cobra.ExactArgs(2),
+	Long: `Runs a single task of a PRD.
+
+With --interactive, you judge every attempt: its changes and verification
+results are shown, and you accept the work, retry with guidance for the
+worker, escalate to the next tier, or discard the attempt's changes.
+
+With --adhoc, there's no PRD to start from: a one-task PRD is written for
+the description given, with verification suggested for the project's
+stack, and run. Afterwards it's removed with its state unless --keep is
+given or you choose to keep it; a task that doesn't finish keeps its PRD
+so it can be resumed.
+
+Examples:
+  ./brigade-go ticket brigade/tasks/prd.json US-003
+  ./brigade-go ticket -i brigade/tasks/prd.json US-003
+  ./brigade-go ticket --adhoc "fix the flaky TestFoo"`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if adhoc, _ := cmd.Flags().GetString("adhoc"); adhoc != "" {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.ExactArgs(2)(cmd, args)
+	},
 	RunE: func(cmd *cobra.Command, args []string) error {
 		cfg, err := config.Load(cfgFile)
 		if err != nil {
 			return err
 		}
 
-		logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+		var review func(orchestrator.AttemptReport) orchestrator.AttemptChoice
+		if interactive, _ := cmd.Flags().GetBool("interactive"); interactive {
+			if !util.IsTerminal(os.Stdin) {
+				return fmt.Errorf("--interactive needs a terminal")
+			}
+			review = reviewAttempt
+		}
+
+		if adhoc, _ := cmd.Flags().GetString("adhoc"); adhoc != "" {
+			keep, _ := cmd.Flags().GetBool("keep")
+			return cmdTicketAdhoc(cmd.Context(), cfg, adhoc, keep, review)
+		}
 
+		logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
 		orch, err := orchestrator.New(orchestrator.Options{
-			Config:    cfg,
-			PRDPath:   args[0],
-			Logger:    logger,
-			OnlyTasks: []string{args[1]},
+			Config:        cfg,
+			PRDPath:       args[0],
+			Logger:        logger,
+			OnlyTasks:     []string{args[1]},
+			ReviewAttempt: review,
 		})
 		if err != nil {
 			return err
 		}
 
-		return orch.Run(context.Background())
+		err = orch.Run(cmd.Context())
+		if errors.Is(err, orchestrator.ErrAttemptDiscarded) {
+			fmt.Printf("%sDiscarded the attempt's changes; %s is still pending.%s\n", colorDim, args[1], colorReset)
+			return nil
+		}
+		return err
 	},
 }
 
+func init() {
+	ticketCmd.Flags().BoolP("interactive", "i", false, "review each attempt and decide what happens next")
+	ticketCmd.Flags().String("adhoc", "", "run a task described here instead of one from a PRD")
+	ticketCmd.Flags().Bool("keep", false, "with --adhoc, keep the PRD and its state afterwards")
+}
+
 // costCmd shows cost estimation.
 var costCmd = &cobra.Command{
 	Use:   "cost <prd.json>",
@@ -393,11 +750,21 @@ var costCmd = &cobra.Command{
 		}
 
 		cfg, _ := config.Load(cfgFile)
+		if write, _ := cmd.Flags().GetBool("write-pricing"); write {
+			if err := newEstimator(cfg).Pricing.Write(cfg.PricingFile); err != nil {
+				return err
+			}
+			fmt.Printf("Wrote pricing table to %s\n\n", cfg.PricingFile)
+		}
 		fmt.Println(estimateCost(p, cfg))
 		return nil
 	},
 }
 
+func init() {
+	costCmd.Flags().Bool("write-pricing", false, "write the pricing table to PRICING_FILE for editing")
+}
+
 // riskCmd performs risk assessment.
 var riskCmd = &cobra.Command{
 	Use:   "risk <prd.json>",
@@ -428,27 +795,95 @@ func previewExecution(prdPath string, cfg *config.Config) error {
 		return err
 	}
 
+	included, err := p.Select(taskSelection())
+	if err != nil {
+		return err
+	}
+	if included != nil {
+		if err := p.ValidateDependencies(included); err != nil {
+			return err
+		}
+	}
+
 	fmt.Printf("=== DRY RUN: %s ===\n\n", p.FeatureName)
 	fmt.Printf("Branch: %s\n", p.BranchName)
-	fmt.Printf("Tasks: %d\n\n", len(p.Tasks))
+	if included != nil {
+		fmt.Printf("Tasks: %d of %d selected\n\n", len(included), len(p.Tasks))
+	} else {
+		fmt.Printf("Tasks: %d\n\n", len(p.Tasks))
+	}
 
 	order, err := p.TopologicalOrder()
 	if err != nil {
 		return fmt.Errorf("dependency error: %w", err)
 	}
+	if p.HasPhases() {
+		order = phaseOrder(p, order)
+	}
 
-	for i, taskID := range order {
+	i := 0
+	for _, taskID := range order {
+		if included != nil && !included[taskID] {
+			continue
+		}
+		i++
 		task := p.TaskByID(taskID)
 		tier := "line"
 		if task.Complexity == prd.ComplexitySenior {
 			tier = "sous"
 		}
-		fmt.Printf("%d. [%s] %s: %s\n", i+1, tier, task.ID, task.Title)
+		phase := ""
+		if task.Phase > 0 {
+			phase = fmt.Sprintf(" (phase %d)", task.Phase)
+		}
+		fmt.Printf("%d. [%s] %s: %s%s\n", i, tier, task.ID, task.Title, phase)
 	}
 
 	return nil
 }
 
+// phaseOrder reorders a dependency order so earlier phases run first. A
+// task without a phase runs with the latest phase it depends on.
+func phaseOrder(p *prd.PRD, order []string) []string {
+	rank := make(map[string]int)
+	for _, id := range order {
+		task := p.TaskByID(id)
+		rank[id] = task.Phase
+		if task.Phase == 0 {
+			for _, dep := range task.DependsOn {
+				if rank[dep] > rank[id] {
+					rank[id] = rank[dep]
+				}
+			}
+		}
+	}
+	sorted := append([]string(nil), order...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return rank[sorted[i]] < rank[sorted[j]]
+	})
+	return sorted
+}
+
+// taskSelection returns the partial execution flags as a selection.
+func taskSelection() prd.Selection {
+	return prd.Selection{Only: onlyTasks, Skip: skipTasks, From: fromTask, Until: untilTask, Tags: onlyTags}
+}
+
+// applyTagFilter narrows a PRD to the tasks carrying one of --tags, for
+// reports on part of a large PRD.
+func applyTagFilter(p *prd.PRD) {
+	if len(onlyTags) == 0 {
+		return
+	}
+	var kept []prd.Task
+	for _, task := range p.Tasks {
+		if task.HasTag(onlyTags...) {
+			kept = append(kept, task)
+		}
+	}
+	p.Tasks = kept
+}
+
 func findActivePRD() string {
 	// Look for PRDs in brigade/tasks/
 	// Find one with active state
@@ -459,6 +894,7 @@ func findActivePRD() string {
 type statusInfo struct {
 	PRD          string
 	FeatureName  string
+	ParentPRD    string
 	Done         int
 	Total        int
 	Current      string
@@ -469,7 +905,25 @@ type statusInfo struct {
 	Absorptions  int
 	ReviewsPassed int
 	ReviewsFailed int
+	VerificationsPassed int
+	VerificationsFailed int
+	SpentCost     float64 // USD of worker time so far
+	ProjectedCost float64 // USD expected for the whole PRD
 	TotalTime    time.Duration
+	Tags         []string `json:",omitempty"` // Tasks shown are limited to these tags
+	Phases       []phaseStatus `json:",omitempty"`
+	PRDRevision  int           `json:",omitempty"` // PRD history version the state started from
+	PRDChanges   []string      `json:",omitempty"` // Revisions since then
+
+	brief *supervisor.Status // The --brief format
+}
+
+type phaseStatus struct {
+	Phase  int
+	Done   int
+	Total  int
+	Status string // complete, in_progress, or waiting
+	Review string `json:",omitempty"` // Latest phase review result
 }
 
 type taskStatus struct {
@@ -480,6 +934,10 @@ type taskStatus struct {
 	Worker     string
 	Iterations int
 	Escalated  bool
+	Priority   int      `json:",omitempty"`
+	Tags       []string `json:",omitempty"`
+	Phase      int      `json:",omitempty"`
+	BlockedBy  []string `json:",omitempty"` // Skipped tasks it can't run without
 }
 
 func getStatus(prdPath string) (*statusInfo, error) {
@@ -487,6 +945,9 @@ func getStatus(prdPath string) (*statusInfo, error) {
 	if err != nil {
 		return nil, err
 	}
+	revisions, _ := p.Revisions()
+	hash := p.ContentHash()
+	applyTagFilter(p)
 
 	store := state.ForPRD(prdPath)
 	st, err := store.Load()
@@ -526,6 +987,7 @@ func getStatus(prdPath string) (*statusInfo, error) {
 	info := &statusInfo{
 		PRD:           p.Prefix(),
 		FeatureName:   p.FeatureName,
+		ParentPRD:     p.ParentPRD,
 		Done:          done,
 		Total:         len(p.Tasks),
 		Current:       st.CurrentTask,
@@ -534,7 +996,21 @@ func getStatus(prdPath string) (*statusInfo, error) {
 		ReviewsPassed: reviewsPassed,
 		ReviewsFailed: reviewsFailed,
 		TotalTime:     totalTime,
+		Tags:          onlyTags,
+		PRDRevision:   st.PRDRevision,
+		PRDChanges:    prdChangesSince(revisions, hash, st.PRDRevision),
 	}
+	info.VerificationsPassed, info.VerificationsFailed = st.VerificationStats()
+
+	// Project cost from this run's durations and earlier runs
+	cfg, err := config.Load(cfgFile)
+	if err != nil {
+		cfg = config.Default()
+	}
+	estimator := newEstimator(cfg)
+	projection := cost.Project(p, st, cost.LoadHistory(filepath.Dir(prdPath), prdPath, estimator), estimator)
+	info.SpentCost, info.ProjectedCost = projection.Spent, projection.Total
+	info.brief = briefStatus(p, st, cfg, projection.Total)
 
 	// Build task history lookup - count iterations and find latest worker
 	iterationsByTask := make(map[string]int)
@@ -544,10 +1020,20 @@ func getStatus(prdPath string) (*statusInfo, error) {
 		workerByTask[h.TaskID] = h.Worker // Latest worker
 	}
 
+	// Skipped tasks and the tasks stuck behind them
+	skipped := make(map[string]bool)
+	for _, id := range st.SkippedTaskIDs() {
+		skipped[id] = true
+	}
+	blockedBy := p.BlockedBy(st.SkippedTaskIDs())
+
 	for _, task := range p.Tasks {
 		ts := taskStatus{
-			ID:    task.ID,
-			Title: task.Title,
+			ID:       task.ID,
+			Title:    task.Title,
+			Priority: task.Priority,
+			Tags:     task.Tags,
+			Phase:    task.Phase,
 		}
 
 		// Determine worker based on complexity (default)
@@ -575,26 +1061,71 @@ func getStatus(prdPath string) (*statusInfo, error) {
 		// Check if task was escalated (separate from status)
 		ts.Escalated = st.WasEscalated(task.ID)
 
-		if completed[task.ID] {
+		if q := st.QueuedReview(task.ID); completed[task.ID] && q != nil && q.Status == state.HumanReviewPending {
+			ts.Status = "awaiting_review"
+			ts.Marker = "◐"
+		} else if completed[task.ID] {
 			ts.Status = "complete"
 			ts.Marker = "✓"
 		} else if task.ID == st.CurrentTask {
 			ts.Status = "in_progress"
 			ts.Marker = "→"
 			info.Worker = ts.Worker
+		} else if skipped[task.ID] {
+			ts.Status = "skipped"
+			ts.Marker = "⊘"
 		} else {
 			ts.Status = "pending"
 			ts.Marker = "○"
+			ts.BlockedBy = blockedBy[task.ID]
 		}
 
 		info.Tasks = append(info.Tasks, ts)
 	}
 
+	// Phase progress, with the latest review of each phase
+	reviews := make(map[int]string)
+	for _, r := range st.PhaseReviews {
+		reviews[r.Phase] = r.Status
+	}
+	current := p.CurrentPhase()
+	for _, phase := range p.Phases() {
+		ps := phaseStatus{Phase: phase, Review: reviews[phase]}
+		ps.Done, ps.Total = p.PhaseProgress(phase)
+		switch {
+		case ps.Done == ps.Total:
+			ps.Status = "complete"
+		case phase == current:
+			ps.Status = "in_progress"
+		default:
+			ps.Status = "waiting"
+		}
+		info.Phases = append(info.Phases, ps)
+	}
+
 	return info, nil
 }
 
-// ANSI color codes
-const (
+// prdChangesSince describes the PRD revisions recorded after the given
+// version, and an edit made since the last one.
+func prdChangesSince(revisions []prd.Revision, hash string, since int) []string {
+	if since == 0 || len(revisions) == 0 {
+		return nil
+	}
+	var changes []string
+	for _, rev := range revisions {
+		if rev.Version > since {
+			changes = append(changes, fmt.Sprintf("v%d %s: %s", rev.Version, rev.Source, rev.Summary))
+		}
+	}
+	if latest := revisions[len(revisions)-1]; latest.Hash != hash {
+		changes = append(changes, "edited since the last recorded revision")
+	}
+	return changes
+}
+
+// ANSI color codes (cleared by disableColors)
+var (
 	colorReset  = "\033[0m"
 	colorBold   = "\033[1m"
 	colorDim    = "\033[0;90m"
@@ -604,6 +1135,38 @@ const (
 	colorRed    = "\033[0;31m"
 )
 
+// disableColors switches output to plain text, for pipes, logs, and CI.
+func disableColors() {
+	colorReset, colorBold, colorDim, colorCyan = "", "", "", ""
+	colorGreen, colorYellow, colorRed = "", "", ""
+}
+
+// applyTheme sets up the output theme from --no-color and BRIGADE_THEME:
+// plain drops colors and emoji, ascii also spells box drawing and symbols
+// in ASCII. Themed output is filtered on its way out, so no command needs
+// to know about themes; it counts as not being a terminal.
+func applyTheme() {
+	theme := os.Getenv("BRIGADE_THEME")
+	if noColor && theme != util.ThemeASCII {
+		theme = util.ThemePlain
+	}
+	switch theme {
+	case "", util.ThemeDefault:
+		return
+	case util.ThemePlain, util.ThemeASCII:
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown BRIGADE_THEME %q (default, plain or ascii), using default\n", theme)
+		return
+	}
+	disableColors()
+	flush, err := util.ThemeOutput(theme)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Can't apply BRIGADE_THEME=%s: %v\n", theme, err)
+		return
+	}
+	stdoutTerminal, flushOutput = false, flush
+}
+
 func (s *statusInfo) Format() string {
 	var sb strings.Builder
 
@@ -615,19 +1178,50 @@ func (s *statusInfo) Format() string {
 
 	// Feature name header
 	sb.WriteString(fmt.Sprintf("%sKitchen Status: %s%s\n", colorBold, s.FeatureName, colorReset))
+	if s.ParentPRD != "" {
+		sb.WriteString(fmt.Sprintf("%sIterating on: %s%s\n", colorDim, s.ParentPRD, colorReset))
+	}
+	if len(s.Tags) > 0 {
+		sb.WriteString(fmt.Sprintf("%sTasks tagged: %s%s\n", colorDim, strings.Join(s.Tags, ", "), colorReset))
+	}
+	if len(s.PRDChanges) > 0 {
+		sb.WriteString(fmt.Sprintf("%s⚠ PRD changed since this run started (v%d):%s\n", colorYellow, s.PRDRevision, colorReset))
+		for _, change := range s.PRDChanges {
+			sb.WriteString(fmt.Sprintf("  %s%s%s\n", colorDim, change, colorReset))
+		}
+	}
 	sb.WriteString(fmt.Sprintf("%s═══════════════════════════════════════════════════════════%s\n", colorCyan, colorReset))
 
 	// Progress bar
-	percent := 0
+	barWidth := 20
+	filled := 0
 	if s.Total > 0 {
-		percent = (s.Done * 100) / s.Total
+		filled = (s.Done * barWidth) / s.Total
 	}
-	barWidth := 20
-	filled := (percent * barWidth) / 100
 	filledBar := strings.Repeat("█", filled)
 	emptyBar := strings.Repeat("░", barWidth-filled)
-	sb.WriteString(fmt.Sprintf("%s📊 Progress:%s [%s%s%s%s] %d%% (%d/%d)\n\n",
-		colorBold, colorReset, colorGreen, filledBar, colorReset, emptyBar, percent, s.Done, s.Total))
+	sb.WriteString(fmt.Sprintf("%s📊 Progress:%s [%s%s%s%s] %s (%d/%d)\n\n",
+		colorBold, colorReset, colorGreen, filledBar, colorReset, emptyBar, util.FormatPercent(float64(s.Done), float64(s.Total)), s.Done, s.Total))
+
+	// Phases
+	if len(s.Phases) > 0 {
+		sb.WriteString(fmt.Sprintf("%sPhases:%s\n", colorBold, colorReset))
+		for _, ph := range s.Phases {
+			marker, markerColor := "○", colorDim
+			switch ph.Status {
+			case "complete":
+				marker, markerColor = "✓", colorGreen
+			case "in_progress":
+				marker, markerColor = "→", colorYellow
+			}
+			review := ""
+			if ph.Review != "" {
+				review = fmt.Sprintf(" %s(review: %s)%s", colorDim, strings.ReplaceAll(ph.Review, "_", " "), colorReset)
+			}
+			sb.WriteString(fmt.Sprintf("  %s%s%s Phase %d: %d/%d%s\n", markerColor, marker, colorReset, ph.Phase, ph.Done, ph.Total, review))
+		}
+		sb.WriteString("\n")
+	}
 
 	// Tasks header
 	sb.WriteString(fmt.Sprintf("%sTasks:%s\n", colorBold, colorReset))
@@ -641,6 +1235,10 @@ func (s *statusInfo) Format() string {
 			markerColor = colorYellow
 		case "escalated":
 			markerColor = colorYellow
+		case "awaiting_review":
+			markerColor = colorYellow
+		case "skipped":
+			markerColor = colorRed
 		default:
 			markerColor = colorReset
 		}
@@ -649,7 +1247,7 @@ func (s *statusInfo) Format() string {
 		workerInfo := ""
 		if t.Status == "in_progress" {
 			workerInfo = fmt.Sprintf(" %s[%s · iter %d]%s", colorYellow, t.Worker, t.Iterations, colorReset)
-		} else if t.Status == "complete" {
+		} else if t.Status == "complete" || t.Status == "awaiting_review" {
 			// Show worker for completed tasks too
 			iterInfo := ""
 			if t.Iterations > 1 {
@@ -665,71 +1263,180 @@ func (s *statusInfo) Format() string {
 		if t.Escalated {
 			escIndicator = fmt.Sprintf(" %s⬆%s", colorYellow, colorReset)
 		}
-		sb.WriteString(fmt.Sprintf("  %s%s%s %s: %s%s%s\n", markerColor, t.Marker, colorReset, t.ID, t.Title, workerInfo, escIndicator))
+		tagInfo := ""
+		if t.Phase > 0 {
+			tagInfo = fmt.Sprintf(" %s(phase %d)%s", colorDim, t.Phase, colorReset)
+		}
+		if len(t.Tags) > 0 {
+			tagInfo += fmt.Sprintf(" %s#%s%s", colorDim, strings.Join(t.Tags, " #"), colorReset)
+		}
+		if len(t.BlockedBy) > 0 {
+			tagInfo += fmt.Sprintf(" %sblocked by %s%s", colorRed, strings.Join(t.BlockedBy, ", "), colorReset)
+		}
+		sb.WriteString(fmt.Sprintf("  %s%s%s %s: %s%s%s%s\n", markerColor, t.Marker, colorReset, t.ID, t.Title, workerInfo, escIndicator, tagInfo))
 	}
 
 	// Session stats
 	sb.WriteString(fmt.Sprintf("\n%sSession Stats:%s\n", colorBold, colorReset))
-	sb.WriteString(fmt.Sprintf("  Total time:       %s\n", formatDuration(s.TotalTime)))
+	sb.WriteString(fmt.Sprintf("  Total time:       %s\n", util.FormatDuration(s.TotalTime)))
 	sb.WriteString(fmt.Sprintf("  Escalations:      %d\n", s.Escalations))
 	sb.WriteString(fmt.Sprintf("  Absorptions:      %d\n", s.Absorptions))
 	sb.WriteString(fmt.Sprintf("  Reviews:          %d (%s%d passed%s, %s%d failed%s)\n",
 		s.ReviewsPassed+s.ReviewsFailed, colorGreen, s.ReviewsPassed, colorReset, colorRed, s.ReviewsFailed, colorReset))
+	sb.WriteString(fmt.Sprintf("  Verifications:    %d (%s%d passed%s, %s%d failed%s)\n",
+		s.VerificationsPassed+s.VerificationsFailed, colorGreen, s.VerificationsPassed, colorReset, colorRed, s.VerificationsFailed, colorReset))
+	sb.WriteString(fmt.Sprintf("  Cost:             %s spent, ~%s projected\n", util.FormatCost(s.SpentCost), util.FormatCost(s.ProjectedCost)))
 
 	// Legend
-	sb.WriteString(fmt.Sprintf("\n%sLegend: ✓ complete  → in progress  ◐ awaiting review  ○ not started  ⬆ escalated%s\n\n", colorDim, colorReset))
+	sb.WriteString(fmt.Sprintf("\n%sLegend: ✓ complete  → in progress  ◐ awaiting review  ○ not started  ⊘ skipped  ⬆ escalated%s\n\n", colorDim, colorReset))
 
 	return sb.String()
 }
 
-func formatDuration(d time.Duration) string {
-	if d == 0 {
-		return "0s"
-	}
-	h := int(d.Hours())
-	m := int(d.Minutes()) % 60
-	s := int(d.Seconds()) % 60
-
-	if h > 0 {
-		return fmt.Sprintf("%dh %dm", h, m)
-	} else if m > 0 {
-		return fmt.Sprintf("%dm %ds", m, s)
-	}
-	return fmt.Sprintf("%ds", s)
-}
-
 func (s *statusInfo) JSON() string {
 	data, _ := json.MarshalIndent(s, "", "  ")
 	return string(data)
 }
 
 func (s *statusInfo) Brief() string {
-	data, _ := json.Marshal(map[string]interface{}{
-		"done":    s.Done,
-		"total":   s.Total,
-		"current": s.Current,
-		"worker":  s.Worker,
-	})
+	data, _ := json.Marshal(s.brief)
 	return string(data)
 }
 
+// briefStatus builds the --brief status, the same format as the supervisor
+// status file. Whether the run needs attention, is paused, and what's
+// running come from that file, when a service is writing one.
+func briefStatus(p *prd.PRD, st *state.State, cfg *config.Config, projectedCost float64) *supervisor.Status {
+	brief := &supervisor.Status{
+		Version:       supervisor.StatusVersion,
+		PRD:           p.Prefix(),
+		Current:       st.CurrentTask,
+		ProjectedCost: projectedCost,
+		SessionID:     st.SessionID,
+		PRDName:       p.FeatureName,
+		ConsecutiveSkips: st.ConsecutiveSkips,
+		Tasks:         supervisor.TaskStatuses(p, st),
+	}
+	for _, ts := range brief.Tasks {
+		switch ts.Status {
+		case supervisor.TaskComplete, supervisor.TaskAwaitingReview:
+			brief.Done++
+		case supervisor.TaskInProgress:
+			brief.Worker = ts.Worker
+		}
+	}
+	brief.Total = len(brief.Tasks)
+	if started, err := time.Parse(time.RFC3339, st.CurrentTaskStarted); err == nil && st.CurrentTask != "" {
+		brief.Elapsed = int(time.Since(started).Seconds())
+	}
+	if cfg.SupervisorStatusFile != "" {
+		written, _ := supervisor.NewStatusWriter(cfg.SupervisorStatusFile, p.Prefix(), cfg.SupervisorPRDScoped).Read()
+		if written != nil {
+			brief.Attention, brief.AttentionReason = written.Attention, written.AttentionReason
+			brief.StartedAt, brief.Running, brief.LastEvent, brief.Paused = written.StartedAt, written.Running, written.LastEvent, written.Paused
+		}
+	}
+	return brief
+}
+
 func generateSummary(p *prd.PRD, st *state.State) string {
 	var sb strings.Builder
 
 	sb.WriteString(fmt.Sprintf("# Summary: %s\n\n", p.FeatureName))
 
 	completed := st.CompletedTaskIDs()
-	sb.WriteString(fmt.Sprintf("**Progress:** %d/%d tasks complete\n\n", len(completed), len(p.Tasks)))
+	done := 0
+	for _, task := range p.Tasks {
+		if completed[task.ID] {
+			done++
+		}
+	}
+	sb.WriteString(fmt.Sprintf("**Progress:** %d/%d tasks complete\n\n", done, len(p.Tasks)))
 
 	// Escalations
-	if len(st.Escalations) > 0 {
+	var escalations []state.Escalation
+	for _, e := range st.Escalations {
+		if p.TaskByID(e.TaskID) != nil {
+			escalations = append(escalations, e)
+		}
+	}
+	if len(escalations) > 0 {
 		sb.WriteString("## Escalations\n\n")
-		for _, e := range st.Escalations {
+		for _, e := range escalations {
 			sb.WriteString(fmt.Sprintf("- %s: %s → %s (%s)\n", e.TaskID, e.From, e.To, e.Reason))
 		}
 		sb.WriteString("\n")
 	}
 
+	// Verification
+	if len(st.Verifications) > 0 {
+		passed, failed := st.VerificationStats()
+		sb.WriteString("## Verification\n\n")
+		sb.WriteString(fmt.Sprintf("%d runs: %d passed, %d failed\n\n", passed+failed, passed, failed))
+
+		// Count each task's failing commands
+		failures := make(map[string]map[string]int)
+		for _, v := range st.Verifications {
+			for _, cmd := range v.Failed {
+				if failures[v.TaskID] == nil {
+					failures[v.TaskID] = make(map[string]int)
+				}
+				failures[v.TaskID][cmd]++
+			}
+		}
+		for _, task := range p.Tasks {
+			cmds := failures[task.ID]
+			names := make([]string, 0, len(cmds))
+			for cmd := range cmds {
+				names = append(names, cmd)
+			}
+			sort.Strings(names)
+			for _, cmd := range names {
+				sb.WriteString(fmt.Sprintf("- %s: `%s` failed %dx\n", task.ID, cmd, cmds[cmd]))
+			}
+		}
+		if len(failures) > 0 {
+			sb.WriteString("\n")
+		}
+	}
+
+	// Flaky verification commands, for humans to fix
+	if flaky := st.FlakyCommands(); len(flaky) > 0 {
+		sb.WriteString("## Flaky Verification\n\n")
+		sb.WriteString("These commands passed and failed on the same code:\n\n")
+		for _, task := range p.Tasks {
+			cmds := flaky[task.ID]
+			names := make([]string, 0, len(cmds))
+			for cmd := range cmds {
+				names = append(names, cmd)
+			}
+			sort.Strings(names)
+			for _, cmd := range names {
+				sb.WriteString(fmt.Sprintf("- %s: `%s` (%dx)\n", task.ID, cmd, cmds[cmd]))
+			}
+		}
+		sb.WriteString("\n")
+	}
+
+	// Attempts and failures per worker credential
+	if section := credentialSummary(st); section != "" {
+		sb.WriteString(section)
+	}
+
+	// Files attempts left for people to look at
+	if section := artifactsSummary(p, st); section != "" {
+		sb.WriteString(section)
+	}
+
+	// Ways out of the last blocked run
+	if len(st.UnblockSuggestions) > 0 {
+		sb.WriteString("## Unblock Suggestions\n\n")
+		for _, u := range st.UnblockSuggestions {
+			sb.WriteString(fmt.Sprintf("- **%s**: %s\n", u.Kind, u.Suggestion))
+		}
+		sb.WriteString("\n")
+	}
+
 	// Task history
 	sb.WriteString("## Task History\n\n")
 	for _, task := range p.Tasks {
@@ -743,64 +1450,161 @@ func generateSummary(p *prd.PRD, st *state.State) string {
 	return sb.String()
 }
 
-func estimateCost(p *prd.PRD, cfg *config.Config) string {
+// artifactsSummary lists the artifacts each task's attempts left, for the
+// attempts whose directories haven't been pruned.
+func artifactsSummary(p *prd.PRD, st *state.State) string {
 	var sb strings.Builder
-	var totalCost float64
-
-	sb.WriteString(fmt.Sprintf("=== Cost Estimate: %s ===\n\n", p.FeatureName))
-
-	juniorCount := 0
-	seniorCount := 0
-
 	for _, task := range p.Tasks {
-		switch task.Complexity {
-		case prd.ComplexityJunior:
-			juniorCount++
-		case prd.ComplexitySenior:
-			seniorCount++
-		default:
-			juniorCount++ // Default to junior
+		attempt := 0
+		for _, h := range st.TaskHistory {
+			if h.TaskID != task.ID {
+				continue
+			}
+			attempt++
+			if len(h.Artifacts) == 0 {
+				continue
+			}
+			if _, err := os.Stat(h.ArtifactsDir); err != nil {
+				continue
+			}
+			sb.WriteString(fmt.Sprintf("- %s attempt %d (`%s`): %s\n", task.ID, attempt, h.ArtifactsDir, strings.Join(h.Artifacts, ", ")))
 		}
 	}
+	if sb.Len() == 0 {
+		return ""
+	}
+	return "## Artifacts\n\n" + sb.String() + "\n"
+}
 
-	// Estimate 5 min per junior, 15 min per senior
-	juniorMinutes := juniorCount * 5
-	seniorMinutes := seniorCount * 15
+// credentialSummary tallies the attempts each named worker credential ran
+// and how often it was rate limited or rejected. Empty when no tier has
+// *_CREDENTIALS.
+func credentialSummary(st *state.State) string {
+	type tally struct{ attempts, limited, rejected int }
+	tallies := make(map[string]*tally)
+	var names []string
+	get := func(tier state.WorkerTier, name string) *tally {
+		key := fmt.Sprintf("%s/%s", tier, name)
+		if tallies[key] == nil {
+			tallies[key] = &tally{}
+			names = append(names, key)
+		}
+		return tallies[key]
+	}
+	for _, h := range st.TaskHistory {
+		if h.Credential != "" {
+			get(h.Worker, h.Credential).attempts++
+		}
+	}
+	for _, f := range st.CredentialFailures {
+		t := get(f.Tier, f.Credential)
+		if f.Reason == "auth_failed" {
+			t.rejected++
+		} else {
+			t.limited++
+		}
+	}
+	if len(names) == 0 {
+		return ""
+	}
 
-	juniorCost := float64(juniorMinutes) * cfg.CostRateLine
-	seniorCost := float64(seniorMinutes) * cfg.CostRateSous
-	totalCost = juniorCost + seniorCost
+	sort.Strings(names)
+	var sb strings.Builder
+	sb.WriteString("## Credentials\n\n")
+	for _, name := range names {
+		t := tallies[name]
+		sb.WriteString(fmt.Sprintf("- %s: %d attempts, %d rate limited, %d rejected\n", name, t.attempts, t.limited, t.rejected))
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
 
-	sb.WriteString(fmt.Sprintf("Junior tasks: %d × ~5min @ $%.2f/min = $%.2f\n", juniorCount, cfg.CostRateLine, juniorCost))
-	sb.WriteString(fmt.Sprintf("Senior tasks: %d × ~15min @ $%.2f/min = $%.2f\n", seniorCount, cfg.CostRateSous, seniorCost))
-	sb.WriteString(fmt.Sprintf("\nEstimated total: $%.2f\n", totalCost))
+// costEstimate is a structured cost projection for a PRD.
+type costEstimate struct {
+	JuniorTasks   int     `json:"juniorTasks"`
+	SeniorTasks   int     `json:"seniorTasks"`
+	JuniorCost    float64 `json:"juniorCost"`
+	SeniorCost    float64 `json:"seniorCost"`
+	JuniorBasis   string  `json:"juniorBasis,omitempty"` // What the per-task estimate is based on
+	SeniorBasis   string  `json:"seniorBasis,omitempty"`
+	Total         float64 `json:"total"`
+	Threshold     float64 `json:"threshold,omitempty"`
+	OverThreshold bool    `json:"overThreshold"`
+}
 
-	if cfg.CostWarnThreshold > 0 && totalCost > cfg.CostWarnThreshold {
-		sb.WriteString(fmt.Sprintf("\n⚠️ Warning: Exceeds threshold of $%.2f\n", cfg.CostWarnThreshold))
+// newEstimator returns the cost estimator for cfg, warning if the pricing
+// file can't be used.
+func newEstimator(cfg *config.Config) *cost.Estimator {
+	est, err := cost.NewEstimator(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v (using built-in prices)\n", err)
 	}
+	return est
+}
 
-	return sb.String()
+func computeCost(p *prd.PRD, cfg *config.Config) costEstimate {
+	var est costEstimate
+	estimator := newEstimator(cfg)
+	hist := cost.LoadHistory(filepath.Dir(p.Path()), p.Path(), estimator)
+	for i := range p.Tasks {
+		usd, basis := estimator.TaskEstimate(&p.Tasks[i], hist)
+		if p.Tasks[i].Complexity == prd.ComplexitySenior {
+			est.SeniorTasks++
+			est.SeniorCost += usd
+			est.SeniorBasis = basis
+		} else {
+			est.JuniorTasks++ // Default to junior
+			est.JuniorCost += usd
+			est.JuniorBasis = basis
+		}
+	}
+	est.Total = est.JuniorCost + est.SeniorCost
+	est.Threshold = cfg.CostWarnThreshold
+	est.OverThreshold = cfg.CostWarnThreshold > 0 && est.Total > cfg.CostWarnThreshold
+	return est
 }
 
-func assessRisk(p *prd.PRD, cfg *config.Config, includeHistory bool) string {
+func estimateCost(p *prd.PRD, cfg *config.Config) string {
 	var sb strings.Builder
-	var riskScore int
+	est := computeCost(p, cfg)
 
-	sb.WriteString(fmt.Sprintf("=== Risk Assessment: %s ===\n\n", p.FeatureName))
+	sb.WriteString(fmt.Sprintf("=== Cost Estimate: %s ===\n\n", p.FeatureName))
+	if est.JuniorTasks > 0 {
+		sb.WriteString(fmt.Sprintf("Junior tasks: %d × %s = %s\n", est.JuniorTasks, est.JuniorBasis, util.FormatCost(est.JuniorCost)))
+	}
+	if est.SeniorTasks > 0 {
+		sb.WriteString(fmt.Sprintf("Senior tasks: %d × %s = %s\n", est.SeniorTasks, est.SeniorBasis, util.FormatCost(est.SeniorCost)))
+	}
+	sb.WriteString(fmt.Sprintf("\nEstimated total: %s\n", util.FormatCost(est.Total)))
+
+	if est.OverThreshold {
+		sb.WriteString(fmt.Sprintf("\n⚠️ Warning: Exceeds threshold of %s\n", util.FormatCost(cfg.CostWarnThreshold)))
+	}
+
+	return sb.String()
+}
+
+// riskReport is a structured risk assessment for a PRD.
+type riskReport struct {
+	Level  string   `json:"level"`
+	Score  int      `json:"score"`
+	Issues []string `json:"issues"`
+}
 
-	// Check for issues
-	issues := []string{}
+func computeRisk(p *prd.PRD) riskReport {
+	var r riskReport
+	r.Issues = []string{}
 
 	// Many tasks
 	if len(p.Tasks) > 15 {
-		issues = append(issues, fmt.Sprintf("Large PRD (%d tasks)", len(p.Tasks)))
-		riskScore += 3
+		r.Issues = append(r.Issues, fmt.Sprintf("Large PRD (%d tasks)", len(p.Tasks)))
+		r.Score += 3
 	}
 
 	// Check for complex dependency chains
 	if p.HasCircularDependency() {
-		issues = append(issues, "Circular dependencies detected")
-		riskScore += 10
+		r.Issues = append(r.Issues, "Circular dependencies detected")
+		r.Score += 10
 	}
 
 	// Check verification coverage
@@ -811,28 +1615,35 @@ func assessRisk(p *prd.PRD, cfg *config.Config, includeHistory bool) string {
 		}
 	}
 	if tasksMissingVerification > 0 {
-		issues = append(issues, fmt.Sprintf("%d tasks missing verification", tasksMissingVerification))
-		riskScore += tasksMissingVerification
+		r.Issues = append(r.Issues, fmt.Sprintf("%d tasks missing verification", tasksMissingVerification))
+		r.Score += tasksMissingVerification
 	}
 
 	// Risk level
-	var level string
 	switch {
-	case riskScore >= 21:
-		level = "CRITICAL"
-	case riskScore >= 13:
-		level = "HIGH"
-	case riskScore >= 6:
-		level = "MEDIUM"
+	case r.Score >= 21:
+		r.Level = "CRITICAL"
+	case r.Score >= 13:
+		r.Level = "HIGH"
+	case r.Score >= 6:
+		r.Level = "MEDIUM"
 	default:
-		level = "LOW"
+		r.Level = "LOW"
 	}
 
-	sb.WriteString(fmt.Sprintf("Risk Level: %s (score: %d)\n\n", level, riskScore))
+	return r
+}
+
+func assessRisk(p *prd.PRD, cfg *config.Config, includeHistory bool) string {
+	var sb strings.Builder
+	risk := computeRisk(p)
+
+	sb.WriteString(fmt.Sprintf("=== Risk Assessment: %s ===\n\n", p.FeatureName))
+	sb.WriteString(fmt.Sprintf("Risk Level: %s (score: %d)\n\n", risk.Level, risk.Score))
 
-	if len(issues) > 0 {
+	if len(risk.Issues) > 0 {
 		sb.WriteString("Issues:\n")
-		for _, issue := range issues {
+		for _, issue := range risk.Issues {
 			sb.WriteString(fmt.Sprintf("  - %s\n", issue))
 		}
 	} else {
@@ -841,3 +1652,61 @@ func assessRisk(p *prd.PRD, cfg *config.Config, includeHistory bool) string {
 
 	return sb.String()
 }
+
+// riskLevels orders risk levels for comparison with RISK_WARN_THRESHOLD.
+var riskLevels = map[string]int{"LOW": 1, "MEDIUM": 2, "HIGH": 3, "CRITICAL": 4}
+
+// checkRiskThreshold stops a service from starting on a PRD whose risk
+// level meets RISK_WARN_THRESHOLD until someone accepts the risk, either at
+// a prompt or with --accept-risk. Walkaway mode never prompts. Acceptances
+// are recorded in the PRD's state file.
+func checkRiskThreshold(prdPath string, cfg *config.Config, acceptRisk bool) error {
+	if cfg.RiskWarnThreshold == "" {
+		return nil
+	}
+	p, err := prd.Load(prdPath)
+	if err != nil {
+		return err
+	}
+	risk := computeRisk(p)
+	threshold := strings.ToUpper(cfg.RiskWarnThreshold)
+	if riskLevels[risk.Level] < riskLevels[threshold] {
+		return nil
+	}
+
+	acceptedBy := "--accept-risk"
+	if !acceptRisk {
+		if cfg.WalkawayMode || p.Walkaway || !util.IsTerminal(os.Stdin) {
+			return fmt.Errorf("risk level %s (score %d) meets RISK_WARN_THRESHOLD=%s; rerun with --accept-risk to start anyway",
+				risk.Level, risk.Score, cfg.RiskWarnThreshold)
+		}
+		fmt.Print(assessRisk(p, cfg, false))
+		fmt.Println()
+		if !confirmPrompt(fmt.Sprintf("Risk level meets RISK_WARN_THRESHOLD=%s. Start anyway? (y/N) ", cfg.RiskWarnThreshold), false) {
+			return fmt.Errorf("risk not accepted")
+		}
+		acceptedBy = "prompt"
+	}
+
+	op, err := policy.FromConfig(cfg).Approve(policy.AcceptRisk, prdPath,
+		fmt.Sprintf("risk %s (score %d) at RISK_WARN_THRESHOLD=%s, by %s", risk.Level, risk.Score, cfg.RiskWarnThreshold, acceptedBy))
+	if err != nil {
+		return err
+	}
+	user := op.Name
+	if user == "" {
+		user = os.Getenv("USER")
+	}
+
+	return state.ForPRD(prdPath).Update(func(s *state.State) error {
+		s.AddRiskAcceptance(state.RiskAcceptance{
+			Level:      risk.Level,
+			Score:      risk.Score,
+			Threshold:  cfg.RiskWarnThreshold,
+			Issues:     risk.Issues,
+			AcceptedBy: acceptedBy,
+			User:       user,
+		})
+		return nil
+	})
+}
@@ -12,9 +12,11 @@ import (
 	"github.com/spf13/cobra"
 
 	"brigade/internal/config"
+	"brigade/internal/orchestrator"
 	"brigade/internal/state"
 	"brigade/internal/util"
 	"brigade/internal/worker"
+	"brigade/internal/workspace"
 )
 
 var mapCmd = &cobra.Command{
@@ -23,23 +25,35 @@ var mapCmd = &cobra.Command{
 	Long: `Analyzes the codebase and generates a markdown map.
 
 The map is auto-included in future planning sessions.
-Default output: brigade/codebase-map.md`,
+Default output: brigade/codebase-map.md
+
+With --workspace, only that monorepo package is analyzed. Its map is
+included in the prompts of tasks scoped to the workspace.
+Default output: brigade/codebase-map-<workspace>.md`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		cfg, err := config.Load(cfgFile)
 		if err != nil {
 			return fmt.Errorf("loading config: %w", err)
 		}
+		ws, _ := cmd.Flags().GetString("workspace")
 
 		outputPath := "brigade/codebase-map.md"
+		if ws != "" {
+			outputPath = workspace.MapPath(ws)
+		}
 		if len(args) > 0 {
 			outputPath = args[0]
 		}
 
-		return cmdMap(outputPath, cfg)
+		return cmdMap(cmd.Context(), outputPath, ws, cfg)
 	},
 }
 
-func cmdMap(outputPath string, cfg *config.Config) error {
+func init() {
+	mapCmd.Flags().String("workspace", "", "analyze one monorepo package (e.g. services/api)")
+}
+
+func cmdMap(ctx context.Context, outputPath, ws string, cfg *config.Config) error {
 	fmt.Printf("%sGenerating codebase map...%s\n\n", colorBold, colorReset)
 
 	// Ensure output directory exists
@@ -47,7 +61,15 @@ func cmdMap(outputPath string, cfg *config.Config) error {
 		return err
 	}
 
-	prompt := `Analyze this codebase and generate a comprehensive codebase map in markdown format.
+	prompt := ""
+	if ws != "" {
+		if info, err := os.Stat(ws); err != nil || !info.IsDir() {
+			return fmt.Errorf("workspace %s is not a directory", ws)
+		}
+		prompt = fmt.Sprintf("This is the %s package of a monorepo. Map only this package; mention other packages only where it depends on them.\n\n", ws)
+	}
+
+	prompt += `Analyze this codebase and generate a comprehensive codebase map in markdown format.
 
 Include the following sections:
 
@@ -89,20 +111,31 @@ Output the result as markdown that can be saved to a file.`
 	start := time.Now()
 
 	// Create worker for Executive Chef
+	env, err := orchestrator.WorkerEnv(cfg, state.TierExecutive)
+	if err != nil {
+		return err
+	}
 	workerCfg := &worker.Config{
 		Command:    cfg.ExecutiveCmd,
 		Tier:       state.TierExecutive,
 		Timeout:    cfg.TaskTimeoutExecutive,
-		WorkingDir: "",
+		WorkingDir: ws,
 		Quiet:      false,
+		Env:        env,
+	}
+	var exec worker.Worker = worker.NewCLIWorker(workerCfg)
+	if cache := orchestrator.ExecCache(cfg); cache != nil {
+		exec = cache.Wrap(exec, cfg.ExecutiveCmd, orchestrator.CodeState())
 	}
-	exec := worker.NewCLIWorker(workerCfg)
 
 	// Execute
-	result, err := exec.Execute(context.Background(), prompt)
+	result, err := exec.Execute(ctx, prompt)
 	if err != nil {
 		return fmt.Errorf("executing map: %w", err)
 	}
+	if result.Cached {
+		fmt.Printf("%sCode unchanged since the last map; reusing it (EXEC_CACHE_TTL)%s\n", colorDim, colorReset)
+	}
 
 	duration := time.Since(start)
 
@@ -0,0 +1,326 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"brigade/internal/config"
+	"brigade/internal/mcp"
+	"brigade/internal/prd"
+	"brigade/internal/supervisor"
+)
+
+var mcpCmd = &cobra.Command{
+	Use:   "mcp",
+	Short: "Serve Brigade over the Model Context Protocol (stdio)",
+	Long: `Runs an MCP server on stdin/stdout so IDE assistants and Claude Desktop
+can drive Brigade.
+
+Tools:
+  list_prds          PRDs in brigade/tasks with their progress
+  prd_status         Task-by-task status of a PRD
+  run_ticket         Start a single task in the background
+  pending_decisions  Decisions a running service is waiting on
+  answer_decision    Retry, skip or abort in answer to a decision
+  nudge_task         Send guidance to a task's worker
+
+Decisions need SUPERVISOR_EVENTS_FILE and SUPERVISOR_CMD_FILE set.
+
+Claude Desktop (claude_desktop_config.json):
+  {"mcpServers": {"brigade": {
+    "command": "/path/to/brigade-go", "args": ["mcp"], "cwd": "/path/to/project"}}}
+
+Claude CLI:
+  claude mcp add brigade -- /path/to/brigade-go mcp`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load(cfgFile)
+		if err != nil {
+			return fmt.Errorf("loading config: %w", err)
+		}
+		return newMCPServer(cfg).Serve(cmd.Context(), os.Stdin, os.Stdout)
+	},
+}
+
+// newMCPServer builds the MCP server and its tools.
+func newMCPServer(cfg *config.Config) *mcp.Server {
+	s := mcp.NewServer("brigade", Version)
+	prdArg := mcp.String("Path to the PRD JSON file, e.g. brigade/tasks/prd-auth.json")
+
+	s.AddTool(mcp.Tool{
+		Name:        "list_prds",
+		Description: "List the PRDs in brigade/tasks with their progress.",
+		Handler: func(ctx context.Context, args json.RawMessage) (string, error) {
+			return mcpListPRDs()
+		},
+	})
+	s.AddTool(mcp.Tool{
+		Name:        "prd_status",
+		Description: "Show the status of each task in a PRD, plus escalations and reviews.",
+		InputSchema: mcp.Object(map[string]interface{}{"prd": prdArg}, "prd"),
+		Handler: func(ctx context.Context, args json.RawMessage) (string, error) {
+			var a struct{ PRD string }
+			if err := json.Unmarshal(args, &a); err != nil {
+				return "", err
+			}
+			status, err := getStatus(a.PRD)
+			if err != nil {
+				return "", err
+			}
+			return status.JSON(), nil
+		},
+	})
+	s.AddTool(mcp.Tool{
+		Name:        "run_ticket",
+		Description: "Start a single task from a PRD in the background. Returns immediately; follow progress with prd_status.",
+		InputSchema: mcp.Object(map[string]interface{}{
+			"prd":  prdArg,
+			"task": mcp.String("Task ID, e.g. US-003"),
+		}, "prd", "task"),
+		Handler: func(ctx context.Context, args json.RawMessage) (string, error) {
+			var a struct{ PRD, Task string }
+			if err := json.Unmarshal(args, &a); err != nil {
+				return "", err
+			}
+			return mcpRunTicket(a.PRD, a.Task)
+		},
+	})
+	s.AddTool(mcp.Tool{
+		Name:        "pending_decisions",
+		Description: "List decisions running services are waiting on (walkaway off, supervisor configured).",
+		InputSchema: mcp.Object(map[string]interface{}{"prd": prdArg}),
+		Handler: func(ctx context.Context, args json.RawMessage) (string, error) {
+			var a struct{ PRD string }
+			if err := json.Unmarshal(args, &a); err != nil {
+				return "", err
+			}
+			pending, err := mcpPendingDecisions(cfg, a.PRD)
+			if err != nil {
+				return "", err
+			}
+			if len(pending) == 0 {
+				return "No decisions pending.", nil
+			}
+			data, _ := json.MarshalIndent(pending, "", "  ")
+			return string(data), nil
+		},
+	})
+	s.AddTool(mcp.Tool{
+		Name:        "answer_decision",
+		Description: "Answer a pending decision: retry the task (optionally with guidance), skip it, or abort the service.",
+		InputSchema: mcp.Object(map[string]interface{}{
+			"decision_id": mcp.String("ID from pending_decisions"),
+			"action":      mcp.Enum("What the service should do", "retry", "skip", "abort"),
+			"guidance":    mcp.String("Hints for the retry"),
+			"reason":      mcp.String("Why, for skip or abort"),
+		}, "decision_id", "action"),
+		Handler: func(ctx context.Context, args json.RawMessage) (string, error) {
+			var a struct {
+				DecisionID string `json:"decision_id"`
+				Action     string `json:"action"`
+				Guidance   string `json:"guidance"`
+				Reason     string `json:"reason"`
+			}
+			if err := json.Unmarshal(args, &a); err != nil {
+				return "", err
+			}
+			return mcpAnswerDecision(cfg, a.DecisionID, supervisor.Action(a.Action), a.Guidance, a.Reason)
+		},
+	})
+	s.AddTool(mcp.Tool{
+		Name:        "nudge_task",
+		Description: "Send guidance to a task's worker. It sees the message on its next attempt, or right away with now=true (the running attempt restarts).",
+		InputSchema: mcp.Object(map[string]interface{}{
+			"prd":     prdArg,
+			"task":    mcp.String("Task ID, e.g. US-003"),
+			"message": mcp.String("Guidance for the worker"),
+			"now":     map[string]interface{}{"type": "boolean", "description": "Restart the running attempt to deliver it now"},
+		}, "task", "message"),
+		Handler: func(ctx context.Context, args json.RawMessage) (string, error) {
+			var a struct {
+				PRD, Task, Message string
+				Now                bool
+			}
+			if err := json.Unmarshal(args, &a); err != nil {
+				return "", err
+			}
+			if strings.TrimSpace(a.Message) == "" {
+				return "", fmt.Errorf("message is empty")
+			}
+			p, taskID, err := resolveTaskPRD(a.PRD, a.Task)
+			if err != nil {
+				return "", err
+			}
+			queue := supervisor.NewNudgeQueue(p.NudgesPath())
+			if err := queue.Add(supervisor.Nudge{TaskID: taskID, Message: a.Message, Now: a.Now}); err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("Nudge queued for %s.", p.FormatTaskID(taskID)), nil
+		},
+	})
+	return s
+}
+
+// listPRDPaths lists the PRD files in brigade/tasks.
+func listPRDPaths() []string {
+	matches, _ := filepath.Glob("brigade/tasks/*.json")
+	var paths []string
+	for _, m := range matches {
+		if !strings.HasSuffix(m, ".state.json") && !strings.HasSuffix(m, ".result.json") {
+			paths = append(paths, m)
+		}
+	}
+	return paths
+}
+
+// mcpListPRDs summarizes each PRD in brigade/tasks.
+func mcpListPRDs() (string, error) {
+	type prdSummary struct {
+		Path    string `json:"path"`
+		Feature string `json:"feature"`
+		Done    int    `json:"done"`
+		Total   int    `json:"total"`
+		Current string `json:"current,omitempty"`
+	}
+	var summaries []prdSummary
+	for _, path := range listPRDPaths() {
+		status, err := getStatus(path)
+		if err != nil {
+			continue // Not a PRD
+		}
+		summaries = append(summaries, prdSummary{path, status.FeatureName, status.Done, status.Total, status.Current})
+	}
+	if len(summaries) == 0 {
+		return "No PRDs found in brigade/tasks.", nil
+	}
+	data, _ := json.MarshalIndent(summaries, "", "  ")
+	return string(data), nil
+}
+
+// mcpRunTicket starts `ticket` as a detached process logging to
+// brigade/logs, so the tool call returns while the task runs.
+func mcpRunTicket(prdPath, taskID string) (string, error) {
+	p, err := prd.Load(prdPath)
+	if err != nil {
+		return "", err
+	}
+	if p.TaskByID(taskID) == nil {
+		return "", fmt.Errorf("task %s not found in %s", taskID, prdPath)
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll("brigade/logs", 0755); err != nil {
+		return "", err
+	}
+	logPath := filepath.Join("brigade/logs", fmt.Sprintf("ticket-%s-%s.log", p.Prefix(), taskID))
+	logFile, err := os.Create(logPath)
+	if err != nil {
+		return "", err
+	}
+	defer logFile.Close()
+
+	args := []string{"ticket", prdPath, taskID}
+	if cfgFile != "" {
+		args = append([]string{"--config", cfgFile}, args...)
+	}
+	cmd := exec.Command(exe, args...)
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	if err := cmd.Start(); err != nil {
+		return "", err
+	}
+	go cmd.Wait()
+
+	return fmt.Sprintf("Started %s (pid %d). Log: %s", p.FormatTaskID(taskID), cmd.Process.Pid, logPath), nil
+}
+
+// mcpSupervisor returns the supervisor files for a PRD prefix.
+func mcpSupervisor(cfg *config.Config, prefix string) *supervisor.Supervisor {
+	return supervisor.NewSupervisor(
+		cfg.SupervisorStatusFile,
+		cfg.SupervisorEventsFile,
+		cfg.SupervisorCmdFile,
+		prefix,
+		cfg.SupervisorPRDScoped,
+		cfg.SupervisorCmdPollInterval,
+		cfg.SupervisorCmdTimeout,
+	)
+}
+
+// mcpPendingDecisions collects unanswered decisions, for one PRD or, with
+// per-PRD supervisor files, every PRD in brigade/tasks.
+func mcpPendingDecisions(cfg *config.Config, prdPath string) ([]supervisor.PendingDecision, error) {
+	if cfg.SupervisorEventsFile == "" || cfg.SupervisorCmdFile == "" {
+		return nil, fmt.Errorf("set SUPERVISOR_EVENTS_FILE and SUPERVISOR_CMD_FILE to answer decisions")
+	}
+
+	prefixes := []string{""}
+	if prdPath != "" {
+		p, err := prd.Load(prdPath)
+		if err != nil {
+			return nil, err
+		}
+		prefixes = []string{p.Prefix()}
+	} else if cfg.SupervisorPRDScoped {
+		prefixes = nil
+		for _, path := range listPRDPaths() {
+			if p, err := prd.Load(path); err == nil {
+				prefixes = append(prefixes, p.Prefix())
+			}
+		}
+	}
+
+	var pending []supervisor.PendingDecision
+	for _, prefix := range prefixes {
+		found, err := mcpSupervisor(cfg, prefix).Events().PendingDecisions()
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range found {
+			if prdPath == "" || d.PRD == prefixes[0] {
+				pending = append(pending, d)
+			}
+		}
+	}
+	return pending, nil
+}
+
+// mcpAnswerDecision sends the answer to a pending decision.
+func mcpAnswerDecision(cfg *config.Config, decisionID string, action supervisor.Action, guidance, reason string) (string, error) {
+	switch action {
+	case supervisor.ActionRetry, supervisor.ActionSkip, supervisor.ActionAbort:
+	default:
+		return "", fmt.Errorf("action must be retry, skip or abort, not %q", action)
+	}
+
+	pending, err := mcpPendingDecisions(cfg, "")
+	if err != nil {
+		return "", err
+	}
+	for _, d := range pending {
+		if d.ID != decisionID {
+			continue
+		}
+		sup := mcpSupervisor(cfg, d.PRD)
+		if err := sup.Commands().Send(&supervisor.Command{
+			Decision: decisionID,
+			Action:   action,
+			Guidance: guidance,
+			Reason:   reason,
+		}); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("Sent %s for %s. The service picks it up on its next poll.", action, d.TaskID), nil
+	}
+	return "", fmt.Errorf("no pending decision %s (see pending_decisions)", decisionID)
+}
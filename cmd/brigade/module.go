@@ -0,0 +1,199 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"brigade/internal/config"
+	"brigade/internal/module"
+)
+
+var moduleCmd = &cobra.Command{
+	Use:   "module",
+	Short: "Find, enable and disable modules",
+	Long: `Modules are executables in modules/ that Brigade runs on service events.
+MODULES in the config names the ones a service loads. These commands list
+what's there and edit MODULES for you.`,
+}
+
+var moduleDiscoverCmd = &cobra.Command{
+	Use:   "discover",
+	Short: "List the modules in modules/ and whether they load",
+	Long: `Lists every module in modules/, marking the ones that ship with Brigade,
+the ones MODULES enables, and the events each handles. Each module's init
+is run, so a module that would be disabled at load says why. Modules named
+in MODULES that aren't in modules/ are listed too.
+
+Examples:
+  ./brigade-go module discover`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load(cfgFile)
+		if err != nil {
+			return fmt.Errorf("loading config: %w", err)
+		}
+		return cmdModuleDiscover(cfg)
+	},
+}
+
+var moduleEnableCmd = &cobra.Command{
+	Use:   "enable <name>",
+	Short: "Add a module to MODULES",
+	Long: `Adds a module to MODULES in the config file. The module must be in
+modules/; if its init fails now it's still enabled, with a warning, and is
+disabled at load until that's fixed.
+
+Examples:
+  ./brigade-go module enable telegram
+  ./brigade-go --dry-run module enable webhook`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load(cfgFile)
+		if err != nil {
+			return fmt.Errorf("loading config: %w", err)
+		}
+		return cmdModuleSet(cfg, args[0], true, dryRun)
+	},
+}
+
+var moduleDisableCmd = &cobra.Command{
+	Use:   "disable <name>",
+	Short: "Remove a module from MODULES",
+	Long: `Removes a module from MODULES in the config file. Works for a module that
+is no longer in modules/.
+
+Examples:
+  ./brigade-go module disable telegram`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load(cfgFile)
+		if err != nil {
+			return fmt.Errorf("loading config: %w", err)
+		}
+		return cmdModuleSet(cfg, args[0], false, dryRun)
+	},
+}
+
+func init() {
+	moduleCmd.AddCommand(moduleDiscoverCmd, moduleEnableCmd, moduleDisableCmd)
+}
+
+// moduleLoader returns a loader that runs modules the way a service does.
+func moduleLoader(cfg *config.Config) *module.Loader {
+	loader := module.NewLoader(module.Dir, cfg.ModuleConfig)
+	loader.Sandbox = module.SandboxFromConfig(cfg)
+	return loader
+}
+
+func cmdModuleDiscover(cfg *config.Config) error {
+	loader := moduleLoader(cfg)
+	names, err := loader.DiscoverModules()
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", module.Dir, err)
+	}
+	sort.Strings(names)
+
+	if len(names) == 0 {
+		fmt.Printf("No modules in %s/\n", module.Dir)
+	} else {
+		fmt.Printf("%sModules in %s/%s\n\n", colorBold, module.Dir, colorReset)
+	}
+	for _, name := range names {
+		enabled := containsString(cfg.Modules, name)
+		symbol, state := colorDim+"○"+colorReset, "disabled"
+		if enabled {
+			symbol, state = colorGreen+"●"+colorReset, "enabled"
+		}
+		kind, summary := "custom", ""
+		if description, ok := module.Builtins[name]; ok {
+			kind, summary = "built-in", description
+		}
+		fmt.Printf("  %s %-15s %-9s %-8s %s\n", symbol, name, kind, state, summary)
+
+		mods, err := loader.LoadModules([]string{name})
+		if err != nil {
+			fmt.Printf("      %s✗ won't load: %v%s\n", colorRed, err, colorReset)
+			continue
+		}
+		m := mods[0]
+		if len(m.Events) > 0 {
+			events := make([]string, len(m.Events))
+			for i, e := range m.Events {
+				events[i] = string(e)
+			}
+			fmt.Printf("      %sevents: %s%s\n", colorDim, strings.Join(events, " "), colorReset)
+		} else {
+			fmt.Printf("      %s⚠ handles no events%s\n", colorYellow, colorReset)
+		}
+		if loader.InitModule(m) != nil {
+			fmt.Printf("      %s⚠ init fails, so it's disabled at load: %s%s\n", colorYellow, m.DisabledReason, colorReset)
+		}
+	}
+
+	for _, name := range cfg.Modules {
+		if name != "" && !containsString(names, name) {
+			fmt.Printf("  %s✗%s %-15s enabled in MODULES but not in %s/\n", colorRed, colorReset, name, module.Dir)
+		}
+	}
+
+	fmt.Printf("\n%sEnable one with: ./brigade-go module enable <name>%s\n", colorDim, colorReset)
+	return nil
+}
+
+// cmdModuleSet adds a module to MODULES, or removes it, in the config file.
+func cmdModuleSet(cfg *config.Config, name string, enable, dryRun bool) error {
+	var modules []string
+	for _, m := range cfg.Modules {
+		if m != "" {
+			modules = append(modules, m)
+		}
+	}
+
+	if enable {
+		if containsString(modules, name) {
+			fmt.Printf("%s is already enabled\n", name)
+			return nil
+		}
+		loader := moduleLoader(cfg)
+		mods, err := loader.LoadModules([]string{name})
+		if err != nil {
+			return fmt.Errorf("%w (see ./brigade-go module discover)", err)
+		}
+		if loader.InitModule(mods[0]) != nil {
+			fmt.Printf("%s⚠%s %s's init fails, so it's disabled at load until that's fixed: %s\n",
+				colorYellow, colorReset, name, mods[0].DisabledReason)
+		}
+		modules = append(modules, name)
+	} else {
+		if !containsString(modules, name) {
+			fmt.Printf("%s isn't enabled\n", name)
+			return nil
+		}
+		kept := modules[:0]
+		for _, m := range modules {
+			if m != name {
+				kept = append(kept, m)
+			}
+		}
+		modules = kept
+	}
+
+	path := activeConfigPath(cfg)
+	value := strings.Join(modules, ",")
+	if dryRun {
+		fmt.Printf("Dry run: would set MODULES=%q in %s\n", value, path)
+		return nil
+	}
+	if err := config.SetFileValue(path, "MODULES", value); err != nil {
+		return err
+	}
+	fmt.Printf("%s✓%s Set MODULES=%q in %s\n", colorGreen, colorReset, value, path)
+	if os.Getenv("MODULES") != "" {
+		fmt.Printf("%s⚠%s MODULES is set in the environment, which overrides the config file\n", colorYellow, colorReset)
+	}
+	return nil
+}
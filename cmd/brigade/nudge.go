@@ -0,0 +1,116 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"brigade/internal/prd"
+	"brigade/internal/state"
+	"brigade/internal/supervisor"
+)
+
+var nudgeCmd = &cobra.Command{
+	Use:   "nudge [prd.json] <task-id> <message>",
+	Short: "Send guidance to a task's worker",
+	Long: `Queues guidance for a task. The worker sees it at the top of its next
+attempt's prompt.
+
+Worker CLIs can't take input mid-run, so by default the running attempt
+finishes first. With --now, the running attempt is stopped within a few
+seconds and restarted with the guidance; the stopped attempt doesn't count
+as an iteration.
+
+Without a PRD, the task is looked up in brigade/tasks, preferring the PRD
+where it is running. Use prefix/task-id (e.g. add-auth/US-003) to pick one.
+
+Examples:
+  ./brigade-go nudge US-003 "Stop editing the generated client; change the OpenAPI spec instead"
+  ./brigade-go nudge --now brigade/tasks/prd-auth.json US-003 "Use the existing session store"`,
+	Args: cobra.RangeArgs(2, 3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		now, _ := cmd.Flags().GetBool("now")
+
+		var prdPath string
+		if len(args) == 3 {
+			prdPath, args = args[0], args[1:]
+		}
+		taskID, message := args[0], strings.TrimSpace(args[1])
+		if message == "" {
+			return fmt.Errorf("message is empty")
+		}
+
+		p, taskID, err := resolveTaskPRD(prdPath, taskID)
+		if err != nil {
+			return err
+		}
+		return sendNudge(p, taskID, message, now)
+	},
+}
+
+func init() {
+	nudgeCmd.Flags().Bool("now", false, "restart the running attempt to deliver the guidance immediately")
+}
+
+// sendNudge queues guidance for a task.
+func sendNudge(p *prd.PRD, taskID, message string, now bool) error {
+	queue := supervisor.NewNudgeQueue(p.NudgesPath())
+	if err := queue.Add(supervisor.Nudge{TaskID: taskID, Message: message, Now: now}); err != nil {
+		return fmt.Errorf("queueing nudge: %w", err)
+	}
+
+	when := "on its next attempt"
+	if now {
+		when = "after restarting the running attempt"
+	}
+	fmt.Printf("%s✓%s Nudge queued for %s; the worker sees it %s\n", colorGreen, colorReset, p.FormatTaskID(taskID), when)
+	return nil
+}
+
+// resolveTaskPRD finds the PRD a task belongs to. taskID may carry a PRD
+// prefix ("add-auth/US-003"); without a PRD path, the PRD running the task
+// wins over others that merely contain it. Returns the bare task ID.
+func resolveTaskPRD(prdPath, taskID string) (*prd.PRD, string, error) {
+	prefix := ""
+	if i := strings.LastIndex(taskID, "/"); i >= 0 {
+		prefix, taskID = taskID[:i], taskID[i+1:]
+	}
+
+	if prdPath != "" {
+		p, err := prd.Load(prdPath)
+		if err != nil {
+			return nil, "", err
+		}
+		if p.TaskByID(taskID) == nil {
+			return nil, "", fmt.Errorf("task %s not found in %s", taskID, prdPath)
+		}
+		return p, taskID, nil
+	}
+
+	var matches, running []*prd.PRD
+	for _, path := range listPRDPaths() {
+		p, err := prd.Load(path)
+		if err != nil || p.TaskByID(taskID) == nil || (prefix != "" && p.Prefix() != prefix) {
+			continue
+		}
+		matches = append(matches, p)
+		if st, err := state.ForPRD(path).Load(); err == nil && st.CurrentTask == taskID {
+			running = append(running, p)
+		}
+	}
+
+	switch {
+	case len(running) == 1:
+		return running[0], taskID, nil
+	case len(matches) == 1:
+		return matches[0], taskID, nil
+	case len(matches) == 0:
+		return nil, "", fmt.Errorf("task %s not found in brigade/tasks", taskID)
+	}
+	var names []string
+	for _, p := range matches {
+		names = append(names, p.FormatTaskID(taskID))
+	}
+	return nil, "", fmt.Errorf("task %s is in several PRDs; use one of: %s", taskID, strings.Join(names, ", "))
+}
@@ -2,9 +2,14 @@ package main
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
 	"os/exec"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -13,59 +18,278 @@ import (
 )
 
 var opencodeModelsCmd = &cobra.Command{
-	Use:   "opencode-models",
-	Short: "List available OpenCode models",
+	Use:   "opencode-models [filter]",
+	Short: "List, test, and select OpenCode models",
+	Long: `Lists models available to OpenCode with context size and pricing where known.
+
+Models come from the OpenCode server (OPENCODE_SERVER) when configured,
+otherwise from the opencode CLI. An optional filter matches model IDs.
+
+  --test <model>   Send a tiny prompt to the model and report latency
+  --set <model>    Write OPENCODE_MODEL to brigade.config`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return cmdOpencodeModels()
+		testModel, _ := cmd.Flags().GetString("test")
+		setModel, _ := cmd.Flags().GetString("set")
+		filter := ""
+		if len(args) > 0 {
+			filter = args[0]
+		}
+		return cmdOpencodeModels(filter, testModel, setModel)
 	},
 }
 
-func cmdOpencodeModels() error {
-	fmt.Printf("%sAvailable OpenCode Models%s\n", colorBold, colorReset)
-	fmt.Printf("%sUse these values for OPENCODE_MODEL in brigade.config%s\n\n", colorDim, colorReset)
+func init() {
+	opencodeModelsCmd.Flags().String("test", "", "send a test prompt to this model")
+	opencodeModelsCmd.Flags().String("set", "", "write this model to OPENCODE_MODEL in brigade.config")
+}
+
+// opencodeModel describes a model and whatever metadata OpenCode reports.
+type opencodeModel struct {
+	ID       string  // provider/model
+	Name     string  // display name
+	Context  int     // context window in tokens (0 if unknown)
+	CostIn   float64 // USD per 1M input tokens
+	CostOut  float64 // USD per 1M output tokens
+	HasPrice bool
+}
+
+// opencodeModelInfo is the metadata shape shared by `opencode models --verbose`
+// and the server's /config/providers endpoint.
+type opencodeModelInfo struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Cost *struct {
+		Input  float64 `json:"input"`
+		Output float64 `json:"output"`
+	} `json:"cost"`
+	Limit struct {
+		Context int `json:"context"`
+	} `json:"limit"`
+}
 
-	if !util.CommandExists("opencode") {
-		fmt.Printf("%sError: opencode CLI not found%s\n\n", colorRed, colorReset)
-		fmt.Println("Install OpenCode: https://opencode.ai")
-		return fmt.Errorf("opencode CLI not found")
+func (info opencodeModelInfo) toModel(id string) opencodeModel {
+	m := opencodeModel{ID: id, Name: info.Name, Context: info.Limit.Context}
+	if info.Cost != nil {
+		m.CostIn, m.CostOut, m.HasPrice = info.Cost.Input, info.Cost.Output, true
 	}
+	return m
+}
+
+func cmdOpencodeModels(filter, testModel, setModel string) error {
+	fmt.Printf("%sAvailable OpenCode Models%s\n", colorBold, colorReset)
+	fmt.Printf("%sUse these values for OPENCODE_MODEL in brigade.config%s\n\n", colorDim, colorReset)
 
-	// Show current config if available
-	cfg, _ := config.Load("")
+	cfg, _ := config.Load(cfgFile)
 	if cfg != nil && cfg.OpenCodeModel != "" {
 		fmt.Printf("%sCurrent config: OPENCODE_MODEL=\"%s\"%s\n\n", colorCyan, cfg.OpenCodeModel, colorReset)
 	}
 
-	// Run opencode models command
-	cmd := exec.Command("opencode", "models")
-	var out bytes.Buffer
-	cmd.Stdout = &out
-	cmd.Stderr = &out
-	_ = cmd.Run()
+	if testModel != "" || setModel != "" {
+		if !util.CommandExists("opencode") && testModel != "" {
+			return fmt.Errorf("opencode CLI not found")
+		}
+		if testModel != "" {
+			if !testOpencodeModel(testModel) {
+				return fmt.Errorf("model %s did not respond correctly", testModel)
+			}
+			if setModel == "" && confirmPrompt(fmt.Sprintf("Write OPENCODE_MODEL=%q to config? (y/N) ", testModel), false) {
+				setModel = testModel
+			}
+		}
+		if setModel != "" {
+			return writeOpencodeModel(cfg, setModel)
+		}
+		return nil
+	}
 
-	output := out.String()
-	lines := strings.Split(output, "\n")
+	var models []opencodeModel
+	var err error
+	if cfg != nil && cfg.OpenCodeServer != "" {
+		models, err = opencodeModelsFromServer(cfg.OpenCodeServer)
+		if err != nil {
+			fmt.Printf("%s⚠%s Server query failed (%v), falling back to CLI\n\n", colorYellow, colorReset, err)
+		}
+	}
+	if len(models) == 0 {
+		if !util.CommandExists("opencode") {
+			fmt.Printf("%sError: opencode CLI not found%s\n\n", colorRed, colorReset)
+			fmt.Println("Install OpenCode: https://opencode.ai")
+			return fmt.Errorf("opencode CLI not found")
+		}
+		models = opencodeModelsFromCLI()
+	}
 
-	// Filter and display GLM models
-	fmt.Printf("%sGLM models (cost-effective):%s\n", colorBold, colorReset)
-	for _, line := range lines {
-		if strings.Contains(line, "zai-coding-plan") || strings.Contains(line, "opencode/glm") {
-			fmt.Println(line)
+	filter = strings.ToLower(filter)
+	shown := 0
+	provider := ""
+	for _, m := range models {
+		if filter != "" && !strings.Contains(strings.ToLower(m.ID), filter) {
+			continue
+		}
+		p, _, _ := strings.Cut(m.ID, "/")
+		if p != provider {
+			provider = p
+			fmt.Printf("\n%s%s%s\n", colorBold, provider, colorReset)
+		}
+		fmt.Printf("  %-45s", m.ID)
+		if m.Context > 0 {
+			fmt.Printf(" %6dk ctx", m.Context/1000)
+		} else {
+			fmt.Printf(" %10s", "")
 		}
+		if m.HasPrice {
+			if m.CostIn == 0 && m.CostOut == 0 {
+				fmt.Printf("  %sfree%s", colorGreen, colorReset)
+			} else {
+				fmt.Printf("  $%.2f / $%.2f per 1M in/out", m.CostIn, m.CostOut)
+			}
+		}
+		fmt.Println()
+		shown++
+	}
+
+	if shown == 0 {
+		fmt.Println("No models found.")
 	}
 	fmt.Println()
+	fmt.Printf("%sTest a model:   ./brigade-go opencode-models --test <model>%s\n", colorDim, colorReset)
+	fmt.Printf("%sSelect a model: ./brigade-go opencode-models --set <model>%s\n", colorDim, colorReset)
+	return nil
+}
+
+// opencodeModelsFromServer queries a running OpenCode server for providers and models.
+func opencodeModelsFromServer(server string) ([]opencodeModel, error) {
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Get(strings.TrimRight(server, "/") + "/config/providers")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("server returned %s", resp.Status)
+	}
 
-	// Filter and display Claude models (limit to 10)
-	fmt.Printf("%sClaude models (via OpenCode):%s\n", colorBold, colorReset)
-	count := 0
-	for _, line := range lines {
-		if strings.Contains(line, "anthropic/claude") && count < 10 {
-			fmt.Println(line)
-			count++
+	var body struct {
+		Providers []struct {
+			ID     string                       `json:"id"`
+			Models map[string]opencodeModelInfo `json:"models"`
+		} `json:"providers"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("decoding providers: %w", err)
+	}
+
+	var models []opencodeModel
+	for _, p := range body.Providers {
+		for id, info := range p.Models {
+			models = append(models, info.toModel(p.ID+"/"+id))
 		}
 	}
-	fmt.Println()
+	sort.Slice(models, func(i, j int) bool { return models[i].ID < models[j].ID })
+	return models, nil
+}
+
+// opencodeModelsFromCLI runs `opencode models --verbose`, which prints each
+// model ID followed by its JSON metadata. Older CLIs print IDs only.
+func opencodeModelsFromCLI() []opencodeModel {
+	out, err := exec.Command("opencode", "models", "--verbose").Output()
+	if err != nil {
+		out, _ = exec.Command("opencode", "models").Output()
+	}
+
+	var models []opencodeModel
+	var current *opencodeModel
+	var block strings.Builder
+	depth := 0
+
+	for _, line := range strings.Split(string(out), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if depth == 0 && !strings.HasPrefix(trimmed, "{") {
+			if trimmed != "" && strings.Contains(trimmed, "/") && !strings.Contains(trimmed, " ") {
+				models = append(models, opencodeModel{ID: trimmed})
+				current = &models[len(models)-1]
+			}
+			continue
+		}
 
-	fmt.Printf("%sRun 'opencode models' for full list%s\n", colorDim, colorReset)
+		block.WriteString(line)
+		block.WriteString("\n")
+		depth += strings.Count(line, "{") - strings.Count(line, "}")
+		if depth == 0 {
+			var info opencodeModelInfo
+			if current != nil && json.Unmarshal([]byte(block.String()), &info) == nil {
+				*current = info.toModel(current.ID)
+			}
+			block.Reset()
+		}
+	}
+
+	sort.Slice(models, func(i, j int) bool { return models[i].ID < models[j].ID })
+	return models
+}
+
+// testOpencodeModel sends a tiny prompt to the model and reports the result.
+func testOpencodeModel(model string) bool {
+	fmt.Printf("Testing %s...\n", model)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	start := time.Now()
+	cmd := exec.CommandContext(ctx, "opencode", "run", "--model", model, "Reply with exactly the word OK and nothing else.")
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	err := cmd.Run()
+	elapsed := time.Since(start).Round(100 * time.Millisecond)
+
+	if err != nil {
+		fmt.Printf("  %s✗%s Failed after %s: %v\n", colorRed, colorReset, elapsed, err)
+		if tail := strings.TrimSpace(out.String()); tail != "" {
+			fmt.Printf("  %s%s%s\n", colorDim, lastLines(tail, 5), colorReset)
+		}
+		return false
+	}
+	if !strings.Contains(strings.ToUpper(out.String()), "OK") {
+		fmt.Printf("  %s⚠%s Responded in %s but without the expected reply\n", colorYellow, colorReset, elapsed)
+		return false
+	}
+	fmt.Printf("  %s✓%s Responded in %s\n", colorGreen, colorReset, elapsed)
+	return true
+}
+
+// activeConfigPath returns the config file settings are written to: the
+// --config file, else the one loaded, else brigade/brigade.config.
+func activeConfigPath(cfg *config.Config) string {
+	path := cfgFile
+	if path == "" && cfg != nil {
+		path = cfg.Path()
+	}
+	if path == "" {
+		path = "brigade/brigade.config"
+	}
+	return path
+}
+
+// writeOpencodeModel stores the model in the active config file.
+func writeOpencodeModel(cfg *config.Config, model string) error {
+	path := activeConfigPath(cfg)
+	if err := config.SetFileValue(path, "OPENCODE_MODEL", model); err != nil {
+		return err
+	}
+	fmt.Printf("%s✓%s Set OPENCODE_MODEL=%q in %s\n", colorGreen, colorReset, model, path)
+	if cfg != nil && !cfg.UseOpenCode {
+		fmt.Printf("%sSet USE_OPENCODE=true to route Line Cook tasks through OpenCode.%s\n", colorDim, colorReset)
+	}
 	return nil
 }
+
+// lastLines returns the last n lines of s.
+func lastLines(s string, n int) string {
+	lines := strings.Split(s, "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
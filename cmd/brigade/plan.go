@@ -12,10 +12,12 @@ import (
 	"github.com/spf13/cobra"
 
 	"brigade/internal/config"
+	"brigade/internal/orchestrator"
 	"brigade/internal/prd"
 	"brigade/internal/state"
 	"brigade/internal/util"
 	"brigade/internal/worker"
+	"brigade/internal/workspace"
 )
 
 var planCmd = &cobra.Command{
@@ -32,11 +34,17 @@ Example:
 			return fmt.Errorf("loading config: %w", err)
 		}
 		description := strings.Join(args, " ")
-		return cmdPlan(description, cfg)
+		return cmdPlan(cmd.Context(), description, cfg)
 	},
 }
 
-func cmdPlan(description string, cfg *config.Config) error {
+func cmdPlan(ctx context.Context, description string, cfg *config.Config) error {
+	return cmdPlanWithContext(ctx, description, "", cfg)
+}
+
+// cmdPlanWithContext generates a PRD, seeding the planner with an exploration
+// report when one is given.
+func cmdPlanWithContext(ctx context.Context, description, explorationPath string, cfg *config.Config) error {
 	// Create tasks directory if it doesn't exist
 	if err := os.MkdirAll("brigade/tasks", 0755); err != nil {
 		return err
@@ -83,6 +91,28 @@ func cmdPlan(description string, cfg *config.Config) error {
 		promptBuilder.WriteString("\n---\n")
 	}
 
+	// Let the planner scope tasks to monorepo packages
+	if ws := workspace.Detect("."); ws != nil && len(ws.Members) > 0 {
+		promptBuilder.WriteString(fmt.Sprintf("\n---\nMONOREPO WORKSPACES (%s):\n", ws.Kind))
+		for _, m := range ws.Members {
+			promptBuilder.WriteString("- " + m + "\n")
+		}
+		promptBuilder.WriteString("Set \"workspace\" on each task that only touches one of these packages, e.g. \"workspace\": \"" + ws.Members[0] + "\". ")
+		promptBuilder.WriteString("The worker runs in that directory and verification commands run from it, so write them relative to the package.\n---\n")
+	}
+
+	// Include exploration findings the plan should build on
+	if explorationPath != "" {
+		content, err := os.ReadFile(explorationPath)
+		if err != nil {
+			return fmt.Errorf("reading exploration: %w", err)
+		}
+		promptBuilder.WriteString(fmt.Sprintf("\n---\nEXPLORATION REPORT (%s):\n", explorationPath))
+		promptBuilder.Write(content)
+		promptBuilder.WriteString("\n---\n")
+		promptBuilder.WriteString("Derive the tasks from the report's recommended approach. Respect its risks and open questions; add an investigation task where something is unresolved.\n\n")
+	}
+
 	// Add planning request
 	promptBuilder.WriteString(fmt.Sprintf(`PLANNING REQUEST
 
@@ -113,17 +143,22 @@ BEGIN PLANNING:`, description, prdPath, today, prdPath, prdPath))
 	start := time.Now()
 
 	// Create worker for Executive Chef
+	env, err := orchestrator.WorkerEnv(cfg, state.TierExecutive)
+	if err != nil {
+		return err
+	}
 	workerCfg := &worker.Config{
 		Command:    cfg.ExecutiveCmd,
 		Tier:       state.TierExecutive,
 		Timeout:    cfg.TaskTimeoutExecutive,
 		WorkingDir: "",
 		Quiet:      false, // Show output
+		Env:        env,
 	}
 	exec := worker.NewCLIWorker(workerCfg)
 
 	// Execute
-	result, err := exec.Execute(context.Background(), prompt)
+	result, err := exec.Execute(ctx, prompt)
 	if err != nil {
 		return fmt.Errorf("executing plan: %w", err)
 	}
@@ -160,6 +195,7 @@ BEGIN PLANNING:`, description, prdPath, today, prdPath, prdPath))
 
 		// Show summary
 		if p, err := prd.Load(generatedPath); err == nil {
+			recordPRDRevision(p, prd.SourcePlanner)
 			juniorCount := 0
 			seniorCount := 0
 			for _, task := range p.Tasks {
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"brigade/internal/prd"
+)
+
+var prdCmd = &cobra.Command{
+	Use:   "prd",
+	Short: "Work with PRD files",
+}
+
+var prdDiffCmd = &cobra.Command{
+	Use:   "diff <old.json> <new.json>",
+	Short: "Show what changed between two PRDs",
+	Long: `Compares two versions of a PRD, matching tasks by ID: tasks added and
+removed, and for each task in both, changed fields, acceptance criteria,
+dependencies and verification commands. Useful for reviewing planner output
+or hand edits before a re-run.
+
+Examples:
+  ./brigade-go prd diff brigade/tasks/prd-auth.json /tmp/prd-auth-replanned.json
+  ./brigade-go prd diff --json old.json new.json`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+		return cmdPRDDiff(args[0], args[1], jsonOutput)
+	},
+}
+
+var prdHistoryCmd = &cobra.Command{
+	Use:   "history <prd.json>",
+	Short: "List a PRD's recorded revisions",
+	Long: `Lists the versions of a PRD kept in its history directory
+(prd-<name>.history/), with what made each change: a hand edit, the planner,
+replan, a graph edit or a phase review's remediation tasks. A revision is
+recorded when one of those saves the PRD and when a service starts on a PRD
+that changed since the last one. Task completion doesn't count as a change.
+
+Each revision is a full copy, so any two can be compared with prd diff.
+
+Examples:
+  ./brigade-go prd history brigade/tasks/prd-auth.json
+  ./brigade-go prd diff brigade/tasks/prd-auth.history/v1.json brigade/tasks/prd-auth.json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+		return cmdPRDHistory(args[0], jsonOutput)
+	},
+}
+
+func init() {
+	prdDiffCmd.Flags().Bool("json", false, "output as JSON")
+	prdHistoryCmd.Flags().Bool("json", false, "output as JSON")
+	prdCmd.AddCommand(prdDiffCmd, prdHistoryCmd)
+}
+
+// cmdPRDDiff prints the differences between two PRDs.
+func cmdPRDDiff(oldPath, newPath string, jsonOutput bool) error {
+	before, err := prd.Load(oldPath)
+	if err != nil {
+		return fmt.Errorf("%s: %w", oldPath, err)
+	}
+	after, err := prd.Load(newPath)
+	if err != nil {
+		return fmt.Errorf("%s: %w", newPath, err)
+	}
+	d := prd.Compare(before, after)
+
+	if jsonOutput {
+		data, _ := json.MarshalIndent(d, "", "  ")
+		fmt.Println(string(data))
+		return nil
+	}
+
+	printPRDDiff(before, after, d, oldPath, newPath)
+	return nil
+}
+
+// printPRDDiff prints a PRD diff with the two versions labeled.
+func printPRDDiff(before, after *prd.PRD, d *prd.Diff, oldLabel, newLabel string) {
+	fmt.Printf("%s--- %s%s\n", colorRed, oldLabel, colorReset)
+	fmt.Printf("%s+++ %s%s\n", colorGreen, newLabel, colorReset)
+	if d.Empty() {
+		fmt.Println("\nNo differences")
+		return
+	}
+
+	if len(d.Fields) > 0 {
+		fmt.Println()
+		for _, f := range d.Fields {
+			printFieldChange("", f)
+		}
+	}
+
+	if len(d.Added) > 0 {
+		fmt.Printf("\n%sAdded tasks:%s\n", colorBold, colorReset)
+		for _, id := range d.Added {
+			fmt.Printf("  %s+ %s: %s%s\n", colorGreen, id, after.TaskByID(id).Title, colorReset)
+		}
+	}
+	if len(d.Removed) > 0 {
+		fmt.Printf("\n%sRemoved tasks:%s\n", colorBold, colorReset)
+		for _, id := range d.Removed {
+			fmt.Printf("  %s- %s: %s%s\n", colorRed, id, before.TaskByID(id).Title, colorReset)
+		}
+	}
+	if len(d.Modified) > 0 {
+		fmt.Printf("\n%sModified tasks:%s\n", colorBold, colorReset)
+		for _, td := range d.Modified {
+			fmt.Printf("  %s~ %s: %s%s\n", colorYellow, td.ID, after.TaskByID(td.ID).Title, colorReset)
+			for _, f := range td.Fields {
+				printFieldChange("      ", f)
+			}
+			printListChange("criterion", td.CriteriaAdded, td.CriteriaRemoved)
+			printListChange("depends on", td.DepsAdded, td.DepsRemoved)
+			printListChange("verification", td.VerificationAdded, td.VerificationRemoved)
+		}
+	}
+
+	fmt.Printf("\n%d added, %d removed, %d modified\n", len(d.Added), len(d.Removed), len(d.Modified))
+}
+
+// cmdPRDHistory prints a PRD's revisions.
+func cmdPRDHistory(path string, jsonOutput bool) error {
+	p, err := prd.Load(path)
+	if err != nil {
+		return err
+	}
+	revisions, err := p.Revisions()
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput {
+		data, _ := json.MarshalIndent(revisions, "", "  ")
+		fmt.Println(string(data))
+		return nil
+	}
+
+	fmt.Printf("%s=== History: %s ===%s\n", colorBold, p.FeatureName, colorReset)
+	if len(revisions) == 0 {
+		fmt.Println("\nNo revisions recorded yet")
+		return nil
+	}
+	fmt.Println()
+	for _, rev := range revisions {
+		when := rev.Timestamp
+		if t, err := time.Parse(time.RFC3339, rev.Timestamp); err == nil {
+			when = t.Format("2006-01-02 15:04")
+		}
+		author := ""
+		if rev.Author != "" {
+			author = fmt.Sprintf(" %s(%s)%s", colorDim, rev.Author, colorReset)
+		}
+		fmt.Printf("  %sv%d%s  %s  %-12s %s%s\n", colorCyan, rev.Version, colorReset, when, rev.Source, rev.Summary, author)
+	}
+	if latest := revisions[len(revisions)-1]; latest.Hash != p.ContentHash() {
+		fmt.Printf("\n%s⚠ %s changed since v%d (not yet recorded)%s\n", colorYellow, path, latest.Version, colorReset)
+	}
+	fmt.Printf("\n%sCopies in %s/%s\n", colorDim, p.HistoryDir(), colorReset)
+	return nil
+}
+
+// recordPRDRevision adds a PRD to its version history, warning if it can't.
+func recordPRDRevision(p *prd.PRD, source string) {
+	if _, _, err := p.RecordRevision(source); err != nil {
+		fmt.Printf("%sWarning: PRD history not updated: %v%s\n", colorYellow, err, colorReset)
+	}
+}
+
+// printFieldChange prints a field's old and new value.
+func printFieldChange(indent string, f prd.FieldChange) {
+	fmt.Printf("%s%s: %s → %s\n", indent, f.Field, quoteValue(f.Old), quoteValue(f.New))
+}
+
+// printListChange prints the entries added to and removed from a task list.
+func printListChange(label string, added, removed []string) {
+	for _, s := range removed {
+		fmt.Printf("      %s- %s: %s%s\n", colorRed, label, s, colorReset)
+	}
+	for _, s := range added {
+		fmt.Printf("      %s+ %s: %s%s\n", colorGreen, label, s, colorReset)
+	}
+}
+
+// quoteValue quotes a changed value, showing an empty one as (none).
+func quoteValue(s string) string {
+	if strings.TrimSpace(s) == "" {
+		return "(none)"
+	}
+	return fmt.Sprintf("%q", s)
+}
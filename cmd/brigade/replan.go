@@ -0,0 +1,195 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"brigade/internal/config"
+	"brigade/internal/orchestrator"
+	"brigade/internal/prd"
+	"brigade/internal/state"
+	"brigade/internal/worker"
+)
+
+var replanCmd = &cobra.Command{
+	Use:   "replan <prd.json> <requirement>",
+	Short: "Have the Executive Chef update a PRD for a new requirement",
+	Long: `Asks the Executive Chef to rework an existing PRD for a changed or new
+requirement: adding and modifying tasks and adjusting dependencies. Completed
+tasks are kept as they are, and the PRD keeps its path and branch, so the
+run's state still applies and a resumed (or running) service carries on from
+where it was.
+
+The proposed changes are shown as a diff and validated before anything is
+saved; a PRD with validation errors isn't saved. A running service picks up
+the saved PRD before its next task.
+
+Examples:
+  ./brigade-go replan brigade/tasks/prd-auth.json "Support OAuth login alongside passwords"
+  ./brigade-go --dry-run replan brigade/tasks/prd-auth.json "Drop the admin UI"
+  ./brigade-go replan --yes brigade/tasks/prd-auth.json "Sessions expire after 24h"`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load(cfgFile)
+		if err != nil {
+			return fmt.Errorf("loading config: %w", err)
+		}
+		yes, _ := cmd.Flags().GetBool("yes")
+		return cmdReplan(cmd.Context(), args[0], strings.Join(args[1:], " "), yes, cfg)
+	},
+}
+
+func init() {
+	replanCmd.Flags().BoolP("yes", "y", false, "save without confirming")
+}
+
+// cmdReplan has the executive rework a PRD for a new requirement and saves
+// the result once it's been reviewed.
+func cmdReplan(ctx context.Context, path, requirement string, yes bool, cfg *config.Config) error {
+	current, err := prd.Load(path)
+	if err != nil {
+		return err
+	}
+	completed := make(map[string]bool)
+	if st, err := state.ForPRD(path).Load(); err == nil {
+		completed = st.CompletedTaskIDs()
+	}
+	for _, task := range current.Tasks {
+		if task.Passes {
+			completed[task.ID] = true
+		}
+	}
+
+	fmt.Printf("%sAsking Executive Chef to replan %s (%d tasks, %d complete)...%s\n",
+		colorDim, path, len(current.Tasks), len(completed), colorReset)
+	prerequisites, err := orchestrator.PrerequisiteContext(current)
+	if err != nil {
+		fmt.Printf("%sNote: %v%s\n", colorYellow, err, colorReset)
+	}
+	prompt, err := replanPrompt(current, completed, requirement, prerequisites)
+	if err != nil {
+		return err
+	}
+	start := time.Now()
+	env, err := orchestrator.WorkerEnv(cfg, state.TierExecutive)
+	if err != nil {
+		return err
+	}
+	exec := worker.NewCLIWorker(&worker.Config{
+		Command: cfg.ExecutiveCmd,
+		Tier:    state.TierExecutive,
+		Timeout: cfg.TaskTimeoutExecutive,
+		Quiet:   true,
+		Env:     env,
+	})
+	result, err := exec.Execute(ctx, prompt)
+	if err != nil {
+		return fmt.Errorf("replanning: %w", err)
+	}
+	fmt.Printf("%sDuration: %ds%s\n", colorDim, int(time.Since(start).Seconds()), colorReset)
+
+	proposed, err := prd.ParseReplan(result.Output)
+	if err != nil {
+		return fmt.Errorf("replanning: %w", err)
+	}
+	updated, notes := prd.Replan(current, proposed, completed)
+	for _, note := range notes {
+		fmt.Printf("%sNote: %s%s\n", colorYellow, note, colorReset)
+	}
+
+	fmt.Println()
+	d := prd.Compare(current, updated)
+	printPRDDiff(current, updated, d, path, path+" (replanned)")
+	if d.Empty() {
+		return nil
+	}
+
+	validation := updated.ValidateQuick()
+	if len(validation.Errors) > 0 {
+		fmt.Println("\nErrors:")
+		for _, e := range validation.Errors {
+			fmt.Printf("  ✗ %s\n", e)
+		}
+		return &prd.InvalidError{Path: path, Errors: validation.Errors}
+	}
+
+	if dryRun {
+		fmt.Printf("\n%sDry run - %s not saved%s\n", colorDim, path, colorReset)
+		return nil
+	}
+	if !yes && !confirmPrompt("\nSave the replanned PRD? (y/N) ", false) {
+		fmt.Printf("%sAborted.%s\n", colorDim, colorReset)
+		return nil
+	}
+	recordPRDRevision(current, prd.SourceEdit)
+	if err := updated.Save(""); err != nil {
+		return err
+	}
+	recordPRDRevision(updated, prd.SourceReplan)
+	fmt.Printf("%s✓ PRD updated:%s %s\n", colorGreen, colorReset, path)
+
+	lock := state.NewServiceLock(path, state.WithHeartbeatInterval(cfg.LockHeartbeatInterval))
+	if lock.Exists() && !lock.IsStale() {
+		fmt.Println("The running service picks it up before its next task.")
+	} else {
+		fmt.Printf("Resume with: %s./brigade-go resume %s%s\n", colorCyan, path, colorReset)
+	}
+	return nil
+}
+
+// replanPrompt asks the executive for the full updated PRD. prerequisites
+// summarizes the PRDs it depends on, if any.
+func replanPrompt(current *prd.PRD, completed map[string]bool, requirement, prerequisites string) (string, error) {
+	data, err := json.MarshalIndent(current, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshaling PRD: %w", err)
+	}
+	done := make([]string, 0, len(completed))
+	for id := range completed {
+		if current.TaskByID(id) != nil {
+			done = append(done, id)
+		}
+	}
+	sort.Strings(done)
+	if len(done) == 0 {
+		done = append(done, "(none)")
+	}
+	if prerequisites != "" {
+		prerequisites = "\nPREREQUISITE PRDS:\n" + prerequisites + "\n"
+	}
+
+	return fmt.Sprintf(`You are the Executive Chef. A PRD is partway through execution and its
+requirements have changed. Update the PRD for the new requirement.
+
+NEW REQUIREMENT:
+%s
+
+CURRENT PRD:
+%s
+
+COMPLETED TASKS (already implemented - do not change or remove them):
+%s
+%s
+INSTRUCTIONS:
+1. Analyze the codebase and the current PRD to see what the requirement affects
+2. Add tasks for new work, continuing the existing ID sequence
+3. Modify or remove pending tasks the requirement changes or makes unnecessary
+4. Adjust dependencies so new and changed tasks run in the right order; tasks
+   may depend on completed ones
+5. Keep existing task IDs for tasks you keep, and leave featureName and
+   branchName as they are
+6. Give every new or changed task a complexity and specific, verifiable
+   acceptance criteria
+
+OUTPUT:
+The complete updated PRD JSON (every task, not only the changed ones), in:
+<prd>
+{...}
+</prd>`, requirement, data, strings.Join(done, ", "), prerequisites), nil
+}
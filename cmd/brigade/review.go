@@ -0,0 +1,305 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"brigade/internal/prd"
+	"brigade/internal/state"
+	"brigade/internal/util"
+)
+
+var reviewCmd = &cobra.Command{
+	Use:   "review",
+	Short: "Spot-check completions flagged for a human",
+	Long: `With HUMAN_REVIEW_QUEUE on, a service flags completed tasks whose executive
+review passed with low confidence (HUMAN_REVIEW_CONFIDENCE) or that took more
+than HUMAN_REVIEW_ITERATIONS attempts. These commands work through the queue:
+look at what a task changed, then approve it or add a remediation task.`,
+}
+
+var reviewListCmd = &cobra.Command{
+	Use:   "list [prd.json...]",
+	Short: "List completions awaiting a human review",
+	Long: `Lists the tasks flagged for a human spot-check and why. Without arguments
+every PRD in brigade/tasks/ is checked.
+
+Examples:
+  ./brigade-go review list
+  ./brigade-go review list --all brigade/tasks/prd-auth.json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		all, _ := cmd.Flags().GetBool("all")
+		return cmdReviewList(args, all)
+	},
+}
+
+var reviewOpenCmd = &cobra.Command{
+	Use:   "open <prd.json> <task>",
+	Short: "Show a flagged task's diff, criteria and AI review",
+	Long: `Shows why a task was flagged, its acceptance criteria, what the executive
+review said, and the changes it made, then asks whether to approve it or add
+a remediation task.
+
+Examples:
+  ./brigade-go review open brigade/tasks/prd-auth.json US-004
+  ./brigade-go review open --stat brigade/tasks/prd-auth.json US-004`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		stat, _ := cmd.Flags().GetBool("stat")
+		return cmdReviewOpen(args[0], args[1], stat)
+	},
+}
+
+var reviewApproveCmd = &cobra.Command{
+	Use:   "approve <prd.json> <task> [note]",
+	Short: "Approve a flagged task",
+	Args:  cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmdReviewResolve(args[0], args[1], strings.Join(args[2:], " "), false)
+	},
+}
+
+var reviewRemediateCmd = &cobra.Command{
+	Use:   "remediate <prd.json> <task> <what needs fixing>",
+	Short: "Add a task to fix what a flagged task got wrong",
+	Long: `Adds a remediation task to the PRD that depends on the flagged task, with
+your note as its acceptance criterion, and marks the review resolved. The
+next service run (or a running one, before its next task) picks it up.
+
+Examples:
+  ./brigade-go review remediate brigade/tasks/prd-auth.json US-004 "Token refresh ignores the clock skew setting"`,
+	Args: cobra.MinimumNArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return cmdReviewResolve(args[0], args[1], strings.Join(args[2:], " "), true)
+	},
+}
+
+func init() {
+	reviewListCmd.Flags().Bool("all", false, "include reviews already resolved")
+	reviewOpenCmd.Flags().Bool("stat", false, "show only a summary of the changes")
+	reviewCmd.AddCommand(reviewListCmd, reviewOpenCmd, reviewApproveCmd, reviewRemediateCmd)
+}
+
+// cmdReviewList prints the human review queue of each PRD.
+func cmdReviewList(prdPaths []string, all bool) error {
+	if len(prdPaths) == 0 {
+		states, _ := filepath.Glob("brigade/tasks/*.state.json")
+		for _, path := range states {
+			prdPaths = append(prdPaths, strings.TrimSuffix(path, ".state.json")+".json")
+		}
+	}
+
+	pending := 0
+	for _, path := range prdPaths {
+		p, err := prd.Load(path)
+		if err != nil {
+			continue
+		}
+		st, err := state.ForPRD(path).Load()
+		if err != nil {
+			continue
+		}
+		entries := st.PendingHumanReviews()
+		if all {
+			entries = st.ReviewQueue
+		}
+		if len(entries) == 0 {
+			continue
+		}
+
+		fmt.Printf("%s%s%s %s(%s)%s\n", colorBold, p.FeatureName, colorReset, colorDim, path, colorReset)
+		for _, q := range entries {
+			title := ""
+			if task := p.TaskByID(q.TaskID); task != nil {
+				title = task.Title
+			}
+			marker := colorYellow + "◐" + colorReset
+			detail := strings.Join(q.Reasons, "; ")
+			switch q.Status {
+			case state.HumanReviewApproved:
+				marker = colorGreen + "✓" + colorReset
+				detail = "approved"
+			case state.HumanReviewRemediated:
+				marker = colorRed + "✗" + colorReset
+				detail = "remediation " + q.Remediation
+			default:
+				pending++
+			}
+			fmt.Printf("  %s %s: %s %s- %s%s\n", marker, q.TaskID, title, colorDim, detail, colorReset)
+		}
+		fmt.Println()
+	}
+
+	if pending == 0 {
+		fmt.Printf("%s✓ Nothing awaiting review%s\n", colorGreen, colorReset)
+		return nil
+	}
+	fmt.Printf("%d awaiting review. Open one with: %s./brigade-go review open <prd.json> <task>%s\n", pending, colorCyan, colorReset)
+	return nil
+}
+
+// cmdReviewOpen shows a flagged task and asks for a verdict.
+func cmdReviewOpen(path, taskID string, stat bool) error {
+	p, err := prd.Load(path)
+	if err != nil {
+		return err
+	}
+	st, err := state.ForPRD(path).Load()
+	if err != nil {
+		return err
+	}
+	q := st.QueuedReview(taskID)
+	if q == nil {
+		return fmt.Errorf("%s isn't in the review queue", taskID)
+	}
+	task := p.TaskByID(taskID)
+	if task == nil {
+		return fmt.Errorf("task %s not found in %s", taskID, path)
+	}
+
+	fmt.Printf("%s=== Review: %s - %s ===%s\n", colorBold, task.ID, task.Title, colorReset)
+	fmt.Printf("Flagged:   %s\n", strings.Join(q.Reasons, "; "))
+	fmt.Printf("Completed: %s\n", q.Timestamp)
+	if q.Status != state.HumanReviewPending {
+		fmt.Printf("Resolved:  %s %s", q.Status, q.ResolvedAt)
+		if q.Remediation != "" {
+			fmt.Printf(" (%s)", q.Remediation)
+		}
+		fmt.Println()
+	}
+
+	fmt.Printf("\n%sAcceptance criteria:%s\n", colorBold, colorReset)
+	for _, trace := range task.Trace() {
+		fmt.Printf("  %d. %s\n", trace.Index, trace.Criterion)
+		if len(trace.Commands) > 0 {
+			fmt.Printf("     %sverified by: %s%s\n", colorDim, strings.Join(trace.Commands, "; "), colorReset)
+		}
+	}
+
+	fmt.Printf("\n%sExecutive review:%s", colorBold, colorReset)
+	if q.Confidence != "" {
+		fmt.Printf(" passed with %s confidence", q.Confidence)
+	}
+	fmt.Println()
+	if q.Review != "" {
+		for _, line := range strings.Split(q.Review, "\n") {
+			fmt.Printf("  %s\n", line)
+		}
+	} else {
+		fmt.Printf("  %s(no concerns recorded)%s\n", colorDim, colorReset)
+	}
+
+	to := q.Commit
+	if to == q.BaseCommit {
+		to = "" // Nothing committed; the work is in the tree
+	}
+	fmt.Printf("\n%sChanges:%s\n", colorBold, colorReset)
+	if diff := util.GitDiffRange(q.BaseCommit, to, stat); diff != "" {
+		fmt.Println(diff)
+	} else {
+		fmt.Printf("  %s(not recorded - the task ran in parallel, or outside a git repo)%s\n", colorDim, colorReset)
+	}
+
+	if q.Status != state.HumanReviewPending {
+		return nil
+	}
+	reader := bufio.NewReader(os.Stdin)
+	fmt.Print("\n[a]pprove, [r]emediate, or [s]kip? (s) ")
+	response, _ := reader.ReadString('\n')
+	switch strings.TrimSpace(strings.ToLower(response)) {
+	case "a", "approve":
+		return cmdReviewResolve(path, taskID, "", false)
+	case "r", "remediate":
+		fmt.Print("What needs fixing? ")
+		note, _ := reader.ReadString('\n')
+		if note = strings.TrimSpace(note); note == "" {
+			fmt.Printf("%sNo note given; left in the queue.%s\n", colorDim, colorReset)
+			return nil
+		}
+		return cmdReviewResolve(path, taskID, note, true)
+	}
+	fmt.Printf("%sLeft in the queue.%s\n", colorDim, colorReset)
+	return nil
+}
+
+// cmdReviewResolve approves a flagged task, or adds a remediation task for
+// it to the PRD.
+func cmdReviewResolve(path, taskID, note string, remediate bool) error {
+	p, err := prd.Load(path)
+	if err != nil {
+		return err
+	}
+	task := p.TaskByID(taskID)
+	if task == nil {
+		return fmt.Errorf("task %s not found in %s", taskID, path)
+	}
+	store := state.ForPRD(path)
+	st, err := store.Load()
+	if err != nil {
+		return err
+	}
+	if st.QueuedReview(taskID) == nil {
+		return fmt.Errorf("%s isn't in the review queue", taskID)
+	}
+
+	if !remediate {
+		if dryRun {
+			fmt.Printf("Would approve %s\n", taskID)
+			return nil
+		}
+		if err := store.Update(func(st *state.State) error {
+			st.ResolveHumanReview(taskID, state.HumanReviewApproved, note, "")
+			return nil
+		}); err != nil {
+			return err
+		}
+		fmt.Printf("%s✓ Approved %s%s\n", colorGreen, taskID, colorReset)
+		return nil
+	}
+
+	fix := remediationTask(p, task, note)
+	if dryRun {
+		fmt.Printf("Would add %s: %s\n", fix.ID, fix.Title)
+		return nil
+	}
+	p.Tasks = append(p.Tasks, fix)
+	if err := p.Save(""); err != nil {
+		return err
+	}
+	recordPRDRevision(p, prd.SourceHumanReview)
+	if err := store.Update(func(st *state.State) error {
+		st.ResolveHumanReview(taskID, state.HumanReviewRemediated, note, fix.ID)
+		return nil
+	}); err != nil {
+		return err
+	}
+	fmt.Printf("%s✓ Added %s: %s%s\n", colorGreen, fix.ID, fix.Title, colorReset)
+	fmt.Printf("Run it with: %s./brigade-go resume %s%s\n", colorCyan, path, colorReset)
+	return nil
+}
+
+// remediationTask builds a task that fixes what a reviewer found wrong with
+// another, named after it (US-004-FIX, US-004-FIX2, ...).
+func remediationTask(p *prd.PRD, task *prd.Task, note string) prd.Task {
+	id := task.ID + "-FIX"
+	for n := 2; p.TaskByID(id) != nil; n++ {
+		id = fmt.Sprintf("%s-FIX%d", task.ID, n)
+	}
+	return prd.Task{
+		ID:                 id,
+		Title:              fmt.Sprintf("Fix review findings on %s", task.ID),
+		Description:        fmt.Sprintf("A human review of %s (%s) on %s found: %s", task.ID, task.Title, time.Now().Format("2006-01-02"), note),
+		AcceptanceCriteria: []string{note},
+		DependsOn:          []string{task.ID},
+		Complexity:         prd.ComplexitySenior,
+		Phase:              task.Phase,
+		Tags:               task.Tags,
+		Files:              task.Files,
+	}
+}
@@ -0,0 +1,276 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"brigade/internal/config"
+	"brigade/internal/orchestrator"
+	"brigade/internal/prd"
+)
+
+// Inbox statuses written to a dropped PRD's validation file.
+const (
+	inboxInvalid  = "invalid"
+	inboxValid    = "valid"
+	inboxQueued   = "queued"
+	inboxRunning  = "running"
+	inboxComplete = "complete"
+	inboxFailed   = "failed"
+)
+
+// validationSuffix names the file posted next to a dropped PRD.
+const validationSuffix = ".validation.json"
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Validate, and optionally run, PRDs dropped in an inbox",
+	Long: `Watches a directory for PRDs written by other tools. Each new or changed
+PRD is validated, and the result is written next to it as
+<name>.validation.json.
+
+With INBOX_AUTO_QUEUE=true, valid PRDs are moved to brigade/tasks and run
+one at a time, and the validation file follows them through queued, running,
+complete or failed. Otherwise they're left for a human to start.
+
+Stop with Ctrl-C; a PRD being run stops with it and can be resumed.
+
+Examples:
+  ./brigade-go serve --watch brigade/inbox/
+  INBOX_AUTO_QUEUE=true ./brigade-go serve`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load(cfgFile)
+		if err != nil {
+			return fmt.Errorf("loading config: %w", err)
+		}
+		dir, _ := cmd.Flags().GetString("watch")
+		return cmdServe(cmd.Context(), cfg, dir)
+	},
+}
+
+func init() {
+	serveCmd.Flags().String("watch", "brigade/inbox", "directory to watch for PRDs")
+}
+
+// inboxResult is the validation file posted next to a dropped PRD.
+type inboxResult struct {
+	PRD       string   `json:"prd"`
+	CheckedAt string   `json:"checkedAt"`
+	Valid     bool     `json:"valid"`
+	Tasks     int      `json:"tasks"`
+	Errors    []string `json:"errors,omitempty"`
+	Warnings  []string `json:"warnings,omitempty"`
+	Status    string   `json:"status"`
+	QueuedAs  string   `json:"queuedAs,omitempty"` // Where a queued PRD was moved
+	Error     string   `json:"error,omitempty"`    // Why it wasn't queued, or how its run failed
+}
+
+// inboxJob is a queued PRD waiting to run.
+type inboxJob struct {
+	sidecar string
+	result  inboxResult
+}
+
+// cmdServe validates PRDs dropped in dir until ctx is done, queueing valid
+// ones when INBOX_AUTO_QUEUE is on.
+func cmdServe(ctx context.Context, cfg *config.Config, dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	mode := "validating only (INBOX_AUTO_QUEUE=false)"
+	if cfg.InboxAutoQueue {
+		mode = "running valid PRDs"
+	}
+	fmt.Printf("Watching %s for PRDs, %s. Ctrl-C to stop.\n", dir, mode)
+
+	queue := make(chan inboxJob, 64)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for job := range queue {
+			runInboxJob(ctx, job)
+		}
+	}()
+
+	for {
+		for _, path := range pendingInbox(dir) {
+			result := checkInbox(cfg, path)
+			if result.Valid && cfg.InboxAutoQueue && !dryRun {
+				queueInbox(path, &result)
+			}
+			sidecar := sidecarPath(path)
+			if err := writeInboxResult(sidecar, result); err != nil {
+				fmt.Printf("%s✗%s %s: %v\n", colorRed, colorReset, sidecar, err)
+			}
+			printInboxResult(path, result)
+			if result.Status == inboxQueued {
+				queue <- inboxJob{sidecar: sidecar, result: result}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			close(queue)
+			<-done
+			return nil
+		case <-time.After(cfg.InboxPollInterval):
+		}
+	}
+}
+
+// pendingInbox returns the PRDs in dir without a validation file, or
+// changed since theirs was written.
+func pendingInbox(dir string) []string {
+	paths, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	sort.Strings(paths)
+	var pending []string
+	for _, path := range paths {
+		if strings.HasSuffix(path, validationSuffix) {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		if checked, err := os.Stat(sidecarPath(path)); err == nil && !info.ModTime().After(checked.ModTime()) {
+			continue
+		}
+		pending = append(pending, path)
+	}
+	return pending
+}
+
+// sidecarPath returns the validation file for a dropped PRD.
+func sidecarPath(path string) string {
+	return strings.TrimSuffix(path, ".json") + validationSuffix
+}
+
+// checkInbox validates a dropped PRD the way `validate` does.
+func checkInbox(cfg *config.Config, path string) inboxResult {
+	result := inboxResult{PRD: filepath.Base(path), CheckedAt: time.Now().Format(time.RFC3339), Status: inboxInvalid}
+	p, err := prd.Load(path)
+	if err != nil {
+		result.Errors = []string{err.Error()}
+		return result
+	}
+	v := p.ValidateFull(validationOptions(cfg))
+	checkWorkspaces(p, v)
+	for _, e := range v.Errors {
+		result.Errors = append(result.Errors, e.Error())
+	}
+	for _, w := range v.Warnings {
+		result.Warnings = append(result.Warnings, w.Error())
+	}
+	result.Tasks = len(p.Tasks)
+	result.Valid = v.IsValid()
+	if result.Valid {
+		result.Status = inboxValid
+	}
+	return result
+}
+
+// queueInbox moves a valid PRD into brigade/tasks, leaving it where it is
+// if a PRD of that name is already there.
+func queueInbox(path string, result *inboxResult) {
+	name := filepath.Base(path)
+	if !strings.HasPrefix(name, "prd-") {
+		name = "prd-" + name
+	}
+	target := filepath.Join("brigade", "tasks", name)
+	if _, err := os.Stat(target); err == nil {
+		result.Error = fmt.Sprintf("not queued: %s already exists", target)
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		result.Error = "not queued: " + err.Error()
+		return
+	}
+	if err := os.Rename(path, target); err != nil {
+		result.Error = "not queued: " + err.Error()
+		return
+	}
+	result.Status, result.QueuedAs = inboxQueued, target
+}
+
+// runInboxJob runs a queued PRD, keeping its validation file up to date.
+func runInboxJob(ctx context.Context, job inboxJob) {
+	if ctx.Err() != nil {
+		return
+	}
+	update := func(status, errMsg string) {
+		job.result.Status, job.result.Error = status, errMsg
+		if err := writeInboxResult(job.sidecar, job.result); err != nil {
+			fmt.Printf("%s✗%s %s: %v\n", colorRed, colorReset, job.sidecar, err)
+		}
+	}
+
+	// Reload so config changes apply to the next PRD without a restart
+	cfg, err := config.Load(cfgFile)
+	if err != nil {
+		update(inboxFailed, fmt.Sprintf("loading config: %v", err))
+		return
+	}
+	fmt.Printf("\n%s━━━ Running %s ━━━%s\n", colorBold, job.result.QueuedAs, colorReset)
+	// Dropped PRDs meet RISK_WARN_THRESHOLD like any other
+	if err := checkRiskThreshold(job.result.QueuedAs, cfg, false); err != nil {
+		fmt.Printf("%s✗ %s not started:%s %v\n", colorRed, job.result.QueuedAs, colorReset, err)
+		update(inboxFailed, err.Error())
+		return
+	}
+	update(inboxRunning, "")
+
+	orch, err := orchestrator.New(orchestrator.Options{
+		Config:  cfg,
+		PRDPath: job.result.QueuedAs,
+		Logger:  slog.New(slog.NewTextHandler(os.Stderr, nil)),
+	})
+	if err == nil {
+		err = orch.Run(ctx)
+		if result := orch.Result(); result != nil && result.Report != nil {
+			fmt.Print(formatKitchenReport(result.Report))
+		}
+	}
+	if err != nil {
+		fmt.Printf("%s✗ %s failed:%s %v\n", colorRed, job.result.QueuedAs, colorReset, err)
+		update(inboxFailed, err.Error())
+		return
+	}
+	fmt.Printf("%s✓ %s complete%s\n", colorGreen, job.result.QueuedAs, colorReset)
+	update(inboxComplete, "")
+}
+
+// writeInboxResult writes a validation file.
+func writeInboxResult(path string, result inboxResult) error {
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// printInboxResult reports a checked PRD on the console.
+func printInboxResult(path string, result inboxResult) {
+	switch {
+	case !result.Valid:
+		fmt.Printf("%s✗%s %s: invalid\n", colorRed, colorReset, path)
+		for _, e := range result.Errors {
+			fmt.Printf("    %s\n", e)
+		}
+	case result.Status == inboxQueued:
+		fmt.Printf("%s✓%s %s: valid, %d tasks, queued as %s\n", colorGreen, colorReset, path, result.Tasks, result.QueuedAs)
+	case result.Error != "":
+		fmt.Printf("%s⚠%s %s: valid, %d tasks, %s\n", colorYellow, colorReset, path, result.Tasks, result.Error)
+	default:
+		fmt.Printf("%s✓%s %s: valid, %d tasks\n", colorGreen, colorReset, path, result.Tasks)
+	}
+}
@@ -0,0 +1,373 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"brigade/internal/prd"
+	"brigade/internal/state"
+	"brigade/internal/util"
+)
+
+var showCmd = &cobra.Command{
+	Use:   "show [prd.json] <task-id>",
+	Short: "Show a task's attempts, verification, review and diff",
+	Long: `Puts everything known about a task in one view: its details and acceptance
+criteria, a timeline of its attempts, escalations, verifications and
+reviews, the latest verification output, the review verdict, and the diff
+of its completing attempt.
+
+The diff is recorded for tasks completed in a sequential run inside a git
+repo. On a terminal the output goes through $PAGER (less by default).
+
+Without a PRD, the task is looked up in brigade/tasks, preferring the PRD
+where it is running. Use prefix/task-id (e.g. add-auth/US-003) to pick one.
+
+Examples:
+  ./brigade-go show US-003
+  ./brigade-go show --stat brigade/tasks/prd-auth.json US-003
+  ./brigade-go show --no-pager add-auth/US-003 > us-003.txt`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		stat, _ := cmd.Flags().GetBool("stat")
+		noPager, _ := cmd.Flags().GetBool("no-pager")
+
+		var prdPath string
+		if len(args) == 2 {
+			prdPath, args = args[0], args[1:]
+		}
+		p, taskID, err := resolveTaskPRD(prdPath, args[0])
+		if err != nil {
+			return err
+		}
+		st, err := state.ForPRD(p.Path()).Load()
+		if err != nil {
+			return err
+		}
+
+		out := formatTaskView(p, st, p.TaskByID(taskID), stat)
+		if noPager || !stdoutTerminal {
+			fmt.Print(out)
+			return nil
+		}
+		return pageOutput(out)
+	},
+}
+
+func init() {
+	showCmd.Flags().Bool("stat", false, "show only a summary of the changes")
+	showCmd.Flags().Bool("no-pager", false, "print straight to the terminal")
+}
+
+// timelineEvent is one entry in a task's timeline.
+type timelineEvent struct {
+	Timestamp string
+	Marker    string
+	Text      string
+}
+
+// formatTaskView renders everything the state records about a task.
+func formatTaskView(p *prd.PRD, st *state.State, task *prd.Task, stat bool) string {
+	var sb strings.Builder
+	w := func(format string, args ...any) { fmt.Fprintf(&sb, format, args...) }
+
+	w("%s=== %s: %s ===%s\n", colorBold, p.FormatTaskID(task.ID), task.Title, colorReset)
+	w("Status:     %s\n", showTaskStatus(st, task))
+	w("Complexity: %s\n", task.Complexity)
+	if len(task.DependsOn) > 0 {
+		w("Depends on: %s\n", strings.Join(task.DependsOn, ", "))
+	}
+	if task.Phase > 0 {
+		w("Phase:      %d\n", task.Phase)
+	}
+	if len(task.Tags) > 0 {
+		w("Tags:       %s\n", strings.Join(task.Tags, ", "))
+	}
+	if len(task.Files) > 0 {
+		w("Files:      %s\n", strings.Join(task.Files, ", "))
+	}
+	if task.Description != "" {
+		w("\n%s\n", task.Description)
+	}
+
+	w("\n%sAcceptance criteria:%s\n", colorBold, colorReset)
+	for _, trace := range task.Trace() {
+		w("  %d. %s\n", trace.Index, trace.Criterion)
+		if len(trace.Commands) > 0 {
+			w("     %sverified by: %s%s\n", colorDim, strings.Join(trace.Commands, "; "), colorReset)
+		}
+	}
+
+	w("\n%sTimeline:%s\n", colorBold, colorReset)
+	events := taskTimeline(st, task.ID)
+	if len(events) == 0 {
+		w("  %s(no attempts yet)%s\n", colorDim, colorReset)
+	}
+	for _, e := range events {
+		w("  %s%-25s%s %s %s\n", colorDim, e.Timestamp, colorReset, e.Marker, e.Text)
+	}
+
+	if failures := st.GetVerificationFailures(task.ID); len(failures) > 0 {
+		w("\n%sLast verification failures:%s\n", colorBold, colorReset)
+		for _, f := range failures {
+			w("  %s✗ %s%s (exit %d)\n", colorRed, f.Command, colorReset, f.ExitCode)
+			for _, line := range strings.Split(strings.TrimRight(f.Output, "\n"), "\n") {
+				if line != "" {
+					w("    %s\n", line)
+				}
+			}
+		}
+	}
+
+	w("\n%sReview:%s\n", colorBold, colorReset)
+	w("%s", formatReviewVerdict(st, task.ID))
+
+	w("\n%sChanges:%s\n", colorBold, colorReset)
+	from, to := taskCommits(st, task.ID)
+	if from == "" {
+		w("  %s(not recorded - the task hasn't completed, ran in parallel, or ran outside a git repo)%s\n", colorDim, colorReset)
+		return sb.String()
+	}
+	if to == from {
+		w("  %s(left uncommitted; showing the working tree against %s)%s\n", colorDim, shortCommit(from), colorReset)
+		to = ""
+	} else {
+		w("  %s%s..%s%s\n", colorDim, shortCommit(from), shortCommit(to), colorReset)
+	}
+	diff := util.GitDiffRange(from, to, stat)
+	if diff == "" {
+		w("  %s(no changes)%s\n", colorDim, colorReset)
+		return sb.String()
+	}
+	w("%s\n", colorizeDiff(diff))
+	return sb.String()
+}
+
+// showTaskStatus describes where a task stands.
+func showTaskStatus(st *state.State, task *prd.Task) string {
+	completed := st.CompletedTaskIDs()
+	for _, a := range st.Absorptions {
+		if a.TaskID == task.ID {
+			return fmt.Sprintf("%sabsorbed by %s%s", colorGreen, a.AbsorbedBy, colorReset)
+		}
+	}
+	switch {
+	case task.Passes || completed[task.ID]:
+		if q := st.QueuedReview(task.ID); q != nil && q.Status == state.HumanReviewPending {
+			return colorYellow + "complete, awaiting human review" + colorReset
+		}
+		return colorGreen + "complete" + colorReset
+	case st.CurrentTask == task.ID:
+		return colorYellow + "in progress" + colorReset
+	}
+	for _, id := range st.SkippedTaskIDs() {
+		if id == task.ID {
+			return colorRed + "skipped" + colorReset
+		}
+	}
+	return "pending"
+}
+
+// taskTimeline merges a task's attempts, escalations, verifications and
+// reviews in the order they happened.
+func taskTimeline(st *state.State, taskID string) []timelineEvent {
+	var events []timelineEvent
+	attempt := 0
+	for _, h := range st.TaskHistory {
+		if h.TaskID != taskID {
+			continue
+		}
+		attempt++
+		marker := colorRed + "✗" + colorReset
+		switch h.Status {
+		case state.StatusComplete, state.StatusAbsorbed:
+			marker = colorGreen + "✓" + colorReset
+		case state.StatusInProgress:
+			marker = colorYellow + "→" + colorReset
+		}
+		text := fmt.Sprintf("attempt %d (%s): %s", attempt, h.Worker, h.Status)
+		if h.Duration > 0 {
+			text += fmt.Sprintf(" in %s", util.FormatDuration(time.Duration(h.Duration)*time.Second))
+		}
+		if h.DiffLines > 0 {
+			text += fmt.Sprintf(", %d lines changed", h.DiffLines)
+		}
+		if h.Error != "" {
+			text += fmt.Sprintf(" %s- %s%s", colorDim, firstLine(h.Error), colorReset)
+		} else if h.Approach != "" {
+			text += fmt.Sprintf(" %s- %s%s", colorDim, firstLine(h.Approach), colorReset)
+		}
+		events = append(events, timelineEvent{h.Timestamp, marker, text})
+	}
+	for _, e := range st.Escalations {
+		if e.TaskID == taskID {
+			events = append(events, timelineEvent{e.Timestamp, colorYellow + "↑" + colorReset,
+				fmt.Sprintf("escalated %s → %s %s- %s%s", e.From, e.To, colorDim, e.Reason, colorReset)})
+		}
+	}
+	for _, v := range st.Verifications {
+		if v.TaskID != taskID {
+			continue
+		}
+		marker, text := colorGreen+"✓"+colorReset, "verification passed"
+		if !v.Passed {
+			marker, text = colorRed+"✗"+colorReset, "verification failed: "+strings.Join(v.Failed, "; ")
+		}
+		if len(v.Flaky) > 0 {
+			text += fmt.Sprintf(" %s(flaky: %s)%s", colorDim, strings.Join(v.Flaky, "; "), colorReset)
+		}
+		events = append(events, timelineEvent{v.Timestamp, marker, text})
+	}
+	for _, r := range st.Reviews {
+		if r.TaskID != taskID {
+			continue
+		}
+		marker, text := colorGreen+"✓"+colorReset, "executive review passed"
+		if !strings.EqualFold(r.Result, "pass") {
+			marker, text = colorRed+"✗"+colorReset, "executive review failed"
+		}
+		if r.Reason != "" {
+			text += fmt.Sprintf(" %s- %s%s", colorDim, firstLine(r.Reason), colorReset)
+		}
+		events = append(events, timelineEvent{r.Timestamp, marker, text})
+	}
+	for _, a := range st.Absorptions {
+		if a.TaskID == taskID {
+			events = append(events, timelineEvent{a.Timestamp, colorGreen + "✓" + colorReset, "absorbed by " + a.AbsorbedBy})
+		}
+	}
+	if q := st.QueuedReview(taskID); q != nil {
+		events = append(events, timelineEvent{q.Timestamp, colorYellow + "◐" + colorReset,
+			"queued for human review: " + strings.Join(q.Reasons, "; ")})
+		if q.ResolvedAt != "" {
+			events = append(events, timelineEvent{q.ResolvedAt, colorGreen + "✓" + colorReset, "human review: " + q.Status})
+		}
+	}
+
+	// RFC 3339 timestamps in one zone sort as strings
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Timestamp < events[j].Timestamp })
+	return events
+}
+
+// formatReviewVerdict describes the executive review's latest verdict on a
+// task and any human review of it.
+func formatReviewVerdict(st *state.State, taskID string) string {
+	var sb strings.Builder
+	var last *state.Review
+	for i := range st.Reviews {
+		if st.Reviews[i].TaskID == taskID {
+			last = &st.Reviews[i]
+		}
+	}
+	switch {
+	case last == nil:
+		sb.WriteString(fmt.Sprintf("  %s(not reviewed)%s\n", colorDim, colorReset))
+	case strings.EqualFold(last.Result, "pass"):
+		sb.WriteString(fmt.Sprintf("  %s✓ Executive review passed%s\n", colorGreen, colorReset))
+	default:
+		sb.WriteString(fmt.Sprintf("  %s✗ Executive review failed%s\n", colorRed, colorReset))
+		if last.Reason != "" {
+			sb.WriteString(fmt.Sprintf("    %s\n", last.Reason))
+		}
+	}
+
+	q := st.QueuedReview(taskID)
+	if q == nil {
+		return sb.String()
+	}
+	if q.Confidence != "" {
+		sb.WriteString(fmt.Sprintf("  Confidence: %s\n", q.Confidence))
+	}
+	if q.Review != "" {
+		sb.WriteString("  Concerns:\n")
+		for _, line := range strings.Split(q.Review, "\n") {
+			sb.WriteString(fmt.Sprintf("    %s\n", line))
+		}
+	}
+	switch q.Status {
+	case state.HumanReviewPending:
+		sb.WriteString(fmt.Sprintf("  %s◐ Awaiting human review%s (%s)\n", colorYellow, colorReset, strings.Join(q.Reasons, "; ")))
+	case state.HumanReviewApproved:
+		sb.WriteString(fmt.Sprintf("  %s✓ Approved by a human reviewer%s\n", colorGreen, colorReset))
+	case state.HumanReviewRemediated:
+		sb.WriteString(fmt.Sprintf("  %s✗ Human reviewer added %s%s\n", colorRed, q.Remediation, colorReset))
+	}
+	if q.Note != "" {
+		sb.WriteString(fmt.Sprintf("    %s\n", q.Note))
+	}
+	return sb.String()
+}
+
+// taskCommits returns the commits a task's completing attempt spans, or ""
+// if they weren't recorded.
+func taskCommits(st *state.State, taskID string) (string, string) {
+	for i := len(st.TaskHistory) - 1; i >= 0; i-- {
+		h := st.TaskHistory[i]
+		if h.TaskID == taskID && h.Status == state.StatusComplete && h.BaseCommit != "" {
+			return h.BaseCommit, h.Commit
+		}
+	}
+	if q := st.QueuedReview(taskID); q != nil && q.BaseCommit != "" {
+		return q.BaseCommit, q.Commit
+	}
+	return "", ""
+}
+
+// colorizeDiff colors a unified diff's added, removed and hunk lines.
+func colorizeDiff(diff string) string {
+	lines := strings.Split(diff, "\n")
+	for i, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"), strings.HasPrefix(line, "diff --git"):
+			lines[i] = colorBold + line + colorReset
+		case strings.HasPrefix(line, "+"):
+			lines[i] = colorGreen + line + colorReset
+		case strings.HasPrefix(line, "-"):
+			lines[i] = colorRed + line + colorReset
+		case strings.HasPrefix(line, "@@"):
+			lines[i] = colorCyan + line + colorReset
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// pageOutput shows text through $PAGER, or less when it isn't set, falling
+// back to printing it.
+func pageOutput(text string) error {
+	pager := os.Getenv("PAGER")
+	if pager == "" {
+		if _, err := exec.LookPath("less"); err != nil {
+			fmt.Print(text)
+			return nil
+		}
+		// Keep colors, and quit when it fits on one screen
+		pager = "less -FRX"
+	}
+	cmd := exec.Command("sh", "-c", pager)
+	cmd.Stdin = strings.NewReader(text)
+	cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		fmt.Print(text)
+	}
+	return nil
+}
+
+// shortCommit abbreviates a commit hash.
+func shortCommit(hash string) string {
+	if len(hash) > 8 {
+		return hash[:8]
+	}
+	return hash
+}
+
+// firstLine returns the first line of s.
+func firstLine(s string) string {
+	s, _, _ = strings.Cut(strings.TrimSpace(s), "\n")
+	return s
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"brigade/internal/config"
+	"brigade/internal/state"
+)
+
+var stateCmd = &cobra.Command{
+	Use:   "state",
+	Short: "Maintain PRD state files",
+}
+
+var stateScrubCmd = &cobra.Command{
+	Use:   "scrub [prd.json...]",
+	Short: "Redact stored worker output in state files",
+	Long: `Reduces the error messages, approaches and verification output stored in
+state files to their first line, with absolute paths shortened. Categories,
+statuses, timings and costs are kept.
+
+Entries are selected by age (--older-than, default STATE_SCRUB_AFTER_DAYS),
+by task completion (--completed, default STATE_SCRUB_COMPLETED), or all of
+them (--all). Without arguments every state file in brigade/tasks/ is
+scrubbed. A running service applies the configured policy itself.
+
+Examples:
+  ./brigade-go state scrub --completed
+  ./brigade-go state scrub --older-than 14 brigade/tasks/prd-auth.json
+  ./brigade-go state scrub --all --dry-run`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load(cfgFile)
+		if err != nil {
+			return fmt.Errorf("loading config: %w", err)
+		}
+		policy := state.ScrubPolicy{
+			OlderThan: time.Duration(cfg.StateScrubAfterDays) * 24 * time.Hour,
+			Completed: cfg.StateScrubCompleted,
+		}
+		if cmd.Flags().Changed("older-than") {
+			days, _ := cmd.Flags().GetInt("older-than")
+			policy.OlderThan = time.Duration(days) * 24 * time.Hour
+		}
+		if cmd.Flags().Changed("completed") {
+			policy.Completed, _ = cmd.Flags().GetBool("completed")
+		}
+		policy.All, _ = cmd.Flags().GetBool("all")
+		if !policy.Active() {
+			return fmt.Errorf("nothing to scrub: pass --older-than, --completed or --all, or set STATE_SCRUB_AFTER_DAYS / STATE_SCRUB_COMPLETED")
+		}
+		return cmdStateScrub(args, policy, dryRun)
+	},
+}
+
+func init() {
+	stateScrubCmd.Flags().Int("older-than", 0, "scrub entries older than this many days")
+	stateScrubCmd.Flags().Bool("completed", false, "scrub entries of completed tasks")
+	stateScrubCmd.Flags().Bool("all", false, "scrub every entry")
+	stateCmd.AddCommand(stateScrubCmd)
+}
+
+func cmdStateScrub(prdPaths []string, policy state.ScrubPolicy, dryRun bool) error {
+	var paths []string
+	for _, prdPath := range prdPaths {
+		paths = append(paths, state.ForPRD(prdPath).Path())
+	}
+	if len(prdPaths) == 0 {
+		paths, _ = filepath.Glob("brigade/tasks/*.state.json")
+		if len(paths) == 0 {
+			fmt.Println("No state files in brigade/tasks/")
+			return nil
+		}
+	}
+
+	now := time.Now()
+	total := 0
+	for _, path := range paths {
+		store := state.NewStore(path)
+		if !store.Exists() {
+			fmt.Printf("%s⚠%s %s: no state file\n", colorYellow, colorReset, path)
+			continue
+		}
+
+		var stats state.ScrubStats
+		scrub := func(st *state.State) error {
+			stats = st.Scrub(policy, now)
+			return nil
+		}
+		if dryRun {
+			st, err := store.Load()
+			if err == nil {
+				err = scrub(st)
+			}
+			if err != nil {
+				return fmt.Errorf("%s: %w", path, err)
+			}
+		} else if err := store.Update(scrub); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+
+		total += stats.Total()
+		if stats.Total() == 0 {
+			fmt.Printf("  %s: nothing to scrub\n", path)
+			continue
+		}
+		var parts []string
+		if stats.Attempts > 0 {
+			parts = append(parts, fmt.Sprintf("%d attempts", stats.Attempts))
+		}
+		if stats.SessionFailures > 0 {
+			parts = append(parts, fmt.Sprintf("%d session failures", stats.SessionFailures))
+		}
+		if stats.VerificationFailures > 0 {
+			parts = append(parts, fmt.Sprintf("%d verification failures", stats.VerificationFailures))
+		}
+		fmt.Printf("%s✓%s %s: %s\n", colorGreen, colorReset, path, strings.Join(parts, ", "))
+	}
+
+	if dryRun && total > 0 {
+		fmt.Printf("\nDry run: %d entries would be scrubbed.\n", total)
+	}
+	return nil
+}
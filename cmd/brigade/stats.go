@@ -0,0 +1,117 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"brigade/internal/config"
+	"brigade/internal/cost"
+	"brigade/internal/prd"
+	"brigade/internal/util"
+)
+
+var statsCmd = &cobra.Command{
+	Use:   "stats [tasks-dir]",
+	Short: "Show task duration and iteration percentiles from earlier runs",
+	Long: `Summarizes the completed tasks of every run in a tasks directory (default
+brigade/tasks): the p50 and p90 of worker time per task, per attempt, and
+attempts per task, by complexity and by the worker command that completed
+them.
+
+Once a complexity has enough completed tasks, its median task time replaces
+the built-in 5/15 minute guess in analyze's critical path. With
+TASK_TIMEOUT_FROM_HISTORY on, a service sets each tier's timeout to twice
+the p90 of its command's attempts; the suggested timeouts are shown here.
+
+Examples:
+  ./brigade-go stats
+  ./brigade-go stats --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load(cfgFile)
+		if err != nil {
+			return fmt.Errorf("loading config: %w", err)
+		}
+		dir := "brigade/tasks"
+		if len(args) == 1 {
+			dir = args[0]
+		}
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+		return cmdStats(dir, jsonOutput, cfg)
+	},
+}
+
+func init() {
+	statsCmd.Flags().Bool("json", false, "output as JSON")
+}
+
+// cmdStats prints the percentile breakdown of a tasks directory's runs.
+func cmdStats(dir string, jsonOutput bool, cfg *config.Config) error {
+	stats := cost.LoadStats(dir, "")
+	if jsonOutput {
+		data, _ := json.MarshalIndent(stats, "", "  ")
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(stats.Complexity) == 0 {
+		fmt.Printf("No completed tasks in %s yet.\n", dir)
+		return nil
+	}
+
+	fmt.Printf("%s=== Task Stats: %s ===%s\n\n", colorBold, dir, colorReset)
+	header := fmt.Sprintf("  %-28s %6s  %-15s %-15s %-11s", "", "tasks", "min/task", "min/attempt", "attempts")
+	fmt.Printf("%s%s%s\n", colorDim, header, colorReset)
+	fmt.Printf("  %s(p50 / p90)%s\n", colorDim, colorReset)
+
+	fmt.Printf("%sBy complexity:%s\n", colorBold, colorReset)
+	for _, c := range []prd.Complexity{prd.ComplexityJunior, prd.ComplexitySenior} {
+		if g := stats.Complexity[c]; g != nil {
+			printStatsRow(string(c), g)
+		}
+	}
+
+	fmt.Printf("\n%sBy worker command:%s\n", colorBold, colorReset)
+	commands := make([]string, 0, len(stats.Command))
+	for command := range stats.Command {
+		commands = append(commands, command)
+	}
+	sort.Strings(commands)
+	for _, command := range commands {
+		printStatsRow(command, stats.Command[command])
+	}
+
+	fmt.Printf("\n%sSuggested timeouts%s %s(2 × p90 attempt; TASK_TIMEOUT_FROM_HISTORY=%t):%s\n",
+		colorBold, colorReset, colorDim, cfg.TaskTimeoutFromHistory, colorReset)
+	for _, t := range []struct {
+		key        string
+		command    string
+		configured time.Duration
+	}{
+		{"TASK_TIMEOUT_JUNIOR", cfg.LineCmd, cfg.TaskTimeoutJunior},
+		{"TASK_TIMEOUT_SENIOR", cfg.SousCmd, cfg.TaskTimeoutSenior},
+		{"TASK_TIMEOUT_EXECUTIVE", cfg.ExecutiveCmd, cfg.TaskTimeoutExecutive},
+	} {
+		suggested := "not enough history"
+		if d := stats.SuggestedTimeout(t.command); d > 0 {
+			suggested = util.FormatDuration(d)
+		}
+		fmt.Printf("  %-24s %-20s configured %s\n", t.key, suggested, util.FormatDuration(t.configured))
+	}
+	return nil
+}
+
+// printStatsRow prints one group's percentiles.
+func printStatsRow(label string, g *cost.GroupStats) {
+	if len(label) > 28 {
+		label = label[:25] + "..."
+	}
+	fmt.Printf("  %-28s %6d  %-15s %-15s %-11s\n", label, g.Minutes.Count,
+		fmt.Sprintf("%.1f / %.1f", g.Minutes.P50, g.Minutes.P90),
+		fmt.Sprintf("%.1f / %.1f", g.AttemptMinutes.P50, g.AttemptMinutes.P90),
+		fmt.Sprintf("%.0f / %.0f", g.Iterations.P50, g.Iterations.P90))
+}
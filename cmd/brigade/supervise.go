@@ -1,22 +1,309 @@
 package main
 
 import (
+	"bufio"
+	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
+
+	"brigade/internal/config"
+	"brigade/internal/module"
+	"brigade/internal/prd"
+	"brigade/internal/supervisor"
 )
 
 var superviseCmd = &cobra.Command{
-	Use:   "supervise",
-	Short: "Print quick reference for supervisor mode",
-	Long:  `Shows how to monitor and intervene in Brigade's autonomous execution.`,
+	Use:   "supervise [prd.json]",
+	Short: "Interactive supervisor for a running service",
+	Long: `Tails service events, shows decision prompts as they arrive, and sends
+answers or control commands to a running service.
+
+Requires SUPERVISOR_EVENTS_FILE and SUPERVISOR_CMD_FILE in brigade.config.
+Pass the PRD when SUPERVISOR_PRD_SCOPED is enabled (the default).
+
+Commands:
+  retry [guidance]   Retry the pending decision's task
+  skip [reason]      Skip the pending decision's task
+  abort [reason]     Abort the service
+  pause / resume     Pause or resume between tasks
+  parallel <n>       Change max parallel workers
+  nudge [--now] <task> <message>
+                     Send guidance to a task's worker
+  status             Show the supervisor status file
+  help / quit`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return cmdSupervise()
+		reference, _ := cmd.Flags().GetBool("reference")
+		cfg, err := config.Load(cfgFile)
+		if err != nil {
+			return fmt.Errorf("loading config: %w", err)
+		}
+		if reference || cfg.SupervisorEventsFile == "" || cfg.SupervisorCmdFile == "" {
+			if !reference {
+				fmt.Printf("%s⚠%s Set SUPERVISOR_EVENTS_FILE and SUPERVISOR_CMD_FILE to use the interactive supervisor.\n\n", colorYellow, colorReset)
+			}
+			return cmdSuperviseReference()
+		}
+
+		prefix, prdPath := "", ""
+		if len(args) > 0 {
+			p, err := prd.Load(args[0])
+			if err != nil {
+				return err
+			}
+			prefix, prdPath = p.Prefix(), args[0]
+		}
+		return cmdSupervise(cmd.Context(), cfg, prefix, prdPath)
 	},
 }
 
-func cmdSupervise() error {
+func init() {
+	superviseCmd.Flags().Bool("reference", false, "print the supervisor quick reference and exit")
+}
+
+// pendingDecision is the most recent unanswered decision_needed event.
+type pendingDecision struct {
+	ID       string
+	TaskID   string
+	Question string
+}
+
+func cmdSupervise(ctx context.Context, cfg *config.Config, prefix, prdPath string) error {
+	sup := supervisor.NewSupervisor(
+		cfg.SupervisorStatusFile,
+		cfg.SupervisorEventsFile,
+		cfg.SupervisorCmdFile,
+		prefix,
+		cfg.SupervisorPRDScoped,
+		cfg.SupervisorCmdPollInterval,
+		cfg.SupervisorCmdTimeout,
+	)
+	eventsPath := sup.Events().Path()
+
+	fmt.Printf("%sBrigade Supervisor%s\n", colorBold, colorReset)
+	fmt.Printf("%sEvents: %s | Commands: %s%s\n", colorDim, eventsPath, sup.Commands().Path(), colorReset)
+	fmt.Printf("%sType 'help' for commands.%s\n\n", colorDim, colorReset)
+
+	events := make(chan *module.Event)
+	go tailEvents(ctx, eventsPath, events)
+
+	lines := make(chan string)
+	go func() {
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+		close(lines)
+	}()
+
+	var pending *pendingDecision
+	fmt.Print("> ")
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event := <-events:
+			fmt.Print("\r")
+			printEvent(event)
+			switch event.Type {
+			case module.EventDecisionNeeded:
+				pending = &pendingDecision{
+					ID:       fmt.Sprint(event.Data["decisionId"]),
+					TaskID:   event.TaskID,
+					Question: fmt.Sprint(event.Data["question"]),
+				}
+				fmt.Printf("\n%s%s DECISION NEEDED%s %s\n", colorBold, colorYellow, colorReset, pending.Question)
+				fmt.Println("  Answer with: retry [guidance] | skip [reason] | abort [reason]")
+			case module.EventDecisionReceived:
+				if pending != nil && fmt.Sprint(event.Data["decisionId"]) == pending.ID {
+					pending = nil
+				}
+			}
+			fmt.Print("> ")
+		case line, ok := <-lines:
+			if !ok {
+				return nil
+			}
+			quit, err := handleSuperviseInput(sup, prdPath, strings.TrimSpace(line), &pending)
+			if err != nil {
+				fmt.Printf("%s✗%s %v\n", colorRed, colorReset, err)
+			}
+			if quit {
+				return nil
+			}
+			fmt.Print("> ")
+		}
+	}
+}
+
+// handleSuperviseInput runs one REPL command. Returns true to quit.
+func handleSuperviseInput(sup *supervisor.Supervisor, prdPath, line string, pending **pendingDecision) (bool, error) {
+	if line == "" {
+		return false, nil
+	}
+	verb, rest, _ := strings.Cut(line, " ")
+	rest = strings.TrimSpace(rest)
+
+	send := func(cmd *supervisor.Command) error {
+		if sup.Commands().HasCommand() {
+			fmt.Printf("%s⚠%s Previous command not yet picked up; replacing it\n", colorYellow, colorReset)
+		}
+		if err := sup.Commands().Send(cmd); err != nil {
+			return err
+		}
+		fmt.Printf("%s✓%s Sent %s\n", colorGreen, colorReset, cmd.Action)
+		return nil
+	}
+
+	switch verb {
+	case "retry", "skip":
+		if *pending == nil {
+			return false, fmt.Errorf("no decision pending")
+		}
+		cmd := &supervisor.Command{Decision: (*pending).ID, Action: supervisor.Action(verb)}
+		if verb == "retry" {
+			cmd.Guidance = rest
+		} else {
+			cmd.Reason = rest
+		}
+		return false, send(cmd)
+	case "abort":
+		cmd := &supervisor.Command{Action: supervisor.ActionAbort, Reason: rest}
+		if *pending != nil {
+			cmd.Decision = (*pending).ID
+		}
+		return false, send(cmd)
+	case "pause":
+		return false, send(&supervisor.Command{Action: supervisor.ActionPause, Reason: rest})
+	case "resume":
+		return false, send(&supervisor.Command{Action: supervisor.ActionResume})
+	case "parallel":
+		n, err := strconv.Atoi(rest)
+		if err != nil || n < 0 {
+			return false, fmt.Errorf("usage: parallel <n>")
+		}
+		return false, send(&supervisor.Command{Action: supervisor.ActionSetParallel, Value: n})
+	case "nudge":
+		now := strings.HasPrefix(rest, "--now ")
+		rest = strings.TrimSpace(strings.TrimPrefix(rest, "--now "))
+		taskID, message, _ := strings.Cut(rest, " ")
+		if strings.TrimSpace(message) == "" {
+			return false, fmt.Errorf("usage: nudge [--now] <task> <message>")
+		}
+		p, taskID, err := resolveTaskPRD(prdPath, taskID)
+		if err != nil {
+			return false, err
+		}
+		return false, sendNudge(p, taskID, strings.TrimSpace(message), now)
+	case "status":
+		status, err := sup.Status().Read()
+		if err != nil {
+			return false, err
+		}
+		if status == nil {
+			fmt.Println("No status available (is SUPERVISOR_STATUS_FILE set and the service running?)")
+			return false, nil
+		}
+		data, _ := json.Marshal(status)
+		fmt.Println(string(data))
+		if *pending != nil {
+			fmt.Printf("Pending decision for %s: %s\n", (*pending).TaskID, (*pending).Question)
+		}
+	case "help", "?":
+		fmt.Println("retry [guidance] | skip [reason] | abort [reason] | pause | resume | parallel <n> | nudge [--now] <task> <message> | status | quit")
+	case "quit", "exit", "q":
+		return true, nil
+	default:
+		return false, fmt.Errorf("unknown command %q (type 'help')", verb)
+	}
+	return false, nil
+}
+
+// tailEvents follows the events file from its current end, sending each
+// new event on ch. The file may not exist yet.
+func tailEvents(ctx context.Context, path string, ch chan<- *module.Event) {
+	var offset int64
+	if info, err := os.Stat(path); err == nil {
+		offset = info.Size()
+	}
+
+	var partial string
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(500 * time.Millisecond):
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			continue
+		}
+		if info, err := f.Stat(); err == nil && info.Size() < offset {
+			offset = 0 // File was truncated or replaced
+		}
+		f.Seek(offset, io.SeekStart)
+		data, _ := io.ReadAll(f)
+		f.Close()
+		offset += int64(len(data))
+
+		chunk := partial + string(data)
+		lines := strings.Split(chunk, "\n")
+		partial = lines[len(lines)-1]
+		for _, line := range lines[:len(lines)-1] {
+			var event module.Event
+			if err := json.Unmarshal([]byte(line), &event); err != nil {
+				continue
+			}
+			select {
+			case ch <- &event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+// printEvent renders a single event line.
+func printEvent(e *module.Event) {
+	ts := e.Timestamp
+	if t, err := time.Parse(time.RFC3339, e.Timestamp); err == nil {
+		ts = t.Local().Format("15:04:05")
+	}
+
+	color := colorDim
+	switch e.Type {
+	case module.EventTaskComplete, module.EventServiceComplete:
+		color = colorGreen
+	case module.EventTaskBlocked, module.EventAttention, module.EventDecisionNeeded:
+		color = colorYellow
+	case module.EventEscalation:
+		color = colorCyan
+	}
+
+	line := fmt.Sprintf("%s %s%-18s%s", ts, color, e.Type, colorReset)
+	if e.TaskID != "" {
+		line += " " + e.TaskID
+	}
+	if e.Worker != "" {
+		line += " [" + e.Worker + "]"
+	}
+	for _, key := range []string{"attempt", "status", "reason", "result", "action", "from", "to"} {
+		if v, ok := e.Data[key]; ok && v != "" {
+			line += fmt.Sprintf(" %s=%v", key, v)
+		}
+	}
+	fmt.Println(line)
+}
+
+func cmdSuperviseReference() error {
 	fmt.Printf("%s Supervisor Mode%s\n\n", colorBold, colorReset)
 
 	// Check for supervisor docs
@@ -40,6 +327,9 @@ func cmdSupervise() error {
 	fmt.Println("    abort  - Stop everything")
 	fmt.Println("    pause  - Stop and wait for investigation")
 	fmt.Println()
+	fmt.Println("  Control commands (no decision ID) steer a running service between tasks:")
+	fmt.Println(`    {"action":"pause"}  {"action":"resume"}  {"action":"set_parallel","value":2}`)
+	fmt.Println()
 	fmt.Println("  Example:")
 	fmt.Println(`    {"decision":"d-123","action":"retry","guidance":"Check the OpenAPI spec"}`)
 	fmt.Println()
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"bufio"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -10,6 +11,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"brigade/internal/prd"
+	tmpl "brigade/internal/template"
 	"brigade/internal/util"
 )
 
@@ -20,7 +22,12 @@ var templateCmd = &cobra.Command{
 
 Without arguments, lists available templates.
 With a template name, generates a PRD from that template.
-Some templates require a resource name (e.g., "users" for an API template).`,
+Some templates require a resource name (e.g., "users" for an API template).
+
+Templates may declare variables in a manifest next to the template
+(auth.json -> auth.manifest.json) with descriptions, defaults, and
+validation patterns. Set them with --var key=value; missing variables
+are prompted for interactively.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) == 0 {
 			return listTemplates()
@@ -32,10 +39,99 @@ Some templates require a resource name (e.g., "users" for an API template).`,
 			resourceName = args[1]
 		}
 
-		return runTemplate(templateName, resourceName)
+		varFlags, _ := cmd.Flags().GetStringArray("var")
+		vars := make(map[string]string)
+		for _, kv := range varFlags {
+			key, value, ok := strings.Cut(kv, "=")
+			if !ok || key == "" {
+				return fmt.Errorf("invalid --var %q, expected key=value", kv)
+			}
+			vars[key] = value
+		}
+
+		return runTemplate(templateName, resourceName, vars)
+	},
+}
+
+var templateCreateCmd = &cobra.Command{
+	Use:   "create <name> --from <prd.json>",
+	Short: "Create a reusable template from an existing PRD",
+	Long: `Generalizes a PRD (typically a completed one) into a project template.
+
+Occurrences of the resource name given with --resource are replaced by
+{{name}} placeholders (plural, singular, capitalized, and uppercase forms).
+Task completion and creation dates are stripped. The template is written to
+brigade/templates/<name>.json.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		from, _ := cmd.Flags().GetString("from")
+		resource, _ := cmd.Flags().GetString("resource")
+		if from == "" {
+			return fmt.Errorf("--from <prd.json> is required")
+		}
+		return createTemplate(args[0], from, resource)
 	},
 }
 
+func init() {
+	templateCmd.Flags().StringArray("var", nil, "set a template variable (key=value, repeatable)")
+	templateCreateCmd.Flags().String("from", "", "PRD to generalize")
+	templateCreateCmd.Flags().String("resource", "", "resource name to replace with {{name}} (e.g. users)")
+	templateCmd.AddCommand(templateCreateCmd)
+}
+
+func createTemplate(name, fromPath, resource string) error {
+	p, err := prd.Load(fromPath)
+	if err != nil {
+		return err
+	}
+
+	// Strip execution state
+	p.CreatedAt = ""
+	for i := range p.Tasks {
+		p.Tasks[i].Passes = false
+	}
+
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling template: %w", err)
+	}
+	content := tmpl.Generalize(string(data), resource)
+
+	outputPath := filepath.Join("brigade/templates", util.Slugify(name, 50)+".json")
+	if _, err := os.Stat(outputPath); err == nil {
+		fmt.Printf("%sWarning: %s already exists%s\n", colorYellow, outputPath, colorReset)
+		if !confirmPrompt("Overwrite? (y/N) ", false) {
+			fmt.Printf("%sAborted.%s\n", colorDim, colorReset)
+			return nil
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(outputPath, []byte(content+"\n"), 0644); err != nil {
+		return err
+	}
+
+	placeholders := strings.Count(content, "{{")
+	fmt.Printf("%s✓%s Created template %s%s%s from %s\n", colorGreen, colorReset, colorBold, outputPath, colorReset, fromPath)
+	fmt.Printf("  Tasks: %d | Placeholders: %d\n", len(p.Tasks), placeholders)
+	if resource == "" {
+		fmt.Printf("  %sNo --resource given; the template has no {{name}} placeholders.%s\n", colorDim, colorReset)
+	} else if placeholders == 0 {
+		fmt.Printf("  %s⚠%s %q was not found in the PRD\n", colorYellow, colorReset, resource)
+	}
+	fmt.Println()
+	fmt.Printf("%sNext steps:%s\n", colorDim, colorReset)
+	fmt.Printf("  Review:  %scat %s%s\n", colorCyan, outputPath, colorReset)
+	if resource != "" {
+		fmt.Printf("  Use:     %s./brigade-go template %s <resource>%s\n", colorCyan, strings.TrimSuffix(filepath.Base(outputPath), ".json"), colorReset)
+	} else {
+		fmt.Printf("  Use:     %s./brigade-go template %s%s\n", colorCyan, strings.TrimSuffix(filepath.Base(outputPath), ".json"), colorReset)
+	}
+	return nil
+}
+
 func listTemplates() error {
 	fmt.Printf("%sAvailable Templates%s\n\n", colorBold, colorReset)
 
@@ -46,7 +142,7 @@ func listTemplates() error {
 	if entries, err := os.ReadDir(projectDir); err == nil && len(entries) > 0 {
 		fmt.Printf("%sProject templates (brigade/templates/):%s\n", colorCyan, colorReset)
 		for _, entry := range entries {
-			if strings.HasSuffix(entry.Name(), ".json") {
+			if strings.HasSuffix(entry.Name(), ".json") && !tmpl.IsManifest(entry.Name()) {
 				name := strings.TrimSuffix(entry.Name(), ".json")
 				templatePath := filepath.Join(projectDir, entry.Name())
 				desc := getTemplateDescription(templatePath)
@@ -67,7 +163,7 @@ func listTemplates() error {
 		if entries, err := os.ReadDir(builtinDir); err == nil && len(entries) > 0 {
 			fmt.Printf("%sBuilt-in templates:%s\n", colorCyan, colorReset)
 			for _, entry := range entries {
-				if strings.HasSuffix(entry.Name(), ".json") {
+				if strings.HasSuffix(entry.Name(), ".json") && !tmpl.IsManifest(entry.Name()) {
 					name := strings.TrimSuffix(entry.Name(), ".json")
 					// Skip if overridden by project template
 					if _, err := os.Stat(filepath.Join(projectDir, entry.Name())); err == nil {
@@ -92,11 +188,11 @@ func listTemplates() error {
 		fmt.Printf("Create templates in %sbrigade/templates/%s\n", colorCyan, colorReset)
 	}
 
-	fmt.Printf("%sUsage: ./brigade.sh template <name> [resource_name]%s\n", colorDim, colorReset)
+	fmt.Printf("%sUsage: ./brigade.sh template <name> [resource_name] [--var key=value]%s\n", colorDim, colorReset)
 	return nil
 }
 
-func runTemplate(templateName, resourceName string) error {
+func runTemplate(templateName, resourceName string, vars map[string]string) error {
 	// Find template file
 	templateFile := findTemplate(templateName)
 	if templateFile == "" {
@@ -137,7 +233,10 @@ func runTemplate(templateName, resourceName string) error {
 	}
 
 	// Read and interpolate template
-	content, err := interpolateTemplate(templateFile, resourceName)
+	if resourceName != "" {
+		vars["name"] = resourceName
+	}
+	content, err := interpolateTemplate(templateFile, vars)
 	if err != nil {
 		return err
 	}
@@ -216,6 +315,10 @@ func templateRequiresResource(templatePath string) bool {
 }
 
 func getTemplateDescription(templatePath string) string {
+	if manifest, err := tmpl.LoadManifest(templatePath); err == nil && manifest != nil && manifest.Description != "" {
+		return manifest.Description
+	}
+
 	content, err := os.ReadFile(templatePath)
 	if err != nil {
 		return "No description"
@@ -235,26 +338,104 @@ func getTemplateDescription(templatePath string) string {
 	return "No description"
 }
 
-func interpolateTemplate(templatePath, resource string) ([]byte, error) {
+// interpolateTemplate fills in template variables. Values come from vars,
+// then manifest defaults (confirmed interactively on a terminal), then a prompt.
+func interpolateTemplate(templatePath string, vars map[string]string) ([]byte, error) {
 	content, err := os.ReadFile(templatePath)
 	if err != nil {
 		return nil, err
 	}
 
-	if resource == "" {
-		return content, nil
+	manifest, err := tmpl.LoadManifest(templatePath)
+	if err != nil {
+		return nil, fmt.Errorf("loading template manifest: %w", err)
 	}
 
-	result := string(content)
+	// Variables: everything declared in the manifest plus anything referenced
+	names := tmpl.Placeholders(string(content))
+	if manifest != nil {
+		for _, v := range manifest.Variables {
+			// A placeholder seen only in uppercase, {{URL}}, is the
+			// manifest's variable whatever its case
+			found := false
+			for i := range names {
+				if strings.EqualFold(names[i], v.Name) {
+					names[i], found = v.Name, true
+				}
+			}
+			if !found {
+				names = append(names, v.Name)
+			}
+		}
+	}
+
+	interactive := util.IsTerminal(os.Stdin)
+	reader := bufio.NewReader(os.Stdin)
+
+	for _, name := range names {
+		v := manifest.Variable(name)
+		if v == nil {
+			v = &tmpl.Variable{Name: name}
+		}
+
+		value, ok := vars[name]
+		for !ok {
+			if !interactive {
+				if v.Default == "" {
+					return nil, fmt.Errorf("missing template variable %q (use --var %s=value)", name, name)
+				}
+				value, ok = v.Default, true
+				break
+			}
+
+			prompt := name
+			if v.Description != "" {
+				prompt += " - " + v.Description
+			}
+			if v.Default != "" {
+				prompt += fmt.Sprintf(" [%s]", v.Default)
+			}
+			fmt.Printf("%s%s%s: ", colorCyan, prompt, colorReset)
+			line, err := reader.ReadString('\n')
+			value = strings.TrimSpace(line)
+			if value == "" {
+				value = v.Default
+			}
+			if err != nil && value == "" {
+				return nil, fmt.Errorf("missing template variable %q (use --var %s=value)", name, name)
+			}
+			if value == "" {
+				fmt.Printf("  %sA value is required.%s\n", colorYellow, colorReset)
+				continue
+			}
+			if err := v.Validate(value); err != nil {
+				fmt.Printf("  %s%v%s\n", colorYellow, err, colorReset)
+				continue
+			}
+			ok = true
+		}
+
+		if err := v.Validate(value); err != nil {
+			return nil, err
+		}
+		vars[name] = value
+	}
 
-	// Replace all placeholder variants
-	result = strings.ReplaceAll(result, "{{name}}", resource)
-	result = strings.ReplaceAll(result, "{{Name}}", util.ToCapitalized(resource))
-	result = strings.ReplaceAll(result, "{{NAME}}", strings.ToUpper(resource))
+	// Escape values for embedding inside JSON strings
+	escaped := make(map[string]string, len(vars))
+	for name, value := range vars {
+		data, _ := json.Marshal(value)
+		escaped[name] = string(data[1 : len(data)-1])
+	}
 
-	singular := util.ToSingular(resource)
-	result = strings.ReplaceAll(result, "{{name_singular}}", singular)
-	result = strings.ReplaceAll(result, "{{Name_singular}}", util.ToCapitalized(singular))
+	return []byte(tmpl.Interpolate(string(content), escaped)), nil
+}
 
-	return []byte(result), nil
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
 }
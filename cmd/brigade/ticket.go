@@ -0,0 +1,190 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"brigade/internal/config"
+	"brigade/internal/orchestrator"
+	"brigade/internal/prd"
+	"brigade/internal/util"
+)
+
+// adhocTaskID is the task of a PRD written by `ticket --adhoc`.
+const adhocTaskID = "ADHOC-001"
+
+// cmdTicketAdhoc writes a one-task PRD for description, runs it, then
+// removes it with its state unless it's kept.
+func cmdTicketAdhoc(ctx context.Context, cfg *config.Config, description string, keep bool, review func(orchestrator.AttemptReport) orchestrator.AttemptChoice) error {
+	p := adhocPRD(description, prd.DetectProjectStack("."))
+	path := filepath.Join("brigade", "tasks", fmt.Sprintf("prd-adhoc-%d.json", time.Now().Unix()))
+	if dryRun {
+		fmt.Printf("Dry run: would write %s and run %s: %s\n", path, adhocTaskID, description)
+		for _, v := range p.Tasks[0].Verification {
+			fmt.Printf("  verify [%s] %s\n", v.Type, v.Cmd)
+		}
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	if err := p.Save(path); err != nil {
+		return err
+	}
+	fmt.Printf("%s✓%s Wrote %s\n", colorGreen, colorReset, path)
+	for _, v := range p.Tasks[0].Verification {
+		fmt.Printf("  %sverify [%s] %s%s\n", colorDim, v.Type, v.Cmd, colorReset)
+	}
+	fmt.Println()
+
+	orch, err := orchestrator.New(orchestrator.Options{
+		Config:        cfg,
+		PRDPath:       path,
+		Logger:        slog.New(slog.NewTextHandler(os.Stderr, nil)),
+		OnlyTasks:     []string{adhocTaskID},
+		ReviewAttempt: review,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = orch.Run(ctx)
+	discarded := errors.Is(err, orchestrator.ErrAttemptDiscarded)
+	if err != nil && !discarded {
+		fmt.Printf("\n%sThe task didn't finish. Kept %s; resume with:%s\n", colorYellow, path, colorReset)
+		fmt.Printf("  ./brigade-go resume %s\n", path)
+		return err
+	}
+	if discarded {
+		fmt.Printf("%sDiscarded the attempt's changes.%s\n", colorDim, colorReset)
+	}
+
+	if keep || (util.IsTerminal(os.Stdin) && confirmPrompt(fmt.Sprintf("Keep %s? (y/N) ", path), false)) {
+		fmt.Printf("%sKept %s%s\n", colorDim, path, colorReset)
+		return nil
+	}
+	removeAdhocPRD(cfg, p)
+	fmt.Printf("%s✓%s Removed %s and its state\n", colorGreen, colorReset, path)
+	return nil
+}
+
+// adhocPRD builds the one-task PRD `ticket --adhoc` runs, verified the way
+// stack usually is.
+func adhocPRD(description, stack string) *prd.PRD {
+	task := prd.Task{
+		ID:                 adhocTaskID,
+		Title:              description,
+		AcceptanceCriteria: []string{"Done as described"},
+		DependsOn:          []string{},
+		Complexity:         prd.ComplexityAuto,
+	}
+	for _, v := range prd.SuggestVerification(&task, stack) {
+		if !strings.HasPrefix(v.Cmd, "#") { // Placeholders need a human to fill in
+			task.Verification = append(task.Verification, v)
+		}
+	}
+	// Tasks about tests get no suggestion, but the suite is what shows
+	// they're done
+	if testCmd, _ := prd.StackCommands(".", stack); len(task.Verification) == 0 && testCmd != "" {
+		task.Verification = []prd.Verification{{Type: prd.VerificationUnit, Cmd: testCmd}}
+	}
+
+	return &prd.PRD{
+		FeatureName: "Ad hoc: " + description,
+		CreatedAt:   time.Now().Format(time.RFC3339),
+		Description: description,
+		Tasks:       []prd.Task{task},
+	}
+}
+
+// removeAdhocPRD removes an ad-hoc PRD with its state, history and
+// supervisor files.
+func removeAdhocPRD(cfg *config.Config, p *prd.PRD) {
+	targets := prdCleanTargets(p.Path(), cleanKinds{locks: true, state: true}, cfg)
+	targets = append(targets,
+		cleanTarget{p.Path(), "PRD"},
+		cleanTarget{p.HistoryDir(), "PRD history"},
+		cleanTarget{p.HandoffPath(), "handoff"},
+	)
+	for _, t := range targets {
+		if err := os.RemoveAll(t.path); err != nil {
+			fmt.Printf("%s✗%s %s: %v\n", colorRed, colorReset, t.path, err)
+		}
+	}
+}
+
+// reviewAttempt shows an attempt of `ticket --interactive` and asks what
+// to do with it.
+func reviewAttempt(r orchestrator.AttemptReport) orchestrator.AttemptChoice {
+	fmt.Printf("\n%s━━━ %s attempt %d (%s) ━━━%s %s\n", colorBold, r.TaskID, r.Attempt, r.Worker, colorReset, r.Title)
+	if r.Passed {
+		fmt.Printf("%s✓ Done, and its checks passed%s\n", colorGreen, colorReset)
+	} else {
+		fmt.Printf("%s✗ Not done: %s%s\n", colorRed, r.Reason, colorReset)
+	}
+
+	if r.Verification != nil {
+		fmt.Printf("\n%sVerification:%s\n", colorBold, colorReset)
+		for _, c := range r.Verification.Results {
+			if c.Passed {
+				fmt.Printf("  %s✓%s %s\n", colorGreen, colorReset, c.Command)
+				continue
+			}
+			fmt.Printf("  %s✗%s %s (exit %d)\n", colorRed, colorReset, c.Command, c.ExitCode)
+			output := strings.TrimSpace(c.Output)
+			if lines := strings.Split(output, "\n"); len(lines) > 10 {
+				output = strings.Join(lines[len(lines)-10:], "\n")
+			}
+			for _, line := range strings.Split(output, "\n") {
+				if line != "" {
+					fmt.Printf("      %s%s%s\n", colorDim, line, colorReset)
+				}
+			}
+		}
+	}
+
+	fmt.Printf("\n%sChanges:%s\n", colorBold, colorReset)
+	if r.DiffStat != "" {
+		fmt.Println(r.DiffStat)
+	} else {
+		fmt.Printf("  %s(none)%s\n", colorDim, colorReset)
+	}
+
+	def := "r"
+	if r.Passed {
+		def = "a"
+	}
+	for {
+		switch strings.ToLower(readLine(fmt.Sprintf("\n[a]ccept, [r]etry, [e]scalate, [d]iscard, or [v]iew the diff? (%s) ", def))) {
+		case "":
+			if def == "a" {
+				return orchestrator.AttemptChoice{Action: orchestrator.AttemptAccept}
+			}
+			fallthrough
+		case "r", "retry":
+			guidance := readLine("Guidance for the next attempt (optional): ")
+			return orchestrator.AttemptChoice{Action: orchestrator.AttemptRetry, Guidance: guidance}
+		case "a", "accept":
+			return orchestrator.AttemptChoice{Action: orchestrator.AttemptAccept}
+		case "e", "escalate":
+			guidance := readLine("Guidance for the next tier (optional): ")
+			return orchestrator.AttemptChoice{Action: orchestrator.AttemptEscalate, Guidance: guidance}
+		case "d", "discard":
+			if confirmPrompt("Undo this attempt's changes and stop? (y/N) ", false) {
+				return orchestrator.AttemptChoice{Action: orchestrator.AttemptDiscard}
+			}
+		case "v", "view":
+			if r.Diff == "" {
+				fmt.Printf("%s(no changes)%s\n", colorDim, colorReset)
+			} else {
+				fmt.Println(r.Diff)
+			}
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"brigade/internal/classify"
+	"brigade/internal/config"
+	"brigade/internal/orchestrator"
+	"brigade/internal/prd"
+	"brigade/internal/state"
+	"brigade/internal/triage"
+	"brigade/internal/verify"
+	"brigade/internal/worker"
+)
+
+var triageCmd = &cobra.Command{
+	Use:   "triage",
+	Short: "Generate a PRD from the current state of the codebase",
+	Long: `Inspects the codebase and drafts a PRD of fix-up work.
+
+With --test-cmd, the test suite is run and its failures are grouped by
+package and error category, one task per group. Each task carries the
+failing tests and their error output, and is verified by re-running them.
+
+With --todos, the repo is scanned for TODO/FIXME/HACK/XXX markers, grouped
+by directory into cleanup tasks whose criteria name each marker. Add
+--prioritize to have the Executive Chef order the tasks by value.
+
+Examples:
+  ./brigade-go triage --test-cmd "go test ./..."
+  ./brigade-go triage --test-cmd "pytest -q" --output brigade/tasks/prd-fix-tests.json
+  ./brigade-go triage --todos --prioritize`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load(cfgFile)
+		if err != nil {
+			return fmt.Errorf("loading config: %w", err)
+		}
+		testCmd, _ := cmd.Flags().GetString("test-cmd")
+		todos, _ := cmd.Flags().GetBool("todos")
+		prioritize, _ := cmd.Flags().GetBool("prioritize")
+		output, _ := cmd.Flags().GetString("output")
+
+		switch {
+		case testCmd != "" && todos:
+			return fmt.Errorf("use either --test-cmd or --todos, not both")
+		case testCmd != "":
+			if output == "" {
+				output = "brigade/tasks/prd-fix-failing-tests.json"
+			}
+			return cmdTriageTests(cmd.Context(), testCmd, output, cfg)
+		case todos:
+			if output == "" {
+				output = "brigade/tasks/prd-resolve-todos.json"
+			}
+			return cmdTriageTodos(cmd.Context(), prioritize, output, cfg)
+		default:
+			return fmt.Errorf("nothing to triage: pass --test-cmd or --todos")
+		}
+	},
+}
+
+func init() {
+	triageCmd.Flags().String("test-cmd", "", "test command to run, e.g. \"go test ./...\"")
+	triageCmd.Flags().Bool("todos", false, "draft cleanup tasks from TODO/FIXME markers")
+	triageCmd.Flags().Bool("prioritize", false, "with --todos, have the Executive Chef order tasks by value")
+	triageCmd.Flags().String("output", "", "PRD path to write (default brigade/tasks/prd-fix-failing-tests.json or prd-resolve-todos.json)")
+}
+
+// cmdTriageTests runs the test suite and writes a PRD for its failures.
+func cmdTriageTests(ctx context.Context, testCmd, output string, cfg *config.Config) error {
+	fmt.Printf("%sRunning:%s %s\n", colorBold, colorReset, testCmd)
+
+	// Whole suites take longer than a single verification command
+	timeout := cfg.TestTimeout
+	if timeout < cfg.VerificationTimeout {
+		timeout = cfg.VerificationTimeout
+	}
+	runner := verify.NewRunner(timeout*5, "")
+	result, err := runner.RunTestCmd(ctx, testCmd)
+	if err != nil {
+		return err
+	}
+	if result.Passed {
+		fmt.Printf("%s✓ All tests pass%s - nothing to triage\n", colorGreen, colorReset)
+		return nil
+	}
+
+	classifier := classify.NewClassifier()
+	if cfg.SmartRetryCustomPatterns != "" {
+		classifier.AddPatternsFromString(cfg.SmartRetryCustomPatterns)
+	}
+	failures := triage.ParseFailures(result.Output)
+	if len(failures) == 0 {
+		return fmt.Errorf("test command failed (%s) with no output to triage", result.Error)
+	}
+	clusters := triage.ClusterFailures(failures, classifier)
+
+	p := triage.TestFailurePRD(testCmd, clusters)
+	fmt.Printf("\n%d failures in %d clusters:\n", len(failures), len(clusters))
+	printTriageTasks(p)
+
+	return writeDraftPRD(p, output, false)
+}
+
+// cmdTriageTodos scans for TODO/FIXME markers and writes a cleanup PRD.
+func cmdTriageTodos(ctx context.Context, prioritize bool, output string, cfg *config.Config) error {
+	fmt.Printf("%sScanning for TODO/FIXME markers...%s\n", colorDim, colorReset)
+
+	scan, err := verify.NewTodoScanner().ScanDirectory(".")
+	if err != nil {
+		return fmt.Errorf("scanning: %w", err)
+	}
+	var markers []verify.TodoMarker
+	for _, m := range scan.Markers {
+		if !triage.SkipMarker(m) {
+			markers = append(markers, m)
+		}
+	}
+	if len(markers) == 0 {
+		fmt.Printf("%s✓ No markers found%s in %d files - nothing to triage\n", colorGreen, colorReset, scan.Scanned)
+		return nil
+	}
+
+	clusters := triage.ClusterMarkers(markers)
+	p := triage.TodoPRD(clusters)
+
+	if prioritize {
+		fmt.Printf("%sAsking Executive Chef to prioritize %d tasks...%s\n", colorDim, len(p.Tasks), colorReset)
+		env, err := orchestrator.WorkerEnv(cfg, state.TierExecutive)
+		if err != nil {
+			return err
+		}
+		exec := worker.NewCLIWorker(&worker.Config{
+			Command: cfg.ExecutiveCmd,
+			Tier:    state.TierExecutive,
+			Timeout: cfg.TaskTimeoutExecutive,
+			Quiet:   true,
+			Env:     env,
+		})
+		result, err := exec.Execute(ctx, triage.PriorityPrompt(p))
+		switch {
+		case err != nil:
+			fmt.Printf("%sWarning: prioritization failed (%v); keeping scan order%s\n", colorYellow, err, colorReset)
+		case !triage.ApplyPriority(p, result.Output):
+			fmt.Printf("%sWarning: no <priority> ordering in response; keeping scan order%s\n", colorYellow, colorReset)
+		}
+	}
+
+	fmt.Printf("\n%d markers in %d files, %d tasks:\n", len(markers), countFiles(markers), len(p.Tasks))
+	printTriageTasks(p)
+
+	return writeDraftPRD(p, output, false)
+}
+
+// countFiles returns the number of distinct files holding markers.
+func countFiles(markers []verify.TodoMarker) int {
+	files := make(map[string]bool)
+	for _, m := range markers {
+		files[m.File] = true
+	}
+	return len(files)
+}
+
+// printTriageTasks lists the tasks of a generated PRD.
+func printTriageTasks(p *prd.PRD) {
+	for _, task := range p.Tasks {
+		fmt.Printf("  %s%s%s %s %s[%s]%s\n", colorCyan, task.ID, colorReset, task.Title, colorDim, task.Complexity, colorReset)
+	}
+}
+
+// writeDraftPRD saves a generated PRD, confirming before overwriting
+// unless force is set.
+func writeDraftPRD(p *prd.PRD, output string, force bool) error {
+	if _, err := os.Stat(output); err == nil && !force {
+		fmt.Printf("%sWarning: %s already exists%s\n", colorYellow, output, colorReset)
+		if !confirmPrompt("Overwrite? (y/N) ", false) {
+			fmt.Printf("%sAborted.%s\n", colorDim, colorReset)
+			return nil
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(output), 0755); err != nil {
+		return err
+	}
+	p.CreatedAt = time.Now().Format(time.RFC3339)
+	if err := p.Save(output); err != nil {
+		return err
+	}
+
+	fmt.Printf("\n%s✓ PRD written:%s %s\n", colorGreen, colorReset, output)
+	fmt.Printf("Review it, then run: %s./brigade-go service %s%s\n", colorCyan, output, colorReset)
+	return nil
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"brigade/internal/config"
+	"brigade/internal/policy"
+	"brigade/internal/state"
+)
+
+var unlockCmd = &cobra.Command{
+	Use:   "unlock <prd.json>",
+	Short: "Remove a stale service lock",
+	Long: `Removes the service lock for a PRD left behind by a crashed or killed service.
+
+Stale locks (holder process gone or heartbeat expired) are removed directly.
+If the holder still looks alive, unlock refuses unless --force is given,
+and asks for confirmation before removing it.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.Load(cfgFile)
+		if err != nil {
+			return fmt.Errorf("loading config: %w", err)
+		}
+		return cmdUnlock(args[0], cfg)
+	},
+}
+
+func cmdUnlock(prdPath string, cfg *config.Config) error {
+	lock := state.NewServiceLock(prdPath, state.WithHeartbeatInterval(cfg.LockHeartbeatInterval))
+
+	if !lock.Exists() {
+		fmt.Printf("%s✓%s No service lock held for %s\n", colorGreen, colorReset, prdPath)
+		return nil
+	}
+
+	holder, err := lock.Holder()
+	if err != nil {
+		fmt.Printf("%s⚠%s Lock info unreadable (%v)\n", colorYellow, colorReset, err)
+	} else {
+		fmt.Printf("Lock holder: %s\n", holder)
+	}
+
+	if !lock.IsStale() {
+		if !forceFlag {
+			fmt.Printf("\n%s✗%s The holder still appears to be running.\n", colorRed, colorReset)
+			fmt.Println("  Stop the running service first, or re-run with --force to remove the lock anyway.")
+			return fmt.Errorf("lock is active")
+		}
+		if !confirmPrompt("Holder appears alive. Remove the lock anyway? (y/N) ", false) {
+			fmt.Println("Aborted.")
+			return nil
+		}
+		detail := "unlock --force"
+		if holder != nil {
+			detail = fmt.Sprintf("unlock --force on %s", holder)
+		}
+		op, err := policy.FromConfig(cfg).Approve(policy.ForceUnlock, prdPath, detail)
+		if err != nil {
+			return err
+		}
+		if op.Name != "" {
+			fmt.Printf("Approved by %s (%s), recorded in %s\n", op.Name, op.Source, cfg.AuditLog)
+		}
+	}
+
+	if err := lock.Lock.Release(); err != nil {
+		return fmt.Errorf("removing lock: %w", err)
+	}
+	fmt.Printf("%s✓%s Removed lock %s\n", colorGreen, colorReset, lock.Path())
+	return nil
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// screen redraws a view in place on a terminal. Each frame rewrites only
+// the lines that changed since the last one, so the view doesn't flicker
+// and whatever was printed above it stays in the scrollback.
+type screen struct {
+	out   io.Writer
+	lines []string // The frame on screen
+	drawn bool
+}
+
+// draw shows frame, redrawing over the previous one.
+func (s *screen) draw(frame string) {
+	lines := strings.Split(strings.TrimSuffix(frame, "\n"), "\n")
+	var sb strings.Builder
+	if s.drawn && len(s.lines) > 0 {
+		// Back to the top of the previous frame
+		sb.WriteString(fmt.Sprintf("\033[%dA", len(s.lines)))
+	}
+	for i, line := range lines {
+		if s.drawn && i < len(s.lines) && s.lines[i] == line {
+			sb.WriteString("\n")
+			continue
+		}
+		sb.WriteString("\r" + line + "\033[K\n")
+	}
+	if len(lines) < len(s.lines) {
+		// The previous frame was longer
+		sb.WriteString("\033[J")
+	}
+	fmt.Fprint(s.out, sb.String())
+	s.lines, s.drawn = lines, true
+}
+
+// watch shows render's output every interval until ctx is cancelled. On a
+// terminal the view is redrawn in place; otherwise, as in logs and pipes, a
+// snapshot is appended each time the output changes. Machine-readable
+// output is always appended, one snapshot per change, without headers.
+func watch(ctx context.Context, out io.Writer, terminal, machine bool, interval time.Duration, render func() (string, error)) error {
+	scr := &screen{out: out}
+	var last string
+	for {
+		frame, err := render()
+		if err != nil {
+			return err
+		}
+		switch {
+		case frame == last:
+		case terminal && !machine:
+			scr.draw(frame)
+		case machine:
+			fmt.Fprint(out, frame)
+		default:
+			fmt.Fprintf(out, "--- %s ---\n%s", time.Now().Format("15:04:05"), frame)
+		}
+		last = frame
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(interval):
+		}
+	}
+}
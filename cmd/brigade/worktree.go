@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"brigade/internal/config"
+	"brigade/internal/prd"
+	"brigade/internal/worktree"
+)
+
+// enterWorktree moves into the PRD's own worktree, on its branch, creating
+// it from the default branch the first time. The PRD is copied in if the
+// worktree's checkout doesn't have it. It returns the PRD's path from the
+// new working directory, the chef prompt directory to use and a func that
+// moves back.
+func enterWorktree(cfg *config.Config, p *prd.PRD, prdPath string) (string, string, func(), error) {
+	if p.BranchName == "" {
+		return "", "", nil, fmt.Errorf("--worktree needs the PRD to have a branchName")
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", "", nil, err
+	}
+	root, err := worktree.Root(cwd)
+	if err != nil {
+		return "", "", nil, err
+	}
+	rel, err := filepath.Rel(root, cwd)
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	dir := worktree.Dir(root, cfg.WorktreeDir, p.Prefix())
+	base := worktree.DefaultBranch(root, cfg.DefaultBranch)
+	created, err := worktree.Ensure(root, dir, p.BranchName, base)
+	if err != nil {
+		return "", "", nil, fmt.Errorf("creating worktree: %w", err)
+	}
+	if created {
+		fmt.Printf("Created worktree %s on %s (from %s)\n", dir, p.BranchName, base)
+	} else {
+		fmt.Printf("Using worktree %s on %s\n", dir, p.BranchName)
+	}
+
+	// Carry over a PRD that isn't committed on the branch
+	absPRD, err := filepath.Abs(prdPath)
+	if err != nil {
+		return "", "", nil, err
+	}
+	prdRel, err := filepath.Rel(root, absPRD)
+	if err != nil || strings.HasPrefix(prdRel, "..") {
+		return "", "", nil, fmt.Errorf("%s is outside the repository", prdPath)
+	}
+	target := filepath.Join(dir, prdRel)
+	if _, err := os.Stat(target); os.IsNotExist(err) {
+		data, err := os.ReadFile(absPRD)
+		if err != nil {
+			return "", "", nil, err
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return "", "", nil, err
+		}
+		if err := os.WriteFile(target, data, 0644); err != nil {
+			return "", "", nil, err
+		}
+	}
+
+	chefDir := ""
+	if info, err := os.Stat("chef"); err == nil && info.IsDir() {
+		chefDir = filepath.Join(cwd, "chef")
+	}
+
+	workDir := filepath.Join(dir, rel)
+	if err := os.MkdirAll(workDir, 0755); err != nil {
+		return "", "", nil, err
+	}
+	if err := os.Chdir(workDir); err != nil {
+		return "", "", nil, err
+	}
+	leave := func() { os.Chdir(cwd) }
+
+	path, err := filepath.Rel(workDir, target)
+	if err != nil {
+		leave()
+		return "", "", nil, err
+	}
+	return path, chefDir, leave, nil
+}
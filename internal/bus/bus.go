@@ -0,0 +1,73 @@
+// Package bus delivers the events a service emits to everything following
+// them: modules, supervisor files, the activity log and metrics. Emitters
+// publish each event once; subscribers decide what to do with it.
+package bus
+
+import (
+	"sync"
+
+	"brigade/internal/module"
+)
+
+// Handler receives a published event. Handlers run synchronously, in the
+// order they subscribed, so one that does slow work should hand it off.
+type Handler func(*module.Event)
+
+// Bus fans events out to its subscribers.
+type Bus struct {
+	mu       sync.RWMutex
+	handlers []Handler
+}
+
+// New creates a bus with no subscribers.
+func New() *Bus {
+	return &Bus{}
+}
+
+// Subscribe adds a handler for every event published from now on.
+func (b *Bus) Subscribe(h Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers = append(b.handlers, h)
+}
+
+// Publish delivers an event to every subscriber.
+func (b *Bus) Publish(event *module.Event) {
+	b.mu.RLock()
+	handlers := b.handlers
+	b.mu.RUnlock()
+
+	for _, h := range handlers {
+		h(event)
+	}
+}
+
+// Metrics counts published events by type.
+type Metrics struct {
+	mu     sync.Mutex
+	counts map[module.EventType]int
+}
+
+// NewMetrics creates an empty event counter.
+func NewMetrics() *Metrics {
+	return &Metrics{counts: make(map[module.EventType]int)}
+}
+
+// Handle counts an event. Subscribe it to a bus.
+func (m *Metrics) Handle(event *module.Event) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.counts[event.Type]++
+}
+
+// Counts returns how many events of each type were published.
+func (m *Metrics) Counts() map[string]int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	counts := make(map[string]int, len(m.counts))
+	for eventType, n := range m.counts {
+		counts[string(eventType)] = n
+	}
+	return counts
+}
@@ -0,0 +1,31 @@
+package bus
+
+import (
+	"strings"
+	"testing"
+
+	"brigade/internal/module"
+)
+
+func TestPublish(t *testing.T) {
+	b := New()
+	var seen []string
+	b.Subscribe(func(e *module.Event) { seen = append(seen, "first:"+string(e.Type)) })
+	b.Subscribe(func(e *module.Event) { seen = append(seen, "second:"+string(e.Type)) })
+	metrics := NewMetrics()
+	b.Subscribe(metrics.Handle)
+
+	b.Publish(module.TaskStartEvent("auth", "US-001", "line"))
+	b.Publish(module.TaskCompleteEvent("auth", "US-001", "line", 0))
+	b.Publish(module.TaskStartEvent("auth", "US-002", "line"))
+
+	want := "first:task_start,second:task_start,first:task_complete,second:task_complete,first:task_start,second:task_start"
+	if got := strings.Join(seen, ","); got != want {
+		t.Errorf("Publish() delivered %s, want %s", got, want)
+	}
+
+	counts := metrics.Counts()
+	if counts["task_start"] != 2 || counts["task_complete"] != 1 || len(counts) != 2 {
+		t.Errorf("Counts() = %v, want task_start 2, task_complete 1", counts)
+	}
+}
@@ -0,0 +1,215 @@
+// Package chef checks the chef prompt files workers are given: that each
+// tier has one, that it teaches the signals the orchestrator parses, that
+// it fits a token budget, and that it doesn't contradict the config.
+package chef
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"brigade/internal/config"
+	"brigade/internal/state"
+)
+
+// Tiers are the worker tiers with a chef prompt, in escalation order.
+var Tiers = []state.WorkerTier{state.TierLine, state.TierSous, state.TierExecutive}
+
+// File returns the chef prompt file name for a worker tier.
+func File(tier state.WorkerTier) string {
+	switch tier {
+	case state.TierSous:
+		return "sous.md"
+	case state.TierExecutive:
+		return "executive.md"
+	}
+	return "line.md"
+}
+
+// Severity is how serious a finding is. Errors fail the lint.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Finding is one problem with a chef prompt.
+type Finding struct {
+	File     string
+	Line     int // 0 when the finding is about the whole file
+	Severity Severity
+	Message  string
+}
+
+func (f Finding) String() string {
+	if f.Line > 0 {
+		return fmt.Sprintf("%s:%d: %s", f.File, f.Line, f.Message)
+	}
+	return fmt.Sprintf("%s: %s", f.File, f.Message)
+}
+
+// signal is a piece of the output protocol a chef prompt teaches.
+type signal struct {
+	Text     string
+	Severity Severity
+	Tiers    []state.WorkerTier // nil for every tier
+	Why      string
+}
+
+// signals is the protocol the orchestrator parses from worker output. A
+// prompt that never mentions COMPLETE leaves every task iterating until it
+// hits its limit.
+var signals = []signal{
+	{"<promise>COMPLETE</promise>", SeverityError, nil, "tasks never complete without it"},
+	{"<promise>BLOCKED</promise>", SeverityError, nil, "blocked tasks iterate instead of escalating"},
+	{"<promise>ALREADY_DONE</promise>", SeverityWarning, nil, "work done by an earlier task gets redone"},
+	{"<promise>ABSORBED_BY:", SeverityWarning, nil, "tasks covered by another can't be absorbed"},
+	{"<approach>", SeverityWarning, []state.WorkerTier{state.TierLine, state.TierSous}, "retries can't steer away from failed approaches"},
+	{"<learning>", SeverityWarning, []state.WorkerTier{state.TierLine, state.TierSous}, "nothing is added to the learnings file"},
+}
+
+var (
+	promiseTagPattern = regexp.MustCompile(`<promise>([^<]*)</promise>`)
+	knownPromises     = regexp.MustCompile(`^(COMPLETE|BLOCKED|ALREADY_DONE|ABSORBED_BY:\S+)$`)
+)
+
+// claim is a statement a chef prompt can make that's only true for some
+// configs.
+type claim struct {
+	Pattern *regexp.Regexp
+	// Contradicts returns why the claim is false under cfg, or "".
+	Contradicts func(cfg *config.Config) string
+}
+
+var claims = []claim{
+	{
+		regexp.MustCompile(`(?i)verification( commands)?\s+(will not|won't|isn't|is not|aren't|are not|never)\s+(be\s+)?run`),
+		func(cfg *config.Config) string {
+			if cfg.VerificationEnabled {
+				return "says verification won't run, but VERIFICATION_ENABLED is on"
+			}
+			return ""
+		},
+	},
+	{
+		regexp.MustCompile(`(?i)verification( commands)?\s+(will|are|is)\s+(be\s+)?run`),
+		func(cfg *config.Config) string {
+			if !cfg.VerificationEnabled {
+				return "says verification runs, but VERIFICATION_ENABLED is off"
+			}
+			return ""
+		},
+	},
+	{
+		regexp.MustCompile(`(?i)(no|without)\s+(an?\s+)?executive\s+review|(won't|will not|isn't|is not)\s+(be\s+)?reviewed`),
+		func(cfg *config.Config) string {
+			if cfg.ReviewEnabled {
+				return "says work isn't reviewed, but REVIEW_ENABLED is on"
+			}
+			return ""
+		},
+	},
+	{
+		regexp.MustCompile(`(?i)(will|is going to)\s+be\s+reviewed\s+by\s+the\s+executive`),
+		func(cfg *config.Config) string {
+			if !cfg.ReviewEnabled {
+				return "says the executive reviews the work, but REVIEW_ENABLED is off"
+			}
+			return ""
+		},
+	},
+	{
+		regexp.MustCompile(`(?i)(TODOs?|FIXMEs?)\s+(are|is)\s+(fine|ok|okay|allowed|acceptable)`),
+		func(cfg *config.Config) string {
+			if cfg.TodoScanEnabled {
+				return "says TODOs are fine, but TODO_SCAN_ENABLED fails tasks that leave them"
+			}
+			return ""
+		},
+	},
+}
+
+// EstimateTokens approximates a prompt's size in tokens at about four
+// characters each.
+func EstimateTokens(text string) int {
+	return (len(text) + 3) / 4
+}
+
+// Lint checks the chef prompt of every tier in dir against cfg.
+func Lint(dir string, cfg *config.Config) []Finding {
+	var findings []Finding
+	for _, tier := range Tiers {
+		path := filepath.Join(dir, File(tier))
+		data, err := os.ReadFile(path)
+		if err != nil {
+			msg := fmt.Sprintf("can't read the %s prompt: %v", tier, err)
+			if os.IsNotExist(err) {
+				msg = fmt.Sprintf("missing; every %s task fails without it", tier)
+			}
+			findings = append(findings, Finding{File: path, Severity: SeverityError, Message: msg})
+			continue
+		}
+		findings = append(findings, LintPrompt(path, tier, string(data), cfg)...)
+	}
+	return findings
+}
+
+// LintPrompt checks one tier's chef prompt.
+func LintPrompt(path string, tier state.WorkerTier, text string, cfg *config.Config) []Finding {
+	var findings []Finding
+	add := func(line int, severity Severity, format string, args ...any) {
+		findings = append(findings, Finding{File: path, Line: line, Severity: severity, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if strings.TrimSpace(text) == "" {
+		add(0, SeverityError, "empty")
+		return findings
+	}
+
+	for _, s := range signals {
+		if s.Tiers != nil && !slices.Contains(s.Tiers, tier) {
+			continue
+		}
+		if !strings.Contains(text, s.Text) {
+			add(0, s.Severity, "doesn't teach %s; %s", s.Text, s.Why)
+		}
+	}
+
+	if budget := cfg.ChefPromptMaxTokens; budget > 0 {
+		if n := EstimateTokens(text); n > budget {
+			add(0, SeverityError, "about %d tokens, over the %d-token budget (CHEF_PROMPT_MAX_TOKENS)", n, budget)
+		}
+	}
+
+	for i, line := range strings.Split(text, "\n") {
+		for _, m := range promiseTagPattern.FindAllStringSubmatch(line, -1) {
+			if !knownPromises.MatchString(strings.TrimSpace(m[1])) {
+				add(i+1, SeverityError, "teaches %s, which the orchestrator doesn't recognize", m[0])
+			}
+		}
+		for _, c := range claims {
+			if !c.Pattern.MatchString(line) {
+				continue
+			}
+			if why := c.Contradicts(cfg); why != "" {
+				add(i+1, SeverityError, "%s", why)
+			}
+		}
+	}
+	return findings
+}
+
+// Errors counts the findings that fail the lint.
+func Errors(findings []Finding) int {
+	n := 0
+	for _, f := range findings {
+		if f.Severity == SeverityError {
+			n++
+		}
+	}
+	return n
+}
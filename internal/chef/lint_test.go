@@ -0,0 +1,84 @@
+package chef
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"brigade/internal/config"
+	"brigade/internal/state"
+)
+
+const goodPrompt = `# Line Cook
+
+Output <promise>COMPLETE</promise> when done, <promise>ALREADY_DONE</promise>
+if an earlier task did it, <promise>ABSORBED_BY:US-XXX</promise> if another
+task covers it, or <promise>BLOCKED</promise> if you're stuck.
+
+<approach>Your strategy</approach>
+<learning>What you discovered</learning>
+`
+
+func TestLintPrompt(t *testing.T) {
+	reviewOff := config.Default()
+	reviewOff.ReviewEnabled = false
+	small := config.Default()
+	small.ChefPromptMaxTokens = 10
+
+	tests := []struct {
+		name   string
+		tier   state.WorkerTier
+		text   string
+		cfg    *config.Config
+		errors int
+		want   string // Substring of the first finding
+	}{
+		{"clean", state.TierLine, goodPrompt, config.Default(), 0, ""},
+		{"empty", state.TierLine, "  \n", config.Default(), 1, "empty"},
+		{"no complete signal", state.TierSous, strings.ReplaceAll(goodPrompt, "<promise>COMPLETE</promise>", ""), config.Default(), 1, "COMPLETE"},
+		{"executive needs no approach", state.TierExecutive, strings.ReplaceAll(goodPrompt, "<approach>", ""), config.Default(), 0, ""},
+		{"line needs approach", state.TierLine, strings.ReplaceAll(goodPrompt, "<approach>", ""), config.Default(), 0, "<approach>"},
+		{"unknown signal", state.TierLine, goodPrompt + "Or <promise>DONE</promise>.\n", config.Default(), 1, ":9: teaches <promise>DONE</promise>"},
+		{"over budget", state.TierLine, goodPrompt, small, 1, "token budget"},
+		{"verification claim", state.TierLine, goodPrompt + "Verification commands will not be run.\n", config.Default(), 1, "VERIFICATION_ENABLED is on"},
+		{"review claim holds", state.TierLine, goodPrompt + "Your work won't be reviewed.\n", reviewOff, 0, ""},
+		{"review claim contradicted", state.TierLine, goodPrompt + "Your work won't be reviewed.\n", config.Default(), 1, "REVIEW_ENABLED is on"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings := LintPrompt("line.md", tt.tier, tt.text, tt.cfg)
+			if got := Errors(findings); got != tt.errors {
+				t.Errorf("Errors() = %d, want %d (%v)", got, tt.errors, findings)
+			}
+			if tt.want == "" {
+				if tt.errors == 0 && len(findings) > 0 {
+					t.Errorf("LintPrompt() = %v, want no findings", findings)
+				}
+				return
+			}
+			if len(findings) == 0 || !strings.Contains(findings[0].String(), tt.want) {
+				t.Errorf("LintPrompt() = %v, want a finding containing %q", findings, tt.want)
+			}
+		})
+	}
+}
+
+func TestLintMissingPrompt(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "line.md"), []byte(goodPrompt), 0644)
+	os.WriteFile(filepath.Join(dir, "sous.md"), []byte(goodPrompt), 0644)
+
+	findings := Lint(dir, config.Default())
+	if len(findings) != 1 || !strings.Contains(findings[0].String(), "executive.md: missing") {
+		t.Errorf("Lint() = %v, want executive.md missing", findings)
+	}
+}
+
+// TestShippedPrompts keeps the chef prompts in the repo passing the lint
+// under the default config.
+func TestShippedPrompts(t *testing.T) {
+	for _, f := range Lint(filepath.Join("..", "..", "chef"), config.Default()) {
+		t.Errorf("chef prompt: %s", f)
+	}
+}
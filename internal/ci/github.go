@@ -0,0 +1,226 @@
+// Package ci formats service output for CI systems.
+package ci
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"brigade/internal/module"
+	"brigade/internal/orchestrator"
+	"brigade/internal/prd"
+	"brigade/internal/util"
+)
+
+// maxAnnotations caps the file annotations made for one verification failure.
+// GitHub shows at most 10 error annotations per step.
+const maxAnnotations = 10
+
+// locationPattern matches compiler and test output locations such as
+// "pkg/foo.go:12:5: message" or "tests/test_api.py:40: AssertionError".
+var locationPattern = regexp.MustCompile(`^\s*([\w./\\-]+\.[A-Za-z0-9]+):(\d+)(?::(\d+))?:\s*(.*)$`)
+
+// GitHub writes GitHub Actions workflow commands: a collapsible group per
+// task attempt, warnings for escalations and blocks, and error annotations
+// for verification failures.
+type GitHub struct {
+	out  io.Writer
+	prd  *prd.PRD
+	mu   sync.Mutex
+	open bool // a ::group:: is open
+}
+
+// NewGitHub creates a GitHub Actions reporter for a PRD.
+func NewGitHub(out io.Writer, p *prd.PRD) *GitHub {
+	return &GitHub{out: out, prd: p}
+}
+
+// HandleEvent writes workflow commands for a service event.
+func (g *GitHub) HandleEvent(e *module.Event) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	switch e.Type {
+	case module.EventTaskStart:
+		g.endGroup()
+		title := e.TaskID
+		if task := g.prd.TaskByID(e.TaskID); task != nil {
+			title += ": " + task.Title
+		}
+		fmt.Fprintf(g.out, "::group::%s [%s]\n", title, e.Worker)
+		g.open = true
+
+	case module.EventTaskIteration:
+		fmt.Fprintf(g.out, "Attempt %v on %s: %v\n", e.Data["attempt"], e.TaskID, e.Data["status"])
+		g.endGroup()
+
+	case module.EventEscalation:
+		fmt.Fprintf(g.out, "::warning title=Escalated %s::%s\n", escapeProperty(e.TaskID),
+			escapeData(fmt.Sprintf("%v → %v: %v", e.Data["from"], e.Data["to"], e.Data["reason"])))
+
+	case module.EventTaskBlocked:
+		fmt.Fprintf(g.out, "::warning title=Blocked %s::%s\n", escapeProperty(e.TaskID), escapeData(fmt.Sprint(e.Data["reason"])))
+
+	case module.EventAttention:
+		fmt.Fprintf(g.out, "::warning title=Attention::%s\n", escapeData(fmt.Sprint(e.Data["reason"])))
+
+	case module.EventVerification:
+		if passed, _ := e.Data["passed"].(bool); passed {
+			return
+		}
+		details, _ := e.Data["details"].(string)
+		for _, line := range Annotations(e.TaskID, details) {
+			fmt.Fprintln(g.out, line)
+		}
+
+	case module.EventServiceComplete:
+		g.endGroup()
+	}
+}
+
+// endGroup closes the open group, if any.
+func (g *GitHub) endGroup() {
+	if g.open {
+		fmt.Fprintln(g.out, "::endgroup::")
+		g.open = false
+	}
+}
+
+// Error writes an error annotation for a failed run.
+func (g *GitHub) Error(err error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.endGroup()
+	fmt.Fprintf(g.out, "::error title=Brigade::%s\n", escapeData(err.Error()))
+}
+
+// Annotations turns verification failure output into error annotations.
+// Lines naming a file and line become located annotations; if none do, a
+// single annotation carries the start of the output.
+func Annotations(taskID, output string) []string {
+	title := escapeProperty("Verification failed: " + taskID)
+	var annotations []string
+	for _, line := range strings.Split(output, "\n") {
+		m := locationPattern.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		loc := fmt.Sprintf("file=%s,line=%s", escapeProperty(strings.TrimPrefix(m[1], "./")), m[2])
+		if m[3] != "" {
+			loc += ",col=" + m[3]
+		}
+		msg := strings.TrimSpace(m[4])
+		if msg == "" {
+			msg = "verification failed"
+		}
+		annotations = append(annotations, fmt.Sprintf("::error %s,title=%s::%s", loc, title, escapeData(msg)))
+		if len(annotations) == maxAnnotations {
+			break
+		}
+	}
+	if len(annotations) == 0 {
+		annotations = append(annotations, fmt.Sprintf("::error title=%s::%s", title, escapeData(firstLines(output, 20))))
+	}
+	return annotations
+}
+
+// WriteSummary appends a markdown report to the job summary file
+// ($GITHUB_STEP_SUMMARY). It does nothing when path is empty.
+func WriteSummary(path string, r *orchestrator.RunResult) error {
+	if path == "" || r == nil {
+		return nil
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("opening job summary: %w", err)
+	}
+	defer f.Close()
+	_, err = io.WriteString(f, Summary(r))
+	return err
+}
+
+// Summary renders a run result as a job summary in markdown.
+func Summary(r *orchestrator.RunResult) string {
+	var sb strings.Builder
+	mark := "✅"
+	if !r.Success {
+		mark = "❌"
+	}
+	sb.WriteString(fmt.Sprintf("## %s Brigade: %s\n\n", mark, r.FeatureName))
+	sb.WriteString(fmt.Sprintf("**%d/%d tasks complete** in %s · estimated cost %s\n\n",
+		r.Completed, r.Total, util.FormatDuration(time.Duration(r.DurationSeconds)*time.Second), util.FormatCost(r.EstimatedCost)))
+	if r.Error != "" {
+		sb.WriteString(fmt.Sprintf("> %s\n\n", r.Error))
+	}
+
+	sb.WriteString("| Task | Status | Worker | Attempts | Time |\n")
+	sb.WriteString("|------|--------|--------|----------|------|\n")
+	for _, t := range r.Tasks {
+		worker := string(t.Worker)
+		if t.Escalated {
+			worker += " ⬆"
+		}
+		sb.WriteString(fmt.Sprintf("| %s: %s | %s | %s | %d | %s |\n",
+			t.ID, escapeCell(t.Title), t.Status, worker, t.Attempts, util.FormatDuration(time.Duration(t.DurationSeconds)*time.Second)))
+	}
+
+	if len(r.Escalations) > 0 {
+		sb.WriteString("\n**Escalations**\n\n")
+		for _, e := range r.Escalations {
+			sb.WriteString(fmt.Sprintf("- %s: %s → %s (%s)\n", e.TaskID, e.From, e.To, e.Reason))
+		}
+	}
+	if len(r.Skipped) > 0 {
+		sb.WriteString(fmt.Sprintf("\n**Skipped:** %s\n", strings.Join(r.Skipped, ", ")))
+	}
+	var flaky []string
+	for _, t := range r.Tasks {
+		for _, cmd := range t.Flaky {
+			flaky = append(flaky, fmt.Sprintf("- %s: `%s`", t.ID, cmd))
+		}
+	}
+	if len(flaky) > 0 {
+		sb.WriteString("\n**Flaky verification** (passed and failed on the same code)\n\n")
+		sb.WriteString(strings.Join(flaky, "\n") + "\n")
+	}
+	if len(r.UnblockSuggestions) > 0 {
+		sb.WriteString("\n**Unblock suggestions**\n\n")
+		for _, u := range r.UnblockSuggestions {
+			sb.WriteString(fmt.Sprintf("- %s: %s\n", u.Kind, u.Suggestion))
+		}
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+// firstLines returns up to n lines from the start of s.
+func firstLines(s string, n int) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	if len(lines) > n {
+		lines = lines[:n]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// escapeData escapes a workflow command message.
+func escapeData(s string) string {
+	s = strings.ReplaceAll(s, "%", "%25")
+	s = strings.ReplaceAll(s, "\r", "%0D")
+	return strings.ReplaceAll(s, "\n", "%0A")
+}
+
+// escapeProperty escapes a workflow command property value.
+func escapeProperty(s string) string {
+	s = escapeData(s)
+	s = strings.ReplaceAll(s, ":", "%3A")
+	return strings.ReplaceAll(s, ",", "%2C")
+}
+
+// escapeCell keeps a value from breaking a markdown table row.
+func escapeCell(s string) string {
+	return strings.ReplaceAll(s, "|", "\\|")
+}
@@ -0,0 +1,56 @@
+package ci
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"brigade/internal/module"
+	"brigade/internal/prd"
+)
+
+func TestAnnotations(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   []string
+	}{
+		{
+			name:   "go compiler error",
+			output: "$ go build ./... (exited with code 1)\n./internal/api/users.go:42:9: undefined: userStore\n",
+			want:   []string{"::error file=internal/api/users.go,line=42,col=9,title=Verification failed%3A US-001::undefined: userStore"},
+		},
+		{
+			name:   "pytest failure",
+			output: "tests/test_users.py:17: AssertionError\n1 failed",
+			want:   []string{"::error file=tests/test_users.py,line=17,title=Verification failed%3A US-001::AssertionError"},
+		},
+		{
+			name:   "no locations",
+			output: "npm ERR! missing script: test\n",
+			want:   []string{"::error title=Verification failed%3A US-001::npm ERR! missing script: test"},
+		},
+	}
+
+	for _, tt := range tests {
+		got := Annotations("US-001", tt.output)
+		if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+			t.Errorf("%s: Annotations() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestHandleEventGroups(t *testing.T) {
+	p := &prd.PRD{Tasks: []prd.Task{{ID: "US-001", Title: "Add login"}}}
+	var out bytes.Buffer
+	gh := NewGitHub(&out, p)
+
+	gh.HandleEvent(module.TaskStartEvent("auth", "US-001", "line"))
+	gh.HandleEvent(module.TaskIterationEvent("auth", "US-001", "line", 1, "complete"))
+	gh.HandleEvent(module.ServiceCompleteEvent("auth", 1, 1, 0))
+
+	want := "::group::US-001: Add login [line]\nAttempt 1 on US-001: complete\n::endgroup::\n"
+	if out.String() != want {
+		t.Errorf("output = %q, want %q", out.String(), want)
+	}
+}
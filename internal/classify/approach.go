@@ -0,0 +1,69 @@
+package classify
+
+import (
+	"regexp"
+	"strings"
+)
+
+// approachSimilarity is the share of words two approaches must have in
+// common to count as the same approach.
+const approachSimilarity = 0.6
+
+var approachWordPattern = regexp.MustCompile(`[a-z0-9]+`)
+
+// approachStopwords are words that don't tell approaches apart.
+var approachStopwords = map[string]bool{
+	"a": true, "an": true, "the": true, "to": true, "and": true, "or": true,
+	"of": true, "for": true, "in": true, "on": true, "at": true, "with": true,
+	"by": true, "it": true, "its": true, "this": true, "that": true, "then": true,
+	"again": true, "instead": true, "try": true, "approach": true, "so": true,
+	"is": true, "be": true, "all": true, "any": true, "some": true, "just": true,
+}
+
+// ApproachWords normalizes a declared approach to its distinctive words:
+// lowercased, stopwords dropped, and common suffixes stripped so "fixing
+// the failing tests" and "fix failed test" reduce to the same words.
+func ApproachWords(approach string) map[string]bool {
+	words := make(map[string]bool)
+	for _, w := range approachWordPattern.FindAllString(strings.ToLower(approach), -1) {
+		if approachStopwords[w] {
+			continue
+		}
+		words[stem(w)] = true
+	}
+	return words
+}
+
+// stem strips an -ing, -ed, -es or -s suffix from longer words.
+func stem(w string) string {
+	for _, suffix := range []string{"ing", "ed", "es", "s"} {
+		if len(w) > len(suffix)+2 && strings.HasSuffix(w, suffix) {
+			return strings.TrimSuffix(w, suffix)
+		}
+	}
+	return w
+}
+
+// SimilarApproaches reports whether two declared approaches are the same
+// idea in different words: most of their words are shared, or every word
+// of the shorter one (at least two) appears in the longer.
+func SimilarApproaches(a, b string) bool {
+	wa, wb := ApproachWords(a), ApproachWords(b)
+	if len(wa) == 0 || len(wb) == 0 {
+		return false
+	}
+	if len(wa) > len(wb) {
+		wa, wb = wb, wa
+	}
+	shared := 0
+	for w := range wa {
+		if wb[w] {
+			shared++
+		}
+	}
+	if shared == len(wa) && (len(wa) >= 2 || len(wa) == len(wb)) {
+		return true
+	}
+	union := len(wa) + len(wb) - shared
+	return float64(shared)/float64(union) >= approachSimilarity
+}
@@ -14,6 +14,7 @@ const (
 	CategoryIntegration Category = "integration"
 	CategoryEnvironment Category = "environment"
 	CategoryLogic       Category = "logic"
+	CategorySecret      Category = "secret" // A secret committed to the code
 	CategoryUnknown     Category = "unknown"
 )
 
@@ -271,6 +272,8 @@ func Suggestions(category Category) string {
 		return "Try: Check file paths, verify permissions, ensure dependencies installed"
 	case CategoryLogic:
 		return "Try: Re-read acceptance criteria, check edge cases, verify test setup"
+	case CategorySecret:
+		return "Try: Remove the secret from the code and its commits, read it from configuration or an environment variable"
 	default:
 		return "Try: Review the error output carefully and take a different approach"
 	}
@@ -105,6 +105,7 @@ func TestSuggestions(t *testing.T) {
 		{CategoryIntegration, "Mock the service"},
 		{CategoryEnvironment, "Check file paths"},
 		{CategoryLogic, "Re-read acceptance criteria"},
+		{CategorySecret, "environment variable"},
 	}
 
 	for _, tt := range tests {
@@ -132,3 +133,26 @@ func TestIsRetryable(t *testing.T) {
 		t.Error("logic errors should be retryable")
 	}
 }
+
+func TestSimilarApproaches(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"fix the test", "fix failing test", true},
+		{"Fixing the failing tests", "fix failed test", true},
+		{"fix test", "fix the failing unit test", true},
+		{"Mock the HTTP client", "mock http client in tests", true},
+		{"fix the test", "rewrite the parser", false},
+		{"Mock the HTTP client", "Start the real server", false},
+		{"refactor", "refactor", true},
+		{"refactor", "refactor the handler", false},
+		{"", "fix the test", false},
+	}
+
+	for _, tt := range tests {
+		if got := SimilarApproaches(tt.a, tt.b); got != tt.want {
+			t.Errorf("SimilarApproaches(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package classify
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Strategies maps error categories to the retry suggestion shown to
+// workers, overriding the built-in ones (see Suggestions).
+type Strategies map[Category]string
+
+// LoadStrategies reads a SMART_RETRY_STRATEGIES_FILE: a JSON object of
+// category to suggestion. A missing file or empty path yields no overrides.
+func LoadStrategies(path string) (Strategies, error) {
+	s := make(Strategies)
+	if path == "" {
+		return s, nil
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return s, err
+	}
+	if err := json.Unmarshal(data, &s); err != nil {
+		return make(Strategies), fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return s, nil
+}
+
+// Save writes the strategies as JSON.
+func (s Strategies) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// Suggestion returns the suggestion for a category, falling back to the
+// built-in one.
+func (s Strategies) Suggestion(category Category) string {
+	if suggestion, ok := s[category]; ok && suggestion != "" {
+		return suggestion
+	}
+	return Suggestions(category)
+}
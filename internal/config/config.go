@@ -24,6 +24,32 @@ type Config struct {
 	LineCmd        string `mapstructure:"LINE_CMD"`
 	LineAgent      string `mapstructure:"LINE_AGENT"`
 
+	// Credentials each tier rotates through ("name: KEY=value ...; ...")
+	LineCredentials      string `mapstructure:"LINE_CREDENTIALS"`
+	SousCredentials      string `mapstructure:"SOUS_CREDENTIALS"`
+	ExecutiveCredentials string `mapstructure:"EXECUTIVE_CREDENTIALS"`
+
+	// Providers (LINE_AGENT=bedrock or azure etc.)
+	BedrockRegion       string `mapstructure:"BEDROCK_REGION"`
+	BedrockProfile      string `mapstructure:"BEDROCK_PROFILE"`
+	AzureOpenAIResource string `mapstructure:"AZURE_OPENAI_RESOURCE"`
+	LocalModelURL       string `mapstructure:"LOCAL_MODEL_URL"`
+
+	// Gateway every tier is sent through (LiteLLM or another proxy)
+	GatewayURL     string `mapstructure:"GATEWAY_URL"`
+	GatewayAPIKey  string `mapstructure:"GATEWAY_API_KEY"`
+	GatewayHeaders string `mapstructure:"GATEWAY_HEADERS"`
+
+	// Offline (air-gapped) mode
+	Offline             bool   `mapstructure:"OFFLINE"`
+	OfflineAllowedHosts string `mapstructure:"OFFLINE_ALLOWED_HOSTS"`
+
+	// MCP servers attached to Claude workers
+	MCPConfig          string `mapstructure:"MCP_CONFIG"`
+	LineMCPConfig      string `mapstructure:"LINE_MCP_CONFIG"`
+	SousMCPConfig      string `mapstructure:"SOUS_MCP_CONFIG"`
+	ExecutiveMCPConfig string `mapstructure:"EXECUTIVE_MCP_CONFIG"`
+
 	// OpenCode Settings
 	OpenCodeServer                   string `mapstructure:"OPENCODE_SERVER"`
 	ClaudeDangerouslySkipPermissions bool   `mapstructure:"CLAUDE_DANGEROUSLY_SKIP_PERMISSIONS"`
@@ -46,12 +72,20 @@ type Config struct {
 	SupervisorCmdPollInterval time.Duration `mapstructure:"SUPERVISOR_CMD_POLL_INTERVAL"`
 	SupervisorCmdTimeout     time.Duration `mapstructure:"SUPERVISOR_CMD_TIMEOUT"`
 	SupervisorPRDScoped      bool          `mapstructure:"SUPERVISOR_PRD_SCOPED"`
+	SupervisorTaskFallback   string        `mapstructure:"SUPERVISOR_TASK_FALLBACK"` // executive, skip, pause
+	SupervisorGateFallback   string        `mapstructure:"SUPERVISOR_GATE_FALLBACK"` // pause, continue, abort
 
 	// Modules
 	Modules       []string      `mapstructure:"MODULES"`
 	ModuleTimeout time.Duration `mapstructure:"MODULE_TIMEOUT"`
 	ModuleConfig  map[string]string // MODULE_* env vars
 
+	// Module sandbox: handlers see only their MODULE_<NAME>_ vars and a
+	// minimal PATH, within CPU and memory limits (0 for none)
+	ModuleSandbox     bool          `mapstructure:"MODULE_SANDBOX"`
+	ModuleCPULimit    time.Duration `mapstructure:"MODULE_CPU_LIMIT"`
+	ModuleMemoryLimit int           `mapstructure:"MODULE_MEMORY_LIMIT"` // MB
+
 	// Terminal Module
 	ModuleTerminalBell bool `mapstructure:"MODULE_TERMINAL_BELL"`
 
@@ -60,28 +94,75 @@ type Config struct {
 	CostRateSous      float64 `mapstructure:"COST_RATE_SOUS"`
 	CostRateExecutive float64 `mapstructure:"COST_RATE_EXECUTIVE"`
 	CostWarnThreshold float64 `mapstructure:"COST_WARN_THRESHOLD"`
+	PricingFile       string  `mapstructure:"PRICING_FILE"`
 
 	// Risk Assessment
 	RiskReportEnabled bool   `mapstructure:"RISK_REPORT_ENABLED"`
 	RiskHistoryScan   bool   `mapstructure:"RISK_HISTORY_SCAN"`
 	RiskWarnThreshold string `mapstructure:"RISK_WARN_THRESHOLD"`
 
+	// Approvals (overrides that need an operator, and the audit log)
+	ApprovalRequired   []string `mapstructure:"APPROVAL_REQUIRED"`    // force-unlock, accept-risk, accept-cost, protected-paths
+	Operators          []string `mapstructure:"OPERATORS"`            // Who may approve; empty means anyone
+	OperatorTokensFile string   `mapstructure:"OPERATOR_TOKENS_FILE"` // "name:sha256" lines identifying operators
+	AuditLog           string   `mapstructure:"AUDIT_LOG"`
+	ProtectedPaths     []string `mapstructure:"PROTECTED_PATHS"` // Globs of files tasks may not change
+
 	// Codebase Map
 	MapStaleCommits int `mapstructure:"MAP_STALE_COMMITS"`
 
+	// Task Context
+	TaskFilesMaxBytes   int           `mapstructure:"TASK_FILES_MAX_BYTES"`
+	ChefPromptMaxTokens int           `mapstructure:"CHEF_PROMPT_MAX_TOKENS"`
+	PrepCookEnabled     bool          `mapstructure:"PREP_COOK_ENABLED"`
+	PrepCookCmd         string        `mapstructure:"PREP_COOK_CMD"`
+	PrepCookMaxBytes    int           `mapstructure:"PREP_COOK_MAX_BYTES"`
+	PrepCookTimeout     time.Duration `mapstructure:"PREP_COOK_TIMEOUT"`
+	IndexTopK           int           `mapstructure:"INDEX_TOP_K"`
+	EmbeddingProvider   string        `mapstructure:"EMBEDDING_PROVIDER"`
+	EmbeddingModel      string        `mapstructure:"EMBEDDING_MODEL"`
+	EmbeddingURL        string        `mapstructure:"EMBEDDING_URL"`
+
 	// Git
-	DefaultBranch string `mapstructure:"DEFAULT_BRANCH"`
+	DefaultBranch     string `mapstructure:"DEFAULT_BRANCH"`
+	CommitConvention  string `mapstructure:"COMMIT_CONVENTION"` // default or conventional
+	CommitSignoff     bool   `mapstructure:"COMMIT_SIGNOFF"`
+	CommitGPGSign     bool   `mapstructure:"COMMIT_GPG_SIGN"`
+	PRDWorktree       bool   `mapstructure:"PRD_WORKTREE"`        // Run each PRD in its own worktree
+	WorktreeDir       string `mapstructure:"WORKTREE_DIR"`        // Where PRD worktrees go
+	MergeAssist       bool   `mapstructure:"MERGE_ASSIST"`        // Rebase a finished worktree onto the default branch
+	MergeTrivialLines int    `mapstructure:"MERGE_TRIVIAL_LINES"` // Largest conflict the sous chef resolves
+
+	// Monorepo Workspaces
+	WorkspaceConfineEdits bool `mapstructure:"WORKSPACE_CONFINE_EDITS"`
 
 	// Testing
-	TestCmd     string        `mapstructure:"TEST_CMD"`
-	TestTimeout time.Duration `mapstructure:"TEST_TIMEOUT"`
+	TestCmd          string        `mapstructure:"TEST_CMD"`
+	TestTimeout      time.Duration `mapstructure:"TEST_TIMEOUT"`
+	TestGate         string        `mapstructure:"TEST_GATE"`         // off, task, every, phase
+	TestGateEvery    int           `mapstructure:"TEST_GATE_EVERY"`   // Completed tasks between runs for "every"
+	BuildCmd         string        `mapstructure:"BUILD_CMD"`
+	BuildTimeout     time.Duration `mapstructure:"BUILD_TIMEOUT"`
+	TaskHookTimeout  time.Duration `mapstructure:"TASK_HOOK_TIMEOUT"` // Limit on a task's setup or teardown command
+	PreCommitHooks   string        `mapstructure:"PRE_COMMIT_HOOKS"`  // auto, off, or a command to run instead
+	PreCommitTimeout time.Duration `mapstructure:"PRE_COMMIT_TIMEOUT"`
 
 	// Verification
 	VerificationEnabled         bool          `mapstructure:"VERIFICATION_ENABLED"`
 	VerificationTimeout         time.Duration `mapstructure:"VERIFICATION_TIMEOUT"`
 	TodoScanEnabled             bool          `mapstructure:"TODO_SCAN_ENABLED"`
+	SecretScanEnabled           bool          `mapstructure:"SECRET_SCAN_ENABLED"`
+	SecretScanIgnore            []string      `mapstructure:"SECRET_SCAN_IGNORE"` // Globs of files not scanned for secrets
+	LicenseHeaderFile           string        `mapstructure:"LICENSE_HEADER_FILE"` // Header new files must start with
+	LicenseHeaderFix            bool          `mapstructure:"LICENSE_HEADER_FIX"`  // Add missing headers instead of retrying
 	VerificationWarnGrepOnly    bool          `mapstructure:"VERIFICATION_WARN_GREP_ONLY"`
 	ManualVerificationEnabled   bool          `mapstructure:"MANUAL_VERIFICATION_ENABLED"`
+	VerificationFlakyRetries    int           `mapstructure:"VERIFICATION_FLAKY_RETRIES"`
+	ServicesConfig              string        `mapstructure:"SERVICES_CONFIG"`       // Services file ({"services": {...}}) for smoke verification
+	ServiceReadyTimeout         time.Duration `mapstructure:"SERVICE_READY_TIMEOUT"` // Wait for a service's health check
+	BrowserTestCmd              string        `mapstructure:"BROWSER_TEST_CMD"`      // Runs browser verification without a cmd
+	BrowserTimeout              time.Duration `mapstructure:"BROWSER_TIMEOUT"`
+	BrowserResultsDir           string        `mapstructure:"BROWSER_RESULTS_DIR"` // Where the runner leaves screenshots and traces
 
 	// PRD Quality & Verification Depth
 	CriteriaLintEnabled        bool `mapstructure:"CRITERIA_LINT_ENABLED"`
@@ -103,29 +184,62 @@ type Config struct {
 	EscalationAfter       int  `mapstructure:"ESCALATION_AFTER"`
 	EscalationToExec      bool `mapstructure:"ESCALATION_TO_EXEC"`
 	EscalationToExecAfter int  `mapstructure:"ESCALATION_TO_EXEC_AFTER"`
+	EscalationDiffMax     int  `mapstructure:"ESCALATION_DIFF_MAX"`
 
 	// Task Timeouts (Per-Complexity)
-	TaskTimeoutJunior    time.Duration `mapstructure:"TASK_TIMEOUT_JUNIOR"`
-	TaskTimeoutSenior    time.Duration `mapstructure:"TASK_TIMEOUT_SENIOR"`
-	TaskTimeoutExecutive time.Duration `mapstructure:"TASK_TIMEOUT_EXECUTIVE"`
+	TaskTimeoutJunior      time.Duration `mapstructure:"TASK_TIMEOUT_JUNIOR"`
+	TaskTimeoutSenior      time.Duration `mapstructure:"TASK_TIMEOUT_SENIOR"`
+	TaskTimeoutExecutive   time.Duration `mapstructure:"TASK_TIMEOUT_EXECUTIVE"`
+	TaskTimeoutFromHistory bool          `mapstructure:"TASK_TIMEOUT_FROM_HISTORY"` // Derive timeouts from earlier runs
 
 	// Worker Health Checks
 	WorkerHealthCheckInterval time.Duration `mapstructure:"WORKER_HEALTH_CHECK_INTERVAL"`
 	WorkerCrashExitCode       int           `mapstructure:"WORKER_CRASH_EXIT_CODE"`
+	WorkerShutdownGrace       time.Duration `mapstructure:"WORKER_SHUTDOWN_GRACE"`
+
+	// Worker Stall Detection (Per-Tier, 0 disables)
+	WorkerStallTimeoutJunior    time.Duration `mapstructure:"WORKER_STALL_TIMEOUT_JUNIOR"`
+	WorkerStallTimeoutSenior    time.Duration `mapstructure:"WORKER_STALL_TIMEOUT_SENIOR"`
+	WorkerStallTimeoutExecutive time.Duration `mapstructure:"WORKER_STALL_TIMEOUT_EXECUTIVE"`
+
+	// Rate Limits
+	RateLimitBackoff time.Duration `mapstructure:"RATE_LIMIT_BACKOFF"`  // Wait when the provider gives no reset time (0 = treat as failures)
+	RateLimitMaxWait time.Duration `mapstructure:"RATE_LIMIT_MAX_WAIT"` // Longest wait for one reset
+
+	// Executive response cache (0 disables)
+	ExecCacheTTL time.Duration `mapstructure:"EXEC_CACHE_TTL"`
 
 	// Executive Review
 	ReviewEnabled    bool `mapstructure:"REVIEW_ENABLED"`
 	ReviewJuniorOnly bool `mapstructure:"REVIEW_JUNIOR_ONLY"`
 
 	// Phase Review
-	PhaseReviewEnabled bool   `mapstructure:"PHASE_REVIEW_ENABLED"`
-	PhaseReviewAfter   int    `mapstructure:"PHASE_REVIEW_AFTER"`
-	PhaseReviewAction  string `mapstructure:"PHASE_REVIEW_ACTION"`
+	PhaseReviewEnabled bool          `mapstructure:"PHASE_REVIEW_ENABLED"`
+	PhaseReviewAfter   int           `mapstructure:"PHASE_REVIEW_AFTER"`
+	PhaseReviewAction  string        `mapstructure:"PHASE_REVIEW_ACTION"`
+	PhaseReviewTimeout time.Duration `mapstructure:"PHASE_REVIEW_TIMEOUT"`
+
+	// Unblock Analysis
+	UnblockAnalysis        bool          `mapstructure:"UNBLOCK_ANALYSIS"`
+	UnblockAnalysisTimeout time.Duration `mapstructure:"UNBLOCK_ANALYSIS_TIMEOUT"`
+
+	// Human Review Queue (completions flagged for a human spot-check)
+	HumanReviewQueue      bool   `mapstructure:"HUMAN_REVIEW_QUEUE"`
+	HumanReviewConfidence string `mapstructure:"HUMAN_REVIEW_CONFIDENCE"`
+	HumanReviewIterations int    `mapstructure:"HUMAN_REVIEW_ITERATIONS"`
 
 	// Context Isolation
 	ContextIsolation bool   `mapstructure:"CONTEXT_ISOLATION"`
 	StateFile        string `mapstructure:"STATE_FILE"`
 
+	// State Retention (redact stored worker output)
+	StateScrubAfterDays int  `mapstructure:"STATE_SCRUB_AFTER_DAYS"`
+	StateScrubCompleted bool `mapstructure:"STATE_SCRUB_COMPLETED"`
+
+	// Artifact Retention (brigade/artifacts)
+	ArtifactsKeepAttempts int `mapstructure:"ARTIFACTS_KEEP_ATTEMPTS"` // Newest attempts kept per task
+	ArtifactsMaxAgeDays   int `mapstructure:"ARTIFACTS_MAX_AGE_DAYS"`
+
 	// Knowledge Sharing
 	KnowledgeSharing bool   `mapstructure:"KNOWLEDGE_SHARING"`
 	LearningsFile    string `mapstructure:"LEARNINGS_FILE"`
@@ -140,11 +254,24 @@ type Config struct {
 	AutoContinue bool   `mapstructure:"AUTO_CONTINUE"`
 	PhaseGate    string `mapstructure:"PHASE_GATE"`
 
+	// Inbox (PRDs dropped for `serve --watch`)
+	InboxAutoQueue    bool          `mapstructure:"INBOX_AUTO_QUEUE"`
+	InboxPollInterval time.Duration `mapstructure:"INBOX_POLL_INTERVAL"`
+
 	// Walkaway Mode (Autonomous Execution)
 	WalkawayMode           bool          `mapstructure:"WALKAWAY_MODE"`
 	WalkawayMaxSkips       int           `mapstructure:"WALKAWAY_MAX_SKIPS"`
 	WalkawayDecisionTimeout time.Duration `mapstructure:"WALKAWAY_DECISION_TIMEOUT"`
 	WalkawayScopeDecisions bool          `mapstructure:"WALKAWAY_SCOPE_DECISIONS"`
+	WalkawayMaxDuration    time.Duration `mapstructure:"WALKAWAY_MAX_DURATION"`
+
+	// Anomaly Detection (walkaway mode)
+	AnomalyDetection        bool    `mapstructure:"ANOMALY_DETECTION"`
+	AnomalyWindow           int     `mapstructure:"ANOMALY_WINDOW"`
+	AnomalyEscalationRate   int     `mapstructure:"ANOMALY_ESCALATION_RATE"`
+	AnomalyRepeatedFailures int     `mapstructure:"ANOMALY_REPEATED_FAILURES"`
+	AnomalyDiffFactor       int     `mapstructure:"ANOMALY_DIFF_FACTOR"`
+	AnomalyBurnRate         float64 `mapstructure:"ANOMALY_BURN_RATE"`
 
 	// Lock Heartbeat
 	LockHeartbeatInterval time.Duration `mapstructure:"LOCK_HEARTBEAT_INTERVAL"`
@@ -156,6 +283,21 @@ type Config struct {
 	// Limits
 	MaxIterations int `mapstructure:"MAX_ITERATIONS"`
 
+	// Record / Replay
+	RecordFile string `mapstructure:"RECORD_FILE"`
+	ReplayFile string `mapstructure:"REPLAY_FILE"`
+
+	// Deterministic runs (pinned sampling, for benchmarking)
+	Deterministic     bool `mapstructure:"DETERMINISTIC"`
+	DeterministicSeed int  `mapstructure:"DETERMINISTIC_SEED"`
+
+	// Chaos Mode (failure injection, for testing Brigade itself)
+	ChaosMode          bool    `mapstructure:"CHAOS_MODE"`
+	ChaosCrashRate     float64 `mapstructure:"CHAOS_CRASH_RATE"`
+	ChaosTimeoutRate   float64 `mapstructure:"CHAOS_TIMEOUT_RATE"`
+	ChaosMalformedRate float64 `mapstructure:"CHAOS_MALFORMED_RATE"`
+	ChaosVerifyRate    float64 `mapstructure:"CHAOS_VERIFY_RATE"`
+
 	// Runtime flags (set via CLI, not config file)
 	ForceOverrideLock bool
 
@@ -194,11 +336,14 @@ func Default() *Config {
 		SupervisorCmdPollInterval: 2 * time.Second,
 		SupervisorCmdTimeout:      5 * time.Minute,
 		SupervisorPRDScoped:       true,
+		SupervisorTaskFallback:    "executive",
+		SupervisorGateFallback:    "pause",
 
 		// Modules
 		Modules:       []string{},
 		ModuleTimeout: 5 * time.Second,
 		ModuleConfig:  make(map[string]string),
+		ModuleSandbox: true,
 
 		// Terminal Module
 		ModuleTerminalBell: true,
@@ -207,21 +352,51 @@ func Default() *Config {
 		CostRateLine:      0.05,
 		CostRateSous:      0.15,
 		CostRateExecutive: 0.30,
+		PricingFile:       "brigade/pricing.json",
 
 		// Risk Assessment
 		RiskReportEnabled: true,
 		RiskHistoryScan:   false,
 
+		// Approvals
+		AuditLog: "brigade/audit.jsonl",
+
 		// Codebase Map
 		MapStaleCommits: 20,
 
+		// Task Context
+		TaskFilesMaxBytes:   60000,
+		ChefPromptMaxTokens: 4000,
+		PrepCookEnabled:     true,
+		PrepCookMaxBytes:    12000,
+		PrepCookTimeout:     2 * time.Minute,
+		IndexTopK:           5,
+		EmbeddingProvider:   "hash",
+
+		// Git
+		CommitConvention:  "default",
+		MergeAssist:       true,
+		MergeTrivialLines: 20,
+
 		// Testing
-		TestTimeout: 2 * time.Minute,
+		TestTimeout:      2 * time.Minute,
+		TestGate:         "off",
+		TestGateEvery:    5,
+		BuildTimeout:     2 * time.Minute,
+		TaskHookTimeout:  5 * time.Minute,
+		PreCommitHooks:   "auto",
+		PreCommitTimeout: 5 * time.Minute,
 
 		// Verification
 		VerificationEnabled:      true,
 		VerificationTimeout:      60 * time.Second,
+		VerificationFlakyRetries: 1,
+		ServiceReadyTimeout:      60 * time.Second,
+		BrowserTimeout:           5 * time.Minute,
+		BrowserResultsDir:        "test-results",
 		TodoScanEnabled:          true,
+		SecretScanEnabled:        true,
+		LicenseHeaderFix:         true,
 		VerificationWarnGrepOnly: true,
 
 		// PRD Quality
@@ -242,6 +417,7 @@ func Default() *Config {
 		EscalationAfter:       3,
 		EscalationToExec:      true,
 		EscalationToExecAfter: 5,
+		EscalationDiffMax:     20000,
 
 		// Task Timeouts
 		TaskTimeoutJunior:    15 * time.Minute,
@@ -251,19 +427,40 @@ func Default() *Config {
 		// Worker Health Checks
 		WorkerHealthCheckInterval: 5 * time.Second,
 		WorkerCrashExitCode:       125,
+		WorkerShutdownGrace:       10 * time.Second,
+
+		// Rate Limits
+		RateLimitBackoff: 60 * time.Second,
+		RateLimitMaxWait: time.Hour,
+
+		// Executive response cache
+		ExecCacheTTL: 24 * time.Hour,
 
 		// Executive Review
 		ReviewEnabled:    true,
 		ReviewJuniorOnly: true,
 
 		// Phase Review
-		PhaseReviewAfter:  5,
-		PhaseReviewAction: "continue",
+		PhaseReviewAfter:   5,
+		PhaseReviewAction:  "continue",
+		PhaseReviewTimeout: 300 * time.Second,
+
+		// Unblock Analysis
+		UnblockAnalysis:        true,
+		UnblockAnalysisTimeout: 300 * time.Second,
+
+		// Human Review Queue
+		HumanReviewConfidence: "low",
+		HumanReviewIterations: 3,
 
 		// Context Isolation
 		ContextIsolation: true,
 		StateFile:        "brigade-state.json",
 
+		// Artifact Retention
+		ArtifactsKeepAttempts: 5,
+		ArtifactsMaxAgeDays:   30,
+
 		// Knowledge Sharing
 		KnowledgeSharing: true,
 		LearningsFile:    "brigade-learnings.md",
@@ -277,11 +474,21 @@ func Default() *Config {
 		// Auto-Continue
 		PhaseGate: "continue",
 
+		// Inbox
+		InboxPollInterval: 5 * time.Second,
+
 		// Walkaway Mode
 		WalkawayMaxSkips:        3,
 		WalkawayDecisionTimeout: 2 * time.Minute,
 		WalkawayScopeDecisions:  true,
 
+		// Anomaly Detection
+		AnomalyDetection:        true,
+		AnomalyWindow:           5,
+		AnomalyEscalationRate:   60,
+		AnomalyRepeatedFailures: 3,
+		AnomalyDiffFactor:       5,
+
 		// Lock Heartbeat
 		LockHeartbeatInterval: 30 * time.Second,
 
@@ -291,6 +498,16 @@ func Default() *Config {
 
 		// Limits
 		MaxIterations: 50,
+
+		// Deterministic runs
+		DeterministicSeed: 1,
+
+		// Chaos Mode
+		ChaosMode:          false,
+		ChaosCrashRate:     0.1,
+		ChaosTimeoutRate:   0.05,
+		ChaosMalformedRate: 0.1,
+		ChaosVerifyRate:    0.1,
 	}
 }
 
@@ -374,6 +591,11 @@ func (c *Config) loadFromEnv() {
 	envVars := []string{
 		"USE_OPENCODE", "OPENCODE_MODEL",
 		"EXECUTIVE_CMD", "EXECUTIVE_AGENT", "SOUS_CMD", "SOUS_AGENT", "LINE_CMD", "LINE_AGENT",
+		"LINE_CREDENTIALS", "SOUS_CREDENTIALS", "EXECUTIVE_CREDENTIALS",
+		"BEDROCK_REGION", "BEDROCK_PROFILE", "AZURE_OPENAI_RESOURCE", "LOCAL_MODEL_URL",
+		"GATEWAY_URL", "GATEWAY_API_KEY", "GATEWAY_HEADERS",
+		"OFFLINE", "OFFLINE_ALLOWED_HOSTS",
+		"MCP_CONFIG", "LINE_MCP_CONFIG", "SOUS_MCP_CONFIG", "EXECUTIVE_MCP_CONFIG",
 		"OPENCODE_SERVER", "CLAUDE_DANGEROUSLY_SKIP_PERMISSIONS",
 		"QUIET_WORKERS",
 		"ACTIVITY_LOG", "ACTIVITY_LOG_INTERVAL",
@@ -381,29 +603,51 @@ func (c *Config) loadFromEnv() {
 		"WORKER_LOG_DIR", "STATUS_WATCH_INTERVAL",
 		"SUPERVISOR_STATUS_FILE", "SUPERVISOR_EVENTS_FILE", "SUPERVISOR_CMD_FILE",
 		"SUPERVISOR_CMD_POLL_INTERVAL", "SUPERVISOR_CMD_TIMEOUT", "SUPERVISOR_PRD_SCOPED",
+		"SUPERVISOR_TASK_FALLBACK", "SUPERVISOR_GATE_FALLBACK",
 		"MODULES", "MODULE_TIMEOUT", "MODULE_TERMINAL_BELL",
-		"COST_RATE_LINE", "COST_RATE_SOUS", "COST_RATE_EXECUTIVE", "COST_WARN_THRESHOLD",
+		"MODULE_SANDBOX", "MODULE_CPU_LIMIT", "MODULE_MEMORY_LIMIT",
+		"COST_RATE_LINE", "COST_RATE_SOUS", "COST_RATE_EXECUTIVE", "COST_WARN_THRESHOLD", "PRICING_FILE",
 		"RISK_REPORT_ENABLED", "RISK_HISTORY_SCAN", "RISK_WARN_THRESHOLD",
-		"MAP_STALE_COMMITS", "DEFAULT_BRANCH",
-		"TEST_CMD", "TEST_TIMEOUT",
+		"APPROVAL_REQUIRED", "OPERATORS", "OPERATOR_TOKENS_FILE", "AUDIT_LOG", "PROTECTED_PATHS",
+		"MAP_STALE_COMMITS", "TASK_FILES_MAX_BYTES", "CHEF_PROMPT_MAX_TOKENS",
+		"PREP_COOK_ENABLED", "PREP_COOK_CMD", "PREP_COOK_MAX_BYTES", "PREP_COOK_TIMEOUT",
+		"INDEX_TOP_K", "EMBEDDING_PROVIDER", "EMBEDDING_MODEL", "EMBEDDING_URL",
+		"DEFAULT_BRANCH", "COMMIT_CONVENTION", "COMMIT_SIGNOFF", "COMMIT_GPG_SIGN",
+		"PRD_WORKTREE", "WORKTREE_DIR", "MERGE_ASSIST", "MERGE_TRIVIAL_LINES", "WORKSPACE_CONFINE_EDITS",
+		"TEST_CMD", "TEST_TIMEOUT", "TEST_GATE", "TEST_GATE_EVERY",
+		"BUILD_CMD", "BUILD_TIMEOUT", "TASK_HOOK_TIMEOUT", "PRE_COMMIT_HOOKS", "PRE_COMMIT_TIMEOUT",
 		"VERIFICATION_ENABLED", "VERIFICATION_TIMEOUT", "TODO_SCAN_ENABLED",
-		"VERIFICATION_WARN_GREP_ONLY", "MANUAL_VERIFICATION_ENABLED",
+		"SECRET_SCAN_ENABLED", "SECRET_SCAN_IGNORE", "LICENSE_HEADER_FILE", "LICENSE_HEADER_FIX",
+		"VERIFICATION_WARN_GREP_ONLY", "MANUAL_VERIFICATION_ENABLED", "VERIFICATION_FLAKY_RETRIES",
+		"SERVICES_CONFIG", "SERVICE_READY_TIMEOUT",
+		"BROWSER_TEST_CMD", "BROWSER_TIMEOUT", "BROWSER_RESULTS_DIR",
 		"CRITERIA_LINT_ENABLED", "VERIFICATION_SCAFFOLD_ENABLED", "E2E_DETECTION_ENABLED",
 		"CROSS_PRD_CONTEXT_ENABLED", "CROSS_PRD_MAX_RELATED",
 		"SMART_RETRY_ENABLED", "SMART_RETRY_CUSTOM_PATTERNS", "SMART_RETRY_STRATEGIES_FILE",
 		"SMART_RETRY_APPROACH_HISTORY_MAX", "SMART_RETRY_SESSION_FAILURES_MAX",
 		"SMART_RETRY_AUTO_LEARNING_THRESHOLD",
-		"ESCALATION_ENABLED", "ESCALATION_AFTER", "ESCALATION_TO_EXEC", "ESCALATION_TO_EXEC_AFTER",
-		"TASK_TIMEOUT_JUNIOR", "TASK_TIMEOUT_SENIOR", "TASK_TIMEOUT_EXECUTIVE",
-		"WORKER_HEALTH_CHECK_INTERVAL", "WORKER_CRASH_EXIT_CODE",
+		"ESCALATION_ENABLED", "ESCALATION_AFTER", "ESCALATION_TO_EXEC", "ESCALATION_TO_EXEC_AFTER", "ESCALATION_DIFF_MAX",
+		"TASK_TIMEOUT_JUNIOR", "TASK_TIMEOUT_SENIOR", "TASK_TIMEOUT_EXECUTIVE", "TASK_TIMEOUT_FROM_HISTORY",
+		"WORKER_HEALTH_CHECK_INTERVAL", "WORKER_CRASH_EXIT_CODE", "WORKER_SHUTDOWN_GRACE",
+		"WORKER_STALL_TIMEOUT_JUNIOR", "WORKER_STALL_TIMEOUT_SENIOR", "WORKER_STALL_TIMEOUT_EXECUTIVE",
+		"RATE_LIMIT_BACKOFF", "RATE_LIMIT_MAX_WAIT", "EXEC_CACHE_TTL",
 		"REVIEW_ENABLED", "REVIEW_JUNIOR_ONLY",
-		"PHASE_REVIEW_ENABLED", "PHASE_REVIEW_AFTER", "PHASE_REVIEW_ACTION",
+		"PHASE_REVIEW_ENABLED", "PHASE_REVIEW_AFTER", "PHASE_REVIEW_ACTION", "PHASE_REVIEW_TIMEOUT",
+		"UNBLOCK_ANALYSIS", "UNBLOCK_ANALYSIS_TIMEOUT",
+		"HUMAN_REVIEW_QUEUE", "HUMAN_REVIEW_CONFIDENCE", "HUMAN_REVIEW_ITERATIONS",
 		"CONTEXT_ISOLATION", "STATE_FILE",
+		"STATE_SCRUB_AFTER_DAYS", "STATE_SCRUB_COMPLETED",
+		"ARTIFACTS_KEEP_ATTEMPTS", "ARTIFACTS_MAX_AGE_DAYS",
 		"KNOWLEDGE_SHARING", "LEARNINGS_FILE", "BACKLOG_FILE", "LEARNINGS_MAX", "LEARNINGS_ARCHIVE",
 		"MAX_PARALLEL", "AUTO_CONTINUE", "PHASE_GATE",
-		"WALKAWAY_MODE", "WALKAWAY_MAX_SKIPS", "WALKAWAY_DECISION_TIMEOUT", "WALKAWAY_SCOPE_DECISIONS",
+		"INBOX_AUTO_QUEUE", "INBOX_POLL_INTERVAL",
+		"WALKAWAY_MODE", "WALKAWAY_MAX_SKIPS", "WALKAWAY_DECISION_TIMEOUT", "WALKAWAY_SCOPE_DECISIONS", "WALKAWAY_MAX_DURATION",
+		"ANOMALY_DETECTION", "ANOMALY_WINDOW", "ANOMALY_ESCALATION_RATE", "ANOMALY_REPEATED_FAILURES",
+		"ANOMALY_DIFF_FACTOR", "ANOMALY_BURN_RATE",
 		"LOCK_HEARTBEAT_INTERVAL", "SERVICE_IDLE_THRESHOLD", "SERVICE_IDLE_ACTION",
 		"MAX_ITERATIONS",
+		"RECORD_FILE", "REPLAY_FILE", "DETERMINISTIC", "DETERMINISTIC_SEED",
+		"CHAOS_MODE", "CHAOS_CRASH_RATE", "CHAOS_TIMEOUT_RATE", "CHAOS_MALFORMED_RATE", "CHAOS_VERIFY_RATE",
 	}
 
 	for _, key := range envVars {
@@ -412,9 +656,9 @@ func (c *Config) loadFromEnv() {
 		}
 	}
 
-	// Collect MODULE_* config
+	// Collect MODULE_* config, leaving out Brigade's own module settings
 	for _, env := range os.Environ() {
-		if strings.HasPrefix(env, "MODULE_") && !strings.HasPrefix(env, "MODULE_TIMEOUT") && !strings.HasPrefix(env, "MODULE_TERMINAL_BELL") {
+		if strings.HasPrefix(env, "MODULE_") && !isModuleSetting(env) {
 			parts := strings.SplitN(env, "=", 2)
 			if len(parts) == 2 {
 				c.ModuleConfig[parts[0]] = parts[1]
@@ -423,6 +667,17 @@ func (c *Config) loadFromEnv() {
 	}
 }
 
+// isModuleSetting reports whether a KEY=value env entry is one of Brigade's
+// MODULE_* settings rather than a module's own config.
+func isModuleSetting(env string) bool {
+	for _, key := range []string{"MODULE_TIMEOUT", "MODULE_TERMINAL_BELL", "MODULE_SANDBOX", "MODULE_CPU_LIMIT", "MODULE_MEMORY_LIMIT"} {
+		if strings.HasPrefix(env, key+"=") {
+			return true
+		}
+	}
+	return false
+}
+
 // setValue sets a config value by key name.
 func (c *Config) setValue(key, value string) {
 	switch key {
@@ -437,6 +692,8 @@ func (c *Config) setValue(key, value string) {
 		c.SupervisorPRDScoped = parseBool(value)
 	case "MODULE_TERMINAL_BELL":
 		c.ModuleTerminalBell = parseBool(value)
+	case "MODULE_SANDBOX":
+		c.ModuleSandbox = parseBool(value)
 	case "RISK_REPORT_ENABLED":
 		c.RiskReportEnabled = parseBool(value)
 	case "RISK_HISTORY_SCAN":
@@ -445,6 +702,10 @@ func (c *Config) setValue(key, value string) {
 		c.VerificationEnabled = parseBool(value)
 	case "TODO_SCAN_ENABLED":
 		c.TodoScanEnabled = parseBool(value)
+	case "SECRET_SCAN_ENABLED":
+		c.SecretScanEnabled = parseBool(value)
+	case "LICENSE_HEADER_FIX":
+		c.LicenseHeaderFix = parseBool(value)
 	case "VERIFICATION_WARN_GREP_ONLY":
 		c.VerificationWarnGrepOnly = parseBool(value)
 	case "MANUAL_VERIFICATION_ENABLED":
@@ -463,24 +724,36 @@ func (c *Config) setValue(key, value string) {
 		c.EscalationEnabled = parseBool(value)
 	case "ESCALATION_TO_EXEC":
 		c.EscalationToExec = parseBool(value)
+	case "TASK_TIMEOUT_FROM_HISTORY":
+		c.TaskTimeoutFromHistory = parseBool(value)
 	case "REVIEW_ENABLED":
 		c.ReviewEnabled = parseBool(value)
 	case "REVIEW_JUNIOR_ONLY":
 		c.ReviewJuniorOnly = parseBool(value)
 	case "PHASE_REVIEW_ENABLED":
 		c.PhaseReviewEnabled = parseBool(value)
+	case "UNBLOCK_ANALYSIS":
+		c.UnblockAnalysis = parseBool(value)
+	case "HUMAN_REVIEW_QUEUE":
+		c.HumanReviewQueue = parseBool(value)
 	case "CONTEXT_ISOLATION":
 		c.ContextIsolation = parseBool(value)
+	case "STATE_SCRUB_COMPLETED":
+		c.StateScrubCompleted = parseBool(value)
 	case "KNOWLEDGE_SHARING":
 		c.KnowledgeSharing = parseBool(value)
 	case "LEARNINGS_ARCHIVE":
 		c.LearningsArchive = parseBool(value)
 	case "AUTO_CONTINUE":
 		c.AutoContinue = parseBool(value)
+	case "INBOX_AUTO_QUEUE":
+		c.InboxAutoQueue = parseBool(value)
 	case "WALKAWAY_MODE":
 		c.WalkawayMode = parseBool(value)
 	case "WALKAWAY_SCOPE_DECISIONS":
 		c.WalkawayScopeDecisions = parseBool(value)
+	case "ANOMALY_DETECTION":
+		c.AnomalyDetection = parseBool(value)
 
 	// Strings
 	case "OPENCODE_MODEL":
@@ -497,6 +770,38 @@ func (c *Config) setValue(key, value string) {
 		c.LineCmd = value
 	case "LINE_AGENT":
 		c.LineAgent = value
+	case "LINE_CREDENTIALS":
+		c.LineCredentials = value
+	case "SOUS_CREDENTIALS":
+		c.SousCredentials = value
+	case "EXECUTIVE_CREDENTIALS":
+		c.ExecutiveCredentials = value
+	case "BEDROCK_REGION":
+		c.BedrockRegion = value
+	case "BEDROCK_PROFILE":
+		c.BedrockProfile = value
+	case "AZURE_OPENAI_RESOURCE":
+		c.AzureOpenAIResource = value
+	case "LOCAL_MODEL_URL":
+		c.LocalModelURL = value
+	case "GATEWAY_URL":
+		c.GatewayURL = value
+	case "GATEWAY_API_KEY":
+		c.GatewayAPIKey = value
+	case "GATEWAY_HEADERS":
+		c.GatewayHeaders = value
+	case "OFFLINE":
+		c.Offline = parseBool(value)
+	case "OFFLINE_ALLOWED_HOSTS":
+		c.OfflineAllowedHosts = value
+	case "MCP_CONFIG":
+		c.MCPConfig = value
+	case "LINE_MCP_CONFIG":
+		c.LineMCPConfig = value
+	case "SOUS_MCP_CONFIG":
+		c.SousMCPConfig = value
+	case "EXECUTIVE_MCP_CONFIG":
+		c.ExecutiveMCPConfig = value
 	case "OPENCODE_SERVER":
 		c.OpenCodeServer = value
 	case "ACTIVITY_LOG":
@@ -509,12 +814,52 @@ func (c *Config) setValue(key, value string) {
 		c.SupervisorEventsFile = value
 	case "SUPERVISOR_CMD_FILE":
 		c.SupervisorCmdFile = value
+	case "SUPERVISOR_TASK_FALLBACK":
+		c.SupervisorTaskFallback = value
+	case "SUPERVISOR_GATE_FALLBACK":
+		c.SupervisorGateFallback = value
 	case "RISK_WARN_THRESHOLD":
 		c.RiskWarnThreshold = value
+	case "OPERATOR_TOKENS_FILE":
+		c.OperatorTokensFile = value
+	case "AUDIT_LOG":
+		c.AuditLog = value
 	case "DEFAULT_BRANCH":
 		c.DefaultBranch = value
+	case "COMMIT_CONVENTION":
+		c.CommitConvention = value
+	case "COMMIT_SIGNOFF":
+		c.CommitSignoff = parseBool(value)
+	case "COMMIT_GPG_SIGN":
+		c.CommitGPGSign = parseBool(value)
+	case "PRD_WORKTREE":
+		c.PRDWorktree = parseBool(value)
+	case "WORKTREE_DIR":
+		c.WorktreeDir = value
+	case "MERGE_ASSIST":
+		c.MergeAssist = parseBool(value)
+	case "MERGE_TRIVIAL_LINES":
+		c.MergeTrivialLines = parseInt(value)
+	case "MODULE_MEMORY_LIMIT":
+		c.ModuleMemoryLimit = parseInt(value)
+	case "WORKSPACE_CONFINE_EDITS":
+		c.WorkspaceConfineEdits = parseBool(value)
+	case "RECORD_FILE":
+		c.RecordFile = value
+	case "REPLAY_FILE":
+		c.ReplayFile = value
+	case "DETERMINISTIC":
+		c.Deterministic = parseBool(value)
+	case "DETERMINISTIC_SEED":
+		c.DeterministicSeed = parseInt(value)
 	case "TEST_CMD":
 		c.TestCmd = value
+	case "TEST_GATE":
+		c.TestGate = value
+	case "BUILD_CMD":
+		c.BuildCmd = value
+	case "TEST_GATE_EVERY":
+		c.TestGateEvery = parseInt(value)
 	case "SMART_RETRY_CUSTOM_PATTERNS":
 		c.SmartRetryCustomPatterns = value
 	case "SMART_RETRY_STRATEGIES_FILE":
@@ -529,12 +874,48 @@ func (c *Config) setValue(key, value string) {
 		c.PhaseGate = value
 	case "PHASE_REVIEW_ACTION":
 		c.PhaseReviewAction = value
+	case "HUMAN_REVIEW_CONFIDENCE":
+		c.HumanReviewConfidence = value
 
 	// Integers
 	case "MAP_STALE_COMMITS":
 		c.MapStaleCommits = parseInt(value)
+	case "TASK_FILES_MAX_BYTES":
+		c.TaskFilesMaxBytes = parseInt(value)
+	case "CHEF_PROMPT_MAX_TOKENS":
+		c.ChefPromptMaxTokens = parseInt(value)
+	case "PREP_COOK_ENABLED":
+		c.PrepCookEnabled = parseBool(value)
+	case "PREP_COOK_CMD":
+		c.PrepCookCmd = value
+	case "PREP_COOK_MAX_BYTES":
+		c.PrepCookMaxBytes = parseInt(value)
+	case "PREP_COOK_TIMEOUT":
+		c.PrepCookTimeout = parseDurationSeconds(value)
+	case "INDEX_TOP_K":
+		c.IndexTopK = parseInt(value)
+	case "EMBEDDING_PROVIDER":
+		c.EmbeddingProvider = value
+	case "EMBEDDING_MODEL":
+		c.EmbeddingModel = value
+	case "EMBEDDING_URL":
+		c.EmbeddingURL = value
 	case "CROSS_PRD_MAX_RELATED":
 		c.CrossPRDMaxRelated = parseInt(value)
+	case "VERIFICATION_FLAKY_RETRIES":
+		c.VerificationFlakyRetries = parseInt(value)
+	case "SERVICES_CONFIG":
+		c.ServicesConfig = value
+	case "LICENSE_HEADER_FILE":
+		c.LicenseHeaderFile = value
+	case "SERVICE_READY_TIMEOUT":
+		c.ServiceReadyTimeout = parseDurationSeconds(value)
+	case "BROWSER_TEST_CMD":
+		c.BrowserTestCmd = value
+	case "BROWSER_TIMEOUT":
+		c.BrowserTimeout = parseDurationSeconds(value)
+	case "BROWSER_RESULTS_DIR":
+		c.BrowserResultsDir = value
 	case "SMART_RETRY_APPROACH_HISTORY_MAX":
 		c.SmartRetryApproachHistoryMax = parseInt(value)
 	case "SMART_RETRY_SESSION_FAILURES_MAX":
@@ -545,16 +926,34 @@ func (c *Config) setValue(key, value string) {
 		c.EscalationAfter = parseInt(value)
 	case "ESCALATION_TO_EXEC_AFTER":
 		c.EscalationToExecAfter = parseInt(value)
+	case "ESCALATION_DIFF_MAX":
+		c.EscalationDiffMax = parseInt(value)
 	case "WORKER_CRASH_EXIT_CODE":
 		c.WorkerCrashExitCode = parseInt(value)
+	case "HUMAN_REVIEW_ITERATIONS":
+		c.HumanReviewIterations = parseInt(value)
 	case "PHASE_REVIEW_AFTER":
 		c.PhaseReviewAfter = parseInt(value)
 	case "LEARNINGS_MAX":
 		c.LearningsMax = parseInt(value)
+	case "STATE_SCRUB_AFTER_DAYS":
+		c.StateScrubAfterDays = parseInt(value)
+	case "ARTIFACTS_KEEP_ATTEMPTS":
+		c.ArtifactsKeepAttempts = parseInt(value)
+	case "ARTIFACTS_MAX_AGE_DAYS":
+		c.ArtifactsMaxAgeDays = parseInt(value)
 	case "MAX_PARALLEL":
 		c.MaxParallel = parseInt(value)
 	case "WALKAWAY_MAX_SKIPS":
 		c.WalkawayMaxSkips = parseInt(value)
+	case "ANOMALY_WINDOW":
+		c.AnomalyWindow = parseInt(value)
+	case "ANOMALY_ESCALATION_RATE":
+		c.AnomalyEscalationRate = parseInt(value)
+	case "ANOMALY_REPEATED_FAILURES":
+		c.AnomalyRepeatedFailures = parseInt(value)
+	case "ANOMALY_DIFF_FACTOR":
+		c.AnomalyDiffFactor = parseInt(value)
 	case "MAX_ITERATIONS":
 		c.MaxIterations = parseInt(value)
 
@@ -567,6 +966,20 @@ func (c *Config) setValue(key, value string) {
 		c.CostRateExecutive = parseFloat(value)
 	case "COST_WARN_THRESHOLD":
 		c.CostWarnThreshold = parseFloat(value)
+	case "ANOMALY_BURN_RATE":
+		c.AnomalyBurnRate = parseFloat(value)
+	case "PRICING_FILE":
+		c.PricingFile = value
+	case "CHAOS_MODE":
+		c.ChaosMode = parseBool(value)
+	case "CHAOS_CRASH_RATE":
+		c.ChaosCrashRate = parseFloat(value)
+	case "CHAOS_TIMEOUT_RATE":
+		c.ChaosTimeoutRate = parseFloat(value)
+	case "CHAOS_MALFORMED_RATE":
+		c.ChaosMalformedRate = parseFloat(value)
+	case "CHAOS_VERIFY_RATE":
+		c.ChaosVerifyRate = parseFloat(value)
 
 	// Durations (in seconds unless specified)
 	case "ACTIVITY_LOG_INTERVAL":
@@ -577,14 +990,26 @@ func (c *Config) setValue(key, value string) {
 		c.TaskTimeoutWarningSenior = parseDurationMinutes(value)
 	case "STATUS_WATCH_INTERVAL":
 		c.StatusWatchInterval = parseDurationSeconds(value)
+	case "INBOX_POLL_INTERVAL":
+		c.InboxPollInterval = parseDurationSeconds(value)
 	case "SUPERVISOR_CMD_POLL_INTERVAL":
 		c.SupervisorCmdPollInterval = parseDurationSeconds(value)
 	case "SUPERVISOR_CMD_TIMEOUT":
 		c.SupervisorCmdTimeout = parseDurationSeconds(value)
 	case "MODULE_TIMEOUT":
 		c.ModuleTimeout = parseDurationSeconds(value)
+	case "MODULE_CPU_LIMIT":
+		c.ModuleCPULimit = parseDurationSeconds(value)
 	case "TEST_TIMEOUT":
 		c.TestTimeout = parseDurationSeconds(value)
+	case "BUILD_TIMEOUT":
+		c.BuildTimeout = parseDurationSeconds(value)
+	case "TASK_HOOK_TIMEOUT":
+		c.TaskHookTimeout = parseDurationSeconds(value)
+	case "PRE_COMMIT_HOOKS":
+		c.PreCommitHooks = value
+	case "PRE_COMMIT_TIMEOUT":
+		c.PreCommitTimeout = parseDurationSeconds(value)
 	case "VERIFICATION_TIMEOUT":
 		c.VerificationTimeout = parseDurationSeconds(value)
 	case "TASK_TIMEOUT_JUNIOR":
@@ -593,10 +1018,30 @@ func (c *Config) setValue(key, value string) {
 		c.TaskTimeoutSenior = parseDurationSeconds(value)
 	case "TASK_TIMEOUT_EXECUTIVE":
 		c.TaskTimeoutExecutive = parseDurationSeconds(value)
+	case "PHASE_REVIEW_TIMEOUT":
+		c.PhaseReviewTimeout = parseDurationSeconds(value)
+	case "UNBLOCK_ANALYSIS_TIMEOUT":
+		c.UnblockAnalysisTimeout = parseDurationSeconds(value)
 	case "WORKER_HEALTH_CHECK_INTERVAL":
 		c.WorkerHealthCheckInterval = parseDurationSeconds(value)
+	case "WORKER_SHUTDOWN_GRACE":
+		c.WorkerShutdownGrace = parseDurationSeconds(value)
+	case "RATE_LIMIT_BACKOFF":
+		c.RateLimitBackoff = parseDurationSeconds(value)
+	case "RATE_LIMIT_MAX_WAIT":
+		c.RateLimitMaxWait = parseDurationSeconds(value)
+	case "EXEC_CACHE_TTL":
+		c.ExecCacheTTL = parseDurationSeconds(value)
+	case "WORKER_STALL_TIMEOUT_JUNIOR":
+		c.WorkerStallTimeoutJunior = parseDurationSeconds(value)
+	case "WORKER_STALL_TIMEOUT_SENIOR":
+		c.WorkerStallTimeoutSenior = parseDurationSeconds(value)
+	case "WORKER_STALL_TIMEOUT_EXECUTIVE":
+		c.WorkerStallTimeoutExecutive = parseDurationSeconds(value)
 	case "WALKAWAY_DECISION_TIMEOUT":
 		c.WalkawayDecisionTimeout = parseDurationSeconds(value)
+	case "WALKAWAY_MAX_DURATION":
+		c.WalkawayMaxDuration = parseDurationOrMinutes(value)
 	case "LOCK_HEARTBEAT_INTERVAL":
 		c.LockHeartbeatInterval = parseDurationSeconds(value)
 	case "SERVICE_IDLE_THRESHOLD":
@@ -614,6 +1059,39 @@ func (c *Config) setValue(key, value string) {
 				c.Modules[i] = strings.TrimSpace(c.Modules[i])
 			}
 		}
+	case "SECRET_SCAN_IGNORE":
+		if value != "" {
+			c.SecretScanIgnore = strings.Split(value, ",")
+			for i := range c.SecretScanIgnore {
+				c.SecretScanIgnore[i] = strings.TrimSpace(c.SecretScanIgnore[i])
+			}
+		}
+	case "APPROVAL_REQUIRED":
+		if value != "" {
+			c.ApprovalRequired = strings.Split(value, ",")
+			for i := range c.ApprovalRequired {
+				c.ApprovalRequired[i] = strings.TrimSpace(c.ApprovalRequired[i])
+			}
+		}
+	case "OPERATORS":
+		if value != "" {
+			c.Operators = strings.Split(value, ",")
+			for i := range c.Operators {
+				c.Operators[i] = strings.TrimSpace(c.Operators[i])
+			}
+		}
+	case "PROTECTED_PATHS":
+		if value != "" {
+			c.ProtectedPaths = strings.Split(value, ",")
+			for i := range c.ProtectedPaths {
+				c.ProtectedPaths[i] = strings.TrimSpace(c.ProtectedPaths[i])
+			}
+		}
+	default:
+		// A module's own config, like MODULE_SLACK_TIMEOUT
+		if strings.HasPrefix(key, "MODULE_") && !isModuleSetting(key+"=") {
+			c.ModuleConfig[key] = value
+		}
 	}
 }
 
@@ -628,6 +1106,18 @@ func (c *Config) Validate() []string {
 		c.PhaseGate = "continue"
 	}
 
+	// Validate supervisor decision fallbacks
+	validTaskFallbacks := map[string]bool{"executive": true, "skip": true, "pause": true}
+	if !validTaskFallbacks[c.SupervisorTaskFallback] {
+		warnings = append(warnings, fmt.Sprintf("SUPERVISOR_TASK_FALLBACK '%s' invalid, using 'executive'", c.SupervisorTaskFallback))
+		c.SupervisorTaskFallback = "executive"
+	}
+	validGateFallbacks := map[string]bool{"pause": true, "continue": true, "abort": true}
+	if !validGateFallbacks[c.SupervisorGateFallback] {
+		warnings = append(warnings, fmt.Sprintf("SUPERVISOR_GATE_FALLBACK '%s' invalid, using 'pause'", c.SupervisorGateFallback))
+		c.SupervisorGateFallback = "pause"
+	}
+
 	// Validate phase review action
 	validActions := map[string]bool{"continue": true, "pause": true, "remediate": true}
 	if !validActions[c.PhaseReviewAction] {
@@ -635,13 +1125,79 @@ func (c *Config) Validate() []string {
 		c.PhaseReviewAction = "continue"
 	}
 
+	// Validate commit convention
+	if c.CommitConvention != "default" && c.CommitConvention != "conventional" {
+		warnings = append(warnings, fmt.Sprintf("COMMIT_CONVENTION '%s' invalid, using 'default'", c.CommitConvention))
+		c.CommitConvention = "default"
+	}
+
+	// Validate merge assist
+	if c.MergeTrivialLines < 0 {
+		warnings = append(warnings, fmt.Sprintf("MERGE_TRIVIAL_LINES %d invalid, using 20", c.MergeTrivialLines))
+		c.MergeTrivialLines = 20
+	}
+
+	// Validate inbox
+	if c.InboxPollInterval <= 0 {
+		warnings = append(warnings, fmt.Sprintf("INBOX_POLL_INTERVAL %v invalid, using 5s", c.InboxPollInterval))
+		c.InboxPollInterval = 5 * time.Second
+	}
+
+	// Validate module limits
+	if c.ModuleCPULimit < 0 {
+		warnings = append(warnings, fmt.Sprintf("MODULE_CPU_LIMIT %v invalid, using 0 (no limit)", c.ModuleCPULimit))
+		c.ModuleCPULimit = 0
+	}
+	if c.ModuleMemoryLimit < 0 {
+		warnings = append(warnings, fmt.Sprintf("MODULE_MEMORY_LIMIT %d invalid, using 0 (no limit)", c.ModuleMemoryLimit))
+		c.ModuleMemoryLimit = 0
+	}
+
+	// Validate approvals
+	validApprovals := map[string]bool{"force-unlock": true, "accept-risk": true, "accept-cost": true, "protected-paths": true}
+	var required []string
+	for _, action := range c.ApprovalRequired {
+		if !validApprovals[action] {
+			warnings = append(warnings, fmt.Sprintf("APPROVAL_REQUIRED action '%s' unknown, ignoring", action))
+			continue
+		}
+		required = append(required, action)
+	}
+	c.ApprovalRequired = required
+	if len(c.ApprovalRequired) > 0 && c.AuditLog == "" {
+		warnings = append(warnings, "AUDIT_LOG empty with APPROVAL_REQUIRED set, using 'brigade/audit.jsonl'")
+		c.AuditLog = "brigade/audit.jsonl"
+	}
+
+	// Validate human review confidence
+	validConfidence := map[string]bool{"off": true, "low": true, "medium": true}
+	if !validConfidence[c.HumanReviewConfidence] {
+		warnings = append(warnings, fmt.Sprintf("HUMAN_REVIEW_CONFIDENCE '%s' invalid, using 'low'", c.HumanReviewConfidence))
+		c.HumanReviewConfidence = "low"
+	}
+
 	// Validate risk threshold
-	validRisks := map[string]bool{"": true, "low": true, "medium": true, "high": true}
+	validRisks := map[string]bool{"": true, "low": true, "medium": true, "high": true, "critical": true}
 	if !validRisks[c.RiskWarnThreshold] {
 		warnings = append(warnings, fmt.Sprintf("RISK_WARN_THRESHOLD '%s' invalid, disabling", c.RiskWarnThreshold))
 		c.RiskWarnThreshold = ""
 	}
 
+	// Validate test gate
+	validTestGates := map[string]bool{"off": true, "task": true, "every": true, "phase": true}
+	if !validTestGates[c.TestGate] {
+		warnings = append(warnings, fmt.Sprintf("TEST_GATE '%s' invalid, using 'off'", c.TestGate))
+		c.TestGate = "off"
+	}
+	if c.TestGate != "off" && c.TestCmd == "" {
+		warnings = append(warnings, "TEST_GATE is set but TEST_CMD is empty, disabling the test gate")
+		c.TestGate = "off"
+	}
+	if c.TestGateEvery < 1 {
+		warnings = append(warnings, "TEST_GATE_EVERY must be >= 1, using 5")
+		c.TestGateEvery = 5
+	}
+
 	// Validate service idle action
 	validIdleActions := map[string]bool{"warn": true, "abort": true, "heal": true}
 	if !validIdleActions[c.ServiceIdleAction] {
@@ -670,6 +1226,29 @@ func (c *Config) Validate() []string {
 		c.MaxIterations = 50
 	}
 
+	if c.Deterministic && c.DeterministicSeed == 0 {
+		warnings = append(warnings, "DETERMINISTIC_SEED can't be 0, using 1")
+		c.DeterministicSeed = 1
+	}
+
+	if c.ChaosMode {
+		warnings = append(warnings, "CHAOS_MODE is enabled: worker failures will be injected")
+		for _, rate := range []struct {
+			key string
+			val *float64
+		}{
+			{"CHAOS_CRASH_RATE", &c.ChaosCrashRate},
+			{"CHAOS_TIMEOUT_RATE", &c.ChaosTimeoutRate},
+			{"CHAOS_MALFORMED_RATE", &c.ChaosMalformedRate},
+			{"CHAOS_VERIFY_RATE", &c.ChaosVerifyRate},
+		} {
+			if *rate.val < 0 || *rate.val > 1 {
+				warnings = append(warnings, fmt.Sprintf("%s must be between 0 and 1, using 0", rate.key))
+				*rate.val = 0
+			}
+		}
+	}
+
 	return warnings
 }
 
@@ -704,3 +1283,12 @@ func parseDurationMinutes(s string) time.Duration {
 	i := parseInt(s)
 	return time.Duration(i) * time.Minute
 }
+
+// parseDurationOrMinutes accepts a duration like "4h" or "90m", or a bare
+// number of minutes.
+func parseDurationOrMinutes(s string) time.Duration {
+	if d, err := time.ParseDuration(strings.TrimSpace(s)); err == nil {
+		return d
+	}
+	return parseDurationMinutes(s)
+}
@@ -0,0 +1,291 @@
+// Package cost estimates what running a PRD costs.
+package cost
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"brigade/internal/config"
+	"brigade/internal/prd"
+	"brigade/internal/state"
+	"brigade/internal/util"
+)
+
+// priorWeight is how many observed tasks the up-front estimate for a
+// complexity counts as when blended with what this run has measured.
+const priorWeight = 3
+
+// Rates are the per-minute prices of each worker tier in USD, used for
+// work whose tokens aren't known or whose model isn't in the pricing table.
+type Rates struct {
+	Line      float64
+	Sous      float64
+	Executive float64
+}
+
+// RatesFromConfig returns the COST_RATE_* settings.
+func RatesFromConfig(cfg *config.Config) Rates {
+	return Rates{Line: cfg.CostRateLine, Sous: cfg.CostRateSous, Executive: cfg.CostRateExecutive}
+}
+
+// For returns the rate for a tier.
+func (r Rates) For(tier state.WorkerTier) float64 {
+	switch tier {
+	case state.TierSous:
+		return r.Sous
+	case state.TierExecutive:
+		return r.Executive
+	default:
+		return r.Line
+	}
+}
+
+// TaskMinutes returns the up-front guess of a task's worker time: ~5
+// minutes for junior, ~15 for senior.
+func TaskMinutes(task *prd.Task) float64 {
+	if task.Complexity == prd.ComplexitySenior {
+		return 15
+	}
+	return 5
+}
+
+// taskTier returns the tier a task starts on.
+func taskTier(task *prd.Task) state.WorkerTier {
+	if task.Complexity == prd.ComplexitySenior {
+		return state.TierSous
+	}
+	return state.TierLine
+}
+
+// taskClass groups tasks by what they cost; anything not senior runs as junior.
+func taskClass(task *prd.Task) prd.Complexity {
+	if task.Complexity == prd.ComplexitySenior {
+		return prd.ComplexitySenior
+	}
+	return prd.ComplexityJunior
+}
+
+// Estimator prices work with a pricing table, falling back to per-minute
+// rates.
+type Estimator struct {
+	Pricing *Pricing
+	Rates   Rates
+	models  map[state.WorkerTier]string
+}
+
+// NewEstimator builds an estimator from PRICING_FILE and the worker
+// commands. If the pricing file can't be read it returns an estimator
+// using the built-in table along with the error.
+func NewEstimator(cfg *config.Config) (*Estimator, error) {
+	pricing, err := LoadPricing(cfg.PricingFile)
+	e := &Estimator{
+		Pricing: pricing,
+		Rates:   RatesFromConfig(cfg),
+		models: map[state.WorkerTier]string{
+			state.TierLine:      modelFromCommand(cfg.LineCmd),
+			state.TierSous:      modelFromCommand(cfg.SousCmd),
+			state.TierExecutive: modelFromCommand(cfg.ExecutiveCmd),
+		},
+	}
+	for tier, model := range pricing.Tiers {
+		e.models[state.WorkerTier(tier)] = model
+	}
+	return e, err
+}
+
+// Model returns the model a tier runs, "" if unknown.
+func (e *Estimator) Model(tier state.WorkerTier) string {
+	return e.models[tier]
+}
+
+// price returns the per-token price of a tier's model.
+func (e *Estimator) price(tier state.WorkerTier) (ModelPrice, bool) {
+	return e.Pricing.Price(e.models[tier])
+}
+
+// historyTokens returns the tokens an attempt reported.
+func historyTokens(h state.TaskHistory) Tokens {
+	return Tokens{Input: float64(h.InputTokens), CacheRead: float64(h.CacheReadTokens), Output: float64(h.OutputTokens)}
+}
+
+// AttemptCost prices a recorded attempt by its tokens, or by its duration
+// if it reported none or its model has no price.
+func (e *Estimator) AttemptCost(h state.TaskHistory) float64 {
+	if tokens := historyTokens(h); tokens.Total() > 0 {
+		if price, ok := e.price(h.Worker); ok {
+			return price.Cost(tokens)
+		}
+	}
+	return float64(h.Duration) / 60 * e.Rates.For(h.Worker)
+}
+
+// TaskEstimate returns the expected cost of a task before it runs, and
+// what the estimate is based on. Token counts learned from earlier runs
+// come first, then their average cost, then the pricing table's token
+// estimate, then minutes at the tier's rate.
+func (e *Estimator) TaskEstimate(task *prd.Task, hist History) (float64, string) {
+	class := taskClass(task)
+	tier := taskTier(task)
+	price, priced := e.price(tier)
+	h := hist[class]
+
+	if h != nil && h.TokenCount > 0 && priced {
+		tokens := h.Tokens.scale(1 / float64(h.TokenCount))
+		return price.Cost(tokens), fmt.Sprintf("~%s tokens on %s, from %d earlier tasks", formatTokens(tokens.Total()), e.models[tier], h.TokenCount)
+	}
+	if h != nil && h.Count > 0 {
+		return h.Total / float64(h.Count), fmt.Sprintf("average of %d earlier tasks", h.Count)
+	}
+	if tokens, ok := e.Pricing.Tokens[string(class)]; ok && priced {
+		return price.Cost(tokens), fmt.Sprintf("~%s tokens on %s", formatTokens(tokens.Total()), e.models[tier])
+	}
+	rate := e.Rates.For(tier)
+	return TaskMinutes(task) * rate, fmt.Sprintf("~%.0fmin @ %s/min", TaskMinutes(task), util.FormatCost(rate))
+}
+
+// formatTokens abbreviates a token count: 1.2M, 340k, 800.
+func formatTokens(n float64) string {
+	switch {
+	case n >= 1e6:
+		return fmt.Sprintf("%.1fM", n/1e6)
+	case n >= 1e3:
+		return fmt.Sprintf("%.0fk", n/1e3)
+	default:
+		return fmt.Sprintf("%.0f", n)
+	}
+}
+
+// Sample is what a number of completed tasks cost. Tokens only cover the
+// TokenCount tasks whose every attempt reported usage.
+type Sample struct {
+	Total      float64
+	Count      int
+	Tokens     Tokens
+	TokenCount int
+}
+
+// History is what completed tasks cost in earlier runs, by complexity.
+// Each task's cost includes its retries and escalations.
+type History map[prd.Complexity]*Sample
+
+// add records a completed task.
+func (h History) add(class prd.Complexity, t taskSpend) {
+	if h[class] == nil {
+		h[class] = &Sample{}
+	}
+	s := h[class]
+	s.Total += t.cost
+	s.Count++
+	if t.tokensKnown {
+		s.Tokens.add(t.tokens)
+		s.TokenCount++
+	}
+}
+
+// LoadHistory collects completed task costs from the state files in dir,
+// skipping the state of the PRD at exclude. State files whose PRD is gone
+// are ignored.
+func LoadHistory(dir, exclude string, e *Estimator) History {
+	hist := make(History)
+	eachRun(dir, exclude, func(p *prd.PRD, st *state.State) {
+		spent := e.taskSpend(st)
+		for id := range st.CompletedTaskIDs() {
+			if task := p.TaskByID(id); task != nil {
+				hist.add(taskClass(task), spent[id])
+			}
+		}
+	})
+	return hist
+}
+
+// eachRun calls fn with the PRD and state of every state file in dir,
+// skipping the PRD at exclude and state files whose PRD is gone.
+func eachRun(dir, exclude string, fn func(p *prd.PRD, st *state.State)) {
+	paths, _ := filepath.Glob(filepath.Join(dir, "*.state.json"))
+	for _, path := range paths {
+		prdPath := strings.TrimSuffix(path, ".state.json") + ".json"
+		if exclude != "" && filepath.Clean(prdPath) == filepath.Clean(exclude) {
+			continue
+		}
+		p, err := prd.Load(prdPath)
+		if err != nil {
+			continue
+		}
+		st, err := state.NewStore(path).Load()
+		if err != nil {
+			continue
+		}
+		fn(p, st)
+	}
+}
+
+// taskSpend is what a task's attempts have cost so far.
+type taskSpend struct {
+	cost        float64
+	tokens      Tokens
+	tokensKnown bool // Every attempt reported its tokens
+}
+
+// taskSpend totals the recorded attempts of each task.
+func (e *Estimator) taskSpend(st *state.State) map[string]taskSpend {
+	spend := make(map[string]taskSpend)
+	for _, h := range st.TaskHistory {
+		t, seen := spend[h.TaskID]
+		if !seen {
+			t.tokensKnown = true
+		}
+		t.cost += e.AttemptCost(h)
+		tokens := historyTokens(h)
+		t.tokens.add(tokens)
+		t.tokensKnown = t.tokensKnown && tokens.Total() > 0
+		spend[h.TaskID] = t
+	}
+	return spend
+}
+
+// Projection is the expected cost of a PRD run in USD.
+type Projection struct {
+	Spent     float64 `json:"spent"`     // Work recorded so far
+	Remaining float64 `json:"remaining"` // Expected for unfinished tasks
+	Total     float64 `json:"total"`
+}
+
+// Project estimates the total cost of a PRD. Unfinished tasks are priced
+// at the average cost of completed tasks of the same complexity: the
+// up-front estimate (see TaskEstimate) counts as a few tasks, and each task
+// this run completed counts as one, so the projection follows the run as it
+// goes. Work already spent on an unfinished task is subtracted from what it
+// is still expected to cost.
+func Project(p *prd.PRD, st *state.State, hist History, e *Estimator) Projection {
+	spent := e.taskSpend(st)
+	completed := st.CompletedTaskIDs()
+
+	run := make(History)
+	for i := range p.Tasks {
+		task := &p.Tasks[i]
+		if completed[task.ID] {
+			run.add(taskClass(task), spent[task.ID])
+		}
+	}
+
+	var proj Projection
+	for _, t := range spent {
+		proj.Spent += t.cost
+	}
+	for i := range p.Tasks {
+		task := &p.Tasks[i]
+		if completed[task.ID] || task.Passes {
+			continue
+		}
+		expected, _ := e.TaskEstimate(task, hist)
+		if r := run[taskClass(task)]; r != nil {
+			expected = (expected*priorWeight + r.Total) / float64(priorWeight+r.Count)
+		}
+		if left := expected - spent[task.ID].cost; left > 0 {
+			proj.Remaining += left
+		}
+	}
+	proj.Total = proj.Spent + proj.Remaining
+	return proj
+}
@@ -0,0 +1,137 @@
+package cost
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"brigade/internal/config"
+	"brigade/internal/prd"
+	"brigade/internal/state"
+)
+
+var testRates = Rates{Line: 0.05, Sous: 0.15, Executive: 0.30}
+
+// minutesEstimator prices everything by minutes: no tier has a known model.
+func minutesEstimator() *Estimator {
+	return &Estimator{Pricing: DefaultPricing(), Rates: testRates, models: map[state.WorkerTier]string{}}
+}
+
+func testPRD() *prd.PRD {
+	return &prd.PRD{Tasks: []prd.Task{
+		{ID: "US-001", Complexity: prd.ComplexityJunior},
+		{ID: "US-002", Complexity: prd.ComplexityJunior},
+		{ID: "US-003", Complexity: prd.ComplexitySenior},
+	}}
+}
+
+func TestProject(t *testing.T) {
+	tests := []struct {
+		name    string
+		history []state.TaskHistory
+		hist    History
+		want    Projection
+	}{
+		{
+			name: "up-front estimate",
+			want: Projection{Spent: 0, Remaining: 2*0.25 + 2.25, Total: 2.75},
+		},
+		{
+			// Junior tasks cost 0.5 this run: (0.25*3 + 0.5) / 4
+			name: "blends this run",
+			history: []state.TaskHistory{
+				{TaskID: "US-001", Worker: state.TierLine, Status: state.StatusComplete, Duration: 600},
+			},
+			want: Projection{Spent: 0.5, Remaining: 0.3125 + 2.25, Total: 3.0625},
+		},
+		{
+			// History replaces the up-front estimate for senior tasks
+			name: "uses history",
+			hist: History{prd.ComplexitySenior: {Total: 8, Count: 2}},
+			want: Projection{Spent: 0, Remaining: 2*0.25 + 4, Total: 4.5},
+		},
+		{
+			// US-003 already spent 1.5 of its expected 2.25 on a failed attempt
+			name: "subtracts work in progress",
+			history: []state.TaskHistory{
+				{TaskID: "US-003", Worker: state.TierSous, Status: state.StatusFailed, Duration: 600},
+			},
+			want: Projection{Spent: 1.5, Remaining: 2*0.25 + 0.75, Total: 2.75},
+		},
+	}
+
+	for _, tt := range tests {
+		st := state.New()
+		st.TaskHistory = tt.history
+		got := Project(testPRD(), st, tt.hist, minutesEstimator())
+		if !near(got.Spent, tt.want.Spent) || !near(got.Remaining, tt.want.Remaining) || !near(got.Total, tt.want.Total) {
+			t.Errorf("%s: Project() = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLoadHistory(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"prd-a", "prd-b"} {
+		if err := testPRD().Save(filepath.Join(dir, name+".json")); err != nil {
+			t.Fatal(err)
+		}
+		st := state.New()
+		st.AddTaskHistory(state.TaskHistory{TaskID: "US-003", Worker: state.TierSous, Status: state.StatusComplete, Duration: 1200})
+		if err := state.NewStore(filepath.Join(dir, name+".state.json")).Save(st); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A state file without its PRD is ignored
+	os.WriteFile(filepath.Join(dir, "prd-gone.state.json"), []byte(`{"taskHistory":[]}`), 0644)
+
+	hist := LoadHistory(dir, filepath.Join(dir, "prd-b.json"), minutesEstimator())
+	senior := hist[prd.ComplexitySenior]
+	if senior == nil || senior.Count != 1 || !near(senior.Total, 3) {
+		t.Errorf("LoadHistory() senior = %+v, want 1 task costing 3", senior)
+	}
+	if hist[prd.ComplexityJunior] != nil {
+		t.Errorf("LoadHistory() junior = %+v, want none", hist[prd.ComplexityJunior])
+	}
+}
+
+func TestTokenPricing(t *testing.T) {
+	cfg := config.Default()
+	cfg.PricingFile = filepath.Join(t.TempDir(), "pricing.json")
+	cfg.LineCmd = "claude --model claude-sonnet-4-5"
+	cfg.SousCmd = "opencode run --model=acme/big-1"
+	os.WriteFile(cfg.PricingFile, []byte(`{"models": {"big": {"input": 2, "output": 10}}, "tokens": {"senior": {"input": 100000, "output": 10000}}}`), 0644)
+
+	e, err := NewEstimator(cfg)
+	if err != nil {
+		t.Fatalf("NewEstimator() error = %v", err)
+	}
+	if e.Model(state.TierLine) != "claude-sonnet-4-5" || e.Model(state.TierSous) != "acme/big-1" {
+		t.Errorf("models = %q, %q, want claude-sonnet-4-5, acme/big-1", e.Model(state.TierLine), e.Model(state.TierSous))
+	}
+
+	// Reported tokens on sonnet: 1M input at $3, 100k cache reads at $0.30, 100k output at $15
+	attempt := state.TaskHistory{Worker: state.TierLine, Duration: 600, InputTokens: 1000000, CacheReadTokens: 100000, OutputTokens: 100000}
+	if got := e.AttemptCost(attempt); !near(got, 3+0.03+1.5) {
+		t.Errorf("AttemptCost(tokens) = %v, want 4.53", got)
+	}
+	// No tokens: 10 minutes at the line rate
+	attempt = state.TaskHistory{Worker: state.TierLine, Duration: 600}
+	if got := e.AttemptCost(attempt); !near(got, 0.5) {
+		t.Errorf("AttemptCost(no tokens) = %v, want 0.5", got)
+	}
+
+	senior := &prd.Task{ID: "US-001", Complexity: prd.ComplexitySenior}
+	if got, _ := e.TaskEstimate(senior, nil); !near(got, 0.2+0.1) {
+		t.Errorf("TaskEstimate(table) = %v, want 0.3", got)
+	}
+	hist := History{prd.ComplexitySenior: {Total: 9, Count: 3, Tokens: Tokens{Input: 600000, Output: 30000}, TokenCount: 3}}
+	if got, _ := e.TaskEstimate(senior, hist); !near(got, 0.4+0.1) {
+		t.Errorf("TaskEstimate(learned tokens) = %v, want 0.5", got)
+	}
+}
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
@@ -0,0 +1,151 @@
+package cost
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ModelPrice is what a model charges in USD per million tokens.
+type ModelPrice struct {
+	Input     float64 `json:"input"`
+	Output    float64 `json:"output"`
+	CacheRead float64 `json:"cacheRead,omitempty"` // Defaults to the input price
+}
+
+// Tokens counts (or estimates) the tokens a task uses.
+type Tokens struct {
+	Input     float64 `json:"input"`
+	CacheRead float64 `json:"cacheRead,omitempty"`
+	Output    float64 `json:"output"`
+}
+
+func (t *Tokens) add(o Tokens) {
+	t.Input += o.Input
+	t.CacheRead += o.CacheRead
+	t.Output += o.Output
+}
+
+func (t Tokens) scale(f float64) Tokens {
+	return Tokens{Input: t.Input * f, CacheRead: t.CacheRead * f, Output: t.Output * f}
+}
+
+// Total returns the number of tokens of every kind.
+func (t Tokens) Total() float64 {
+	return t.Input + t.CacheRead + t.Output
+}
+
+// Cost prices tokens at this model's rates.
+func (m ModelPrice) Cost(t Tokens) float64 {
+	cacheRead := m.CacheRead
+	if cacheRead == 0 {
+		cacheRead = m.Input
+	}
+	return (t.Input*m.Input + t.CacheRead*cacheRead + t.Output*m.Output) / 1e6
+}
+
+// Pricing is the table cost estimates are made from: model prices, which
+// model each tier runs, and how many tokens a task of each complexity
+// uses before history says otherwise.
+type Pricing struct {
+	Models map[string]ModelPrice `json:"models"`
+	Tiers  map[string]string     `json:"tiers,omitempty"` // line/sous/executive; default from the worker command's --model
+	Tokens map[string]Tokens     `json:"tokens"`          // junior/senior
+}
+
+// DefaultPricing returns the built-in pricing table.
+func DefaultPricing() *Pricing {
+	return &Pricing{
+		Models: map[string]ModelPrice{
+			"opus":   {Input: 5, Output: 25, CacheRead: 0.5},
+			"sonnet": {Input: 3, Output: 15, CacheRead: 0.3},
+			"haiku":  {Input: 1, Output: 5, CacheRead: 0.1},
+		},
+		Tiers: map[string]string{},
+		Tokens: map[string]Tokens{
+			"junior": {Input: 40000, CacheRead: 300000, Output: 8000},
+			"senior": {Input: 120000, CacheRead: 1000000, Output: 25000},
+		},
+	}
+}
+
+// LoadPricing returns the built-in table with the file at path laid over
+// it: its models, tiers and token estimates replace built-in ones of the
+// same name. A missing file is not an error.
+func LoadPricing(path string) (*Pricing, error) {
+	pricing := DefaultPricing()
+	if path == "" {
+		return pricing, nil
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return pricing, nil
+	}
+	if err != nil {
+		return pricing, err
+	}
+	var file Pricing
+	if err := json.Unmarshal(data, &file); err != nil {
+		return pricing, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	for name, price := range file.Models {
+		pricing.Models[name] = price
+	}
+	for tier, model := range file.Tiers {
+		pricing.Tiers[tier] = model
+	}
+	for complexity, tokens := range file.Tokens {
+		pricing.Tokens[complexity] = tokens
+	}
+	return pricing, nil
+}
+
+// Write saves the table as JSON, for editing.
+func (p *Pricing) Write(path string) error {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// Price looks up a model's price: an exact match, or else the longest
+// table entry the model name contains ("claude-sonnet-4-5" is "sonnet").
+func (p *Pricing) Price(model string) (ModelPrice, bool) {
+	model = strings.ToLower(model)
+	if model == "" {
+		return ModelPrice{}, false
+	}
+	if price, ok := p.Models[model]; ok {
+		return price, true
+	}
+	best := ""
+	for name := range p.Models {
+		if strings.Contains(model, strings.ToLower(name)) && len(name) > len(best) {
+			best = name
+		}
+	}
+	if best == "" {
+		return ModelPrice{}, false
+	}
+	return p.Models[best], true
+}
+
+// modelFromCommand returns the --model (or -m) argument of a worker command.
+func modelFromCommand(command string) string {
+	args := strings.Fields(command)
+	for i, arg := range args {
+		if value, ok := strings.CutPrefix(arg, "--model="); ok {
+			return value
+		}
+		if (arg == "--model" || arg == "-m") && i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,165 @@
+package cost
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"brigade/internal/prd"
+	"brigade/internal/state"
+)
+
+// MinStatSamples is how many completed tasks a group needs before its
+// percentiles replace the built-in guesses.
+const MinStatSamples = 5
+
+// minTimeout keeps a suggested timeout from cutting off a worker whose
+// earlier attempts were all quick.
+const minTimeout = 5 * time.Minute
+
+// Distribution summarizes a set of observations by percentile.
+type Distribution struct {
+	Count int     `json:"count"`
+	P50   float64 `json:"p50"`
+	P90   float64 `json:"p90"`
+	Max   float64 `json:"max"`
+}
+
+// newDistribution summarizes values.
+func newDistribution(values []float64) Distribution {
+	if len(values) == 0 {
+		return Distribution{}
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	return Distribution{
+		Count: len(sorted),
+		P50:   percentile(sorted, 50),
+		P90:   percentile(sorted, 90),
+		Max:   sorted[len(sorted)-1],
+	}
+}
+
+// percentile returns the p-th percentile of sorted values, interpolating
+// between the nearest ranks.
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := p / 100 * float64(len(sorted)-1)
+	lo, hi := int(math.Floor(rank)), int(math.Ceil(rank))
+	return sorted[lo] + (sorted[hi]-sorted[lo])*(rank-float64(lo))
+}
+
+// GroupStats is how long completed tasks took and how many attempts they
+// needed.
+type GroupStats struct {
+	Minutes        Distribution `json:"minutes"`        // Worker time per task, across its attempts
+	AttemptMinutes Distribution `json:"attemptMinutes"` // Worker time per attempt
+	Iterations     Distribution `json:"iterations"`     // Attempts per task
+}
+
+// Stats breaks down the completed tasks of earlier runs by complexity and
+// by the worker command that completed them.
+type Stats struct {
+	Complexity map[prd.Complexity]*GroupStats `json:"complexity"`
+	Command    map[string]*GroupStats         `json:"command"`
+}
+
+// statSamples collects the raw observations behind a GroupStats.
+type statSamples struct {
+	minutes, attemptMinutes, iterations []float64
+}
+
+func (s *statSamples) stats() *GroupStats {
+	return &GroupStats{
+		Minutes:        newDistribution(s.minutes),
+		AttemptMinutes: newDistribution(s.attemptMinutes),
+		Iterations:     newDistribution(s.iterations),
+	}
+}
+
+// LoadStats collects the percentiles of completed tasks from the state
+// files in dir, skipping the state of the PRD at exclude. Absorbed tasks
+// did no work of their own and aren't counted.
+func LoadStats(dir, exclude string) *Stats {
+	byClass := make(map[prd.Complexity]*statSamples)
+	byCommand := make(map[string]*statSamples)
+
+	eachRun(dir, exclude, func(p *prd.PRD, st *state.State) {
+		attempts := make(map[string][]state.TaskHistory)
+		for _, h := range st.TaskHistory {
+			if h.Status != state.StatusSkipped && h.Status != state.StatusAbsorbed {
+				attempts[h.TaskID] = append(attempts[h.TaskID], h)
+			}
+		}
+		for id, hs := range attempts {
+			last := hs[len(hs)-1]
+			task := p.TaskByID(id)
+			if task == nil || last.Status != state.StatusComplete {
+				continue
+			}
+			class := samplesFor(byClass, taskClass(task))
+			seconds := 0
+			for _, h := range hs {
+				seconds += h.Duration
+				minutes := float64(h.Duration) / 60
+				class.attemptMinutes = append(class.attemptMinutes, minutes)
+				command := samplesFor(byCommand, CommandLabel(h))
+				command.attemptMinutes = append(command.attemptMinutes, minutes)
+			}
+			for _, s := range []*statSamples{class, samplesFor(byCommand, CommandLabel(last))} {
+				s.minutes = append(s.minutes, float64(seconds)/60)
+				s.iterations = append(s.iterations, float64(len(hs)))
+			}
+		}
+	})
+
+	stats := &Stats{Complexity: make(map[prd.Complexity]*GroupStats), Command: make(map[string]*GroupStats)}
+	for class, s := range byClass {
+		stats.Complexity[class] = s.stats()
+	}
+	for command, s := range byCommand {
+		stats.Command[command] = s.stats()
+	}
+	return stats
+}
+
+// samplesFor returns the samples of a group, adding it if it's new.
+func samplesFor[K comparable](groups map[K]*statSamples, key K) *statSamples {
+	if groups[key] == nil {
+		groups[key] = &statSamples{}
+	}
+	return groups[key]
+}
+
+// CommandLabel names the worker command an attempt ran, or its tier for
+// attempts recorded before commands were.
+func CommandLabel(h state.TaskHistory) string {
+	if h.Command != "" {
+		return h.Command
+	}
+	return "(" + string(h.Worker) + " tier)"
+}
+
+// TaskMinutes returns a task's expected worker time: the median of earlier
+// tasks of its complexity once there are enough of them, otherwise the
+// up-front guess (see TaskMinutes).
+func (s *Stats) TaskMinutes(task *prd.Task) float64 {
+	if g := s.Complexity[taskClass(task)]; g != nil && g.Minutes.Count >= MinStatSamples {
+		return g.Minutes.P50
+	}
+	return TaskMinutes(task)
+}
+
+// SuggestedTimeout returns a worker timeout for a command: twice the p90
+// of its attempts, but never under minTimeout. Returns 0 until the command
+// has MinStatSamples attempts.
+func (s *Stats) SuggestedTimeout(command string) time.Duration {
+	g := s.Command[command]
+	if g == nil || g.AttemptMinutes.Count < MinStatSamples {
+		return 0
+	}
+	timeout := time.Duration(2 * g.AttemptMinutes.P90 * float64(time.Minute)).Round(time.Minute)
+	return max(timeout, minTimeout)
+}
@@ -0,0 +1,102 @@
+package cost
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"brigade/internal/prd"
+	"brigade/internal/state"
+)
+
+func TestPercentile(t *testing.T) {
+	tests := []struct {
+		values []float64
+		p      float64
+		want   float64
+	}{
+		{nil, 50, 0},
+		{[]float64{4}, 90, 4},
+		{[]float64{1, 2, 3, 4, 5}, 50, 3},
+		{[]float64{1, 2, 3, 4}, 50, 2.5},
+		{[]float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}, 90, 10},
+		{[]float64{1, 2, 3, 4, 5}, 100, 5},
+	}
+	for _, tt := range tests {
+		if got := percentile(tt.values, tt.p); !near(got, tt.want) {
+			t.Errorf("percentile(%v, %v) = %v, want %v", tt.values, tt.p, got, tt.want)
+		}
+	}
+}
+
+// statsDir writes a run whose junior tasks each took one failed 10-minute
+// attempt and one complete 20-minute attempt, and whose senior task failed.
+func statsDir(t *testing.T, juniors int) string {
+	t.Helper()
+	dir := t.TempDir()
+	p := &prd.PRD{Tasks: []prd.Task{{ID: "US-900", Complexity: prd.ComplexitySenior}}}
+	st := state.New()
+	for i := range juniors {
+		id := fmt.Sprintf("US-%03d", i+1)
+		p.Tasks = append(p.Tasks, prd.Task{ID: id, Complexity: prd.ComplexityJunior})
+		st.AddTaskHistory(state.TaskHistory{TaskID: id, Worker: state.TierLine, Command: "claude", Status: state.StatusFailed, Duration: 600})
+		st.AddTaskHistory(state.TaskHistory{TaskID: id, Worker: state.TierSous, Status: state.StatusComplete, Duration: 1200})
+	}
+	st.AddTaskHistory(state.TaskHistory{TaskID: "US-900", Worker: state.TierSous, Status: state.StatusFailed, Duration: 600})
+	if err := p.Save(filepath.Join(dir, "prd-a.json")); err != nil {
+		t.Fatal(err)
+	}
+	if err := state.NewStore(filepath.Join(dir, "prd-a.state.json")).Save(st); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestLoadStats(t *testing.T) {
+	stats := LoadStats(statsDir(t, 5), "")
+
+	junior := stats.Complexity[prd.ComplexityJunior]
+	if junior == nil || junior.Minutes.Count != 5 || !near(junior.Minutes.P50, 30) || !near(junior.Iterations.P90, 2) {
+		t.Errorf("LoadStats() junior = %+v, want 5 tasks of 30 minutes over 2 attempts", junior)
+	}
+	if junior != nil && (junior.AttemptMinutes.Count != 10 || !near(junior.AttemptMinutes.P50, 15)) {
+		t.Errorf("LoadStats() junior attempts = %+v, want 10 with p50 15", junior.AttemptMinutes)
+	}
+	if s := stats.Complexity[prd.ComplexitySenior]; s != nil {
+		t.Errorf("LoadStats() senior = %+v, want none (no completed tasks)", s)
+	}
+	// Task times go to the command that completed the task
+	if c := stats.Command["claude"]; c == nil || c.Minutes.Count != 0 || c.AttemptMinutes.Count != 5 {
+		t.Errorf("LoadStats() claude = %+v, want 5 attempts and no completed tasks", c)
+	}
+	if c := stats.Command["(sous tier)"]; c == nil || c.Minutes.Count != 5 {
+		t.Errorf("LoadStats() (sous tier) = %+v, want 5 completed tasks", c)
+	}
+
+	if got := stats.SuggestedTimeout("(sous tier)"); got != 40*time.Minute {
+		t.Errorf("SuggestedTimeout() = %v, want 40m", got)
+	}
+	if got := stats.SuggestedTimeout("codex"); got != 0 {
+		t.Errorf("SuggestedTimeout(unknown) = %v, want 0", got)
+	}
+}
+
+func TestStatsTaskMinutes(t *testing.T) {
+	junior := &prd.Task{ID: "US-001", Complexity: prd.ComplexityJunior}
+	tests := []struct {
+		name    string
+		juniors int
+		want    float64
+	}{
+		{"too few samples", MinStatSamples - 1, TaskMinutes(junior)},
+		{"from history", MinStatSamples, 30},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := LoadStats(statsDir(t, tt.juniors), "").TaskMinutes(junior); !near(got, tt.want) {
+				t.Errorf("TaskMinutes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,259 @@
+// Package deps finds outdated project dependencies and drafts upgrade PRDs.
+package deps
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Ecosystem identifies a package manager.
+type Ecosystem string
+
+const (
+	EcosystemGo     Ecosystem = "go"
+	EcosystemNode   Ecosystem = "node"
+	EcosystemPython Ecosystem = "python"
+)
+
+// manifests maps each ecosystem to the file that declares its dependencies.
+var manifests = map[Ecosystem]string{
+	EcosystemGo:     "go.mod",
+	EcosystemNode:   "package.json",
+	EcosystemPython: "requirements.txt",
+}
+
+// requirementName matches the package name at the start of a requirements line.
+var requirementName = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9._-]*)`)
+
+// Dependency is a direct dependency with a newer version available.
+type Dependency struct {
+	Name      string
+	Current   string
+	Latest    string
+	Ecosystem Ecosystem
+	Manifest  string
+}
+
+// Major reports whether the upgrade crosses a major version, or a minor
+// version below 1.0, where semver allows breaking changes.
+func (d Dependency) Major() bool {
+	cur, latest := versionParts(d.Current), versionParts(d.Latest)
+	if cur[0] != latest[0] {
+		return true
+	}
+	return cur[0] == 0 && cur[1] != latest[1]
+}
+
+// Detect returns the ecosystems whose manifests exist in dir.
+func Detect(dir string) []Ecosystem {
+	var found []Ecosystem
+	for _, eco := range []Ecosystem{EcosystemGo, EcosystemNode, EcosystemPython} {
+		if _, err := os.Stat(filepath.Join(dir, manifests[eco])); err == nil {
+			found = append(found, eco)
+		}
+	}
+	return found
+}
+
+// Outdated lists direct dependencies of an ecosystem that have newer
+// versions, using the ecosystem's own tooling.
+func Outdated(ctx context.Context, dir string, eco Ecosystem) ([]Dependency, error) {
+	switch eco {
+	case EcosystemGo:
+		out, err := run(ctx, dir, "go", "list", "-m", "-u", "-json", "all")
+		if err != nil {
+			return nil, err
+		}
+		return ParseGoList(out)
+
+	case EcosystemNode:
+		// npm outdated exits 1 when anything is outdated
+		out, err := run(ctx, dir, "npm", "outdated", "--json")
+		if err != nil && len(bytes.TrimSpace(out)) == 0 {
+			return nil, err
+		}
+		return ParseNpmOutdated(out)
+
+	case EcosystemPython:
+		names, err := requirementNames(filepath.Join(dir, manifests[eco]))
+		if err != nil {
+			return nil, err
+		}
+		out, err := run(ctx, dir, "pip", "list", "--outdated", "--format=json")
+		if err != nil {
+			return nil, err
+		}
+		return ParsePipOutdated(out, names)
+	}
+	return nil, fmt.Errorf("unsupported ecosystem: %s", eco)
+}
+
+// run executes a command in dir and returns its stdout.
+func run(ctx context.Context, dir, name string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			return out, fmt.Errorf("%s %s: %w", name, strings.Join(args, " "), err)
+		}
+		return out, fmt.Errorf("%s %s: %w: %s", name, strings.Join(args, " "), err, msg)
+	}
+	return out, nil
+}
+
+// ParseGoList parses `go list -m -u -json all` output, keeping direct
+// dependencies with an available update.
+func ParseGoList(output []byte) ([]Dependency, error) {
+	type module struct {
+		Path     string
+		Version  string
+		Main     bool
+		Indirect bool
+		Update   *struct{ Version string }
+	}
+
+	var deps []Dependency
+	dec := json.NewDecoder(bytes.NewReader(output))
+	for {
+		var m module
+		if err := dec.Decode(&m); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("parsing go list output: %w", err)
+		}
+		if m.Main || m.Indirect || m.Update == nil {
+			continue
+		}
+		deps = append(deps, Dependency{
+			Name:      m.Path,
+			Current:   m.Version,
+			Latest:    m.Update.Version,
+			Ecosystem: EcosystemGo,
+			Manifest:  manifests[EcosystemGo],
+		})
+	}
+	return deps, nil
+}
+
+// ParseNpmOutdated parses `npm outdated --json` output.
+func ParseNpmOutdated(output []byte) ([]Dependency, error) {
+	if len(bytes.TrimSpace(output)) == 0 {
+		return nil, nil
+	}
+	var entries map[string]struct {
+		Current string `json:"current"`
+		Latest  string `json:"latest"`
+	}
+	if err := json.Unmarshal(output, &entries); err != nil {
+		return nil, fmt.Errorf("parsing npm outdated output: %w", err)
+	}
+
+	var deps []Dependency
+	for name, e := range entries {
+		// Packages declared but not installed have no current version
+		if e.Current == "" || e.Current == e.Latest {
+			continue
+		}
+		deps = append(deps, Dependency{
+			Name:      name,
+			Current:   e.Current,
+			Latest:    e.Latest,
+			Ecosystem: EcosystemNode,
+			Manifest:  manifests[EcosystemNode],
+		})
+	}
+	sort.Slice(deps, func(i, j int) bool { return deps[i].Name < deps[j].Name })
+	return deps, nil
+}
+
+// ParsePipOutdated parses `pip list --outdated --format=json` output,
+// keeping packages named in requirements. pip lists everything installed,
+// including transitive and unrelated packages.
+func ParsePipOutdated(output []byte, requirements map[string]bool) ([]Dependency, error) {
+	var entries []struct {
+		Name          string `json:"name"`
+		Version       string `json:"version"`
+		LatestVersion string `json:"latest_version"`
+	}
+	if err := json.Unmarshal(output, &entries); err != nil {
+		return nil, fmt.Errorf("parsing pip list output: %w", err)
+	}
+
+	var deps []Dependency
+	for _, e := range entries {
+		if !requirements[normalizeName(e.Name)] {
+			continue
+		}
+		deps = append(deps, Dependency{
+			Name:      e.Name,
+			Current:   e.Version,
+			Latest:    e.LatestVersion,
+			Ecosystem: EcosystemPython,
+			Manifest:  manifests[EcosystemPython],
+		})
+	}
+	return deps, nil
+}
+
+// requirementNames reads the normalized package names from a requirements file.
+func requirementNames(path string) (map[string]bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	names := make(map[string]bool)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "-") {
+			continue
+		}
+		if m := requirementName.FindString(line); m != "" {
+			names[normalizeName(m)] = true
+		}
+	}
+	return names, scanner.Err()
+}
+
+// normalizeName applies PEP 503 name normalization.
+func normalizeName(name string) string {
+	name = strings.ToLower(name)
+	return strings.NewReplacer("_", "-", ".", "-").Replace(name)
+}
+
+// versionParts returns the major and minor numbers of a version such as
+// "v1.2.3", "^2.0.0" or "1.4". Missing parts are 0.
+func versionParts(v string) [2]int {
+	v = strings.TrimLeft(v, "v^~=<>! ")
+	var parts [2]int
+	for i, field := range strings.SplitN(v, ".", 3) {
+		if i == 2 {
+			break
+		}
+		end := 0
+		for end < len(field) && field[end] >= '0' && field[end] <= '9' {
+			end++
+		}
+		parts[i], _ = strconv.Atoi(field[:end])
+	}
+	return parts
+}
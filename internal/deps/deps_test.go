@@ -0,0 +1,93 @@
+package deps
+
+import (
+	"testing"
+)
+
+func TestMajor(t *testing.T) {
+	tests := []struct {
+		current, latest string
+		want            bool
+	}{
+		{"v1.2.3", "v1.4.0", false},
+		{"v1.2.3", "v2.0.0", true},
+		{"0.3.1", "0.4.0", true},
+		{"0.3.1", "0.3.9", false},
+		{"^4.17.1", "5.0.0", true},
+		{"2.31", "2.32.3", false},
+	}
+	for _, tt := range tests {
+		d := Dependency{Current: tt.current, Latest: tt.latest}
+		if got := d.Major(); got != tt.want {
+			t.Errorf("Major(%s → %s) = %v, want %v", tt.current, tt.latest, got, tt.want)
+		}
+	}
+}
+
+func TestParseGoList(t *testing.T) {
+	output := `{"Path": "example.com/app", "Main": true}
+{"Path": "github.com/spf13/cobra", "Version": "v1.8.0", "Update": {"Path": "github.com/spf13/cobra", "Version": "v1.9.1"}}
+{"Path": "github.com/spf13/pflag", "Version": "v1.0.5", "Indirect": true, "Update": {"Version": "v1.0.6"}}
+{"Path": "golang.org/x/sync", "Version": "v0.7.0"}
+`
+	deps, err := ParseGoList([]byte(output))
+	if err != nil {
+		t.Fatalf("ParseGoList() error = %v", err)
+	}
+	if len(deps) != 1 || deps[0].Name != "github.com/spf13/cobra" || deps[0].Latest != "v1.9.1" {
+		t.Errorf("ParseGoList() = %+v, want cobra v1.9.1 only", deps)
+	}
+}
+
+func TestParseNpmOutdated(t *testing.T) {
+	output := `{
+  "react": {"current": "17.0.2", "wanted": "17.0.2", "latest": "18.3.1"},
+  "lodash": {"current": "4.17.20", "wanted": "4.17.21", "latest": "4.17.21"},
+  "left-pad": {"wanted": "1.3.0", "latest": "1.3.0"}
+}`
+	deps, err := ParseNpmOutdated([]byte(output))
+	if err != nil {
+		t.Fatalf("ParseNpmOutdated() error = %v", err)
+	}
+	if len(deps) != 2 || deps[0].Name != "lodash" || deps[1].Name != "react" {
+		t.Errorf("ParseNpmOutdated() = %+v, want lodash and react", deps)
+	}
+}
+
+func TestParsePipOutdated(t *testing.T) {
+	output := `[{"name": "Django", "version": "4.2.1", "latest_version": "5.1.2"},
+{"name": "urllib3", "version": "1.26.0", "latest_version": "2.2.3"}]`
+	deps, err := ParsePipOutdated([]byte(output), map[string]bool{"django": true})
+	if err != nil {
+		t.Fatalf("ParsePipOutdated() error = %v", err)
+	}
+	if len(deps) != 1 || deps[0].Name != "Django" {
+		t.Errorf("ParsePipOutdated() = %+v, want Django only", deps)
+	}
+}
+
+func TestUpgradePRD(t *testing.T) {
+	deps := []Dependency{
+		{Name: "lodash", Current: "4.17.20", Latest: "4.17.21", Ecosystem: EcosystemNode, Manifest: "package.json"},
+		{Name: "react", Current: "17.0.2", Latest: "18.3.1", Ecosystem: EcosystemNode, Manifest: "package.json"},
+		{Name: "express", Current: "4.19.0", Latest: "5.0.1", Ecosystem: EcosystemNode, Manifest: "package.json"},
+	}
+	p := UpgradePRD(deps, "", "2026-01-02")
+	if result := p.ValidateQuick(); !result.IsValid() {
+		t.Errorf("generated PRD is invalid: %v", result.Errors)
+	}
+
+	if len(p.Tasks) != 3 {
+		t.Fatalf("UpgradePRD() created %d tasks, want 3", len(p.Tasks))
+	}
+	if p.Tasks[0].Title != "Update lodash to 4.17.21" {
+		t.Errorf("first task = %q, want the batched minor updates", p.Tasks[0].Title)
+	}
+	react := p.Tasks[1]
+	if react.Title != "Upgrade react to 18.3.1" || len(react.DependsOn) != 1 || react.DependsOn[0] != "US-001" {
+		t.Errorf("major task = %q depending on %v", react.Title, react.DependsOn)
+	}
+	if got := react.Verification[len(react.Verification)-1].Cmd; got != "npm test" {
+		t.Errorf("test verification = %q, want npm test", got)
+	}
+}
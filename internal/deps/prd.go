@@ -0,0 +1,132 @@
+package deps
+
+import (
+	"fmt"
+	"strings"
+
+	"brigade/internal/prd"
+)
+
+// testCommands is the test command assumed for each ecosystem when none is
+// configured.
+var testCommands = map[Ecosystem]string{
+	EcosystemGo:     "go test ./...",
+	EcosystemNode:   "npm test",
+	EcosystemPython: "pytest",
+}
+
+// UpgradePRD builds a PRD with one task per major upgrade. Minor and patch
+// updates are batched into a first task that the major upgrades build on.
+// testCmd overrides each ecosystem's default test command when set.
+func UpgradePRD(deps []Dependency, testCmd, date string) *prd.PRD {
+	var minor, major []Dependency
+	for _, d := range deps {
+		if d.Major() {
+			major = append(major, d)
+		} else {
+			minor = append(minor, d)
+		}
+	}
+
+	p := &prd.PRD{
+		FeatureName: "Dependency upgrades " + date,
+		BranchName:  "chore/deps-" + date,
+		Description: fmt.Sprintf("Generated by brigade plan deps: %d major upgrades, %d minor or patch updates.", len(major), len(minor)),
+	}
+
+	var dependsOn []string
+	if len(minor) > 0 {
+		p.Tasks = append(p.Tasks, minorTask("US-001", minor, testCmd))
+		dependsOn = []string{"US-001"}
+	}
+	for _, d := range major {
+		id := fmt.Sprintf("US-%03d", len(p.Tasks)+1)
+		p.Tasks = append(p.Tasks, majorTask(id, d, dependsOn, testCmd))
+	}
+	return p
+}
+
+// minorTask batches updates that shouldn't break the API.
+func minorTask(id string, deps []Dependency, testCmd string) prd.Task {
+	var desc strings.Builder
+	desc.WriteString("Apply minor and patch updates. These shouldn't change APIs; if one does, pin it at its current version and note why.\n\n")
+	criteria := []string{}
+	var checks []prd.Verification
+	ecosystems := make(map[Ecosystem]bool)
+	for _, d := range deps {
+		desc.WriteString(fmt.Sprintf("- %s (%s): %s → %s\n", d.Name, d.Manifest, d.Current, d.Latest))
+		criteria = append(criteria, fmt.Sprintf("%s is updated from %s to %s in %s", d.Name, d.Current, d.Latest, d.Manifest))
+		checks = append(checks, versionCheck(d))
+		ecosystems[d.Ecosystem] = true
+	}
+	criteria = append(criteria, "Lockfiles are regenerated with the package manager, not edited by hand", "All existing tests pass")
+
+	title := fmt.Sprintf("Apply %d minor and patch dependency updates", len(deps))
+	if len(deps) == 1 {
+		title = fmt.Sprintf("Update %s to %s", deps[0].Name, deps[0].Latest)
+	}
+
+	return prd.Task{
+		ID:                 id,
+		Title:              title,
+		Description:        strings.TrimSpace(desc.String()),
+		AcceptanceCriteria: criteria,
+		DependsOn:          []string{},
+		Complexity:         prd.ComplexityJunior,
+		Verification:       append(checks, testChecks(ecosystems, testCmd)...),
+	}
+}
+
+// majorTask upgrades one dependency across a major version.
+func majorTask(id string, d Dependency, dependsOn []string, testCmd string) prd.Task {
+	desc := fmt.Sprintf("Upgrade %s in %s from %s to %s. This crosses a major version: "+
+		"read the changelog or release notes for every release in between, list the breaking changes, "+
+		"and update the code that uses %s. Keep the upgrade to this one dependency.", d.Name, d.Manifest, d.Current, d.Latest, d.Name)
+	if d.Ecosystem == EcosystemGo {
+		desc += " A new Go major version usually has a new module path (e.g. a /vN suffix); update imports to match."
+	}
+
+	return prd.Task{
+		ID:          id,
+		Title:       fmt.Sprintf("Upgrade %s to %s", d.Name, d.Latest),
+		Description: desc,
+		AcceptanceCriteria: []string{
+			fmt.Sprintf("%s is upgraded from %s to %s in %s", d.Name, d.Current, d.Latest, d.Manifest),
+			fmt.Sprintf("Breaking changes in the %s changelog between %s and %s are addressed", d.Name, d.Current, d.Latest),
+			"Deprecated APIs flagged by the upgrade are replaced",
+			"All existing tests pass",
+		},
+		DependsOn:    append([]string{}, dependsOn...),
+		Complexity:   prd.ComplexitySenior,
+		Verification: append([]prd.Verification{versionCheck(d)}, testChecks(map[Ecosystem]bool{d.Ecosystem: true}, testCmd)...),
+	}
+}
+
+// versionCheck confirms the package manager resolves the new version.
+func versionCheck(d Dependency) prd.Verification {
+	var cmd string
+	switch d.Ecosystem {
+	case EcosystemGo:
+		cmd = fmt.Sprintf("go list -m %s | grep -qF ' %s'", d.Name, d.Latest)
+	case EcosystemNode:
+		cmd = fmt.Sprintf("npm ls %s --depth=0 | grep -qF '@%s'", d.Name, d.Latest)
+	case EcosystemPython:
+		cmd = fmt.Sprintf("pip show %s | grep -qxF 'Version: %s'", d.Name, d.Latest)
+	}
+	return prd.Verification{Type: prd.VerificationPattern, Cmd: cmd}
+}
+
+// testChecks returns the test verification for the given ecosystems, or
+// the configured test command if there is one.
+func testChecks(ecosystems map[Ecosystem]bool, testCmd string) []prd.Verification {
+	if testCmd != "" {
+		return []prd.Verification{{Type: prd.VerificationUnit, Cmd: testCmd}}
+	}
+	var checks []prd.Verification
+	for _, eco := range []Ecosystem{EcosystemGo, EcosystemNode, EcosystemPython} {
+		if ecosystems[eco] {
+			checks = append(checks, prd.Verification{Type: prd.VerificationUnit, Cmd: testCommands[eco]})
+		}
+	}
+	return checks
+}
@@ -0,0 +1,295 @@
+// Package learnings moves team learnings and retry strategies between
+// repos as a portable bundle.
+package learnings
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"brigade/internal/classify"
+	"brigade/internal/config"
+)
+
+// BundleVersion is the bundle format this version of brigade writes.
+const BundleVersion = 1
+
+// DefaultStrategiesFile is where imported strategies go when
+// SMART_RETRY_STRATEGIES_FILE isn't set.
+const DefaultStrategiesFile = "brigade/retry-strategies.json"
+
+// Pattern is a custom error pattern and the category it classifies as.
+type Pattern struct {
+	Pattern  string `json:"pattern"`
+	Category string `json:"category"`
+}
+
+// Bundle is a repo's curated learnings and retry strategies.
+type Bundle struct {
+	Version    int               `json:"version"`
+	Exported   time.Time         `json:"exported"`
+	Source     string            `json:"source,omitempty"` // Repo the bundle came from
+	Learnings  []string          `json:"learnings"`
+	Patterns   []Pattern         `json:"patterns,omitempty"`   // SMART_RETRY_CUSTOM_PATTERNS
+	Strategies map[string]string `json:"strategies,omitempty"` // SMART_RETRY_STRATEGIES_FILE
+}
+
+// Split breaks a learnings file into entries, which are separated by blank
+// lines. Repeated entries are dropped.
+func Split(text string) []string {
+	var entries []string
+	seen := make(map[string]bool)
+	for _, block := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n\n") {
+		entry := strings.TrimSpace(block)
+		if entry == "" || seen[key(entry)] {
+			continue
+		}
+		seen[key(entry)] = true
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// key normalizes an entry for duplicate detection: case and spacing
+// don't matter.
+func key(entry string) string {
+	return strings.Join(strings.Fields(strings.ToLower(entry)), " ")
+}
+
+// ParsePatterns parses a SMART_RETRY_CUSTOM_PATTERNS value.
+func ParsePatterns(s string) []Pattern {
+	var patterns []Pattern
+	for _, pair := range strings.Split(s, ",") {
+		parts := strings.SplitN(strings.TrimSpace(pair), ":", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			continue
+		}
+		patterns = append(patterns, Pattern{
+			Pattern:  strings.TrimSpace(parts[0]),
+			Category: strings.TrimSpace(parts[1]),
+		})
+	}
+	return patterns
+}
+
+// FormatPatterns renders patterns as a SMART_RETRY_CUSTOM_PATTERNS value.
+func FormatPatterns(patterns []Pattern) string {
+	pairs := make([]string, len(patterns))
+	for i, p := range patterns {
+		pairs[i] = p.Pattern + ":" + p.Category
+	}
+	return strings.Join(pairs, ",")
+}
+
+// Export bundles the learnings file, custom error patterns and strategy
+// suggestions of cfg. If match is non-empty, only learnings containing
+// one of its terms (case-insensitively) are included.
+func Export(cfg *config.Config, match []string) (*Bundle, error) {
+	b := &Bundle{
+		Version:    BundleVersion,
+		Exported:   time.Now(),
+		Learnings:  []string{},
+		Patterns:   ParsePatterns(cfg.SmartRetryCustomPatterns),
+		Strategies: map[string]string{},
+	}
+	if wd, err := os.Getwd(); err == nil {
+		b.Source = filepath.Base(wd)
+	}
+
+	data, err := os.ReadFile(cfg.LearningsFile)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	for _, entry := range Split(string(data)) {
+		if matches(entry, match) {
+			b.Learnings = append(b.Learnings, entry)
+		}
+	}
+
+	strategies, err := classify.LoadStrategies(cfg.SmartRetryStrategiesFile)
+	if err != nil {
+		return nil, err
+	}
+	for category, suggestion := range strategies {
+		b.Strategies[string(category)] = suggestion
+	}
+	return b, nil
+}
+
+// matches reports whether entry contains any of terms; no terms match
+// everything.
+func matches(entry string, terms []string) bool {
+	if len(terms) == 0 {
+		return true
+	}
+	lower := strings.ToLower(entry)
+	for _, term := range terms {
+		if strings.Contains(lower, strings.ToLower(term)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Load reads a bundle.
+func Load(path string) (*Bundle, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var b Bundle
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if b.Version < 1 || b.Version > BundleVersion {
+		return nil, fmt.Errorf("%s: unsupported bundle version %d", path, b.Version)
+	}
+	return &b, nil
+}
+
+// Write saves the bundle as JSON.
+func (b *Bundle) Write(path string) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// ImportResult describes what an import added.
+type ImportResult struct {
+	Learnings          int // New learnings appended
+	DuplicateLearnings int // Learnings already present
+	Strategies         []string
+	KeptStrategies     []string // Categories whose local suggestion was kept
+	StrategiesFile     string   // Where strategies were written
+	Patterns           int      // New custom error patterns
+	PatternsValue      string   // Merged SMART_RETRY_CUSTOM_PATTERNS, if patterns were added
+}
+
+// Import merges a bundle into cfg's learnings file and strategies file.
+// Entries the repo already has are left alone: duplicate learnings are
+// skipped, and local strategy suggestions win over the bundle's. Custom
+// patterns live in the config file, which Import doesn't edit; the merged
+// value is returned in PatternsValue. With dryRun nothing is written.
+func Import(b *Bundle, cfg *config.Config, dryRun bool) (*ImportResult, error) {
+	result := &ImportResult{}
+
+	// Learnings
+	data, err := os.ReadFile(cfg.LearningsFile)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	existing := make(map[string]bool)
+	for _, entry := range Split(string(data)) {
+		existing[key(entry)] = true
+	}
+	var added []string
+	for _, entry := range b.Learnings {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if existing[key(entry)] {
+			result.DuplicateLearnings++
+			continue
+		}
+		existing[key(entry)] = true
+		added = append(added, entry)
+	}
+	result.Learnings = len(added)
+	if len(added) > 0 && !dryRun {
+		if err := appendLearnings(cfg.LearningsFile, string(data), added); err != nil {
+			return nil, err
+		}
+	}
+
+	// Strategy suggestions
+	result.StrategiesFile = cfg.SmartRetryStrategiesFile
+	if result.StrategiesFile == "" {
+		result.StrategiesFile = DefaultStrategiesFile
+	}
+	strategies, err := classify.LoadStrategies(result.StrategiesFile)
+	if err != nil {
+		return nil, err
+	}
+	for category, suggestion := range b.Strategies {
+		if local, ok := strategies[classify.Category(category)]; ok {
+			if local != suggestion {
+				result.KeptStrategies = append(result.KeptStrategies, category)
+			}
+			continue
+		}
+		strategies[classify.Category(category)] = suggestion
+		result.Strategies = append(result.Strategies, category)
+	}
+	sort.Strings(result.Strategies)
+	sort.Strings(result.KeptStrategies)
+	if len(result.Strategies) > 0 && !dryRun {
+		if err := strategies.Save(result.StrategiesFile); err != nil {
+			return nil, err
+		}
+	}
+
+	// Custom error patterns
+	patterns := ParsePatterns(cfg.SmartRetryCustomPatterns)
+	known := make(map[string]bool)
+	for _, p := range patterns {
+		known[p.Pattern] = true
+	}
+	for _, p := range b.Patterns {
+		if p.Pattern == "" || known[p.Pattern] {
+			continue
+		}
+		known[p.Pattern] = true
+		patterns = append(patterns, p)
+		result.Patterns++
+	}
+	if result.Patterns > 0 {
+		result.PatternsValue = FormatPatterns(patterns)
+	}
+	return result, nil
+}
+
+// appendLearnings appends entries to the learnings file in the format
+// workers' learnings are recorded in.
+func appendLearnings(path, existing string, entries []string) error {
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var sb strings.Builder
+	if existing != "" && !strings.HasSuffix(existing, "\n\n") {
+		sb.WriteString(strings.Repeat("\n", 2-trailingNewlines(existing)))
+	}
+	for _, entry := range entries {
+		sb.WriteString(entry + "\n\n")
+	}
+	_, err = f.WriteString(sb.String())
+	return err
+}
+
+// trailingNewlines counts the newlines s ends with, up to two.
+func trailingNewlines(s string) int {
+	n := 0
+	for n < 2 && strings.HasSuffix(s[:len(s)-n], "\n") {
+		n++
+	}
+	return n
+}
@@ -0,0 +1,78 @@
+package learnings
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"brigade/internal/classify"
+	"brigade/internal/config"
+)
+
+func TestSplit(t *testing.T) {
+	text := "Use t.TempDir in tests.\n\nThe API client\nretries on 429.\n\n\n  use   T.TempDir in tests. \n"
+	want := []string{"Use t.TempDir in tests.", "The API client\nretries on 429."}
+	if got := Split(text); !reflect.DeepEqual(got, want) {
+		t.Errorf("Split() = %q, want %q", got, want)
+	}
+}
+
+func TestExportImport(t *testing.T) {
+	src := config.Default()
+	srcDir := t.TempDir()
+	src.LearningsFile = filepath.Join(srcDir, "learnings.md")
+	src.SmartRetryStrategiesFile = filepath.Join(srcDir, "strategies.json")
+	src.SmartRetryCustomPatterns = "FlakyDB:integration,BadConfig:environment"
+	os.WriteFile(src.LearningsFile, []byte("Run migrations before tests.\n\nThe cache must be warmed.\n\n"), 0644)
+	classify.Strategies{"integration": "Try: Start the database container"}.Save(src.SmartRetryStrategiesFile)
+
+	b, err := Export(src, []string{"MIGRATIONS"})
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	if want := []string{"Run migrations before tests."}; !reflect.DeepEqual(b.Learnings, want) {
+		t.Errorf("Export() learnings = %q, want %q", b.Learnings, want)
+	}
+	bundlePath := filepath.Join(srcDir, "bundle.json")
+	if err := b.Write(bundlePath); err != nil {
+		t.Fatal(err)
+	}
+	b, err = Load(bundlePath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	dst := config.Default()
+	dstDir := t.TempDir()
+	dst.LearningsFile = filepath.Join(dstDir, "learnings.md")
+	dst.SmartRetryStrategiesFile = filepath.Join(dstDir, "strategies.json")
+	dst.SmartRetryCustomPatterns = "FlakyDB:integration"
+	os.WriteFile(dst.LearningsFile, []byte("Local learning."), 0644)
+	b.Learnings = append(b.Learnings, "local  LEARNING.")
+
+	result, err := Import(b, dst, false)
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	if result.Learnings != 1 || result.DuplicateLearnings != 1 {
+		t.Errorf("Import() learnings = %d new, %d duplicate, want 1, 1", result.Learnings, result.DuplicateLearnings)
+	}
+	if want := "FlakyDB:integration,BadConfig:environment"; result.PatternsValue != want {
+		t.Errorf("Import() patterns = %q, want %q", result.PatternsValue, want)
+	}
+	data, _ := os.ReadFile(dst.LearningsFile)
+	if want := "Local learning.\n\nRun migrations before tests.\n\n"; string(data) != want {
+		t.Errorf("learnings file = %q, want %q", data, want)
+	}
+	strategies, _ := classify.LoadStrategies(dst.SmartRetryStrategiesFile)
+	if got := strategies.Suggestion(classify.CategoryIntegration); got != "Try: Start the database container" {
+		t.Errorf("imported integration strategy = %q", got)
+	}
+
+	// A second import adds nothing
+	result, _ = Import(b, dst, false)
+	if result.Learnings != 0 || len(result.Strategies) != 0 {
+		t.Errorf("second Import() = %+v, want nothing new", result)
+	}
+}
@@ -0,0 +1,69 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestServe(t *testing.T) {
+	s := NewServer("brigade", "test")
+	s.AddTool(Tool{
+		Name:        "echo",
+		Description: "Echo the input",
+		InputSchema: Object(map[string]interface{}{"text": String("Text to echo")}, "text"),
+		Handler: func(ctx context.Context, args json.RawMessage) (string, error) {
+			var a struct{ Text string }
+			json.Unmarshal(args, &a)
+			if a.Text == "" {
+				return "", errors.New("text is required")
+			}
+			return a.Text, nil
+		},
+	})
+
+	input := strings.Join([]string{
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"echo","arguments":{"text":"hi"}}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"echo","arguments":{}}}`,
+		`{"jsonrpc":"2.0","id":5,"method":"tools/call","params":{"name":"nope"}}`,
+		`{"jsonrpc":"2.0","id":6,"method":"resources/list"}`,
+		`not json`,
+	}, "\n")
+
+	var out bytes.Buffer
+	if err := s.Serve(context.Background(), strings.NewReader(input), &out); err != nil {
+		t.Fatalf("Serve() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 7 {
+		t.Fatalf("got %d responses, want 7 (notifications get none):\n%s", len(lines), out.String())
+	}
+
+	tests := []struct {
+		name string
+		line int
+		want string
+	}{
+		{"initialize", 0, `"protocolVersion":"2024-11-05"`},
+		{"tools/list", 1, `"inputSchema":{"properties":{"text":{"description":"Text to echo","type":"string"}},"required":["text"],"type":"object"}`},
+		{"tools/call", 2, `"result":{"content":[{"type":"text","text":"hi"}]}`},
+		{"tool error", 3, `"content":[{"type":"text","text":"text is required"}],"isError":true`},
+		{"unknown tool", 4, `"error":{"code":-32602,"message":"unknown tool: nope"}`},
+		{"unknown method", 5, `"error":{"code":-32601`},
+		{"parse error", 6, `"id":null,"error":{"code":-32700`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !strings.Contains(lines[tt.line], tt.want) {
+				t.Errorf("response = %s, want it to contain %s", lines[tt.line], tt.want)
+			}
+		})
+	}
+}
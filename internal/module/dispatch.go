@@ -5,10 +5,7 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
-	"os"
 	"os/exec"
-	"path/filepath"
-	"strings"
 	"sync"
 	"time"
 )
@@ -18,10 +15,13 @@ type Dispatcher struct {
 	modules []*Module
 	timeout time.Duration
 	logger  *slog.Logger
+	env     []string // Additional environment for every module
+	sandbox Sandbox
 
 	// Tracking for cleanup
 	mu       sync.Mutex
 	running  map[*exec.Cmd]bool
+	inflight sync.WaitGroup
 }
 
 // NewDispatcher creates a new event dispatcher.
@@ -49,7 +49,11 @@ func (d *Dispatcher) Dispatch(event *Event) {
 		}
 
 		// Dispatch asynchronously
-		go d.dispatchToModule(module, event)
+		d.inflight.Add(1)
+		go func(m *Module) {
+			defer d.inflight.Done()
+			d.dispatchToModule(m, event)
+		}(module)
 	}
 }
 
@@ -87,10 +91,7 @@ func (d *Dispatcher) DispatchSync(ctx context.Context, event *Event) []error {
 
 // dispatchToModule dispatches an event to a single module asynchronously.
 func (d *Dispatcher) dispatchToModule(module *Module, event *Event) {
-	ctx, cancel := context.WithTimeout(context.Background(), d.timeout)
-	defer cancel()
-
-	if err := d.dispatchToModuleSync(ctx, module, event); err != nil {
+	if err := d.dispatchToModuleSync(context.Background(), module, event); err != nil {
 		d.logger.Warn("module event handler failed",
 			"module", module.Name,
 			"event", event.Type,
@@ -98,18 +99,17 @@ func (d *Dispatcher) dispatchToModule(module *Module, event *Event) {
 	}
 }
 
-// dispatchToModuleSync dispatches an event to a module and waits for completion.
+// dispatchToModuleSync dispatches an event to a module and waits for
+// completion, killing the handler after the module's timeout.
 func (d *Dispatcher) dispatchToModuleSync(ctx context.Context, module *Module, event *Event) error {
-	// Build command
-	cmd := exec.CommandContext(ctx, module.Path, "--event", string(event.Type))
-	cmd.Dir = filepath.Dir(module.Path)
-
-	// Set environment
-	cmd.Env = os.Environ()
-	for key, value := range module.Config {
-		envKey := "MODULE_" + strings.ToUpper(module.Name) + "_" + key
-		cmd.Env = append(cmd.Env, envKey+"="+value)
-	}
+	timeout := d.timeoutFor(module)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// Build command, with the module's config as environment
+	cmd := d.sandbox.command(ctx, module, d.env, "--event", string(event.Type))
+	// Don't wait on children that outlive a killed handler
+	cmd.WaitDelay = time.Second
 
 	// Pass event data as JSON on stdin
 	eventJSON, err := event.JSON()
@@ -136,7 +136,7 @@ func (d *Dispatcher) dispatchToModuleSync(ctx context.Context, module *Module, e
 	// Run the command
 	if err := cmd.Run(); err != nil {
 		if ctx.Err() == context.DeadlineExceeded {
-			return fmt.Errorf("timeout after %v", d.timeout)
+			return fmt.Errorf("timeout after %v", timeout)
 		}
 		stderrStr := stderr.String()
 		if stderrStr != "" {
@@ -148,6 +148,40 @@ func (d *Dispatcher) dispatchToModuleSync(ctx context.Context, module *Module, e
 	return nil
 }
 
+// timeoutFor returns how long a module's handlers may run: its own
+// MODULE_<NAME>_TIMEOUT, else MODULE_TIMEOUT.
+func (d *Dispatcher) timeoutFor(module *Module) time.Duration {
+	if module.Timeout > 0 {
+		return module.Timeout
+	}
+	return d.timeout
+}
+
+// longestTimeout returns the longest any module's handlers may run.
+func (d *Dispatcher) longestTimeout() time.Duration {
+	longest := d.timeout
+	for _, m := range d.modules {
+		if t := d.timeoutFor(m); t > longest {
+			longest = t
+		}
+	}
+	return longest
+}
+
+// Wait waits up to timeout for asynchronous handlers to finish, so final
+// events (e.g. service_complete) are delivered before shutdown.
+func (d *Dispatcher) Wait(timeout time.Duration) {
+	done := make(chan struct{})
+	go func() {
+		d.inflight.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(timeout):
+	}
+}
+
 // Cleanup kills any running module handlers.
 func (d *Dispatcher) Cleanup() {
 	d.mu.Lock()
@@ -196,14 +230,17 @@ func (d *Dispatcher) HasHandlers(eventType EventType) bool {
 type Manager struct {
 	loader     *Loader
 	dispatcher *Dispatcher
+	timeout    time.Duration // Handler timeout for modules without their own
 	logger     *slog.Logger
 }
 
-// NewManager creates a new module manager.
+// NewManager creates a new module manager. Handlers are killed after
+// timeout unless their module sets MODULE_<NAME>_TIMEOUT.
 func NewManager(modulesDir string, config map[string]string, timeout time.Duration, logger *slog.Logger) *Manager {
 	return &Manager{
-		loader: NewLoader(modulesDir, config),
-		logger: logger,
+		loader:  NewLoader(modulesDir, config),
+		timeout: timeout,
+		logger:  logger,
 	}
 }
 
@@ -219,7 +256,8 @@ func (m *Manager) Load(names []string) error {
 		if err := m.loader.InitModule(module); err != nil {
 			m.logger.Warn("module init failed, disabling",
 				"module", module.Name,
-				"error", err)
+				"error", err,
+				"reason", module.DisabledReason)
 		}
 	}
 
@@ -234,16 +272,28 @@ func (m *Manager) Load(names []string) error {
 		}
 	}
 
-	timeout := 5 * time.Second
-	if len(names) > 0 {
-		// Use configured timeout
-		timeout = m.loader.QueryTimeout
+	timeout := m.timeout
+	if timeout <= 0 {
+		timeout = 5 * time.Second
 	}
 
 	m.dispatcher = NewDispatcher(enabled, timeout, m.logger)
+	m.dispatcher.env = m.loader.Env
+	m.dispatcher.sandbox = m.loader.Sandbox
 	return nil
 }
 
+// SetEnv sets additional environment variables modules run with. Call it
+// before Load.
+func (m *Manager) SetEnv(env []string) {
+	m.loader.Env = env
+}
+
+// SetSandbox sets the limits modules run under. Call it before Load.
+func (m *Manager) SetSandbox(sandbox Sandbox) {
+	m.loader.Sandbox = sandbox
+}
+
 // Dispatch sends an event to all modules.
 func (m *Manager) Dispatch(event *Event) {
 	if m.dispatcher != nil {
@@ -259,9 +309,11 @@ func (m *Manager) DispatchSync(ctx context.Context, event *Event) []error {
 	return nil
 }
 
-// Cleanup cleans up the module manager.
+// Cleanup gives in-flight handlers until their timeout to finish, then
+// kills any still running.
 func (m *Manager) Cleanup() {
 	if m.dispatcher != nil {
+		m.dispatcher.Wait(m.dispatcher.longestTimeout())
 		m.dispatcher.Cleanup()
 	}
 }
@@ -2,14 +2,27 @@ package module
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 )
 
+// Dir is where modules live, relative to where Brigade runs.
+const Dir = "modules"
+
+// Builtins are the modules that ship with Brigade, with what they do.
+var Builtins = map[string]string{
+	"telegram":      "Telegram notifications",
+	"desktop":       "Desktop notifications (macOS/Linux)",
+	"terminal":      "Terminal bell + colored banners",
+	"webhook":       "Webhooks for Slack/Discord",
+	"cost_tracking": "Log task durations to CSV",
+}
+
 // Loader discovers and loads modules.
 type Loader struct {
 	// ModulesDir is the directory containing module executables
@@ -20,6 +33,12 @@ type Loader struct {
 
 	// Timeout for querying module events
 	QueryTimeout time.Duration
+
+	// Env are additional environment variables modules run with
+	Env []string
+
+	// Sandbox limits what modules see and use
+	Sandbox Sandbox
 }
 
 // NewLoader creates a new module loader.
@@ -69,25 +88,32 @@ func (l *Loader) loadModule(name string) (*Module, error) {
 		return nil, fmt.Errorf("not executable")
 	}
 
+	module := &Module{
+		Name:    name,
+		Path:    path,
+		Config:  l.getModuleConfig(name),
+		Enabled: true,
+	}
+	if value, ok := module.Config["TIMEOUT"]; ok {
+		seconds, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || seconds <= 0 {
+			return nil, fmt.Errorf("MODULE_%s_TIMEOUT %q isn't a number of seconds", strings.ToUpper(name), value)
+		}
+		module.Timeout = time.Duration(seconds) * time.Second
+	}
+
 	// Query events
-	events, err := l.queryEvents(path)
+	events, err := l.queryEvents(module)
 	if err != nil {
 		return nil, fmt.Errorf("querying events: %w", err)
 	}
+	module.Events = events
 
-	// Build module config
-	config := l.getModuleConfig(name)
-
-	return &Module{
-		Name:    name,
-		Path:    path,
-		Events:  events,
-		Config:  config,
-		Enabled: true,
-	}, nil
+	return module, nil
 }
 
-// findModulePath finds the path to a module executable.
+// findModulePath finds the path to a module executable. The path is
+// absolute, since modules run from their own directory.
 func (l *Loader) findModulePath(name string) string {
 	// Try different extensions/names
 	candidates := []string{
@@ -101,6 +127,9 @@ func (l *Loader) findModulePath(name string) string {
 	for _, candidate := range candidates {
 		path := filepath.Join(l.ModulesDir, candidate)
 		if _, err := os.Stat(path); err == nil {
+			if abs, err := filepath.Abs(path); err == nil {
+				return abs
+			}
 			return path
 		}
 	}
@@ -109,29 +138,19 @@ func (l *Loader) findModulePath(name string) string {
 }
 
 // queryEvents queries a module for the events it handles.
-func (l *Loader) queryEvents(path string) ([]EventType, error) {
-	cmd := exec.Command(path, "--events")
-	cmd.Dir = filepath.Dir(path)
+func (l *Loader) queryEvents(module *Module) ([]EventType, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), l.QueryTimeout)
+	defer cancel()
+	cmd := l.Sandbox.command(ctx, module, l.Env, "--events")
 
 	var stdout bytes.Buffer
 	cmd.Stdout = &stdout
 
-	// Set a timeout
-	done := make(chan error, 1)
-	go func() {
-		done <- cmd.Run()
-	}()
-
-	select {
-	case err := <-done:
-		if err != nil {
-			return nil, err
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("timeout querying events")
 		}
-	case <-time.After(l.QueryTimeout):
-		if cmd.Process != nil {
-			cmd.Process.Kill()
-		}
-		return nil, fmt.Errorf("timeout querying events")
+		return nil, err
 	}
 
 	// Parse events from output
@@ -213,20 +232,16 @@ func (l *Loader) DiscoverModules() ([]string, error) {
 // InitModule calls the module's init function if it has one.
 func (l *Loader) InitModule(module *Module) error {
 	// Try to call --init
-	cmd := exec.Command(module.Path, "--init")
-	cmd.Dir = filepath.Dir(module.Path)
+	cmd := l.Sandbox.command(context.Background(), module, l.Env, "--init")
 
-	// Set module config as environment
-	cmd.Env = os.Environ()
-	for key, value := range module.Config {
-		envKey := "MODULE_" + strings.ToUpper(module.Name) + "_" + key
-		cmd.Env = append(cmd.Env, envKey+"="+value)
-	}
-
-	err := cmd.Run()
+	output, err := cmd.CombinedOutput()
 	if err != nil {
 		// Init failure means module should be disabled
 		module.Enabled = false
+		module.DisabledReason = strings.TrimSpace(string(output))
+		if module.DisabledReason == "" {
+			module.DisabledReason = err.Error()
+		}
 		return err
 	}
 
@@ -3,6 +3,7 @@ package module
 
 import (
 	"encoding/json"
+	"math"
 	"time"
 )
 
@@ -11,16 +12,25 @@ type EventType string
 
 const (
 	EventServiceStart    EventType = "service_start"
+	EventCostEstimate    EventType = "cost_estimate"
 	EventTaskStart       EventType = "task_start"
 	EventTaskComplete    EventType = "task_complete"
 	EventTaskBlocked     EventType = "task_blocked"
+	EventTaskIteration   EventType = "task_iteration"
+	EventTaskAbsorbed    EventType = "task_absorbed"
+	EventTaskSkipped     EventType = "task_skipped"
 	EventEscalation      EventType = "escalation"
 	EventReview          EventType = "review"
 	EventVerification    EventType = "verification"
+	EventArtifacts       EventType = "artifacts"
 	EventAttention       EventType = "attention"
 	EventDecisionNeeded  EventType = "decision_needed"
 	EventDecisionReceived EventType = "decision_received"
+	EventDecisionFallback EventType = "decision_fallback"
 	EventScopeDecision   EventType = "scope_decision"
+	EventRateLimited     EventType = "rate_limited"
+	EventThrottled       EventType = "throttled"
+	EventHeartbeat       EventType = "heartbeat"
 	EventServiceComplete EventType = "service_complete"
 )
 
@@ -28,16 +38,25 @@ const (
 func AllEventTypes() []EventType {
 	return []EventType{
 		EventServiceStart,
+		EventCostEstimate,
 		EventTaskStart,
 		EventTaskComplete,
 		EventTaskBlocked,
+		EventTaskIteration,
+		EventTaskAbsorbed,
+		EventTaskSkipped,
 		EventEscalation,
 		EventReview,
 		EventVerification,
+		EventArtifacts,
 		EventAttention,
 		EventDecisionNeeded,
 		EventDecisionReceived,
+		EventDecisionFallback,
 		EventScopeDecision,
+		EventRateLimited,
+		EventThrottled,
+		EventHeartbeat,
 		EventServiceComplete,
 	}
 }
@@ -113,8 +132,16 @@ type Module struct {
 	// Config holds module-specific configuration
 	Config map[string]string
 
+	// Timeout is how long its handlers may run (MODULE_<NAME>_TIMEOUT); 0
+	// for the MODULE_TIMEOUT every module shares
+	Timeout time.Duration
+
 	// Enabled indicates if the module is enabled
 	Enabled bool
+
+	// DisabledReason is why init disabled the module: what it printed, or
+	// how it failed if it printed nothing
+	DisabledReason string
 }
 
 // HandlesEvent returns true if the module handles the given event type.
@@ -139,6 +166,16 @@ func ServiceStartEvent(prd string, totalTasks int) *Event {
 		WithData("totalTasks", totalTasks)
 }
 
+// CostEstimateEvent creates a cost_estimate event with the projected spend
+// for a run, sent before any work starts.
+func CostEstimateEvent(prd string, estimate, threshold float64) *Event {
+	return NewEvent(EventCostEstimate).
+		WithPRD(prd).
+		WithData("estimatedCost", estimate).
+		WithData("threshold", threshold).
+		WithData("overThreshold", threshold > 0 && estimate > threshold)
+}
+
 // TaskStartEvent creates a task_start event.
 func TaskStartEvent(prd, taskID, worker string) *Event {
 	return NewEvent(EventTaskStart).
@@ -165,6 +202,36 @@ func TaskBlockedEvent(prd, taskID, worker, reason string) *Event {
 		WithData("reason", reason)
 }
 
+// TaskIterationEvent creates a task_iteration event, emitted after every
+// attempt with the attempt number and its outcome.
+func TaskIterationEvent(prd, taskID, worker string, attempt int, status string) *Event {
+	return NewEvent(EventTaskIteration).
+		WithPRD(prd).
+		WithTask(taskID).
+		WithWorker(worker).
+		WithData("attempt", attempt).
+		WithData("status", status)
+}
+
+// TaskAbsorbedEvent creates a task_absorbed event: the worker found the
+// task already done by another.
+func TaskAbsorbedEvent(prd, taskID, absorbedBy string) *Event {
+	return NewEvent(EventTaskAbsorbed).
+		WithPRD(prd).
+		WithTask(taskID).
+		WithData("absorbedBy", absorbedBy)
+}
+
+// TaskSkippedEvent creates a task_skipped event, with the tasks that can
+// no longer run because they depend on it.
+func TaskSkippedEvent(prd, taskID, reason string, blocks []string) *Event {
+	return NewEvent(EventTaskSkipped).
+		WithPRD(prd).
+		WithTask(taskID).
+		WithData("reason", reason).
+		WithData("blocks", blocks)
+}
+
 // EscalationEvent creates an escalation event.
 func EscalationEvent(prd, taskID, from, to, reason string) *Event {
 	return NewEvent(EventEscalation).
@@ -193,6 +260,17 @@ func VerificationEvent(prd, taskID string, passed bool, details string) *Event {
 		WithData("details", details)
 }
 
+// ArtifactsEvent creates an artifacts event, listing the files an attempt
+// left in its artifacts directory.
+func ArtifactsEvent(prd, taskID string, attempt int, dir string, files []string) *Event {
+	return NewEvent(EventArtifacts).
+		WithPRD(prd).
+		WithTask(taskID).
+		WithData("attempt", attempt).
+		WithData("dir", dir).
+		WithData("files", files)
+}
+
 // AttentionEvent creates an attention event.
 func AttentionEvent(prd, taskID, reason string) *Event {
 	return NewEvent(EventAttention).
@@ -220,6 +298,18 @@ func DecisionReceivedEvent(prd, taskID, decisionID, action, reason string) *Even
 		WithData("reason", reason)
 }
 
+// DecisionFallbackEvent creates a decision_fallback event: no supervisor
+// answered a decision in time, so its fallback applied. decision is the kind
+// of decision (task, phase_gate).
+func DecisionFallbackEvent(prd, taskID, decisionID, decision, fallback string) *Event {
+	return NewEvent(EventDecisionFallback).
+		WithPRD(prd).
+		WithTask(taskID).
+		WithData("decisionId", decisionID).
+		WithData("decision", decision).
+		WithData("fallback", fallback)
+}
+
 // ScopeDecisionEvent creates a scope_decision event.
 func ScopeDecisionEvent(prd, taskID, question, decision string) *Event {
 	return NewEvent(EventScopeDecision).
@@ -229,6 +319,42 @@ func ScopeDecisionEvent(prd, taskID, question, decision string) *Event {
 		WithData("decision", decision)
 }
 
+// RateLimitedEvent creates a rate_limited event: a worker command hit a
+// provider rate limit and won't be given work until resetsAt.
+func RateLimitedEvent(prd, taskID, worker, command string, resetsAt time.Time) *Event {
+	return NewEvent(EventRateLimited).
+		WithPRD(prd).
+		WithTask(taskID).
+		WithWorker(worker).
+		WithData("command", command).
+		WithData("resetsAt", resetsAt.Format(time.RFC3339))
+}
+
+// ThrottledEvent creates a throttled event: a task is waiting for its
+// worker's rate limit to reset, or was set aside for work at another tier.
+func ThrottledEvent(prd, taskID, worker string, resetsAt time.Time, action string) *Event {
+	return NewEvent(EventThrottled).
+		WithPRD(prd).
+		WithTask(taskID).
+		WithWorker(worker).
+		WithData("resetsAt", resetsAt.Format(time.RFC3339)).
+		WithData("action", action)
+}
+
+// HeartbeatEvent creates a heartbeat event: what the service is doing right
+// now, sent on a timer so a watchdog can tell a dead service from an idle
+// one. The task is empty while no worker runs. burnRate is spend per hour
+// this session.
+func HeartbeatEvent(prd, taskID, worker string, elapsed time.Duration, iteration int, burnRate float64) *Event {
+	return NewEvent(EventHeartbeat).
+		WithPRD(prd).
+		WithTask(taskID).
+		WithWorker(worker).
+		WithData("elapsed", int(elapsed.Seconds())).
+		WithData("iteration", iteration).
+		WithData("burnRate", math.Round(burnRate*100)/100)
+}
+
 // ServiceCompleteEvent creates a service_complete event.
 func ServiceCompleteEvent(prd string, completed, total int, duration time.Duration) *Event {
 	return NewEvent(EventServiceComplete).
@@ -0,0 +1,65 @@
+package module
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"brigade/internal/config"
+)
+
+// sandboxPath is the PATH a sandboxed module runs with.
+const sandboxPath = "/usr/local/bin:/usr/bin:/bin"
+
+// Sandbox limits what a module sees and uses while it runs.
+type Sandbox struct {
+	// Restricted runs modules with only their MODULE_<NAME>_ vars and a
+	// minimal PATH instead of Brigade's environment, which holds provider
+	// API keys
+	Restricted bool
+
+	// CPU is the CPU time a run may use; 0 for no limit
+	CPU time.Duration
+
+	// MemoryMB is the memory a run may use; 0 for no limit
+	MemoryMB int
+}
+
+// SandboxFromConfig returns the limits MODULE_SANDBOX, MODULE_CPU_LIMIT and
+// MODULE_MEMORY_LIMIT put on modules.
+func SandboxFromConfig(cfg *config.Config) Sandbox {
+	return Sandbox{
+		Restricted: cfg.ModuleSandbox,
+		CPU:        cfg.ModuleCPULimit,
+		MemoryMB:   cfg.ModuleMemoryLimit,
+	}
+}
+
+// env returns the environment a module runs with: its config as
+// MODULE_<NAME>_ vars and extra, over Brigade's environment unless
+// restricted.
+func (s Sandbox) env(module *Module, extra []string) []string {
+	var env []string
+	if s.Restricted {
+		env = []string{"PATH=" + sandboxPath}
+	} else {
+		env = os.Environ()
+	}
+	for key, value := range module.Config {
+		envKey := "MODULE_" + strings.ToUpper(module.Name) + "_" + key
+		env = append(env, envKey+"="+value)
+	}
+	return append(env, extra...)
+}
+
+// command returns the command that runs a module within the sandbox,
+// from the module's directory.
+func (s Sandbox) command(ctx context.Context, module *Module, extra []string, args ...string) *exec.Cmd {
+	cmd := limitCommand(ctx, s, module.Path, args...)
+	cmd.Dir = filepath.Dir(module.Path)
+	cmd.Env = s.env(module, extra)
+	return cmd
+}
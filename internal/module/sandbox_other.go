@@ -0,0 +1,14 @@
+//go:build !unix
+
+package module
+
+import (
+	"context"
+	"os/exec"
+)
+
+// limitCommand runs path with args without CPU or memory limits, which
+// need a Unix shell.
+func limitCommand(ctx context.Context, s Sandbox, path string, args ...string) *exec.Cmd {
+	return exec.CommandContext(ctx, path, args...)
+}
@@ -0,0 +1,31 @@
+//go:build unix
+
+package module
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// limitCommand returns a command running path with args under the
+// sandbox's CPU and memory limits. The limits are set by a shell that then
+// execs the module, so the module is the process that gets killed on a
+// timeout. A limit the OS doesn't support is skipped.
+func limitCommand(ctx context.Context, s Sandbox, path string, args ...string) *exec.Cmd {
+	var limits []string
+	if s.CPU > 0 {
+		seconds := int((s.CPU + time.Second - 1) / time.Second)
+		limits = append(limits, fmt.Sprintf("ulimit -t %d 2>/dev/null", seconds))
+	}
+	if s.MemoryMB > 0 {
+		limits = append(limits, fmt.Sprintf("ulimit -v %d 2>/dev/null", s.MemoryMB*1024))
+	}
+	if len(limits) == 0 {
+		return exec.CommandContext(ctx, path, args...)
+	}
+	script := strings.Join(limits, "; ") + `; exec "$0" "$@"`
+	return exec.CommandContext(ctx, "/bin/sh", append([]string{"-c", script, path}, args...)...)
+}
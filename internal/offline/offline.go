@@ -0,0 +1,91 @@
+// Package offline keeps an air-gapped run off the internet: it tells local
+// endpoints from remote ones, and builds the environment that stops the
+// commands Brigade runs from reaching anything else.
+package offline
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+)
+
+// blackhole is the proxy offline commands are pointed at. Nothing listens
+// on the discard port, so proxied requests fail at once.
+const blackhole = "http://127.0.0.1:9"
+
+// localSuffixes are domains that only resolve inside a network.
+var localSuffixes = []string{".local", ".internal", ".lan", ".home.arpa"}
+
+// Local reports whether host is this machine or on the local network: a
+// loopback, private or link-local address, a name without dots (a Docker
+// service or /etc/hosts entry), a local-only domain, or one of allowed.
+func Local(host string, allowed []string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if host == "" {
+		return false
+	}
+	for _, a := range allowed {
+		a = strings.ToLower(a)
+		if host == a || (strings.HasPrefix(a, ".") && strings.HasSuffix(host, a)) {
+			return true
+		}
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		return ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast()
+	}
+	if host == "localhost" || !strings.Contains(host, ".") {
+		return true
+	}
+	for _, suffix := range localSuffixes {
+		if strings.HasSuffix(host, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckURL returns an error naming setting if rawURL isn't local.
+func CheckURL(setting, rawURL string, allowed []string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return fmt.Errorf("%s %q isn't a URL", setting, rawURL)
+	}
+	if !Local(u.Hostname(), allowed) {
+		return fmt.Errorf("%s points at %s, which OFFLINE doesn't allow (add it to OFFLINE_ALLOWED_HOSTS if it's on your network)", setting, u.Hostname())
+	}
+	return nil
+}
+
+// Env returns the environment that keeps a command off the internet. HTTP
+// clients that honor proxy settings (curl, pip, npm, go and most SDKs) are
+// sent to a proxy that refuses them, except for local hosts; package
+// managers are put in their offline modes. It's a guard against accidents,
+// not a sandbox: a program that ignores proxies can still connect.
+func Env(allowed []string) []string {
+	noProxy := append([]string{
+		"localhost", "127.0.0.1", "::1",
+		"10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16",
+	}, localSuffixes...)
+	noProxy = append(noProxy, allowed...)
+
+	var env []string
+	for _, key := range []string{"HTTP_PROXY", "HTTPS_PROXY", "ALL_PROXY"} {
+		env = append(env, key+"="+blackhole, strings.ToLower(key)+"="+blackhole)
+	}
+	no := strings.Join(noProxy, ",")
+	return append(env,
+		"NO_PROXY="+no, "no_proxy="+no,
+		"GOPROXY=off",
+		"NPM_CONFIG_OFFLINE=true",
+		"PIP_NO_INDEX=1",
+		"CARGO_NET_OFFLINE=true",
+		"HF_HUB_OFFLINE=1",
+		"CLAUDE_CODE_DISABLE_NONESSENTIAL_TRAFFIC=1",
+	)
+}
+
+// ParseHosts splits OFFLINE_ALLOWED_HOSTS.
+func ParseHosts(value string) []string {
+	return strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ' ' })
+}
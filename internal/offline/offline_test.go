@@ -0,0 +1,55 @@
+package offline
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLocal(t *testing.T) {
+	allowed := []string{"llm.corp.example.com", ".gpu.example.net"}
+	tests := []struct {
+		host string
+		want bool
+	}{
+		{"localhost", true},
+		{"127.0.0.1", true},
+		{"::1", true},
+		{"192.168.1.20", true},
+		{"10.4.0.7", true},
+		{"ollama", true},
+		{"gpu-box.local", true},
+		{"llm.corp.example.com", true},
+		{"node3.gpu.example.net", true},
+		{"api.anthropic.com", false},
+		{"8.8.8.8", false},
+		{"example.com", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := Local(tt.host, allowed); got != tt.want {
+			t.Errorf("Local(%q) = %v, want %v", tt.host, got, tt.want)
+		}
+	}
+}
+
+func TestCheckURL(t *testing.T) {
+	if err := CheckURL("LOCAL_MODEL_URL", "http://localhost:11434", nil); err != nil {
+		t.Errorf("CheckURL(localhost) = %v, want nil", err)
+	}
+	err := CheckURL("GATEWAY_URL", "https://llm-proxy.example.com", nil)
+	if err == nil || !strings.Contains(err.Error(), "llm-proxy.example.com") {
+		t.Errorf("CheckURL(remote) = %v, want an error naming the host", err)
+	}
+}
+
+func TestEnv(t *testing.T) {
+	env := strings.Join(Env([]string{"gpu-box"}), "\n")
+	for _, want := range []string{"HTTPS_PROXY=" + blackhole, "https_proxy=" + blackhole, "GOPROXY=off", "NO_PROXY=localhost,"} {
+		if !strings.Contains(env, want) {
+			t.Errorf("Env() missing %q", want)
+		}
+	}
+	if !strings.Contains(env, ",gpu-box\n") {
+		t.Errorf("Env() doesn't let the allowed host through:\n%s", env)
+	}
+}
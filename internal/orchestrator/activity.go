@@ -3,98 +3,37 @@ package orchestrator
 import (
 	"fmt"
 	"os"
-	"sync"
 	"time"
+
+	"brigade/internal/module"
 )
 
-// ActivityLogger writes periodic status updates to a file for monitoring.
+// ActivityLogger writes status updates to a file for monitoring: state
+// transitions, and a line per heartbeat while a worker runs.
 type ActivityLogger struct {
 	path      string
-	interval  time.Duration
 	prdPrefix string
-
-	mu            sync.Mutex
-	currentTask   string
-	currentWorker string
-	taskStart     time.Time
-
-	stopChan chan struct{}
-	doneChan chan struct{}
 }
 
 // NewActivityLogger creates a new activity logger.
-func NewActivityLogger(path string, interval time.Duration, prdPrefix string) *ActivityLogger {
+func NewActivityLogger(path string, prdPrefix string) *ActivityLogger {
 	return &ActivityLogger{
 		path:      path,
-		interval:  interval,
 		prdPrefix: prdPrefix,
 	}
 }
 
-// Start begins the background heartbeat logger.
-func (a *ActivityLogger) Start() {
-	if a.path == "" {
-		return
-	}
-
-	a.mu.Lock()
-	if a.stopChan != nil {
-		a.mu.Unlock()
-		return // Already running
-	}
-	a.stopChan = make(chan struct{})
-	a.doneChan = make(chan struct{})
-	a.mu.Unlock()
-
-	go func() {
-		defer close(a.doneChan)
-		ticker := time.NewTicker(a.interval)
-		defer ticker.Stop()
-
-		for {
-			select {
-			case <-ticker.C:
-				a.writeHeartbeat()
-			case <-a.stopChan:
-				return
-			}
-		}
-	}()
-}
-
-// Stop halts the background logger.
-func (a *ActivityLogger) Stop() {
-	a.mu.Lock()
-	defer a.mu.Unlock()
-
-	if a.stopChan != nil {
-		close(a.stopChan)
-		<-a.doneChan
-		a.stopChan = nil
-		a.doneChan = nil
+// Handle logs published heartbeats and escalations. Subscribe it to the
+// event bus.
+func (a *ActivityLogger) Handle(event *module.Event) {
+	switch event.Type {
+	case module.EventHeartbeat:
+		a.writeHeartbeat(event)
+	case module.EventEscalation:
+		a.WriteState("ESCALATION", fmt.Sprintf("%v -> %v", event.Data["from"], event.Data["to"]), event.TaskID)
 	}
 }
 
-// SetTask updates the current task being worked on.
-func (a *ActivityLogger) SetTask(taskID, worker string) {
-	a.mu.Lock()
-	defer a.mu.Unlock()
-
-	a.currentTask = taskID
-	a.currentWorker = worker
-	a.taskStart = time.Now()
-}
-
-// ClearTask clears the current task.
-func (a *ActivityLogger) ClearTask() {
-	a.mu.Lock()
-	defer a.mu.Unlock()
-
-	a.currentTask = ""
-	a.currentWorker = ""
-	a.taskStart = time.Time{}
-}
-
 // WriteState writes a state transition event.
 // Events: SERVICE_START, LOOP_EXIT, SERVICE_END, IDLE, ESCALATION
 func (a *ActivityLogger) WriteState(event, reason, detail string) {
@@ -115,24 +54,19 @@ func (a *ActivityLogger) WriteState(event, reason, detail string) {
 	a.appendToFile(line)
 }
 
-// writeHeartbeat writes the periodic heartbeat entry.
-func (a *ActivityLogger) writeHeartbeat() {
-	a.mu.Lock()
-	task := a.currentTask
-	worker := a.currentWorker
-	taskStart := a.taskStart
-	a.mu.Unlock()
-
-	if task == "" {
+// writeHeartbeat writes the heartbeat entry for the task a worker is on.
+func (a *ActivityLogger) writeHeartbeat(event *module.Event) {
+	if a.path == "" || event.TaskID == "" {
 		return // No active task, skip heartbeat
 	}
 
 	timestamp := time.Now().Format("15:04:05")
-	elapsed := time.Since(taskStart).Round(time.Second)
+	seconds, _ := event.Data["elapsed"].(int)
+	elapsed := time.Duration(seconds) * time.Second
 
 	// Format: [HH:MM:SS] prefix/task: Worker working (Xm Ys)
 	line := fmt.Sprintf("[%s] %s/%s: %s working (%s)\n",
-		timestamp, a.prdPrefix, task, worker, formatElapsed(elapsed))
+		timestamp, a.prdPrefix, event.TaskID, event.Worker, formatElapsed(elapsed))
 
 	a.appendToFile(line)
 }
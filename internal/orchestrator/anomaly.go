@@ -0,0 +1,354 @@
+package orchestrator
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"brigade/internal/state"
+	"brigade/internal/util"
+)
+
+const (
+	// minDiffSamples is how many earlier diffs set a norm for diff size.
+	minDiffSamples = 5
+	// minAnomalousDiff keeps small diffs from tripping the size check in a
+	// repo whose tasks are usually tiny.
+	minAnomalousDiff = 200
+	// minBurnWindow is how long a session runs before its burn rate counts.
+	minBurnWindow = 10 * time.Minute
+)
+
+// anomalyTracker holds what the walkaway anomaly checks need across
+// iterations: where each task's work started, the diff sizes seen before
+// this session, and the signals already raised so each pauses once.
+type anomalyTracker struct {
+	mu      sync.Mutex
+	starts  map[string]string // Task ID → HEAD when its first attempt started
+	batch   bool              // A parallel batch is running
+	norms   []int             // Diff sizes of earlier completions
+	loaded  bool
+	flagged map[string]string // Signal → why it was raised
+}
+
+// anomalyDetection reports whether anomalies pause the run: walkaway mode
+// with ANOMALY_DETECTION.
+func (o *Orchestrator) anomalyDetection() bool {
+	return o.config.WalkawayMode && o.config.AnomalyDetection
+}
+
+// markTaskStart records the commit a task's first attempt starts from, so
+// its diff can be measured (and shown by `brigade show` and to a human
+// reviewer) when it completes. Parallel tasks share the tree, so their diffs
+// can't be told apart and aren't recorded.
+func (o *Orchestrator) markTaskStart(taskID string) {
+	a := &o.anomaly
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.batch {
+		delete(a.starts, taskID)
+		return
+	}
+	if a.starts == nil {
+		a.starts = make(map[string]string)
+	}
+	if _, ok := a.starts[taskID]; !ok {
+		a.starts[taskID] = util.GetHeadCommit()
+	}
+}
+
+// setParallelBatch records whether tasks are running in parallel.
+func (o *Orchestrator) setParallelBatch(running bool) {
+	o.anomaly.mu.Lock()
+	o.anomaly.batch = running
+	o.anomaly.mu.Unlock()
+}
+
+// inParallelBatch reports whether tasks are running in parallel.
+func (o *Orchestrator) inParallelBatch() bool {
+	o.anomaly.mu.Lock()
+	defer o.anomaly.mu.Unlock()
+	return o.anomaly.batch
+}
+
+// taskStart returns the commit a task's first attempt started from, or ""
+// if it wasn't recorded.
+func (o *Orchestrator) taskStart(taskID string) string {
+	a := &o.anomaly
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.starts[taskID]
+}
+
+// recordDiffSize stores the lines a completed task changed on its last
+// attempt, and the commits its work spans.
+func (o *Orchestrator) recordDiffSize(taskID string) {
+	a := &o.anomaly
+	a.mu.Lock()
+	start, ok := a.starts[taskID]
+	a.mu.Unlock()
+	last := o.state.LastAttempt(taskID)
+	if !ok || last == nil {
+		return
+	}
+	if start != "unknown" {
+		last.BaseCommit, last.Commit = start, util.GetHeadCommit()
+	}
+	if lines := util.GitDiffLines(start); lines > 0 {
+		last.DiffLines = lines
+	}
+}
+
+// checkAnomalies looks for signs a walkaway run has gone off the rails: a
+// spike in escalations, the same failure across several tasks, a diff far
+// larger than usual, or spend burning faster than ANOMALY_BURN_RATE. The
+// first time a signal crosses its threshold the run pauses until a
+// supervisor resumes it, or stops when no one can.
+func (o *Orchestrator) checkAnomalies() error {
+	if !o.anomalyDetection() {
+		return nil
+	}
+
+	var found []string
+	for _, check := range []func() (string, string){
+		o.escalationSpike,
+		o.repeatedFailure,
+		o.oversizedDiff,
+		o.burnRate,
+	} {
+		key, reason := check()
+		if key == "" || !o.flagAnomaly(key, reason) {
+			continue
+		}
+		o.logger.Warn("anomaly detected", "signal", key, "reason", reason)
+		if o.activity != nil {
+			o.activity.WriteState("ANOMALY", key, reason)
+		}
+		found = append(found, reason)
+	}
+	if len(found) == 0 {
+		return nil
+	}
+
+	reason := "anomaly: " + strings.Join(found, "; ")
+	if !o.supervisor.Commands().Enabled() {
+		o.raiseAttention(reason)
+		return &BlockedError{Reason: reason, Pending: taskIDs(o.selected(o.prd.PendingTasks()))}
+	}
+	o.raiseAttention(reason + ", waiting for resume")
+	o.setPaused(true)
+	return nil
+}
+
+// flagAnomaly records a signal, reporting false if it was already raised.
+func (o *Orchestrator) flagAnomaly(key, reason string) bool {
+	a := &o.anomaly
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.flagged == nil {
+		a.flagged = make(map[string]string)
+	}
+	if _, ok := a.flagged[key]; ok {
+		return false
+	}
+	a.flagged[key] = reason
+	return true
+}
+
+// sessionAttempts returns the attempts started this session.
+func (o *Orchestrator) sessionAttempts() []state.TaskHistory {
+	since := o.sessionStart.Truncate(time.Second)
+	var attempts []state.TaskHistory
+	for _, h := range o.state.TaskHistory {
+		if ts, err := time.Parse(time.RFC3339, h.Timestamp); err == nil && !ts.Before(since) {
+			attempts = append(attempts, h)
+		}
+	}
+	return attempts
+}
+
+// escalationSpike checks whether more than ANOMALY_ESCALATION_RATE percent
+// of the last ANOMALY_WINDOW tasks completed this session had to escalate.
+func (o *Orchestrator) escalationSpike() (string, string) {
+	window, rate := o.config.AnomalyWindow, o.config.AnomalyEscalationRate
+	if window <= 0 || rate <= 0 {
+		return "", ""
+	}
+	var finished []string
+	for _, h := range o.sessionAttempts() {
+		if h.Status == state.StatusComplete || h.Status == state.StatusAbsorbed {
+			finished = append(finished, h.TaskID)
+		}
+	}
+	if len(finished) < window {
+		return "", ""
+	}
+	escalated := 0
+	for _, taskID := range finished[len(finished)-window:] {
+		if o.state.WasEscalated(taskID) {
+			escalated++
+		}
+	}
+	if escalated*100 < rate*window {
+		return "", ""
+	}
+	return "escalation_rate", fmt.Sprintf("%d of the last %d tasks escalated", escalated, window)
+}
+
+// repeatedFailure checks whether the same failure has hit
+// ANOMALY_REPEATED_FAILURES different tasks this session, which usually
+// means something outside the tasks is broken.
+func (o *Orchestrator) repeatedFailure() (string, string) {
+	limit := o.config.AnomalyRepeatedFailures
+	if limit <= 1 {
+		return "", ""
+	}
+	tasks := make(map[string]map[string]bool)
+	var order []string
+	for _, h := range o.sessionAttempts() {
+		key := failureKey(h)
+		if key == "" {
+			continue
+		}
+		if tasks[key] == nil {
+			tasks[key] = make(map[string]bool)
+			order = append(order, key)
+		}
+		tasks[key][h.TaskID] = true
+	}
+	for _, key := range order {
+		if n := len(tasks[key]); n >= limit {
+			return "repeated_failure:" + key, fmt.Sprintf("the same failure hit %d tasks: %s", n, key)
+		}
+	}
+	return "", ""
+}
+
+// failureKey identifies a failure by its category and the first line of
+// its error, or returns "" for an attempt that didn't fail with one.
+func failureKey(h state.TaskHistory) string {
+	msg := strings.TrimSpace(h.Error)
+	if msg == "" || msg == needsIteration {
+		return ""
+	}
+	if i := strings.IndexByte(msg, '\n'); i >= 0 {
+		msg = strings.TrimSpace(msg[:i])
+	}
+	msg = strings.ToLower(msg)
+	if len(msg) > 120 {
+		msg = msg[:120]
+	}
+	if h.Category != "" {
+		return h.Category + ": " + msg
+	}
+	return msg
+}
+
+// oversizedDiff checks whether a task completed this session changed more
+// than ANOMALY_DIFF_FACTOR times the usual number of lines.
+func (o *Orchestrator) oversizedDiff() (string, string) {
+	factor := o.config.AnomalyDiffFactor
+	if factor <= 0 {
+		return "", ""
+	}
+	norms := o.diffNorms()
+	if len(norms) < minDiffSamples {
+		return "", ""
+	}
+	median := norms[len(norms)/2]
+	for _, h := range o.sessionAttempts() {
+		if h.Status != state.StatusComplete || h.DiffLines < minAnomalousDiff || h.DiffLines <= factor*median {
+			continue
+		}
+		a := &o.anomaly
+		a.mu.Lock()
+		_, seen := a.flagged["diff_size:"+h.TaskID]
+		a.mu.Unlock()
+		if !seen {
+			return "diff_size:" + h.TaskID, fmt.Sprintf("%s changed %d lines, over %dx the usual %d", h.TaskID, h.DiffLines, factor, median)
+		}
+	}
+	return "", ""
+}
+
+// diffNorms returns the sorted diff sizes of tasks completed before this
+// session, in this PRD and the others beside it. They are read once.
+func (o *Orchestrator) diffNorms() []int {
+	a := &o.anomaly
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.loaded {
+		return a.norms
+	}
+	a.loaded = true
+
+	since := o.sessionStart.Truncate(time.Second)
+	for _, h := range o.state.TaskHistory {
+		if ts, err := time.Parse(time.RFC3339, h.Timestamp); err == nil && ts.Before(since) && h.DiffLines > 0 {
+			a.norms = append(a.norms, h.DiffLines)
+		}
+	}
+	if dir := filepath.Dir(o.prd.Path()); o.prd.Path() != "" {
+		paths, _ := filepath.Glob(filepath.Join(dir, "*.state.json"))
+		for _, path := range paths {
+			if filepath.Clean(path) == filepath.Clean(o.state.Path()) {
+				continue
+			}
+			st, err := state.NewStore(path).Load()
+			if err != nil {
+				continue
+			}
+			for _, h := range st.TaskHistory {
+				if h.Status == state.StatusComplete && h.DiffLines > 0 {
+					a.norms = append(a.norms, h.DiffLines)
+				}
+			}
+		}
+	}
+	sort.Ints(a.norms)
+	return a.norms
+}
+
+// burnRate checks whether this session's spend per hour is over
+// ANOMALY_BURN_RATE, once it has run long enough to tell.
+func (o *Orchestrator) burnRate() (string, string) {
+	limit := o.config.AnomalyBurnRate
+	if limit <= 0 || time.Since(o.sessionStart) < minBurnWindow {
+		return "", ""
+	}
+	perHour := o.spendPerHour()
+	if perHour <= limit {
+		return "", ""
+	}
+	return "burn_rate", fmt.Sprintf("spending $%.2f/hour, over the $%.2f/hour limit", perHour, limit)
+}
+
+// spendPerHour returns what this session has spent per hour so far, 0
+// without a cost estimator.
+func (o *Orchestrator) spendPerHour() float64 {
+	elapsed := time.Since(o.sessionStart)
+	if o.costEstimator == nil || elapsed <= 0 {
+		return 0
+	}
+	spent := 0.0
+	for _, h := range o.sessionAttempts() {
+		spent += o.costEstimator.AttemptCost(h)
+	}
+	return spent / elapsed.Hours()
+}
+
+// anomalySignals lists the anomalies raised this run, for the handoff.
+func (o *Orchestrator) anomalySignals() []string {
+	a := &o.anomaly
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	var reasons []string
+	for _, reason := range a.flagged {
+		reasons = append(reasons, reason)
+	}
+	sort.Strings(reasons)
+	return reasons
+}
@@ -0,0 +1,66 @@
+package orchestrator
+
+import (
+	"fmt"
+	"strings"
+
+	"brigade/internal/policy"
+	"brigade/internal/prd"
+	"brigade/internal/state"
+	"brigade/internal/util"
+)
+
+// approveForceLock gets an operator's approval, if APPROVAL_REQUIRED asks
+// for one, before --force takes over a lock whose holder looks alive.
+func (o *Orchestrator) approveForceLock() error {
+	if !o.config.ForceOverrideLock || !o.policy.Requires(policy.ForceUnlock) {
+		return nil
+	}
+	lock := state.NewServiceLock(o.prd.Path(), state.WithHeartbeatInterval(o.config.LockHeartbeatInterval))
+	if !lock.Exists() || lock.IsStale() {
+		return nil
+	}
+	detail := "service --force"
+	if holder, err := lock.Holder(); err == nil {
+		detail = fmt.Sprintf("service --force over %s", holder)
+	}
+	op, err := o.policy.Approve(policy.ForceUnlock, o.prd.Path(), detail)
+	if err != nil {
+		return err
+	}
+	o.logger.Warn("taking over a live service lock", "operator", op.Name)
+	return nil
+}
+
+// protectedViolation describes a task's changes to PROTECTED_PATHS, or
+// returns "" if there are none or the run allows them. Allowing them needs
+// an operator's approval if APPROVAL_REQUIRED includes protected-paths.
+func (o *Orchestrator) protectedViolation(task *prd.Task) string {
+	if len(o.config.ProtectedPaths) == 0 {
+		return ""
+	}
+	var changed []string
+	for _, f := range util.GitChangedSince(o.taskStart(task.ID)) {
+		if !strings.HasPrefix(f, "brigade/") {
+			changed = append(changed, f)
+		}
+	}
+	touched := policy.Protected(changed, o.config.ProtectedPaths)
+	if len(touched) == 0 {
+		return ""
+	}
+
+	if o.allowProtected {
+		op, err := o.policy.Approve(policy.ProtectedPaths, o.prd.Path(), task.ID+": "+strings.Join(touched, ", "))
+		if err == nil {
+			o.logger.Warn("protected paths changed, allowed", "task", task.ID, "files", len(touched), "operator", op.Name)
+			return ""
+		}
+		o.logger.Warn("protected path change not approved", "task", task.ID, "error", err)
+	}
+	if len(touched) > 5 {
+		touched = append(touched[:5], fmt.Sprintf("and %d more", len(touched)-5))
+	}
+	return fmt.Sprintf("changed protected files: %s. Revert them; PROTECTED_PATHS may only change with an operator's approval",
+		strings.Join(touched, ", "))
+}
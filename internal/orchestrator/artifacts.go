@@ -0,0 +1,120 @@
+package orchestrator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"brigade/internal/module"
+	"brigade/internal/prd"
+	"brigade/internal/verify"
+)
+
+// artifactsRoot holds the files attempts leave for people to look at, such
+// as screenshots, traces, coverage reports and benchmark output:
+// brigade/artifacts/<prefix>/<task>/attempt-<n>.
+var artifactsRoot = filepath.Join("brigade", "artifacts")
+
+// artifactsDir returns where an attempt on a task, numbered from 1, keeps
+// its artifacts.
+func (o *Orchestrator) artifactsDir(task *prd.Task, attempt int) string {
+	return filepath.Join(artifactsRoot, o.prd.Prefix(), task.ID, fmt.Sprintf("attempt-%d", attempt))
+}
+
+// attemptArtifactsDir returns the artifacts directory of the latest attempt
+// on a task.
+func (o *Orchestrator) attemptArtifactsDir(task *prd.Task) string {
+	return o.artifactsDir(task, max(o.state.TotalAttempts(task.ID), 1))
+}
+
+// artifactsEnv creates an attempt's artifacts directory and points the
+// worker at it with BRIGADE_ARTIFACTS_DIR.
+func (o *Orchestrator) artifactsEnv(task *prd.Task, attempt int) []string {
+	dir, err := filepath.Abs(o.artifactsDir(task, attempt))
+	if err != nil || os.MkdirAll(dir, 0755) != nil {
+		return nil
+	}
+	return []string{"BRIGADE_ARTIFACTS_DIR=" + dir}
+}
+
+// recordArtifacts records and reports the files an attempt left in its
+// artifacts directory, once its outcome is known, and prunes the task's
+// older attempts past ARTIFACTS_KEEP_ATTEMPTS. An attempt that didn't run
+// to an outcome (rate limited, or restarted for a nudge) keeps its
+// directory for the next one.
+func (o *Orchestrator) recordArtifacts(task *prd.Task, attempt int) {
+	if o.state.TotalAttempts(task.ID) < attempt {
+		return
+	}
+	dir := o.artifactsDir(task, attempt)
+	files := verify.ListArtifacts(dir)
+	if len(files) == 0 {
+		os.Remove(dir) // Only if it's empty
+		return
+	}
+
+	o.state.SetArtifacts(task.ID, attempt, dir, files)
+	o.logger.Info("attempt left artifacts", "task", task.ID, "dir", dir, "files", len(files))
+	o.events.Publish(module.ArtifactsEvent(o.prd.Prefix(), task.ID, attempt, dir, files))
+
+	if keep := o.config.ArtifactsKeepAttempts; keep > 0 {
+		attempts := attemptDirs(filepath.Dir(dir))
+		for len(attempts) > keep {
+			os.RemoveAll(attempts[0])
+			attempts = attempts[1:]
+		}
+	}
+}
+
+// attemptDirs lists a task's attempt-<n> artifact directories, oldest
+// first.
+func attemptDirs(taskDir string) []string {
+	entries, err := os.ReadDir(taskDir)
+	if err != nil {
+		return nil
+	}
+	type numbered struct {
+		n    int
+		path string
+	}
+	var dirs []numbered
+	for _, e := range entries {
+		n, err := strconv.Atoi(strings.TrimPrefix(e.Name(), "attempt-"))
+		if err != nil || !e.IsDir() {
+			continue
+		}
+		dirs = append(dirs, numbered{n, filepath.Join(taskDir, e.Name())})
+	}
+	sort.Slice(dirs, func(i, j int) bool { return dirs[i].n < dirs[j].n })
+
+	paths := make([]string, len(dirs))
+	for i, d := range dirs {
+		paths[i] = d.path
+	}
+	return paths
+}
+
+// pruneArtifacts removes the attempt directories of every PRD that haven't
+// changed in ARTIFACTS_MAX_AGE_DAYS.
+func (o *Orchestrator) pruneArtifacts(now time.Time) {
+	if o.config.ArtifactsMaxAgeDays <= 0 {
+		return
+	}
+	cutoff := now.Add(-time.Duration(o.config.ArtifactsMaxAgeDays) * 24 * time.Hour)
+	dirs, _ := filepath.Glob(filepath.Join(artifactsRoot, "*", "*", "attempt-*"))
+	removed := 0
+	for _, dir := range dirs {
+		if info, err := os.Stat(dir); err == nil && info.ModTime().Before(cutoff) {
+			if os.RemoveAll(dir) == nil {
+				removed++
+			}
+		}
+	}
+	if removed > 0 {
+		o.logger.Info("removed old artifacts", "attempts", removed)
+	}
+}
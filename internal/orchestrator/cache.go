@@ -0,0 +1,46 @@
+package orchestrator
+
+import (
+	"path/filepath"
+	"strings"
+
+	"brigade/internal/config"
+	"brigade/internal/util"
+	"brigade/internal/worker"
+)
+
+// execCacheDir holds cached executive responses.
+var execCacheDir = filepath.Join("brigade", "cache", "executive")
+
+// ExecCache returns the executive response cache, or nil when
+// EXEC_CACHE_TTL is 0.
+func ExecCache(cfg *config.Config) *worker.Cache {
+	if cfg.ExecCacheTTL <= 0 {
+		return nil
+	}
+	return worker.NewCache(execCacheDir, cfg.ExecCacheTTL)
+}
+
+// executive returns an executive worker for a call that only reads the code
+// and answers: a review, a decision, an analysis. An answer it gave to the
+// same prompt against the same code within EXEC_CACHE_TTL is reused.
+func (o *Orchestrator) executive() worker.Worker {
+	w := o.workers.Executive()
+	if o.execCache == nil {
+		return w
+	}
+	return o.execCache.Wrap(w, o.config.ExecutiveCmd, CodeState())
+}
+
+// CodeState identifies the code an executive call sees: HEAD plus the
+// uncommitted changes outside brigade/, whose state files change on every
+// attempt.
+func CodeState() string {
+	var files []string
+	for _, f := range util.GitChangedFiles() {
+		if !strings.HasPrefix(f, "brigade/") {
+			files = append(files, f)
+		}
+	}
+	return util.GitTreeState(files)
+}
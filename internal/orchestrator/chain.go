@@ -0,0 +1,132 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"brigade/internal/module"
+	"brigade/internal/supervisor"
+)
+
+// ErrChainPaused is returned by Gate when PHASE_GATE is pause and no one is
+// around to say whether the chain goes on.
+var ErrChainPaused = errors.New("chain paused after PRD")
+
+// GateError stops an --auto-continue chain after a PRD: its review failed
+// or a supervisor aborted.
+type GateError struct {
+	PRD    string
+	Reason string
+}
+
+func (e *GateError) Error() string {
+	return fmt.Sprintf("chain stopped after %s: %s", e.PRD, e.Reason)
+}
+
+var chainReviewPattern = regexp.MustCompile(`(?s)<prd_review>.*?RESULT:\s*(PASS|FAIL)\b(?:.*?REASON:\s*(.*?))?\s*</prd_review>`)
+
+// Gate applies PHASE_GATE between the PRDs of an --auto-continue chain,
+// after this one completed and before next starts: continue, pause for a
+// supervisor or the user (confirm, nil when no one can answer), or an
+// executive review of the completed PRD. A nil error means go on.
+func (o *Orchestrator) Gate(ctx context.Context, next string, confirm func(question string) bool) error {
+	switch o.config.PhaseGate {
+	case "pause":
+		return o.pauseGate(ctx, next, confirm)
+	case "review":
+		return o.reviewGate(ctx, next)
+	}
+	return nil
+}
+
+// pauseGate waits for a supervisor's resume, or asks the user.
+func (o *Orchestrator) pauseGate(ctx context.Context, next string, confirm func(question string) bool) error {
+	question := fmt.Sprintf("%s is complete. Continue with %s?", o.prd.FeatureName, next)
+	if o.supervisor.Commands().Enabled() {
+		o.logger.Info("phase gate: waiting for the supervisor", "next", next)
+		cmd, err := o.supervisor.RequestDecision(ctx, "", question, []string{"resume", "abort"})
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		var timeout *supervisor.TimeoutError
+		if errors.As(err, &timeout) {
+			// SUPERVISOR_GATE_FALLBACK
+			o.decisionFallback("", "phase_gate", o.config.SupervisorGateFallback, timeout)
+			switch o.config.SupervisorGateFallback {
+			case "continue":
+				return nil
+			case "abort":
+				return &GateError{PRD: o.prd.Prefix(), Reason: "no supervisor decision"}
+			}
+			return ErrChainPaused
+		}
+		if err != nil {
+			o.logger.Warn("phase gate: no supervisor decision", "error", err)
+			return ErrChainPaused
+		}
+		if cmd.Action == supervisor.ActionAbort {
+			return &GateError{PRD: o.prd.Prefix(), Reason: "supervisor aborted: " + cmd.Reason}
+		}
+		if cmd.Action == supervisor.ActionPause {
+			return ErrChainPaused
+		}
+		return nil
+	}
+	if confirm != nil {
+		if confirm(question + " (Y/n) ") {
+			return nil
+		}
+		return &GateError{PRD: o.prd.Prefix(), Reason: "declined"}
+	}
+	o.raiseAttention(fmt.Sprintf("phase_gate: paused before %s", next))
+	return ErrChainPaused
+}
+
+// reviewGate has the executive review the completed PRD, stopping the
+// chain unless it passes.
+func (o *Orchestrator) reviewGate(ctx context.Context, next string) error {
+	handoff, _ := os.ReadFile(o.prd.HandoffPath())
+	prompt, err := o.promptBuilder.BuildChainReviewPrompt(o.prd, next, strings.TrimSpace(string(handoff)))
+	if err != nil {
+		return &GateError{PRD: o.prd.Prefix(), Reason: "building review prompt: " + err.Error()}
+	}
+
+	o.logger.Info("phase gate: executive review", "prd", o.prd.Prefix(), "next", next)
+	o.events.Publish(module.AttentionEvent(o.prd.Prefix(), "", "phase_gate: reviewing before "+next))
+	reviewCtx := ctx
+	if o.config.PhaseReviewTimeout > 0 {
+		var cancel context.CancelFunc
+		reviewCtx, cancel = context.WithTimeout(ctx, o.config.PhaseReviewTimeout)
+		defer cancel()
+	}
+	result, err := o.executive().Execute(reviewCtx, prompt)
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if err != nil || result == nil || result.Timeout {
+		reason := "review failed"
+		if err != nil {
+			reason += ": " + err.Error()
+		}
+		o.raiseAttention("phase_gate: " + reason)
+		return &GateError{PRD: o.prd.Prefix(), Reason: reason}
+	}
+
+	m := chainReviewPattern.FindStringSubmatch(result.Output)
+	if m == nil {
+		o.raiseAttention("phase_gate: review gave no verdict")
+		return &GateError{PRD: o.prd.Prefix(), Reason: "review gave no verdict"}
+	}
+	reason := strings.Join(strings.Fields(m[2]), " ")
+	if m[1] == "FAIL" {
+		o.logger.Error("phase gate: review failed", "prd", o.prd.Prefix(), "reason", reason)
+		o.raiseAttention("phase_gate: review failed: " + reason)
+		return &GateError{PRD: o.prd.Prefix(), Reason: "review failed: " + reason}
+	}
+	o.logger.Info("phase gate: review passed", "prd", o.prd.Prefix(), "reason", reason)
+	return nil
+}
@@ -0,0 +1,26 @@
+package orchestrator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"brigade/internal/chef"
+)
+
+// checkChefPrompts lints the chef prompts before any work starts. A missing
+// prompt stops the run, since every task on its tier would fail; other
+// problems are logged for `brigade chef lint` to explain.
+func (o *Orchestrator) checkChefPrompts() error {
+	for _, tier := range chef.Tiers {
+		if _, err := os.Stat(filepath.Join(o.chefDir, chef.File(tier))); err != nil {
+			return fmt.Errorf("chef prompt for the %s tier: %w", tier, err)
+		}
+	}
+	for _, f := range chef.Lint(o.chefDir, o.config) {
+		if f.Severity == chef.SeverityError {
+			o.logger.Warn("chef prompt problem (see brigade chef lint)", "problem", f.String())
+		}
+	}
+	return nil
+}
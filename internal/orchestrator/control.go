@@ -0,0 +1,98 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"brigade/internal/module"
+	"brigade/internal/supervisor"
+)
+
+// applyControl handles supervisor control commands (pause, resume, abort,
+// set_parallel) between tasks. While paused it blocks, polling for resume.
+func (o *Orchestrator) applyControl(ctx context.Context) error {
+	commands := o.supervisor.Commands()
+	if !commands.Enabled() {
+		return nil
+	}
+
+	for {
+		cmd, err := commands.ReadControl()
+		if err != nil {
+			o.logger.Warn("failed to read supervisor command", "error", err)
+		}
+		if cmd != nil {
+			if err := o.handleControl(cmd); err != nil {
+				return err
+			}
+		}
+
+		if !o.paused {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(o.config.SupervisorCmdPollInterval):
+		}
+	}
+}
+
+// handleControl applies a single control command.
+func (o *Orchestrator) handleControl(cmd *supervisor.Command) error {
+	o.logger.Info("supervisor command received", "action", cmd.Action, "reason", cmd.Reason)
+
+	switch cmd.Action {
+	case supervisor.ActionPause:
+		if !o.paused {
+			o.setPaused(true)
+			o.logger.Warn("service paused by supervisor, waiting for resume")
+			o.events.Publish(module.AttentionEvent(o.prd.Prefix(), "", "service paused by supervisor"))
+		}
+	case supervisor.ActionResume:
+		if o.paused {
+			o.setPaused(false)
+			o.lastProgressTime = time.Now() // Paused time doesn't count as idle
+			o.logger.Info("service resumed by supervisor")
+		}
+	case supervisor.ActionAbort:
+		return fmt.Errorf("supervisor aborted: %s", cmd.Reason)
+	case supervisor.ActionSetParallel:
+		if cmd.Value < 0 {
+			o.logger.Warn("ignoring invalid max-parallel", "value", cmd.Value)
+			break
+		}
+		o.logger.Info("max-parallel changed by supervisor", "from", o.config.MaxParallel, "to", cmd.Value)
+		o.config.MaxParallel = cmd.Value
+	default:
+		o.logger.Warn("ignoring unknown supervisor command", "action", cmd.Action)
+	}
+	return nil
+}
+
+// setPaused pauses or resumes the service, showing it in the supervisor
+// status.
+func (o *Orchestrator) setPaused(paused bool) {
+	o.paused = paused
+	o.supervisor.Status().SetPaused(paused)
+	o.writeStatus()
+}
+
+// pauseFor pauses the service until a supervisor resumes it, flagging why.
+func (o *Orchestrator) pauseFor(reason string) {
+	o.setPaused(true)
+	o.raiseAttention(reason + ", waiting for resume")
+}
+
+// decisionFallback reports that no supervisor answered a decision within
+// SUPERVISOR_CMD_TIMEOUT, and the fallback that applies instead.
+func (o *Orchestrator) decisionFallback(taskID, decision, fallback string, timeout *supervisor.TimeoutError) {
+	o.logger.Warn("no supervisor decision, falling back",
+		"decision", decision,
+		"task", taskID,
+		"fallback", fallback,
+		"after", timeout.After)
+	o.events.Publish(module.DecisionFallbackEvent(o.prd.Prefix(), taskID, timeout.DecisionID, decision, fallback))
+}
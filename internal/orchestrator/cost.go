@@ -0,0 +1,108 @@
+package orchestrator
+
+import (
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"time"
+
+	"brigade/internal/config"
+	"brigade/internal/cost"
+	"brigade/internal/module"
+	"brigade/internal/policy"
+	"brigade/internal/state"
+)
+
+// projectCost estimates the run's total cost from the work recorded so far,
+// earlier runs, and the up-front estimate.
+func (o *Orchestrator) projectCost() cost.Projection {
+	return cost.Project(o.prd, o.state, o.costHistory, o.costEstimator)
+}
+
+// announceCost sends a cost_estimate event to modules and the supervisor.
+func (o *Orchestrator) announceCost(estimate float64) {
+	threshold := o.config.CostWarnThreshold
+	o.events.Publish(module.CostEstimateEvent(o.prd.Prefix(), estimate, threshold))
+}
+
+// checkCost announces the projected spend to modules and the supervisor
+// before any work starts. If it exceeds COST_WARN_THRESHOLD the run needs
+// --accept-cost or a confirmation; walkaway mode never asks.
+func (o *Orchestrator) checkCost() error {
+	estimate := roundCost(o.projectCost().Total)
+	o.supervisor.Status().SetProjectedCost(estimate)
+	if estimate <= 0 {
+		return nil
+	}
+	o.announceCost(estimate)
+
+	threshold := o.config.CostWarnThreshold
+	if threshold <= 0 || estimate <= threshold {
+		return nil
+	}
+	o.costOverrun = true
+	accepted := o.acceptCost
+	if accepted {
+		o.logger.Warn("projected cost over threshold, accepted", "estimate", estimate, "threshold", threshold)
+	} else {
+		accepted = !o.config.WalkawayMode && o.confirmCost != nil && o.confirmCost(estimate, threshold)
+	}
+	if !accepted {
+		return &BudgetError{Spent: estimate, Limit: threshold, Projected: true}
+	}
+	_, err := o.policy.Approve(policy.AcceptCost, o.prd.Path(), fmt.Sprintf("projected $%.2f over COST_WARN_THRESHOLD $%.2f", estimate, threshold))
+	return err
+}
+
+// updateCostProjection re-projects the total cost with the durations
+// recorded so far. The first time the projection goes over
+// COST_WARN_THRESHOLD it is logged and announced again.
+func (o *Orchestrator) updateCostProjection() {
+	estimate := roundCost(o.projectCost().Total)
+	o.supervisor.Status().SetProjectedCost(estimate)
+
+	threshold := o.config.CostWarnThreshold
+	if threshold <= 0 || estimate <= threshold || o.costOverrun {
+		return
+	}
+	o.costOverrun = true
+	o.logger.Warn("projected cost now over threshold", "estimate", estimate, "threshold", threshold)
+	o.announceCost(estimate)
+}
+
+// tierCommand returns the worker command configured for a tier.
+func tierCommand(cfg *config.Config, tier state.WorkerTier) string {
+	switch tier {
+	case state.TierSous:
+		return cfg.SousCmd
+	case state.TierExecutive:
+		return cfg.ExecutiveCmd
+	}
+	return cfg.LineCmd
+}
+
+// workerCommand returns the worker command a tier's attempts run.
+func (o *Orchestrator) workerCommand(tier state.WorkerTier) string {
+	return tierCommand(o.config, tier)
+}
+
+// applyHistoryTimeouts sets each tier's worker timeout from how long its
+// command's attempts took in earlier runs (see cost.Stats.SuggestedTimeout)
+// when TASK_TIMEOUT_FROM_HISTORY is on. Tiers without enough history keep
+// TASK_TIMEOUT_*.
+func applyHistoryTimeouts(cfg *config.Config, prdPath string, logger *slog.Logger) {
+	if !cfg.TaskTimeoutFromHistory {
+		return
+	}
+	stats := cost.LoadStats(filepath.Dir(prdPath), prdPath)
+	for tier, timeout := range map[state.WorkerTier]*time.Duration{
+		state.TierLine:      &cfg.TaskTimeoutJunior,
+		state.TierSous:      &cfg.TaskTimeoutSenior,
+		state.TierExecutive: &cfg.TaskTimeoutExecutive,
+	} {
+		if suggested := stats.SuggestedTimeout(tierCommand(cfg, tier)); suggested > 0 {
+			logger.Info("worker timeout from history", "tier", tier, "timeout", suggested, "configured", *timeout)
+			*timeout = suggested
+		}
+	}
+}
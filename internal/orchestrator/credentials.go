@@ -0,0 +1,242 @@
+package orchestrator
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"brigade/internal/config"
+	"brigade/internal/prd"
+	"brigade/internal/state"
+	"brigade/internal/worker"
+)
+
+// loadCredentials parses each tier's *_CREDENTIALS.
+func loadCredentials(cfg *config.Config) (map[state.WorkerTier][]worker.Credential, error) {
+	credentials := make(map[state.WorkerTier][]worker.Credential)
+	for _, c := range []struct {
+		key  string
+		tier state.WorkerTier
+		spec string
+	}{
+		{"LINE_CREDENTIALS", state.TierLine, cfg.LineCredentials},
+		{"SOUS_CREDENTIALS", state.TierSous, cfg.SousCredentials},
+		{"EXECUTIVE_CREDENTIALS", state.TierExecutive, cfg.ExecutiveCredentials},
+	} {
+		creds, err := worker.ParseCredentials(c.spec)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", c.key, err)
+		}
+		if len(creds) > 0 {
+			credentials[c.tier] = creds
+		}
+	}
+	return credentials, nil
+}
+
+// loadWorkerEnv returns the gateway, if there is one, and the environment
+// each tier's workers run with: their provider's or the gateway's settings,
+// with OFFLINE, the guard that keeps them off the internet, and with
+// DETERMINISTIC, the pinned sampling.
+func loadWorkerEnv(cfg *config.Config, credentials map[state.WorkerTier][]worker.Credential, logger *slog.Logger) (*worker.Gateway, map[state.WorkerTier][]string, error) {
+	gateway, err := worker.ParseGateway(cfg.GatewayURL, cfg.GatewayAPIKey, cfg.GatewayHeaders)
+	if err != nil {
+		return nil, nil, err
+	}
+	if cfg.Offline {
+		if err := checkOffline(cfg, gateway); err != nil {
+			return nil, nil, err
+		}
+	}
+	env, err := loadProviderEnv(cfg, gateway, credentials, logger)
+	if err != nil {
+		return nil, nil, err
+	}
+	for tier := range env {
+		env[tier] = append(env[tier], offlineEnv(cfg)...)
+		if s := sampling(cfg); s != nil {
+			env[tier] = append(env[tier], s.Env()...)
+		}
+	}
+	return gateway, env, nil
+}
+
+// WorkerEnv returns the environment for a tier's worker run outside the
+// service (plan, replan, explore and the like), which uses the tier's first
+// credential rather than rotating.
+func WorkerEnv(cfg *config.Config, tier state.WorkerTier) ([]string, error) {
+	credentials, err := loadCredentials(cfg)
+	if err != nil {
+		return nil, err
+	}
+	_, env, err := loadWorkerEnv(cfg, credentials, slog.New(slog.DiscardHandler))
+	if err != nil {
+		return nil, err
+	}
+	if creds := credentials[tier]; len(creds) > 0 {
+		return append(env[tier], creds[0].Env...), nil
+	}
+	return env[tier], nil
+}
+
+// loadProviderEnv returns the environment each tier's workers need to
+// reach their agent's provider (see worker.ProviderEnv), or the gateway
+// when there is one.
+func loadProviderEnv(cfg *config.Config, gateway *worker.Gateway, credentials map[state.WorkerTier][]worker.Credential, logger *slog.Logger) (map[state.WorkerTier][]string, error) {
+	settings := worker.ProviderSettings{
+		BedrockRegion:  cfg.BedrockRegion,
+		BedrockProfile: cfg.BedrockProfile,
+		AzureResource:  cfg.AzureOpenAIResource,
+		LocalModelURL:  cfg.LocalModelURL,
+	}
+	env := make(map[state.WorkerTier][]string)
+	for _, t := range []struct {
+		key  string
+		tier state.WorkerTier
+	}{
+		{"LINE_AGENT", state.TierLine},
+		{"SOUS_AGENT", state.TierSous},
+		{"EXECUTIVE_AGENT", state.TierExecutive},
+	} {
+		agent := tierAgent(cfg, t.tier)
+		if gateway != nil {
+			tierEnv, err := gateway.Env(agent)
+			if err != nil {
+				return nil, fmt.Errorf("%s=%s: %w", t.key, agent, err)
+			}
+			if !worker.UsesClaudeCLI(agent) {
+				logger.Warn("gateway tags are only sent by Claude workers", "tier", t.tier, "agent", agent)
+			}
+			env[t.tier] = tierEnv
+			continue
+		}
+
+		var credentialEnv [][]string
+		for _, c := range credentials[t.tier] {
+			credentialEnv = append(credentialEnv, c.Env)
+		}
+		tierEnv, err := worker.ProviderEnv(agent, tierCommand(cfg, t.tier), settings, credentialEnv)
+		if err != nil {
+			return nil, fmt.Errorf("%s=%s: %w", t.key, agent, err)
+		}
+		env[t.tier] = tierEnv
+	}
+	return env, nil
+}
+
+// tierAgent returns the agent a tier's worker command runs.
+func tierAgent(cfg *config.Config, tier state.WorkerTier) string {
+	switch tier {
+	case state.TierSous:
+		return cfg.SousAgent
+	case state.TierExecutive:
+		return cfg.ExecutiveAgent
+	}
+	return cfg.LineAgent
+}
+
+// gatewayEnv returns the environment that tags an attempt's requests to the
+// gateway, or nil without one.
+func (o *Orchestrator) gatewayEnv(task *prd.Task, tier state.WorkerTier) []string {
+	if o.gateway == nil {
+		return nil
+	}
+	return o.gateway.AttemptEnv(tierAgent(o.config, tier), worker.AttemptTags{
+		PRD:     o.prd.Prefix(),
+		TaskID:  task.ID,
+		Tier:    string(tier),
+		Attempt: o.state.TotalAttempts(task.ID) + 1,
+	})
+}
+
+// limitKey names what a rate limit applies to: a worker command, or a
+// command run with one of its tier's credentials.
+func (o *Orchestrator) limitKey(tier state.WorkerTier, credential string) string {
+	if credential == "" {
+		return o.workerCommand(tier)
+	}
+	return o.workerCommand(tier) + " [" + credential + "]"
+}
+
+// pickCredential returns the credential a tier's next attempt runs with:
+// the one in use, until it's rate limited or rejected, then the next in
+// order that isn't. When every credential is limited, the one that resets
+// first is used; when every one was rejected, nil (the worker falls back to
+// Brigade's own environment). Returns nil for tiers without credentials.
+func (o *Orchestrator) pickCredential(tier state.WorkerTier) *worker.Credential {
+	creds := o.credentials[tier]
+	if len(creds) == 0 {
+		return nil
+	}
+
+	o.rateLimits.mu.Lock()
+	defer o.rateLimits.mu.Unlock()
+	if o.rateLimits.current == nil {
+		o.rateLimits.current = make(map[state.WorkerTier]string)
+	}
+
+	start := 0
+	for i, c := range creds {
+		if c.Name == o.rateLimits.current[tier] {
+			start = i
+		}
+	}
+	now := time.Now()
+	var pick, soonest *worker.Credential
+	for i := range creds {
+		c := &creds[(start+i)%len(creds)]
+		if o.rateLimits.rejected[o.limitKey(tier, c.Name)] {
+			continue
+		}
+		until := o.rateLimits.until[o.limitKey(tier, c.Name)]
+		if !until.After(now) {
+			pick = c
+			break
+		}
+		if soonest == nil || until.Before(o.rateLimits.until[o.limitKey(tier, soonest.Name)]) {
+			soonest = c
+		}
+	}
+	if pick == nil {
+		pick = soonest
+	}
+	if pick == nil {
+		return nil
+	}
+
+	if previous := o.rateLimits.current[tier]; previous != "" && previous != pick.Name {
+		o.logger.Info("rotating worker credential", "worker", tier, "from", previous, "to", pick.Name)
+	}
+	o.rateLimits.current[tier] = pick.Name
+	return pick
+}
+
+// rejectCredential sets aside a credential the provider rejected for the
+// rest of the run, and returns how many of the tier's credentials are left.
+func (o *Orchestrator) rejectCredential(task *prd.Task, tier state.WorkerTier, credential string) int {
+	o.rateLimits.mu.Lock()
+	if o.rateLimits.rejected == nil {
+		o.rateLimits.rejected = make(map[string]bool)
+	}
+	o.rateLimits.rejected[o.limitKey(tier, credential)] = true
+	remaining := 0
+	for _, c := range o.credentials[tier] {
+		if !o.rateLimits.rejected[o.limitKey(tier, c.Name)] {
+			remaining++
+		}
+	}
+	o.rateLimits.mu.Unlock()
+
+	o.state.AddCredentialFailure(state.CredentialFailure{
+		Tier:       tier,
+		Credential: credential,
+		TaskID:     task.ID,
+		Reason:     "auth_failed",
+	})
+	o.logger.Error("worker credential rejected",
+		"task", o.prd.FormatTaskID(task.ID),
+		"worker", tier,
+		"credential", credential,
+		"remaining", remaining)
+	return remaining
+}
@@ -0,0 +1,47 @@
+package orchestrator
+
+import (
+	"brigade/internal/config"
+	"brigade/internal/state"
+	"brigade/internal/worker"
+)
+
+// applyDeterministic takes the noise out of a DETERMINISTIC run that Brigade
+// controls itself: tasks run one at a time, in order, and every executive
+// answer is asked for rather than reused from another run.
+func applyDeterministic(cfg *config.Config) {
+	if !cfg.Deterministic {
+		return
+	}
+	cfg.MaxParallel = 0
+	cfg.ExecCacheTTL = 0
+}
+
+// sampling returns what a DETERMINISTIC run pins workers to, or nil.
+func sampling(cfg *config.Config) *worker.Sampling {
+	if !cfg.Deterministic {
+		return nil
+	}
+	return &worker.Sampling{Seed: int64(cfg.DeterministicSeed), Temperature: 0}
+}
+
+// recordSampling notes a deterministic run's sampling in the state file,
+// and warns about tiers whose workers can't be pinned.
+func (o *Orchestrator) recordSampling() {
+	s := sampling(o.config)
+	if s == nil {
+		return
+	}
+	run := state.SamplingRun{Seed: s.Seed, Temperature: s.Temperature}
+	for _, tier := range []state.WorkerTier{state.TierLine, state.TierSous, state.TierExecutive} {
+		agent := tierAgent(o.config, tier)
+		if worker.SamplingPinned(agent) {
+			run.Pinned = append(run.Pinned, tier)
+			continue
+		}
+		run.Unpinned = append(run.Unpinned, tier)
+		o.logger.Warn("deterministic: worker CLI chooses its own sampling", "tier", tier, "agent", agent)
+	}
+	o.state.AddSamplingRun(run)
+	o.logger.Info("deterministic run", "seed", s.Seed, "temperature", s.Temperature, "pinned", run.Pinned)
+}
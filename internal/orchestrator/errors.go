@@ -0,0 +1,79 @@
+package orchestrator
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// BlockedError is returned when the service cannot make further progress
+// without outside help: a task failed with no one to decide, or no pending
+// task has its dependencies met.
+type BlockedError struct {
+	TaskID   string // Task that failed, empty if nothing was ready
+	Reason   string
+	Pending  []string  // Tasks left unfinished
+	Blockers []Blocker // Given-up tasks and the pending tasks stuck behind them
+}
+
+// Blocker is a task that was given up on and the unfinished tasks that
+// can't run without it.
+type Blocker struct {
+	TaskID string
+	Blocks []string
+}
+
+func (e *BlockedError) Error() string {
+	if e.TaskID != "" {
+		return fmt.Sprintf("task %s failed: %s", e.TaskID, e.Reason)
+	}
+	msg := "blocked: " + e.Reason
+	for _, b := range e.Blockers {
+		msg += fmt.Sprintf("; %s was skipped, blocking %s", b.TaskID, strings.Join(b.Blocks, ", "))
+	}
+	if len(e.Pending) > 0 {
+		msg += " (pending: " + strings.Join(e.Pending, ", ") + ")"
+	}
+	return msg
+}
+
+// TimeoutError is returned when the service gives up waiting.
+type TimeoutError struct {
+	What  string
+	After time.Duration
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("%s after %v, aborting", e.What, e.After.Round(time.Second))
+}
+
+// BudgetError is returned when a run would exceed its cost budget.
+type BudgetError struct {
+	Spent     float64 // USD
+	Limit     float64 // USD
+	Projected bool    // Spent is an estimate made before the run started
+}
+
+func (e *BudgetError) Error() string {
+	if e.Projected {
+		return fmt.Sprintf("projected cost $%.2f exceeds COST_WARN_THRESHOLD $%.2f (rerun with --accept-cost to start anyway)", e.Spent, e.Limit)
+	}
+	return fmt.Sprintf("cost budget exceeded: $%.2f of $%.2f", e.Spent, e.Limit)
+}
+
+// SessionLimitError is returned when a walkaway session stops at
+// WALKAWAY_MAX_DURATION with work left.
+type SessionLimitError struct {
+	Limit     time.Duration
+	Completed int
+	Total     int
+	Handoff   string // Path of the handoff summary, if written
+}
+
+func (e *SessionLimitError) Error() string {
+	msg := fmt.Sprintf("walkaway session limit %v reached with %d/%d tasks complete", e.Limit, e.Completed, e.Total)
+	if e.Handoff != "" {
+		msg += " (handoff: " + e.Handoff + ")"
+	}
+	return msg
+}
@@ -0,0 +1,122 @@
+package orchestrator
+
+import (
+	"strings"
+	"sync"
+
+	"brigade/internal/prd"
+	"brigade/internal/state"
+	"brigade/internal/util"
+	"brigade/internal/worker"
+)
+
+// attemptDiffs tracks the code each tier's attempts on a task changed, so
+// an escalated worker sees the attempted code and not only the approach
+// summaries. Tasks are diffed against the files already changed when their
+// first attempt started.
+type attemptDiffs struct {
+	mu        sync.Mutex
+	baselines map[string]map[string]bool
+	diffs     map[string][]worker.AttemptDiff
+}
+
+// escalationDiffsEnabled reports whether attempted code is collected.
+func (o *Orchestrator) escalationDiffsEnabled() bool {
+	return o.config.EscalationEnabled && o.config.EscalationDiffMax > 0
+}
+
+// baselineTask records the files already changed before a task's first
+// attempt. A task resumed from an earlier run has no baseline, so
+// everything left changed counts as its attempts' work.
+func (o *Orchestrator) baselineTask(task *prd.Task) {
+	if !o.escalationDiffsEnabled() {
+		return
+	}
+	d := &o.attemptDiffs
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.baselines == nil {
+		d.baselines = make(map[string]map[string]bool)
+	}
+	if _, ok := d.baselines[task.ID]; ok {
+		return
+	}
+	baseline := make(map[string]bool)
+	if o.state.TotalAttempts(task.ID) == 0 {
+		for _, f := range util.GitChangedFiles() {
+			baseline[f] = true
+		}
+	}
+	d.baselines[task.ID] = baseline
+}
+
+// recordAttemptDiff saves the code a task's attempts on a tier have left
+// changed, or marks it reverted if the tree no longer has it.
+func (o *Orchestrator) recordAttemptDiff(task *prd.Task, tier state.WorkerTier) {
+	if !o.escalationDiffsEnabled() {
+		return
+	}
+	d := &o.attemptDiffs
+	d.mu.Lock()
+	baseline := d.baselines[task.ID]
+	d.mu.Unlock()
+
+	var files []string
+	for _, f := range util.GitChangedFiles() {
+		if !baseline[f] && !strings.HasPrefix(f, "brigade/") {
+			files = append(files, f)
+		}
+	}
+	diff, truncated := truncateDiff(util.GitDiff(files), o.config.EscalationDiffMax)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.diffs == nil {
+		d.diffs = make(map[string][]worker.AttemptDiff)
+	}
+	entries := d.diffs[task.ID]
+	for i := range entries {
+		if entries[i].Tier != tier {
+			continue
+		}
+		if diff != "" {
+			entries[i] = worker.AttemptDiff{Tier: tier, Diff: diff, Truncated: truncated}
+		} else {
+			entries[i].Reverted = true
+		}
+		return
+	}
+	if diff != "" {
+		d.diffs[task.ID] = append(entries, worker.AttemptDiff{Tier: tier, Diff: diff, Truncated: truncated})
+	}
+}
+
+// escalationDiffs returns the attempted code to show a task's escalated
+// worker: what each lower tier tried and reverted, and what the latest
+// tier left in the tree. Diffs are cumulative, so earlier tiers' leftovers
+// are part of the latest one.
+func (o *Orchestrator) escalationDiffs(taskID string) []worker.AttemptDiff {
+	d := &o.attemptDiffs
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	entries := d.diffs[taskID]
+	var shown []worker.AttemptDiff
+	for i, e := range entries {
+		if e.Reverted || i == len(entries)-1 {
+			shown = append(shown, e)
+		}
+	}
+	return shown
+}
+
+// truncateDiff cuts a diff to at most max bytes at a line boundary.
+func truncateDiff(diff string, max int) (string, bool) {
+	if len(diff) <= max {
+		return diff, false
+	}
+	cut := diff[:max]
+	if i := strings.LastIndex(cut, "\n"); i > 0 {
+		cut = cut[:i+1]
+	}
+	return cut, true
+}
@@ -0,0 +1,83 @@
+package orchestrator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"brigade/internal/prd"
+	"brigade/internal/state"
+	"brigade/internal/util"
+	"brigade/internal/verify"
+)
+
+// codeFingerprint hashes HEAD and the contents of every uncommitted file
+// outside brigade/, so two verification runs of the same code can be told
+// apart from runs after an edit. Returns "" outside a git repository.
+func codeFingerprint() string {
+	head := util.GetHeadCommit()
+	if head == "unknown" {
+		return ""
+	}
+
+	var files []string
+	for _, f := range util.GitChangedFiles() {
+		if !strings.HasPrefix(f, "brigade/") {
+			files = append(files, f)
+		}
+	}
+	sort.Strings(files)
+
+	h := sha256.New()
+	io.WriteString(h, head+"\n")
+	for _, f := range files {
+		io.WriteString(h, f+"\n")
+		if data, err := os.ReadFile(f); err == nil {
+			h.Write(data)
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// recordVerification saves a verification run in state, tagging commands
+// as flaky if they passed only on a retry or flipped since the last run of
+// the same code.
+func (o *Orchestrator) recordVerification(task *prd.Task, result *verify.Result) {
+	failures := verificationFailures(result)
+	run := state.VerificationRun{
+		TaskID:   task.ID,
+		Passed:   result.Passed,
+		Duration: int(result.Duration.Milliseconds()),
+		Tree:     codeFingerprint(),
+	}
+	for _, f := range failures {
+		if !f.Log {
+			run.Failed = append(run.Failed, f.Command)
+		}
+	}
+
+	var prev *state.VerificationRun
+	if run.Tree != "" {
+		prev = o.state.LastVerification(task.ID, run.Tree)
+	}
+	for _, r := range result.Results {
+		flaky := r.Flaky
+		if prev != nil {
+			failedBefore := false
+			for _, cmd := range prev.Failed {
+				failedBefore = failedBefore || cmd == r.Command
+			}
+			flaky = flaky || failedBefore == r.Passed
+		}
+		if flaky {
+			o.logger.Warn("flaky verification command", "task", task.ID, "command", r.Command, "retries", r.Retries)
+			run.Flaky = append(run.Flaky, r.Command)
+		}
+	}
+
+	o.state.AddVerification(run)
+	o.state.SetVerificationFailures(task.ID, failures)
+}
@@ -0,0 +1,99 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"brigade/internal/prd"
+	"brigade/internal/util"
+	"brigade/internal/verify"
+)
+
+// testGateDue reports whether the full test suite should run before task
+// is marked complete, per TEST_GATE.
+func (o *Orchestrator) testGateDue(task *prd.Task) bool {
+	if o.config.TestCmd == "" {
+		return false
+	}
+
+	// Count the task being completed
+	done, _ := o.prd.Progress()
+	if !task.Passes {
+		done++
+	}
+
+	switch o.config.TestGate {
+	case "task":
+		return true
+	case "every":
+		return o.config.TestGateEvery > 0 && done%o.config.TestGateEvery == 0
+	case "phase":
+		return o.phaseBoundary(task)
+	}
+	return false
+}
+
+// runTestGate runs TEST_CMD before task is marked complete.
+func (o *Orchestrator) runTestGate(ctx context.Context, task *prd.Task) error {
+	err := o.runGate(ctx, task, "test gate", o.config.TestCmd, o.config.TestTimeout)
+	if err == nil {
+		// Verification passed to get here, so anything recorded is a stale gate failure
+		o.state.SetVerificationFailures(task.ID, nil)
+	}
+	return err
+}
+
+// runBuildGate runs BUILD_CMD after an attempt, if configured.
+func (o *Orchestrator) runBuildGate(ctx context.Context, task *prd.Task) error {
+	if o.config.BuildCmd == "" {
+		return nil
+	}
+	return o.runGate(ctx, task, "build gate", o.config.BuildCmd, o.config.BuildTimeout)
+}
+
+// runPreCommitHooks runs the repo's pre-commit hooks against the files the
+// task changed, so its work will pass them when someone commits or pushes
+// it. PRE_COMMIT_HOOKS=auto detects the hook manager, and any other value
+// but off is the command to run instead.
+func (o *Orchestrator) runPreCommitHooks(ctx context.Context, task *prd.Task) error {
+	var command string
+	switch o.config.PreCommitHooks {
+	case "", "off":
+		return nil
+	case "auto":
+		manager := verify.DetectHooks(".")
+		if manager == "" {
+			return nil
+		}
+		var files []string
+		for _, f := range util.GitChangedSince(o.taskStart(task.ID)) {
+			if !strings.HasPrefix(f, "brigade/") {
+				files = append(files, f)
+			}
+		}
+		if len(files) == 0 {
+			return nil
+		}
+		command = verify.HooksCommand(manager, files)
+	default:
+		command = o.config.PreCommitHooks
+	}
+	return o.runGate(ctx, task, "pre-commit hooks", command, o.config.PreCommitTimeout)
+}
+
+// runGate runs a repo-wide check command. On failure it records the output
+// as a verification failure for the retry prompt and returns an error
+// carrying the output for the classifier.
+func (o *Orchestrator) runGate(ctx context.Context, task *prd.Task, name, command string, timeout time.Duration) error {
+	o.logger.Info("running "+name, "task", task.ID, "cmd", command)
+	result, err := verify.NewRunner(timeout, "").RunTestCmd(ctx, command)
+	if err != nil || result == nil || result.Passed {
+		return err
+	}
+
+	failures := verificationFailures(&verify.Result{Results: []verify.CommandResult{*result}})
+	o.state.SetVerificationFailures(task.ID, failures)
+	return fmt.Errorf("%s failed: %s (%s)\n%s", name, command, result.Error, failures[0].Output)
+}
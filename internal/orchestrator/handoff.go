@@ -0,0 +1,269 @@
+package orchestrator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"brigade/internal/state"
+	"brigade/internal/util"
+	"brigade/internal/verify"
+)
+
+// sessionLimitReached reports whether a walkaway session has run for
+// WALKAWAY_MAX_DURATION.
+func (o *Orchestrator) sessionLimitReached() bool {
+	limit := o.config.WalkawayMaxDuration
+	return o.config.WalkawayMode && limit > 0 && time.Since(o.sessionStart) >= limit
+}
+
+// endSession stops a walkaway session that hit WALKAWAY_MAX_DURATION. The
+// task in flight has already finished; the handoff summary is written once
+// the run result is.
+func (o *Orchestrator) endSession() error {
+	done, total := o.prd.Progress()
+	o.logger.Warn("walkaway session limit reached, stopping",
+		"limit", o.config.WalkawayMaxDuration,
+		"completed", done,
+		"total", total)
+	if o.activity != nil {
+		o.activity.WriteState("LOOP_EXIT", "session_limit", o.config.WalkawayMaxDuration.String())
+	}
+	o.raiseAttention(fmt.Sprintf("walkaway session limit %v reached with %d/%d tasks complete", o.config.WalkawayMaxDuration, done, total))
+	return &SessionLimitError{Limit: o.config.WalkawayMaxDuration, Completed: done, Total: total}
+}
+
+// writeHandoff writes a summary of a stopped session for whoever picks the
+// PRD up next: progress, decisions waiting on a human, and anything unusual
+// that happened along the way.
+func (o *Orchestrator) writeHandoff(r *RunResult) error {
+	path := o.prd.HandoffPath()
+	if path == "" {
+		return fmt.Errorf("no PRD path for handoff file")
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("# Handoff: %s\n\n", o.prd.FeatureName))
+	sb.WriteString(fmt.Sprintf("Walkaway session stopped at its %v limit on %s.\n\n",
+		o.config.WalkawayMaxDuration, time.Now().Format("2006-01-02 15:04")))
+	sb.WriteString(fmt.Sprintf("**Progress:** %d/%d tasks complete, ~$%.2f spent this PRD.\n", r.Completed, r.Total, r.EstimatedCost))
+	if phase := o.prd.CurrentPhase(); phase > 0 {
+		done, total := o.prd.PhaseProgress(phase)
+		sb.WriteString(fmt.Sprintf("**Phase:** %d (%d/%d tasks)\n", phase, done, total))
+	}
+
+	// Progress
+	var finished, remaining []string
+	for _, t := range r.Tasks {
+		switch t.Status {
+		case state.StatusComplete, state.StatusAbsorbed:
+			if o.completedSince(t.ID, o.sessionStart) {
+				finished = append(finished, fmt.Sprintf("- %s: %s (%s, attempts: %d)", t.ID, t.Title, t.Worker, t.Attempts))
+			}
+		case state.StatusSkipped:
+		default:
+			line := fmt.Sprintf("- %s: %s", t.ID, t.Title)
+			if t.Error != "" {
+				line += fmt.Sprintf(" - last failure: %s", t.Error)
+			}
+			remaining = append(remaining, line)
+		}
+	}
+	writeHandoffSection(&sb, "Completed this session", finished)
+	writeHandoffSection(&sb, "Remaining", remaining)
+
+	// Decisions waiting on a human
+	var decisions []string
+	if o.supervisor.Events().Enabled() {
+		pending, err := o.supervisor.Events().PendingDecisions()
+		if err != nil {
+			o.logger.Warn("failed to read pending decisions", "error", err)
+		}
+		for _, d := range pending {
+			if d.PRD == "" || d.PRD == o.prd.Prefix() {
+				decisions = append(decisions, fmt.Sprintf("- %s (%s): %s", d.TaskID, d.ID, d.Question))
+			}
+		}
+	}
+	for _, t := range r.Tasks {
+		if t.Status == state.StatusSkipped {
+			decisions = append(decisions, fmt.Sprintf("- %s was skipped (%s); retry it or drop it from the PRD", t.ID, t.Error))
+		}
+	}
+	writeHandoffSection(&sb, "Pending decisions", decisions)
+
+	writeHandoffSection(&sb, "Anomalies", o.anomalies())
+
+	sb.WriteString("\n## Resume\n\n")
+	sb.WriteString(fmt.Sprintf("```bash\n./brigade-go resume %s\n```\n", o.prd.Path()))
+
+	return os.WriteFile(path, []byte(sb.String()), 0644)
+}
+
+// maxHandoffAPIs caps the new APIs a completion handoff lists.
+const maxHandoffAPIs = 30
+
+// writeCompletionHandoff writes what a finished PRD leaves the PRDs chained
+// after it: the decisions its tasks made, the APIs it added, and the
+// gotchas its workers ran into.
+func (o *Orchestrator) writeCompletionHandoff(r *RunResult) error {
+	path := o.prd.HandoffPath()
+	if path == "" {
+		return fmt.Errorf("no PRD path for handoff file")
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("# Handoff: %s\n\n", o.prd.FeatureName))
+	sb.WriteString(fmt.Sprintf("Completed %d tasks on %s", r.Total, time.Now().Format("2006-01-02 15:04")))
+	if o.prd.BranchName != "" {
+		sb.WriteString(fmt.Sprintf(" (branch %s)", o.prd.BranchName))
+	}
+	sb.WriteString(".\n")
+	if o.prd.Description != "" {
+		sb.WriteString(fmt.Sprintf("\n%s\n", o.prd.Description))
+	}
+
+	// Each task's completing approach, and where a junior tier fell short
+	var decisions, gotchas []string
+	var apis []verify.API
+	for _, task := range o.prd.Tasks {
+		completion := o.completion(task.ID)
+		if completion == nil {
+			continue
+		}
+		if completion.Approach != "" {
+			decisions = append(decisions, fmt.Sprintf("- %s (%s): %s", task.ID, task.Title, completion.Approach))
+		}
+		if completion.BaseCommit != "" && completion.BaseCommit != completion.Commit {
+			apis = append(apis, verify.DeclaredAPIs(util.GitDiffRange(completion.BaseCommit, completion.Commit, false))...)
+		}
+	}
+	for _, e := range o.state.Escalations {
+		decisions = append(decisions, fmt.Sprintf("- %s escalated %s → %s: %s", e.TaskID, e.From, e.To, e.Reason))
+	}
+	seen := make(map[string]bool)
+	for _, h := range o.state.TaskHistory {
+		for _, learning := range h.Learnings {
+			line := "- " + strings.Join(strings.Fields(learning), " ")
+			if !seen[line] {
+				seen[line] = true
+				gotchas = append(gotchas, line)
+			}
+		}
+	}
+	for _, t := range r.Tasks {
+		for _, cmd := range t.Flaky {
+			gotchas = append(gotchas, fmt.Sprintf("- `%s` is flaky", cmd))
+		}
+	}
+
+	var apiLines []string
+	for i, api := range apis {
+		if i == maxHandoffAPIs {
+			apiLines = append(apiLines, fmt.Sprintf("- ...and %d more", len(apis)-maxHandoffAPIs))
+			break
+		}
+		apiLines = append(apiLines, fmt.Sprintf("- `%s` in %s", api.Name, api.File))
+	}
+
+	writeHandoffSection(&sb, "Key decisions", decisions)
+	writeHandoffSection(&sb, "New APIs", apiLines)
+	writeHandoffSection(&sb, "Gotchas", gotchas)
+
+	return os.WriteFile(path, []byte(sb.String()), 0644)
+}
+
+// completion returns the attempt that completed a task, or nil.
+func (o *Orchestrator) completion(taskID string) *state.TaskHistory {
+	for i := len(o.state.TaskHistory) - 1; i >= 0; i-- {
+		h := &o.state.TaskHistory[i]
+		if h.TaskID == taskID && (h.Status == state.StatusComplete || h.Status == state.StatusAbsorbed) {
+			return h
+		}
+	}
+	return nil
+}
+
+// loadHandoffs reads the handoff docs of the PRDs before this one, for its
+// prompts. Missing docs are skipped.
+func loadHandoffs(paths []string) string {
+	var docs []string
+	seen := make(map[string]bool)
+	for _, path := range paths {
+		if abs, err := filepath.Abs(path); err == nil {
+			path = abs
+		}
+		if seen[path] {
+			continue
+		}
+		seen[path] = true
+		if data, err := os.ReadFile(path); err == nil {
+			docs = append(docs, strings.TrimSpace(string(data)))
+		}
+	}
+	return strings.Join(docs, "\n\n")
+}
+
+// writeHandoffSection writes a titled list, or "None" when it is empty.
+func writeHandoffSection(sb *strings.Builder, title string, lines []string) {
+	sb.WriteString(fmt.Sprintf("\n## %s\n\n", title))
+	if len(lines) == 0 {
+		sb.WriteString("None.\n")
+		return
+	}
+	sb.WriteString(strings.Join(lines, "\n"))
+	sb.WriteString("\n")
+}
+
+// completedSince reports whether a task's completion was recorded after t.
+func (o *Orchestrator) completedSince(taskID string, t time.Time) bool {
+	for _, h := range o.state.TaskHistory {
+		if h.TaskID != taskID || (h.Status != state.StatusComplete && h.Status != state.StatusAbsorbed) {
+			continue
+		}
+		if ts, err := time.Parse(time.RFC3339, h.Timestamp); err == nil && !ts.Before(t.Truncate(time.Second)) {
+			return true
+		}
+	}
+	return false
+}
+
+// anomalies lists what a reviewer should look at: escalations, repeated
+// approaches, flaky verification, phase reviews that raised concerns, and
+// the signals anomaly detection raised.
+func (o *Orchestrator) anomalies() []string {
+	var lines []string
+	for _, e := range o.state.Escalations {
+		lines = append(lines, fmt.Sprintf("- %s escalated %s → %s: %s", e.TaskID, e.From, e.To, e.Reason))
+	}
+	for _, task := range o.prd.Tasks {
+		for _, a := range o.state.GetApproachHistory(task.ID, 0) {
+			if a.RepeatOf > 0 {
+				lines = append(lines, fmt.Sprintf("- %s attempt %d repeated the approach of attempt %d", task.ID, a.Attempt, a.RepeatOf))
+			}
+		}
+	}
+	flaky := o.state.FlakyCommands()
+	var flakyTasks []string
+	for taskID := range flaky {
+		flakyTasks = append(flakyTasks, taskID)
+	}
+	sort.Strings(flakyTasks)
+	for _, taskID := range flakyTasks {
+		for cmd := range flaky[taskID] {
+			lines = append(lines, fmt.Sprintf("- %s has a flaky verification command: `%s`", taskID, cmd))
+		}
+	}
+	for _, r := range o.state.PhaseReviews {
+		if r.Status != phaseOnTrack {
+			lines = append(lines, fmt.Sprintf("- Phase review at %d/%d tasks: %s", r.CompletedTasks, r.TotalTasks, strings.ReplaceAll(r.Status, "_", " ")))
+		}
+	}
+	for _, reason := range o.anomalySignals() {
+		lines = append(lines, "- Anomaly detected: "+reason)
+	}
+	return lines
+}
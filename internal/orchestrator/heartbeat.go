@@ -0,0 +1,113 @@
+package orchestrator
+
+import (
+	"sync"
+	"time"
+
+	"brigade/internal/module"
+)
+
+// heartbeat publishes a heartbeat event every ACTIVITY_LOG_INTERVAL while
+// the service runs, following task events to know what's running.
+type heartbeat struct {
+	prefix  string
+	publish func(*module.Event)
+	spend   func() float64 // Spend per hour this session
+
+	mu       sync.Mutex
+	running  map[string]runningTask // By task ID
+	burnRate float64
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// runningTask is an attempt a worker is on.
+type runningTask struct {
+	worker  string
+	attempt int
+	start   time.Time
+}
+
+func newHeartbeat(prefix string, publish func(*module.Event)) *heartbeat {
+	return &heartbeat{prefix: prefix, publish: publish, running: make(map[string]runningTask)}
+}
+
+// Handle follows the attempts workers start and finish. It runs on the
+// goroutine that published the event, so it may read state. Subscribe it
+// to the event bus.
+func (h *heartbeat) Handle(event *module.Event) {
+	switch event.Type {
+	case module.EventTaskStart:
+		attempt, _ := event.Data["attempt"].(int)
+		h.mu.Lock()
+		h.running[event.TaskID] = runningTask{worker: event.Worker, attempt: attempt, start: time.Now()}
+		h.mu.Unlock()
+	case module.EventTaskIteration:
+		burnRate := 0.0
+		if h.spend != nil {
+			burnRate = h.spend()
+		}
+		h.mu.Lock()
+		delete(h.running, event.TaskID)
+		h.burnRate = burnRate
+		h.mu.Unlock()
+	case module.EventTaskComplete, module.EventTaskAbsorbed, module.EventTaskSkipped:
+		h.mu.Lock()
+		delete(h.running, event.TaskID)
+		h.mu.Unlock()
+	}
+}
+
+// Start publishes heartbeats every interval until Stop, with the burn rate
+// spend reports.
+func (h *heartbeat) Start(interval time.Duration, spend func() float64) {
+	h.spend = spend
+	if interval <= 0 || h.stop != nil {
+		return
+	}
+	h.stop = make(chan struct{})
+	h.done = make(chan struct{})
+	go func() {
+		defer close(h.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				h.publish(h.event())
+			case <-h.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop halts the heartbeats.
+func (h *heartbeat) Stop() {
+	if h.stop != nil {
+		close(h.stop)
+		<-h.done
+		h.stop, h.done = nil, nil
+	}
+}
+
+// event reports the longest-running attempt, with how many are running.
+func (h *heartbeat) event() *module.Event {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var taskID string
+	var current runningTask
+	for id, t := range h.running {
+		if taskID == "" || t.start.Before(current.start) {
+			taskID, current = id, t
+		}
+	}
+	var elapsed time.Duration
+	if taskID != "" {
+		elapsed = time.Since(current.start)
+	}
+	return module.HeartbeatEvent(h.prefix, taskID, current.worker, elapsed, current.attempt, h.burnRate).
+		WithData("running", len(h.running))
+}
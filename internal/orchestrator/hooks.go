@@ -0,0 +1,82 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+
+	"brigade/internal/classify"
+	"brigade/internal/prd"
+	"brigade/internal/state"
+	"brigade/internal/worker"
+)
+
+// maxHookOutput caps the hook output kept in a setup failure.
+const maxHookOutput = 2000
+
+// runTaskHook runs a task's setup or teardown command from the task's
+// workspace, with the task's env and TASK_HOOK_TIMEOUT.
+func (o *Orchestrator) runTaskHook(ctx context.Context, task *prd.Task, name, command string) error {
+	o.logger.Info("running task "+name, "task", task.ID, "cmd", command)
+	runner := o.taskVerifier(task)
+	runner.Timeout = o.config.TaskHookTimeout
+	result, err := runner.RunTestCmd(ctx, command)
+	if err != nil || result == nil || result.Passed {
+		return err
+	}
+	output := result.Output
+	if len(output) > maxHookOutput {
+		output = "..." + output[len(output)-maxHookOutput:]
+	}
+	return fmt.Errorf("%s failed: %s (%s)\n%s", name, command, result.Error, output)
+}
+
+// setupTask runs the task's setup command, if it has one. Returns a
+// teardown to run once the attempt has been verified, which is a no-op
+// when setup didn't run.
+func (o *Orchestrator) setupTask(ctx context.Context, task *prd.Task) (func(), error) {
+	if task.Setup == "" {
+		return func() {}, nil
+	}
+	if err := o.runTaskHook(ctx, task, "setup", task.Setup); err != nil {
+		// A half-started environment is still torn down
+		o.teardownTask(ctx, task)
+		return func() {}, err
+	}
+	return func() { o.teardownTask(ctx, task) }, nil
+}
+
+// teardownTask runs the task's teardown command, if it has one. It runs
+// even when the attempt was interrupted, and a failure is only logged: the
+// attempt's outcome is already decided.
+func (o *Orchestrator) teardownTask(ctx context.Context, task *prd.Task) {
+	if task.Teardown == "" {
+		return
+	}
+	if err := o.runTaskHook(context.WithoutCancel(ctx), task, "teardown", task.Teardown); err != nil {
+		o.logger.Warn("task teardown failed", "task", task.ID, "error", err)
+	}
+}
+
+// handleSetupFailure records an attempt whose setup failed. The worker never
+// ran, so the failure is an environment problem: it counts toward
+// MAX_ITERATIONS but doesn't escalate, since a bigger model can't fix it.
+func (o *Orchestrator) handleSetupFailure(ctx context.Context, task *prd.Task, w worker.Worker, err error) (attemptOutcome, error) {
+	o.logger.Warn("task setup failed", "task", task.ID, "error", err)
+
+	o.state.AddTaskHistory(state.TaskHistory{
+		TaskID:  task.ID,
+		Worker:  w.Tier(),
+		Command: o.workerCommand(w.Tier()),
+		Status:  state.StatusInProgress,
+	})
+	errorMsg := classify.ExtractErrorMessage(err.Error(), 100)
+	category := string(classify.CategoryEnvironment)
+	o.state.AddSessionFailure(task.ID, category, errorMsg, o.config.SmartRetrySessionFailuresMax)
+	o.state.ResolveAttempt(task.ID, state.StatusFailed, errorMsg, category)
+
+	if attempts := o.state.TotalAttempts(task.ID); attempts >= o.config.MaxIterations {
+		o.logger.Error("max iterations reached", "task", task.ID, "attempts", attempts)
+		return o.handleDecision(ctx, task, "task setup failed")
+	}
+	return outcomeRetry, nil
+}
@@ -0,0 +1,56 @@
+package orchestrator
+
+import (
+	"fmt"
+	"strings"
+
+	"brigade/internal/prd"
+)
+
+// blockers returns each skipped task with the selected, unfinished tasks
+// that can't run without it.
+func (o *Orchestrator) blockers() []Blocker {
+	var blockers []Blocker
+	for _, id := range o.state.SkippedTaskIDs() {
+		if blocks := o.unreachable(id); len(blocks) > 0 {
+			blockers = append(blockers, Blocker{TaskID: id, Blocks: blocks})
+		}
+	}
+	return blockers
+}
+
+// unreachable returns the selected, unfinished tasks downstream of taskID.
+func (o *Orchestrator) unreachable(taskID string) []string {
+	var ids []string
+	for _, id := range o.prd.Downstream(taskID) {
+		task := o.prd.TaskByID(id)
+		if task != nil && !task.Passes && (o.included == nil || o.included[id]) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// skipImpact describes what giving up on a task costs the rest of the run,
+// e.g. "skipping US-003 blocks 7 of 12 remaining tasks (US-004, ...)".
+func (o *Orchestrator) skipImpact(task *prd.Task) string {
+	remaining := 0
+	for _, t := range o.selected(o.prd.PendingTasks()) {
+		if t.ID != task.ID {
+			remaining++
+		}
+	}
+	blocked := o.unreachable(task.ID)
+	if len(blocked) == 0 {
+		return fmt.Sprintf("skipping %s blocks none of the %d remaining tasks", task.ID, remaining)
+	}
+	return fmt.Sprintf("skipping %s blocks %d of %d remaining tasks (%s)", task.ID, len(blocked), remaining, joinIDs(blocked, 5))
+}
+
+// joinIDs lists up to max task IDs, noting how many more there are.
+func joinIDs(ids []string, max int) string {
+	if len(ids) <= max {
+		return strings.Join(ids, ", ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(ids[:max], ", "), len(ids)-max)
+}
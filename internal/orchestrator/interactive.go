@@ -0,0 +1,158 @@
+package orchestrator
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"brigade/internal/module"
+	"brigade/internal/prd"
+	"brigade/internal/state"
+	"brigade/internal/supervisor"
+	"brigade/internal/util"
+	"brigade/internal/verify"
+	"brigade/internal/worker"
+)
+
+// ErrAttemptDiscarded is returned when a human discarded an attempt's
+// changes in interactive mode, leaving the task pending.
+var ErrAttemptDiscarded = errors.New("attempt discarded")
+
+// AttemptAction is what a human chose to do with an attempt.
+type AttemptAction string
+
+const (
+	AttemptAccept   AttemptAction = "accept"   // Complete the task with the attempt's changes
+	AttemptRetry    AttemptAction = "retry"    // Try again on the same tier, with guidance
+	AttemptEscalate AttemptAction = "escalate" // Try again on the next tier
+	AttemptDiscard  AttemptAction = "discard"  // Undo the attempt's changes and stop
+)
+
+// AttemptChoice is a human's decision about an attempt.
+type AttemptChoice struct {
+	Action   AttemptAction
+	Guidance string // For retry and escalate: passed to the next attempt
+}
+
+// AttemptReport is what an attempt did, for a human to judge.
+type AttemptReport struct {
+	TaskID       string
+	Title        string
+	Attempt      int
+	Worker       string
+	Passed       bool   // The worker finished and every check passed
+	Reason       string // Why it didn't pass
+	Base         string // Commit the attempt started from
+	DiffStat     string // Changes since Base, committed or not, outside brigade/
+	Diff         string
+	Verification *verify.Result // nil when verification didn't run
+}
+
+// attemptCheckpoint is where an attempt started, so its changes can be
+// shown and discarded.
+type attemptCheckpoint struct {
+	commit       string
+	dirty        map[string]string // Files already changed when it started, saved by util.GitSnapshot
+	verification *verify.Result
+}
+
+// checkpointAttempt records where an attempt starts, in interactive mode.
+func (o *Orchestrator) checkpointAttempt() {
+	if o.reviewAttempt == nil {
+		return
+	}
+	o.checkpoint = attemptCheckpoint{commit: util.GetHeadCommit()}
+	dirty, err := util.GitSnapshot()
+	if err != nil {
+		o.logger.Warn("failed to save uncommitted changes, the attempt can't be discarded", "error", err)
+		return
+	}
+	o.checkpoint.dirty = dirty
+}
+
+// askAttempt reports an attempt to the human reviewing it and applies
+// their choice. Accepting a passed attempt isn't handled, so it completes
+// as usual; a failed one the human accepts completes anyway.
+func (o *Orchestrator) askAttempt(task *prd.Task, w worker.Worker, result *worker.Result, reason string) (attemptOutcome, bool, error) {
+	base := o.checkpoint.commit
+	report := AttemptReport{
+		TaskID:       o.prd.FormatTaskID(task.ID),
+		Title:        task.Title,
+		Attempt:      o.state.TotalAttempts(task.ID),
+		Worker:       string(w.Tier()),
+		Passed:       reason == "",
+		Reason:       reason,
+		Base:         base,
+		DiffStat:     util.GitDiffRange(base, "", true, ".", ":(exclude)brigade/"),
+		Diff:         util.GitDiffRange(base, "", false, ".", ":(exclude)brigade/"),
+		Verification: o.checkpoint.verification,
+	}
+	choice := o.reviewAttempt(report)
+	o.logger.Info("attempt reviewed", "task", task.ID, "action", choice.Action)
+
+	if choice.Action == AttemptAccept {
+		if report.Passed {
+			return outcomeDone, false, nil
+		}
+		if last := o.state.LastAttempt(task.ID); last != nil {
+			last.Status, last.Error, last.Category = state.StatusInProgress, "", ""
+		}
+		o.state.AddReview(task.ID, "pass", "accepted by reviewer despite: "+reason)
+		o.markComplete(task, w, result.Duration, "")
+		return outcomeDone, true, nil
+	}
+
+	note := choice.Guidance
+	if note == "" {
+		note = reason
+	}
+	if note == "" {
+		note = "rejected by reviewer"
+	}
+	o.state.ResolveAttempt(task.ID, state.StatusFailed, note, "")
+	o.recordAttemptDiff(task, w.Tier())
+
+	switch choice.Action {
+	case AttemptDiscard:
+		// Without its snapshot, discarding would lose work from before the attempt
+		if o.checkpoint.dirty == nil {
+			return outcomeDone, true, fmt.Errorf("discarding changes: uncommitted changes from before the attempt weren't saved")
+		}
+		err := util.GitRestore(base, o.checkpoint.dirty, func(path string) bool {
+			return strings.HasPrefix(path, "brigade/")
+		})
+		if err != nil {
+			return outcomeDone, true, fmt.Errorf("discarding changes: %w", err)
+		}
+		o.state.ClearCurrentTask()
+		return outcomeDone, true, ErrAttemptDiscarded
+	case AttemptEscalate:
+		if next := nextTier(w.Tier()); next != "" {
+			o.state.AddEscalation(task.ID, w.Tier(), next, "escalated by reviewer")
+			o.events.Publish(module.EscalationEvent(o.prd.Prefix(), task.ID, string(w.Tier()), string(next), "escalated by reviewer").
+				WithData("attempts", report.Attempt).
+				WithData("categories", o.state.ErrorCategories(task.ID)).
+				WithData("lastError", o.state.LastError(task.ID)))
+		} else {
+			o.logger.Warn("already on the top tier, retrying", "task", task.ID, "worker", w.Tier())
+		}
+	}
+	if choice.Guidance != "" {
+		if err := o.nudges.Add(supervisor.Nudge{TaskID: task.ID, Message: choice.Guidance}); err != nil {
+			o.logger.Warn("failed to queue guidance", "task", task.ID, "error", err)
+		}
+	}
+	return outcomeRetry, true, nil
+}
+
+// nextTier returns the tier a task escalates to from tier, or "" from the
+// top.
+func nextTier(tier state.WorkerTier) state.WorkerTier {
+	switch tier {
+	case state.TierLine:
+		return state.TierSous
+	case state.TierSous:
+		return state.TierExecutive
+	}
+	return ""
+}
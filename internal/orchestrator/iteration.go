@@ -0,0 +1,69 @@
+package orchestrator
+
+import (
+	"fmt"
+	"strings"
+
+	"brigade/internal/prd"
+	"brigade/internal/state"
+	"brigade/internal/util"
+)
+
+// maxParentDiffLines caps the diff summary included in iteration prompts.
+const maxParentDiffLines = 40
+
+// buildParentContext summarizes a parent PRD for an iteration's workers:
+// what it built, how each task was completed, and what changed in the tree.
+func buildParentContext(parentPath, defaultBranch string) (string, error) {
+	parent, err := prd.Load(parentPath)
+	if err != nil {
+		return "", fmt.Errorf("loading parent PRD: %w", err)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("This task iterates on a completed feature: %s\n", parent.FeatureName))
+	if parent.Description != "" {
+		sb.WriteString(fmt.Sprintf("Description: %s\n", parent.Description))
+	}
+	if parent.BranchName != "" {
+		sb.WriteString(fmt.Sprintf("Branch: %s\n", parent.BranchName))
+	}
+
+	// Parent state is optional; completion details are a bonus
+	var st *state.State
+	if store := state.ForPRD(parentPath); store.Exists() {
+		st, _ = store.Load()
+	}
+
+	sb.WriteString("\nParent tasks:\n")
+	for _, task := range parent.Tasks {
+		mark := " "
+		if task.Passes {
+			mark = "x"
+		}
+		sb.WriteString(fmt.Sprintf("  [%s] %s: %s\n", mark, task.ID, task.Title))
+		if st == nil {
+			continue
+		}
+		if last := st.LastAttempt(task.ID); last != nil && last.Approach != "" {
+			sb.WriteString(fmt.Sprintf("      Approach: %s\n", last.Approach))
+		}
+		if feedback := st.GetLastReviewFeedback(task.ID); feedback != "" {
+			sb.WriteString(fmt.Sprintf("      Review: %s\n", feedback))
+		}
+	}
+
+	if diff := util.GitDiffStat(defaultBranch); diff != "" {
+		lines := strings.Split(diff, "\n")
+		if len(lines) > maxParentDiffLines {
+			lines = append(lines[:maxParentDiffLines-1], "  ...", lines[len(lines)-1])
+		}
+		sb.WriteString(fmt.Sprintf("\nFiles changed since %s:\n%s\n", defaultBranch, strings.Join(lines, "\n")))
+	}
+	if commits := util.GitRecentCommits(10); commits != "" {
+		sb.WriteString("\nRecent commits:\n" + commits + "\n")
+	}
+
+	sb.WriteString("\nKeep the change focused: build on the existing implementation rather than redoing it.")
+	return sb.String(), nil
+}
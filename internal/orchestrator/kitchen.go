@@ -0,0 +1,71 @@
+package orchestrator
+
+import (
+	"time"
+
+	"brigade/internal/state"
+)
+
+// kitchenReport sums up this run from the state: the wall time, each
+// tier's worker time, attempts, escalations, reviews, cost and the
+// categories attempts failed with. Entries from earlier runs are left out.
+func (o *Orchestrator) kitchenReport(finished time.Time) state.RunReport {
+	start := o.startTime.Truncate(time.Second)
+	inRun := func(timestamp string) bool {
+		t, err := time.Parse(time.RFC3339, timestamp)
+		return err == nil && !t.Before(start)
+	}
+
+	r := state.RunReport{
+		DurationSeconds: int(finished.Sub(o.startTime).Seconds()),
+		TierSeconds:     make(map[state.WorkerTier]int),
+		ErrorCategories: make(map[string]int),
+	}
+	var spent float64
+	for _, h := range o.state.TaskHistory {
+		if !inRun(h.Timestamp) || h.Status == state.StatusSkipped {
+			continue
+		}
+		r.Iterations++
+		r.TierSeconds[h.Worker] += h.Duration
+		spent += o.costEstimator.AttemptCost(h)
+		switch {
+		case h.Status == state.StatusComplete:
+			r.Completed++
+		case h.Category != "":
+			r.ErrorCategories[h.Category]++
+		}
+	}
+	for _, e := range o.state.Escalations {
+		if inRun(e.Timestamp) {
+			r.Escalations++
+		}
+	}
+	for _, rv := range o.state.Reviews {
+		if !inRun(rv.Timestamp) {
+			continue
+		}
+		if rv.Reused {
+			r.ReviewsReused++
+		}
+		if rv.Result == "pass" {
+			r.ReviewsPassed++
+		} else {
+			r.ReviewsFailed++
+		}
+	}
+	r.EstimatedCost = roundCost(spent)
+	return r
+}
+
+// recordReport keeps the run's kitchen report in the state file, so earlier
+// runs can be compared.
+func (o *Orchestrator) recordReport() {
+	if o.result == nil || o.result.Report == nil {
+		return
+	}
+	o.state.AddRunReport(*o.result.Report)
+	if err := o.store.Save(o.state); err != nil {
+		o.logger.Warn("failed to save kitchen report", "error", err)
+	}
+}
@@ -0,0 +1,103 @@
+package orchestrator
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"brigade/internal/prd"
+	"brigade/internal/state"
+	"brigade/internal/util"
+	"brigade/internal/verify"
+)
+
+// checkLicenseHeaders makes sure the files a task added start with the
+// LICENSE_HEADER_FILE header. With LICENSE_HEADER_FIX, missing headers are
+// added, and committed if the worker committed the files; otherwise they
+// are recorded as a verification failure and an error is returned. The
+// result describes the check for the executive review.
+func (o *Orchestrator) checkLicenseHeaders(task *prd.Task) ([]string, error) {
+	if o.licenseHeader == "" {
+		return nil, nil
+	}
+
+	committed, uncommitted := util.GitNewFiles(o.taskStart(task.ID))
+	wasCommitted := make(map[string]bool)
+	for _, f := range committed {
+		wasCommitted[f] = true
+	}
+
+	checked := 0
+	var missing []string
+	for _, f := range append(committed, uncommitted...) {
+		if strings.HasPrefix(f, "brigade/") || !verify.LicenseHeaderApplies(f) {
+			continue
+		}
+		data, err := os.ReadFile(f)
+		if err != nil {
+			continue // Deleted again since
+		}
+		checked++
+		if !verify.HasLicenseHeader(string(data), o.licenseHeader) {
+			missing = append(missing, f)
+		}
+	}
+	if checked == 0 {
+		return nil, nil
+	}
+	if len(missing) == 0 {
+		return []string{fmt.Sprintf("License header: present in all %d new files", checked)}, nil
+	}
+
+	if !o.config.LicenseHeaderFix {
+		report := fmt.Sprintf("%d new file(s) lack the license header: %s\nStart each with this header, as a comment:\n%s",
+			len(missing), strings.Join(missing, ", "), o.licenseHeader)
+		o.state.SetVerificationFailures(task.ID, []state.VerificationFailure{{
+			Command:  "license header check",
+			ExitCode: 1,
+			Output:   report,
+		}})
+		return nil, fmt.Errorf("license header check failed: %s", report)
+	}
+
+	year := time.Now().Year()
+	var fixed, unfixed, toCommit []string
+	for _, f := range missing {
+		info, err := os.Stat(f)
+		if err != nil {
+			unfixed = append(unfixed, f)
+			continue
+		}
+		data, err := os.ReadFile(f)
+		if err != nil {
+			unfixed = append(unfixed, f)
+			continue
+		}
+		content, ok := verify.AddLicenseHeader(f, string(data), o.licenseHeader, year)
+		if !ok || os.WriteFile(f, []byte(content), info.Mode().Perm()) != nil {
+			unfixed = append(unfixed, f)
+			continue
+		}
+		fixed = append(fixed, f)
+		if wasCommitted[f] {
+			toCommit = append(toCommit, f)
+		}
+	}
+	if len(toCommit) > 0 {
+		if err := util.GitCommitPaths(o.commits.Message(task, "add license headers"), toCommit, o.commits.Args()...); err != nil {
+			o.logger.Warn("failed to commit license headers", "task", task.ID, "error", err)
+		}
+	}
+
+	var notes []string
+	if len(fixed) > 0 {
+		o.logger.Info("added license headers", "task", task.ID, "files", len(fixed))
+		notes = append(notes, fmt.Sprintf("License header: missing from %s, added automatically", strings.Join(fixed, ", ")))
+	}
+	if len(unfixed) > 0 {
+		o.logger.Warn("failed to add license headers", "task", task.ID, "files", strings.Join(unfixed, ", "))
+		notes = append(notes, fmt.Sprintf("License header: missing from %s, and couldn't be added", strings.Join(unfixed, ", ")))
+	}
+	return notes, nil
+}
@@ -0,0 +1,64 @@
+package orchestrator
+
+import (
+	"fmt"
+	"log/slog"
+	"path/filepath"
+
+	"brigade/internal/config"
+	"brigade/internal/prd"
+	"brigade/internal/state"
+	"brigade/internal/worker"
+)
+
+// writeMCPConfigs builds the MCP config each tier's Claude workers get:
+// MCP_CONFIG, then the tier's own config, then the PRD's mcpServers, later
+// servers overriding earlier ones of the same name. Tiers without any
+// servers are left out.
+func writeMCPConfigs(cfg *config.Config, p *prd.PRD, logger *slog.Logger) (map[state.WorkerTier]string, error) {
+	var shared worker.MCPServers
+	if cfg.MCPConfig != "" {
+		servers, err := worker.LoadMCPServers(cfg.MCPConfig)
+		if err != nil {
+			return nil, fmt.Errorf("loading MCP_CONFIG: %w", err)
+		}
+		shared = servers
+	}
+
+	tiers := []struct {
+		tier   state.WorkerTier
+		config string
+		agent  string
+	}{
+		{state.TierLine, cfg.LineMCPConfig, cfg.LineAgent},
+		{state.TierSous, cfg.SousMCPConfig, cfg.SousAgent},
+		{state.TierExecutive, cfg.ExecutiveMCPConfig, cfg.ExecutiveAgent},
+	}
+
+	paths := make(map[state.WorkerTier]string)
+	for _, t := range tiers {
+		var own worker.MCPServers
+		if t.config != "" {
+			servers, err := worker.LoadMCPServers(t.config)
+			if err != nil {
+				return nil, fmt.Errorf("loading %s MCP config: %w", t.tier, err)
+			}
+			own = servers
+		}
+
+		path := filepath.Join("brigade", "mcp", fmt.Sprintf("%s-%s.json", p.Prefix(), t.tier))
+		wrote, err := worker.WriteMCPConfig(path, shared, own, worker.MCPServers(p.MCPServers))
+		if err != nil {
+			return nil, fmt.Errorf("writing %s MCP config: %w", t.tier, err)
+		}
+		if !wrote {
+			continue
+		}
+		if !worker.UsesClaudeCLI(t.agent) {
+			logger.Warn("MCP servers are only attached to Claude workers", "tier", t.tier, "agent", t.agent)
+			continue
+		}
+		paths[t.tier] = path
+	}
+	return paths, nil
+}
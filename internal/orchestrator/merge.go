@@ -0,0 +1,147 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"brigade/internal/worktree"
+)
+
+// MergeReport is how rebasing a worktree's branch onto the default branch
+// went.
+type MergeReport struct {
+	Onto     string   `json:"onto"`
+	Behind   int      `json:"behind"`             // Commits the branch was missing
+	Rebased  bool     `json:"rebased"`            // Branch is now on top of Onto
+	Resolved []string `json:"resolved,omitempty"` // Conflicts the sous chef resolved
+	Flagged  []string `json:"flagged,omitempty"`  // Conflicts left for a person
+	Error    string   `json:"error,omitempty"`
+}
+
+// mergeAssist rebases the finished PRD's branch onto the default branch.
+// Conflicts of at most MERGE_TRIVIAL_LINES lines go to the sous chef; if
+// any conflict is bigger, or the sous chef leaves markers behind, the
+// rebase is abandoned and the files are flagged for a person.
+func (o *Orchestrator) mergeAssist(ctx context.Context) *MergeReport {
+	if done, total := o.prd.Progress(); done < total {
+		return nil
+	}
+	branch := worktree.DefaultBranch(".", o.config.DefaultBranch)
+	if branch == "" {
+		o.logger.Warn("merge assist: no default branch found, set DEFAULT_BRANCH")
+		return &MergeReport{Error: "no default branch found"}
+	}
+	// git names conflicted files from the top of the repository, which
+	// needn't be where Brigade runs
+	root, err := worktree.Root(".")
+	if err != nil {
+		o.logger.Warn("merge assist: can't find the repository", "error", err)
+		return &MergeReport{Error: err.Error()}
+	}
+	report := &MergeReport{Onto: worktree.Upstream(".", branch)}
+	report.Behind = worktree.Behind(".", report.Onto)
+	if report.Behind == 0 {
+		report.Rebased = true
+		o.logger.Info("merge assist: branch is up to date", "onto", report.Onto)
+		return report
+	}
+	o.logger.Info("merge assist: rebasing", "onto", report.Onto, "behind", report.Behind)
+
+	conflicts, err := worktree.Rebase(root, report.Onto)
+	for len(conflicts) > 0 {
+		if flagged := o.largeConflicts(root, conflicts); len(flagged) > 0 {
+			return o.abandonMerge(report, flagged)
+		}
+		if err := o.resolveConflicts(ctx, report.Onto, fromRoot(root, conflicts)); err != nil {
+			o.logger.Warn("merge assist: sous chef failed", "error", err)
+			return o.abandonMerge(report, conflicts)
+		}
+		var unresolved []string
+		for _, f := range conflicts {
+			if content, err := os.ReadFile(filepath.Join(root, f)); err != nil || worktree.HasMarkers(string(content)) {
+				unresolved = append(unresolved, f)
+			}
+		}
+		if len(unresolved) > 0 {
+			return o.abandonMerge(report, unresolved)
+		}
+		report.Resolved = append(report.Resolved, conflicts...)
+		conflicts, err = worktree.Continue(root, conflicts)
+	}
+	if err != nil {
+		worktree.Abort(".")
+		report.Resolved = nil
+		report.Error = err.Error()
+		o.logger.Warn("merge assist: rebase failed", "error", err)
+		return report
+	}
+
+	report.Rebased = true
+	o.logger.Info("merge assist: rebased", "onto", report.Onto, "resolved", len(report.Resolved))
+	return report
+}
+
+// largeConflicts returns the conflicted files the sous chef shouldn't be
+// trusted with: too many conflicting lines, or no markers to edit.
+func (o *Orchestrator) largeConflicts(root string, files []string) []string {
+	var large []string
+	for _, f := range files {
+		content, err := os.ReadFile(filepath.Join(root, f))
+		if err != nil {
+			large = append(large, f)
+			continue
+		}
+		if lines, ok := worktree.ConflictSize(string(content)); !ok || lines > o.config.MergeTrivialLines {
+			large = append(large, f)
+		}
+	}
+	return large
+}
+
+// fromRoot turns paths from the top of the repository into paths from the
+// working directory, where the sous chef runs.
+func fromRoot(root string, files []string) []string {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return files
+	}
+	paths := make([]string, len(files))
+	for i, f := range files {
+		paths[i] = filepath.Join(root, f)
+		if rel, err := filepath.Rel(cwd, paths[i]); err == nil {
+			paths[i] = rel
+		}
+	}
+	return paths
+}
+
+// resolveConflicts asks the sous chef to resolve conflicts in place.
+func (o *Orchestrator) resolveConflicts(ctx context.Context, onto string, files []string) error {
+	prompt, err := o.promptBuilder.BuildMergePrompt(o.prd, onto, worktree.Replaying("."), files)
+	if err != nil {
+		return err
+	}
+	result, err := o.workers.Sous().Execute(ctx, prompt)
+	if err != nil {
+		return err
+	}
+	if result == nil || result.Timeout {
+		return fmt.Errorf("timed out")
+	}
+	return nil
+}
+
+// abandonMerge aborts the rebase, leaving the branch as the PRD left it,
+// and flags the conflicts for a person.
+func (o *Orchestrator) abandonMerge(report *MergeReport, flagged []string) *MergeReport {
+	commit := worktree.Replaying(".")
+	worktree.Abort(".")
+	report.Resolved = nil
+	report.Flagged = flagged
+	o.logger.Warn("merge assist: conflicts need a person", "onto", report.Onto, "commit", commit, "files", strings.Join(flagged, ", "))
+	o.raiseAttention(fmt.Sprintf("rebasing %s onto %s conflicts at %s: %s", o.prd.BranchName, report.Onto, commit, strings.Join(flagged, ", ")))
+	return report
+}
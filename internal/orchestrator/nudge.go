@@ -0,0 +1,85 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"brigade/internal/prd"
+	"brigade/internal/worker"
+)
+
+// nudgePollInterval is how often a running attempt checks for urgent nudges.
+const nudgePollInterval = 2 * time.Second
+
+// nudgeWatch tracks whether an urgent nudge cut a running attempt short.
+type nudgeWatch struct {
+	mu      sync.Mutex
+	running bool
+	nudged  bool
+}
+
+// finish records that the attempt's worker has returned, so a nudge arriving
+// from now on waits for the next prompt instead of stopping it.
+func (n *nudgeWatch) finish() {
+	n.mu.Lock()
+	n.running = false
+	n.mu.Unlock()
+}
+
+// interrupted reports whether the attempt ended because a nudge stopped it.
+// A worker that finished before the stop took effect keeps its result.
+func (n *nudgeWatch) interrupted(result *worker.Result, err error) bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if !n.nudged {
+		return false
+	}
+	return errors.Is(err, context.Canceled) || (result != nil && errors.Is(result.Error, context.Canceled))
+}
+
+// watchNudges polls for a `brigade nudge --now` aimed at the task while its
+// worker runs. CLI workers can't take input mid-run, so the attempt is
+// stopped with stop and restarted with the nudge in its prompt. Call finish
+// on the returned watch as soon as the worker returns.
+func (o *Orchestrator) watchNudges(ctx context.Context, task *prd.Task, stop context.CancelFunc) *nudgeWatch {
+	watch := &nudgeWatch{running: true}
+	go func() {
+		ticker := time.NewTicker(nudgePollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if o.nudges.Urgent(task.ID) {
+					watch.mu.Lock()
+					if watch.running {
+						watch.nudged = true
+						stop()
+					}
+					watch.mu.Unlock()
+					return
+				}
+			}
+		}
+	}()
+	return watch
+}
+
+// takeNudges removes the task's queued nudges for its next prompt.
+func (o *Orchestrator) takeNudges(task *prd.Task) []string {
+	nudges, err := o.nudges.Take(task.ID)
+	if err != nil {
+		o.logger.Warn("failed to read nudges", "task", task.ID, "error", err)
+	}
+	var messages []string
+	for _, n := range nudges {
+		messages = append(messages, n.Message)
+	}
+	if len(messages) > 0 {
+		o.logger.Info("delivering nudges", "task", o.prd.FormatTaskID(task.ID), "count", len(messages))
+	}
+	return messages
+}
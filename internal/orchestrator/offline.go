@@ -0,0 +1,79 @@
+package orchestrator
+
+import (
+	"fmt"
+	"net/url"
+
+	"brigade/internal/config"
+	"brigade/internal/offline"
+	"brigade/internal/retrieval"
+	"brigade/internal/state"
+	"brigade/internal/worker"
+)
+
+// checkOffline refuses an OFFLINE run whose workers would send code to a
+// hosted model. Every tier has to run a local agent, or go through a
+// gateway on the local network.
+func checkOffline(cfg *config.Config, gateway *worker.Gateway) error {
+	allowed := offline.ParseHosts(cfg.OfflineAllowedHosts)
+	if gateway != nil {
+		return offlineErr(offline.CheckURL("GATEWAY_URL", gateway.URL, allowed))
+	}
+	for _, t := range []struct {
+		key  string
+		tier state.WorkerTier
+	}{
+		{"LINE_AGENT", state.TierLine},
+		{"SOUS_AGENT", state.TierSous},
+		{"EXECUTIVE_AGENT", state.TierExecutive},
+	} {
+		if agent := tierAgent(cfg, t.tier); agent != worker.AgentLocal {
+			return fmt.Errorf("OFFLINE: %s=%s sends code to a hosted model; use %s (see LOCAL_MODEL_URL) or a GATEWAY_URL on your network",
+				t.key, agent, worker.AgentLocal)
+		}
+	}
+	return offlineErr(offline.CheckURL("LOCAL_MODEL_URL", localModelURL(cfg), allowed))
+}
+
+func offlineErr(err error) error {
+	if err != nil {
+		return fmt.Errorf("OFFLINE: %w", err)
+	}
+	return nil
+}
+
+// offlineEnv returns the environment that keeps workers, verification and
+// modules off the internet (see offline.Env), or nil when OFFLINE is off.
+// The model server's host is let through along with OFFLINE_ALLOWED_HOSTS.
+func offlineEnv(cfg *config.Config) []string {
+	if !cfg.Offline {
+		return nil
+	}
+	allowed := offline.ParseHosts(cfg.OfflineAllowedHosts)
+	for _, endpoint := range []string{cfg.GatewayURL, localModelURL(cfg)} {
+		if u, err := url.Parse(endpoint); err == nil && u.Hostname() != "" {
+			allowed = append(allowed, u.Hostname())
+		}
+	}
+	return offline.Env(allowed)
+}
+
+func localModelURL(cfg *config.Config) string {
+	if cfg.LocalModelURL != "" {
+		return cfg.LocalModelURL
+	}
+	return worker.DefaultLocalModelURL
+}
+
+// CheckOfflineEmbeddings returns an error if OFFLINE is on and the
+// embedding provider is remote. The hash embedder needs no network.
+func CheckOfflineEmbeddings(cfg *config.Config) error {
+	endpoint := cfg.EmbeddingURL
+	if endpoint == "" {
+		endpoint = retrieval.EmbedderURL(cfg.EmbeddingProvider)
+	}
+	if !cfg.Offline || endpoint == "" {
+		return nil
+	}
+	return offlineErr(offline.CheckURL("EMBEDDING_PROVIDER="+cfg.EmbeddingProvider, endpoint, offline.ParseHosts(cfg.OfflineAllowedHosts)))
+}
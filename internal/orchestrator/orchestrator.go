@@ -3,19 +3,27 @@ package orchestrator
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
-	"os"
 	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
 	"syscall"
 	"time"
 
+	"brigade/internal/bus"
 	"brigade/internal/classify"
 	"brigade/internal/config"
+	"brigade/internal/cost"
 	"brigade/internal/module"
+	"brigade/internal/policy"
 	"brigade/internal/prd"
+	"brigade/internal/services"
 	"brigade/internal/state"
 	"brigade/internal/supervisor"
+	"brigade/internal/util"
 	"brigade/internal/verify"
 	"brigade/internal/worker"
 )
@@ -29,22 +37,120 @@ type Orchestrator struct {
 	serviceLock  *state.ServiceLock
 	workers      *worker.Factory
 	promptBuilder *worker.PromptBuilder
+	chefDir      string
 	verifier     *verify.Runner
 	classifier   *classify.Classifier
 	modules      *module.Manager
+	events       *bus.Bus     // Where every event is published
+	metrics      *bus.Metrics // Counts of published events
 	supervisor   *supervisor.Supervisor
 	logger       *slog.Logger
 
 	// Activity and monitoring
-	activity *ActivityLogger
+	activity  *ActivityLogger
+	heartbeat *heartbeat
+
+	// Iteration context from the parent PRD (empty if not an iteration)
+	parentContext string
+
+	// Outcome of the PRDs this one depends on (empty without dependsOnPRD)
+	prerequisiteContext string
+
+	// Handoff docs left by the PRDs before this one
+	handoffContext string
+
+	// Record/replay of worker executions (nil when disabled)
+	recorder *worker.Recorder
+	replayer *worker.Replayer
+
+	// Failure injection (nil unless CHAOS_MODE)
+	chaos *worker.Chaos
+
+	// Outcome of the last run, also written to the result file
+	result *RunResult
+
+	// Interrupted attempt from a previous run, described in the task's next prompt
+	recoveryTask    string
+	recoveryContext string
+
+	// Guidance queued for tasks by `brigade nudge`
+	nudges *supervisor.NudgeQueue
+
+	// Cost projection: the pricing, earlier runs' task costs, whether the user
+	// accepted going over COST_WARN_THRESHOLD, and whether a mid-run overrun
+	// was reported
+	costEstimator *cost.Estimator
+	costHistory  cost.History
+	acceptCost   bool
+	confirmCost  func(estimate, threshold float64) bool
+	costOverrun  bool
+
+	// Overrides that need an operator's approval, and whether edits to
+	// PROTECTED_PATHS were allowed for this run
+	policy         *policy.Policy
+	allowProtected bool
+
+	// Files already changed when each task's current attempt started, for
+	// WORKSPACE_CONFINE_EDITS
+	confinement confinement
+
+	// Code each tier's attempts left on a task, shown when it escalates
+	attemptDiffs attemptDiffs
+
+	// Asks a human about each attempt in interactive mode (nil otherwise),
+	// and where the current attempt started
+	reviewAttempt func(AttemptReport) AttemptChoice
+	checkpoint    attemptCheckpoint
+
+	// Tasks this run is limited to by the partial execution filters (nil
+	// runs every task)
+	included map[string]bool
+
+	// Phase boundaries already reviewed
+	phases phaseTracker
+
+	// Walkaway anomaly signals and the diff sizes they're judged against
+	anomaly anomalyTracker
+
+	// When each worker command's provider rate limit resets, the
+	// credentials each tier rotates through, and the gateway requests are
+	// sent through
+	rateLimits  rateLimitTracker
+	credentials map[state.WorkerTier][]worker.Credential
+	gateway     *worker.Gateway
+
+	// Executive answers to reuse (nil when EXEC_CACHE_TTL is 0)
+	execCache *worker.Cache
+
+	// Services run around verification (nil when none are declared); one
+	// task's services run at a time, since they usually share ports
+	services   *services.Manager
+	servicesMu sync.Mutex
+
+	// Header new files must start with (empty when LICENSE_HEADER_FILE is
+	// unset)
+	licenseHeader string
+
+	// How task commits are written and signed
+	commits worker.CommitConvention
+
+	// Whether the PRD runs in its own worktree, and how rebasing its branch
+	// onto the default branch went once it finished
+	worktree bool
+	merge    *MergeReport
+
+	// When the PRD file was last read, to pick up edits mid-run
+	prdWatch prdWatch
 
 	// Runtime state
+	sessionStart     time.Time
 	startTime        time.Time
 	taskStartTime    time.Time
 	cancelled        bool
 	runningWorkers   []*workerExecution
 	lastProgressTime time.Time
 	idleWarningShown bool
+	paused           bool
 }
 
 // Options configures the orchestrator.
@@ -57,11 +163,47 @@ type Options struct {
 	WalkawayMode   bool
 	MaxIterations  int
 
+	// Workers overrides the worker factory built from config (e.g. mock workers)
+	Workers *worker.Factory
+
+	// ChefDir is the directory holding chef prompts (default "chef")
+	ChefDir string
+
+	// OnEvent is called for every event the service emits (e.g. CI output)
+	OnEvent func(*module.Event)
+
+	// A projected cost over COST_WARN_THRESHOLD needs AcceptCost or a yes
+	// from ConfirmCost (nil when no one can answer) before the run starts
+	AcceptCost  bool
+	ConfirmCost func(estimate, threshold float64) bool
+
+	// ReviewAttempt, when set, is shown each attempt's changes and checks
+	// and decides what happens next, as in `ticket --interactive`
+	ReviewAttempt func(AttemptReport) AttemptChoice
+
+	// AllowProtected lets tasks change PROTECTED_PATHS, with an operator's
+	// approval if APPROVAL_REQUIRED includes protected-paths
+	AllowProtected bool
+
+	// Handoffs are handoff docs of PRDs run before this one, such as the
+	// previous PRD of an --auto-continue chain; those of its dependsOnPRD
+	// prerequisites are added
+	Handoffs []string
+
+	// Worktree says the PRD runs in its own worktree: when it finishes, its
+	// branch is rebased onto the default branch (MERGE_ASSIST)
+	Worktree bool
+
+	// SessionStart is when a walkaway session chaining several PRDs began;
+	// WALKAWAY_MAX_DURATION counts from it (default: this run's start)
+	SessionStart time.Time
+
 	// Partial execution filters
 	OnlyTasks      []string
 	SkipTasks      []string
 	FromTask       string
 	UntilTask      string
+	Tags           []string
 }
 
 // workerExecution tracks a running worker.
@@ -100,6 +242,50 @@ func New(opts Options) (*Orchestrator, error) {
 		cfg.WalkawayMode = true
 	}
 
+	// Limit the run to the selected tasks; their dependencies must be
+	// selected too or already done
+	included, err := p.Select(prd.Selection{
+		Only:  opts.OnlyTasks,
+		Skip:  opts.SkipTasks,
+		From:  opts.FromTask,
+		Until: opts.UntilTask,
+		Tags:  opts.Tags,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if included != nil {
+		if len(included) == 0 {
+			return nil, fmt.Errorf("no tasks match the --only/--skip/--from/--until/--tags filters")
+		}
+		for taskID := range st.CompletedTaskIDs() {
+			p.MarkTaskComplete(taskID)
+		}
+		if err := p.ValidateDependencies(included); err != nil {
+			return nil, err
+		}
+	}
+
+	// Link iterations to their parent PRD
+	var parentContext string
+	if p.ParentPRD != "" {
+		st.ParentPRD = p.ParentPRD
+		parentContext, err = buildParentContext(p.ParentPRD, cfg.DefaultBranch)
+		if err != nil {
+			logger.Warn("failed to load parent PRD context", "parent", p.ParentPRD, "error", err)
+		}
+	}
+	prerequisiteContext, err := PrerequisiteContext(p)
+	if err != nil {
+		logger.Warn("failed to load prerequisite PRD context", "error", err)
+	}
+	handoffs := append([]string{}, opts.Handoffs...)
+	for _, name := range p.DependsOnPRD {
+		if dep, err := prd.Load(p.PrerequisitePath(name)); err == nil {
+			handoffs = append(handoffs, dep.HandoffPath())
+		}
+	}
+
 	// Create service lock with config options
 	lockOpts := []state.LockOption{
 		state.WithHeartbeatInterval(cfg.LockHeartbeatInterval),
@@ -110,16 +296,122 @@ func New(opts Options) (*Orchestrator, error) {
 	serviceLock := state.NewServiceLock(opts.PRDPath, lockOpts...)
 
 	// Create workers
-	workers := createWorkerFactory(cfg)
+	workers := opts.Workers
+	var replayer *worker.Replayer
+	if workers == nil && cfg.ReplayFile != "" {
+		replayer, err = worker.LoadReplay(cfg.ReplayFile)
+		if err != nil {
+			return nil, fmt.Errorf("loading replay: %w", err)
+		}
+		workers = replayer.Factory()
+	}
+	applyDeterministic(cfg)
+	var credentials map[state.WorkerTier][]worker.Credential
+	var gateway *worker.Gateway
+	var execCache *worker.Cache
+	if workers == nil {
+		execCache = ExecCache(cfg)
+		credentials, err = loadCredentials(cfg)
+		if err != nil {
+			return nil, err
+		}
+		var workerEnv map[state.WorkerTier][]string
+		gateway, workerEnv, err = loadWorkerEnv(cfg, credentials, logger)
+		if err != nil {
+			return nil, err
+		}
+		if cfg.Offline {
+			logger.Info("offline mode: workers, verification and modules are kept off the internet")
+		}
+		applyHistoryTimeouts(cfg, opts.PRDPath, logger)
+		mcpConfigs, err := writeMCPConfigs(cfg, p, logger)
+		if err != nil {
+			return nil, err
+		}
+		workers = createWorkerFactory(cfg, mcpConfigs, workerEnv)
+	}
+
+	// Inject failures in chaos mode
+	var chaos *worker.Chaos
+	if cfg.ChaosMode {
+		var chaosSeed int64 // Random unless the run is deterministic
+		if s := sampling(cfg); s != nil {
+			chaosSeed = s.Seed
+		}
+		chaos = worker.NewChaos(worker.ChaosRates{
+			Crash:        cfg.ChaosCrashRate,
+			Timeout:      cfg.ChaosTimeoutRate,
+			Malformed:    cfg.ChaosMalformedRate,
+			Verification: cfg.ChaosVerifyRate,
+		}, chaosSeed)
+		workers = workers.Wrap(chaos.Wrap)
+		logger.Warn("chaos mode enabled: injecting worker failures",
+			"crash", cfg.ChaosCrashRate,
+			"timeout", cfg.ChaosTimeoutRate,
+			"malformed", cfg.ChaosMalformedRate,
+			"verification", cfg.ChaosVerifyRate)
+	}
+
+	// Record worker executions if enabled
+	var recorder *worker.Recorder
+	if cfg.RecordFile != "" {
+		recorder, err = worker.NewRecorder(cfg.RecordFile)
+		if err != nil {
+			return nil, err
+		}
+		workers = workers.Wrap(recorder.Wrap)
+	}
 
 	// Create prompt builder
-	chefDir := "chef"
+	chefDir := opts.ChefDir
+	if chefDir == "" {
+		chefDir = "chef"
+	}
 	learningsPath := cfg.LearningsFile
 	backlogPath := cfg.BacklogFile
 	promptBuilder := worker.NewPromptBuilder(chefDir, learningsPath, backlogPath)
+	if r := loadRetriever(cfg, logger); r != nil {
+		promptBuilder.SetRetriever(r)
+	}
+	if cfg.SmartRetryStrategiesFile != "" {
+		strategies, err := classify.LoadStrategies(cfg.SmartRetryStrategiesFile)
+		if err != nil {
+			logger.Warn("failed to load retry strategies", "error", err)
+		}
+		promptBuilder.SetStrategies(strategies)
+	}
+
+	// How workers, and Brigade's own fixes, commit
+	commits := worker.CommitConvention{
+		Conventional: cfg.CommitConvention == "conventional",
+		SignOff:      cfg.CommitSignoff,
+		GPGSign:      cfg.CommitGPGSign,
+	}
+	promptBuilder.SetCommitConvention(commits)
+
+	// Load the license header new files must carry
+	var licenseHeader string
+	if cfg.LicenseHeaderFile != "" {
+		licenseHeader, err = verify.LoadLicenseHeader(cfg.LicenseHeaderFile)
+		if err != nil {
+			return nil, fmt.Errorf("loading license header: %w", err)
+		}
+		promptBuilder.SetLicenseHeader(licenseHeader)
+	}
+
+	// Load the services smoke verification runs against
+	svcManager, err := loadServices(cfg, p)
+	if err != nil {
+		return nil, err
+	}
 
 	// Create verifier
 	verifier := verify.NewRunner(cfg.VerificationTimeout, "")
+	verifier.FlakyRetries = cfg.VerificationFlakyRetries
+	verifier.Env = offlineEnv(cfg)
+	verifier.BrowserCmd = cfg.BrowserTestCmd
+	verifier.BrowserTimeout = cfg.BrowserTimeout
+	verifier.BrowserResultsDir = cfg.BrowserResultsDir
 
 	// Create classifier
 	classifier := classify.NewClassifier()
@@ -128,13 +420,14 @@ func New(opts Options) (*Orchestrator, error) {
 	}
 
 	// Create module manager
-	modules := module.NewManager("modules", cfg.ModuleConfig, cfg.ModuleTimeout, logger)
+	modules := module.NewManager(module.Dir, cfg.ModuleConfig, cfg.ModuleTimeout, logger)
+	modules.SetEnv(offlineEnv(cfg))
+	modules.SetSandbox(module.SandboxFromConfig(cfg))
 	if len(cfg.Modules) > 0 {
 		if err := modules.Load(cfg.Modules); err != nil {
 			logger.Warn("failed to load modules", "error", err)
 		}
 	}
-
 	// Create supervisor integration
 	sup := supervisor.NewSupervisor(
 		cfg.SupervisorStatusFile,
@@ -146,11 +439,35 @@ func New(opts Options) (*Orchestrator, error) {
 		cfg.SupervisorCmdTimeout,
 	)
 
+	// Price work from the pricing table
+	estimator, err := cost.NewEstimator(cfg)
+	if err != nil {
+		logger.Warn("failed to load pricing file, using built-in prices", "error", err)
+	}
+
 	// Create activity logger
 	var activity *ActivityLogger
 	if cfg.ActivityLog != "" {
-		activity = NewActivityLogger(cfg.ActivityLog, cfg.ActivityLogInterval, p.Prefix())
+		activity = NewActivityLogger(cfg.ActivityLog, p.Prefix())
+	}
+
+	// Every event goes to the modules, the supervisor events and status
+	// files, the activity log, the run's metrics and the caller
+	events := bus.New()
+	events.Subscribe(modules.Dispatch)
+	events.Subscribe(func(e *module.Event) { sup.Events().Write(e) })
+	events.Subscribe(sup.Status().Handle)
+	if activity != nil {
+		events.Subscribe(activity.Handle)
+	}
+	heartbeat := newHeartbeat(p.Prefix(), events.Publish)
+	events.Subscribe(heartbeat.Handle)
+	metrics := bus.NewMetrics()
+	events.Subscribe(metrics.Handle)
+	if opts.OnEvent != nil {
+		events.Subscribe(opts.OnEvent)
 	}
+	sup.SetPublisher(events.Publish)
 
 	return &Orchestrator{
 		config:        cfg,
@@ -160,23 +477,52 @@ func New(opts Options) (*Orchestrator, error) {
 		serviceLock:   serviceLock,
 		workers:       workers,
 		promptBuilder: promptBuilder,
+		chefDir:       chefDir,
 		verifier:      verifier,
 		classifier:    classifier,
 		modules:       modules,
+		events:        events,
+		metrics:       metrics,
 		supervisor:    sup,
 		activity:      activity,
+		heartbeat:     heartbeat,
+		parentContext: parentContext,
+		prerequisiteContext: prerequisiteContext,
+		handoffContext: loadHandoffs(handoffs),
+		recorder:      recorder,
+		replayer:      replayer,
+		chaos:         chaos,
+		nudges:        supervisor.NewNudgeQueue(p.NudgesPath()),
+		costEstimator: estimator,
+		costHistory:   cost.LoadHistory(filepath.Dir(opts.PRDPath), opts.PRDPath, estimator),
+		acceptCost:    opts.AcceptCost,
+		confirmCost:   opts.ConfirmCost,
+		policy:        policy.FromConfig(cfg),
+		allowProtected: opts.AllowProtected,
+		reviewAttempt:  opts.ReviewAttempt,
+		included:      included,
+		credentials:   credentials,
+		gateway:       gateway,
+		execCache:     execCache,
+		services:      svcManager,
+		licenseHeader: licenseHeader,
+		commits:       commits,
+		worktree:      opts.Worktree,
+		sessionStart:  opts.SessionStart,
 		logger:        logger,
 	}, nil
 }
 
 // createWorkerFactory creates workers based on configuration.
-func createWorkerFactory(cfg *config.Config) *worker.Factory {
+func createWorkerFactory(cfg *config.Config, mcpConfigs map[state.WorkerTier]string, workerEnv map[state.WorkerTier][]string) *worker.Factory {
 	lineConfig := &worker.Config{
 		Command: cfg.LineCmd,
 		Tier:    state.TierLine,
 		Timeout: cfg.TaskTimeoutJunior,
 		Quiet:   cfg.QuietWorkers,
 		HealthCheckInterval: cfg.WorkerHealthCheckInterval,
+		StallTimeout:        cfg.WorkerStallTimeoutJunior,
+		ShutdownGrace:       cfg.WorkerShutdownGrace,
 	}
 
 	sousConfig := &worker.Config{
@@ -185,6 +531,8 @@ func createWorkerFactory(cfg *config.Config) *worker.Factory {
 		Timeout: cfg.TaskTimeoutSenior,
 		Quiet:   cfg.QuietWorkers,
 		HealthCheckInterval: cfg.WorkerHealthCheckInterval,
+		StallTimeout:        cfg.WorkerStallTimeoutSenior,
+		ShutdownGrace:       cfg.WorkerShutdownGrace,
 	}
 
 	execConfig := &worker.Config{
@@ -193,77 +541,160 @@ func createWorkerFactory(cfg *config.Config) *worker.Factory {
 		Timeout: cfg.TaskTimeoutExecutive,
 		Quiet:   cfg.QuietWorkers,
 		HealthCheckInterval: cfg.WorkerHealthCheckInterval,
+		StallTimeout:        cfg.WorkerStallTimeoutExecutive,
+		ShutdownGrace:       cfg.WorkerShutdownGrace,
 	}
 
+	lineConfig.MCPConfig = mcpConfigs[state.TierLine]
+	sousConfig.MCPConfig = mcpConfigs[state.TierSous]
+	execConfig.MCPConfig = mcpConfigs[state.TierExecutive]
+
+	lineConfig.Env = workerEnv[state.TierLine]
+	sousConfig.Env = workerEnv[state.TierSous]
+	execConfig.Env = workerEnv[state.TierExecutive]
+
 	return worker.NewFactory(lineConfig, sousConfig, execConfig)
 }
 
 // Run executes the PRD.
 func (o *Orchestrator) Run(ctx context.Context) error {
 	o.startTime = time.Now()
+	if o.sessionStart.IsZero() {
+		o.sessionStart = o.startTime
+	}
+	o.supervisor.Status().SetRun(o.state.SessionID, o.prd.FeatureName, o.startTime)
 
-	// Set up signal handling
-	ctx, cancel := context.WithCancel(ctx)
-	defer cancel()
-
-	sigCh := make(chan os.Signal, 1)
-	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
-
-	go func() {
-		select {
-		case <-sigCh:
-			o.logger.Info("received interrupt signal, shutting down gracefully")
-			o.cancelled = true
-			cancel()
-			o.cleanup()
-		case <-ctx.Done():
-		}
-	}()
+	// Everything below runs under one context: a signal or the caller's
+	// cancellation stops scheduling, and in-flight workers get
+	// WORKER_SHUTDOWN_GRACE to exit before they are killed
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, stop := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
 
-	// Acquire service lock
+	// Acquire service lock (starts the lock heartbeat)
+	if err := o.approveForceLock(); err != nil {
+		return err
+	}
 	if err := o.serviceLock.AcquireExclusive(); err != nil {
 		return err
 	}
 	defer o.serviceLock.Release()
+	defer o.cleanup()
+	o.watchPRD()
+	o.recordRevision(prd.SourceEdit)
 
-	// Start lock heartbeat
-	o.serviceLock.StartHeartbeat(o.config.LockHeartbeatInterval)
+	if o.recorder != nil {
+		defer o.recorder.Close()
+	}
+	if o.execCache != nil {
+		defer func() {
+			if hits, misses, saved := o.execCache.Stats(); hits > 0 {
+				o.logger.Info("executive answers reused", "hits", hits, "misses", misses, "saved", saved.Round(time.Second))
+			}
+		}()
+	}
+	if o.replayer != nil {
+		defer func() {
+			served, mismatched := o.replayer.Stats()
+			o.logger.Info("replay finished", "served", served, "mismatched", mismatched)
+		}()
+	}
 
-	// Start activity logger
+	// Start activity logger and heartbeats
+	o.heartbeat.Start(o.config.ActivityLogInterval, o.spendPerHour)
+	defer o.heartbeat.Stop()
 	if o.activity != nil {
-		o.activity.Start()
 		o.activity.WriteState("SERVICE_START", "", "")
-		defer func() {
-			o.activity.WriteState("SERVICE_END", "", "")
-			o.activity.Stop()
-		}()
+		defer o.activity.WriteState("SERVICE_END", "", "")
 	}
 
+	// Pick up an attempt a previous run left in flight
+	o.recoverOrphanedAttempt()
+	o.recordSampling()
+	o.pruneArtifacts(time.Now())
+
+	// Suggestions for unblocking an earlier run are stale once work resumes
+	o.state.SetUnblockSuggestions(nil)
+
 	// Initialize idle tracking
 	o.lastProgressTime = time.Now()
 
 	// Update state timestamp
 	o.state.UpdateLastStartTime()
+	o.scrubState()
 	if err := o.store.Save(o.state); err != nil {
 		return fmt.Errorf("saving state: %w", err)
 	}
 
-	// Dispatch service_start event
-	o.modules.Dispatch(module.ServiceStartEvent(o.prd.Prefix(), o.prd.TotalTasks()))
-	if o.supervisor.Events().Enabled() {
-		o.supervisor.Events().WriteServiceStart(o.prd.Prefix(), o.prd.TotalTasks())
+	if err := o.checkChefPrompts(); err != nil {
+		return err
+	}
+	if err := o.checkPrerequisites(); err != nil {
+		return err
 	}
+	if err := o.checkCost(); err != nil {
+		return err
+	}
+	o.startPhases()
+
+	// Dispatch service_start event
+	o.events.Publish(module.ServiceStartEvent(o.prd.Prefix(), o.prd.TotalTasks()))
 
 	// Main service loop
 	err := o.serviceLoop(ctx)
+	if ctx.Err() != nil {
+		o.cancelled = true
+		o.logger.Info("interrupted, shutting down gracefully")
+		if o.activity != nil {
+			o.activity.WriteState("LOOP_EXIT", "interrupted", "")
+		}
+	}
+
+	// Bring a finished worktree's branch up to date with the default branch
+	if err == nil && o.worktree && o.config.MergeAssist {
+		o.merge = o.mergeAssist(ctx)
+	}
+
+	// Ask the executive how to get a blocked run moving again
+	var blocked *BlockedError
+	if errors.As(err, &blocked) && ctx.Err() == nil {
+		o.suggestUnblock(ctx, blocked)
+	}
+
+	// Write the machine-readable result
+	if resultErr := o.writeResult(err); resultErr != nil {
+		o.logger.Error("failed to write result", "error", resultErr)
+	} else {
+		o.logger.Info("result written", "path", o.ResultPath())
+	}
+	o.recordReport()
+
+	// Leave what a finished PRD learned for the PRDs chained after it
+	if err == nil && o.result != nil && o.result.Success {
+		if handoffErr := o.writeCompletionHandoff(o.result); handoffErr != nil {
+			o.logger.Error("failed to write handoff", "error", handoffErr)
+		} else {
+			o.logger.Info("handoff written", "path", o.prd.HandoffPath())
+		}
+	}
+
+	// Leave a handoff for whoever picks up a time-boxed session
+	var limit *SessionLimitError
+	if errors.As(err, &limit) && o.result != nil {
+		if handoffErr := o.writeHandoff(o.result); handoffErr != nil {
+			o.logger.Error("failed to write handoff", "error", handoffErr)
+		} else {
+			limit.Handoff = o.prd.HandoffPath()
+			o.logger.Info("handoff written", "path", limit.Handoff)
+		}
+	}
 
 	// Dispatch service_complete event
 	completed, total := o.prd.Progress()
 	duration := time.Since(o.startTime)
-	o.modules.Dispatch(module.ServiceCompleteEvent(o.prd.Prefix(), completed, total, duration))
-	if o.supervisor.Events().Enabled() {
-		o.supervisor.Events().WriteServiceComplete(o.prd.Prefix(), completed, total, duration)
-	}
+	o.events.Publish(module.ServiceCompleteEvent(o.prd.Prefix(), completed, total, duration))
 
 	return err
 }
@@ -277,14 +708,22 @@ func (o *Orchestrator) serviceLoop(ctx context.Context) error {
 		default:
 		}
 
+		// Apply supervisor control commands (may block while paused)
+		if err := o.applyControl(ctx); err != nil {
+			return err
+		}
+
 		// Check for idle service
 		if o.checkIdle() {
 			if o.activity != nil {
 				o.activity.WriteState("LOOP_EXIT", "idle_abort", "")
 			}
-			return fmt.Errorf("service idle for too long, aborting")
+			return &TimeoutError{What: "service idle", After: time.Since(o.lastProgressTime)}
 		}
 
+		// Pick up edits to the PRD (e.g. brigade replan)
+		o.reloadPRD()
+
 		// Get completed tasks
 		completed := o.state.CompletedTaskIDs()
 
@@ -294,20 +733,26 @@ func (o *Orchestrator) serviceLoop(ctx context.Context) error {
 		}
 
 		// Check if all done
-		if o.prd.IsComplete() {
+		if o.selectionComplete() {
 			o.logger.Info("all tasks complete!")
 			return nil
 		}
 
+		// Stop a time-boxed walkaway session between tasks
+		if o.sessionLimitReached() {
+			return o.endSession()
+		}
+
 		// Get ready tasks
-		readyTasks := o.prd.ReadyTasks(completed)
+		readyTasks := o.selected(o.prd.ReadyTasks(completed))
+		readyTasks = o.preferUnthrottled(readyTasks)
 		if len(readyTasks) == 0 {
 			// No ready tasks - might be blocked
-			pending := o.prd.PendingTasks()
+			pending := o.selected(o.prd.PendingTasks())
 			if len(pending) > 0 {
 				o.logger.Warn("no ready tasks but work remains",
 					"pending", len(pending))
-				return fmt.Errorf("blocked: no tasks ready to execute")
+				return &BlockedError{Reason: "no tasks ready to execute", Pending: taskIDs(pending), Blockers: o.blockers()}
 			}
 			return nil
 		}
@@ -325,51 +770,181 @@ func (o *Orchestrator) serviceLoop(ctx context.Context) error {
 			}
 		}
 
+		// Review progress at phase boundaries
+		if err := o.checkPhaseReviews(ctx); err != nil {
+			return err
+		}
+
+		// Pause a walkaway run that looks like it's going wrong
+		if err := o.checkAnomalies(); err != nil {
+			return err
+		}
+
 		// Save state after each iteration
+		o.scrubState()
 		if err := o.store.Save(o.state); err != nil {
 			o.logger.Error("failed to save state", "error", err)
 		}
+		o.persistCompletions()
+
+		o.updateCostProjection()
 
 		// Update status
-		done, total := o.prd.Progress()
-		if o.supervisor.Status().Enabled() {
-			o.supervisor.UpdateStatus(done, total, "", "", time.Time{}, false)
+		o.writeStatus()
+	}
+}
+
+// writeStatus writes the supervisor status between tasks.
+func (o *Orchestrator) writeStatus() {
+	if !o.supervisor.Status().Enabled() {
+		return
+	}
+	done, total := o.prd.Progress()
+	o.supervisor.Status().SetTasks(supervisor.TaskStatuses(o.prd, o.state))
+	o.supervisor.Status().SetSkips(o.state.ConsecutiveSkips)
+	o.supervisor.UpdateStatus(done, total, "", "", time.Time{}, false)
+}
+
+// scrubState reduces stored worker output per STATE_SCRUB_AFTER_DAYS and
+// STATE_SCRUB_COMPLETED.
+func (o *Orchestrator) scrubState() {
+	policy := state.ScrubPolicy{
+		OlderThan: time.Duration(o.config.StateScrubAfterDays) * 24 * time.Hour,
+		Completed: o.config.StateScrubCompleted,
+	}
+	if stats := o.state.Scrub(policy, time.Now()); stats.Total() > 0 {
+		o.logger.Debug("scrubbed stored worker output", "entries", stats.Total())
+	}
+}
+
+// selected filters tasks to the ones this run is limited to.
+func (o *Orchestrator) selected(tasks []*prd.Task) []*prd.Task {
+	if o.included == nil {
+		return tasks
+	}
+	var kept []*prd.Task
+	for _, task := range tasks {
+		if o.included[task.ID] {
+			kept = append(kept, task)
+		}
+	}
+	return kept
+}
+
+// selectionComplete reports whether every task this run is limited to has
+// passed.
+func (o *Orchestrator) selectionComplete() bool {
+	return len(o.selected(o.prd.PendingTasks())) == 0
+}
+
+// persistCompletions writes completed tasks to the PRD file so status and
+// resume see them. Skipped tasks stay pending on disk.
+func (o *Orchestrator) persistCompletions() {
+	onDisk, err := prd.Load(o.prd.Path())
+	if err != nil {
+		o.logger.Error("failed to reload PRD", "error", err)
+		return
+	}
+	changed := false
+	for taskID := range o.state.CompletedTaskIDs() {
+		if task := onDisk.TaskByID(taskID); task != nil && !task.Passes {
+			task.Passes = true
+			changed = true
 		}
 	}
+	if !changed {
+		return
+	}
+	if err := onDisk.Save(""); err != nil {
+		o.logger.Error("failed to save PRD", "error", err)
+	}
 }
 
-// executeTask executes a single task.
+// needsIteration is the error recorded for an attempt that ended without
+// completing or failing outright.
+const needsIteration = "needs iteration"
+
+// attemptOutcome tells the attempt loop whether a task needs another attempt.
+type attemptOutcome int
+
+const (
+	outcomeDone  attemptOutcome = iota // Task finished: complete, absorbed, or skipped
+	outcomeRetry                       // Task needs another attempt (same or escalated tier)
+	outcomeRestart                     // Attempt was stopped to deliver a nudge; not counted
+	outcomeThrottled                   // Worker hit a provider rate limit; not counted
+)
+
+// executeTask runs attempts on a task until it is done or fails. State is
+// saved and a task_iteration event is emitted after every attempt.
 func (o *Orchestrator) executeTask(ctx context.Context, task *prd.Task) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		// Hold the task back while its tier is rate limited
+		if yield, err := o.awaitRateLimit(ctx, task); yield || err != nil {
+			return err
+		}
+
+		outcome, err := o.runAttempt(ctx, task)
+
+		// Persist after every attempt so a crash mid-task loses at most one
+		if saveErr := o.store.Save(o.state); saveErr != nil {
+			o.logger.Error("failed to save state", "error", saveErr)
+		}
+		if outcome != outcomeRestart && outcome != outcomeThrottled {
+			o.emitIteration(task)
+		}
+
+		if err != nil || outcome == outcomeDone {
+			return err
+		}
+	}
+}
+
+// runAttempt executes one worker attempt on a task.
+func (o *Orchestrator) runAttempt(ctx context.Context, task *prd.Task) (attemptOutcome, error) {
 	o.taskStartTime = time.Now()
 	o.state.SetCurrentTask(task.ID)
 	o.markProgress()
 
+	// Persist the in-flight attempt so a crash can be recovered
+	if err := o.store.Save(o.state); err != nil {
+		o.logger.Error("failed to save state", "error", err)
+	}
+
 	// Determine worker tier
 	tier := o.determineWorkerTier(task)
 
 	// Build prompt
-	prompt, err := o.buildTaskPrompt(task, tier)
+	prompt, err := o.buildTaskPrompt(task, tier, o.prepContext(ctx, task, tier))
 	if err != nil {
-		return fmt.Errorf("building prompt: %w", err)
+		return outcomeDone, fmt.Errorf("building prompt: %w", err)
 	}
 
-	// Get worker
-	w := o.workers.ForTier(tier)
-
-	// Dispatch task_start event
-	o.modules.Dispatch(module.TaskStartEvent(o.prd.Prefix(), task.ID, string(tier)))
-	if o.supervisor.Events().Enabled() {
-		o.supervisor.Events().WriteTaskStart(o.prd.Prefix(), task.ID, string(tier))
+	// Get worker, running in the task's workspace if it has one and with
+	// the task's env, its artifacts directory, the tier's current
+	// credential, gateway tags and commit signing
+	attempt := o.state.TotalAttempts(task.ID) + 1
+	env := append(task.EnvList(), o.artifactsEnv(task, attempt)...)
+	env = append(env, o.gatewayEnv(task, tier)...)
+	env = append(env, o.commits.Env()...)
+	defer o.recordArtifacts(task, attempt)
+	var credential string
+	if cred := o.pickCredential(tier); cred != nil {
+		env, credential = append(env, cred.Env...), cred.Name
 	}
+	w := o.workers.ForAttempt(tier, task.Workspace, env)
 
-	// Update activity logger
-	if o.activity != nil {
-		o.activity.SetTask(task.ID, string(tier))
-	}
+	// Dispatch task_start event
+	o.events.Publish(module.TaskStartEvent(o.prd.Prefix(), task.ID, string(tier)).WithData("attempt", attempt))
 
 	// Update status
 	done, total := o.prd.Progress()
 	if o.supervisor.Status().Enabled() {
+		o.supervisor.Status().SetTasks(supervisor.TaskStatuses(o.prd, o.state))
+		o.supervisor.Status().SetSkips(o.state.ConsecutiveSkips)
 		o.supervisor.UpdateStatus(done, total, task.ID, string(tier), o.taskStartTime, false)
 	}
 
@@ -377,29 +952,167 @@ func (o *Orchestrator) executeTask(ctx context.Context, task *prd.Task) error {
 		"task", o.prd.FormatTaskID(task.ID),
 		"worker", tier)
 
+	// Start what the task needs, such as services for integration tests;
+	// it's stopped again once the attempt has been verified
+	teardown, err := o.setupTask(ctx, task)
+	if err != nil {
+		if ctx.Err() != nil {
+			return outcomeDone, ctx.Err()
+		}
+		return o.handleSetupFailure(ctx, task, w, err)
+	}
+	defer teardown()
+
 	// Execute worker
-	result, err := w.Execute(ctx, prompt)
+	o.snapshotChanges(task)
+	o.baselineTask(task)
+	o.checkpointAttempt()
+	o.markTaskStart(task.ID)
+	attemptCtx, stop := context.WithCancel(ctx)
+	nudge := o.watchNudges(attemptCtx, task, stop)
+	result, err := w.Execute(attemptCtx, prompt)
+	nudge.finish()
+	stop()
+	if nudge.interrupted(result, err) && ctx.Err() == nil {
+		o.logger.Info("restarting attempt to deliver nudge", "task", o.prd.FormatTaskID(task.ID))
+		return outcomeRestart, nil
+	}
 	if err != nil {
-		return fmt.Errorf("worker execution: %w", err)
+		return outcomeDone, fmt.Errorf("worker execution: %w", err)
 	}
 
 	// Process result
-	return o.processResult(ctx, task, w, result)
+	return o.processResult(ctx, task, w, credential, result)
+}
+
+// emitIteration reports the attempt just finished on a task.
+func (o *Orchestrator) emitIteration(task *prd.Task) {
+	last := o.state.LastAttempt(task.ID)
+	if last == nil {
+		return
+	}
+	attempt := o.state.TotalAttempts(task.ID)
+
+	o.events.Publish(module.TaskIterationEvent(o.prd.Prefix(), task.ID, string(last.Worker), attempt, string(last.Status)))
+}
+
+// maxVerificationDetails caps the command output carried by a verification event.
+const maxVerificationDetails = 4000
+
+// emitVerification reports a task's verification outcome. Details hold the
+// failing commands and the tail of their output.
+func (o *Orchestrator) emitVerification(task *prd.Task, result *verify.Result) {
+	var sb strings.Builder
+	for _, r := range result.Results {
+		if r.Passed {
+			continue
+		}
+		output := r.Output
+		if len(output) > maxVerificationDetails {
+			output = output[len(output)-maxVerificationDetails:]
+		}
+		sb.WriteString(fmt.Sprintf("$ %s (%s)\n%s\n", r.Command, r.Error, output))
+	}
+	if !result.Passed {
+		for _, l := range result.Logs {
+			output := l.Output
+			if len(output) > maxVerificationDetails {
+				output = output[len(output)-maxVerificationDetails:]
+			}
+			sb.WriteString(fmt.Sprintf("--- %s log ---\n%s\n", l.Name, output))
+		}
+	}
+	details := strings.TrimSpace(sb.String())
+
+	o.events.Publish(module.VerificationEvent(o.prd.Prefix(), task.ID, result.Passed, details))
+}
+
+// maxVerificationFailureOutput caps the output kept per failed command for
+// the retry prompt.
+const maxVerificationFailureOutput = 2000
+
+// verificationFailures extracts the failed commands from a verification run,
+// keeping the tail of each command's output. The run's logs follow them, so
+// the retry prompt shows what the services said.
+func verificationFailures(result *verify.Result) []state.VerificationFailure {
+	var failures []state.VerificationFailure
+	for _, r := range result.Results {
+		if r.Passed {
+			continue
+		}
+		output := r.Output
+		if output == "" {
+			output = r.Error
+		}
+		if len(output) > maxVerificationFailureOutput {
+			output = "..." + util.Tail(output, maxVerificationFailureOutput)
+		}
+		failures = append(failures, state.VerificationFailure{
+			Command:  r.Command,
+			ExitCode: r.ExitCode,
+			Output:   output,
+		})
+	}
+	if len(failures) > 0 {
+		for _, l := range result.Logs {
+			output := l.Output
+			if len(output) > maxVerificationFailureOutput {
+				output = "..." + util.Tail(output, maxVerificationFailureOutput)
+			}
+			failures = append(failures, state.VerificationFailure{
+				Command: l.Name,
+				Output:  output,
+				Log:     true,
+			})
+		}
+	}
+	return failures
 }
 
 // processResult handles the result of a worker execution.
-func (o *Orchestrator) processResult(ctx context.Context, task *prd.Task, w worker.Worker, result *worker.Result) error {
+func (o *Orchestrator) processResult(ctx context.Context, task *prd.Task, w worker.Worker, credential string, result *worker.Result) (attemptOutcome, error) {
+	// An interrupted attempt is not a failure; leave the task for resume
+	if err := ctx.Err(); err != nil {
+		return outcomeDone, err
+	}
+
+	if result.RateLimited && o.rateLimitsEnabled() {
+		return o.handleRateLimit(task, w, credential, result)
+	}
+	// A rejected credential is retried with the tier's next one
+	if result.AuthFailed && credential != "" && o.rejectCredential(task, w.Tier(), credential) > 0 {
+		o.state.ClearCurrentTask()
+		return outcomeThrottled, nil
+	}
+
 	duration := result.Duration
 
-	// Record approach if declared
-	if result.Approach != "" {
-		entry := state.TaskHistory{
-			TaskID:   task.ID,
-			Worker:   w.Tier(),
-			Status:   state.StatusInProgress,
-			Approach: result.Approach,
+	// Record the attempt; handlers resolve its final status
+	o.state.AddTaskHistory(state.TaskHistory{
+		TaskID:     task.ID,
+		Worker:     w.Tier(),
+		Command:    o.workerCommand(w.Tier()),
+		Credential: credential,
+		Status:     state.StatusInProgress,
+		Duration:   int(duration.Seconds()),
+		Approach:   result.Approach,
+		Learnings:  result.Learnings,
+
+		InputTokens:     result.Usage.InputTokens,
+		CacheReadTokens: result.Usage.CacheReadTokens,
+		OutputTokens:    result.Usage.OutputTokens,
+	})
+
+	if len(result.Leftover) > 0 {
+		o.logger.Warn("worker left processes running", "task", task.ID, "processes", result.Leftover)
+	}
+
+	// Note an approach the task already tried; the next prompt calls it out
+	if result.Approach != "" && o.config.SmartRetryEnabled {
+		approaches := o.state.GetApproachHistory(task.ID, 0)
+		if last := approaches[len(approaches)-1]; last.RepeatOf > 0 {
+			o.logger.Warn("worker repeated an earlier approach", "task", task.ID, "attempt", last.Attempt, "repeats", last.RepeatOf)
 		}
-		o.state.AddTaskHistory(entry)
 	}
 
 	// Process learnings
@@ -426,57 +1139,140 @@ func (o *Orchestrator) processResult(ctx context.Context, task *prd.Task, w work
 	case result.Timeout:
 		return o.handleTimeout(ctx, task, w)
 
+	case result.Stalled:
+		return o.handleStall(ctx, task, w, result)
+
 	case result.Crashed:
 		return o.handleCrash(ctx, task, w, result)
 
 	default:
-		// Needs iteration
+		// Needs iteration; a broken build is the most precise feedback
+		if err := o.runBuildGate(ctx, task); err != nil {
+			if ctx.Err() != nil {
+				return outcomeDone, ctx.Err()
+			}
+			result.Error = err
+		}
 		return o.handleIteration(ctx, task, w, result)
 	}
 }
 
 // handleComplete handles successful task completion.
-func (o *Orchestrator) handleComplete(ctx context.Context, task *prd.Task, w worker.Worker, result *worker.Result, duration time.Duration) error {
+func (o *Orchestrator) handleComplete(ctx context.Context, task *prd.Task, w worker.Worker, result *worker.Result, duration time.Duration) (attemptOutcome, error) {
+	// Reject edits outside the task's workspace
+	if violation := o.workspaceViolation(task); violation != "" {
+		o.logger.Warn("workspace violation", "task", task.ID, "reason", violation)
+		o.state.AddReview(task.ID, "fail", violation)
+		return o.handleIteration(ctx, task, w, result)
+	}
+
+	// Reject edits to protected paths unless an operator allowed them
+	if violation := o.protectedViolation(task); violation != "" {
+		o.logger.Warn("protected path violation", "task", task.ID, "reason", violation)
+		o.state.AddReview(task.ID, "fail", violation)
+		return o.handleIteration(ctx, task, w, result)
+	}
+
+	// Reject secrets before they go any further
+	if err := o.scanSecrets(task); err != nil {
+		result.Error = err
+		return o.handleIteration(ctx, task, w, result)
+	}
+
+	// New files must carry the license header, if there's one
+	policy, err := o.checkLicenseHeaders(task)
+	if err != nil {
+		result.Error = err
+		return o.handleIteration(ctx, task, w, result)
+	}
+
+	// Fail fast on a broken build before the slower verification
+	if err := o.runBuildGate(ctx, task); err != nil {
+		if ctx.Err() != nil {
+			return outcomeDone, ctx.Err()
+		}
+		o.logger.Warn("build gate failed", "task", task.ID)
+		result.Error = err
+		return o.handleIteration(ctx, task, w, result)
+	}
+
+	// Run the repo's pre-commit hooks, so the work passes them when pushed
+	if err := o.runPreCommitHooks(ctx, task); err != nil {
+		if ctx.Err() != nil {
+			return outcomeDone, ctx.Err()
+		}
+		o.logger.Warn("pre-commit hooks failed", "task", task.ID)
+		result.Error = err
+		return o.handleIteration(ctx, task, w, result)
+	}
+
 	// Run verification if enabled
 	if o.config.VerificationEnabled && len(task.Verification) > 0 {
-		verifyResult, err := o.verifier.Run(ctx, task)
+		verifyResult, err := o.runVerification(ctx, task)
 		if err != nil {
 			o.logger.Error("verification error", "error", err)
-		} else if !verifyResult.Passed {
-			o.logger.Warn("verification failed", "task", task.ID)
-			// Treat as needing iteration
+		} else {
+			o.emitVerification(task, verifyResult)
+			o.recordVerification(task, verifyResult)
+			o.checkpoint.verification = verifyResult
+			if !verifyResult.Passed {
+				o.logger.Warn("verification failed", "task", task.ID)
+				// Treat as needing iteration
+				return o.handleIteration(ctx, task, w, result)
+			}
+		}
+	}
+	if o.chaos != nil && o.chaos.FailVerification() {
+		o.logger.Warn("chaos: injected verification failure", "task", task.ID)
+		return o.handleIteration(ctx, task, w, result)
+	}
+
+	// Run the full test suite if the test gate is due
+	if o.testGateDue(task) {
+		if err := o.runTestGate(ctx, task); err != nil {
+			if ctx.Err() != nil {
+				return outcomeDone, ctx.Err()
+			}
+			o.logger.Warn("test gate failed", "task", task.ID)
+			result.Error = err
 			return o.handleIteration(ctx, task, w, result)
 		}
 	}
 
 	// Run executive review if enabled
+	reviewOutput := ""
 	if o.config.ReviewEnabled {
 		if !o.config.ReviewJuniorOnly || w.Tier() == state.TierLine {
-			passed, reason := o.runReview(ctx, task, result.Output)
+			var passed bool
+			var reason string
+			passed, reason, reviewOutput = o.reviewChanges(ctx, task, w, result.Output, policy)
 			if !passed {
 				o.logger.Warn("review failed", "task", task.ID, "reason", reason)
-				// Store feedback for next iteration
-				o.state.AddReview(task.ID, "fail", reason)
 				return o.handleIteration(ctx, task, w, result)
 			}
-			o.state.AddReview(task.ID, "pass", "")
 		}
 	}
 
-	// Mark complete
-	o.state.AddTaskHistory(state.TaskHistory{
-		TaskID:   task.ID,
-		Worker:   w.Tier(),
-		Status:   state.StatusComplete,
-		Duration: int(duration.Seconds()),
-	})
+	// In interactive mode a human has the last word
+	if o.reviewAttempt != nil {
+		if outcome, handled, err := o.askAttempt(task, w, result, ""); handled {
+			return outcome, err
+		}
+	}
+
+	o.markComplete(task, w, duration, reviewOutput)
+	return outcomeDone, nil
+}
+
+// markComplete records a task's attempt as completing it.
+func (o *Orchestrator) markComplete(task *prd.Task, w worker.Worker, duration time.Duration, reviewOutput string) {
+	o.state.ResolveAttempt(task.ID, state.StatusComplete, "", "")
 	o.prd.MarkTaskComplete(task.ID)
+	o.recordDiffSize(task.ID)
+	o.queueHumanReview(task, reviewOutput)
 
 	// Dispatch task_complete event
-	o.modules.Dispatch(module.TaskCompleteEvent(o.prd.Prefix(), task.ID, string(w.Tier()), duration))
-	if o.supervisor.Events().Enabled() {
-		o.supervisor.Events().WriteTaskComplete(o.prd.Prefix(), task.ID, string(w.Tier()), duration)
-	}
+	o.events.Publish(module.TaskCompleteEvent(o.prd.Prefix(), task.ID, string(w.Tier()), duration))
 
 	o.logger.Info("task complete",
 		"task", o.prd.FormatTaskID(task.ID),
@@ -485,75 +1281,92 @@ func (o *Orchestrator) handleComplete(ctx context.Context, task *prd.Task, w wor
 	o.state.ResetSkips()
 	o.state.ClearCurrentTask()
 	o.markProgress()
-	if o.activity != nil {
-		o.activity.ClearTask()
-	}
-	return nil
 }
 
 // handleBlocked handles a blocked task.
-func (o *Orchestrator) handleBlocked(ctx context.Context, task *prd.Task, w worker.Worker, result *worker.Result) error {
+func (o *Orchestrator) handleBlocked(ctx context.Context, task *prd.Task, w worker.Worker, result *worker.Result) (attemptOutcome, error) {
 	o.logger.Warn("task blocked", "task", task.ID)
+	o.state.ResolveAttempt(task.ID, state.StatusBlocked, "worker signaled BLOCKED", "")
 
 	// Dispatch event
-	o.modules.Dispatch(module.TaskBlockedEvent(o.prd.Prefix(), task.ID, string(w.Tier()), "worker signaled BLOCKED"))
-	if o.supervisor.Events().Enabled() {
-		o.supervisor.Events().WriteTaskBlocked(o.prd.Prefix(), task.ID, string(w.Tier()), "worker signaled BLOCKED")
-	}
+	o.events.Publish(module.TaskBlockedEvent(o.prd.Prefix(), task.ID, string(w.Tier()), "worker signaled BLOCKED"))
 
 	// Try escalation
 	return o.handleEscalation(ctx, task, w, "worker signaled BLOCKED")
 }
 
 // handleAbsorbed handles a task absorbed by another.
-func (o *Orchestrator) handleAbsorbed(task *prd.Task, absorbedBy string) error {
+func (o *Orchestrator) handleAbsorbed(task *prd.Task, absorbedBy string) (attemptOutcome, error) {
 	o.logger.Info("task absorbed", "task", task.ID, "by", absorbedBy)
 
+	o.state.ResolveAttempt(task.ID, state.StatusAbsorbed, "", "")
 	o.state.AddAbsorption(task.ID, absorbedBy)
 	o.prd.MarkTaskComplete(task.ID)
 	o.state.ClearCurrentTask()
 	o.markProgress()
-	if o.activity != nil {
-		o.activity.ClearTask()
-	}
-	return nil
+	o.events.Publish(module.TaskAbsorbedEvent(o.prd.Prefix(), task.ID, absorbedBy))
+	return outcomeDone, nil
 }
 
 // handleTimeout handles a worker timeout.
-func (o *Orchestrator) handleTimeout(ctx context.Context, task *prd.Task, w worker.Worker) error {
+func (o *Orchestrator) handleTimeout(ctx context.Context, task *prd.Task, w worker.Worker) (attemptOutcome, error) {
 	o.logger.Warn("worker timeout", "task", task.ID)
+	o.state.ResolveAttempt(task.ID, state.StatusFailed, "worker timeout", "")
 	return o.handleEscalation(ctx, task, w, "worker timeout")
 }
 
+// handleStall handles a worker that went silent and was interrupted.
+func (o *Orchestrator) handleStall(ctx context.Context, task *prd.Task, w worker.Worker, result *worker.Result) (attemptOutcome, error) {
+	o.logger.Warn("worker stalled", "task", task.ID, "error", result.Error)
+	if result.StallDump != "" {
+		o.logger.Info("stall dump", "task", task.ID, "dump", result.StallDump)
+	}
+	o.state.ResolveAttempt(task.ID, state.StatusFailed, "worker stalled", "")
+	return o.handleEscalation(ctx, task, w, "worker stalled")
+}
+
 // handleCrash handles a worker crash.
-func (o *Orchestrator) handleCrash(ctx context.Context, task *prd.Task, w worker.Worker, result *worker.Result) error {
+func (o *Orchestrator) handleCrash(ctx context.Context, task *prd.Task, w worker.Worker, result *worker.Result) (attemptOutcome, error) {
 	o.logger.Error("worker crashed", "task", task.ID)
+	o.state.ResolveAttempt(task.ID, state.StatusFailed, "worker crashed", "")
 	return o.handleEscalation(ctx, task, w, "worker crashed")
 }
 
 // handleIteration handles a task needing another iteration.
-func (o *Orchestrator) handleIteration(ctx context.Context, task *prd.Task, w worker.Worker, result *worker.Result) error {
+func (o *Orchestrator) handleIteration(ctx context.Context, task *prd.Task, w worker.Worker, result *worker.Result) (attemptOutcome, error) {
 	attempts := o.state.TotalAttempts(task.ID)
 
-	// Check max iterations
-	if attempts >= o.config.MaxIterations {
-		o.logger.Error("max iterations reached", "task", task.ID, "attempts", attempts)
-		return o.handleDecision(ctx, task, "max iterations reached")
-	}
-
 	// Classify error if present
 	var category classify.Category
+	errorMsg := needsIteration
 	if result.Error != nil || !result.Success() {
 		errorOutput := result.Output
 		if result.Error != nil {
 			errorOutput = result.Error.Error() + "\n" + result.Output
 		}
 		category = o.classifier.Classify(errorOutput)
+		if errors.Is(result.Error, errSecretLeak) {
+			category = classify.CategorySecret
+		}
 
 		// Record failure
-		errorMsg := classify.ExtractErrorMessage(errorOutput, 100)
+		errorMsg = classify.ExtractErrorMessage(errorOutput, 100)
 		o.state.AddSessionFailure(task.ID, string(category), errorMsg, o.config.SmartRetrySessionFailuresMax)
 	}
+	o.state.ResolveAttempt(task.ID, state.StatusFailed, errorMsg, string(category))
+	o.recordAttemptDiff(task, w.Tier())
+
+	// In interactive mode a human decides what happens next
+	if o.reviewAttempt != nil {
+		outcome, _, err := o.askAttempt(task, w, result, errorMsg)
+		return outcome, err
+	}
+
+	// Check max iterations
+	if attempts >= o.config.MaxIterations {
+		o.logger.Error("max iterations reached", "task", task.ID, "attempts", attempts)
+		return o.handleDecision(ctx, task, "max iterations reached")
+	}
 
 	// Check escalation
 	if o.shouldEscalate(task.ID, w.Tier()) {
@@ -566,17 +1379,18 @@ func (o *Orchestrator) handleIteration(ctx context.Context, task *prd.Task, w wo
 		"attempt", attempts+1,
 		"category", category)
 
-	return o.executeTask(ctx, task)
+	return outcomeRetry, nil
 }
 
 // handleEscalation handles escalating to a higher tier.
-func (o *Orchestrator) handleEscalation(ctx context.Context, task *prd.Task, w worker.Worker, reason string) error {
+func (o *Orchestrator) handleEscalation(ctx context.Context, task *prd.Task, w worker.Worker, reason string) (attemptOutcome, error) {
 	if !o.config.EscalationEnabled {
 		return o.handleDecision(ctx, task, reason)
 	}
 
 	currentTier := w.Tier()
 	var nextTier state.WorkerTier
+	o.recordAttemptDiff(task, currentTier)
 
 	switch currentTier {
 	case state.TierLine:
@@ -594,11 +1408,11 @@ func (o *Orchestrator) handleEscalation(ctx context.Context, task *prd.Task, w w
 	// Record escalation
 	o.state.AddEscalation(task.ID, currentTier, nextTier, reason)
 
-	// Dispatch event
-	o.modules.Dispatch(module.EscalationEvent(o.prd.Prefix(), task.ID, string(currentTier), string(nextTier), reason))
-	if o.supervisor.Events().Enabled() {
-		o.supervisor.Events().WriteEscalation(o.prd.Prefix(), task.ID, string(currentTier), string(nextTier), reason)
-	}
+	// Dispatch event, with enough history to judge how bad it is
+	o.events.Publish(module.EscalationEvent(o.prd.Prefix(), task.ID, string(currentTier), string(nextTier), reason).
+		WithData("attempts", o.state.TotalAttempts(task.ID)).
+		WithData("categories", o.state.ErrorCategories(task.ID)).
+		WithData("lastError", o.state.LastError(task.ID)))
 
 	o.logger.Info("escalating task",
 		"task", task.ID,
@@ -606,28 +1420,29 @@ func (o *Orchestrator) handleEscalation(ctx context.Context, task *prd.Task, w w
 		"to", nextTier,
 		"reason", reason)
 
-	// Execute with higher tier
-	return o.executeTask(ctx, task)
+	// Next attempt picks up the higher tier
+	return outcomeRetry, nil
 }
 
 // handleDecision handles a decision point (walkaway or interactive).
-func (o *Orchestrator) handleDecision(ctx context.Context, task *prd.Task, reason string) error {
+func (o *Orchestrator) handleDecision(ctx context.Context, task *prd.Task, reason string) (attemptOutcome, error) {
 	if o.config.WalkawayMode {
 		return o.handleWalkawayDecision(ctx, task, reason)
 	}
 
 	// In interactive mode, we'd prompt the user
 	// For now, just fail
-	return fmt.Errorf("task %s failed: %s", task.ID, reason)
+	return outcomeDone, &BlockedError{TaskID: task.ID, Reason: reason}
 }
 
 // handleWalkawayDecision handles autonomous decision making.
-func (o *Orchestrator) handleWalkawayDecision(ctx context.Context, task *prd.Task, reason string) error {
+func (o *Orchestrator) handleWalkawayDecision(ctx context.Context, task *prd.Task, reason string) (attemptOutcome, error) {
 	attempts := o.state.TotalAttempts(task.ID)
+	impact := o.skipImpact(task)
 
 	// Step 1: Check for supervisor command first (if enabled)
 	if o.supervisor.Commands().Enabled() {
-		question := fmt.Sprintf("Task %s failed after %d attempts: %s", task.ID, attempts, reason)
+		question := fmt.Sprintf("Task %s failed after %d attempts: %s (%s)", task.ID, attempts, reason, impact)
 		cmd, err := o.supervisor.RequestDecision(ctx, task.ID, question, []string{"retry", "skip", "abort"})
 		if err == nil && cmd != nil {
 			o.logger.Info("supervisor decision received",
@@ -637,33 +1452,56 @@ func (o *Orchestrator) handleWalkawayDecision(ctx context.Context, task *prd.Tas
 
 			switch cmd.Action {
 			case supervisor.ActionRetry:
-				return o.executeTask(ctx, task)
+				if cmd.Guidance != "" {
+					if err := o.nudges.Add(supervisor.Nudge{TaskID: task.ID, Message: cmd.Guidance}); err != nil {
+						o.logger.Warn("failed to queue guidance", "task", task.ID, "error", err)
+					}
+				}
+				return outcomeRetry, nil
 			case supervisor.ActionSkip:
-				return o.skipTask(task, cmd.Reason)
+				return outcomeDone, o.skipTask(task, cmd.Reason)
 			case supervisor.ActionAbort:
-				return fmt.Errorf("supervisor aborted: %s", cmd.Reason)
+				return outcomeDone, fmt.Errorf("supervisor aborted: %s", cmd.Reason)
 			case supervisor.ActionPause:
-				return fmt.Errorf("supervisor paused execution")
+				return outcomeDone, fmt.Errorf("supervisor paused execution")
 			}
 		} else if err != nil {
-			o.logger.Info("supervisor timeout, using exec chef", "error", err)
+			if ctx.Err() != nil {
+				return outcomeDone, ctx.Err()
+			}
+			var timeout *supervisor.TimeoutError
+			if !errors.As(err, &timeout) {
+				o.logger.Warn("no supervisor decision, using exec chef", "error", err)
+			} else {
+				// SUPERVISOR_TASK_FALLBACK: the exec chef decides, the task
+				// is skipped, or the service pauses with it still pending
+				fallback := o.config.SupervisorTaskFallback
+				o.decisionFallback(task.ID, "task", fallback, timeout)
+				switch fallback {
+				case "skip":
+					return outcomeDone, o.skipTask(task, "no supervisor decision: "+reason)
+				case "pause":
+					o.pauseFor(fmt.Sprintf("no supervisor decision on %s", task.ID))
+					return outcomeDone, nil
+				}
+			}
 		}
 	}
 
 	// Step 2: Fall back to exec chef decision
-	prompt, err := o.promptBuilder.BuildWalkawayDecisionPrompt(task, reason, attempts)
+	prompt, err := o.promptBuilder.BuildWalkawayDecisionPrompt(task, reason, attempts, impact)
 	if err != nil {
 		o.logger.Error("failed to build decision prompt", "error", err)
-		return fmt.Errorf("building decision prompt: %w", err)
+		return outcomeDone, fmt.Errorf("building decision prompt: %w", err)
 	}
 
 	// Get executive to decide
-	exec := o.workers.Executive()
+	exec := o.executive()
 	result, err := exec.Execute(ctx, prompt)
 	if err != nil {
 		o.logger.Error("decision failed", "error", err)
 		// Default to skip
-		return o.skipTask(task, "decision execution failed")
+		return outcomeDone, o.skipTask(task, "decision execution failed")
 	}
 
 	// Parse decision from output
@@ -673,25 +1511,30 @@ func (o *Orchestrator) handleWalkawayDecision(ctx context.Context, task *prd.Tas
 	switch decision {
 	case "RETRY":
 		o.logger.Info("walkaway: retrying task", "task", task.ID, "guidance", guidance)
-		return o.executeTask(ctx, task)
+		return outcomeRetry, nil
 	case "SKIP":
-		return o.skipTask(task, reason)
+		return outcomeDone, o.skipTask(task, reason)
 	case "ABORT":
-		return fmt.Errorf("walkaway aborted: %s", reason)
+		return outcomeDone, fmt.Errorf("walkaway aborted: %s", reason)
 	default:
 		// Default to skip
-		return o.skipTask(task, "unknown decision")
+		return outcomeDone, o.skipTask(task, "unknown decision")
 	}
 }
 
 // skipTask skips a task and handles consecutive skip tracking.
 func (o *Orchestrator) skipTask(task *prd.Task, reason string) error {
 	skips := o.state.IncrementSkips()
+	blocked := o.unreachable(task.ID)
 
 	o.logger.Warn("skipping task",
 		"task", task.ID,
 		"reason", reason,
-		"consecutiveSkips", skips)
+		"consecutiveSkips", skips,
+		"blocks", len(blocked))
+	if len(blocked) > 0 {
+		o.logger.Warn(o.skipImpact(task))
+	}
 
 	o.state.AddTaskHistory(state.TaskHistory{
 		TaskID: task.ID,
@@ -702,15 +1545,13 @@ func (o *Orchestrator) skipTask(task *prd.Task, reason string) error {
 
 	// Check safety rail
 	if skips >= o.config.WalkawayMaxSkips {
-		return fmt.Errorf("too many consecutive skips (%d), pausing", skips)
+		return &BlockedError{Reason: fmt.Sprintf("too many consecutive skips (%d), pausing", skips), Blockers: o.blockers()}
 	}
 
 	o.prd.MarkTaskComplete(task.ID) // Mark as "done" so we don't retry
 	o.state.ClearCurrentTask()
 	o.markProgress()
-	if o.activity != nil {
-		o.activity.ClearTask()
-	}
+	o.events.Publish(module.TaskSkippedEvent(o.prd.Prefix(), task.ID, reason, blocked))
 	return nil
 }
 
@@ -751,16 +1592,33 @@ func (o *Orchestrator) shouldEscalate(taskID string, tier state.WorkerTier) bool
 }
 
 // buildTaskPrompt builds the prompt for a task.
-func (o *Orchestrator) buildTaskPrompt(task *prd.Task, tier state.WorkerTier) (string, error) {
+func (o *Orchestrator) buildTaskPrompt(task *prd.Task, tier state.WorkerTier, prepContext string) (string, error) {
 	opts := worker.TaskPromptOptions{
-		Task: task,
-		PRD:  o.prd,
-		Tier: tier,
+		Task:          task,
+		PRD:           o.prd,
+		Tier:          tier,
+		ParentContext: o.parentContext,
+		PrerequisiteContext: o.prerequisiteContext,
+		HandoffContext: o.handoffContext,
+		CodebaseMap:   workspaceMap(task),
+		FilesBudget:   o.config.TaskFilesMaxBytes,
+		PrepContext:   prepContext,
+	}
+	if task.ID == o.recoveryTask {
+		// Only the first retry needs to hear about the interrupted attempt
+		opts.RecoveryContext = o.recoveryContext
+		o.recoveryTask = ""
 	}
 
+	// Add guidance from `brigade nudge`
+	opts.Nudges = o.takeNudges(task)
+
 	// Add review feedback if present
 	opts.ReviewFeedback = o.state.GetLastReviewFeedback(task.ID)
 
+	// Add the verification failures the last attempt left
+	opts.VerificationFailures = o.state.GetVerificationFailures(task.ID)
+
 	// Add previous approaches for smart retry
 	if o.config.SmartRetryEnabled {
 		opts.PreviousApproaches = o.state.GetApproachHistory(task.ID, o.config.SmartRetryApproachHistoryMax)
@@ -773,28 +1631,43 @@ func (o *Orchestrator) buildTaskPrompt(task *prd.Task, tier state.WorkerTier) (s
 		opts.EscalationContext = &worker.EscalationContext{
 			FromTier: o.state.CurrentTier(task.ID, state.TierLine),
 			Attempts: approaches,
+			Diffs:    o.escalationDiffs(task.ID),
 		}
 	}
 
 	return o.promptBuilder.BuildTaskPrompt(opts)
 }
 
-// runReview runs an executive review on completed work.
-func (o *Orchestrator) runReview(ctx context.Context, task *prd.Task, workerOutput string) (bool, string) {
-	prompt, err := o.promptBuilder.BuildReviewPrompt(task, workerOutput)
+// reviewVerdict is the outcome of an executive review.
+type reviewVerdict struct {
+	passed bool
+	reason string
+	output string
+	judged bool // The executive gave a verdict, rather than failing to answer
+}
+
+// runReview runs an executive review on completed work, returning the
+// verdict and the review's output.
+func (o *Orchestrator) runReview(ctx context.Context, task *prd.Task, workerOutput string, policy []string) reviewVerdict {
+	prompt, err := o.promptBuilder.BuildReviewPrompt(task, workerOutput, policy)
 	if err != nil {
 		o.logger.Error("failed to build review prompt", "error", err)
-		return true, "" // Pass by default if we can't build prompt
+		return reviewVerdict{passed: true} // Pass by default if we can't build prompt
 	}
 
-	exec := o.workers.Executive()
+	exec := o.executive()
 	result, err := exec.Execute(ctx, prompt)
 	if err != nil {
 		o.logger.Error("review execution failed", "error", err)
-		return true, "" // Pass by default on error
+		return reviewVerdict{passed: true} // Pass by default on error
+	}
+	if result.Cached {
+		o.logger.Info("reusing review of unchanged code", "task", task.ID)
 	}
 
-	return parseReview(result.Output)
+	passed, reason := parseReview(result.Output)
+	judged := result.Error == nil && contains(result.Output, "<review>")
+	return reviewVerdict{passed: passed, reason: reason, output: result.Output, judged: judged}
 }
 
 // markProgress marks that the service made progress (resets idle timer).
@@ -816,7 +1689,7 @@ func (o *Orchestrator) checkIdle() bool {
 	}
 
 	// Skip if all tasks done
-	if o.prd.IsComplete() {
+	if o.selectionComplete() {
 		return false
 	}
 
@@ -825,9 +1698,7 @@ func (o *Orchestrator) checkIdle() bool {
 		if o.activity != nil {
 			o.activity.WriteState("IDLE", "no_progress", idle.String())
 		}
-		if o.supervisor.Events().Enabled() {
-			o.supervisor.Events().WriteAttention(o.prd.Prefix(), "", "service_idle: "+idle.String())
-		}
+		o.events.Publish(module.AttentionEvent(o.prd.Prefix(), "", "service_idle: "+idle.String()))
 		o.idleWarningShown = true
 	}
 
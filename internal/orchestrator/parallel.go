@@ -32,6 +32,9 @@ func (o *Orchestrator) executeParallel(ctx context.Context, tasks []*prd.Task) e
 		"count", len(batch),
 		"tasks", taskIDs(batch))
 
+	o.setParallelBatch(true)
+	defer o.setParallelBatch(false)
+
 	// Create channels for results
 	results := make(chan taskResult, len(batch))
 	var wg sync.WaitGroup
@@ -0,0 +1,271 @@
+package orchestrator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"brigade/internal/module"
+	"brigade/internal/prd"
+)
+
+// Phase review statuses, from the executive's STATUS line.
+const (
+	phaseOnTrack        = "on_track"
+	phaseMinorConcerns  = "minor_concerns"
+	phaseNeedsAttention = "needs_attention"
+	phaseTimeout        = "timeout"
+)
+
+// maxPhaseReviewLines caps the review kept in the state file.
+const maxPhaseReviewLines = 50
+
+var (
+	phaseStatusPattern      = regexp.MustCompile(`STATUS:\s*([a-z_]+)`)
+	phaseReviewPattern      = regexp.MustCompile(`(?s)<phase_review>.*?</phase_review>`)
+	remediationTasksPattern = regexp.MustCompile(`(?s)<remediation_tasks>(.*?)</remediation_tasks>`)
+)
+
+// phaseTracker remembers the review boundaries already passed, so each
+// phase or PHASE_REVIEW_AFTER interval is reviewed once.
+type phaseTracker struct {
+	done     map[int]bool // Phases complete when last checked
+	reviewed int          // Completed tasks when last checked, without phases
+}
+
+// startPhases records the phases and task count already complete when the
+// run starts; only boundaries crossed during the run are reviewed.
+func (o *Orchestrator) startPhases() {
+	for taskID := range o.state.CompletedTaskIDs() {
+		o.prd.MarkTaskComplete(taskID)
+	}
+	o.phases.done = make(map[int]bool)
+	for _, phase := range o.prd.Phases() {
+		if o.prd.PhaseComplete(phase) {
+			o.phases.done[phase] = true
+		}
+	}
+	o.phases.reviewed, _ = o.prd.Progress()
+}
+
+// phaseBoundary reports whether completing task ends a review phase: its
+// PRD phase, or every PHASE_REVIEW_AFTER tasks in a PRD without phases. The
+// PRD's last task always ends one.
+func (o *Orchestrator) phaseBoundary(task *prd.Task) bool {
+	done, total := o.prd.Progress()
+	if !task.Passes {
+		done++
+	}
+	if done == total {
+		return true
+	}
+	if o.prd.HasPhases() {
+		if task.Phase == 0 {
+			return false
+		}
+		phaseDone, phaseTotal := o.prd.PhaseProgress(task.Phase)
+		if !task.Passes {
+			phaseDone++
+		}
+		return phaseDone == phaseTotal
+	}
+	return o.config.PhaseReviewAfter > 0 && done%o.config.PhaseReviewAfter == 0
+}
+
+// checkPhaseReviews runs a phase review for each boundary the last tasks
+// crossed, when PHASE_REVIEW_ENABLED.
+func (o *Orchestrator) checkPhaseReviews(ctx context.Context) error {
+	if !o.config.PhaseReviewEnabled {
+		return nil
+	}
+
+	if o.prd.HasPhases() {
+		for _, phase := range o.prd.Phases() {
+			if o.phases.done[phase] || !o.prd.PhaseComplete(phase) {
+				continue
+			}
+			o.phases.done[phase] = true
+			if err := o.runPhaseReview(ctx, phase); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	done, _ := o.prd.Progress()
+	last := o.phases.reviewed
+	o.phases.reviewed = done
+	if n := o.config.PhaseReviewAfter; n > 0 && done/n > last/n {
+		return o.runPhaseReview(ctx, 0)
+	}
+	return nil
+}
+
+// runPhaseReview has the executive check overall progress against the PRD
+// and acts on the result per PHASE_REVIEW_ACTION. A review that fails or
+// times out is recorded and the run continues.
+func (o *Orchestrator) runPhaseReview(ctx context.Context, phase int) error {
+	done, total := o.prd.Progress()
+	o.logger.Info("phase review", "phase", phase, "completed", done, "total", total)
+	if o.activity != nil {
+		o.activity.WriteState("PHASE_REVIEW", "starting", fmt.Sprintf("%d/%d", done, total))
+	}
+
+	remediate := o.config.PhaseReviewAction == "remediate"
+	prompt, err := o.promptBuilder.BuildPhaseReviewPrompt(o.prd, phase, remediate)
+	if err != nil {
+		o.logger.Error("failed to build phase review prompt", "error", err)
+		return nil
+	}
+
+	reviewCtx := ctx
+	if o.config.PhaseReviewTimeout > 0 {
+		var cancel context.CancelFunc
+		reviewCtx, cancel = context.WithTimeout(ctx, o.config.PhaseReviewTimeout)
+		defer cancel()
+	}
+	result, err := o.executive().Execute(reviewCtx, prompt)
+	o.markProgress()
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	status, content := "unknown", ""
+	switch {
+	case errors.Is(reviewCtx.Err(), context.DeadlineExceeded) || (result != nil && result.Timeout):
+		status = phaseTimeout
+	case err != nil:
+		o.logger.Error("phase review failed", "error", err)
+	default:
+		status, content = parsePhaseReview(result.Output)
+	}
+	o.state.AddPhaseReview(phase, done, total, status, content)
+	o.events.Publish(module.ReviewEvent(o.prd.Prefix(), "", status, content).
+		WithData("phase", phase).
+		WithData("completed", done).
+		WithData("total", total))
+	if o.activity != nil {
+		o.activity.WriteState("PHASE_REVIEW", status, fmt.Sprintf("%d/%d", done, total))
+	}
+
+	switch status {
+	case phaseOnTrack:
+		o.logger.Info("phase review: on track", "phase", phase)
+	case phaseMinorConcerns:
+		o.logger.Warn("phase review: minor concerns", "phase", phase)
+	case phaseTimeout:
+		o.logger.Warn("phase review timed out, continuing", "phase", phase, "timeout", o.config.PhaseReviewTimeout)
+		o.raiseAttention("phase_review: timeout")
+	case phaseNeedsAttention:
+		o.logger.Error("phase review: needs attention", "phase", phase, "review", content)
+		return o.phaseNeedsAttention(phase, result.Output)
+	}
+	return nil
+}
+
+// phaseNeedsAttention applies PHASE_REVIEW_ACTION to a review that found
+// problems: continue, pause until a supervisor resumes (or stop the run
+// when no one can), or add the review's corrective tasks.
+func (o *Orchestrator) phaseNeedsAttention(phase int, output string) error {
+	reason := "phase review needs attention"
+	if phase > 0 {
+		reason = fmt.Sprintf("phase %d review needs attention", phase)
+	}
+
+	switch o.config.PhaseReviewAction {
+	case "pause":
+		if !o.supervisor.Commands().Enabled() {
+			o.raiseAttention(reason)
+			return &BlockedError{Reason: reason, Pending: taskIDs(o.selected(o.prd.PendingTasks()))}
+		}
+		o.pauseFor(reason)
+	case "remediate":
+		o.raiseAttention(reason)
+		o.addRemediationTasks(phase, output)
+	default:
+		o.raiseAttention(reason)
+	}
+	return nil
+}
+
+// raiseAttention flags something for the operator to look at.
+func (o *Orchestrator) raiseAttention(reason string) {
+	o.supervisor.Status().SetAttention(reason)
+	o.events.Publish(module.AttentionEvent(o.prd.Prefix(), "", reason))
+}
+
+// addRemediationTasks adds the corrective tasks a phase review proposed to
+// the PRD, in memory and on disk. They join the reviewed phase, so later
+// phases wait for them.
+func (o *Orchestrator) addRemediationTasks(phase int, output string) {
+	m := remediationTasksPattern.FindStringSubmatch(output)
+	if m == nil {
+		o.logger.Info("phase review added no remediation tasks")
+		return
+	}
+	var proposed []prd.Task
+	if err := json.Unmarshal([]byte(strings.TrimSpace(m[1])), &proposed); err != nil {
+		o.logger.Warn("could not parse remediation tasks", "error", err)
+		return
+	}
+
+	var added []prd.Task
+	for _, task := range proposed {
+		if task.ID == "" || task.Title == "" || o.prd.TaskByID(task.ID) != nil {
+			o.logger.Warn("ignoring remediation task", "task", task.ID)
+			continue
+		}
+		task.Passes = false
+		if task.Complexity == "" {
+			task.Complexity = prd.ComplexitySenior
+		}
+		if task.DependsOn == nil {
+			task.DependsOn = []string{}
+		}
+		if task.Phase == 0 {
+			task.Phase = phase
+		}
+		added = append(added, task)
+	}
+	if len(added) == 0 {
+		return
+	}
+
+	onDisk, err := prd.Load(o.prd.Path())
+	if err != nil {
+		o.logger.Error("failed to reload PRD", "error", err)
+		return
+	}
+	onDisk.Tasks = append(onDisk.Tasks, added...)
+	if err := onDisk.Save(""); err != nil {
+		o.logger.Error("failed to save PRD", "error", err)
+		return
+	}
+	if _, _, err := onDisk.RecordRevision(prd.SourceReview); err != nil {
+		o.logger.Warn("failed to record PRD revision", "error", err)
+	}
+	o.prd.Tasks = append(o.prd.Tasks, added...)
+	for _, task := range added {
+		if o.included != nil {
+			o.included[task.ID] = true
+		}
+		o.logger.Info("remediation task added", "task", task.ID, "title", task.Title, "phase", task.Phase)
+	}
+}
+
+// parsePhaseReview extracts the status and the <phase_review> block from a
+// phase review.
+func parsePhaseReview(output string) (string, string) {
+	status := "unknown"
+	if m := phaseStatusPattern.FindStringSubmatch(output); m != nil {
+		status = m[1]
+	}
+	content := phaseReviewPattern.FindString(output)
+	if lines := strings.Split(content, "\n"); len(lines) > maxPhaseReviewLines {
+		content = strings.Join(lines[:maxPhaseReviewLines], "\n")
+	}
+	return status, content
+}
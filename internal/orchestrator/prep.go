@@ -0,0 +1,92 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"time"
+
+	"brigade/internal/config"
+	"brigade/internal/prd"
+	"brigade/internal/retrieval"
+	"brigade/internal/state"
+	"brigade/internal/worker"
+)
+
+// prepContext runs the prep cook before an attempt on an expensive tier:
+// a keyword search of the repo, refined by PREP_COOK_CMD when one is set.
+// Returns "" when disabled, on the line tier, or when nothing is found.
+func (o *Orchestrator) prepContext(ctx context.Context, task *prd.Task, tier state.WorkerTier) string {
+	if !o.config.PrepCookEnabled || tier == state.TierLine {
+		return ""
+	}
+
+	bundle, err := retrieval.Gather(".", retrieval.Keywords(task), worker.ExpandFiles(task.Files))
+	if err != nil {
+		o.logger.Warn("prep cook search failed", "task", task.ID, "error", err)
+		return ""
+	}
+	found := bundle.Render(o.config.PrepCookMaxBytes)
+
+	if o.config.PrepCookCmd != "" {
+		prep := worker.NewCLIWorker(&worker.Config{
+			Command: o.config.PrepCookCmd,
+			Tier:    state.TierLine,
+			Timeout: o.config.PrepCookTimeout,
+			Quiet:   true,
+			Env:     offlineEnv(o.config),
+		})
+		result, err := prep.Execute(ctx, retrieval.PrepPrompt(task, found, o.config.PrepCookMaxBytes))
+		if err == nil && result.Success() {
+			if summary := retrieval.ExtractContext(result.Output); summary != "" {
+				found = truncateBytes(summary, o.config.PrepCookMaxBytes)
+			}
+		} else {
+			o.logger.Warn("prep cook model failed, using search results", "task", task.ID, "error", err)
+		}
+	}
+
+	if found != "" {
+		o.logger.Info("prep cook gathered context", "task", task.ID, "files", len(bundle.Files), "bytes", len(found))
+	}
+	return found
+}
+
+// truncateBytes cuts s to at most n bytes.
+func truncateBytes(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "\n..."
+}
+
+// loadRetriever opens the code index built by `brigade index`, if there is
+// one. Returns nil when retrieval is off or the index can't be used.
+func loadRetriever(cfg *config.Config, logger *slog.Logger) worker.Retriever {
+	if cfg.IndexTopK <= 0 {
+		return nil
+	}
+	index, err := retrieval.LoadIndex(retrieval.IndexPath)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			logger.Warn("failed to load code index", "error", err)
+		}
+		return nil
+	}
+	if err := CheckOfflineEmbeddings(cfg); err != nil {
+		logger.Warn("code index unavailable", "error", err)
+		return nil
+	}
+	emb, err := retrieval.NewEmbedder(cfg.EmbeddingProvider, cfg.EmbeddingModel, cfg.EmbeddingURL)
+	if err != nil {
+		logger.Warn("code index unavailable", "error", err)
+		return nil
+	}
+	if emb.Name() != index.Embedder {
+		logger.Warn("code index was built with a different embedder, run brigade index",
+			"index", index.Embedder, "configured", emb.Name())
+		return nil
+	}
+	return retrieval.NewIndexRetriever(index, emb, cfg.IndexTopK, 30*time.Second)
+}
@@ -0,0 +1,121 @@
+package orchestrator
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"brigade/internal/prd"
+	"brigade/internal/state"
+)
+
+// prerequisite is a PRD named in dependsOnPRD and how far it got.
+type prerequisite struct {
+	name      string
+	prd       *prd.PRD
+	state     *state.State // nil if it never ran
+	completed map[string]bool
+}
+
+// loadPrerequisites loads the PRDs p depends on.
+func loadPrerequisites(p *prd.PRD) ([]prerequisite, error) {
+	var prereqs []prerequisite
+	for _, name := range p.DependsOnPRD {
+		dep, err := prd.Load(p.PrerequisitePath(name))
+		if err != nil {
+			return nil, fmt.Errorf("prerequisite PRD %s: %w", name, err)
+		}
+		pr := prerequisite{name: name, prd: dep, completed: make(map[string]bool)}
+		if store := state.ForPRD(dep.Path()); store.Exists() {
+			if st, err := store.Load(); err == nil {
+				pr.state = st
+				pr.completed = st.CompletedTaskIDs()
+			}
+		}
+		for _, task := range dep.Tasks {
+			if task.Passes {
+				pr.completed[task.ID] = true
+			}
+		}
+		prereqs = append(prereqs, pr)
+	}
+	return prereqs, nil
+}
+
+// done returns how many of the prerequisite's tasks are complete.
+func (pr prerequisite) done() int {
+	n := 0
+	for _, task := range pr.prd.Tasks {
+		if pr.completed[task.ID] {
+			n++
+		}
+	}
+	return n
+}
+
+// checkPrerequisites stops a run whose prerequisite PRDs aren't complete.
+func (o *Orchestrator) checkPrerequisites() error {
+	prereqs, err := loadPrerequisites(o.prd)
+	if err != nil {
+		return &BlockedError{Reason: err.Error()}
+	}
+	var waiting []string
+	for _, pr := range prereqs {
+		if done, total := pr.done(), len(pr.prd.Tasks); done < total {
+			waiting = append(waiting, fmt.Sprintf("%s (%d/%d tasks complete)", pr.name, done, total))
+		}
+	}
+	if len(waiting) > 0 {
+		return &BlockedError{Reason: "waiting on prerequisite PRD " + strings.Join(waiting, ", ")}
+	}
+	return nil
+}
+
+// PrerequisiteContext summarizes the outcome of the PRDs p depends on for
+// the prompts that plan and carry out p: what each built, how its tasks
+// were done, and what it left undone.
+func PrerequisiteContext(p *prd.PRD) (string, error) {
+	prereqs, err := loadPrerequisites(p)
+	if err != nil || len(prereqs) == 0 {
+		return "", err
+	}
+
+	var sb strings.Builder
+	sb.WriteString("This PRD builds on earlier PRDs. Use what they built rather than redoing it.\n")
+	for _, pr := range prereqs {
+		sb.WriteString(fmt.Sprintf("\n%s (%s)", pr.prd.FeatureName, pr.name))
+		if pr.prd.BranchName != "" {
+			sb.WriteString(fmt.Sprintf(", branch %s", pr.prd.BranchName))
+		}
+		sb.WriteString(fmt.Sprintf(": %d/%d tasks complete\n", pr.done(), len(pr.prd.Tasks)))
+		if pr.prd.Description != "" {
+			sb.WriteString(fmt.Sprintf("Description: %s\n", pr.prd.Description))
+		}
+		for _, task := range pr.prd.Tasks {
+			mark := " "
+			if pr.completed[task.ID] {
+				mark = "x"
+			}
+			sb.WriteString(fmt.Sprintf("  [%s] %s: %s\n", mark, task.ID, task.Title))
+			if pr.state == nil {
+				continue
+			}
+			if last := pr.state.LastAttempt(task.ID); last != nil && last.Approach != "" {
+				sb.WriteString(fmt.Sprintf("      Approach: %s\n", last.Approach))
+			}
+		}
+
+		// The run result says what was given up on along the way
+		var result RunResult
+		if data, err := os.ReadFile(pr.prd.ResultPath()); err == nil && json.Unmarshal(data, &result) == nil {
+			if len(result.Skipped) > 0 {
+				sb.WriteString(fmt.Sprintf("Skipped: %s\n", strings.Join(result.Skipped, ", ")))
+			}
+			for _, e := range result.Escalations {
+				sb.WriteString(fmt.Sprintf("Escalated: %s %s → %s: %s\n", e.TaskID, e.From, e.To, e.Reason))
+			}
+		}
+	}
+	return strings.TrimRight(sb.String(), "\n"), nil
+}
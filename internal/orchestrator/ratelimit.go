@@ -0,0 +1,168 @@
+package orchestrator
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"brigade/internal/module"
+	"brigade/internal/prd"
+	"brigade/internal/state"
+	"brigade/internal/worker"
+)
+
+// rateLimitTracker holds when each worker command's provider rate limit
+// resets. Limits are kept per command rather than per tier: tiers that run
+// the same command share an account, and so share its limit. A tier with
+// *_CREDENTIALS has a limit per credential instead (see limitKey).
+type rateLimitTracker struct {
+	mu       sync.Mutex
+	until    map[string]time.Time        // Limit key → when its limit resets
+	rejected map[string]bool             // Limit keys whose credential was rejected
+	current  map[state.WorkerTier]string // Credential each tier is using
+}
+
+// rateLimitsEnabled reports whether rate-limited attempts are held back
+// rather than counted as failures.
+func (o *Orchestrator) rateLimitsEnabled() bool {
+	return o.config.RateLimitBackoff > 0
+}
+
+// throttledUntil returns when a tier's rate limit resets, or the zero time
+// if it isn't limited. A tier with credentials is limited only while all
+// of them are, until the first resets.
+func (o *Orchestrator) throttledUntil(tier state.WorkerTier) time.Time {
+	o.rateLimits.mu.Lock()
+	defer o.rateLimits.mu.Unlock()
+	now := time.Now()
+
+	keys := []string{o.limitKey(tier, "")}
+	if creds := o.credentials[tier]; len(creds) > 0 {
+		keys = keys[:0]
+		for _, c := range creds {
+			if key := o.limitKey(tier, c.Name); !o.rateLimits.rejected[key] {
+				keys = append(keys, key)
+			}
+		}
+	}
+
+	var soonest time.Time
+	for _, key := range keys {
+		until := o.rateLimits.until[key]
+		if !until.After(now) {
+			return time.Time{}
+		}
+		if soonest.IsZero() || until.Before(soonest) {
+			soonest = until
+		}
+	}
+	return soonest
+}
+
+// handleRateLimit sets a task's worker command (or the credential it ran
+// with) aside until its provider's rate limit resets. The attempt isn't
+// recorded: the provider turned it away, so it counts toward neither
+// escalation nor MAX_ITERATIONS.
+func (o *Orchestrator) handleRateLimit(task *prd.Task, w worker.Worker, credential string, result *worker.Result) (attemptOutcome, error) {
+	wait := result.RetryAfter
+	if wait <= 0 {
+		wait = o.config.RateLimitBackoff
+	}
+	if o.config.RateLimitMaxWait > 0 {
+		wait = min(wait, o.config.RateLimitMaxWait)
+	}
+	resetsAt := time.Now().Add(wait)
+	limit := o.limitKey(w.Tier(), credential)
+
+	o.rateLimits.mu.Lock()
+	if o.rateLimits.until == nil {
+		o.rateLimits.until = make(map[string]time.Time)
+	}
+	if resetsAt.After(o.rateLimits.until[limit]) {
+		o.rateLimits.until[limit] = resetsAt
+	}
+	o.rateLimits.mu.Unlock()
+
+	// Nothing to recover if the service stops while waiting
+	o.state.ClearCurrentTask()
+	if credential != "" {
+		o.state.AddCredentialFailure(state.CredentialFailure{
+			Tier:       w.Tier(),
+			Credential: credential,
+			TaskID:     task.ID,
+			Reason:     "rate_limited",
+			Until:      resetsAt.Format(time.RFC3339),
+		})
+	}
+
+	o.logger.Warn("worker rate limited",
+		"task", o.prd.FormatTaskID(task.ID),
+		"worker", w.Tier(),
+		"limit", limit,
+		"resets", resetsAt.Format("15:04:05"))
+	o.events.Publish(module.RateLimitedEvent(o.prd.Prefix(), task.ID, string(w.Tier()), limit, resetsAt))
+	return outcomeThrottled, nil
+}
+
+// awaitRateLimit holds a task back while its next attempt's tier is rate
+// limited. If another ready task can run at a tier that isn't, the task is
+// set aside (yield is true) so the loop dispatches that one instead;
+// otherwise it waits for the limit to reset. Escalations to a limited tier
+// wait here too.
+func (o *Orchestrator) awaitRateLimit(ctx context.Context, task *prd.Task) (yield bool, err error) {
+	tier := o.determineWorkerTier(task)
+	until := o.throttledUntil(tier)
+	if until.IsZero() {
+		return false, nil
+	}
+
+	if o.unthrottledWork(task) {
+		o.logger.Info("deferring rate-limited task",
+			"task", o.prd.FormatTaskID(task.ID),
+			"worker", tier,
+			"resets", until.Format("15:04:05"))
+		o.emitThrottled(task, tier, until, "deferred")
+		return true, nil
+	}
+
+	o.logger.Info("waiting for rate limit to reset",
+		"task", o.prd.FormatTaskID(task.ID),
+		"worker", tier,
+		"wait", time.Until(until).Round(time.Second))
+	o.emitThrottled(task, tier, until, "waiting")
+	select {
+	case <-ctx.Done():
+		return false, ctx.Err()
+	case <-time.After(time.Until(until)):
+	}
+	o.markProgress() // Waiting out a limit isn't idling
+	return false, nil
+}
+
+// emitThrottled reports a task held back by a rate limit.
+func (o *Orchestrator) emitThrottled(task *prd.Task, tier state.WorkerTier, until time.Time, action string) {
+	o.events.Publish(module.ThrottledEvent(o.prd.Prefix(), task.ID, string(tier), until, action))
+}
+
+// unthrottledWork reports whether a ready task other than task can run at
+// a tier that isn't rate limited.
+func (o *Orchestrator) unthrottledWork(task *prd.Task) bool {
+	for _, t := range o.selected(o.prd.ReadyTasks(o.state.CompletedTaskIDs())) {
+		if t.ID != task.ID && o.throttledUntil(o.determineWorkerTier(t)).IsZero() {
+			return true
+		}
+	}
+	return false
+}
+
+// preferUnthrottled moves ready tasks whose tier is rate limited behind the
+// rest, keeping the order within each group.
+func (o *Orchestrator) preferUnthrottled(tasks []*prd.Task) []*prd.Task {
+	sorted := append([]*prd.Task(nil), tasks...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return o.throttledUntil(o.determineWorkerTier(sorted[i])).IsZero() &&
+			!o.throttledUntil(o.determineWorkerTier(sorted[j])).IsZero()
+	})
+	return sorted
+}
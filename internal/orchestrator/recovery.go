@@ -0,0 +1,84 @@
+package orchestrator
+
+import (
+	"fmt"
+	"strings"
+
+	"brigade/internal/state"
+	"brigade/internal/util"
+)
+
+// maxRecoveryStatusLines caps the working tree listing in recovery prompts.
+const maxRecoveryStatusLines = 40
+
+// orphanedAttemptError is recorded for attempts Brigade did not see finish.
+const orphanedAttemptError = "attempt interrupted: brigade exited while the worker was running"
+
+// recoverOrphanedAttempt detects an attempt left in flight by a previous
+// run that crashed or was killed. The attempt is recorded as failed so it
+// counts toward escalation, and the task's next prompt describes what the
+// interrupted worker left in the working tree.
+func (o *Orchestrator) recoverOrphanedAttempt() {
+	taskID := o.state.OrphanedTask()
+	if taskID == "" {
+		return
+	}
+	task := o.prd.TaskByID(taskID)
+	if task == nil {
+		o.state.ClearCurrentTask()
+		return
+	}
+
+	// An attempt cut off while it was being checked already has an entry
+	if !o.state.ResolveAttempt(taskID, state.StatusFailed, orphanedAttemptError, "") {
+		o.state.AddTaskHistory(state.TaskHistory{
+			TaskID: taskID,
+			Worker: o.determineWorkerTier(task),
+			Status: state.StatusFailed,
+			Error:  orphanedAttemptError,
+		})
+	}
+
+	changes := util.GitStatusShort()
+	o.recoveryTask = taskID
+	o.recoveryContext = buildRecoveryContext(changes)
+
+	o.logger.Warn("recovered interrupted attempt",
+		"task", o.prd.FormatTaskID(taskID),
+		"started", o.state.CurrentTaskStarted,
+		"changedFiles", countLines(changes))
+	if o.activity != nil {
+		o.activity.WriteState("RECOVERED", "orphaned_attempt", taskID)
+	}
+}
+
+// buildRecoveryContext tells the retrying worker that a previous attempt
+// was cut off and what it may have left behind.
+func buildRecoveryContext(changes string) string {
+	var sb strings.Builder
+	sb.WriteString("A previous attempt at this task was interrupted before it finished ")
+	sb.WriteString("(Brigade exited while the worker was running).\n")
+
+	if changes == "" {
+		sb.WriteString("No uncommitted changes were detected; check for partial work before starting over.\n")
+		return sb.String()
+	}
+
+	sb.WriteString("It may have left partial work. Uncommitted changes in the working tree:\n\n")
+	lines := strings.Split(changes, "\n")
+	if len(lines) > maxRecoveryStatusLines {
+		lines = append(lines[:maxRecoveryStatusLines], fmt.Sprintf("... and %d more", len(lines)-maxRecoveryStatusLines))
+	}
+	sb.WriteString(strings.Join(lines, "\n"))
+	sb.WriteString("\n\nReview these changes first. Keep what is correct and finish it; ")
+	sb.WriteString("revert anything half-written or broken rather than building on it.\n")
+	return sb.String()
+}
+
+// countLines returns the number of non-empty lines in s.
+func countLines(s string) int {
+	if s == "" {
+		return 0
+	}
+	return strings.Count(s, "\n") + 1
+}
@@ -0,0 +1,85 @@
+package orchestrator
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"brigade/internal/prd"
+)
+
+// prdWatch notices edits to the PRD file made while the service runs (a
+// `brigade replan`, a `graph add-dep`, a hand edit) so they take effect from
+// the next task.
+type prdWatch struct {
+	modTime time.Time
+}
+
+// watchPRD records the PRD file's current modification time.
+func (o *Orchestrator) watchPRD() {
+	if info, err := os.Stat(o.prd.Path()); err == nil {
+		o.prdWatch.modTime = info.ModTime()
+	}
+}
+
+// reloadPRD picks up the PRD file if it changed since the last look. Tasks
+// that passed or were skipped this run stay that way, tasks added join a
+// partial run's selection, and an edit that doesn't validate is ignored.
+func (o *Orchestrator) reloadPRD() {
+	info, err := os.Stat(o.prd.Path())
+	if err != nil || !info.ModTime().After(o.prdWatch.modTime) {
+		return
+	}
+	o.prdWatch.modTime = info.ModTime()
+
+	fresh, err := prd.Load(o.prd.Path())
+	if err != nil {
+		o.logger.Warn("ignoring unreadable PRD edit", "error", err)
+		return
+	}
+	if result := fresh.ValidateQuick(); len(result.Errors) > 0 {
+		o.logger.Warn("ignoring invalid PRD edit", "errors", len(result.Errors), "first", result.Errors[0].Error())
+		return
+	}
+	for i := range fresh.Tasks {
+		if task := o.prd.TaskByID(fresh.Tasks[i].ID); task != nil && task.Passes {
+			fresh.Tasks[i].Passes = true
+		}
+	}
+
+	d := prd.Compare(o.prd, fresh)
+	if d.Empty() {
+		return
+	}
+	*o.prd = *fresh
+	if o.included != nil {
+		for _, id := range d.Added {
+			o.included[id] = true
+		}
+	}
+
+	o.recordRevision(prd.SourceEdit)
+
+	o.logger.Info("PRD changed on disk, reloaded",
+		"added", len(d.Added), "removed", len(d.Removed), "modified", len(d.Modified))
+	if o.activity != nil {
+		o.activity.WriteState("PRD_RELOAD", "",
+			fmt.Sprintf("+%d -%d ~%d", len(d.Added), len(d.Removed), len(d.Modified)))
+	}
+}
+
+// recordRevision adds the PRD to its version history if it changed, and
+// links a new state to the revision it started from.
+func (o *Orchestrator) recordRevision(source string) {
+	rev, recorded, err := o.prd.RecordRevision(source)
+	if err != nil {
+		o.logger.Warn("failed to record PRD revision", "error", err)
+		return
+	}
+	if recorded {
+		o.logger.Debug("PRD revision recorded", "version", rev.Version, "source", source, "summary", rev.Summary)
+	}
+	if o.state.PRDRevision == 0 {
+		o.state.PRDRevision = rev.Version
+	}
+}
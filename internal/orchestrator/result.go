@@ -0,0 +1,174 @@
+package orchestrator
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"time"
+
+	"brigade/internal/state"
+)
+
+// RunResult is the machine-readable outcome of a service run, written next
+// to the state file so CI wrappers have a single artifact to parse.
+type RunResult struct {
+	PRD             string             `json:"prd"`
+	FeatureName     string             `json:"featureName"`
+	Success         bool               `json:"success"`
+	Error           string             `json:"error,omitempty"`
+	StartedAt       string             `json:"startedAt"`
+	FinishedAt      string             `json:"finishedAt"`
+	DurationSeconds int                `json:"durationSeconds"`
+	Completed       int                `json:"completed"`
+	Total           int                `json:"total"`
+	Tasks           []TaskResult       `json:"tasks"`
+	Escalations     []state.Escalation `json:"escalations"`
+	Skipped         []string           `json:"skipped"`
+	EstimatedCost   float64            `json:"estimatedCost"` // USD, from the pricing table
+
+	// Ways out the executive proposed, when the run ended blocked
+	UnblockSuggestions []state.UnblockSuggestion `json:"unblockSuggestions,omitempty"`
+
+	// How rebasing a worktree's branch onto the default branch went
+	Merge *MergeReport `json:"merge,omitempty"`
+
+	// Where this run's time and money went
+	Report *state.RunReport `json:"report,omitempty"`
+
+	// How many events of each type the run published, up to its end
+	Events map[string]int `json:"events,omitempty"`
+}
+
+// TaskResult is the outcome of one task.
+type TaskResult struct {
+	ID              string           `json:"id"`
+	Title           string           `json:"title"`
+	Status          state.TaskStatus `json:"status"`
+	Worker          state.WorkerTier `json:"worker,omitempty"` // Tier of the last attempt
+	Attempts        int              `json:"attempts"`
+	DurationSeconds int              `json:"durationSeconds"`
+	Escalated       bool             `json:"escalated,omitempty"`
+	Error           string           `json:"error,omitempty"` // Last failure, if not complete
+	EstimatedCost   float64          `json:"estimatedCost"`
+	Flaky           []string         `json:"flaky,omitempty"`     // Verification commands caught flipping
+	BlockedBy       []string         `json:"blockedBy,omitempty"` // Skipped tasks it can't run without
+}
+
+// ResultPath returns where the run result is written.
+func (o *Orchestrator) ResultPath() string {
+	return o.prd.ResultPath()
+}
+
+// HandoffPath returns where the handoff doc is written.
+func (o *Orchestrator) HandoffPath() string {
+	return o.prd.HandoffPath()
+}
+
+// Result returns the result of the last run, or nil before Run finishes.
+func (o *Orchestrator) Result() *RunResult {
+	return o.result
+}
+
+// buildResult summarizes the PRD and state after a run.
+func (o *Orchestrator) buildResult(runErr error) *RunResult {
+	finished := time.Now()
+	r := &RunResult{
+		PRD:             o.prd.Path(),
+		FeatureName:     o.prd.FeatureName,
+		StartedAt:       o.startTime.Format(time.RFC3339),
+		FinishedAt:      finished.Format(time.RFC3339),
+		DurationSeconds: int(finished.Sub(o.startTime).Seconds()),
+		Total:           o.prd.TotalTasks(),
+		Tasks:           []TaskResult{},
+		Escalations:     append([]state.Escalation{}, o.state.Escalations...),
+		Skipped:         []string{},
+		Merge:           o.merge,
+		Events:          o.metrics.Counts(),
+	}
+	report := o.kitchenReport(finished)
+	r.Report = &report
+	if runErr != nil {
+		r.Error = runErr.Error()
+		var blocked *BlockedError
+		if errors.As(runErr, &blocked) {
+			r.UnblockSuggestions = o.state.UnblockSuggestions
+		}
+	}
+
+	completed := o.state.CompletedTaskIDs()
+	flaky := o.state.FlakyCommands()
+	blockedBy := o.prd.BlockedBy(o.state.SkippedTaskIDs())
+	for _, task := range o.prd.Tasks {
+		tr := TaskResult{
+			ID:        task.ID,
+			Title:     task.Title,
+			Status:    state.StatusPending,
+			Escalated: o.state.WasEscalated(task.ID),
+		}
+		for _, h := range o.state.TaskHistory {
+			if h.TaskID != task.ID {
+				continue
+			}
+			if h.Status != state.StatusSkipped {
+				tr.Attempts++
+				tr.Worker = h.Worker
+			}
+			tr.Status = h.Status
+			tr.Error = h.Error
+			tr.DurationSeconds += h.Duration
+			tr.EstimatedCost += o.costEstimator.AttemptCost(h)
+		}
+		if completed[task.ID] && tr.Status != state.StatusAbsorbed {
+			tr.Status = state.StatusComplete
+		} else if tr.Status == state.StatusPending && task.Passes {
+			// Completed before Brigade tracked it
+			tr.Status = state.StatusComplete
+		}
+
+		switch tr.Status {
+		case state.StatusComplete, state.StatusAbsorbed:
+			tr.Error = ""
+			r.Completed++
+		case state.StatusSkipped:
+			r.Skipped = append(r.Skipped, task.ID)
+		default:
+			tr.BlockedBy = blockedBy[task.ID]
+		}
+		for cmd := range flaky[task.ID] {
+			tr.Flaky = append(tr.Flaky, cmd)
+		}
+		sort.Strings(tr.Flaky)
+		tr.EstimatedCost = roundCost(tr.EstimatedCost)
+		r.EstimatedCost += tr.EstimatedCost
+		r.Tasks = append(r.Tasks, tr)
+	}
+
+	r.EstimatedCost = roundCost(r.EstimatedCost)
+	r.Success = runErr == nil && r.Completed == r.Total
+	return r
+}
+
+// roundCost rounds a dollar amount to a tenth of a cent.
+func roundCost(usd float64) float64 {
+	return math.Round(usd*1000) / 1000
+}
+
+// writeResult writes the run result file.
+func (o *Orchestrator) writeResult(runErr error) error {
+	path := o.ResultPath()
+	if path == "" {
+		return fmt.Errorf("no PRD path for result file")
+	}
+	o.result = o.buildResult(runErr)
+	data, err := json.MarshalIndent(o.result, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling result: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("writing result: %w", err)
+	}
+	return nil
+}
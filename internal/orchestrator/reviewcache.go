@@ -0,0 +1,94 @@
+package orchestrator
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"brigade/internal/module"
+	"brigade/internal/prd"
+	"brigade/internal/state"
+	"brigade/internal/util"
+	"brigade/internal/worker"
+)
+
+// taskDiffHash hashes the changes a task's attempts have made since it
+// started, committed or not, outside brigade/. Unlike codeFingerprint it
+// leaves HEAD out, so an attempt that commits the same change again
+// matches. Returns "" when the task's start isn't known.
+func (o *Orchestrator) taskDiffHash(taskID string) string {
+	base := o.taskStart(taskID)
+	if base == "" || base == "unknown" {
+		return ""
+	}
+
+	h := sha256.New()
+	io.WriteString(h, util.GitDiffRange(base, "", false, ".", ":(exclude)brigade/"))
+
+	// git diff leaves out untracked files
+	var files []string
+	for _, f := range util.GitChangedFiles() {
+		if !strings.HasPrefix(f, "brigade/") {
+			files = append(files, f)
+		}
+	}
+	sort.Strings(files)
+	for _, f := range files {
+		io.WriteString(h, "\x00"+f+"\n")
+		if data, err := os.ReadFile(f); err == nil {
+			h.Write(data)
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// reviewChanges runs the executive review of a completed attempt and
+// records its verdict. When an earlier attempt left exactly the same
+// changes within EXEC_CACHE_TTL and the executive judged them, that
+// verdict is reused instead: the review would cost the same and say the
+// same, and a worker repeating itself should hear so. The outcome of a
+// review that didn't run, or got no verdict, is never reused.
+func (o *Orchestrator) reviewChanges(ctx context.Context, task *prd.Task, w worker.Worker, workerOutput string, policy []string) (bool, string, string) {
+	diff := o.taskDiffHash(task.ID)
+	review := state.Review{TaskID: task.ID, Diff: diff}
+
+	var prior *state.Review
+	if diff != "" && o.config.ExecCacheTTL > 0 {
+		prior = o.state.ReviewOfDiff(task.ID, diff)
+		if prior != nil {
+			if reviewed, err := time.Parse(time.RFC3339, prior.Timestamp); err != nil || time.Since(reviewed) > o.config.ExecCacheTTL {
+				prior = nil
+			}
+		}
+	}
+	var passed bool
+	var reviewOutput string
+	if prior != nil {
+		o.logger.Warn("attempt repeats changes already reviewed, reusing the verdict", "task", task.ID, "result", prior.Result, "reviewed", prior.Timestamp)
+		passed, review.Reused = prior.Result == "pass", true
+		if !passed {
+			review.Reason = "the changes are the same as an earlier attempt's, which failed review: " + prior.Reason
+		}
+	} else {
+		v := o.runReview(ctx, task, workerOutput, policy)
+		passed, review.Reason, reviewOutput = v.passed, v.reason, v.output
+		if !v.judged {
+			review.Diff = "" // An executive that didn't answer mustn't stand in for a review
+		}
+	}
+
+	review.Result = "pass"
+	if !passed {
+		review.Result = "fail"
+	}
+	o.state.RecordReview(review)
+	o.events.Publish(module.ReviewEvent(o.prd.Prefix(), task.ID, review.Result, review.Reason).
+		WithWorker(string(w.Tier())).
+		WithData("reused", review.Reused))
+	return passed, review.Reason, reviewOutput
+}
@@ -0,0 +1,69 @@
+package orchestrator
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"brigade/internal/prd"
+	"brigade/internal/state"
+	"brigade/internal/util"
+	"brigade/internal/verify"
+)
+
+// errSecretLeak marks a failed secret scan, so the attempt is classified as
+// a leak whatever else its output matches.
+var errSecretLeak = errors.New("secret scan failed")
+
+// scanSecrets looks for secrets the task's work added: in the commits since
+// its first attempt started and in the uncommitted changes. On a find it
+// records the findings, redacted, as a verification failure for the retry
+// prompt and returns an error wrapping errSecretLeak.
+func (o *Orchestrator) scanSecrets(task *prd.Task) error {
+	if !o.config.SecretScanEnabled {
+		return nil
+	}
+
+	var changed []string
+	for _, f := range util.GitChangedFiles() {
+		if !strings.HasPrefix(f, "brigade/") {
+			changed = append(changed, f)
+		}
+	}
+	start := o.taskStart(task.ID)
+	scanner := verify.NewSecretScanner()
+	scanner.SetIgnore(o.config.SecretScanIgnore)
+
+	var findings []verify.SecretFinding
+	committed := false
+	for _, f := range scanner.ScanDiff(util.GitLogPatch(start) + util.GitDiff(changed)) {
+		if strings.HasPrefix(f.File, "brigade/") {
+			continue
+		}
+		findings = append(findings, f)
+		committed = committed || f.Commit != ""
+	}
+	if len(findings) == 0 {
+		return nil
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%d secret(s) in the task's changes:\n", len(findings)))
+	for _, f := range findings {
+		sb.WriteString("  " + f.String() + "\n")
+	}
+	sb.WriteString("Remove each secret and read it from configuration or an environment variable instead.")
+	if committed {
+		sb.WriteString(fmt.Sprintf(" Commits keep what they added, so rewrite them without the secret (e.g. `git reset --soft %s` and commit again).", start[:min(len(start), 7)]))
+	}
+	sb.WriteString(" If a value is a deliberate test fixture, mark its line with a `brigade:allow-secret` comment.")
+	report := sb.String()
+
+	o.logger.Warn("secrets in task changes", "task", task.ID, "count", len(findings))
+	o.state.SetVerificationFailures(task.ID, []state.VerificationFailure{{
+		Command:  "secret scan",
+		ExitCode: 1,
+		Output:   report,
+	}})
+	return fmt.Errorf("%w: %s", errSecretLeak, report)
+}
@@ -0,0 +1,85 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"brigade/internal/config"
+	"brigade/internal/prd"
+	"brigade/internal/services"
+	"brigade/internal/verify"
+)
+
+// maxServiceLog caps the service output kept from a failed verification.
+const maxServiceLog = 2000
+
+// loadServices merges the services in SERVICES_CONFIG with the PRD's, the
+// PRD's overriding ones of the same name, and checks that every service a
+// task lists is declared. Returns nil when there are none.
+func loadServices(cfg *config.Config, p *prd.PRD) (*services.Manager, error) {
+	var shared map[string]prd.Service
+	if cfg.ServicesConfig != "" {
+		loaded, err := services.Load(cfg.ServicesConfig)
+		if err != nil {
+			return nil, fmt.Errorf("loading SERVICES_CONFIG: %w", err)
+		}
+		shared = loaded
+	}
+
+	specs := services.Merge(shared, p.Services)
+	for _, task := range p.Tasks {
+		for _, name := range task.Services {
+			if _, ok := specs[name]; !ok {
+				return nil, fmt.Errorf("task %s: unknown service %q", task.ID, name)
+			}
+		}
+	}
+	if len(specs) == 0 {
+		return nil, nil
+	}
+	return services.NewManager(specs, services.LogDir, cfg.ServiceReadyTimeout), nil
+}
+
+// runVerification runs a task's verification commands with the services
+// it needs running, and stops them afterwards. A service that doesn't
+// come up fails verification, since the worker's changes may be why. On
+// failure the services' logs are kept with the result for the retry
+// prompt.
+func (o *Orchestrator) runVerification(ctx context.Context, task *prd.Task) (*verify.Result, error) {
+	runner := o.taskVerifier(task)
+	var names []string
+	if o.services != nil {
+		names = task.ServicesNeeded(o.services.Specs())
+	}
+	if len(names) == 0 {
+		return runner.Run(ctx, task)
+	}
+
+	o.servicesMu.Lock()
+	defer o.servicesMu.Unlock()
+	defer o.services.Stop()
+
+	o.logger.Info("starting services", "task", task.ID, "services", names)
+	var result *verify.Result
+	if err := o.services.Start(ctx, names, runner.Env); err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		o.logger.Warn("services failed to start", "task", task.ID, "error", err)
+		result = &verify.Result{Results: []verify.CommandResult{{
+			Command: "start services: " + strings.Join(names, ", "),
+			Error:   err.Error(),
+		}}}
+	} else {
+		result, err = runner.Run(ctx, task)
+		if err != nil || result.Passed {
+			return result, err
+		}
+	}
+
+	for _, l := range o.services.Logs(maxServiceLog) {
+		result.Logs = append(result.Logs, verify.Log{Name: "service " + l.Name, Output: l.Output})
+	}
+	return result, nil
+}
@@ -0,0 +1,80 @@
+package orchestrator
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"brigade/internal/prd"
+	"brigade/internal/state"
+	"brigade/internal/util"
+)
+
+var (
+	reviewConfidencePattern = regexp.MustCompile(`(?i)<confidence>\s*(high|medium|low)\s*</confidence>`)
+	reviewConcernsPattern   = regexp.MustCompile(`(?s)<concerns>(.*?)</concerns>`)
+)
+
+// queueHumanReview flags a completed task for a human spot-check when
+// HUMAN_REVIEW_QUEUE is on and its executive review passed with no more than
+// HUMAN_REVIEW_CONFIDENCE, or it took more than HUMAN_REVIEW_ITERATIONS
+// attempts. `brigade review` works through the queue.
+func (o *Orchestrator) queueHumanReview(task *prd.Task, reviewOutput string) {
+	if !o.config.HumanReviewQueue {
+		return
+	}
+
+	confidence, concerns := parseReviewConfidence(reviewOutput)
+	var reasons []string
+	if confidence != "" && confidenceAtMost(confidence, o.config.HumanReviewConfidence) {
+		reasons = append(reasons, fmt.Sprintf("review passed with %s confidence", confidence))
+	}
+	if limit := o.config.HumanReviewIterations; limit > 0 {
+		if n := o.state.TotalAttempts(task.ID); n > limit {
+			reasons = append(reasons, fmt.Sprintf("took %d attempts", n))
+		}
+	}
+	if len(reasons) == 0 {
+		return
+	}
+
+	o.state.QueueHumanReview(state.QueuedReview{
+		TaskID:     task.ID,
+		Reasons:    reasons,
+		Confidence: confidence,
+		Review:     concerns,
+		BaseCommit: o.taskStart(task.ID),
+		Commit:     util.GetHeadCommit(),
+	})
+	o.logger.Info("queued for human review",
+		"task", o.prd.FormatTaskID(task.ID), "reasons", strings.Join(reasons, "; "))
+	if o.activity != nil {
+		o.activity.WriteState("HUMAN_REVIEW", "queued", task.ID)
+	}
+}
+
+// parseReviewConfidence extracts the confidence a passing review gave and
+// the concerns it listed.
+func parseReviewConfidence(output string) (string, string) {
+	confidence := ""
+	if m := reviewConfidencePattern.FindStringSubmatch(output); m != nil {
+		confidence = strings.ToLower(m[1])
+	}
+	concerns := ""
+	if m := reviewConcernsPattern.FindStringSubmatch(output); m != nil {
+		concerns = strings.TrimSpace(m[1])
+	}
+	return confidence, concerns
+}
+
+// confidenceAtMost reports whether a review's confidence is at or below the
+// threshold ("off" flags nothing).
+func confidenceAtMost(confidence, threshold string) bool {
+	switch threshold {
+	case "low":
+		return confidence == "low"
+	case "medium":
+		return confidence == "low" || confidence == "medium"
+	}
+	return false
+}
@@ -0,0 +1,66 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"brigade/internal/state"
+)
+
+var unblockSuggestionPattern = regexp.MustCompile(`(?s)<suggestion\s+kind="([a-z_]+)">(.*?)</suggestion>`)
+
+// suggestUnblock has the executive chef look at a blocked run's dependency
+// graph, skipped tasks and failures and propose ways out. The suggestions
+// go into the state (and so the result and `brigade summary`) and the
+// backlog. A failed analysis is logged and otherwise ignored.
+func (o *Orchestrator) suggestUnblock(ctx context.Context, blocked *BlockedError) {
+	if !o.config.UnblockAnalysis {
+		return
+	}
+	o.logger.Info("analyzing how to unblock the run")
+
+	prompt, err := o.promptBuilder.BuildUnblockPrompt(o.prd, o.state, blocked.Error())
+	if err != nil {
+		o.logger.Warn("failed to build unblock prompt", "error", err)
+		return
+	}
+
+	analysisCtx := ctx
+	if o.config.UnblockAnalysisTimeout > 0 {
+		var cancel context.CancelFunc
+		analysisCtx, cancel = context.WithTimeout(ctx, o.config.UnblockAnalysisTimeout)
+		defer cancel()
+	}
+	result, err := o.executive().Execute(analysisCtx, prompt)
+	if err != nil || result == nil || result.Timeout {
+		o.logger.Warn("unblock analysis failed", "error", err)
+		return
+	}
+
+	suggestions := parseUnblockSuggestions(result.Output)
+	if len(suggestions) == 0 {
+		o.logger.Info("unblock analysis made no suggestions")
+		return
+	}
+	o.state.SetUnblockSuggestions(suggestions)
+	for _, s := range suggestions {
+		o.logger.Info("unblock suggestion", "kind", s.Kind, "suggestion", s.Suggestion)
+		o.promptBuilder.AppendBacklog(fmt.Sprintf("[unblock %s] %s: %s", o.prd.Prefix(), s.Kind, s.Suggestion))
+	}
+}
+
+// parseUnblockSuggestions extracts the <suggestion> entries of an unblock
+// analysis.
+func parseUnblockSuggestions(output string) []state.UnblockSuggestion {
+	var suggestions []state.UnblockSuggestion
+	for _, m := range unblockSuggestionPattern.FindAllStringSubmatch(output, -1) {
+		text := strings.Join(strings.Fields(m[2]), " ")
+		if text == "" {
+			continue
+		}
+		suggestions = append(suggestions, state.UnblockSuggestion{Kind: m[1], Suggestion: text})
+	}
+	return suggestions
+}
@@ -0,0 +1,109 @@
+package orchestrator
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"sync"
+
+	"brigade/internal/prd"
+	"brigade/internal/util"
+	"brigade/internal/verify"
+	"brigade/internal/workspace"
+)
+
+// sharedFiles may change outside a workspace: root lockfiles that package
+// managers update for any member.
+var sharedFiles = map[string]bool{
+	"go.work.sum":       true,
+	"pnpm-lock.yaml":    true,
+	"package-lock.json": true,
+	"yarn.lock":         true,
+}
+
+// taskVerifier returns the verification runner for a task, scoped to its
+// workspace if it has one and with the task's env. Browser artifacts go to
+// the current attempt's artifacts directory.
+func (o *Orchestrator) taskVerifier(task *prd.Task) *verify.Runner {
+	runner := o.verifier
+	if task.Workspace != "" {
+		runner = runner.In(task.Workspace)
+	}
+	if len(task.Env) > 0 {
+		runner = runner.WithEnv(task.EnvList()...)
+	}
+	scoped := *runner
+	scoped.ArtifactsDir = o.attemptArtifactsDir(task)
+	return &scoped
+}
+
+// workspaceMap returns the codebase map for a task's workspace, if one has
+// been generated with `brigade map --workspace`.
+func workspaceMap(task *prd.Task) string {
+	if task.Workspace == "" {
+		return ""
+	}
+	data, err := os.ReadFile(workspace.MapPath(task.Workspace))
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// confinement holds, by task ID, the files already changed when a task's
+// current attempt started. Tasks without an entry aren't confined.
+type confinement struct {
+	mu        sync.Mutex
+	baselines map[string]map[string]bool
+}
+
+// snapshotChanges records the files already changed before an attempt, so
+// only the attempt's own edits are checked against its workspace. Tasks in
+// a parallel batch share the working tree, so their edits can't be told
+// apart and they aren't confined.
+func (o *Orchestrator) snapshotChanges(task *prd.Task) {
+	c := &o.confinement
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.baselines, task.ID)
+	if !o.config.WorkspaceConfineEdits || task.Workspace == "" || o.inParallelBatch() {
+		return
+	}
+	baseline := make(map[string]bool)
+	for _, f := range util.GitChangedFiles() {
+		baseline[f] = true
+	}
+	if c.baselines == nil {
+		c.baselines = make(map[string]map[string]bool)
+	}
+	c.baselines[task.ID] = baseline
+}
+
+// workspaceViolation describes edits an attempt made outside its task's
+// workspace, or returns "" if there are none or edits aren't confined.
+func (o *Orchestrator) workspaceViolation(task *prd.Task) string {
+	c := &o.confinement
+	c.mu.Lock()
+	baseline, ok := c.baselines[task.ID]
+	c.mu.Unlock()
+	if !ok {
+		return ""
+	}
+
+	var changed []string
+	for _, f := range util.GitChangedFiles() {
+		if !baseline[f] && !sharedFiles[f] && !strings.HasPrefix(f, "brigade/") {
+			changed = append(changed, f)
+		}
+	}
+	outside := workspace.Outside(changed, path.Clean(task.Workspace))
+	if len(outside) == 0 {
+		return ""
+	}
+	if len(outside) > 5 {
+		outside = append(outside[:5], fmt.Sprintf("and %d more", len(outside)-5))
+	}
+	return fmt.Sprintf("edited files outside workspace %s: %s. Revert them and keep changes inside the workspace",
+		task.Workspace, strings.Join(outside, ", "))
+}
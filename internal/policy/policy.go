@@ -0,0 +1,231 @@
+// Package policy decides which overrides need an operator's approval, works
+// out who the operator is, and records each approval in an audit log.
+package policy
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"brigade/internal/config"
+)
+
+// Actions that APPROVAL_REQUIRED can name.
+const (
+	ForceUnlock    = "force-unlock"    // Remove or take over a live service lock
+	AcceptRisk     = "accept-risk"     // Start a PRD at or over RISK_WARN_THRESHOLD
+	AcceptCost     = "accept-cost"     // Start a run projected over COST_WARN_THRESHOLD
+	ProtectedPaths = "protected-paths" // Keep a task's edits to PROTECTED_PATHS
+)
+
+// Where an operator's identity came from.
+const (
+	SourceToken = "token" // BRIGADE_OPERATOR_TOKEN, checked against OPERATOR_TOKENS_FILE
+	SourceEnv   = "env"   // BRIGADE_OPERATOR
+	SourceUser  = "user"  // The OS user
+)
+
+// Operator is who approved an action.
+type Operator struct {
+	Name   string `json:"operator"`
+	Source string `json:"source"`
+}
+
+// Entry is one line of the audit log.
+type Entry struct {
+	Timestamp time.Time `json:"timestamp"`
+	Action    string    `json:"action"`
+	Operator
+	PRD    string `json:"prd,omitempty"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// Policy holds the approval settings.
+type Policy struct {
+	Required   []string // Actions that need an approval
+	Operators  []string // Who may approve; empty means anyone
+	TokensFile string   // "name:sha256" lines; when set, only a token identifies an operator
+	AuditLog   string   // JSON lines of approvals
+}
+
+// FromConfig returns the policy APPROVAL_REQUIRED, OPERATORS,
+// OPERATOR_TOKENS_FILE and AUDIT_LOG configure.
+func FromConfig(cfg *config.Config) *Policy {
+	return &Policy{
+		Required:   cfg.ApprovalRequired,
+		Operators:  cfg.Operators,
+		TokensFile: cfg.OperatorTokensFile,
+		AuditLog:   cfg.AuditLog,
+	}
+}
+
+// Requires reports whether action needs an operator's approval.
+func (p *Policy) Requires(action string) bool {
+	for _, a := range p.Required {
+		if a == action {
+			return true
+		}
+	}
+	return false
+}
+
+// Approve checks that the current operator may approve action and records
+// the approval in the audit log. Actions that don't need approval pass
+// without a record. An approval that can't be recorded is refused.
+func (p *Policy) Approve(action, prdPath, detail string) (Operator, error) {
+	if !p.Requires(action) {
+		return Operator{}, nil
+	}
+	op, err := p.Identify()
+	if err != nil {
+		return op, fmt.Errorf("%s needs an operator's approval: %w", action, err)
+	}
+	if !p.allowed(op.Name) {
+		return op, fmt.Errorf("%s needs an operator's approval: %s is not in OPERATORS", action, op.Name)
+	}
+	entry := Entry{
+		Timestamp: time.Now(),
+		Action:    action,
+		Operator:  op,
+		PRD:       prdPath,
+		Detail:    detail,
+	}
+	if err := p.record(entry); err != nil {
+		return op, fmt.Errorf("recording %s approval: %w", action, err)
+	}
+	return op, nil
+}
+
+// Identify returns the current operator: the owner of BRIGADE_OPERATOR_TOKEN,
+// else BRIGADE_OPERATOR, else the OS user. With a tokens file only a token
+// will do, since anyone sharing the credentials can set a name.
+func (p *Policy) Identify() (Operator, error) {
+	if token := os.Getenv("BRIGADE_OPERATOR_TOKEN"); token != "" {
+		if p.TokensFile == "" {
+			return Operator{}, fmt.Errorf("BRIGADE_OPERATOR_TOKEN is set but OPERATOR_TOKENS_FILE isn't")
+		}
+		name, err := lookupToken(p.TokensFile, token)
+		if err != nil {
+			return Operator{}, err
+		}
+		return Operator{Name: name, Source: SourceToken}, nil
+	}
+	if p.TokensFile != "" {
+		return Operator{}, fmt.Errorf("set BRIGADE_OPERATOR_TOKEN to a token from %s", p.TokensFile)
+	}
+	if name := os.Getenv("BRIGADE_OPERATOR"); name != "" {
+		return Operator{Name: name, Source: SourceEnv}, nil
+	}
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return Operator{Name: u.Username, Source: SourceUser}, nil
+	}
+	if name := os.Getenv("USER"); name != "" {
+		return Operator{Name: name, Source: SourceUser}, nil
+	}
+	return Operator{}, fmt.Errorf("no operator identity; set BRIGADE_OPERATOR")
+}
+
+func (p *Policy) allowed(name string) bool {
+	if len(p.Operators) == 0 {
+		return true
+	}
+	for _, op := range p.Operators {
+		if op == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *Policy) record(entry Entry) error {
+	if p.AuditLog == "" {
+		return fmt.Errorf("AUDIT_LOG is empty")
+	}
+	if dir := filepath.Dir(p.AuditLog); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(p.AuditLog, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// HashToken returns the hex SHA-256 of a token, as OPERATOR_TOKENS_FILE
+// stores it.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// lookupToken returns the operator whose hash in the tokens file matches
+// token. Blank lines and # comments are skipped.
+func lookupToken(file, token string) (string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return "", fmt.Errorf("reading OPERATOR_TOKENS_FILE: %w", err)
+	}
+	defer f.Close()
+
+	hash := HashToken(token)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, sum, ok := strings.Cut(line, ":")
+		if ok && strings.EqualFold(strings.TrimSpace(sum), hash) {
+			return strings.TrimSpace(name), nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	return "", fmt.Errorf("BRIGADE_OPERATOR_TOKEN matches no operator in %s", file)
+}
+
+// Protected returns the files matching one of the globs. A glob matches a
+// file's path, its name, or one of its directories, so "migrations" covers
+// everything under any migrations directory.
+func Protected(files, globs []string) []string {
+	var matched []string
+	for _, file := range files {
+		if protected(file, globs) {
+			matched = append(matched, file)
+		}
+	}
+	return matched
+}
+
+func protected(file string, globs []string) bool {
+	for _, glob := range globs {
+		for p := file; p != "." && p != "/"; p = path.Dir(p) {
+			if ok, _ := path.Match(glob, p); ok {
+				return true
+			}
+			if ok, _ := path.Match(glob, path.Base(p)); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
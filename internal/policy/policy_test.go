@@ -0,0 +1,93 @@
+package policy
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestProtected(t *testing.T) {
+	files := []string{"main.go", ".github/workflows/ci.yml", "db/migrations/001.sql", "go.mod", "internal/auth/token.go"}
+	tests := []struct {
+		globs []string
+		want  []string
+	}{
+		{nil, nil},
+		{[]string{".github"}, []string{".github/workflows/ci.yml"}},
+		{[]string{"migrations", "go.mod"}, []string{"db/migrations/001.sql", "go.mod"}},
+		{[]string{"internal/auth/*"}, []string{"internal/auth/token.go"}},
+		{[]string{"*.sql"}, []string{"db/migrations/001.sql"}},
+	}
+
+	for _, tt := range tests {
+		got := Protected(files, tt.globs)
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("Protected(%v) = %v, want %v", tt.globs, got, tt.want)
+		}
+	}
+}
+
+func TestIdentify(t *testing.T) {
+	tokens := filepath.Join(t.TempDir(), "operators")
+	os.WriteFile(tokens, []byte("# on-call\nalice:"+HashToken("s3cret")+"\n"), 0600)
+
+	tests := []struct {
+		name       string
+		tokensFile string
+		token      string
+		operator   string
+		want       Operator
+		wantErr    bool
+	}{
+		{"name", "", "", "bob", Operator{"bob", SourceEnv}, false},
+		{"token", tokens, "s3cret", "bob", Operator{"alice", SourceToken}, false},
+		{"unknown token", tokens, "guess", "", Operator{}, true},
+		{"name without token", tokens, "", "bob", Operator{}, true},
+		{"token without file", "", "s3cret", "", Operator{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Setenv("BRIGADE_OPERATOR_TOKEN", tt.token)
+		t.Setenv("BRIGADE_OPERATOR", tt.operator)
+		got, err := (&Policy{TokensFile: tt.tokensFile}).Identify()
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("Identify() %s = %v, %v, want %v, error %v", tt.name, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestApprove(t *testing.T) {
+	t.Setenv("BRIGADE_OPERATOR_TOKEN", "")
+	t.Setenv("BRIGADE_OPERATOR", "bob")
+	log := filepath.Join(t.TempDir(), "brigade", "audit.jsonl")
+	p := &Policy{Required: []string{AcceptCost, ForceUnlock}, AuditLog: log}
+
+	if _, err := p.Approve(AcceptRisk, "prd.json", ""); err != nil {
+		t.Errorf("Approve(%s) = %v, want nil when not required", AcceptRisk, err)
+	}
+	if op, err := p.Approve(AcceptCost, "prd.json", "$12.00 over $10.00"); err != nil || op.Name != "bob" {
+		t.Errorf("Approve(%s) = %v, %v, want bob", AcceptCost, op, err)
+	}
+	p.Operators = []string{"alice"}
+	if _, err := p.Approve(ForceUnlock, "prd.json", ""); err == nil {
+		t.Errorf("Approve(%s) by an unlisted operator = nil, want error", ForceUnlock)
+	}
+
+	data, err := os.ReadFile(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("audit log has %d entries, want 1", len(lines))
+	}
+	var entry Entry
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatal(err)
+	}
+	if entry.Action != AcceptCost || entry.Name != "bob" || entry.Source != SourceEnv || entry.Detail != "$12.00 over $10.00" {
+		t.Errorf("audit entry = %+v", entry)
+	}
+}
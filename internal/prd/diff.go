@@ -0,0 +1,167 @@
+package prd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Diff is what changed between two versions of a PRD. Tasks are matched by
+// ID.
+type Diff struct {
+	Fields   []FieldChange `json:"fields,omitempty"`  // PRD-level fields
+	Added    []string      `json:"added,omitempty"`   // Task IDs only in the new PRD, in its order
+	Removed  []string      `json:"removed,omitempty"` // Task IDs only in the old PRD, in its order
+	Modified []TaskDiff    `json:"modified,omitempty"`
+}
+
+// FieldChange is a field whose value changed.
+type FieldChange struct {
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+// TaskDiff is what changed in a task present in both PRDs.
+type TaskDiff struct {
+	ID                  string        `json:"id"`
+	Fields              []FieldChange `json:"fields,omitempty"`
+	CriteriaAdded       []string      `json:"criteriaAdded,omitempty"`
+	CriteriaRemoved     []string      `json:"criteriaRemoved,omitempty"`
+	DepsAdded           []string      `json:"depsAdded,omitempty"`
+	DepsRemoved         []string      `json:"depsRemoved,omitempty"`
+	VerificationAdded   []string      `json:"verificationAdded,omitempty"`
+	VerificationRemoved []string      `json:"verificationRemoved,omitempty"`
+}
+
+// Empty reports whether the PRDs are the same.
+func (d *Diff) Empty() bool {
+	return len(d.Fields) == 0 && len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Modified) == 0
+}
+
+// empty reports whether the task is unchanged.
+func (d *TaskDiff) empty() bool {
+	return len(d.Fields) == 0 && len(d.CriteriaAdded) == 0 && len(d.CriteriaRemoved) == 0 &&
+		len(d.DepsAdded) == 0 && len(d.DepsRemoved) == 0 &&
+		len(d.VerificationAdded) == 0 && len(d.VerificationRemoved) == 0
+}
+
+// Compare returns the differences between an old and a new version of a
+// PRD.
+func Compare(before, after *PRD) *Diff {
+	d := &Diff{}
+	d.Fields = compareFields([][3]string{
+		{"featureName", before.FeatureName, after.FeatureName},
+		{"branchName", before.BranchName, after.BranchName},
+		{"description", before.Description, after.Description},
+		{"walkaway", strconv.FormatBool(before.Walkaway), strconv.FormatBool(after.Walkaway)},
+	})
+
+	for _, task := range after.Tasks {
+		if before.TaskByID(task.ID) == nil {
+			d.Added = append(d.Added, task.ID)
+		}
+	}
+	for i := range before.Tasks {
+		task := &before.Tasks[i]
+		changed := after.TaskByID(task.ID)
+		if changed == nil {
+			d.Removed = append(d.Removed, task.ID)
+			continue
+		}
+		if td := compareTasks(task, changed); !td.empty() {
+			d.Modified = append(d.Modified, td)
+		}
+	}
+	return d
+}
+
+// Summary describes the diff in a line, e.g. "1 added, 2 modified".
+func (d *Diff) Summary() string {
+	var parts []string
+	if len(d.Fields) > 0 {
+		names := make([]string, 0, len(d.Fields))
+		for _, f := range d.Fields {
+			names = append(names, f.Field)
+		}
+		parts = append(parts, strings.Join(names, ", ")+" changed")
+	}
+	for _, c := range []struct {
+		n     int
+		label string
+	}{{len(d.Added), "added"}, {len(d.Removed), "removed"}, {len(d.Modified), "modified"}} {
+		if c.n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", c.n, c.label))
+		}
+	}
+	if len(parts) == 0 {
+		return "no changes"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// compareTasks diffs two versions of a task.
+func compareTasks(before, after *Task) TaskDiff {
+	td := TaskDiff{ID: before.ID}
+	td.Fields = compareFields([][3]string{
+		{"title", before.Title, after.Title},
+		{"description", before.Description, after.Description},
+		{"complexity", string(before.Complexity), string(after.Complexity)},
+		{"passes", strconv.FormatBool(before.Passes), strconv.FormatBool(after.Passes)},
+		{"manualVerification", strconv.FormatBool(before.ManualVerification), strconv.FormatBool(after.ManualVerification)},
+		{"workspace", before.Workspace, after.Workspace},
+		{"priority", strconv.Itoa(before.Priority), strconv.Itoa(after.Priority)},
+		{"phase", strconv.Itoa(before.Phase), strconv.Itoa(after.Phase)},
+		{"tags", strings.Join(before.Tags, ", "), strings.Join(after.Tags, ", ")},
+		{"files", strings.Join(before.Files, ", "), strings.Join(after.Files, ", ")},
+		{"env", strings.Join(before.EnvList(), ", "), strings.Join(after.EnvList(), ", ")},
+		{"setup", before.Setup, after.Setup},
+		{"teardown", before.Teardown, after.Teardown},
+	})
+	td.CriteriaAdded, td.CriteriaRemoved = compareLists(before.AcceptanceCriteria, after.AcceptanceCriteria)
+	td.DepsAdded, td.DepsRemoved = compareLists(before.DependsOn, after.DependsOn)
+	td.VerificationAdded, td.VerificationRemoved = compareLists(verificationLines(before), verificationLines(after))
+	return td
+}
+
+// compareFields returns the {field, old, new} triples whose values differ.
+func compareFields(fields [][3]string) []FieldChange {
+	var changes []FieldChange
+	for _, f := range fields {
+		if f[1] != f[2] {
+			changes = append(changes, FieldChange{Field: f[0], Old: f[1], New: f[2]})
+		}
+	}
+	return changes
+}
+
+// compareLists returns the entries only in the new list and only in the
+// old one, each in its list's order.
+func compareLists(before, after []string) (added, removed []string) {
+	inBefore := toSet(before)
+	inAfter := toSet(after)
+	for _, s := range after {
+		if !inBefore[s] {
+			added = append(added, s)
+		}
+	}
+	for _, s := range before {
+		if !inAfter[s] {
+			removed = append(removed, s)
+		}
+	}
+	return added, removed
+}
+
+// verificationLines describes a task's verification commands for diffing.
+func verificationLines(task *Task) []string {
+	lines := make([]string, 0, len(task.Verification))
+	for _, v := range task.Verification {
+		line := v.Cmd
+		if v.Type != "" {
+			line = fmt.Sprintf("[%s] %s", v.Type, v.Cmd)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
@@ -0,0 +1,260 @@
+package prd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// GraphMetrics summarizes the shape of a PRD's dependency graph.
+type GraphMetrics struct {
+	Depth           int          `json:"depth"`           // Number of levels (longest dependency chain)
+	Width           int          `json:"width"`           // Most tasks runnable at the same level
+	Levels          [][]string   `json:"levels"`          // Task IDs grouped by earliest level they can run
+	CriticalPath    []string     `json:"criticalPath"`    // Heaviest dependency chain by task weight
+	CriticalWeight  float64      `json:"criticalWeight"`  // Total weight of the critical path
+	TotalWeight     float64      `json:"totalWeight"`     // Sum of all task weights
+	ParallelSpeedup float64      `json:"parallelSpeedup"` // TotalWeight / CriticalWeight (ideal, unlimited workers)
+	Bottlenecks     []Bottleneck `json:"bottlenecks"`     // Tasks gating the most downstream work
+}
+
+// Bottleneck is a task that many other tasks transitively depend on.
+type Bottleneck struct {
+	TaskID     string `json:"taskId"`
+	Downstream int    `json:"downstream"`
+}
+
+// Downstream returns all task IDs that transitively depend on taskID.
+func (p *PRD) Downstream(taskID string) []string {
+	graph := p.DependencyGraph()
+	seen := make(map[string]bool)
+	queue := []string{taskID}
+	var result []string
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, dependent := range graph[id] {
+			if !seen[dependent] {
+				seen[dependent] = true
+				result = append(result, dependent)
+				queue = append(queue, dependent)
+			}
+		}
+	}
+	return result
+}
+
+// BlockedBy maps each task that can't run until one of blockers completes
+// to the blockers it waits on, in the order given. Tasks that have passed
+// aren't waiting on anything.
+func (p *PRD) BlockedBy(blockers []string) map[string][]string {
+	blocked := make(map[string][]string)
+	for _, id := range blockers {
+		for _, dependent := range p.Downstream(id) {
+			if task := p.TaskByID(dependent); task != nil && !task.Passes {
+				blocked[dependent] = append(blocked[dependent], id)
+			}
+		}
+	}
+	return blocked
+}
+
+// AddDependency makes taskID depend on dep. Unknown task IDs, a task
+// depending on itself, and a dependency that would close a cycle are
+// errors, and leave the PRD unchanged.
+func (p *PRD) AddDependency(taskID, dep string) error {
+	task, err := p.lookupTask(taskID)
+	if err != nil {
+		return err
+	}
+	if _, err := p.lookupTask(dep); err != nil {
+		return err
+	}
+	if taskID == dep {
+		return fmt.Errorf("%s cannot depend on itself", taskID)
+	}
+	for _, existing := range task.DependsOn {
+		if existing == dep {
+			return fmt.Errorf("%s already depends on %s", taskID, dep)
+		}
+	}
+	if path := p.DependencyPath(dep, taskID); path != nil {
+		return fmt.Errorf("%s depending on %s would create a cycle: %s → %s", taskID, dep, taskID, strings.Join(path, " → "))
+	}
+	task.DependsOn = append(task.DependsOn, dep)
+	return nil
+}
+
+// RemoveDependency drops dep from taskID's dependencies.
+func (p *PRD) RemoveDependency(taskID, dep string) error {
+	task, err := p.lookupTask(taskID)
+	if err != nil {
+		return err
+	}
+	for i, existing := range task.DependsOn {
+		if existing == dep {
+			task.DependsOn = append(task.DependsOn[:i:i], task.DependsOn[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("%s does not depend on %s", taskID, dep)
+}
+
+// DependencyPath returns a chain of dependencies leading from one task to
+// another, starting with from and ending with to, or nil if from doesn't
+// depend on to, directly or indirectly.
+func (p *PRD) DependencyPath(from, to string) []string {
+	prev := map[string]string{from: ""}
+	queue := []string{from}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if id == to {
+			var path []string
+			for step := to; step != ""; step = prev[step] {
+				path = append([]string{step}, path...)
+			}
+			return path
+		}
+		task := p.TaskByID(id)
+		if task == nil {
+			continue
+		}
+		for _, dep := range task.DependsOn {
+			if _, seen := prev[dep]; !seen {
+				prev[dep] = id
+				queue = append(queue, dep)
+			}
+		}
+	}
+	return nil
+}
+
+// lookupTask returns the task with an ID, or an error suggesting the ID
+// that was probably meant.
+func (p *PRD) lookupTask(id string) (*Task, error) {
+	if task := p.TaskByID(id); task != nil {
+		return task, nil
+	}
+	if guess := p.closestTaskID(id); guess != "" {
+		return nil, fmt.Errorf("unknown task %s (did you mean %s?)", id, guess)
+	}
+	return nil, fmt.Errorf("unknown task %s", id)
+}
+
+// closestTaskID returns the task ID nearest to a mistyped one, or "" if
+// none is close.
+func (p *PRD) closestTaskID(id string) string {
+	best, bestDist := "", 3
+	for _, task := range p.Tasks {
+		if strings.EqualFold(task.ID, id) {
+			return task.ID
+		}
+		if d := editDistance(strings.ToUpper(task.ID), strings.ToUpper(id)); d < bestDist {
+			best, bestDist = task.ID, d
+		}
+	}
+	return best
+}
+
+// editDistance is the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+// AnalyzeGraph computes graph metrics. weight returns the expected cost of a
+// task (e.g. minutes); nil weighs every task equally. Returns nil if the
+// graph has a cycle.
+func (p *PRD) AnalyzeGraph(weight func(*Task) float64) *GraphMetrics {
+	order, err := p.TopologicalOrder()
+	if err != nil {
+		return nil
+	}
+	if weight == nil {
+		weight = func(*Task) float64 { return 1 }
+	}
+
+	m := &GraphMetrics{}
+	level := make(map[string]int)
+	finish := make(map[string]float64) // heaviest chain weight ending at task
+	prev := make(map[string]string)    // predecessor on that chain
+
+	for _, id := range order {
+		task := p.TaskByID(id)
+		w := weight(task)
+		m.TotalWeight += w
+
+		lvl := 0
+		best := 0.0
+		for _, dep := range task.DependsOn {
+			if level[dep]+1 > lvl {
+				lvl = level[dep] + 1
+			}
+			if finish[dep] > best {
+				best = finish[dep]
+				prev[id] = dep
+			}
+		}
+		level[id] = lvl
+		finish[id] = best + w
+
+		for len(m.Levels) <= lvl {
+			m.Levels = append(m.Levels, nil)
+		}
+		m.Levels[lvl] = append(m.Levels[lvl], id)
+	}
+
+	m.Depth = len(m.Levels)
+	for _, ids := range m.Levels {
+		if len(ids) > m.Width {
+			m.Width = len(ids)
+		}
+	}
+
+	// Walk back from the heaviest chain end
+	end := ""
+	for _, id := range order {
+		if end == "" || finish[id] > finish[end] {
+			end = id
+		}
+	}
+	for id := end; id != ""; id = prev[id] {
+		m.CriticalPath = append([]string{id}, m.CriticalPath...)
+	}
+	if end != "" {
+		m.CriticalWeight = finish[end]
+	}
+	if m.CriticalWeight > 0 {
+		m.ParallelSpeedup = m.TotalWeight / m.CriticalWeight
+	}
+
+	for _, task := range p.Tasks {
+		if n := len(p.Downstream(task.ID)); n > 0 {
+			m.Bottlenecks = append(m.Bottlenecks, Bottleneck{TaskID: task.ID, Downstream: n})
+		}
+	}
+	sort.SliceStable(m.Bottlenecks, func(i, j int) bool {
+		return m.Bottlenecks[i].Downstream > m.Bottlenecks[j].Downstream
+	})
+	if len(m.Bottlenecks) > 5 {
+		m.Bottlenecks = m.Bottlenecks[:5]
+	}
+
+	return m
+}
@@ -0,0 +1,136 @@
+package prd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Revision sources: what changed the PRD.
+const (
+	SourceEdit        = "edit"         // Changed outside brigade, e.g. by hand
+	SourcePlanner     = "planner"      // Written by `brigade plan`
+	SourceReplan      = "replan"       // Reworked by `brigade replan`
+	SourceGraph       = "graph"        // Dependency edit by `brigade graph`
+	SourceReview      = "phase-review" // Remediation tasks from a phase review
+	SourceHumanReview = "human-review" // Remediation task from `brigade review`
+)
+
+// Revision is one recorded version of a PRD. Revisions track the plan, not
+// progress: task passes are cleared in the stored copy and ignored when
+// deciding whether the PRD changed.
+type Revision struct {
+	Version   int    `json:"version"`
+	Timestamp string `json:"timestamp"`
+	Source    string `json:"source"`
+	Author    string `json:"author,omitempty"` // $USER of whoever recorded it
+	Summary   string `json:"summary"`
+	Hash      string `json:"hash"`
+	File      string `json:"file"` // The stored copy, relative to the history directory
+}
+
+// HistoryDir returns the directory holding this PRD's revisions.
+func (p *PRD) HistoryDir() string {
+	if p.path == "" {
+		return ""
+	}
+	return strings.TrimSuffix(p.path, ".json") + ".history"
+}
+
+// Revisions returns the PRD's recorded revisions, oldest first.
+func (p *PRD) Revisions() ([]Revision, error) {
+	if p.path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(filepath.Join(p.HistoryDir(), "revisions.json"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading PRD history: %w", err)
+	}
+	var revisions []Revision
+	if err := json.Unmarshal(data, &revisions); err != nil {
+		return nil, fmt.Errorf("parsing PRD history: %w", err)
+	}
+	return revisions, nil
+}
+
+// LoadRevision loads the stored copy of a revision.
+func (p *PRD) LoadRevision(rev Revision) (*PRD, error) {
+	return Load(filepath.Join(p.HistoryDir(), rev.File))
+}
+
+// ContentHash identifies the PRD's plan, ignoring which tasks have passed.
+func (p *PRD) ContentHash() string {
+	data, _ := json.Marshal(p.planOnly())
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])[:12]
+}
+
+// RecordRevision adds the PRD to its history if the plan changed since the
+// last revision, summarizing what changed. It returns the latest revision
+// and whether it was just recorded.
+func (p *PRD) RecordRevision(source string) (*Revision, bool, error) {
+	if p.path == "" {
+		return nil, false, fmt.Errorf("no path for PRD history")
+	}
+	revisions, err := p.Revisions()
+	if err != nil {
+		return nil, false, err
+	}
+	hash := p.ContentHash()
+	summary := "first recorded version"
+	if n := len(revisions); n > 0 {
+		last := revisions[n-1]
+		if last.Hash == hash {
+			return &last, false, nil
+		}
+		if prev, err := p.LoadRevision(last); err == nil {
+			summary = Compare(prev, p.planOnly()).Summary()
+		}
+	}
+
+	rev := Revision{
+		Version:   len(revisions) + 1,
+		Timestamp: time.Now().Format(time.RFC3339),
+		Source:    source,
+		Author:    os.Getenv("USER"),
+		Summary:   summary,
+		Hash:      hash,
+	}
+	rev.File = fmt.Sprintf("v%d.json", rev.Version)
+
+	dir := p.HistoryDir()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, false, fmt.Errorf("creating PRD history: %w", err)
+	}
+	plan := p.planOnly()
+	if err := plan.Save(filepath.Join(dir, rev.File)); err != nil {
+		return nil, false, err
+	}
+	data, err := json.MarshalIndent(append(revisions, rev), "", "  ")
+	if err != nil {
+		return nil, false, fmt.Errorf("marshaling PRD history: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "revisions.json"), data, 0644); err != nil {
+		return nil, false, fmt.Errorf("writing PRD history: %w", err)
+	}
+	return &rev, true, nil
+}
+
+// planOnly returns a copy of the PRD with every task pending.
+func (p *PRD) planOnly() *PRD {
+	plan := *p
+	plan.Tasks = append([]Task(nil), p.Tasks...)
+	for i := range plan.Tasks {
+		plan.Tasks[i].Passes = false
+	}
+	plan.path = ""
+	return &plan
+}
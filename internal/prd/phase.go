@@ -0,0 +1,76 @@
+package prd
+
+import "sort"
+
+// Phases returns the PRD's phase numbers in order, or nil if no task has a
+// phase. Tasks without a phase (0) belong to none and are never gated.
+func (p *PRD) Phases() []int {
+	seen := make(map[int]bool)
+	var phases []int
+	for _, task := range p.Tasks {
+		if task.Phase > 0 && !seen[task.Phase] {
+			seen[task.Phase] = true
+			phases = append(phases, task.Phase)
+		}
+	}
+	sort.Ints(phases)
+	return phases
+}
+
+// HasPhases reports whether any task has a phase.
+func (p *PRD) HasPhases() bool {
+	for _, task := range p.Tasks {
+		if task.Phase > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// PhaseProgress returns the completed and total task counts of a phase.
+func (p *PRD) PhaseProgress(phase int) (int, int) {
+	done, total := 0, 0
+	for _, task := range p.Tasks {
+		if task.Phase != phase {
+			continue
+		}
+		total++
+		if task.Passes {
+			done++
+		}
+	}
+	return done, total
+}
+
+// PhaseComplete reports whether every task of a phase has passed.
+func (p *PRD) PhaseComplete(phase int) bool {
+	done, total := p.PhaseProgress(phase)
+	return total > 0 && done == total
+}
+
+// CurrentPhase returns the earliest phase with unfinished tasks: the only
+// phase whose tasks can run. Returns 0 if the PRD has no phases or they are
+// all complete.
+func (p *PRD) CurrentPhase() int {
+	return p.openPhase(nil)
+}
+
+// openPhase returns the earliest phase with a task that hasn't passed and
+// isn't in completed, or 0 if there is none.
+func (p *PRD) openPhase(completed map[string]bool) int {
+	open := 0
+	for _, task := range p.Tasks {
+		if task.Phase <= 0 || task.Passes || completed[task.ID] {
+			continue
+		}
+		if open == 0 || task.Phase < open {
+			open = task.Phase
+		}
+	}
+	return open
+}
+
+// phaseGated reports whether a task has to wait for an earlier phase.
+func phaseGated(task *Task, open int) bool {
+	return task.Phase > 0 && open > 0 && task.Phase > open
+}
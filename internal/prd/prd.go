@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 )
 
@@ -26,12 +27,14 @@ const (
 	VerificationUnit        VerificationType = "unit"
 	VerificationIntegration VerificationType = "integration"
 	VerificationSmoke       VerificationType = "smoke"
+	VerificationBrowser     VerificationType = "browser" // Playwright or another E2E runner
 )
 
 // Verification represents a verification command for a task.
 type Verification struct {
-	Type VerificationType `json:"type,omitempty"`
-	Cmd  string           `json:"cmd"`
+	Type     VerificationType `json:"type,omitempty"`
+	Cmd      string           `json:"cmd"`
+	Criteria []int            `json:"criteria,omitempty"` // Acceptance criteria it checks (1-based)
 }
 
 // UnmarshalJSON handles both string and object formats for backward compatibility.
@@ -54,17 +57,38 @@ func (v *Verification) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// Service is a background process, such as a dev server, that runs while a
+// task is verified.
+type Service struct {
+	Cmd          string            `json:"cmd"`
+	Dir          string            `json:"dir,omitempty"`          // Working directory, relative to the repo root
+	Env          map[string]string `json:"env,omitempty"`          // Extra environment for the process
+	HealthURL    string            `json:"healthUrl,omitempty"`    // Ready once a GET gets a response below 500
+	HealthCmd    string            `json:"healthCmd,omitempty"`    // Ready once this exits 0
+	ReadyTimeout int               `json:"readyTimeout,omitempty"` // Seconds to wait for ready (default SERVICE_READY_TIMEOUT)
+}
+
 // Task represents a single task in a PRD.
 type Task struct {
-	ID                 string         `json:"id"`
-	Title              string         `json:"title"`
-	Description        string         `json:"description,omitempty"`
-	AcceptanceCriteria []string       `json:"acceptanceCriteria"`
-	DependsOn          []string       `json:"dependsOn"`
-	Complexity         Complexity     `json:"complexity"`
-	Passes             bool           `json:"passes"`
-	Verification       []Verification `json:"verification,omitempty"`
-	ManualVerification bool           `json:"manualVerification,omitempty"`
+	ID                 string            `json:"id"`
+	Title              string            `json:"title"`
+	Description        string            `json:"description,omitempty"`
+	AcceptanceCriteria []string          `json:"acceptanceCriteria"`
+	DependsOn          []string          `json:"dependsOn"`
+	Complexity         Complexity        `json:"complexity"`
+	Passes             bool              `json:"passes"`
+	Verification       []Verification    `json:"verification,omitempty"`
+	ManualVerification bool              `json:"manualVerification,omitempty"`
+	ManualChecks       map[string]string `json:"manualChecks,omitempty"` // Criterion number → how it is checked by hand
+	Workspace          string            `json:"workspace,omitempty"`    // Monorepo package the task is scoped to
+	Files              []string          `json:"files,omitempty"`        // Files or globs the task works on
+	Priority           int               `json:"priority,omitempty"`     // Higher runs first among ready tasks
+	Tags               []string          `json:"tags,omitempty"`         // Labels for --tags and filtered reports
+	Phase              int               `json:"phase,omitempty"`        // Runs after every earlier phase is complete
+	Env                map[string]string `json:"env,omitempty"`          // Extra environment for the worker, hooks and verification
+	Setup              string            `json:"setup,omitempty"`        // Command run before each attempt, e.g. starting services
+	Teardown           string            `json:"teardown,omitempty"`     // Command run after each attempt's verification
+	Services           []string          `json:"services,omitempty"`     // Services started for verification (default: all, for smoke verification)
 }
 
 // IsSenior returns true if the task should be handled by a senior worker.
@@ -72,6 +96,65 @@ func (t *Task) IsSenior() bool {
 	return t.Complexity == ComplexitySenior
 }
 
+// FileDirs returns the directories named by the task's file hints: the
+// part of each path before any glob, in order and without duplicates.
+func (t *Task) FileDirs() []string {
+	seen := make(map[string]bool)
+	var dirs []string
+	for _, f := range t.Files {
+		f = filepath.ToSlash(f)
+		if i := strings.IndexAny(f, "*?["); i >= 0 {
+			f = f[:i]
+			if !strings.HasSuffix(f, "/") {
+				f = filepath.ToSlash(filepath.Dir(f))
+			}
+		} else {
+			f = filepath.ToSlash(filepath.Dir(f))
+		}
+		dir := strings.TrimSuffix(f, "/")
+		if dir == "" {
+			dir = "."
+		}
+		if !seen[dir] {
+			seen[dir] = true
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// EnvList returns the task's env as KEY=VALUE pairs, sorted by key.
+func (t *Task) EnvList() []string {
+	env := make([]string, 0, len(t.Env))
+	for k, v := range t.Env {
+		env = append(env, k+"="+v)
+	}
+	sort.Strings(env)
+	return env
+}
+
+// ServicesNeeded returns the names of the services to run while the task
+// is verified: the ones it lists, or every service in available when it
+// has smoke or browser verification and lists none.
+func (t *Task) ServicesNeeded(available map[string]Service) []string {
+	if len(t.Services) > 0 {
+		return t.Services
+	}
+	smoke := false
+	for _, v := range t.Verification {
+		smoke = smoke || v.Type == VerificationSmoke || v.Type == VerificationBrowser
+	}
+	if !smoke {
+		return nil
+	}
+	names := make([]string, 0, len(available))
+	for name := range available {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 // IsJunior returns true if the task should be handled by a junior worker.
 func (t *Task) IsJunior() bool {
 	return t.Complexity == ComplexityJunior
@@ -84,8 +167,20 @@ type PRD struct {
 	CreatedAt   string `json:"createdAt,omitempty"`
 	Description string `json:"description,omitempty"`
 	Walkaway    bool   `json:"walkaway,omitempty"`
+	ParentPRD   string `json:"parentPrd,omitempty"` // Set on iteration PRDs
 	Tasks       []Task `json:"tasks"`
 
+	// DependsOnPRD names PRDs that must be complete before this one runs
+	DependsOnPRD []string `json:"dependsOnPRD,omitempty"`
+
+	// MCPServers are attached to every Claude worker on this PRD, in the
+	// Claude CLI's mcpServers format
+	MCPServers map[string]json.RawMessage `json:"mcpServers,omitempty"`
+
+	// Services are started around the verification of tasks that need
+	// them, such as a dev server for smoke tests
+	Services map[string]Service `json:"services,omitempty"`
+
 	// Internal tracking
 	path string
 }
@@ -152,6 +247,12 @@ func (p *PRD) Path() string {
 	return p.path
 }
 
+// SetPath sets the file path for the PRD, which names its state and other
+// files.
+func (p *PRD) SetPath(path string) {
+	p.path = path
+}
+
 // TaskByID returns the task with the given ID, or nil if not found.
 func (p *PRD) TaskByID(id string) *Task {
 	for i := range p.Tasks {
@@ -172,12 +273,15 @@ func (p *PRD) TaskIndex(id string) int {
 	return -1
 }
 
-// ReadyTasks returns tasks that are ready to be executed (dependencies met, not passed).
+// ReadyTasks returns tasks that are ready to be executed (dependencies met,
+// earlier phases complete, not passed), highest priority first and in PRD
+// order within a priority.
 func (p *PRD) ReadyTasks(completed map[string]bool) []*Task {
+	open := p.openPhase(completed)
 	var ready []*Task
 	for i := range p.Tasks {
 		task := &p.Tasks[i]
-		if task.Passes {
+		if task.Passes || phaseGated(task, open) {
 			continue
 		}
 
@@ -194,6 +298,9 @@ func (p *PRD) ReadyTasks(completed map[string]bool) []*Task {
 			ready = append(ready, task)
 		}
 	}
+	sort.SliceStable(ready, func(i, j int) bool {
+		return ready[i].Priority > ready[j].Priority
+	})
 	return ready
 }
 
@@ -258,6 +365,32 @@ func (p *PRD) StatePath() string {
 	return strings.TrimSuffix(p.path, ".json") + ".state.json"
 }
 
+// NudgesPath returns the path to the queue of nudges for this PRD's tasks.
+func (p *PRD) NudgesPath() string {
+	if p.path == "" {
+		return ""
+	}
+	return strings.TrimSuffix(p.path, ".json") + ".nudges.jsonl"
+}
+
+// ResultPath returns the path to the run result file for this PRD.
+func (p *PRD) ResultPath() string {
+	if p.path == "" {
+		return ""
+	}
+	return strings.TrimSuffix(p.path, ".json") + ".result.json"
+}
+
+// HandoffPath returns the path to the handoff summary a time-boxed
+// walkaway session leaves for the next person or session, or a completed
+// PRD leaves for the PRDs after it.
+func (p *PRD) HandoffPath() string {
+	if p.path == "" {
+		return ""
+	}
+	return strings.TrimSuffix(p.path, ".json") + ".handoff.md"
+}
+
 // DependencyGraph returns a map of task ID -> tasks that depend on it.
 func (p *PRD) DependencyGraph() map[string][]string {
 	graph := make(map[string][]string)
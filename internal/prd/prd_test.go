@@ -1,8 +1,11 @@
 package prd
 
 import (
+	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -172,6 +175,101 @@ func TestValidateQuick(t *testing.T) {
 	}
 }
 
+func TestValidateWorkspace(t *testing.T) {
+	tests := []struct {
+		workspace string
+		valid     bool
+	}{
+		{"services/api", true},
+		{"./packages/web/", true},
+		{"/srv/api", false},
+		{"../other-repo", false},
+		{"services/../../x", false},
+	}
+
+	for _, tt := range tests {
+		p := &PRD{
+			FeatureName: "Test",
+			BranchName:  "feature/test",
+			Tasks: []Task{
+				{ID: "US-001", Title: "Task", AcceptanceCriteria: []string{"Criterion"}, Complexity: ComplexityJunior, Workspace: tt.workspace},
+			},
+		}
+		if got := p.ValidateQuick().IsValid(); got != tt.valid {
+			t.Errorf("workspace %q: valid = %v, want %v", tt.workspace, got, tt.valid)
+		}
+	}
+}
+
+func TestValidateEnv(t *testing.T) {
+	tests := []struct {
+		name  string
+		valid bool
+	}{
+		{"DATABASE_URL", true},
+		{"_private", true},
+		{"1PASSWORD", false},
+		{"API-KEY", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		p := &PRD{
+			FeatureName: "Test",
+			BranchName:  "feature/test",
+			Tasks: []Task{
+				{ID: "US-001", Title: "Task", AcceptanceCriteria: []string{"Criterion"}, Complexity: ComplexityJunior, Env: map[string]string{tt.name: "x"}},
+			},
+		}
+		if got := p.ValidateQuick().IsValid(); got != tt.valid {
+			t.Errorf("env %q: valid = %v, want %v", tt.name, got, tt.valid)
+		}
+	}
+}
+
+func TestValidateMCPServers(t *testing.T) {
+	tests := []struct {
+		server string
+		valid  bool
+	}{
+		{`{"command": "npx", "args": ["-y", "@modelcontextprotocol/server-postgres"]}`, true},
+		{`{"type": "http", "url": "https://mcp.example.com"}`, true},
+		{`{"args": ["x"]}`, false},
+		{`"npx"`, false},
+	}
+
+	for _, tt := range tests {
+		p := &PRD{
+			FeatureName: "Test",
+			BranchName:  "feature/test",
+			Tasks: []Task{
+				{ID: "US-001", Title: "Task", AcceptanceCriteria: []string{"Criterion"}, Complexity: ComplexityJunior},
+			},
+			MCPServers: map[string]json.RawMessage{"db": json.RawMessage(tt.server)},
+		}
+		if got := p.ValidateQuick().IsValid(); got != tt.valid {
+			t.Errorf("server %s: valid = %v, want %v", tt.server, got, tt.valid)
+		}
+	}
+}
+
+func TestValidationResultErr(t *testing.T) {
+	result := &ValidationResult{}
+	if err := result.Err("prd.json"); err != nil {
+		t.Errorf("Err() = %v, want nil", err)
+	}
+
+	result.AddError("US-001", "title", "required")
+	err := result.Err("prd.json")
+	var invalid *InvalidError
+	if !errors.As(err, &invalid) {
+		t.Fatalf("Err() = %v, want *InvalidError", err)
+	}
+	if len(invalid.Errors) != 1 || invalid.Path != "prd.json" {
+		t.Errorf("InvalidError = %+v, want 1 error for prd.json", invalid)
+	}
+}
+
 func TestVerificationUnmarshal(t *testing.T) {
 	// Test string format
 	prdJSON := `{
@@ -205,3 +303,514 @@ func TestVerificationUnmarshal(t *testing.T) {
 		t.Errorf("unexpected verification cmd: %s", prd.Tasks[0].Verification[0].Cmd)
 	}
 }
+
+func TestAnalyzeGraph(t *testing.T) {
+	prd := &PRD{
+		Tasks: []Task{
+			{ID: "US-001"},
+			{ID: "US-002", DependsOn: []string{"US-001"}},
+			{ID: "US-003", DependsOn: []string{"US-001"}},
+			{ID: "US-004", DependsOn: []string{"US-002", "US-003"}},
+			{ID: "US-005"},
+		},
+	}
+
+	m := prd.AnalyzeGraph(nil)
+	if m == nil {
+		t.Fatal("AnalyzeGraph returned nil")
+	}
+	if m.Depth != 3 {
+		t.Errorf("Depth = %d, want 3", m.Depth)
+	}
+	if m.Width != 2 {
+		t.Errorf("Width = %d, want 2", m.Width)
+	}
+	if len(m.CriticalPath) != 3 || m.CriticalPath[0] != "US-001" || m.CriticalPath[2] != "US-004" {
+		t.Errorf("CriticalPath = %v, want US-001 -> ... -> US-004", m.CriticalPath)
+	}
+	if len(m.Bottlenecks) == 0 || m.Bottlenecks[0].TaskID != "US-001" || m.Bottlenecks[0].Downstream != 3 {
+		t.Errorf("Bottlenecks = %v, want US-001 gating 3 tasks", m.Bottlenecks)
+	}
+	if got := m.ParallelSpeedup; got < 1.66 || got > 1.67 {
+		t.Errorf("ParallelSpeedup = %.2f, want 1.67", got)
+	}
+
+	prd.Tasks[0].DependsOn = []string{"US-004"}
+	if prd.AnalyzeGraph(nil) != nil {
+		t.Error("expected nil metrics for cyclic graph")
+	}
+}
+
+func TestBlockedBy(t *testing.T) {
+	p := &PRD{
+		Tasks: []Task{
+			{ID: "US-001"},
+			{ID: "US-002", DependsOn: []string{"US-001"}},
+			{ID: "US-003", DependsOn: []string{"US-002"}},
+			{ID: "US-004", DependsOn: []string{"US-001"}, Passes: true},
+			{ID: "US-005", DependsOn: []string{"US-003", "US-006"}},
+			{ID: "US-006"},
+		},
+	}
+
+	blocked := p.BlockedBy([]string{"US-001", "US-006"})
+	tests := []struct {
+		id   string
+		want string
+	}{
+		{"US-002", "US-001"},
+		{"US-003", "US-001"},
+		{"US-004", ""}, // Already passed
+		{"US-005", "US-001,US-006"},
+		{"US-006", ""},
+	}
+	for _, tt := range tests {
+		if got := strings.Join(blocked[tt.id], ","); got != tt.want {
+			t.Errorf("BlockedBy()[%s] = %q, want %q", tt.id, got, tt.want)
+		}
+	}
+}
+
+func TestAddDependency(t *testing.T) {
+	newPRD := func() *PRD {
+		return &PRD{
+			Tasks: []Task{
+				{ID: "US-001"},
+				{ID: "US-002", DependsOn: []string{"US-001"}},
+				{ID: "US-003", DependsOn: []string{"US-002"}},
+			},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		task    string
+		dep     string
+		wantErr string
+	}{
+		{"new dependency", "US-003", "US-001", ""},
+		{"cycle", "US-001", "US-003", "cycle: US-001 → US-003 → US-002 → US-001"},
+		{"self", "US-002", "US-002", "itself"},
+		{"duplicate", "US-002", "US-001", "already depends"},
+		{"typo", "US-003", "us-001", "did you mean US-001?"},
+		{"unknown", "US-009", "US-001", "unknown task US-009"},
+	}
+	for _, tt := range tests {
+		p := newPRD()
+		err := p.AddDependency(tt.task, tt.dep)
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("%s: AddDependency() error = %v", tt.name, err)
+			} else if deps := p.TaskByID(tt.task).DependsOn; deps[len(deps)-1] != tt.dep {
+				t.Errorf("%s: DependsOn = %v, want %s added", tt.name, deps, tt.dep)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: AddDependency() error = %v, want %q", tt.name, err, tt.wantErr)
+		}
+		if p.HasCircularDependency() {
+			t.Errorf("%s: PRD changed by a rejected dependency", tt.name)
+		}
+	}
+
+	p := newPRD()
+	if err := p.RemoveDependency("US-003", "US-002"); err != nil || len(p.TaskByID("US-003").DependsOn) != 0 {
+		t.Errorf("RemoveDependency() = %v, DependsOn = %v", err, p.TaskByID("US-003").DependsOn)
+	}
+	if err := p.RemoveDependency("US-003", "US-002"); err == nil {
+		t.Error("RemoveDependency() of a missing dependency should fail")
+	}
+}
+
+func TestCompare(t *testing.T) {
+	old := &PRD{
+		FeatureName: "Auth",
+		Tasks: []Task{
+			{ID: "US-001", Title: "Model", AcceptanceCriteria: []string{"a", "b"}, Complexity: ComplexityJunior},
+			{ID: "US-002", Title: "API", DependsOn: []string{"US-001"}},
+			{ID: "US-003", Title: "Docs"},
+		},
+	}
+	updated := &PRD{
+		FeatureName: "Auth v2",
+		Tasks: []Task{
+			{ID: "US-001", Title: "Model", AcceptanceCriteria: []string{"b", "c"}, Complexity: ComplexitySenior},
+			{ID: "US-002", Title: "API", DependsOn: []string{"US-004"}, Verification: []Verification{{Cmd: "go test ./..."}}},
+			{ID: "US-004", Title: "Schema"},
+		},
+	}
+
+	d := Compare(old, updated)
+	if len(d.Fields) != 1 || d.Fields[0] != (FieldChange{"featureName", "Auth", "Auth v2"}) {
+		t.Errorf("Fields = %v, want featureName change", d.Fields)
+	}
+	if strings.Join(d.Added, ",") != "US-004" || strings.Join(d.Removed, ",") != "US-003" {
+		t.Errorf("Added = %v, Removed = %v, want [US-004] and [US-003]", d.Added, d.Removed)
+	}
+	if len(d.Modified) != 2 {
+		t.Fatalf("Modified = %v, want US-001 and US-002", d.Modified)
+	}
+	m := d.Modified[0]
+	if len(m.Fields) != 1 || m.Fields[0].Field != "complexity" || strings.Join(m.CriteriaAdded, ",") != "c" || strings.Join(m.CriteriaRemoved, ",") != "a" {
+		t.Errorf("Modified[US-001] = %+v", m)
+	}
+	m = d.Modified[1]
+	if strings.Join(m.DepsAdded, ",") != "US-004" || strings.Join(m.DepsRemoved, ",") != "US-001" || len(m.VerificationAdded) != 1 {
+		t.Errorf("Modified[US-002] = %+v", m)
+	}
+
+	if !Compare(old, old).Empty() {
+		t.Error("Compare() of a PRD with itself should be empty")
+	}
+}
+
+func TestReplan(t *testing.T) {
+	current := &PRD{
+		FeatureName: "Auth",
+		BranchName:  "feature/auth",
+		Tasks: []Task{
+			{ID: "US-001", Title: "Model", Passes: true},
+			{ID: "US-002", Title: "API", DependsOn: []string{"US-001"}},
+			{ID: "US-003", Title: "Docs", Passes: true},
+		},
+		path: "brigade/tasks/prd-auth.json",
+	}
+	output := `Here is the plan.
+<prd>
+{"featureName": "Auth", "branchName": "feature/auth-v2", "tasks": [
+  {"id": "US-001", "title": "Model with roles", "passes": true},
+  {"id": "US-002", "title": "API", "dependsOn": ["US-001", "US-004"], "passes": true},
+  {"id": "US-004", "title": "Roles"}
+]}
+</prd>`
+
+	proposed, err := ParseReplan(output)
+	if err != nil {
+		t.Fatalf("ParseReplan() error = %v", err)
+	}
+	merged, notes := Replan(current, proposed, map[string]bool{"US-001": true, "US-003": true})
+
+	if got := strings.Join(merged.AllTaskIDs(), ","); got != "US-001,US-002,US-003,US-004" {
+		t.Errorf("tasks = %s, want US-001,US-002,US-003,US-004", got)
+	}
+	if task := merged.TaskByID("US-001"); task.Title != "Model" || !task.Passes {
+		t.Errorf("US-001 = %+v, want the completed original", task)
+	}
+	if merged.TaskByID("US-002").Passes {
+		t.Error("US-002 should be pending")
+	}
+	if merged.BranchName != "feature/auth" || merged.Path() != current.Path() {
+		t.Errorf("branch = %s, path = %s, want the current ones", merged.BranchName, merged.Path())
+	}
+	if len(notes) != 3 {
+		t.Errorf("notes = %v, want branch, US-001 and US-003", notes)
+	}
+
+	if _, err := ParseReplan("no changes needed"); err == nil {
+		t.Error("ParseReplan() without a <prd> block should fail")
+	}
+}
+
+func TestRecordRevision(t *testing.T) {
+	p := &PRD{
+		FeatureName: "Auth",
+		Tasks:       []Task{{ID: "US-001", Title: "Model"}},
+	}
+	if err := p.Save(filepath.Join(t.TempDir(), "prd-auth.json")); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	rev, recorded, err := p.RecordRevision(SourcePlanner)
+	if err != nil || !recorded || rev.Version != 1 {
+		t.Fatalf("RecordRevision() = %+v, %v, %v, want v1 recorded", rev, recorded, err)
+	}
+
+	// Completing a task isn't a change to the plan
+	p.MarkTaskComplete("US-001")
+	if _, recorded, _ := p.RecordRevision(SourceEdit); recorded {
+		t.Error("RecordRevision() recorded a revision for a task passing")
+	}
+
+	p.Tasks = append(p.Tasks, Task{ID: "US-002", Title: "API"})
+	rev, recorded, err = p.RecordRevision(SourceReplan)
+	if err != nil || !recorded || rev.Version != 2 || rev.Summary != "1 added" {
+		t.Errorf("RecordRevision() = %+v, %v, %v, want v2 with 1 added", rev, recorded, err)
+	}
+
+	revisions, err := p.Revisions()
+	if err != nil || len(revisions) != 2 || revisions[0].Source != SourcePlanner {
+		t.Fatalf("Revisions() = %+v, %v", revisions, err)
+	}
+	first, err := p.LoadRevision(revisions[0])
+	if err != nil || len(first.Tasks) != 1 || first.Tasks[0].Passes {
+		t.Errorf("LoadRevision(v1) = %+v, %v, want the one pending task", first, err)
+	}
+}
+
+func TestSuggestVerificationTargetsFiles(t *testing.T) {
+	task := &Task{
+		Title: "Add session refresh",
+		Files: []string{"internal/auth/*.go", "internal/session/refresh.go", "internal/auth/jwt.go"},
+	}
+	if got := strings.Join(task.FileDirs(), ","); got != "internal/auth,internal/session" {
+		t.Errorf("FileDirs() = %s, want internal/auth,internal/session", got)
+	}
+
+	tests := []struct {
+		stack string
+		want  string
+	}{
+		{"go", "go test ./internal/auth/... ./internal/session/..."},
+		{"python", "pytest internal/auth internal/session"},
+		{"node", "npm test -- internal/auth internal/session"},
+	}
+	for _, tt := range tests {
+		suggestions := SuggestVerification(task, tt.stack)
+		if len(suggestions) != 2 || suggestions[0].Cmd != tt.want {
+			t.Errorf("%s: SuggestVerification() = %+v, want %q first", tt.stack, suggestions, tt.want)
+			continue
+		}
+		if want := "test -f internal/session/refresh.go && test -f internal/auth/jwt.go"; suggestions[1].Cmd != want {
+			t.Errorf("%s: pattern check = %q, want %q", tt.stack, suggestions[1].Cmd, want)
+		}
+	}
+}
+
+func TestDetectProjectStack(t *testing.T) {
+	tests := []struct {
+		files    []string
+		stack    string
+		testCmd  string
+		buildCmd string
+	}{
+		{nil, "unknown", "", ""},
+		{[]string{"go.mod", "package.json"}, "go", "go test ./...", "go build ./..."},
+		{[]string{"package.json"}, "node", "npm test", "npm run build --if-present"},
+		{[]string{"requirements.txt"}, "python", "pytest", ""},
+		{[]string{"pom.xml"}, "java", "mvn -q test", "mvn -q compile"},
+		{[]string{"build.gradle.kts"}, "java", "./gradlew test", "./gradlew assemble"},
+	}
+
+	for _, tt := range tests {
+		dir := t.TempDir()
+		for _, f := range tt.files {
+			os.WriteFile(filepath.Join(dir, f), nil, 0644)
+		}
+		stack := DetectProjectStack(dir)
+		testCmd, buildCmd := StackCommands(dir, stack)
+		if stack != tt.stack || testCmd != tt.testCmd || buildCmd != tt.buildCmd {
+			t.Errorf("%v: stack %q, commands %q, %q, want %q, %q, %q", tt.files, stack, testCmd, buildCmd, tt.stack, tt.testCmd, tt.buildCmd)
+		}
+	}
+}
+
+func TestTaskTrace(t *testing.T) {
+	task := &Task{
+		ID: "US-001",
+		AcceptanceCriteria: []string{
+			"POST /login returns a session token",
+			"Passwords are hashed with bcrypt",
+			"The login page shows a spinner while waiting",
+			"Failed attempts are rate limited",
+		},
+		Verification: []Verification{
+			{Cmd: "go test ./auth -run TestLogin"},
+			{Cmd: "go test ./auth -run TestHash", Criteria: []int{2}},
+		},
+		ManualChecks: map[string]string{"3": "watch the spinner in the browser"},
+	}
+
+	traces := task.Trace()
+	tests := []struct {
+		index    int
+		commands int
+		manual   bool
+		inferred bool
+	}{
+		{1, 1, false, true},  // "login" matches TestLogin
+		{2, 1, false, false}, // declared by criteria
+		{3, 1, true, true},   // manual check, plus "login" in the text
+		{4, 0, false, false}, // nothing covers it
+	}
+	for _, tt := range tests {
+		got := traces[tt.index-1]
+		if len(got.Commands) != tt.commands || (got.Manual != "") != tt.manual || got.Inferred != tt.inferred {
+			t.Errorf("criterion %d = %d commands, manual %q, inferred %v; want %d, %v, %v",
+				tt.index, len(got.Commands), got.Manual, got.Inferred, tt.commands, tt.manual, tt.inferred)
+		}
+	}
+	if traces[3].Covered() {
+		t.Error("criterion 4 should be uncovered")
+	}
+
+	p := &PRD{Tasks: []Task{*task}}
+	result := &ValidationResult{}
+	p.checkTraceability(result)
+	if len(result.Warnings) != 1 || result.Warnings[0].Field != "acceptanceCriteria[3]" {
+		t.Errorf("checkTraceability() warnings = %v, want one for acceptanceCriteria[3]", result.Warnings)
+	}
+}
+
+func TestReadyTasksPriority(t *testing.T) {
+	p := &PRD{
+		Tasks: []Task{
+			{ID: "US-001"},
+			{ID: "US-002", Priority: 5},
+			{ID: "US-003", Priority: -1},
+			{ID: "US-004", Priority: 5},
+			{ID: "US-005", DependsOn: []string{"US-003"}, Priority: 9},
+		},
+	}
+
+	var got []string
+	for _, task := range p.ReadyTasks(map[string]bool{}) {
+		got = append(got, task.ID)
+	}
+	want := []string{"US-002", "US-004", "US-001", "US-003"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("ReadyTasks() = %v, want %v", got, want)
+	}
+}
+
+func TestReadyTasksPhases(t *testing.T) {
+	p := &PRD{
+		Tasks: []Task{
+			{ID: "US-001", Phase: 1},
+			{ID: "US-002", Phase: 2},
+			{ID: "US-003", Phase: 1, Passes: true},
+			{ID: "US-004"},
+			{ID: "US-005", Phase: 3},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		completed map[string]bool
+		want      []string
+	}{
+		{"first phase open", map[string]bool{"US-003": true}, []string{"US-001", "US-004"}},
+		{"phase 1 done", map[string]bool{"US-001": true, "US-003": true}, []string{"US-001", "US-002", "US-004"}}, // US-001 itself isn't marked passed
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, task := range p.ReadyTasks(tt.completed) {
+			got = append(got, task.ID)
+		}
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("%s: ReadyTasks() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	if got := p.CurrentPhase(); got != 1 {
+		t.Errorf("CurrentPhase() = %d, want 1", got)
+	}
+	if done, total := p.PhaseProgress(1); done != 1 || total != 2 {
+		t.Errorf("PhaseProgress(1) = %d/%d, want 1/2", done, total)
+	}
+	if got := p.Phases(); len(got) != 3 || got[0] != 1 || got[2] != 3 {
+		t.Errorf("Phases() = %v, want [1 2 3]", got)
+	}
+}
+
+func TestSelect(t *testing.T) {
+	p := &PRD{
+		Tasks: []Task{
+			{ID: "US-001", Tags: []string{"backend"}},
+			{ID: "US-002", Tags: []string{"frontend"}},
+			{ID: "US-003", Tags: []string{"Backend", "db"}},
+			{ID: "US-004"},
+		},
+	}
+
+	tests := []struct {
+		name string
+		sel  Selection
+		want []string // nil: every task
+	}{
+		{"everything", Selection{}, nil},
+		{"tags", Selection{Tags: []string{"backend"}}, []string{"US-001", "US-003"}},
+		{"tags or", Selection{Tags: []string{"db", "frontend"}}, []string{"US-002", "US-003"}},
+		{"tags and skip", Selection{Tags: []string{"backend"}, Skip: []string{"US-001"}}, []string{"US-003"}},
+		{"from until", Selection{From: "US-002", Until: "US-003"}, []string{"US-002", "US-003"}},
+		{"only", Selection{Only: []string{"US-004", "US-001"}}, []string{"US-001", "US-004"}},
+		{"only and tags", Selection{Only: []string{"US-002", "US-003"}, Tags: []string{"backend"}}, []string{"US-003"}},
+	}
+
+	for _, tt := range tests {
+		included, err := p.Select(tt.sel)
+		if err != nil {
+			t.Fatalf("%s: Select() error = %v", tt.name, err)
+		}
+		if tt.want == nil {
+			if included != nil {
+				t.Errorf("%s: Select() = %v, want every task", tt.name, included)
+			}
+			continue
+		}
+		var got []string
+		for _, task := range p.Tasks {
+			if included[task.ID] {
+				got = append(got, task.ID)
+			}
+		}
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("%s: Select() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	if _, err := p.Select(Selection{Only: []string{"US-999"}}); err == nil {
+		t.Error("Select() with unknown task: expected error")
+	}
+}
+
+func TestOrderByPrerequisites(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, deps ...string) string {
+		path := filepath.Join(dir, name+".json")
+		data, _ := json.Marshal(PRD{FeatureName: name, BranchName: name, DependsOnPRD: deps, Tasks: []Task{{ID: "US-001"}}})
+		os.WriteFile(path, data, 0644)
+		return path
+	}
+	auth := write("prd-auth")
+	billing := write("prd-billing", "auth")
+	reports := write("prd-reports", "prd-billing.json", "prd-auth")
+
+	got, err := OrderByPrerequisites([]string{reports, billing, auth})
+	if err != nil {
+		t.Fatalf("OrderByPrerequisites() error = %v", err)
+	}
+	want := []string{auth, billing, reports}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("OrderByPrerequisites() = %v, want %v", got, want)
+	}
+
+	write("prd-auth", "reports")
+	if _, err := OrderByPrerequisites([]string{auth, reports}); err == nil {
+		t.Error("OrderByPrerequisites() with a cycle: expected error")
+	}
+}
+
+func TestValidatePrerequisites(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "prd-auth.json"), []byte(`{}`), 0644)
+
+	tests := []struct {
+		deps []string
+		want int
+	}{
+		{[]string{"auth"}, 0},
+		{[]string{"prd-auth", "prd-auth.json"}, 0},
+		{[]string{"payments"}, 1},
+		{[]string{"prd-billing"}, 1},
+	}
+
+	for _, tt := range tests {
+		p := &PRD{DependsOnPRD: tt.deps, path: filepath.Join(dir, "prd-billing.json")}
+		result := &ValidationResult{}
+		p.validatePrerequisites(result)
+		if len(result.Errors) != tt.want {
+			t.Errorf("validatePrerequisites(%v) = %v, want %d errors", tt.deps, result.Errors, tt.want)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package prd
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+)
+
+var replanPattern = regexp.MustCompile(`(?s)<prd>\s*(.*?)\s*</prd>`)
+
+// ParseReplan extracts the updated PRD from an executive's replan response,
+// which wraps the full PRD JSON in <prd>...</prd>.
+func ParseReplan(output string) (*PRD, error) {
+	m := replanPattern.FindStringSubmatch(output)
+	if m == nil {
+		return nil, fmt.Errorf("no <prd> block in response")
+	}
+	var p PRD
+	if err := json.Unmarshal([]byte(m[1]), &p); err != nil {
+		return nil, fmt.Errorf("parsing PRD JSON: %w", err)
+	}
+	return &p, nil
+}
+
+// Replan folds a proposed rewrite of a PRD onto the current one so the
+// result still lines up with the run's state: completed tasks are kept
+// exactly as they were (restored if the rewrite dropped or changed them),
+// every other task is left pending, and the branch and file path don't
+// change. It returns the merged PRD and a note for each correction made.
+func Replan(current, proposed *PRD, completed map[string]bool) (*PRD, []string) {
+	merged := *proposed
+	merged.Tasks = append([]Task(nil), proposed.Tasks...)
+	merged.path = current.path
+	merged.CreatedAt = current.CreatedAt
+	merged.ParentPRD = current.ParentPRD
+	if merged.MCPServers == nil {
+		merged.MCPServers = current.MCPServers
+	}
+
+	var notes []string
+	if merged.BranchName != current.BranchName {
+		notes = append(notes, fmt.Sprintf("kept branch %s (rewrite had %q)", current.BranchName, merged.BranchName))
+		merged.BranchName = current.BranchName
+	}
+
+	for i := range merged.Tasks {
+		task := &merged.Tasks[i]
+		if !completed[task.ID] {
+			task.Passes = false
+			continue
+		}
+		original := current.TaskByID(task.ID)
+		if original != nil {
+			if td := compareTasks(original, task); !td.empty() {
+				notes = append(notes, fmt.Sprintf("kept completed task %s unchanged", task.ID))
+				*task = *original
+			}
+		}
+	}
+
+	for i, task := range current.Tasks {
+		if !completed[task.ID] || merged.TaskByID(task.ID) != nil {
+			continue
+		}
+		notes = append(notes, fmt.Sprintf("restored completed task %s", task.ID))
+		at := min(i, len(merged.Tasks))
+		merged.Tasks = append(merged.Tasks[:at], append([]Task{task}, merged.Tasks[at:]...)...)
+	}
+	return &merged, notes
+}
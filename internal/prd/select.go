@@ -0,0 +1,80 @@
+package prd
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Selection narrows a run to some of a PRD's tasks: the --only, --skip,
+// --from, --until and --tags flags.
+type Selection struct {
+	Only  []string
+	Skip  []string
+	From  string // Inclusive, in PRD order
+	Until string // Inclusive, in PRD order
+	Tags  []string
+}
+
+// IsZero reports whether the selection includes every task.
+func (s Selection) IsZero() bool {
+	return len(s.Only) == 0 && len(s.Skip) == 0 && s.From == "" && s.Until == "" && len(s.Tags) == 0
+}
+
+// HasTag reports whether the task carries any of tags, ignoring case.
+func (t *Task) HasTag(tags ...string) bool {
+	for _, want := range tags {
+		for _, tag := range t.Tags {
+			if strings.EqualFold(tag, want) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Select returns the IDs of the tasks a selection includes, or nil if it
+// includes every task. A task must pass every filter given: listed by
+// --only, within --from/--until, tagged with one of --tags, and not
+// skipped. Task IDs that aren't in the PRD are an error.
+func (p *PRD) Select(s Selection) (map[string]bool, error) {
+	if s.IsZero() {
+		return nil, nil
+	}
+	for _, id := range append(append([]string{s.From, s.Until}, s.Only...), s.Skip...) {
+		if id != "" && p.TaskByID(id) == nil {
+			return nil, fmt.Errorf("unknown task %s", id)
+		}
+	}
+
+	from, until := 0, len(p.Tasks)-1
+	if s.From != "" {
+		from = p.TaskIndex(s.From)
+	}
+	if s.Until != "" {
+		until = p.TaskIndex(s.Until)
+	}
+	only := toSet(s.Only)
+	skip := toSet(s.Skip)
+
+	included := make(map[string]bool)
+	for i := range p.Tasks {
+		task := &p.Tasks[i]
+		switch {
+		case i < from || i > until:
+		case len(only) > 0 && !only[task.ID]:
+		case len(s.Tags) > 0 && !task.HasTag(s.Tags...):
+		case skip[task.ID]:
+		default:
+			included[task.ID] = true
+		}
+	}
+	return included, nil
+}
+
+func toSet(ids []string) map[string]bool {
+	set := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		set[id] = true
+	}
+	return set
+}
@@ -0,0 +1,95 @@
+package prd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// PrerequisitePath resolves a dependsOnPRD entry to a PRD file. Entries
+// are paths ("prd-auth.json") or names ("prd-auth" or "auth") of PRDs in
+// the same directory as this one.
+func (p *PRD) PrerequisitePath(name string) string {
+	dir := filepath.Dir(p.path)
+	if strings.HasSuffix(name, ".json") {
+		if filepath.IsAbs(name) {
+			return name
+		}
+		return filepath.Join(dir, name)
+	}
+	candidates := []string{filepath.Join(dir, name+".json")}
+	if !strings.HasPrefix(name, "prd-") {
+		candidates = append(candidates, filepath.Join(dir, "prd-"+name+".json"))
+	}
+	for _, c := range candidates {
+		if _, err := os.Stat(c); err == nil {
+			return c
+		}
+	}
+	return candidates[0]
+}
+
+// validatePrerequisites checks that each dependsOnPRD entry names another
+// PRD that exists.
+func (p *PRD) validatePrerequisites(result *ValidationResult) {
+	for _, name := range p.DependsOnPRD {
+		path := p.PrerequisitePath(name)
+		if p.path != "" && filepath.Clean(path) == filepath.Clean(p.path) {
+			result.AddError("", "dependsOnPRD", "a PRD can't depend on itself")
+		} else if _, err := os.Stat(path); err != nil {
+			result.AddError("", "dependsOnPRD", fmt.Sprintf("PRD %q not found", name))
+		}
+	}
+}
+
+// OrderByPrerequisites orders PRD files so each comes after the PRDs it
+// depends on, keeping the given order otherwise. Prerequisites that aren't
+// in paths don't affect the order.
+func OrderByPrerequisites(paths []string) ([]string, error) {
+	deps := make(map[string][]string)
+	index := make(map[string]string)
+	for _, path := range paths {
+		index[filepath.Clean(path)] = path
+	}
+	for _, path := range paths {
+		p, err := Load(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range p.DependsOnPRD {
+			if dep, ok := index[filepath.Clean(p.PrerequisitePath(name))]; ok {
+				deps[path] = append(deps[path], dep)
+			}
+		}
+	}
+
+	var ordered []string
+	done := make(map[string]bool)
+	visiting := make(map[string]bool)
+	var visit func(path string) error
+	visit = func(path string) error {
+		if done[path] {
+			return nil
+		}
+		if visiting[path] {
+			return fmt.Errorf("circular dependsOnPRD involving %s", path)
+		}
+		visiting[path] = true
+		for _, dep := range deps[path] {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		visiting[path] = false
+		done[path] = true
+		ordered = append(ordered, path)
+		return nil
+	}
+	for _, path := range paths {
+		if err := visit(path); err != nil {
+			return nil, err
+		}
+	}
+	return ordered, nil
+}
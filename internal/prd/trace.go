@@ -0,0 +1,121 @@
+package prd
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// CriterionTrace links one acceptance criterion to what verifies it.
+type CriterionTrace struct {
+	Index     int      `json:"index"` // 1-based, as shown to workers
+	Criterion string   `json:"criterion"`
+	Commands  []string `json:"commands,omitempty"` // Verification commands that cover it
+	Manual    string   `json:"manual,omitempty"`   // Manual check note that covers it
+	Inferred  bool     `json:"inferred,omitempty"` // Commands matched by keyword, not declared
+}
+
+// Covered reports whether anything verifies the criterion.
+func (c *CriterionTrace) Covered() bool {
+	return len(c.Commands) > 0 || c.Manual != ""
+}
+
+// Trace maps each of a task's acceptance criteria to the verification
+// commands or manual checks that cover it. Commands that list criteria
+// cover exactly those; commands that don't are matched by keywords shared
+// with the criterion. A task-wide manualVerification covers everything.
+func (t *Task) Trace() []CriterionTrace {
+	traces := make([]CriterionTrace, len(t.AcceptanceCriteria))
+	for i, criterion := range t.AcceptanceCriteria {
+		traces[i] = CriterionTrace{Index: i + 1, Criterion: criterion}
+		if note, ok := t.ManualChecks[strconv.Itoa(i+1)]; ok {
+			traces[i].Manual = note
+		} else if t.ManualVerification {
+			traces[i].Manual = "task is verified manually"
+		}
+	}
+
+	for _, v := range t.Verification {
+		if len(v.Criteria) > 0 {
+			for _, n := range v.Criteria {
+				if n >= 1 && n <= len(traces) {
+					traces[n-1].Commands = append(traces[n-1].Commands, v.Cmd)
+				}
+			}
+			continue
+		}
+		cmd := strings.ToLower(v.Cmd)
+		for i := range traces {
+			for _, word := range criterionKeywords(traces[i].Criterion) {
+				if strings.Contains(cmd, word) {
+					traces[i].Commands = append(traces[i].Commands, v.Cmd)
+					traces[i].Inferred = true
+					break
+				}
+			}
+		}
+	}
+	return traces
+}
+
+var (
+	wordPattern  = regexp.MustCompile(`[A-Za-z][A-Za-z0-9_]*`)
+	camelPattern = regexp.MustCompile(`[a-z0-9][A-Z]`)
+)
+
+// traceStopwords are common criterion words too generic to link a command.
+var traceStopwords = map[string]bool{
+	"able": true, "after": true, "also": true, "when": true, "with": true,
+	"that": true, "this": true, "from": true, "into": true, "have": true,
+	"must": true, "will": true, "should": true, "returns": true, "return": true,
+	"each": true, "every": true, "valid": true, "invalid": true, "error": true,
+	"errors": true, "user": true, "users": true, "works": true, "correctly": true,
+	"test": true, "tests": true, "pass": true, "passes": true, "file": true,
+	"true": true, "false": true, "than": true, "them": true, "they": true,
+	"new": true, "uses": true, "used": true, "exists": true, "exist": true,
+}
+
+// criterionKeywords extracts the distinctive words of a criterion, splitting
+// identifiers at camelCase and snake_case boundaries as well as keeping
+// them whole.
+func criterionKeywords(criterion string) []string {
+	seen := make(map[string]bool)
+	var words []string
+	add := func(w string) {
+		w = strings.ToLower(w)
+		if len(w) >= 4 && !traceStopwords[w] && !seen[w] {
+			seen[w] = true
+			words = append(words, w)
+		}
+	}
+	for _, w := range wordPattern.FindAllString(criterion, -1) {
+		add(w)
+		split := camelPattern.ReplaceAllStringFunc(w, func(s string) string { return s[:1] + "_" + s[1:] })
+		if parts := strings.Split(split, "_"); len(parts) > 1 {
+			for _, part := range parts {
+				add(part)
+			}
+		}
+	}
+	return words
+}
+
+// checkTraceability warns about acceptance criteria nothing verifies.
+func (p *PRD) checkTraceability(result *ValidationResult) {
+	for i := range p.Tasks {
+		task := &p.Tasks[i]
+		if len(task.Verification) == 0 && !task.ManualVerification && len(task.ManualChecks) == 0 {
+			if len(task.AcceptanceCriteria) > 0 {
+				result.AddWarning(task.ID, "acceptanceCriteria", "no criterion is covered by verification or a manual check")
+			}
+			continue
+		}
+		for _, trace := range task.Trace() {
+			if !trace.Covered() {
+				result.AddWarning(task.ID, fmt.Sprintf("acceptanceCriteria[%d]", trace.Index-1),
+					"not covered by a verification command or manual check")
+			}
+		}
+	}
+}
@@ -1,8 +1,13 @@
 package prd
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
+	"path"
+	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 )
 
@@ -20,6 +25,19 @@ func (e ValidationError) Error() string {
 	return fmt.Sprintf("%s: %s", e.Field, e.Message)
 }
 
+// InvalidError is returned when a PRD fails validation.
+type InvalidError struct {
+	Path   string
+	Errors []ValidationError
+}
+
+func (e *InvalidError) Error() string {
+	if e.Path != "" {
+		return fmt.Sprintf("%s: validation failed with %d errors", e.Path, len(e.Errors))
+	}
+	return fmt.Sprintf("validation failed with %d errors", len(e.Errors))
+}
+
 // ValidationResult holds the results of PRD validation.
 type ValidationResult struct {
 	Errors   []ValidationError
@@ -31,6 +49,14 @@ func (r *ValidationResult) IsValid() bool {
 	return len(r.Errors) == 0
 }
 
+// Err returns an *InvalidError for the PRD at path if there are errors, or nil.
+func (r *ValidationResult) Err(path string) error {
+	if r.IsValid() {
+		return nil
+	}
+	return &InvalidError{Path: path, Errors: r.Errors}
+}
+
 // HasWarnings returns true if there are warnings.
 func (r *ValidationResult) HasWarnings() bool {
 	return len(r.Warnings) > 0
@@ -77,9 +103,28 @@ func (p *PRD) ValidateQuick() *ValidationResult {
 		result.AddError("", "tasks", "circular dependency detected")
 	}
 
+	p.validateMCPServers(result)
+	p.validateServices(result)
+	p.validatePrerequisites(result)
+
 	return result
 }
 
+// validateMCPServers checks that each MCP server says how to reach it.
+func (p *PRD) validateMCPServers(result *ValidationResult) {
+	for name, raw := range p.MCPServers {
+		var server struct {
+			Command string `json:"command"`
+			URL     string `json:"url"`
+		}
+		if err := json.Unmarshal(raw, &server); err != nil {
+			result.AddError("", "mcpServers."+name, "must be an object")
+		} else if server.Command == "" && server.URL == "" {
+			result.AddError("", "mcpServers."+name, "needs a command or url")
+		}
+	}
+}
+
 // validateTask validates a single task.
 func (p *PRD) validateTask(task *Task, taskIDs map[string]bool, result *ValidationResult) {
 	// Required fields
@@ -106,17 +151,87 @@ func (p *PRD) validateTask(task *Task, taskIDs map[string]bool, result *Validati
 		if dep == task.ID {
 			result.AddError(task.ID, "dependsOn", "task cannot depend on itself")
 		}
+		// A later phase can't start until this one is done
+		if depTask := p.TaskByID(dep); depTask != nil && task.Phase > 0 && depTask.Phase > task.Phase {
+			result.AddError(task.ID, "dependsOn", fmt.Sprintf("depends on '%s' from later phase %d", dep, depTask.Phase))
+		}
 	}
 
+	if task.Phase < 0 {
+		result.AddError(task.ID, "phase", "must be 1 or more")
+	}
+
+	// Workspaces are relative to the repo root and stay inside it
+	if ws := filepath.ToSlash(task.Workspace); ws != "" {
+		if strings.HasPrefix(ws, "/") || filepath.IsAbs(task.Workspace) {
+			result.AddError(task.ID, "workspace", "must be relative to the repo root")
+		} else if clean := path.Clean(ws); clean == ".." || strings.HasPrefix(clean, "../") {
+			result.AddError(task.ID, "workspace", "must be inside the repo")
+		}
+	}
+
+	// Tags select tasks on the command line
+	for i, tag := range task.Tags {
+		if strings.TrimSpace(tag) == "" || strings.ContainsAny(tag, ", ") {
+			result.AddError(task.ID, fmt.Sprintf("tags[%d]", i), "must be a non-empty word without spaces or commas")
+		}
+	}
+
+	// Env names must be usable by a shell
+	for name := range task.Env {
+		if !envNamePattern.MatchString(name) {
+			result.AddError(task.ID, "env", fmt.Sprintf("invalid variable name %q", name))
+		}
+	}
+	if task.Teardown != "" && task.Setup == "" {
+		result.AddWarning(task.ID, "teardown", "set without setup")
+	}
+
+	validateManualChecks(task, result)
+
 	// Validate verification commands
 	for i, v := range task.Verification {
-		if v.Cmd == "" {
+		// Browser verification without a cmd runs BROWSER_TEST_CMD
+		if v.Cmd == "" && v.Type != VerificationBrowser {
 			result.AddError(task.ID, fmt.Sprintf("verification[%d]", i), "cmd required")
 		}
+		for _, n := range v.Criteria {
+			if n < 1 || n > len(task.AcceptanceCriteria) {
+				result.AddWarning(task.ID, fmt.Sprintf("verification[%d]", i),
+					fmt.Sprintf("criteria lists %d, but the task has %d acceptance criteria", n, len(task.AcceptanceCriteria)))
+			}
+		}
 		if v.Type != "" && v.Type != VerificationPattern && v.Type != VerificationUnit &&
-			v.Type != VerificationIntegration && v.Type != VerificationSmoke {
+			v.Type != VerificationIntegration && v.Type != VerificationSmoke && v.Type != VerificationBrowser {
 			result.AddWarning(task.ID, fmt.Sprintf("verification[%d]", i),
-				fmt.Sprintf("unknown type '%s', expected pattern/unit/integration/smoke", v.Type))
+				fmt.Sprintf("unknown type '%s', expected pattern/unit/integration/smoke/browser", v.Type))
+		}
+	}
+}
+
+// validateServices checks that each service has a command. Services a task
+// lists may also come from SERVICES_CONFIG, so unknown names are checked
+// when a run starts.
+func (p *PRD) validateServices(result *ValidationResult) {
+	for name, svc := range p.Services {
+		if strings.TrimSpace(svc.Cmd) == "" {
+			result.AddError("", "services."+name, "cmd required")
+		}
+		if svc.ReadyTimeout < 0 {
+			result.AddError("", "services."+name, "readyTimeout must be 0 or more")
+		}
+	}
+}
+
+// envNamePattern matches names a task's env can set.
+var envNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// validateManualChecks checks manualChecks keys name existing criteria.
+func validateManualChecks(task *Task, result *ValidationResult) {
+	for key := range task.ManualChecks {
+		if n, err := strconv.Atoi(key); err != nil || n < 1 || n > len(task.AcceptanceCriteria) {
+			result.AddWarning(task.ID, "manualChecks",
+				fmt.Sprintf("key %q is not an acceptance criterion number (1-%d)", key, len(task.AcceptanceCriteria)))
 		}
 	}
 }
@@ -137,6 +252,10 @@ func (p *PRD) ValidateFull(opts ValidationOptions) *ValidationResult {
 		p.warnGrepOnlyVerification(result)
 	}
 
+	if opts.CheckTraceability {
+		p.checkTraceability(result)
+	}
+
 	return result
 }
 
@@ -145,6 +264,7 @@ type ValidationOptions struct {
 	LintCriteria           bool
 	CheckVerificationTypes bool
 	WarnGrepOnly           bool
+	CheckTraceability      bool
 	WalkawayMode           bool
 }
 
@@ -190,7 +310,7 @@ func (p *PRD) checkVerificationTypes(result *ValidationResult) {
 				hasUnit = true
 			case VerificationIntegration:
 				hasIntegration = true
-			case VerificationSmoke:
+			case VerificationSmoke, VerificationBrowser:
 				hasSmoke = true
 			}
 		}
@@ -245,12 +365,18 @@ func isGrepCommand(cmd string) bool {
 }
 
 // ValidateDependencies checks that a filtered set of tasks has valid dependencies.
-// Returns an error if any included task depends on an excluded task.
+// Returns an error if any included task depends on an excluded task, or
+// waits on an earlier phase with excluded tasks left to do.
 func (p *PRD) ValidateDependencies(included map[string]bool) error {
 	for _, task := range p.Tasks {
 		if !included[task.ID] {
 			continue
 		}
+		for _, other := range p.Tasks {
+			if other.Phase > 0 && other.Phase < task.Phase && !other.Passes && !included[other.ID] {
+				return fmt.Errorf("task %s is in phase %d, but %s from phase %d is not in the execution set", task.ID, task.Phase, other.ID, other.Phase)
+			}
+		}
 		for _, dep := range task.DependsOn {
 			// Dependency must be either included OR already completed
 			if !included[dep] {
@@ -274,22 +400,44 @@ func SuggestVerification(task *Task, projectStack string) []Verification {
 		return nil // Test tasks don't need verification suggestions
 	}
 
+	// Target the task's files when it lists them
+	dirs := task.FileDirs()
+
 	// Suggest based on project stack
 	switch projectStack {
 	case "go":
+		cmd := "go test ./..."
+		if len(dirs) > 0 {
+			pkgs := make([]string, len(dirs))
+			for i, d := range dirs {
+				pkgs[i] = "./" + strings.TrimPrefix(d, "./") + "/..."
+				if d == "." {
+					pkgs[i] = "./..."
+				}
+			}
+			cmd = "go test " + strings.Join(pkgs, " ")
+		}
 		suggestions = append(suggestions, Verification{
 			Type: VerificationUnit,
-			Cmd:  "go test ./...",
+			Cmd:  cmd,
 		})
 	case "node", "javascript", "typescript":
+		cmd := "npm test"
+		if len(dirs) > 0 {
+			cmd += " -- " + strings.Join(dirs, " ")
+		}
 		suggestions = append(suggestions, Verification{
 			Type: VerificationUnit,
-			Cmd:  "npm test",
+			Cmd:  cmd,
 		})
 	case "python":
+		cmd := "pytest"
+		if len(dirs) > 0 {
+			cmd += " " + strings.Join(dirs, " ")
+		}
 		suggestions = append(suggestions, Verification{
 			Type: VerificationUnit,
-			Cmd:  "pytest",
+			Cmd:  cmd,
 		})
 	case "rust":
 		suggestions = append(suggestions, Verification{
@@ -300,39 +448,75 @@ func SuggestVerification(task *Task, projectStack string) []Verification {
 
 	// Add pattern check for add/create tasks
 	if strings.Contains(titleLower, "add") || strings.Contains(titleLower, "create") {
+		var exact []string
+		for _, f := range task.Files {
+			if !strings.ContainsAny(f, "*?[") {
+				exact = append(exact, "test -f "+f)
+			}
+		}
+		cmd := "# TODO: Add pattern check for created files/code"
+		if len(exact) > 0 {
+			cmd = strings.Join(exact, " && ")
+		}
 		suggestions = append(suggestions, Verification{
 			Type: VerificationPattern,
-			Cmd:  "# TODO: Add pattern check for created files/code",
+			Cmd:  cmd,
 		})
 	}
 
 	return suggestions
 }
 
+// stackFiles maps common project files to their stack, in the order they're
+// checked, so a Go module with a package.json for its tooling is still Go.
+var stackFiles = []struct{ file, stack string }{
+	{"go.mod", "go"},
+	{"Cargo.toml", "rust"},
+	{"package.json", "node"},
+	{"pyproject.toml", "python"},
+	{"requirements.txt", "python"},
+	{"setup.py", "python"},
+	{"Gemfile", "ruby"},
+	{"pom.xml", "java"},
+	{"build.gradle", "java"},
+	{"build.gradle.kts", "java"},
+}
+
 // DetectProjectStack attempts to detect the project's technology stack.
 func DetectProjectStack(projectPath string) string {
-	// Check for common project files
-	files := map[string]string{
-		"go.mod":       "go",
-		"Cargo.toml":   "rust",
-		"package.json": "node",
-		"pyproject.toml": "python",
-		"requirements.txt": "python",
-		"setup.py":     "python",
-		"Gemfile":      "ruby",
-		"pom.xml":      "java",
-		"build.gradle": "java",
-	}
-
-	for file, stack := range files {
-		if _, err := FileExists(projectPath, file); err == nil {
-			return stack
+	for _, sf := range stackFiles {
+		if _, err := FileExists(projectPath, sf.file); err == nil {
+			return sf.stack
 		}
 	}
 
 	return "unknown"
 }
 
+// StackCommands returns the usual test and build commands for a stack, empty
+// when there's no usual command. Java picks Maven or Gradle by the build file
+// in projectPath.
+func StackCommands(projectPath, stack string) (testCmd, buildCmd string) {
+	switch stack {
+	case "go":
+		return "go test ./...", "go build ./..."
+	case "rust":
+		return "cargo test", "cargo build"
+	case "node":
+		return "npm test", "npm run build --if-present"
+	case "python":
+		return "pytest", ""
+	case "ruby":
+		return "bundle exec rake test", ""
+	case "java":
+		if _, err := FileExists(projectPath, "pom.xml"); err == nil {
+			return "mvn -q test", "mvn -q compile"
+		}
+		return "./gradlew test", "./gradlew assemble"
+	}
+	return "", ""
+}
+
 // FileExists is a helper to check if a file exists.
 func FileExists(dir, name string) (bool, error) {
 	path := dir + "/" + name
@@ -348,5 +532,5 @@ func FileExists(dir, name string) (bool, error) {
 
 // ReadFileInfo is a helper that can be mocked in tests.
 var ReadFileInfo = func(path string) (interface{}, error) {
-	return nil, fmt.Errorf("not implemented in this context")
+	return os.Stat(path)
 }
@@ -0,0 +1,231 @@
+package retrieval
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math"
+	"net/http"
+	"os"
+	"strings"
+	"unicode"
+)
+
+// hashDims is the vector size of the built-in hash embedder.
+const hashDims = 512
+
+// Embedder turns text into vectors for similarity search.
+type Embedder interface {
+	// Name identifies the provider and model, so an index built with one
+	// embedder is never searched with another.
+	Name() string
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// NewEmbedder returns the embedder for a provider:
+//   - "hash": built-in token hashing, offline and free (default)
+//   - "ollama": a local Ollama server (model default nomic-embed-text)
+//   - "openai": the OpenAI embeddings API, using OPENAI_API_KEY
+//     (model default text-embedding-3-small)
+//
+// url overrides the provider's endpoint.
+func NewEmbedder(provider, model, url string) (Embedder, error) {
+	switch provider {
+	case "", "hash":
+		return hashEmbedder{}, nil
+	case "ollama":
+		if model == "" {
+			model = "nomic-embed-text"
+		}
+		if url == "" {
+			url = EmbedderURL(provider)
+		}
+		return &ollamaEmbedder{model: model, url: url}, nil
+	case "openai":
+		if model == "" {
+			model = "text-embedding-3-small"
+		}
+		if url == "" {
+			url = EmbedderURL(provider)
+		}
+		key := os.Getenv("OPENAI_API_KEY")
+		if key == "" {
+			return nil, fmt.Errorf("openai embeddings need OPENAI_API_KEY")
+		}
+		return &openAIEmbedder{model: model, url: url, key: key}, nil
+	}
+	return nil, fmt.Errorf("unknown embedding provider: %s (use hash, ollama or openai)", provider)
+}
+
+// EmbedderURL returns a provider's default endpoint, or "" for the hash
+// embedder, which needs none.
+func EmbedderURL(provider string) string {
+	switch provider {
+	case "ollama":
+		return "http://localhost:11434/api/embed"
+	case "openai":
+		return "https://api.openai.com/v1/embeddings"
+	}
+	return ""
+}
+
+// hashEmbedder embeds text as hashed identifier counts. It understands no
+// synonyms, but splitting CamelCase and snake_case makes it a fair match
+// for code and it needs no model.
+type hashEmbedder struct{}
+
+func (hashEmbedder) Name() string { return "hash" }
+
+func (hashEmbedder) Embed(_ context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		v := make([]float32, hashDims)
+		for _, tok := range tokenize(text) {
+			h := fnv.New32a()
+			h.Write([]byte(tok))
+			sum := h.Sum32()
+			// The sign bit spreads collisions out instead of stacking them
+			if sum&1 == 0 {
+				v[sum%hashDims]++
+			} else {
+				v[sum%hashDims]--
+			}
+		}
+		vectors[i] = normalize(v)
+	}
+	return vectors, nil
+}
+
+// tokenize splits text into lowercase words, breaking identifiers at
+// case changes, digits, and punctuation. Very short tokens are dropped.
+func tokenize(text string) []string {
+	var tokens []string
+	var cur []rune
+	flush := func() {
+		if len(cur) > 2 {
+			tokens = append(tokens, strings.ToLower(string(cur)))
+		}
+		cur = cur[:0]
+	}
+	var prev rune
+	for _, r := range text {
+		switch {
+		case !unicode.IsLetter(r):
+			flush()
+		case unicode.IsUpper(r) && unicode.IsLower(prev):
+			flush()
+			cur = append(cur, r)
+		default:
+			cur = append(cur, r)
+		}
+		prev = r
+	}
+	flush()
+	return tokens
+}
+
+// ollamaEmbedder calls a local Ollama server.
+type ollamaEmbedder struct {
+	model string
+	url   string
+}
+
+func (e *ollamaEmbedder) Name() string { return "ollama/" + e.model }
+
+func (e *ollamaEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	var resp struct {
+		Embeddings [][]float32 `json:"embeddings"`
+	}
+	req := map[string]interface{}{"model": e.model, "input": texts}
+	if err := postJSON(ctx, e.url, nil, req, &resp); err != nil {
+		return nil, fmt.Errorf("ollama embeddings: %w", err)
+	}
+	if len(resp.Embeddings) != len(texts) {
+		return nil, fmt.Errorf("ollama embeddings: got %d vectors for %d texts", len(resp.Embeddings), len(texts))
+	}
+	for i := range resp.Embeddings {
+		resp.Embeddings[i] = normalize(resp.Embeddings[i])
+	}
+	return resp.Embeddings, nil
+}
+
+// openAIEmbedder calls the OpenAI embeddings API, or a compatible one.
+type openAIEmbedder struct {
+	model string
+	url   string
+	key   string
+}
+
+func (e *openAIEmbedder) Name() string { return "openai/" + e.model }
+
+func (e *openAIEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	var resp struct {
+		Data []struct {
+			Index     int       `json:"index"`
+			Embedding []float32 `json:"embedding"`
+		} `json:"data"`
+	}
+	headers := map[string]string{"Authorization": "Bearer " + e.key}
+	req := map[string]interface{}{"model": e.model, "input": texts}
+	if err := postJSON(ctx, e.url, headers, req, &resp); err != nil {
+		return nil, fmt.Errorf("openai embeddings: %w", err)
+	}
+	vectors := make([][]float32, len(texts))
+	for _, d := range resp.Data {
+		if d.Index >= 0 && d.Index < len(vectors) {
+			vectors[d.Index] = normalize(d.Embedding)
+		}
+	}
+	for i, v := range vectors {
+		if v == nil {
+			return nil, fmt.Errorf("openai embeddings: no vector for text %d", i)
+		}
+	}
+	return vectors, nil
+}
+
+// postJSON sends a JSON request and decodes the JSON response.
+func postJSON(ctx context.Context, url string, headers map[string]string, body, out interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// normalize scales v to unit length so a dot product is cosine similarity.
+func normalize(v []float32) []float32 {
+	var sum float64
+	for _, x := range v {
+		sum += float64(x) * float64(x)
+	}
+	if sum == 0 {
+		return v
+	}
+	norm := float32(math.Sqrt(sum))
+	for i := range v {
+		v[i] /= norm
+	}
+	return v
+}
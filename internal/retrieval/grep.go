@@ -0,0 +1,303 @@
+// Package retrieval gathers code relevant to a task so workers on expensive
+// tiers start with context instead of searching for it.
+package retrieval
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"brigade/internal/prd"
+)
+
+const (
+	// maxFiles caps how many files a bundle covers.
+	maxFiles = 8
+
+	// maxHitsPerFile caps the matching lines shown per file.
+	maxHitsPerFile = 12
+
+	// maxFileSize skips generated and data files.
+	maxFileSize = 512 * 1024
+)
+
+var (
+	// identPattern matches code-like tokens: CamelCase, snake_case, dotted
+	// names, and backticked terms.
+	identPattern = regexp.MustCompile("`([^`]+)`|\\b([A-Za-z_][A-Za-z0-9_]*(?:\\.[A-Za-z_][A-Za-z0-9_]*)*)\\b")
+
+	// definitionPattern matches lines that declare something.
+	definitionPattern = regexp.MustCompile(`^\s*(func|type|class|def|interface|struct|enum|trait|impl|export|public|private|protected|const|var|let|fn|module)\b`)
+)
+
+// skipDirs are never searched.
+var skipDirs = map[string]bool{
+	".git": true, "node_modules": true, "vendor": true, "dist": true, "build": true,
+	"target": true, "brigade": true, "__pycache__": true, ".venv": true, "venv": true,
+}
+
+// sourceExts are the file types searched.
+var sourceExts = map[string]bool{
+	".go": true, ".py": true, ".js": true, ".jsx": true, ".ts": true, ".tsx": true,
+	".rb": true, ".rs": true, ".java": true, ".kt": true, ".swift": true, ".c": true,
+	".h": true, ".cc": true, ".cpp": true, ".cs": true, ".php": true, ".scala": true,
+	".sh": true, ".sql": true, ".proto": true, ".graphql": true, ".vue": true, ".svelte": true,
+}
+
+// stopWords are common words that say nothing about where code lives.
+var stopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "with": true, "that": true, "this": true,
+	"from": true, "into": true, "when": true, "should": true, "must": true, "will": true,
+	"have": true, "each": true, "only": true, "able": true, "user": true, "users": true,
+	"add": true, "adds": true, "new": true, "create": true, "update": true, "remove": true,
+	"implement": true, "support": true, "return": true, "returns": true, "test": true,
+	"tests": true, "pass": true, "passes": true, "work": true, "works": true, "file": true,
+	"files": true, "code": true, "existing": true, "still": true, "also": true, "are": true,
+	"not": true, "all": true, "any": true, "can": true, "use": true, "uses": true,
+	"using": true, "via": true, "its": true, "their": true, "there": true, "than": true,
+	"then": true, "after": true, "before": true, "given": true, "data": true, "value": true,
+	"values": true, "error": true, "errors": true, "handle": true, "handles": true,
+}
+
+// Hit is a matching line in a file.
+type Hit struct {
+	Line       int
+	Text       string
+	Definition bool
+}
+
+// FileHits is a file relevant to a task and its matching lines.
+type FileHits struct {
+	Path  string
+	Score int
+	Hits  []Hit
+}
+
+// Bundle is the context gathered for a task.
+type Bundle struct {
+	Keywords []string
+	Files    []FileHits
+}
+
+// Keywords extracts search terms from a task's title, description, and
+// acceptance criteria. Code-like tokens come first.
+func Keywords(task *prd.Task) []string {
+	text := strings.Join(append([]string{task.Title, task.Description}, task.AcceptanceCriteria...), "\n")
+
+	seen := make(map[string]bool)
+	var code, words []string
+	for _, m := range identPattern.FindAllStringSubmatch(text, -1) {
+		term := m[1]
+		if term == "" {
+			term = m[2]
+		}
+		term = strings.TrimSpace(term)
+		lower := strings.ToLower(term)
+		if len(term) < 4 || stopWords[lower] || seen[lower] {
+			continue
+		}
+		seen[lower] = true
+		if isCodeLike(term) || m[1] != "" {
+			code = append(code, term)
+		} else {
+			words = append(words, lower)
+		}
+	}
+	return append(code, words...)
+}
+
+// isCodeLike reports whether a term looks like an identifier rather than
+// an English word.
+func isCodeLike(term string) bool {
+	if strings.ContainsAny(term, "_.") {
+		return true
+	}
+	for i, r := range term {
+		if i > 0 && unicode.IsUpper(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// Gather searches the source files under root for the keywords and
+// returns the most relevant files with their matching lines. Files in
+// exclude (e.g. ones already inlined into the prompt) are skipped.
+func Gather(root string, keywords []string, exclude []string) (*Bundle, error) {
+	bundle := &Bundle{Keywords: keywords}
+	if len(keywords) == 0 {
+		return bundle, nil
+	}
+
+	excluded := make(map[string]bool)
+	for _, f := range exclude {
+		excluded[filepath.ToSlash(filepath.Clean(f))] = true
+	}
+	terms := make([]term, len(keywords))
+	for i, k := range keywords {
+		terms[i] = term{text: strings.ToLower(k), weight: 1}
+		if isCodeLike(k) {
+			terms[i].weight = 3
+		}
+	}
+
+	var files []FileHits
+	err := walkSources(root, func(path, rel string) {
+		if excluded[rel] {
+			return
+		}
+		if fh := scanFile(path, rel, terms); fh != nil {
+			files = append(files, *fh)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(files, func(i, j int) bool { return files[i].Score > files[j].Score })
+	if len(files) > maxFiles {
+		files = files[:maxFiles]
+	}
+	bundle.Files = files
+	return bundle, nil
+}
+
+// walkSources calls fn for each searchable source file under root, with
+// its slash-separated path relative to root.
+func walkSources(root string, fn func(path, rel string)) error {
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if path != root && (skipDirs[d.Name()] || strings.HasPrefix(d.Name(), ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !sourceExts[filepath.Ext(path)] {
+			return nil
+		}
+		if info, err := d.Info(); err != nil || info.Size() > maxFileSize {
+			return nil
+		}
+		rel, _ := filepath.Rel(root, path)
+		fn(path, filepath.ToSlash(rel))
+		return nil
+	})
+}
+
+// term is a lowercased keyword and how much a match counts.
+type term struct {
+	text   string
+	weight int
+}
+
+// scanFile scores one file against the search terms. Each matching line
+// counts its best term's weight, declarations count extra, and terms in the
+// file's path count most.
+func scanFile(path, rel string, terms []term) *FileHits {
+	data, err := os.ReadFile(path)
+	if err != nil || bytes.IndexByte(data, 0) >= 0 {
+		return nil
+	}
+
+	fh := &FileHits{Path: rel}
+	lowerPath := strings.ToLower(rel)
+	matched := make(map[string]bool)
+	for _, t := range terms {
+		if strings.Contains(lowerPath, t.text) {
+			fh.Score += 5 * t.weight
+			matched[t.text] = true
+		}
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), maxFileSize)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := scanner.Text()
+		lower := strings.ToLower(text)
+		best := 0
+		for _, t := range terms {
+			if strings.Contains(lower, t.text) {
+				matched[t.text] = true
+				if t.weight > best {
+					best = t.weight
+				}
+			}
+		}
+		if best == 0 {
+			continue
+		}
+		def := definitionPattern.MatchString(text)
+		fh.Score += best
+		if def {
+			fh.Score += 3 * best
+		}
+		fh.Hits = append(fh.Hits, Hit{Line: line, Text: strings.TrimRight(text, " \t"), Definition: def})
+	}
+	if fh.Score == 0 {
+		return nil
+	}
+
+	// Files matching several terms beat files repeating one
+	fh.Score *= len(matched)
+	fh.Hits = topHits(fh.Hits)
+	return fh
+}
+
+// topHits keeps declarations first, then other matches, in line order.
+func topHits(hits []Hit) []Hit {
+	if len(hits) <= maxHitsPerFile {
+		return hits
+	}
+	var kept []Hit
+	for _, h := range hits {
+		if h.Definition && len(kept) < maxHitsPerFile {
+			kept = append(kept, h)
+		}
+	}
+	for _, h := range hits {
+		if !h.Definition && len(kept) < maxHitsPerFile {
+			kept = append(kept, h)
+		}
+	}
+	sort.Slice(kept, func(i, j int) bool { return kept[i].Line < kept[j].Line })
+	return kept
+}
+
+// Render formats the bundle for a prompt, within budget bytes.
+func (b *Bundle) Render(budget int) string {
+	if len(b.Files) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Search terms: %s\n", strings.Join(b.Keywords, ", ")))
+	for _, f := range b.Files {
+		var block strings.Builder
+		block.WriteString(fmt.Sprintf("\n%s\n", f.Path))
+		for _, h := range f.Hits {
+			text := h.Text
+			if len(text) > 160 {
+				text = text[:160] + "..."
+			}
+			block.WriteString(fmt.Sprintf("  %d: %s\n", h.Line, text))
+		}
+		if sb.Len()+block.Len() > budget {
+			sb.WriteString(fmt.Sprintf("\nAlso relevant: %s\n", f.Path))
+			continue
+		}
+		sb.WriteString(block.String())
+	}
+	return sb.String()
+}
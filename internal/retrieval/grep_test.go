@@ -0,0 +1,68 @@
+package retrieval
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"brigade/internal/prd"
+)
+
+func TestKeywords(t *testing.T) {
+	task := &prd.Task{
+		Title:              "Add rate limiting to the login handler",
+		Description:        "Wrap `LoginHandler` with a token bucket from rate_limit.go",
+		AcceptanceCriteria: []string{"RateLimiter.Allow is called for every login request"},
+	}
+	got := strings.Join(Keywords(task), ",")
+	want := "LoginHandler,rate_limit.go,RateLimiter.Allow,rate,limiting,login,handler,wrap,token,bucket,called,every,request"
+	if got != want {
+		t.Errorf("Keywords() = %s, want %s", got, want)
+	}
+}
+
+func TestGather(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"internal/auth/login.go":   "package auth\n\nfunc LoginHandler(w, r) {\n\tcheckPassword(r)\n}\n",
+		"internal/auth/session.go": "package auth\n\n// login sessions\nfunc NewSession() {}\n",
+		"internal/billing/bill.go": "package billing\n\nfunc Charge() {}\n",
+		"node_modules/x/login.js":  "function LoginHandler() {}\n",
+		"brigade/tasks/prd.json":   "{\"title\": \"LoginHandler\"}\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		os.WriteFile(path, []byte(content), 0644)
+	}
+
+	bundle, err := Gather(root, []string{"LoginHandler", "login"}, []string{"internal/auth/session.go"})
+	if err != nil {
+		t.Fatalf("Gather() error = %v", err)
+	}
+	if len(bundle.Files) != 1 || bundle.Files[0].Path != "internal/auth/login.go" {
+		t.Fatalf("Gather() files = %+v, want only internal/auth/login.go", bundle.Files)
+	}
+	hit := bundle.Files[0].Hits[0]
+	if hit.Line != 3 || !hit.Definition {
+		t.Errorf("first hit = %+v, want the LoginHandler declaration on line 3", hit)
+	}
+
+	rendered := bundle.Render(1000)
+	if !strings.Contains(rendered, "internal/auth/login.go\n  3: func LoginHandler(w, r) {") {
+		t.Errorf("Render() = %q", rendered)
+	}
+	if short := bundle.Render(10); !strings.Contains(short, "Also relevant: internal/auth/login.go") {
+		t.Errorf("Render() over budget = %q, want the file listed by name", short)
+	}
+}
+
+func TestExtractContext(t *testing.T) {
+	if got := ExtractContext("thinking...\n<context>\nauth/login.go:3 LoginHandler\n</context>"); got != "auth/login.go:3 LoginHandler" {
+		t.Errorf("ExtractContext() = %q", got)
+	}
+	if got := ExtractContext("no tags"); got != "" {
+		t.Errorf("ExtractContext() = %q, want empty", got)
+	}
+}
@@ -0,0 +1,252 @@
+package retrieval
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"brigade/internal/prd"
+)
+
+const (
+	// IndexPath is where `brigade index` stores the embedding index.
+	IndexPath = "brigade/index.json"
+
+	// chunkLines is the size of an indexed chunk.
+	chunkLines = 40
+
+	// chunkOverlap is how many lines consecutive chunks share, so code near
+	// a boundary appears whole in at least one of them.
+	chunkOverlap = 10
+
+	// embedBatch is how many chunks are sent to the embedder at once.
+	embedBatch = 32
+)
+
+// Chunk is an indexed slice of a source file.
+type Chunk struct {
+	Path   string    `json:"path"`
+	Start  int       `json:"start"`
+	End    int       `json:"end"`
+	Text   string    `json:"text"`
+	Vector []float32 `json:"vector"`
+}
+
+// Index is an embedding index of a repo's source files.
+type Index struct {
+	Embedder string            `json:"embedder"`
+	Built    time.Time         `json:"built"`
+	Files    map[string]string `json:"files"` // Path to content hash
+	Chunks   []Chunk           `json:"chunks"`
+}
+
+// IndexStats describes what a build did.
+type IndexStats struct {
+	Files    int // Source files indexed
+	Reused   int // Files unchanged since the previous index
+	Embedded int // Chunks sent to the embedder
+}
+
+// BuildIndex indexes the source files under root. Chunks of files unchanged
+// since prev are reused when prev was built with the same embedder, so
+// rebuilding after small edits is cheap.
+func BuildIndex(ctx context.Context, root string, emb Embedder, prev *Index) (*Index, IndexStats, error) {
+	idx := &Index{Embedder: emb.Name(), Built: time.Now(), Files: make(map[string]string)}
+	var stats IndexStats
+
+	reusable := make(map[string][]Chunk)
+	if prev != nil && prev.Embedder == emb.Name() {
+		for _, c := range prev.Chunks {
+			reusable[c.Path] = append(reusable[c.Path], c)
+		}
+	}
+
+	var pending []Chunk
+	err := walkSources(root, func(path, rel string) {
+		data, err := os.ReadFile(path)
+		if err != nil || isBinaryData(data) {
+			return
+		}
+		stats.Files++
+		hash := contentHash(data)
+		idx.Files[rel] = hash
+		if prev != nil && prev.Files[rel] == hash && reusable[rel] != nil {
+			idx.Chunks = append(idx.Chunks, reusable[rel]...)
+			stats.Reused++
+			return
+		}
+		pending = append(pending, chunkFile(rel, string(data))...)
+	})
+	if err != nil {
+		return nil, stats, err
+	}
+
+	for start := 0; start < len(pending); start += embedBatch {
+		end := start + embedBatch
+		if end > len(pending) {
+			end = len(pending)
+		}
+		batch := pending[start:end]
+		texts := make([]string, len(batch))
+		for i, c := range batch {
+			texts[i] = c.Path + "\n" + c.Text
+		}
+		vectors, err := emb.Embed(ctx, texts)
+		if err != nil {
+			return nil, stats, err
+		}
+		for i := range batch {
+			batch[i].Vector = vectors[i]
+		}
+		stats.Embedded += len(batch)
+	}
+	idx.Chunks = append(idx.Chunks, pending...)
+
+	sort.SliceStable(idx.Chunks, func(i, j int) bool {
+		if idx.Chunks[i].Path != idx.Chunks[j].Path {
+			return idx.Chunks[i].Path < idx.Chunks[j].Path
+		}
+		return idx.Chunks[i].Start < idx.Chunks[j].Start
+	})
+	return idx, stats, nil
+}
+
+// chunkFile splits a file into overlapping chunks of lines.
+func chunkFile(rel, content string) []Chunk {
+	lines := strings.Split(strings.TrimRight(content, "\n"), "\n")
+	var chunks []Chunk
+	for start := 0; start < len(lines); start += chunkLines - chunkOverlap {
+		end := start + chunkLines
+		if end > len(lines) {
+			end = len(lines)
+		}
+		text := strings.Join(lines[start:end], "\n")
+		if strings.TrimSpace(text) != "" {
+			chunks = append(chunks, Chunk{Path: rel, Start: start + 1, End: end, Text: text})
+		}
+		if end == len(lines) {
+			break
+		}
+	}
+	return chunks
+}
+
+// contentHash returns the hex SHA-256 of data.
+func contentHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// isBinaryData reports whether data looks like a binary file.
+func isBinaryData(data []byte) bool {
+	for _, b := range data[:min(len(data), 8000)] {
+		if b == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// LoadIndex reads an index written by Save.
+func LoadIndex(path string) (*Index, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var idx Index
+	if err := json.Unmarshal(data, &idx); err != nil {
+		return nil, fmt.Errorf("parsing index: %w", err)
+	}
+	return &idx, nil
+}
+
+// Save writes the index to path.
+func (idx *Index) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(idx)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// Search returns the k chunks most similar to query.
+func (idx *Index) Search(ctx context.Context, emb Embedder, query string, k int) ([]Chunk, error) {
+	if emb.Name() != idx.Embedder {
+		return nil, fmt.Errorf("index was built with %s, not %s; run brigade index", idx.Embedder, emb.Name())
+	}
+	vectors, err := emb.Embed(ctx, []string{query})
+	if err != nil {
+		return nil, err
+	}
+	q := vectors[0]
+
+	type scored struct {
+		chunk Chunk
+		score float32
+	}
+	results := make([]scored, 0, len(idx.Chunks))
+	for _, c := range idx.Chunks {
+		if len(c.Vector) != len(q) {
+			continue
+		}
+		var dot float32
+		for i := range q {
+			dot += q[i] * c.Vector[i]
+		}
+		if dot > 0 {
+			results = append(results, scored{c, dot})
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool { return results[i].score > results[j].score })
+
+	var top []Chunk
+	for _, r := range results {
+		if len(top) == k {
+			break
+		}
+		top = append(top, r.chunk)
+	}
+	return top, nil
+}
+
+// IndexRetriever finds indexed code related to a task for its prompt.
+type IndexRetriever struct {
+	index   *Index
+	emb     Embedder
+	k       int
+	timeout time.Duration
+}
+
+// NewIndexRetriever returns a retriever returning the top k chunks per task.
+func NewIndexRetriever(index *Index, emb Embedder, k int, timeout time.Duration) *IndexRetriever {
+	return &IndexRetriever{index: index, emb: emb, k: k, timeout: timeout}
+}
+
+// Retrieve returns the chunks most related to the task, formatted for a
+// prompt, or "" if the search fails or finds nothing.
+func (r *IndexRetriever) Retrieve(task *prd.Task) string {
+	query := strings.Join(append([]string{task.Title, task.Description}, task.AcceptanceCriteria...), "\n")
+
+	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+	defer cancel()
+	chunks, err := r.index.Search(ctx, r.emb, query, r.k)
+	if err != nil || len(chunks) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	for _, c := range chunks {
+		sb.WriteString(fmt.Sprintf("\n--- %s:%d-%d ---\n%s\n", c.Path, c.Start, c.End, c.Text))
+	}
+	return sb.String()
+}
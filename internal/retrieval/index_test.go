@@ -0,0 +1,95 @@
+package retrieval
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"brigade/internal/prd"
+)
+
+func TestTokenize(t *testing.T) {
+	got := strings.Join(tokenize("func parseHTTPRequest(rate_limit int) // OAuth2Token"), ",")
+	want := "func,parse,httprequest,rate,limit,int,oauth,token"
+	if got != want {
+		t.Errorf("tokenize() = %s, want %s", got, want)
+	}
+}
+
+func TestChunkFile(t *testing.T) {
+	content := strings.Repeat("line\n", 75)
+
+	var got []string
+	for _, c := range chunkFile("a.go", content) {
+		got = append(got, fmt.Sprintf("%d-%d", c.Start, c.End))
+	}
+	want := "1-40,31-70,61-75"
+	if strings.Join(got, ",") != want {
+		t.Errorf("chunkFile() = %s, want %s", strings.Join(got, ","), want)
+	}
+}
+
+func TestBuildIndexAndSearch(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"auth/login.go":   "package auth\n\n// LoginHandler checks the password and starts a session.\nfunc LoginHandler() { checkPassword() }\n",
+		"billing/bill.go": "package billing\n\n// Charge bills the invoice to the card.\nfunc Charge(invoice Invoice) {}\n",
+		"README.md":       "login and billing\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		os.WriteFile(path, []byte(content), 0644)
+	}
+
+	ctx := context.Background()
+	emb := hashEmbedder{}
+	idx, stats, err := BuildIndex(ctx, root, emb, nil)
+	if err != nil {
+		t.Fatalf("BuildIndex() error = %v", err)
+	}
+	if stats.Files != 2 || stats.Embedded != 2 || len(idx.Chunks) != 2 {
+		t.Fatalf("BuildIndex() stats = %+v, chunks = %d, want 2 files and 2 chunks", stats, len(idx.Chunks))
+	}
+
+	chunks, err := idx.Search(ctx, emb, "Bill the invoice when charging", 1)
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if len(chunks) != 1 || chunks[0].Path != "billing/bill.go" {
+		t.Errorf("Search() = %+v, want billing/bill.go", chunks)
+	}
+
+	// Only the changed file is embedded again
+	os.WriteFile(filepath.Join(root, "auth/login.go"), []byte("package auth\n\nfunc Logout() {}\n"), 0644)
+	_, stats, err = BuildIndex(ctx, root, emb, idx)
+	if err != nil {
+		t.Fatalf("BuildIndex() rebuild error = %v", err)
+	}
+	if stats.Reused != 1 || stats.Embedded != 1 {
+		t.Errorf("BuildIndex() rebuild stats = %+v, want 1 reused and 1 embedded", stats)
+	}
+
+	path := filepath.Join(root, IndexPath)
+	if err := idx.Save(path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	loaded, err := LoadIndex(path)
+	if err != nil {
+		t.Fatalf("LoadIndex() error = %v", err)
+	}
+	task := &prd.Task{Title: "Fix session handling in LoginHandler"}
+	related := NewIndexRetriever(loaded, emb, 1, time.Minute).Retrieve(task)
+	if !strings.Contains(related, "--- auth/login.go:1-4 ---") {
+		t.Errorf("Retrieve() = %q, want the login chunk", related)
+	}
+
+	loaded.Embedder = "ollama/nomic-embed-text"
+	if _, err := loaded.Search(ctx, emb, "login", 1); err == nil {
+		t.Error("Search() with a different embedder should fail")
+	}
+}
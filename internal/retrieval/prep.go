@@ -0,0 +1,46 @@
+package retrieval
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"brigade/internal/prd"
+)
+
+// contextPattern extracts the prep cook's summary from model output.
+var contextPattern = regexp.MustCompile(`(?s)<context>(.*?)</context>`)
+
+// PrepPrompt asks a cheap model to turn search results into a context
+// bundle for the worker that will do the task.
+func PrepPrompt(task *prd.Task, found string, budget int) string {
+	var sb strings.Builder
+	sb.WriteString("You are the prep cook. Another engineer is about to work on the task below. ")
+	sb.WriteString("Do NOT make any changes. Read the code and gather what they will need: ")
+	sb.WriteString("the files to change, the interfaces and types involved with their signatures, ")
+	sb.WriteString("the call sites that will be affected, and any existing code to follow as a pattern.\n\n")
+	sb.WriteString(fmt.Sprintf("Task %s: %s\n", task.ID, task.Title))
+	if task.Description != "" {
+		sb.WriteString(task.Description + "\n")
+	}
+	for _, c := range task.AcceptanceCriteria {
+		sb.WriteString("- " + c + "\n")
+	}
+	if found != "" {
+		sb.WriteString("\nA keyword search found these candidates (may include noise):\n")
+		sb.WriteString(found)
+	}
+	sb.WriteString(fmt.Sprintf("\nRespond with the bundle inside <context></context>, under %d characters. ", budget))
+	sb.WriteString("Cite file paths and line numbers; quote signatures rather than whole files.\n")
+	return sb.String()
+}
+
+// ExtractContext returns the bundle from prep cook output, or "" if the
+// output has none.
+func ExtractContext(output string) string {
+	m := contextPattern.FindStringSubmatch(output)
+	if m == nil {
+		return ""
+	}
+	return strings.TrimSpace(m[1])
+}
@@ -0,0 +1,246 @@
+// Package services runs background processes, such as dev servers, while a
+// task is verified: started before its verification commands, checked
+// for health, and stopped afterwards, with their output kept for failure
+// analysis.
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"brigade/internal/prd"
+)
+
+// LogDir is where each service's output is written, one file per service.
+var LogDir = filepath.Join("brigade", "services")
+
+// pollInterval is how often a starting service's health is checked.
+const pollInterval = 250 * time.Millisecond
+
+// stopGrace is how long a service has to exit after SIGTERM.
+const stopGrace = 5 * time.Second
+
+// Load reads the services declared in a SERVICES_CONFIG file
+// ({"services": {...}}).
+func Load(path string) (map[string]prd.Service, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var config struct {
+		Services map[string]prd.Service `json:"services"`
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	for name, svc := range config.Services {
+		if svc.Cmd == "" {
+			return nil, fmt.Errorf("%s: service %q has no cmd", path, name)
+		}
+	}
+	return config.Services, nil
+}
+
+// Merge combines service sets; later sets override services of the same
+// name in earlier ones.
+func Merge(sets ...map[string]prd.Service) map[string]prd.Service {
+	merged := make(map[string]prd.Service)
+	for _, set := range sets {
+		for name, svc := range set {
+			merged[name] = svc
+		}
+	}
+	return merged
+}
+
+// Manager starts and stops a set of declared services.
+type Manager struct {
+	specs        map[string]prd.Service
+	logDir       string
+	readyTimeout time.Duration
+
+	running []*process
+}
+
+// process is a started service.
+type process struct {
+	name    string
+	cmd     *exec.Cmd
+	logPath string
+	done    chan struct{}
+	err     error // Set once done is closed
+}
+
+// NewManager returns a manager for specs that writes logs to logDir and
+// waits readyTimeout for services that don't set their own.
+func NewManager(specs map[string]prd.Service, logDir string, readyTimeout time.Duration) *Manager {
+	return &Manager{specs: specs, logDir: logDir, readyTimeout: readyTimeout}
+}
+
+// Specs returns the declared services.
+func (m *Manager) Specs() map[string]prd.Service {
+	return m.specs
+}
+
+// Start starts the named services in order, each with env added to its
+// environment, and waits for each to be ready before starting the next.
+// Services from an earlier Start are stopped first. On error the services
+// already started keep running; call Stop.
+func (m *Manager) Start(ctx context.Context, names []string, env []string) error {
+	m.Stop()
+	m.running = nil
+	for _, name := range names {
+		svc, ok := m.specs[name]
+		if !ok {
+			return fmt.Errorf("unknown service %q", name)
+		}
+		p, err := m.start(name, svc, env)
+		if err != nil {
+			return fmt.Errorf("starting service %s: %w", name, err)
+		}
+		m.running = append(m.running, p)
+		if err := m.awaitReady(ctx, p, svc); err != nil {
+			return fmt.Errorf("service %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func (m *Manager) start(name string, svc prd.Service, env []string) (*process, error) {
+	if err := os.MkdirAll(m.logDir, 0755); err != nil {
+		return nil, err
+	}
+	logPath := filepath.Join(m.logDir, name+".log")
+	logFile, err := os.Create(logPath)
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := exec.Command("sh", "-c", svc.Cmd)
+	cmd.Dir = svc.Dir
+	cmd.Env = append(os.Environ(), env...)
+	for k, v := range svc.Env {
+		cmd.Env = append(cmd.Env, k+"="+v)
+	}
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	setProcessGroup(cmd)
+	if err := cmd.Start(); err != nil {
+		logFile.Close()
+		return nil, err
+	}
+
+	p := &process{name: name, cmd: cmd, logPath: logPath, done: make(chan struct{})}
+	go func() {
+		p.err = cmd.Wait()
+		logFile.Close()
+		close(p.done)
+	}()
+	return p, nil
+}
+
+// awaitReady polls a service's health check until it passes, the service
+// exits, or its ready timeout passes. A service without a health check is
+// ready once it has stayed up for one poll.
+func (m *Manager) awaitReady(ctx context.Context, p *process, svc prd.Service) error {
+	timeout := m.readyTimeout
+	if svc.ReadyTimeout > 0 {
+		timeout = time.Duration(svc.ReadyTimeout) * time.Second
+	}
+	deadline := time.Now().Add(timeout)
+
+	for {
+		select {
+		case <-p.done:
+			if p.err != nil {
+				return fmt.Errorf("exited before it was ready: %w", p.err)
+			}
+			return errors.New("exited before it was ready")
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(pollInterval):
+		}
+
+		if healthy(ctx, svc) {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("not ready after %v", timeout)
+		}
+	}
+}
+
+// healthy runs a service's health check.
+func healthy(ctx context.Context, svc prd.Service) bool {
+	switch {
+	case svc.HealthURL != "":
+		reqCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+		defer cancel()
+		req, err := http.NewRequestWithContext(reqCtx, http.MethodGet, svc.HealthURL, nil)
+		if err != nil {
+			return false
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return false
+		}
+		resp.Body.Close()
+		return resp.StatusCode < 500
+	case svc.HealthCmd != "":
+		cmdCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+		cmd := exec.CommandContext(cmdCtx, "sh", "-c", svc.HealthCmd)
+		cmd.Dir = svc.Dir
+		return cmd.Run() == nil
+	default:
+		return true
+	}
+}
+
+// Stop stops the running services, newest first: SIGTERM to each one's
+// process group, then SIGKILL if it hasn't exited within a few seconds.
+// The group is signalled even if the service itself has exited, in case
+// it left children behind.
+func (m *Manager) Stop() {
+	for i := len(m.running) - 1; i >= 0; i-- {
+		p := m.running[i]
+		signalGroup(p.cmd.Process.Pid, syscall.SIGTERM)
+		select {
+		case <-p.done:
+		case <-time.After(stopGrace):
+			signalGroup(p.cmd.Process.Pid, syscall.SIGKILL)
+			<-p.done
+		}
+	}
+}
+
+// Log is the tail of a service's output.
+type Log struct {
+	Name   string
+	Output string
+}
+
+// Logs returns the last maxBytes of output of each service from the last
+// Start, in start order. The logs outlive Stop.
+func (m *Manager) Logs(maxBytes int) []Log {
+	var logs []Log
+	for _, p := range m.running {
+		data, err := os.ReadFile(p.logPath)
+		if err != nil {
+			continue
+		}
+		if len(data) > maxBytes {
+			data = append([]byte("..."), data[len(data)-maxBytes:]...)
+		}
+		logs = append(logs, Log{Name: p.name, Output: string(data)})
+	}
+	return logs
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"brigade/internal/prd"
+)
+
+func TestManagerStart(t *testing.T) {
+	ready := filepath.Join(t.TempDir(), "ready")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		svc     prd.Service
+		wantErr string
+	}{
+		{"health url", prd.Service{Cmd: "sleep 30", HealthURL: server.URL}, ""},
+		{"health cmd", prd.Service{Cmd: "touch " + ready + "; sleep 30", HealthCmd: "test -f " + ready}, ""},
+		{"no health check", prd.Service{Cmd: "sleep 30"}, ""},
+		{"exits early", prd.Service{Cmd: "echo port in use; exit 1", HealthCmd: "false"}, "exited before it was ready"},
+		{"never ready", prd.Service{Cmd: "sleep 30", HealthCmd: "false", ReadyTimeout: 1}, "not ready after 1s"},
+	}
+
+	for _, tt := range tests {
+		m := NewManager(map[string]prd.Service{"web": tt.svc}, t.TempDir(), 5*time.Second)
+		err := m.Start(context.Background(), []string{"web"}, nil)
+		m.Stop()
+		if tt.wantErr == "" && err != nil {
+			t.Errorf("%s: Start() error = %v", tt.name, err)
+		}
+		if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+			t.Errorf("%s: Start() error = %v, want %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestManagerUnknownService(t *testing.T) {
+	m := NewManager(nil, t.TempDir(), time.Second)
+	if err := m.Start(context.Background(), []string{"db"}, nil); err == nil {
+		t.Error("Start() of an undeclared service succeeded")
+	}
+}
+
+func TestManagerLogs(t *testing.T) {
+	m := NewManager(map[string]prd.Service{
+		"web": {Cmd: "echo listening on $PORT; echo boom >&2; exit 1"},
+	}, t.TempDir(), time.Second)
+	if err := m.Start(context.Background(), []string{"web"}, []string{"PORT=3000"}); err == nil {
+		t.Fatal("Start() of a crashing service succeeded")
+	}
+	m.Stop()
+
+	logs := m.Logs(1000)
+	if len(logs) != 1 || logs[0].Name != "web" {
+		t.Fatalf("Logs() = %v, want the web service's log", logs)
+	}
+	if !strings.Contains(logs[0].Output, "listening on 3000") || !strings.Contains(logs[0].Output, "boom") {
+		t.Errorf("Logs() output = %q, want stdout and stderr", logs[0].Output)
+	}
+	if got := m.Logs(4)[0].Output; got != "...oom\n" {
+		t.Errorf("Logs(4) output = %q, want the tail", got)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "services.json")
+	os.WriteFile(path, []byte(`{"services": {"web": {"cmd": "npm run dev", "healthUrl": "http://localhost:3000"}}}`), 0644)
+	specs, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if specs["web"].Cmd != "npm run dev" || specs["web"].HealthURL != "http://localhost:3000" {
+		t.Errorf("Load() = %+v", specs)
+	}
+
+	os.WriteFile(path, []byte(`{"services": {"web": {"healthUrl": "http://localhost:3000"}}}`), 0644)
+	if _, err := Load(path); err == nil {
+		t.Error("Load() accepted a service without a cmd")
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
@@ -12,8 +13,66 @@ import (
 
 // lockInfo represents the JSON lock file format.
 type lockInfo struct {
-	PID       int   `json:"pid"`
-	Heartbeat int64 `json:"heartbeat"`
+	PID       int    `json:"pid"`
+	Host      string `json:"host,omitempty"`
+	StartedAt int64  `json:"startedAt,omitempty"`
+	Heartbeat int64  `json:"heartbeat"`
+	Lease     int64  `json:"lease,omitempty"` // seconds after Heartbeat before the lock expires
+}
+
+// LockHolder describes the process currently holding a lock.
+type LockHolder struct {
+	PID       int           `json:"pid"`
+	Host      string        `json:"host,omitempty"`
+	StartedAt time.Time     `json:"startedAt,omitempty"`
+	Heartbeat time.Time     `json:"heartbeat,omitempty"`
+	Lease     time.Duration `json:"-"`
+}
+
+// IsLocal reports whether the holder runs on this machine.
+// Holders that predate host tracking are assumed local.
+func (h *LockHolder) IsLocal() bool {
+	if h.Host == "" {
+		return true
+	}
+	host, _ := os.Hostname()
+	return h.Host == host
+}
+
+// Expired reports whether the holder's lease has run out without a heartbeat.
+func (h *LockHolder) Expired() bool {
+	if h.Heartbeat.IsZero() || h.Lease <= 0 {
+		return false
+	}
+	return time.Since(h.Heartbeat) > h.Lease
+}
+
+// String returns a human-readable description of the holder.
+func (h *LockHolder) String() string {
+	s := fmt.Sprintf("PID %d", h.PID)
+	if h.Host != "" {
+		s += " on " + h.Host
+	}
+	if !h.StartedAt.IsZero() {
+		s += ", started " + h.StartedAt.Format("2006-01-02 15:04:05")
+	}
+	if !h.Heartbeat.IsZero() {
+		s += fmt.Sprintf(", last heartbeat %s ago", time.Since(h.Heartbeat).Round(time.Second))
+	}
+	return s
+}
+
+// LockHeldError is returned when a lock is held by another process.
+type LockHeldError struct {
+	Path   string
+	Holder *LockHolder // nil if the holder could not be determined
+}
+
+func (e *LockHeldError) Error() string {
+	if e.Holder == nil {
+		return "lock is held by another process"
+	}
+	return "lock held by " + e.Holder.String()
 }
 
 // Lock represents a file-based lock using mkdir (cross-platform compatible).
@@ -23,6 +82,7 @@ type Lock struct {
 	stale             time.Duration
 	heartbeatInterval time.Duration
 	force             bool
+	startedAt         time.Time
 }
 
 // LockOption configures lock behavior.
@@ -104,9 +164,8 @@ func (l *Lock) Acquire() error {
 
 		// Check timeout
 		if time.Now().After(deadline) {
-			holder := l.getHolder()
-			if holder != "" {
-				return fmt.Errorf("lock held by PID %s (timeout after %v)", holder, l.timeout)
+			if holder, err := l.Holder(); err == nil {
+				return fmt.Errorf("timeout after %v: %w", l.timeout, &LockHeldError{Path: l.path, Holder: holder})
 			}
 			return fmt.Errorf("lock acquisition timeout after %v", l.timeout)
 		}
@@ -154,10 +213,19 @@ func (l *Lock) TryAcquire() bool {
 }
 
 // writeLockInfo writes the JSON lock info file.
+// The start time is recorded on first write and preserved across heartbeats.
 func (l *Lock) writeLockInfo() error {
+	now := time.Now()
+	if l.startedAt.IsZero() {
+		l.startedAt = now
+	}
+	host, _ := os.Hostname()
 	info := lockInfo{
 		PID:       os.Getpid(),
-		Heartbeat: time.Now().Unix(),
+		Host:      host,
+		StartedAt: l.startedAt.Unix(),
+		Heartbeat: now.Unix(),
+		Lease:     int64((l.heartbeatInterval * 2).Seconds()),
 	}
 	data, err := json.Marshal(info)
 	if err != nil {
@@ -189,24 +257,30 @@ func (l *Lock) readLockInfo() (*lockInfo, error) {
 	return nil, fmt.Errorf("invalid lock file format")
 }
 
-// isStale checks if the lock is stale based on heartbeat or file age.
+// isStale checks if the lock is stale based on the holder's lease, PID, or file age.
+// PIDs are only meaningful on the holder's own host, so remote holders are
+// judged purely by their heartbeat lease.
 func (l *Lock) isStale() bool {
-	info, err := l.readLockInfo()
+	holder, err := l.Holder()
 	if err != nil {
 		// Can't read lock info, consider stale
 		return true
 	}
 
-	// Check if process is running
-	if !isProcessRunning(info.PID) {
+	// Check if process is running (same host only)
+	if holder.IsLocal() && !isProcessRunning(holder.PID) {
 		return true
 	}
 
-	// If heartbeat is set, check heartbeat freshness (>2x interval = stale)
-	if info.Heartbeat > 0 && l.heartbeatInterval > 0 {
-		age := time.Since(time.Unix(info.Heartbeat, 0))
-		if age > l.heartbeatInterval*2 {
-			return true
+	// If heartbeat is set, its lease is authoritative. The holder's own lease
+	// wins over our interval; older lock files without one use 2x our interval.
+	// The directory mtime doesn't change on heartbeat, so don't fall through.
+	if !holder.Heartbeat.IsZero() {
+		if holder.Lease <= 0 {
+			holder.Lease = l.heartbeatInterval * 2
+		}
+		if holder.Lease > 0 {
+			return holder.Expired()
 		}
 	}
 
@@ -220,9 +294,9 @@ func (l *Lock) isStale() bool {
 
 // tryRemoveStale attempts to remove a stale lock.
 func (l *Lock) tryRemoveStale() bool {
-	// Double-check that holder PID is not running
-	info, err := l.readLockInfo()
-	if err == nil && isProcessRunning(info.PID) {
+	// Double-check that a local holder PID is not running
+	holder, err := l.Holder()
+	if err == nil && holder.IsLocal() && isProcessRunning(holder.PID) {
 		return false // Process still running, not stale
 	}
 
@@ -233,13 +307,38 @@ func (l *Lock) tryRemoveStale() bool {
 	return true
 }
 
-// getHolder returns the PID of the current lock holder as a string.
-func (l *Lock) getHolder() string {
+// Holder returns details about the current lock holder.
+// Returns an error if the lock is not held or its info can't be read.
+func (l *Lock) Holder() (*LockHolder, error) {
 	info, err := l.readLockInfo()
 	if err != nil {
-		return ""
+		return nil, err
+	}
+	holder := &LockHolder{PID: info.PID, Host: info.Host}
+	if info.StartedAt > 0 {
+		holder.StartedAt = time.Unix(info.StartedAt, 0)
 	}
-	return fmt.Sprintf("%d", info.PID)
+	if info.Heartbeat > 0 {
+		holder.Heartbeat = time.Unix(info.Heartbeat, 0)
+	}
+	holder.Lease = time.Duration(info.Lease) * time.Second
+	return holder, nil
+}
+
+// Exists reports whether the lock directory exists.
+func (l *Lock) Exists() bool {
+	_, err := os.Stat(l.path)
+	return err == nil
+}
+
+// IsStale reports whether an existing lock appears abandoned.
+func (l *Lock) IsStale() bool {
+	return l.isStale()
+}
+
+// Path returns the lock directory path.
+func (l *Lock) Path() string {
+	return l.path
 }
 
 // UpdateHeartbeat updates the heartbeat timestamp.
@@ -295,12 +394,18 @@ func NewServiceLock(prdPath string, opts ...LockOption) *ServiceLock {
 	}
 }
 
-// AcquireExclusive acquires an exclusive lock for service execution.
-// This prevents multiple brigade instances from processing the same PRD.
+// AcquireExclusive acquires an exclusive lock for service execution and
+// starts the heartbeat. This prevents multiple brigade instances from
+// processing the same PRD. It fails immediately if a live holder exists.
 func (s *ServiceLock) AcquireExclusive() error {
-	if err := s.Acquire(); err != nil {
-		return fmt.Errorf("another Brigade instance is processing %s: %w", filepath.Base(s.prdPath), err)
+	if !s.TryAcquire() {
+		held := &LockHeldError{Path: s.path}
+		if holder, err := s.Holder(); err == nil {
+			held.Holder = holder
+		}
+		return fmt.Errorf("another Brigade instance is processing %s: %w", filepath.Base(s.prdPath), held)
 	}
+	s.StartHeartbeat(s.heartbeatInterval)
 	return nil
 }
 
@@ -309,8 +414,8 @@ func (s *ServiceLock) StartHeartbeat(interval time.Duration) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	if s.stopHeartbeat != nil {
-		// Already running
+	if s.stopHeartbeat != nil || interval <= 0 {
+		// Already running, or heartbeat disabled
 		return
 	}
 
@@ -351,3 +456,31 @@ func (s *ServiceLock) Release() error {
 	s.StopHeartbeat()
 	return s.Lock.Release()
 }
+
+// ServiceLockStatus describes a service lock found on disk.
+type ServiceLockStatus struct {
+	PRDPath string      `json:"prd"`
+	Holder  *LockHolder `json:"holder,omitempty"`
+	Stale   bool        `json:"stale"`
+}
+
+// FindServiceLocks scans dir for service locks of any host.
+// Useful when several machines share a workspace over a network filesystem.
+func FindServiceLocks(dir string, opts ...LockOption) ([]ServiceLockStatus, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, "*.service.lock"))
+	if err != nil {
+		return nil, err
+	}
+
+	var locks []ServiceLockStatus
+	for _, lockDir := range matches {
+		prdPath := strings.TrimSuffix(lockDir, ".service.lock") + ".json"
+		lock := NewServiceLock(prdPath, opts...)
+		status := ServiceLockStatus{PRDPath: prdPath, Stale: lock.IsStale()}
+		if holder, err := lock.Holder(); err == nil {
+			status.Holder = holder
+		}
+		locks = append(locks, status)
+	}
+	return locks, nil
+}
@@ -0,0 +1,70 @@
+package state
+
+import "slices"
+
+// TaskIDs returns every task the state has records for, in the order they
+// first appear.
+func (s *State) TaskIDs() []string {
+	var ids []string
+	seen := make(map[string]bool)
+	add := func(id string) {
+		if id != "" && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	for _, h := range s.TaskHistory {
+		add(h.TaskID)
+	}
+	for _, e := range s.Escalations {
+		add(e.TaskID)
+	}
+	for _, r := range s.Reviews {
+		add(r.TaskID)
+	}
+	for _, a := range s.Absorptions {
+		add(a.TaskID)
+		add(a.AbsorbedBy)
+	}
+	for _, f := range s.SessionFailures {
+		add(f.TaskID)
+	}
+	for _, v := range s.Verifications {
+		add(v.TaskID)
+	}
+	for _, f := range s.VerificationFailures {
+		add(f.TaskID)
+	}
+	for _, q := range s.ReviewQueue {
+		add(q.TaskID)
+	}
+	add(s.CurrentTask)
+	return ids
+}
+
+// ForgetTask drops every record of a task. Absorptions of other tasks into
+// it are dropped too, returning those tasks to pending.
+func (s *State) ForgetTask(taskID string) {
+	s.TaskHistory = slices.DeleteFunc(s.TaskHistory, func(h TaskHistory) bool { return h.TaskID == taskID })
+	s.Escalations = slices.DeleteFunc(s.Escalations, func(e Escalation) bool { return e.TaskID == taskID })
+	s.Reviews = slices.DeleteFunc(s.Reviews, func(r Review) bool { return r.TaskID == taskID })
+	s.Absorptions = slices.DeleteFunc(s.Absorptions, func(a Absorption) bool {
+		return a.TaskID == taskID || a.AbsorbedBy == taskID
+	})
+	s.SessionFailures = slices.DeleteFunc(s.SessionFailures, func(f SessionFailure) bool { return f.TaskID == taskID })
+	s.Verifications = slices.DeleteFunc(s.Verifications, func(v VerificationRun) bool { return v.TaskID == taskID })
+	s.VerificationFailures = slices.DeleteFunc(s.VerificationFailures, func(f VerificationFailure) bool { return f.TaskID == taskID })
+	s.ReviewQueue = slices.DeleteFunc(s.ReviewQueue, func(q QueuedReview) bool { return q.TaskID == taskID })
+	if s.CurrentTask == taskID {
+		s.ClearCurrentTask()
+	}
+}
+
+// ForgetCompletion drops a task's completion and absorption records so it
+// counts as pending again. Its other attempts are kept.
+func (s *State) ForgetCompletion(taskID string) {
+	s.TaskHistory = slices.DeleteFunc(s.TaskHistory, func(h TaskHistory) bool {
+		return h.TaskID == taskID && (h.Status == StatusComplete || h.Status == StatusAbsorbed)
+	})
+	s.Absorptions = slices.DeleteFunc(s.Absorptions, func(a Absorption) bool { return a.TaskID == taskID })
+}
@@ -0,0 +1,122 @@
+package state
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// scrubMaxLen caps a scrubbed message.
+const scrubMaxLen = 200
+
+// ScrubPolicy selects which stored worker output Scrub reduces. Entries
+// match if they are older than OlderThan (when set) or belong to a
+// completed task (when Completed is set). All matches everything.
+type ScrubPolicy struct {
+	OlderThan time.Duration
+	Completed bool
+	All       bool
+}
+
+// Active reports whether the policy can match anything.
+func (p ScrubPolicy) Active() bool {
+	return p.All || p.Completed || p.OlderThan > 0
+}
+
+// ScrubStats counts the entries a scrub changed.
+type ScrubStats struct {
+	Attempts             int // Task history errors and approaches
+	SessionFailures      int
+	VerificationFailures int
+}
+
+// Total returns the number of entries changed.
+func (s ScrubStats) Total() int {
+	return s.Attempts + s.SessionFailures + s.VerificationFailures
+}
+
+// Scrub reduces the error messages, approaches and verification output
+// stored for entries matching the policy to their first line, with
+// absolute paths shortened to their last element. Categories, statuses and
+// timings are kept, so history stays useful for retries and reporting.
+// Scrubbing is idempotent.
+func (s *State) Scrub(p ScrubPolicy, now time.Time) ScrubStats {
+	var stats ScrubStats
+	if !p.Active() {
+		return stats
+	}
+	completed := s.CompletedTaskIDs()
+	matches := func(taskID, timestamp string) bool {
+		if p.All || (p.Completed && completed[taskID]) {
+			return true
+		}
+		if p.OlderThan > 0 {
+			if t, err := time.Parse(time.RFC3339, timestamp); err == nil {
+				return now.Sub(t) > p.OlderThan
+			}
+		}
+		return false
+	}
+
+	for i := range s.TaskHistory {
+		h := &s.TaskHistory[i]
+		if !matches(h.TaskID, h.Timestamp) {
+			continue
+		}
+		changed := scrubField(&h.Error)
+		changed = scrubField(&h.Approach) || changed
+		if changed {
+			stats.Attempts++
+		}
+	}
+	for i := range s.SessionFailures {
+		f := &s.SessionFailures[i]
+		if matches(f.TaskID, f.Timestamp) && scrubField(&f.Error) {
+			stats.SessionFailures++
+		}
+	}
+	for i := range s.VerificationFailures {
+		f := &s.VerificationFailures[i]
+		if matches(f.TaskID, f.Timestamp) && scrubField(&f.Output) {
+			stats.VerificationFailures++
+		}
+	}
+	return stats
+}
+
+// scrubField replaces *s with ScrubText(*s), reporting whether it changed.
+func scrubField(s *string) bool {
+	scrubbed := ScrubText(*s)
+	if scrubbed == *s {
+		return false
+	}
+	*s = scrubbed
+	return true
+}
+
+// absPathPattern matches absolute Unix and Windows paths, but not the path
+// part of a URL.
+var absPathPattern = regexp.MustCompile(`(^|[\s"'(=\[])((?:[A-Za-z]:)?(?:[/\\][^\s/\\:"'()\[\]]+){2,})`)
+
+// ScrubText reduces stored worker output to its first non-empty line, with
+// absolute paths shortened to ".../<last element>" and the result capped
+// at 200 characters.
+func ScrubText(s string) string {
+	line := ""
+	for _, l := range strings.Split(s, "\n") {
+		if l = strings.TrimSpace(l); l != "" {
+			line = l
+			break
+		}
+	}
+	line = absPathPattern.ReplaceAllStringFunc(line, func(m string) string {
+		parts := absPathPattern.FindStringSubmatch(m)
+		path := parts[2]
+		last := path[strings.LastIndexAny(path, `/\`)+1:]
+		return parts[1] + ".../" + last
+	})
+	if len(line) > scrubMaxLen {
+		line = line[:scrubMaxLen-3] + "..."
+	}
+	return line
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"os"
 	"time"
+
+	"brigade/internal/classify"
 )
 
 // TaskStatus represents the status of a task attempt.
@@ -31,14 +33,32 @@ const (
 
 // TaskHistory records an attempt to complete a task.
 type TaskHistory struct {
-	TaskID    string     `json:"taskId"`
-	Worker    WorkerTier `json:"worker"`
-	Status    TaskStatus `json:"status"`
-	Timestamp string     `json:"timestamp"`
-	Duration  int        `json:"duration,omitempty"` // Duration in seconds
-	Approach  string     `json:"approach,omitempty"`
-	Error     string     `json:"error,omitempty"`
-	Category  string     `json:"category,omitempty"` // Error category (syntax/logic/integration/env)
+	TaskID     string     `json:"taskId"`
+	Worker     WorkerTier `json:"worker"`
+	Command    string     `json:"command,omitempty"`    // Worker command the attempt ran
+	Credential string     `json:"credential,omitempty"` // Named credential it ran with (*_CREDENTIALS)
+	Status     TaskStatus `json:"status"`
+	Timestamp  string     `json:"timestamp"`
+	Duration   int        `json:"duration,omitempty"` // Duration in seconds
+	Approach   string     `json:"approach,omitempty"`
+	Error      string     `json:"error,omitempty"`
+	Category   string     `json:"category,omitempty"`  // Error category (syntax/logic/integration/env)
+	DiffLines  int        `json:"diffLines,omitempty"` // Lines the task changed, on its completing attempt
+	Learnings  []string   `json:"learnings,omitempty"` // <learning> tags the worker left
+
+	// Commits the task's work spans, on its completing attempt. They're equal
+	// when the worker left its changes uncommitted.
+	BaseCommit string `json:"baseCommit,omitempty"`
+	Commit     string `json:"commit,omitempty"`
+
+	// Tokens the worker reported using, if it reported any
+	InputTokens     int `json:"inputTokens,omitempty"`
+	CacheReadTokens int `json:"cacheReadTokens,omitempty"`
+	OutputTokens    int `json:"outputTokens,omitempty"`
+
+	// Files the attempt left in its artifacts directory, relative to it
+	ArtifactsDir string   `json:"artifactsDir,omitempty"`
+	Artifacts    []string `json:"artifacts,omitempty"`
 }
 
 // Escalation records when a task was escalated to a higher tier.
@@ -55,9 +75,33 @@ type Review struct {
 	TaskID    string `json:"taskId"`
 	Result    string `json:"result"` // "pass" or "fail"
 	Reason    string `json:"reason,omitempty"`
+	Diff      string `json:"diff,omitempty"`   // Hash of the task's changes that were reviewed
+	Reused    bool   `json:"reused,omitempty"` // The verdict of an earlier review of the same changes
 	Timestamp string `json:"timestamp"`
 }
 
+// Human review statuses.
+const (
+	HumanReviewPending    = "pending"
+	HumanReviewApproved   = "approved"
+	HumanReviewRemediated = "remediated"
+)
+
+// QueuedReview is a completed task flagged for a human spot-check.
+type QueuedReview struct {
+	TaskID      string   `json:"taskId"`
+	Reasons     []string `json:"reasons"`
+	Confidence  string   `json:"confidence,omitempty"`  // The executive review's confidence in its pass
+	Review      string   `json:"review,omitempty"`      // What the executive review flagged
+	BaseCommit  string   `json:"baseCommit,omitempty"`  // HEAD before the task's first attempt
+	Commit      string   `json:"commit,omitempty"`      // HEAD when it completed
+	Status      string   `json:"status"`                // pending, approved or remediated
+	Note        string   `json:"note,omitempty"`        // The human reviewer's note
+	Remediation string   `json:"remediation,omitempty"` // Task added to fix what the reviewer found
+	Timestamp   string   `json:"timestamp"`
+	ResolvedAt  string   `json:"resolvedAt,omitempty"`
+}
+
 // Absorption records when a task was absorbed by another task.
 type Absorption struct {
 	TaskID     string `json:"taskId"`
@@ -67,6 +111,7 @@ type Absorption struct {
 
 // PhaseReview records a periodic phase review result.
 type PhaseReview struct {
+	Phase          int    `json:"phase,omitempty"` // PRD phase reviewed, 0 for a periodic review
 	CompletedTasks int    `json:"completedTasks"`
 	TotalTasks     int    `json:"totalTasks"`
 	Status         string `json:"status"` // "pass", "concerns", "fail"
@@ -74,6 +119,14 @@ type PhaseReview struct {
 	Timestamp      string `json:"timestamp"`
 }
 
+// UnblockSuggestion is one way out of a blocked run, proposed by the
+// executive chef.
+type UnblockSuggestion struct {
+	Kind       string `json:"kind"` // "reorder", "relax_dependency", "retry" or "human_action"
+	Suggestion string `json:"suggestion"`
+	Timestamp  string `json:"timestamp"`
+}
+
 // SessionFailure tracks failures across tasks in a session for cross-task learning.
 type SessionFailure struct {
 	TaskID    string `json:"taskId"`
@@ -82,21 +135,120 @@ type SessionFailure struct {
 	Timestamp string `json:"timestamp"`
 }
 
+// VerificationRun records one verification run of a task's commands.
+type VerificationRun struct {
+	TaskID    string   `json:"taskId"`
+	Passed    bool     `json:"passed"`
+	Duration  int      `json:"duration"`         // Duration in milliseconds
+	Failed    []string `json:"failed,omitempty"` // Commands that failed
+	Flaky     []string `json:"flaky,omitempty"`  // Commands that flipped without a code change
+	Tree      string   `json:"tree,omitempty"`   // Fingerprint of the code that was verified
+	Timestamp string   `json:"timestamp"`
+}
+
+// VerificationFailure records a verification command that failed on a
+// task's latest completion attempt.
+type VerificationFailure struct {
+	TaskID    string `json:"taskId"`
+	Command   string `json:"command"`
+	ExitCode  int    `json:"exitCode"`
+	Output    string `json:"output,omitempty"` // Tail of the command output
+	Log       bool   `json:"log,omitempty"`    // Command names a service whose log Output holds
+	Timestamp string `json:"timestamp"`
+}
+
+// RiskAcceptance records an operator starting a service despite the PRD's
+// risk level meeting RISK_WARN_THRESHOLD.
+type RiskAcceptance struct {
+	Level      string   `json:"level"`
+	Score      int      `json:"score"`
+	Threshold  string   `json:"threshold"`
+	Issues     []string `json:"issues,omitempty"`
+	AcceptedBy string   `json:"acceptedBy"` // "prompt" or "--accept-risk"
+	User       string   `json:"user,omitempty"`
+	Timestamp  string   `json:"timestamp"`
+}
+
+// CredentialFailure records a worker credential being set aside: until a
+// rate limit resets, or for the rest of the run when it was rejected.
+type CredentialFailure struct {
+	Tier       WorkerTier `json:"tier"`
+	Credential string     `json:"credential"`
+	TaskID     string     `json:"taskId"`
+	Reason     string     `json:"reason"`          // "rate_limited" or "auth_failed"
+	Until      string     `json:"until,omitempty"` // When a rate limit resets
+	Timestamp  string     `json:"timestamp"`
+}
+
+// SamplingRun records the sampling a --deterministic run pinned its
+// workers to, so runs compared later are known to be comparable.
+type SamplingRun struct {
+	Seed        int64        `json:"seed"`
+	Temperature float64      `json:"temperature"`
+	Pinned      []WorkerTier `json:"pinned,omitempty"`   // Tiers whose workers took the sampling
+	Unpinned    []WorkerTier `json:"unpinned,omitempty"` // Tiers whose CLIs choose their own
+	Timestamp   string       `json:"timestamp"`
+}
+
+// RunReport is the kitchen report of one service run: where its time went,
+// how much rework it took, and what it cost.
+type RunReport struct {
+	DurationSeconds int                `json:"durationSeconds"`       // Wall time
+	TierSeconds     map[WorkerTier]int `json:"tierSeconds,omitempty"` // Worker time per tier
+	Iterations      int                `json:"iterations"`            // Worker attempts
+	Escalations     int                `json:"escalations"`
+	ReviewsPassed   int                `json:"reviewsPassed"`
+	ReviewsFailed   int                `json:"reviewsFailed"`
+	ReviewsReused   int                `json:"reviewsReused,omitempty"` // Verdicts reused for a repeated diff
+	EstimatedCost   float64            `json:"estimatedCost"`
+	ErrorCategories map[string]int     `json:"errorCategories,omitempty"` // Failed attempts per category
+	Completed       int                `json:"completed"`                 // Tasks completed by this run
+	Timestamp       string             `json:"timestamp"`
+}
+
+// maxRunReports is how many runs' kitchen reports the state file keeps.
+const maxRunReports = 50
+
 // State represents the execution state for a PRD.
 type State struct {
-	SessionID     string        `json:"sessionId"`
-	StartedAt     string        `json:"startedAt"`
-	LastStartTime string        `json:"lastStartTime"`
-	CurrentTask   string        `json:"currentTask,omitempty"`
-	TaskHistory   []TaskHistory `json:"taskHistory"`
-	Escalations   []Escalation  `json:"escalations"`
-	Reviews       []Review      `json:"reviews"`
-	Absorptions   []Absorption  `json:"absorptions"`
-	PhaseReviews  []PhaseReview `json:"phaseReviews,omitempty"`
+	SessionID          string        `json:"sessionId"`
+	StartedAt          string        `json:"startedAt"`
+	LastStartTime      string        `json:"lastStartTime"`
+	CurrentTask        string        `json:"currentTask,omitempty"`
+	CurrentTaskStarted string        `json:"currentTaskStarted,omitempty"` // When the current attempt began
+	ParentPRD          string        `json:"parentPrd,omitempty"`          // Parent PRD when this is an iteration
+	PRDRevision        int           `json:"prdRevision,omitempty"`        // PRD history version the state started from
+	TaskHistory        []TaskHistory `json:"taskHistory"`
+	Escalations        []Escalation  `json:"escalations"`
+	Reviews            []Review      `json:"reviews"`
+	Absorptions        []Absorption  `json:"absorptions"`
+	PhaseReviews       []PhaseReview `json:"phaseReviews,omitempty"`
+
+	// Completions waiting for (or given) a human spot-check
+	ReviewQueue []QueuedReview `json:"reviewQueue,omitempty"`
+
+	// What the executive proposed the last time a run ended blocked
+	UnblockSuggestions []UnblockSuggestion `json:"unblockSuggestions,omitempty"`
 
 	// Smart retry tracking
 	SessionFailures []SessionFailure `json:"sessionFailures,omitempty"`
 
+	// Verification runs, and the failed commands fed back into retry prompts
+	Verifications        []VerificationRun     `json:"verifications,omitempty"`
+	VerificationFailures []VerificationFailure `json:"verificationFailures,omitempty"`
+
+	// Risk acceptances, the audit trail for starting over RISK_WARN_THRESHOLD
+	RiskAcceptances []RiskAcceptance `json:"riskAcceptances,omitempty"`
+
+	// Worker credentials set aside on rate limits and rejections
+	CredentialFailures []CredentialFailure `json:"credentialFailures,omitempty"`
+
+	// Sampling each --deterministic run pinned
+	SamplingRuns []SamplingRun `json:"samplingRuns,omitempty"`
+
+	// Kitchen reports of the latest runs, oldest first
+	Runs []RunReport `json:"runs,omitempty"`
+
 	// Walkaway mode tracking
 	ConsecutiveSkips int `json:"consecutiveSkips,omitempty"`
 
@@ -128,11 +280,42 @@ func (s *State) UpdateLastStartTime() {
 // SetCurrentTask sets the current task being worked on.
 func (s *State) SetCurrentTask(taskID string) {
 	s.CurrentTask = taskID
+	s.CurrentTaskStarted = time.Now().Format(time.RFC3339)
 }
 
 // ClearCurrentTask clears the current task.
 func (s *State) ClearCurrentTask() {
 	s.CurrentTask = ""
+	s.CurrentTaskStarted = ""
+}
+
+// OrphanedTask returns the current task if its latest attempt never
+// recorded an outcome, i.e. Brigade exited while a worker was running or
+// while its attempt was being checked. Returns "" if there is no current
+// task or its attempt was recorded.
+func (s *State) OrphanedTask() string {
+	if s.CurrentTask == "" {
+		return ""
+	}
+	last := s.LastAttempt(s.CurrentTask)
+	if last == nil || last.Status == StatusInProgress {
+		return s.CurrentTask
+	}
+	if s.CurrentTaskStarted == "" {
+		// Older state files don't record when the attempt began
+		return ""
+	}
+	started, err := time.Parse(time.RFC3339, s.CurrentTaskStarted)
+	if err != nil {
+		return ""
+	}
+	// Timestamps are to the second, so an outcome recorded in the second the
+	// attempt started belongs to it
+	recorded, err := time.Parse(time.RFC3339, last.Timestamp)
+	if err == nil && !recorded.Before(started) {
+		return ""
+	}
+	return s.CurrentTask
 }
 
 // AddTaskHistory adds a task history entry.
@@ -143,6 +326,41 @@ func (s *State) AddTaskHistory(entry TaskHistory) {
 	s.TaskHistory = append(s.TaskHistory, entry)
 }
 
+// ResolveAttempt sets the outcome of a task's in-progress attempt.
+// Returns false if the task has no attempt awaiting an outcome.
+func (s *State) ResolveAttempt(taskID string, status TaskStatus, errMsg, category string) bool {
+	for i := len(s.TaskHistory) - 1; i >= 0; i-- {
+		h := &s.TaskHistory[i]
+		if h.TaskID != taskID {
+			continue
+		}
+		if h.Status != StatusInProgress {
+			return false
+		}
+		h.Status = status
+		h.Error = errMsg
+		h.Category = category
+		return true
+	}
+	return false
+}
+
+// SetArtifacts records the artifacts of a task's attempt, numbered from 1.
+func (s *State) SetArtifacts(taskID string, attempt int, dir string, files []string) {
+	n := 0
+	for i := range s.TaskHistory {
+		h := &s.TaskHistory[i]
+		if h.TaskID != taskID {
+			continue
+		}
+		if n++; n == attempt {
+			h.ArtifactsDir = dir
+			h.Artifacts = files
+			return
+		}
+	}
+}
+
 // AddEscalation records an escalation.
 func (s *State) AddEscalation(taskID string, from, to WorkerTier, reason string) {
 	s.Escalations = append(s.Escalations, Escalation{
@@ -156,12 +374,77 @@ func (s *State) AddEscalation(taskID string, from, to WorkerTier, reason string)
 
 // AddReview records a review result.
 func (s *State) AddReview(taskID, result, reason string) {
-	s.Reviews = append(s.Reviews, Review{
-		TaskID:    taskID,
-		Result:    result,
-		Reason:    reason,
-		Timestamp: time.Now().Format(time.RFC3339),
-	})
+	s.RecordReview(Review{TaskID: taskID, Result: result, Reason: reason})
+}
+
+// RecordReview adds a review, stamped with the current time.
+func (s *State) RecordReview(r Review) {
+	r.Timestamp = time.Now().Format(time.RFC3339)
+	s.Reviews = append(s.Reviews, r)
+}
+
+// ReviewOfDiff returns the latest review that judged a task's changes with
+// the given hash, rather than reusing another's verdict, or nil if there
+// is none.
+func (s *State) ReviewOfDiff(taskID, diff string) *Review {
+	for i := len(s.Reviews) - 1; i >= 0; i-- {
+		r := &s.Reviews[i]
+		if r.TaskID == taskID && r.Diff == diff && !r.Reused {
+			return r
+		}
+	}
+	return nil
+}
+
+// QueueHumanReview flags a completed task for a human spot-check,
+// replacing any earlier entry for it.
+func (s *State) QueueHumanReview(q QueuedReview) {
+	if q.Timestamp == "" {
+		q.Timestamp = time.Now().Format(time.RFC3339)
+	}
+	q.Status = HumanReviewPending
+	for i := range s.ReviewQueue {
+		if s.ReviewQueue[i].TaskID == q.TaskID {
+			s.ReviewQueue[i] = q
+			return
+		}
+	}
+	s.ReviewQueue = append(s.ReviewQueue, q)
+}
+
+// QueuedReview returns the review queue entry for a task, or nil.
+func (s *State) QueuedReview(taskID string) *QueuedReview {
+	for i := range s.ReviewQueue {
+		if s.ReviewQueue[i].TaskID == taskID {
+			return &s.ReviewQueue[i]
+		}
+	}
+	return nil
+}
+
+// PendingHumanReviews returns the review queue entries awaiting a human.
+func (s *State) PendingHumanReviews() []QueuedReview {
+	var pending []QueuedReview
+	for _, q := range s.ReviewQueue {
+		if q.Status == HumanReviewPending {
+			pending = append(pending, q)
+		}
+	}
+	return pending
+}
+
+// ResolveHumanReview records a human's verdict on a queued review. Returns
+// false if the task isn't in the queue.
+func (s *State) ResolveHumanReview(taskID, status, note, remediation string) bool {
+	q := s.QueuedReview(taskID)
+	if q == nil {
+		return false
+	}
+	q.Status = status
+	q.Note = note
+	q.Remediation = remediation
+	q.ResolvedAt = time.Now().Format(time.RFC3339)
+	return true
 }
 
 // AddAbsorption records a task absorption.
@@ -174,8 +457,9 @@ func (s *State) AddAbsorption(taskID, absorbedBy string) {
 }
 
 // AddPhaseReview records a phase review.
-func (s *State) AddPhaseReview(completed, total int, status, content string) {
+func (s *State) AddPhaseReview(phase, completed, total int, status, content string) {
 	s.PhaseReviews = append(s.PhaseReviews, PhaseReview{
+		Phase:          phase,
 		CompletedTasks: completed,
 		TotalTasks:     total,
 		Status:         status,
@@ -184,6 +468,18 @@ func (s *State) AddPhaseReview(completed, total int, status, content string) {
 	})
 }
 
+// SetUnblockSuggestions replaces the suggestions from an earlier blocked
+// run.
+func (s *State) SetUnblockSuggestions(suggestions []UnblockSuggestion) {
+	now := time.Now().Format(time.RFC3339)
+	for i := range suggestions {
+		if suggestions[i].Timestamp == "" {
+			suggestions[i].Timestamp = now
+		}
+	}
+	s.UnblockSuggestions = suggestions
+}
+
 // AddSessionFailure records a failure for cross-task learning.
 func (s *State) AddSessionFailure(taskID, category, errorMsg string, maxFailures int) {
 	s.SessionFailures = append(s.SessionFailures, SessionFailure{
@@ -199,6 +495,109 @@ func (s *State) AddSessionFailure(taskID, category, errorMsg string, maxFailures
 	}
 }
 
+// AddVerification records a verification run.
+func (s *State) AddVerification(run VerificationRun) {
+	run.Timestamp = time.Now().Format(time.RFC3339)
+	s.Verifications = append(s.Verifications, run)
+}
+
+// AddRiskAcceptance records that a risky run was allowed to start.
+func (s *State) AddRiskAcceptance(a RiskAcceptance) {
+	a.Timestamp = time.Now().Format(time.RFC3339)
+	s.RiskAcceptances = append(s.RiskAcceptances, a)
+}
+
+// AddSamplingRun records the sampling a deterministic run started with.
+func (s *State) AddSamplingRun(r SamplingRun) {
+	r.Timestamp = time.Now().Format(time.RFC3339)
+	s.SamplingRuns = append(s.SamplingRuns, r)
+}
+
+// AddRunReport records a run's kitchen report, dropping the oldest beyond
+// the last maxRunReports.
+func (s *State) AddRunReport(r RunReport) {
+	r.Timestamp = time.Now().Format(time.RFC3339)
+	s.Runs = append(s.Runs, r)
+	if len(s.Runs) > maxRunReports {
+		s.Runs = s.Runs[len(s.Runs)-maxRunReports:]
+	}
+}
+
+// AddCredentialFailure records that a worker credential was set aside.
+func (s *State) AddCredentialFailure(f CredentialFailure) {
+	f.Timestamp = time.Now().Format(time.RFC3339)
+	s.CredentialFailures = append(s.CredentialFailures, f)
+}
+
+// LastVerification returns a task's most recent verification run of the
+// code with the given fingerprint, or nil if there is none.
+func (s *State) LastVerification(taskID, tree string) *VerificationRun {
+	for i := len(s.Verifications) - 1; i >= 0; i-- {
+		v := &s.Verifications[i]
+		if v.TaskID == taskID && v.Tree == tree {
+			return v
+		}
+	}
+	return nil
+}
+
+// FlakyCommands counts how often each command was caught flipping between
+// pass and fail, by task.
+func (s *State) FlakyCommands() map[string]map[string]int {
+	flaky := make(map[string]map[string]int)
+	for _, v := range s.Verifications {
+		for _, cmd := range v.Flaky {
+			if flaky[v.TaskID] == nil {
+				flaky[v.TaskID] = make(map[string]int)
+			}
+			flaky[v.TaskID][cmd]++
+		}
+	}
+	return flaky
+}
+
+// VerificationStats counts passed and failed verification runs.
+func (s *State) VerificationStats() (passed, failed int) {
+	for _, v := range s.Verifications {
+		if v.Passed {
+			passed++
+		} else {
+			failed++
+		}
+	}
+	return passed, failed
+}
+
+// SetVerificationFailures replaces a task's recorded verification failures
+// with those from its latest run. An empty list clears them.
+func (s *State) SetVerificationFailures(taskID string, failures []VerificationFailure) {
+	kept := s.VerificationFailures[:0]
+	for _, f := range s.VerificationFailures {
+		if f.TaskID != taskID {
+			kept = append(kept, f)
+		}
+	}
+	now := time.Now().Format(time.RFC3339)
+	for _, f := range failures {
+		f.TaskID = taskID
+		f.Timestamp = now
+		kept = append(kept, f)
+	}
+	s.VerificationFailures = kept
+}
+
+// GetVerificationFailures returns the verification commands that failed on
+// a task's latest run.
+func (s *State) GetVerificationFailures(taskID string) []VerificationFailure {
+	var failures []VerificationFailure
+	for _, f := range s.VerificationFailures {
+		if f.TaskID == taskID {
+			failures = append(failures, f)
+		}
+	}
+	return failures
+}
+
 // CompletedTaskIDs returns a set of completed task IDs.
 func (s *State) CompletedTaskIDs() map[string]bool {
 	completed := make(map[string]bool)
@@ -214,6 +613,26 @@ func (s *State) CompletedTaskIDs() map[string]bool {
 	return completed
 }
 
+// SkippedTaskIDs returns the tasks whose latest outcome is a skip, in the
+// order they were first attempted.
+func (s *State) SkippedTaskIDs() []string {
+	latest := make(map[string]TaskStatus)
+	var order []string
+	for _, h := range s.TaskHistory {
+		if _, seen := latest[h.TaskID]; !seen {
+			order = append(order, h.TaskID)
+		}
+		latest[h.TaskID] = h.Status
+	}
+	var skipped []string
+	for _, id := range order {
+		if latest[id] == StatusSkipped {
+			skipped = append(skipped, id)
+		}
+	}
+	return skipped
+}
+
 // AttemptsAtTier returns the number of attempts for a task at a specific tier.
 func (s *State) AttemptsAtTier(taskID string, tier WorkerTier) int {
 	count := 0
@@ -236,6 +655,32 @@ func (s *State) TotalAttempts(taskID string) int {
 	return count
 }
 
+// ErrorCategories returns the error categories a task's attempts failed
+// with, each once, in the order first seen. It's empty, not nil, when none
+// were classified.
+func (s *State) ErrorCategories(taskID string) []string {
+	categories := []string{}
+	seen := make(map[string]bool)
+	for _, h := range s.TaskHistory {
+		if h.TaskID == taskID && h.Category != "" && !seen[h.Category] {
+			seen[h.Category] = true
+			categories = append(categories, h.Category)
+		}
+	}
+	return categories
+}
+
+// LastError returns the error of a task's most recent attempt that
+// recorded one, or "" if none did.
+func (s *State) LastError(taskID string) string {
+	for i := len(s.TaskHistory) - 1; i >= 0; i-- {
+		if h := s.TaskHistory[i]; h.TaskID == taskID && h.Error != "" {
+			return h.Error
+		}
+	}
+	return ""
+}
+
 // LastAttempt returns the most recent attempt for a task, or nil if none.
 func (s *State) LastAttempt(taskID string) *TaskHistory {
 	for i := len(s.TaskHistory) - 1; i >= 0; i-- {
@@ -246,17 +691,34 @@ func (s *State) LastAttempt(taskID string) *TaskHistory {
 	return nil
 }
 
-// GetApproachHistory returns previous approaches tried for a task.
+// GetApproachHistory returns previous approaches tried for a task. Each
+// entry that restates an earlier attempt's approach (see
+// classify.SimilarApproaches) points at the first attempt that tried it.
 func (s *State) GetApproachHistory(taskID string, maxApproaches int) []ApproachEntry {
 	var approaches []ApproachEntry
+	attempt := 0
 	for _, h := range s.TaskHistory {
-		if h.TaskID == taskID && h.Approach != "" {
-			approaches = append(approaches, ApproachEntry{
-				Worker:   h.Worker,
-				Approach: h.Approach,
-				Category: h.Category,
-			})
+		if h.TaskID != taskID {
+			continue
 		}
+		attempt++
+		if h.Approach == "" {
+			continue
+		}
+		entry := ApproachEntry{
+			Worker:   h.Worker,
+			Approach: h.Approach,
+			Category: h.Category,
+			Attempt:  attempt,
+		}
+		for _, prev := range approaches {
+			if classify.SimilarApproaches(prev.Approach, h.Approach) {
+				entry.RepeatOf = prev.Attempt
+				entry.RepeatCategory = prev.Category
+				break
+			}
+		}
+		approaches = append(approaches, entry)
 	}
 
 	// Return most recent approaches up to max
@@ -272,6 +734,11 @@ type ApproachEntry struct {
 	Worker   WorkerTier
 	Approach string
 	Category string // Error category from the attempt
+	Attempt  int    // 1-based attempt number on the task
+
+	// The earlier attempt this one repeated, and how that one failed
+	RepeatOf       int
+	RepeatCategory string
 }
 
 // WasEscalated returns true if a task was escalated.
@@ -7,6 +7,8 @@ import (
 	"os"
 	"path/filepath"
 	"time"
+
+	"brigade/internal/module"
 )
 
 // Action represents a decision action.
@@ -17,14 +19,36 @@ const (
 	ActionSkip  Action = "skip"
 	ActionAbort Action = "abort"
 	ActionPause Action = "pause"
+
+	// Control actions, sent without a decision ID to steer a running service.
+	ActionResume      Action = "resume"
+	ActionSetParallel Action = "set_parallel"
 )
 
 // Command represents a command from a supervisor.
 type Command struct {
-	Decision string `json:"decision"` // Decision ID this responds to
-	Action   Action `json:"action"`   // retry, skip, abort, pause
+	Decision string `json:"decision"` // Decision ID this responds to (empty for control commands)
+	Action   Action `json:"action"`   // retry, skip, abort, pause, resume, set_parallel
 	Reason   string `json:"reason,omitempty"`
 	Guidance string `json:"guidance,omitempty"` // Optional guidance for retry
+	Value    int    `json:"value,omitempty"`    // Argument for set_parallel
+}
+
+// IsControl returns true if the command steers the service rather than
+// answering a decision.
+func (c *Command) IsControl() bool {
+	return c.Decision == ""
+}
+
+// TimeoutError is returned when no response to a decision arrives within
+// SUPERVISOR_CMD_TIMEOUT.
+type TimeoutError struct {
+	DecisionID string
+	After      time.Duration
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("no response to decision %s within %s", e.DecisionID, e.After)
 }
 
 // CommandReader reads commands from a supervisor.
@@ -59,21 +83,40 @@ func (r *CommandReader) Path() string {
 	return r.path
 }
 
-// Read reads and removes a command from the file.
+// Read reads and removes a command from the file. The file is moved aside
+// before it's read, so a command written meanwhile is left for the next
+// read rather than removed unseen.
 func (r *CommandReader) Read() (*Command, error) {
 	path := r.Path()
+	claimed := path + ".claimed"
 
-	data, err := os.ReadFile(path)
-	if err != nil {
+	if err := os.Rename(path, claimed); err != nil {
 		if os.IsNotExist(err) {
 			return nil, nil
 		}
 		return nil, err
 	}
+	data, err := os.ReadFile(claimed)
+	os.Remove(claimed)
+	if err != nil {
+		return nil, err
+	}
+	return parseCommand(data)
+}
 
-	// Remove the file immediately after reading
-	os.Remove(path)
+// peek reads the pending command without taking it.
+func (r *CommandReader) peek() (*Command, error) {
+	data, err := os.ReadFile(r.Path())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return parseCommand(data)
+}
 
+func parseCommand(data []byte) (*Command, error) {
 	if len(data) == 0 {
 		return nil, nil
 	}
@@ -86,17 +129,19 @@ func (r *CommandReader) Read() (*Command, error) {
 	return &cmd, nil
 }
 
-// WaitForCommand polls for a command with the specified decision ID.
+// WaitForCommand polls for a command with the specified decision ID,
+// returning a *TimeoutError if none arrives within the timeout.
 func (r *CommandReader) WaitForCommand(ctx context.Context, decisionID string) (*Command, error) {
 	if r.path == "" {
 		return nil, fmt.Errorf("no command file configured")
 	}
 
 	// Apply timeout
-	var cancel context.CancelFunc
+	var timeout <-chan time.Time
 	if r.timeout > 0 {
-		ctx, cancel = context.WithTimeout(ctx, r.timeout)
-		defer cancel()
+		timer := time.NewTimer(r.timeout)
+		defer timer.Stop()
+		timeout = timer.C
 	}
 
 	ticker := time.NewTicker(r.pollInterval)
@@ -106,6 +151,8 @@ func (r *CommandReader) WaitForCommand(ctx context.Context, decisionID string) (
 		select {
 		case <-ctx.Done():
 			return nil, ctx.Err()
+		case <-timeout:
+			return nil, &TimeoutError{DecisionID: decisionID, After: r.timeout}
 		case <-ticker.C:
 			cmd, err := r.Read()
 			if err != nil {
@@ -120,12 +167,63 @@ func (r *CommandReader) WaitForCommand(ctx context.Context, decisionID string) (
 				return cmd, nil
 			}
 
-			// Wrong decision ID - put it back (this is a race condition but acceptable)
-			r.writeCommand(cmd)
+			// Wrong decision ID - put it back for whoever is waiting for it
+			r.putBack(cmd)
 		}
 	}
 }
 
+// Send writes a command for the service to pick up.
+func (r *CommandReader) Send(cmd *Command) error {
+	if r.path == "" {
+		return fmt.Errorf("no command file configured")
+	}
+	return r.writeCommand(cmd)
+}
+
+// ReadControl reads a pending control command, leaving decision responses
+// in place for WaitForCommand. Returns nil if no control command is waiting.
+func (r *CommandReader) ReadControl() (*Command, error) {
+	if r.path == "" {
+		return nil, nil
+	}
+	// Look first, so a decision response is never taken off the file
+	cmd, err := r.peek()
+	if err != nil || cmd == nil || !cmd.IsControl() {
+		return nil, err
+	}
+	cmd, err = r.Read()
+	if err != nil || cmd == nil {
+		return nil, err
+	}
+	if !cmd.IsControl() { // Replaced between the look and the read
+		r.putBack(cmd)
+		return nil, nil
+	}
+	return cmd, nil
+}
+
+// putBack returns a command that was read by mistake to the file, unless
+// another command has been written there since; that one is newer and
+// wins.
+func (r *CommandReader) putBack(cmd *Command) error {
+	path := r.Path()
+	data, err := json.Marshal(cmd)
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	defer os.Remove(tmp)
+	// Link fails rather than replace a file that exists
+	if err := os.Link(tmp, path); err != nil && !os.IsExist(err) {
+		return err
+	}
+	return nil
+}
+
 // writeCommand writes a command back to the file.
 func (r *CommandReader) writeCommand(cmd *Command) error {
 	path := r.Path()
@@ -183,6 +281,7 @@ type Supervisor struct {
 	status   *StatusWriter
 	events   *EventWriter
 	commands *CommandReader
+	publish  func(*module.Event) // Where decision events go; the events file if nil
 }
 
 // NewSupervisor creates a new supervisor integration.
@@ -194,6 +293,21 @@ func NewSupervisor(statusPath, eventsPath, cmdPath, prdPrefix string, scopeByPRD
 	}
 }
 
+// SetPublisher sends decision events to publish instead of straight to the
+// events file, for a service whose event bus writes the file.
+func (s *Supervisor) SetPublisher(publish func(*module.Event)) {
+	s.publish = publish
+}
+
+// emit publishes a decision event.
+func (s *Supervisor) emit(event *module.Event) {
+	if s.publish != nil {
+		s.publish(event)
+		return
+	}
+	s.events.Write(event)
+}
+
 // Status returns the status writer.
 func (s *Supervisor) Status() *StatusWriter {
 	return s.status
@@ -232,10 +346,7 @@ func (s *Supervisor) RequestDecision(ctx context.Context, taskID, question strin
 
 	decisionID := GenerateDecisionID()
 
-	// Write decision_needed event
-	if s.events.Enabled() {
-		s.events.WriteDecisionNeeded("", taskID, decisionID, question)
-	}
+	s.emit(module.DecisionNeededEvent(s.events.prdPrefix, taskID, decisionID, question))
 
 	// Wait for response
 	cmd, err := s.commands.WaitForCommand(ctx, decisionID)
@@ -243,9 +354,8 @@ func (s *Supervisor) RequestDecision(ctx context.Context, taskID, question strin
 		return nil, err
 	}
 
-	// Write decision_received event
-	if s.events.Enabled() && cmd != nil {
-		s.events.WriteDecisionReceived("", taskID, decisionID, string(cmd.Action), cmd.Reason)
+	if cmd != nil {
+		s.emit(module.DecisionReceivedEvent(s.events.prdPrefix, taskID, decisionID, string(cmd.Action), cmd.Reason))
 	}
 
 	return cmd, nil
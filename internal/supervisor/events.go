@@ -1,6 +1,7 @@
 package supervisor
 
 import (
+	"bufio"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -207,6 +208,66 @@ func (w *EventWriter) Clear() error {
 	return nil
 }
 
+// PendingDecision is a decision_needed event with no decision_received yet.
+type PendingDecision struct {
+	ID        string `json:"decisionId"`
+	PRD       string `json:"prd,omitempty"`
+	TaskID    string `json:"taskId"`
+	Question  string `json:"question"`
+	Timestamp string `json:"timestamp"`
+}
+
+// PendingDecisions reads the event file for decisions still awaiting an
+// answer, oldest first.
+func (w *EventWriter) PendingDecisions() ([]PendingDecision, error) {
+	f, err := os.Open(w.Path())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var pending []PendingDecision
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var event module.Event
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			continue
+		}
+		id := fmt.Sprint(event.Data["decisionId"])
+		switch event.Type {
+		case module.EventDecisionNeeded:
+			pending = append(pending, PendingDecision{
+				ID:        id,
+				PRD:       event.PRD,
+				TaskID:    event.TaskID,
+				Question:  fmt.Sprint(event.Data["question"]),
+				Timestamp: event.Timestamp,
+			})
+		case module.EventDecisionReceived:
+			for i, d := range pending {
+				if d.ID == id {
+					pending = append(pending[:i], pending[i+1:]...)
+					break
+				}
+			}
+		case module.EventServiceStart, module.EventServiceComplete:
+			// Nothing is waiting on decisions from an earlier run
+			kept := pending[:0]
+			for _, d := range pending {
+				if d.PRD != event.PRD {
+					kept = append(kept, d)
+				}
+			}
+			pending = kept
+		}
+	}
+	return pending, scanner.Err()
+}
+
 // Enabled returns true if the event writer is enabled.
 func (w *EventWriter) Enabled() bool {
 	return w.path != ""
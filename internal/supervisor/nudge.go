@@ -0,0 +1,164 @@
+package supervisor
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// A nudge queue's lock is only held for one read and rewrite, so one older
+// than nudgeLockStale was left by a process that died holding it.
+const (
+	nudgeLockTimeout = 10 * time.Second
+	nudgeLockStale   = 30 * time.Second
+)
+
+// Nudge is guidance from a human for a task's worker.
+type Nudge struct {
+	TaskID    string `json:"taskId"`
+	Message   string `json:"message"`
+	Now       bool   `json:"now,omitempty"` // Restart the running attempt to deliver it
+	Timestamp string `json:"timestamp"`
+}
+
+// NudgeQueue holds nudges until the task's next prompt picks them up. It is
+// a JSONL file next to the PRD, so any process can add to it; adds and takes
+// hold a lock on it so neither loses the other's nudges.
+type NudgeQueue struct {
+	path string
+	mu   sync.Mutex // Orders this process's adds and takes ahead of the lock file
+}
+
+// NewNudgeQueue creates a queue backed by path.
+func NewNudgeQueue(path string) *NudgeQueue {
+	return &NudgeQueue{path: path}
+}
+
+// Add queues a nudge.
+func (q *NudgeQueue) Add(n Nudge) error {
+	if n.Timestamp == "" {
+		n.Timestamp = time.Now().UTC().Format(time.RFC3339)
+	}
+	data, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+	return q.locked(func() error {
+		f, err := os.OpenFile(q.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = f.Write(append(data, '\n'))
+		return err
+	})
+}
+
+// Urgent reports whether a nudge asking for a restart is waiting for the task.
+func (q *NudgeQueue) Urgent(taskID string) bool {
+	nudges, _ := q.read()
+	for _, n := range nudges {
+		if n.TaskID == taskID && n.Now {
+			return true
+		}
+	}
+	return false
+}
+
+// Take removes and returns the nudges waiting for a task, oldest first.
+func (q *NudgeQueue) Take(taskID string) ([]Nudge, error) {
+	var taken []Nudge
+	err := q.locked(func() error {
+		nudges, err := q.read()
+		if err != nil || len(nudges) == 0 {
+			return err
+		}
+
+		var kept []Nudge
+		for _, n := range nudges {
+			if n.TaskID == taskID {
+				taken = append(taken, n)
+			} else {
+				kept = append(kept, n)
+			}
+		}
+		if len(taken) == 0 {
+			return nil
+		}
+
+		if len(kept) == 0 {
+			return os.Remove(q.path)
+		}
+		var buf bytes.Buffer
+		for _, n := range kept {
+			data, _ := json.Marshal(n)
+			buf.Write(append(data, '\n'))
+		}
+		// Replace the file whole, so Urgent never reads it half-written
+		tmp := q.path + ".tmp"
+		if err := os.WriteFile(tmp, buf.Bytes(), 0644); err != nil {
+			return err
+		}
+		return os.Rename(tmp, q.path)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return taken, nil
+}
+
+// locked runs fn holding the queue's lock file.
+func (q *NudgeQueue) locked(fn func() error) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	lock := q.path + ".lock"
+	deadline := time.Now().Add(nudgeLockTimeout)
+	for {
+		f, err := os.OpenFile(lock, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			f.Close()
+			break
+		}
+		if !os.IsExist(err) {
+			return err
+		}
+		if info, err := os.Stat(lock); err == nil && time.Since(info.ModTime()) > nudgeLockStale {
+			os.Remove(lock)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("nudge queue still locked after %v: %s", nudgeLockTimeout, lock)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	defer os.Remove(lock)
+
+	return fn()
+}
+
+// read loads every queued nudge.
+func (q *NudgeQueue) read() ([]Nudge, error) {
+	f, err := os.Open(q.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var nudges []Nudge
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var n Nudge
+		if err := json.Unmarshal(scanner.Bytes(), &n); err == nil && n.Message != "" {
+			nudges = append(nudges, n)
+		}
+	}
+	return nudges, scanner.Err()
+}
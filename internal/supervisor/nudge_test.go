@@ -0,0 +1,79 @@
+package supervisor
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNudgeQueueConcurrent(t *testing.T) {
+	q := NewNudgeQueue(filepath.Join(t.TempDir(), "nudges.jsonl"))
+	tasks := []string{"US-001", "US-002"}
+	const perTask = 1000
+
+	var adders sync.WaitGroup
+	for _, id := range tasks {
+		adders.Add(1)
+		go func(id string) {
+			defer adders.Done()
+			for i := 0; i < perTask; i++ {
+				if err := q.Add(Nudge{TaskID: id, Message: fmt.Sprintf("%s #%d", id, i)}); err != nil {
+					t.Errorf("Add() error = %v", err)
+				}
+			}
+		}(id)
+	}
+
+	var mu sync.Mutex
+	got := make(map[string]int)
+	take := func(id string) {
+		nudges, err := q.Take(id)
+		if err != nil {
+			t.Errorf("Take(%s) error = %v", id, err)
+		}
+		mu.Lock()
+		for _, n := range nudges {
+			if n.TaskID != id {
+				t.Errorf("Take(%s) returned a nudge for %s", id, n.TaskID)
+			}
+			got[n.Message]++
+		}
+		mu.Unlock()
+	}
+
+	done := make(chan struct{})
+	var takers sync.WaitGroup
+	for _, id := range tasks {
+		takers.Add(1)
+		go func(id string) {
+			defer takers.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+					take(id)
+					time.Sleep(100 * time.Microsecond)
+				}
+			}
+		}(id)
+	}
+
+	adders.Wait()
+	close(done)
+	takers.Wait()
+	for _, id := range tasks {
+		take(id)
+	}
+
+	for _, id := range tasks {
+		for i := 0; i < perTask; i++ {
+			msg := fmt.Sprintf("%s #%d", id, i)
+			if got[msg] != 1 {
+				t.Errorf("nudge %q taken %d times, want 1", msg, got[msg])
+			}
+		}
+	}
+}
@@ -6,17 +6,46 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"sync"
 	"time"
+
+	"brigade/internal/module"
 )
 
-// Status represents the compact status for supervisor polling.
+// StatusVersion is the version of the Status format. It changes only when
+// a field is renamed, removed or changes meaning; fields may be added
+// without a new version.
+const StatusVersion = 1
+
+// Status is the compact status written for supervisor polling and printed
+// by `status --brief`. It is a stable format for scripts (see
+// StatusVersion).
 type Status struct {
+	Version   int    `json:"version"`
+	PRD       string `json:"prd,omitempty"` // PRD prefix
 	Done      int    `json:"done"`
 	Total     int    `json:"total"`
 	Current   string `json:"current,omitempty"`
 	Worker    string `json:"worker,omitempty"`
 	Elapsed   int    `json:"elapsed,omitempty"` // Seconds since task started
 	Attention bool   `json:"attention"`
+	AttentionReason string `json:"attentionReason,omitempty"` // Latest reason a person is needed
+
+	// Projected total cost of the run in USD, updated as tasks finish
+	ProjectedCost float64 `json:"projectedCost,omitempty"`
+
+	// The run: its state session, PRD feature name and when it started
+	SessionID string `json:"sessionId,omitempty"`
+	PRDName   string `json:"prdName,omitempty"`
+	StartedAt string `json:"startedAt,omitempty"` // RFC 3339
+
+	ConsecutiveSkips int      `json:"consecutiveSkips"` // Tasks skipped in a row in walkaway mode
+	Running          []string `json:"running,omitempty"` // Tasks workers are on, several when parallel
+	LastEvent        string   `json:"lastEvent,omitempty"` // When the latest event other than a heartbeat was published
+	Paused           bool     `json:"paused"` // Paused by a supervisor command
+
+	Tasks []TaskStatus `json:"tasks"`
 }
 
 // StatusWriter writes status updates to a file.
@@ -24,6 +53,18 @@ type StatusWriter struct {
 	path        string
 	prdPrefix   string
 	scopeByPRD  bool
+
+	mu            sync.Mutex
+	projectedCost float64
+	attention     string
+	tasks         []TaskStatus
+	sessionID     string
+	prdName       string
+	startedAt     time.Time
+	skips         int
+	running       []string
+	lastEvent     string
+	paused        bool
 }
 
 // NewStatusWriter creates a new status writer.
@@ -61,14 +102,97 @@ func (w *StatusWriter) Write(status *Status) error {
 	return w.writeAtomic(data)
 }
 
+// SetProjectedCost sets the projected cost included in later writes.
+func (w *StatusWriter) SetProjectedCost(usd float64) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.projectedCost = usd
+}
+
+// SetAttention sets the reason a person is needed, included in later
+// writes until cleared with "".
+func (w *StatusWriter) SetAttention(reason string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.attention = reason
+}
+
+// SetTasks sets the task statuses included in later writes.
+func (w *StatusWriter) SetTasks(tasks []TaskStatus) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.tasks = tasks
+}
+
+// SetRun sets the run included in later writes: the state's session, the
+// PRD's feature name and when the service started.
+func (w *StatusWriter) SetRun(sessionID, prdName string, startedAt time.Time) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.sessionID, w.prdName, w.startedAt = sessionID, prdName, startedAt
+}
+
+// SetSkips sets the consecutive skip count included in later writes.
+func (w *StatusWriter) SetSkips(skips int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.skips = skips
+}
+
+// SetPaused sets whether a supervisor has paused the service.
+func (w *StatusWriter) SetPaused(paused bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.paused = paused
+}
+
+// Handle follows published events: the tasks workers start and finish, and
+// when the latest one happened. Subscribe it to the event bus.
+func (w *StatusWriter) Handle(event *module.Event) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if event.Type == module.EventHeartbeat {
+		return
+	}
+	w.lastEvent = event.Timestamp
+	switch event.Type {
+	case module.EventTaskStart:
+		if !slices.Contains(w.running, event.TaskID) {
+			w.running = append(w.running, event.TaskID)
+		}
+	case module.EventTaskIteration, module.EventTaskComplete, module.EventTaskAbsorbed, module.EventTaskSkipped:
+		w.running = slices.DeleteFunc(w.running, func(id string) bool { return id == event.TaskID })
+	}
+}
+
 // WriteProgress writes a progress status.
 func (w *StatusWriter) WriteProgress(done, total int, currentTask, worker string, taskStartTime time.Time, attention bool) error {
+	w.mu.Lock()
 	status := &Status{
+		Version:   StatusVersion,
+		PRD:       w.prdPrefix,
 		Done:      done,
 		Total:     total,
 		Current:   currentTask,
 		Worker:    worker,
-		Attention: attention,
+		Attention: attention || w.attention != "",
+		AttentionReason: w.attention,
+		ProjectedCost: w.projectedCost,
+		SessionID: w.sessionID,
+		PRDName:   w.prdName,
+		ConsecutiveSkips: w.skips,
+		Running:   slices.Clone(w.running),
+		LastEvent: w.lastEvent,
+		Paused:    w.paused,
+		Tasks:     w.tasks,
+	}
+	if !w.startedAt.IsZero() {
+		status.StartedAt = w.startedAt.Format(time.RFC3339)
+	}
+	w.mu.Unlock()
+	if status.Tasks == nil {
+		status.Tasks = []TaskStatus{}
 	}
 
 	if !taskStartTime.IsZero() {
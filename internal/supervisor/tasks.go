@@ -0,0 +1,81 @@
+package supervisor
+
+import (
+	"time"
+
+	"brigade/internal/prd"
+	"brigade/internal/state"
+)
+
+// Task statuses in a Status.
+const (
+	TaskPending        = "pending"
+	TaskInProgress     = "in_progress"
+	TaskComplete       = "complete"
+	TaskAwaitingReview = "awaiting_review" // Complete, queued for a human spot-check
+	TaskSkipped        = "skipped"
+)
+
+// TaskStatus is one task's entry in a Status.
+type TaskStatus struct {
+	ID         string   `json:"id"`
+	Status     string   `json:"status"`
+	Worker     string   `json:"worker"`     // Tier of the latest attempt, or the one the task starts on
+	Iterations int      `json:"iterations"` // Attempts so far
+	Escalated  bool     `json:"escalated"`
+	Elapsed    int      `json:"elapsed"`             // Seconds of worker time so far, including a running attempt
+	BlockedBy  []string `json:"blockedBy,omitempty"` // Skipped tasks it can't run without
+}
+
+// TaskStatuses returns the status of each of a PRD's tasks from its state.
+// A task is complete when the PRD says it passes.
+func TaskStatuses(p *prd.PRD, st *state.State) []TaskStatus {
+	iterations := make(map[string]int)
+	elapsed := make(map[string]int)
+	workers := make(map[string]state.WorkerTier)
+	for _, h := range st.TaskHistory {
+		iterations[h.TaskID]++
+		elapsed[h.TaskID] += h.Duration
+		workers[h.TaskID] = h.Worker
+	}
+	if started, err := time.Parse(time.RFC3339, st.CurrentTaskStarted); err == nil && st.CurrentTask != "" {
+		elapsed[st.CurrentTask] += int(time.Since(started).Seconds())
+	}
+	skipped := make(map[string]bool)
+	for _, id := range st.SkippedTaskIDs() {
+		skipped[id] = true
+	}
+	blockedBy := p.BlockedBy(st.SkippedTaskIDs())
+
+	tasks := make([]TaskStatus, 0, len(p.Tasks))
+	for _, task := range p.Tasks {
+		ts := TaskStatus{
+			ID:         task.ID,
+			Status:     TaskPending,
+			Worker:     string(state.TierLine),
+			Iterations: iterations[task.ID],
+			Escalated:  st.WasEscalated(task.ID),
+			Elapsed:    elapsed[task.ID],
+		}
+		if w, ok := workers[task.ID]; ok {
+			ts.Worker = string(w)
+		} else if task.Complexity == prd.ComplexitySenior {
+			ts.Worker = string(state.TierSous)
+		}
+
+		switch q := st.QueuedReview(task.ID); {
+		case task.Passes && q != nil && q.Status == state.HumanReviewPending:
+			ts.Status = TaskAwaitingReview
+		case task.Passes:
+			ts.Status = TaskComplete
+		case task.ID == st.CurrentTask:
+			ts.Status = TaskInProgress
+		case skipped[task.ID]:
+			ts.Status = TaskSkipped
+		default:
+			ts.BlockedBy = blockedBy[task.ID]
+		}
+		tasks = append(tasks, ts)
+	}
+	return tasks
+}
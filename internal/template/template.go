@@ -0,0 +1,197 @@
+// Package template handles PRD templates: variable manifests and interpolation.
+package template
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"brigade/internal/util"
+)
+
+// ManifestSuffix is the file suffix for a template's variable manifest,
+// stored next to the template (api.json -> api.manifest.json).
+const ManifestSuffix = ".manifest.json"
+
+// Variable describes a template variable.
+type Variable struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Default     string `json:"default,omitempty"`
+	Pattern     string `json:"pattern,omitempty"` // Validation regex
+}
+
+// Validate checks value against the variable's pattern.
+func (v *Variable) Validate(value string) error {
+	if v.Pattern == "" {
+		return nil
+	}
+	re, err := regexp.Compile(v.Pattern)
+	if err != nil {
+		return fmt.Errorf("variable %s: invalid pattern %q: %w", v.Name, v.Pattern, err)
+	}
+	if !re.MatchString(value) {
+		return fmt.Errorf("variable %s: %q does not match %s", v.Name, value, v.Pattern)
+	}
+	return nil
+}
+
+// Manifest declares the variables a template accepts.
+type Manifest struct {
+	Description string     `json:"description,omitempty"`
+	Variables   []Variable `json:"variables"`
+}
+
+// ManifestPath returns the manifest path for a template file.
+func ManifestPath(templatePath string) string {
+	return strings.TrimSuffix(templatePath, ".json") + ManifestSuffix
+}
+
+// IsManifest reports whether a file name is a manifest rather than a template.
+func IsManifest(name string) bool {
+	return strings.HasSuffix(name, ManifestSuffix)
+}
+
+// LoadManifest loads the manifest for a template. Returns nil, nil if the
+// template has none.
+func LoadManifest(templatePath string) (*Manifest, error) {
+	data, err := os.ReadFile(ManifestPath(templatePath))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("parsing manifest: %w", err)
+	}
+	return &m, nil
+}
+
+// Variable returns the declared variable with the given name, or nil.
+func (m *Manifest) Variable(name string) *Variable {
+	if m == nil {
+		return nil
+	}
+	for i := range m.Variables {
+		if m.Variables[i].Name == name {
+			return &m.Variables[i]
+		}
+	}
+	return nil
+}
+
+var placeholderPattern = regexp.MustCompile(`\{\{([A-Za-z][A-Za-z0-9_]*)\}\}`)
+
+// Placeholders returns the variable names referenced in content, sorted.
+// Case variants and _singular forms map to their base name
+// ({{Name}}, {{NAME}}, {{name_singular}} all reference "name"), and
+// camelCase names keep their case ({{dbType}} and {{DbType}} reference
+// "dbType").
+func Placeholders(content string) []string {
+	seen := make(map[string]bool)
+	for _, match := range placeholderPattern.FindAllStringSubmatch(content, -1) {
+		seen[baseName(match[1])] = true
+	}
+	// {{DBTYPE}} is the uppercase form of a camelCase name used elsewhere
+	for name := range seen {
+		if name != strings.ToLower(name) {
+			delete(seen, strings.ToLower(name))
+		}
+	}
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// baseName returns the variable a placeholder refers to: without its
+// _singular suffix, and with the first letter lowercased, or every letter
+// for the uppercase form.
+func baseName(placeholder string) string {
+	name := strings.TrimSuffix(strings.TrimSuffix(placeholder, "_singular"), "_SINGULAR")
+	if name == strings.ToUpper(name) {
+		return strings.ToLower(name)
+	}
+	return strings.ToLower(name[:1]) + name[1:]
+}
+
+// Interpolate replaces placeholders for each variable. Every variable supports
+// {{var}}, {{Var}} (capitalized), {{VAR}} (uppercase), and singular forms
+// {{var_singular}} / {{Var_singular}}.
+func Interpolate(content string, vars map[string]string) string {
+	for name, value := range vars {
+		singular := util.ToSingular(value)
+		content = strings.NewReplacer(
+			"{{"+name+"}}", value,
+			"{{"+util.ToCapitalized(name)+"}}", util.ToCapitalized(value),
+			"{{"+strings.ToUpper(name)+"}}", strings.ToUpper(value),
+			"{{"+name+"_singular}}", singular,
+			"{{"+util.ToCapitalized(name)+"_singular}}", util.ToCapitalized(singular),
+		).Replace(content)
+	}
+	return content
+}
+
+// Generalize turns resource-specific text into a template by replacing the
+// resource and its variants with {{name}} placeholders. Plural forms map to
+// {{name}}/{{Name}}/{{NAME}} and singular forms to {{name_singular}}/{{Name_singular}}.
+// Matches inside longer lowercase words ("users" in "username") are left alone.
+func Generalize(content, resource string) string {
+	resource = strings.ToLower(resource)
+	if resource == "" {
+		return content
+	}
+	singular := util.ToSingular(resource)
+
+	// Longest and most specific forms first so "users" isn't consumed as "user"
+	replacements := []struct{ word, placeholder string }{
+		{strings.ToUpper(resource), "{{NAME}}"},
+		{util.ToCapitalized(resource), "{{Name}}"},
+		{resource, "{{name}}"},
+	}
+	if singular != resource {
+		replacements = append(replacements,
+			struct{ word, placeholder string }{util.ToCapitalized(singular), "{{Name_singular}}"},
+			struct{ word, placeholder string }{singular, "{{name_singular}}"},
+		)
+	}
+
+	for _, r := range replacements {
+		content = replaceWord(content, r.word, r.placeholder)
+	}
+	return content
+}
+
+// replaceWord replaces occurrences of word that aren't embedded in a longer
+// lowercase identifier. A following uppercase letter (camelCase) is a boundary.
+func replaceWord(s, word, replacement string) string {
+	var sb strings.Builder
+	for {
+		i := strings.Index(s, word)
+		if i < 0 {
+			sb.WriteString(s)
+			return sb.String()
+		}
+		end := i + len(word)
+		before := i > 0 && isLowerAlnum(s[i-1])
+		after := end < len(s) && isLowerAlnum(s[end])
+		sb.WriteString(s[:i])
+		if before || after {
+			sb.WriteString(word)
+		} else {
+			sb.WriteString(replacement)
+		}
+		s = s[end:]
+	}
+}
+
+func isLowerAlnum(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9')
+}
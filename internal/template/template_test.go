@@ -0,0 +1,95 @@
+package template
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestPlaceholders(t *testing.T) {
+	content := `{"featureName": "{{Name}} via {{provider}}", "x": "{{NAME}} {{name_singular}} {{DB}}"}`
+	got := Placeholders(content)
+	want := []string{"db", "name", "provider"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Placeholders = %v, want %v", got, want)
+	}
+}
+
+func TestPlaceholdersCamelCase(t *testing.T) {
+	content := `{"x": "{{dbType}} {{DbType}} {{DBTYPE}} {{apiKey_singular}} {{PORT}}"}`
+	got := Placeholders(content)
+	want := []string{"apiKey", "dbType", "port"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Placeholders = %v, want %v", got, want)
+	}
+}
+
+func TestInterpolate(t *testing.T) {
+	content := "{{name}} {{Name}} {{NAME}} {{name_singular}} {{Name_singular}} {{provider}} {{Provider}}"
+	got := Interpolate(content, map[string]string{"name": "users", "provider": "oauth"})
+	want := "users Users USERS user User oauth Oauth"
+	if got != want {
+		t.Errorf("Interpolate = %q, want %q", got, want)
+	}
+}
+
+func TestVariableValidate(t *testing.T) {
+	v := &Variable{Name: "db", Pattern: "^(postgres|sqlite)$"}
+	if err := v.Validate("postgres"); err != nil {
+		t.Errorf("Validate(postgres) = %v, want nil", err)
+	}
+	if err := v.Validate("mongo"); err == nil {
+		t.Error("Validate(mongo) = nil, want error")
+	}
+}
+
+func TestLoadManifest(t *testing.T) {
+	dir := t.TempDir()
+	tmpl := filepath.Join(dir, "auth.json")
+
+	m, err := LoadManifest(tmpl)
+	if err != nil || m != nil {
+		t.Fatalf("LoadManifest without manifest = %v, %v; want nil, nil", m, err)
+	}
+
+	manifest := `{"variables": [{"name": "provider", "default": "jwt", "pattern": "^(jwt|oauth)$"}]}`
+	if err := os.WriteFile(ManifestPath(tmpl), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	m, err = LoadManifest(tmpl)
+	if err != nil {
+		t.Fatalf("LoadManifest failed: %v", err)
+	}
+	if v := m.Variable("provider"); v == nil || v.Default != "jwt" {
+		t.Errorf("Variable(provider) = %+v, want default jwt", v)
+	}
+	if !IsManifest(filepath.Base(ManifestPath(tmpl))) {
+		t.Error("IsManifest should match manifest file name")
+	}
+}
+
+func TestGeneralize(t *testing.T) {
+	tests := []struct {
+		content  string
+		resource string
+		want     string
+	}{
+		{"GET /users returns users", "users", "GET /{{name}} returns {{name}}"},
+		{"Create User model", "users", "Create {{Name_singular}} model"},
+		{"USERS table, Users API", "users", "{{NAME}} table, {{Name}} API"},
+		{"username stays, UserService changes", "users", "username stays, {{Name_singular}}Service changes"},
+		{"categories and category", "categories", "{{name}} and {{name_singular}}"},
+	}
+	for _, tt := range tests {
+		if got := Generalize(tt.content, tt.resource); got != tt.want {
+			t.Errorf("Generalize(%q, %q) = %q, want %q", tt.content, tt.resource, got, tt.want)
+		}
+	}
+
+	// Round trip through Interpolate
+	original := "Create User model for /users"
+	if got := Interpolate(Generalize(original, "users"), map[string]string{"name": "users"}); got != original {
+		t.Errorf("round trip = %q, want %q", got, original)
+	}
+}
@@ -0,0 +1,193 @@
+package triage
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"brigade/internal/prd"
+)
+
+// ReviewThreadsQuery is the GitHub GraphQL query for a pull request's review
+// threads, taking $owner, $name and $number.
+const ReviewThreadsQuery = `query($owner: String!, $name: String!, $number: Int!) {
+  repository(owner: $owner, name: $name) {
+    pullRequest(number: $number) {
+      title
+      url
+      headRefName
+      reviewThreads(first: 100) {
+        nodes {
+          isResolved
+          isOutdated
+          path
+          line
+          originalLine
+          comments(first: 50) {
+            nodes { author { login } body url diffHunk }
+          }
+        }
+      }
+    }
+  }
+}`
+
+// maxHunkLines caps the diff context a task quotes for a thread.
+const maxHunkLines = 8
+
+// PullRequest is a pull request and its unresolved review threads.
+type PullRequest struct {
+	Number  int
+	Title   string
+	URL     string
+	Branch  string
+	Threads []ReviewThread
+}
+
+// ReviewThread is a conversation on one line of a pull request's diff.
+type ReviewThread struct {
+	Path     string
+	Line     int
+	Outdated bool // The code has changed since the thread started
+	Comments []ReviewComment
+}
+
+// ReviewComment is one comment in a review thread.
+type ReviewComment struct {
+	Author   string
+	Body     string
+	URL      string
+	DiffHunk string
+}
+
+// ParsePullRequest reads the response to ReviewThreadsQuery, keeping the
+// unresolved threads.
+func ParsePullRequest(data []byte, number int) (*PullRequest, error) {
+	var resp struct {
+		Data struct {
+			Repository struct {
+				PullRequest *struct {
+					Title         string
+					URL           string
+					HeadRefName   string
+					ReviewThreads struct {
+						Nodes []struct {
+							IsResolved   bool
+							IsOutdated   bool
+							Path         string
+							Line         int
+							OriginalLine int
+							Comments     struct {
+								Nodes []struct {
+									Author   struct{ Login string }
+									Body     string
+									URL      string
+									DiffHunk string
+								}
+							}
+						}
+					}
+				}
+			}
+		}
+		Errors []struct{ Message string }
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, fmt.Errorf("parsing review threads: %w", err)
+	}
+	if len(resp.Errors) > 0 {
+		return nil, fmt.Errorf("GitHub: %s", resp.Errors[0].Message)
+	}
+	pr := resp.Data.Repository.PullRequest
+	if pr == nil {
+		return nil, fmt.Errorf("pull request #%d not found", number)
+	}
+
+	result := &PullRequest{Number: number, Title: pr.Title, URL: pr.URL, Branch: pr.HeadRefName}
+	for _, t := range pr.ReviewThreads.Nodes {
+		if t.IsResolved || len(t.Comments.Nodes) == 0 {
+			continue
+		}
+		thread := ReviewThread{Path: t.Path, Line: t.Line, Outdated: t.IsOutdated}
+		if thread.Line == 0 {
+			thread.Line = t.OriginalLine
+		}
+		for _, c := range t.Comments.Nodes {
+			thread.Comments = append(thread.Comments, ReviewComment{Author: c.Author.Login, Body: strings.TrimSpace(c.Body), URL: c.URL, DiffHunk: c.DiffHunk})
+		}
+		result.Threads = append(result.Threads, thread)
+	}
+	return result, nil
+}
+
+// PRCommentsPRD builds a draft PRD with one remediation task per unresolved
+// review thread.
+func PRCommentsPRD(pr *PullRequest) *prd.PRD {
+	p := &prd.PRD{
+		FeatureName: fmt.Sprintf("Address review comments on PR #%d", pr.Number),
+		BranchName:  pr.Branch,
+		Description: fmt.Sprintf("Generated by brigade import from %d unresolved review threads on %q (%s).", len(pr.Threads), pr.Title, pr.URL),
+	}
+	for i, t := range pr.Threads {
+		p.Tasks = append(p.Tasks, threadTask(fmt.Sprintf("US-%03d", i+1), t))
+	}
+	return p
+}
+
+// threadTask builds the remediation task for one review thread.
+func threadTask(id string, t ReviewThread) prd.Task {
+	loc := t.Path
+	if t.Line > 0 {
+		loc = fmt.Sprintf("%s:%d", t.Path, t.Line)
+	}
+	first := t.Comments[0]
+
+	var desc strings.Builder
+	desc.WriteString(fmt.Sprintf("A reviewer left a comment on %s that is still unresolved (%s). ", loc, first.URL))
+	desc.WriteString("Make the change it asks for; if it shouldn't be made, leave the code as it is and say why in your learnings.\n")
+	if t.Outdated {
+		desc.WriteString("The code has changed since the comment was made, so check it still applies.\n")
+	}
+	if hunk := hunkTail(first.DiffHunk); hunk != "" {
+		desc.WriteString("\nCode commented on:\n" + hunk + "\n")
+	}
+	desc.WriteString("\nThread:\n")
+	for _, c := range t.Comments {
+		desc.WriteString(fmt.Sprintf("- @%s: %s\n", c.Author, strings.ReplaceAll(c.Body, "\n", "\n  ")))
+	}
+
+	return prd.Task{
+		ID:          id,
+		Title:       fmt.Sprintf("Address review on %s: %s", loc, summarize(first.Body)),
+		Description: strings.TrimSpace(desc.String()),
+		AcceptanceCriteria: []string{
+			fmt.Sprintf("@%s's review comment at %s is addressed", first.Author, loc),
+			"Existing tests still pass",
+		},
+		DependsOn:  []string{},
+		Complexity: prd.ComplexityJunior,
+		Files:      []string{t.Path},
+	}
+}
+
+// hunkTail returns the last lines of a diff hunk, which end at the line
+// commented on.
+func hunkTail(hunk string) string {
+	lines := strings.Split(strings.TrimRight(hunk, "\n"), "\n")
+	if len(lines) > 0 && strings.HasPrefix(lines[0], "@@") {
+		lines = lines[1:]
+	}
+	if len(lines) > maxHunkLines {
+		lines = lines[len(lines)-maxHunkLines:]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// summarize returns the first line of a comment, shortened for a title.
+func summarize(body string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(body), "\n")
+	if len(line) > 60 {
+		line = line[:57] + "..."
+	}
+	return line
+}
@@ -0,0 +1,95 @@
+package triage
+
+import (
+	"strings"
+	"testing"
+)
+
+const reviewThreadsResponse = `{"data": {"repository": {"pullRequest": {
+  "title": "Add login", "url": "https://github.com/o/r/pull/7", "headRefName": "feature/login",
+  "reviewThreads": {"nodes": [
+    {"isResolved": true, "path": "auth.go", "line": 3,
+     "comments": {"nodes": [{"author": {"login": "ana"}, "body": "done already", "url": "u0"}]}},
+    {"isResolved": false, "isOutdated": false, "path": "auth.go", "line": 12,
+     "comments": {"nodes": [
+       {"author": {"login": "ana"}, "body": "Check the error here\nIt can be nil", "url": "u1",
+        "diffHunk": "@@ -1,3 +1,4 @@\n func login() {\n+\tuser, _ := find()"},
+       {"author": {"login": "ben"}, "body": "+1", "url": "u2"}]}},
+    {"isResolved": false, "isOutdated": true, "path": "db.go", "line": 0, "originalLine": 40,
+     "comments": {"nodes": [{"author": {"login": "ben"}, "body": "Close the rows", "url": "u3"}]}}
+  ]}}}}}`
+
+func TestParsePullRequest(t *testing.T) {
+	pr, err := ParsePullRequest([]byte(reviewThreadsResponse), 7)
+	if err != nil {
+		t.Fatalf("ParsePullRequest() error = %v", err)
+	}
+	if pr.Branch != "feature/login" || pr.Title != "Add login" {
+		t.Errorf("ParsePullRequest() = %q on %q, want \"Add login\" on \"feature/login\"", pr.Title, pr.Branch)
+	}
+	if len(pr.Threads) != 2 {
+		t.Fatalf("ParsePullRequest() kept %d threads, want the 2 unresolved", len(pr.Threads))
+	}
+	if got := len(pr.Threads[0].Comments); got != 2 {
+		t.Errorf("first thread has %d comments, want 2", got)
+	}
+	if got := pr.Threads[1]; got.Line != 40 || !got.Outdated {
+		t.Errorf("outdated thread line = %d, outdated = %v, want 40 and true", got.Line, got.Outdated)
+	}
+}
+
+func TestParsePullRequestErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"missing", `{"data": {"repository": {"pullRequest": null}}}`, "pull request #7 not found"},
+		{"graphql", `{"errors": [{"message": "Could not resolve to a Repository"}]}`, "GitHub: Could not resolve to a Repository"},
+		{"invalid", `not json`, "parsing review threads"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParsePullRequest([]byte(tt.data), 7)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("ParsePullRequest() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestPRCommentsPRD(t *testing.T) {
+	pr, err := ParsePullRequest([]byte(reviewThreadsResponse), 7)
+	if err != nil {
+		t.Fatalf("ParsePullRequest() error = %v", err)
+	}
+	p := PRCommentsPRD(pr)
+	if result := p.ValidateQuick(); !result.IsValid() {
+		t.Errorf("generated PRD is invalid: %v", result.Errors)
+	}
+	if p.BranchName != "feature/login" {
+		t.Errorf("branch = %q, want feature/login", p.BranchName)
+	}
+
+	task := p.Tasks[0]
+	if want := "Address review on auth.go:12: Check the error here"; task.Title != want {
+		t.Errorf("title = %q, want %q", task.Title, want)
+	}
+	if want := "@ana's review comment at auth.go:12 is addressed"; task.AcceptanceCriteria[0] != want {
+		t.Errorf("criterion = %q, want %q", task.AcceptanceCriteria[0], want)
+	}
+	for _, want := range []string{"u1", "+\tuser, _ := find()", "- @ben: +1", "- @ana: Check the error here\n  It can be nil"} {
+		if !strings.Contains(task.Description, want) {
+			t.Errorf("description missing %q:\n%s", want, task.Description)
+		}
+	}
+	if strings.Contains(task.Description, "@@") {
+		t.Errorf("description kept the hunk header:\n%s", task.Description)
+	}
+	if len(task.Files) != 1 || task.Files[0] != "auth.go" {
+		t.Errorf("files = %v, want [auth.go]", task.Files)
+	}
+	if !strings.Contains(p.Tasks[1].Description, "changed since the comment") {
+		t.Errorf("outdated thread not flagged:\n%s", p.Tasks[1].Description)
+	}
+}
@@ -0,0 +1,236 @@
+// Package triage turns the state of an existing codebase (failing tests,
+// leftover markers) into draft PRDs.
+package triage
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"brigade/internal/classify"
+	"brigade/internal/prd"
+)
+
+// maxFailureOutput caps the error output carried into a task description.
+const maxFailureOutput = 1500
+
+// maxCriteriaTests caps the per-test acceptance criteria for one task.
+const maxCriteriaTests = 8
+
+var (
+	goFailTest    = regexp.MustCompile(`^\s*--- FAIL: (\S+)`)
+	goFailPackage = regexp.MustCompile(`^FAIL\s+(\S+)\s`)
+	goBuildHeader = regexp.MustCompile(`^# (\S+)`)
+	goPackageOK   = regexp.MustCompile(`^(ok|FAIL|PASS|---|===|\?)\b`)
+	pytestFailed  = regexp.MustCompile(`^FAILED (\S+?)::(\S+)(?: - (.*))?$`)
+)
+
+// Failure is one failing test or build error.
+type Failure struct {
+	Package string // Go package, test file, or "" if unknown
+	Test    string // Test name, or "" for a build failure
+	Output  string
+}
+
+// Cluster is a group of failures likely to share a root cause.
+type Cluster struct {
+	Package  string
+	Category classify.Category
+	Failures []Failure
+}
+
+// ParseFailures extracts failures from test output. Go test and pytest
+// output are recognized; anything else becomes a single failure holding
+// the tail of the output.
+func ParseFailures(output string) []Failure {
+	var failures []Failure
+	var pending []int        // Go failures awaiting their package line
+	current, build := -1, -1 // Failures collecting output lines
+
+	for _, line := range strings.Split(output, "\n") {
+		if m := goFailTest.FindStringSubmatch(line); m != nil {
+			if strings.Contains(m[1], "/") {
+				// Subtest output is kept with its parent
+				continue
+			}
+			failures = append(failures, Failure{Test: m[1]})
+			current, build = len(failures)-1, -1
+			pending = append(pending, current)
+			continue
+		}
+
+		if m := goFailPackage.FindStringSubmatch(line + " "); m != nil {
+			for _, i := range pending {
+				failures[i].Package = m[1]
+			}
+			pending = nil
+			current = -1
+			continue
+		}
+
+		if m := goBuildHeader.FindStringSubmatch(line); m != nil {
+			failures = append(failures, Failure{Package: m[1]})
+			current, build = -1, len(failures)-1
+			continue
+		}
+
+		if m := pytestFailed.FindStringSubmatch(line); m != nil {
+			failures = append(failures, Failure{Package: m[1], Test: m[2], Output: m[3]})
+			current, build = -1, -1
+			continue
+		}
+
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case build >= 0 && trimmed != "" && !goPackageOK.MatchString(line):
+			failures[build].Output += trimmed + "\n"
+		case current >= 0 && strings.HasPrefix(line, " ") && !goPackageOK.MatchString(trimmed):
+			failures[current].Output += trimmed + "\n"
+		default:
+			build = -1
+		}
+	}
+
+	if len(failures) == 0 && strings.TrimSpace(output) != "" {
+		failures = append(failures, Failure{Output: tail(output, maxFailureOutput)})
+	}
+	return failures
+}
+
+// ClusterFailures groups failures by package and error category, in the
+// order each group first appears.
+func ClusterFailures(failures []Failure, classifier *classify.Classifier) []Cluster {
+	var clusters []Cluster
+	index := make(map[string]int)
+	for _, f := range failures {
+		category := classifier.Classify(f.Output)
+		key := f.Package + "|" + string(category)
+		i, ok := index[key]
+		if !ok {
+			clusters = append(clusters, Cluster{Package: f.Package, Category: category})
+			i = len(clusters) - 1
+			index[key] = i
+		}
+		clusters[i].Failures = append(clusters[i].Failures, f)
+	}
+	return clusters
+}
+
+// TestFailurePRD builds a PRD with one task per failure cluster.
+func TestFailurePRD(testCmd string, clusters []Cluster) *prd.PRD {
+	total := 0
+	for _, c := range clusters {
+		total += len(c.Failures)
+	}
+
+	p := &prd.PRD{
+		FeatureName: "Fix failing tests",
+		BranchName:  "fix/failing-tests",
+		Description: fmt.Sprintf("Generated by brigade triage from `%s`: %d failures in %d clusters.", testCmd, total, len(clusters)),
+	}
+	for i, c := range clusters {
+		p.Tasks = append(p.Tasks, clusterTask(fmt.Sprintf("US-%03d", i+1), testCmd, c))
+	}
+	return p
+}
+
+// clusterTask builds the task for one failure cluster.
+func clusterTask(id, testCmd string, c Cluster) prd.Task {
+	where := c.Package
+	if where == "" {
+		where = "the test suite"
+	}
+
+	var tests []string
+	var output strings.Builder
+	for _, f := range c.Failures {
+		if f.Test != "" {
+			tests = append(tests, f.Test)
+		}
+		if f.Output != "" && output.Len() < maxFailureOutput {
+			if f.Test != "" {
+				output.WriteString(f.Test + ":\n")
+			}
+			output.WriteString(f.Output)
+			output.WriteString("\n")
+		}
+	}
+
+	title := fmt.Sprintf("Fix build failure in %s", where)
+	if len(tests) == 1 {
+		title = fmt.Sprintf("Fix failing test %s in %s", tests[0], where)
+	} else if len(tests) > 1 {
+		title = fmt.Sprintf("Fix %d failing tests in %s", len(tests), where)
+	}
+
+	var desc strings.Builder
+	desc.WriteString(fmt.Sprintf("Failure category: %s. %s\n", c.Category, classify.Suggestions(c.Category)))
+	if len(tests) > 0 {
+		desc.WriteString("\nFailing tests: " + strings.Join(tests, ", ") + "\n")
+	}
+	if output.Len() > 0 {
+		desc.WriteString("\nError output:\n" + truncate(output.String(), maxFailureOutput))
+	}
+
+	var criteria []string
+	for i, t := range tests {
+		if i == maxCriteriaTests {
+			criteria = append(criteria, fmt.Sprintf("The remaining %d listed tests pass", len(tests)-maxCriteriaTests))
+			break
+		}
+		criteria = append(criteria, fmt.Sprintf("%s passes", t))
+	}
+	if len(tests) == 0 {
+		criteria = append(criteria, fmt.Sprintf("%s builds without errors", where))
+	}
+	criteria = append(criteria, fmt.Sprintf("`%s` reports no failures in %s", testCmd, where))
+	criteria = append(criteria, "Fixes address the root cause; tests are not skipped or weakened")
+
+	complexity := prd.ComplexityJunior
+	if c.Category == classify.CategoryIntegration || c.Category == classify.CategoryEnvironment || len(c.Failures) > 5 {
+		complexity = prd.ComplexitySenior
+	}
+
+	return prd.Task{
+		ID:                 id,
+		Title:              title,
+		Description:        strings.TrimSpace(desc.String()),
+		AcceptanceCriteria: criteria,
+		DependsOn:          []string{},
+		Complexity:         complexity,
+		Verification: []prd.Verification{
+			{Type: prd.VerificationUnit, Cmd: verifyCommand(testCmd, c.Package, tests)},
+		},
+	}
+}
+
+// verifyCommand narrows a Go test command to a cluster's package and
+// tests. Other test commands are used as given.
+func verifyCommand(testCmd, pkg string, tests []string) string {
+	if !strings.HasPrefix(testCmd, "go test") || pkg == "" {
+		return testCmd
+	}
+	cmd := "go test " + pkg
+	if len(tests) > 0 {
+		cmd += fmt.Sprintf(" -run '^(%s)$'", strings.Join(tests, "|"))
+	}
+	return cmd
+}
+
+// tail returns the last n bytes of s.
+func tail(s string, n int) string {
+	s = strings.TrimSpace(s)
+	if len(s) <= n {
+		return s
+	}
+	return "..." + s[len(s)-n:]
+}
+
+// truncate returns the first n bytes of s.
+func truncate(s string, n int) string {
+	s = strings.TrimSpace(s)
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
@@ -0,0 +1,82 @@
+package triage
+
+import (
+	"strings"
+	"testing"
+
+	"brigade/internal/classify"
+)
+
+const goTestOutput = `--- FAIL: TestCreateUser (0.00s)
+    users_test.go:42: expected status 201, got 500
+--- FAIL: TestDeleteUser (0.01s)
+    --- FAIL: TestDeleteUser/missing (0.00s)
+        users_test.go:88: expected error for missing user
+FAIL
+FAIL	example.com/app/api	0.021s
+# example.com/app/store
+store/db.go:17:2: undefined: sqlDriver
+FAIL	example.com/app/store [build failed]
+ok  	example.com/app/util	0.003s
+FAIL
+`
+
+func TestParseFailuresGo(t *testing.T) {
+	failures := ParseFailures(goTestOutput)
+	if len(failures) != 3 {
+		t.Fatalf("ParseFailures() returned %d failures, want 3: %+v", len(failures), failures)
+	}
+
+	tests := []struct {
+		pkg, test, output string
+	}{
+		{"example.com/app/api", "TestCreateUser", "expected status 201"},
+		{"example.com/app/api", "TestDeleteUser", "expected error for missing user"},
+		{"example.com/app/store", "", "undefined: sqlDriver"},
+	}
+	for i, tt := range tests {
+		f := failures[i]
+		if f.Package != tt.pkg || f.Test != tt.test || !strings.Contains(f.Output, tt.output) {
+			t.Errorf("failure %d = %+v, want package %s, test %q, output containing %q", i, f, tt.pkg, tt.test, tt.output)
+		}
+	}
+}
+
+func TestParseFailuresPytest(t *testing.T) {
+	output := "FAILED tests/test_api.py::test_login - AssertionError: 401 != 200\n1 failed, 4 passed"
+	failures := ParseFailures(output)
+	if len(failures) != 1 {
+		t.Fatalf("ParseFailures() returned %d failures, want 1", len(failures))
+	}
+	if failures[0].Package != "tests/test_api.py" || failures[0].Test != "test_login" {
+		t.Errorf("failure = %+v, want tests/test_api.py test_login", failures[0])
+	}
+}
+
+func TestParseFailuresUnknownFormat(t *testing.T) {
+	failures := ParseFailures("Error: something broke\n")
+	if len(failures) != 1 || failures[0].Output != "Error: something broke" {
+		t.Errorf("ParseFailures() = %+v, want one failure with the raw output", failures)
+	}
+}
+
+func TestTestFailurePRD(t *testing.T) {
+	clusters := ClusterFailures(ParseFailures(goTestOutput), classify.NewClassifier())
+	if len(clusters) != 2 {
+		t.Fatalf("ClusterFailures() returned %d clusters, want 2", len(clusters))
+	}
+
+	p := TestFailurePRD("go test ./...", clusters)
+	if result := p.ValidateQuick(); !result.IsValid() {
+		t.Errorf("generated PRD is invalid: %v", result.Errors)
+	}
+
+	got := p.Tasks[0].Verification[0].Cmd
+	want := "go test example.com/app/api -run '^(TestCreateUser|TestDeleteUser)$'"
+	if got != want {
+		t.Errorf("verification = %q, want %q", got, want)
+	}
+	if p.Tasks[1].Title != "Fix build failure in example.com/app/store" {
+		t.Errorf("build task title = %q", p.Tasks[1].Title)
+	}
+}
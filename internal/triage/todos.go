@@ -0,0 +1,202 @@
+package triage
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"brigade/internal/prd"
+	"brigade/internal/verify"
+)
+
+// maxMarkersPerTask caps how many markers one cleanup task covers; larger
+// areas are split into several tasks.
+const maxMarkersPerTask = 8
+
+// priorityPattern extracts the executive's task ordering.
+var priorityPattern = regexp.MustCompile(`(?s)<priority>(.*?)</priority>`)
+
+// MarkerCluster is a group of markers in one area of the codebase.
+type MarkerCluster struct {
+	Area    string // Directory the markers are in
+	Part    int    // 1-based part number when an area is split, else 0
+	Markers []verify.TodoMarker
+}
+
+// ClusterMarkers groups markers by directory, largest areas first, and
+// splits areas with more than maxMarkersPerTask markers.
+func ClusterMarkers(markers []verify.TodoMarker) []MarkerCluster {
+	byArea := make(map[string][]verify.TodoMarker)
+	var areas []string
+	for _, m := range markers {
+		area := filepath.ToSlash(filepath.Dir(m.File))
+		if _, ok := byArea[area]; !ok {
+			areas = append(areas, area)
+		}
+		byArea[area] = append(byArea[area], m)
+	}
+	sort.SliceStable(areas, func(i, j int) bool {
+		return len(byArea[areas[i]]) > len(byArea[areas[j]])
+	})
+
+	var clusters []MarkerCluster
+	for _, area := range areas {
+		ms := byArea[area]
+		if len(ms) <= maxMarkersPerTask {
+			clusters = append(clusters, MarkerCluster{Area: area, Markers: ms})
+			continue
+		}
+		for part := 0; part*maxMarkersPerTask < len(ms); part++ {
+			end := (part + 1) * maxMarkersPerTask
+			if end > len(ms) {
+				end = len(ms)
+			}
+			clusters = append(clusters, MarkerCluster{Area: area, Part: part + 1, Markers: ms[part*maxMarkersPerTask : end]})
+		}
+	}
+	return clusters
+}
+
+// TodoPRD builds a draft PRD with one cleanup task per marker cluster.
+func TodoPRD(clusters []MarkerCluster) *prd.PRD {
+	total := 0
+	for _, c := range clusters {
+		total += len(c.Markers)
+	}
+
+	p := &prd.PRD{
+		FeatureName: "Resolve TODO and FIXME markers",
+		BranchName:  "chore/resolve-todos",
+		Description: fmt.Sprintf("Generated by brigade triage from a marker scan: %d markers in %d areas.", total, len(clusters)),
+	}
+	for i, c := range clusters {
+		p.Tasks = append(p.Tasks, markerTask(fmt.Sprintf("US-%03d", i+1), c))
+	}
+	return p
+}
+
+// markerTask builds the cleanup task for one cluster.
+func markerTask(id string, c MarkerCluster) prd.Task {
+	area := c.Area
+	if area == "." {
+		area = "the project root"
+	}
+	noun := "markers"
+	if len(c.Markers) == 1 {
+		noun = "marker"
+	}
+	title := fmt.Sprintf("Resolve %d %s in %s", len(c.Markers), noun, area)
+	if c.Part > 0 {
+		title += fmt.Sprintf(" (part %d)", c.Part)
+	}
+
+	var desc strings.Builder
+	desc.WriteString("Resolve each marker: do the work it describes, or remove it if it no longer applies. ")
+	desc.WriteString("If a marker needs more than a small change, replace it with a backlog item instead.\n\nMarkers:\n")
+
+	complexity := prd.ComplexityJunior
+	var criteria []string
+	files := make(map[string]bool)
+	var fileList []string
+	for _, m := range c.Markers {
+		loc := fmt.Sprintf("%s:%d", filepath.ToSlash(m.File), m.Line)
+		desc.WriteString(fmt.Sprintf("- %s %s: %s\n", loc, m.Type, m.Text))
+		criteria = append(criteria, fmt.Sprintf("%s %s at %s is resolved and the marker removed", m.Type, quoteMarker(m.Text), loc))
+		if m.Type == "FIXME" || m.Type == "HACK" {
+			complexity = prd.ComplexitySenior
+		}
+		if !files[m.File] {
+			files[m.File] = true
+			fileList = append(fileList, filepath.ToSlash(m.File))
+		}
+	}
+	criteria = append(criteria, "Existing tests still pass")
+
+	return prd.Task{
+		ID:                 id,
+		Title:              title,
+		Description:        strings.TrimSpace(desc.String()),
+		AcceptanceCriteria: criteria,
+		DependsOn:          []string{},
+		Complexity:         complexity,
+		Verification: []prd.Verification{
+			{Type: prd.VerificationPattern, Cmd: fmt.Sprintf("! grep -nE '\\b(TODO|FIXME|HACK|XXX)\\b' %s", strings.Join(fileList, " "))},
+		},
+	}
+}
+
+// quoteMarker renders marker text for a criterion, shortened if long.
+func quoteMarker(text string) string {
+	if text == "" {
+		return "(no description)"
+	}
+	if len(text) > 80 {
+		text = text[:77] + "..."
+	}
+	return fmt.Sprintf("%q", text)
+}
+
+// PriorityPrompt asks the executive chef to order cleanup tasks by value.
+func PriorityPrompt(p *prd.PRD) string {
+	var sb strings.Builder
+	sb.WriteString("=== PRIORITIZE CLEANUP TASKS ===\n\n")
+	sb.WriteString("These tasks resolve TODO/FIXME markers found in the codebase. ")
+	sb.WriteString("Order them by value: correctness and security issues first, then ")
+	sb.WriteString("anything blocking other work, then general cleanup. Look at the code ")
+	sb.WriteString("around the markers if that helps you judge.\n\n")
+	for _, task := range p.Tasks {
+		sb.WriteString(fmt.Sprintf("%s: %s\n%s\n\n", task.ID, task.Title, task.Description))
+	}
+	sb.WriteString("Respond with every task ID, most important first:\n")
+	sb.WriteString("<priority>US-002, US-001, ...</priority>\n")
+	return sb.String()
+}
+
+// ApplyPriority reorders tasks by the executive's <priority> response and
+// renumbers them. Tasks the response leaves out keep their relative order
+// at the end. Returns false if the response has no usable ordering.
+func ApplyPriority(p *prd.PRD, output string) bool {
+	m := priorityPattern.FindStringSubmatch(output)
+	if m == nil {
+		return false
+	}
+
+	var ordered []prd.Task
+	seen := make(map[string]bool)
+	for _, id := range strings.FieldsFunc(m[1], func(r rune) bool { return r == ',' || r == ' ' || r == '\n' }) {
+		if task := p.TaskByID(id); task != nil && !seen[id] {
+			ordered = append(ordered, *task)
+			seen[id] = true
+		}
+	}
+	if len(ordered) == 0 {
+		return false
+	}
+	for _, task := range p.Tasks {
+		if !seen[task.ID] {
+			ordered = append(ordered, task)
+		}
+	}
+
+	for i := range ordered {
+		ordered[i].ID = fmt.Sprintf("US-%03d", i+1)
+	}
+	p.Tasks = ordered
+	return true
+}
+
+// SkipMarker reports whether a marker is in a file that shouldn't produce
+// cleanup work: Brigade's own files and docs or data where the words are prose.
+func SkipMarker(m verify.TodoMarker) bool {
+	path := filepath.ToSlash(m.File)
+	if strings.HasPrefix(path, "brigade/") || strings.HasPrefix(path, "./brigade/") {
+		return true
+	}
+	switch filepath.Ext(path) {
+	case ".md", ".json":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,94 @@
+package triage
+
+import (
+	"fmt"
+	"testing"
+
+	"brigade/internal/verify"
+)
+
+func TestClusterMarkers(t *testing.T) {
+	var markers []verify.TodoMarker
+	markers = append(markers, verify.TodoMarker{File: "cmd/main.go", Line: 3, Type: "TODO", Text: "flag parsing"})
+	for i := 1; i <= 10; i++ {
+		markers = append(markers, verify.TodoMarker{File: "internal/api/users.go", Line: i * 10, Type: "TODO", Text: fmt.Sprintf("item %d", i)})
+	}
+
+	clusters := ClusterMarkers(markers)
+	if len(clusters) != 3 {
+		t.Fatalf("ClusterMarkers() returned %d clusters, want 3", len(clusters))
+	}
+
+	tests := []struct {
+		area    string
+		part    int
+		markers int
+	}{
+		{"internal/api", 1, 8},
+		{"internal/api", 2, 2},
+		{"cmd", 0, 1},
+	}
+	for i, tt := range tests {
+		c := clusters[i]
+		if c.Area != tt.area || c.Part != tt.part || len(c.Markers) != tt.markers {
+			t.Errorf("cluster %d = %s part %d with %d markers, want %s part %d with %d", i, c.Area, c.Part, len(c.Markers), tt.area, tt.part, tt.markers)
+		}
+	}
+}
+
+func TestTodoPRD(t *testing.T) {
+	markers := []verify.TodoMarker{
+		{File: "api/users.go", Line: 12, Type: "TODO", Text: "validate email"},
+		{File: "api/users.go", Line: 40, Type: "FIXME", Text: "race on delete"},
+		{File: "main.go", Line: 5, Type: "TODO", Text: "read config"},
+	}
+	p := TodoPRD(ClusterMarkers(markers))
+	if result := p.ValidateQuick(); !result.IsValid() {
+		t.Errorf("generated PRD is invalid: %v", result.Errors)
+	}
+
+	task := p.Tasks[0]
+	if task.Complexity != "senior" {
+		t.Errorf("complexity = %s, want senior for a FIXME", task.Complexity)
+	}
+	want := `FIXME "race on delete" at api/users.go:40 is resolved and the marker removed`
+	if task.AcceptanceCriteria[1] != want {
+		t.Errorf("criterion = %q, want %q", task.AcceptanceCriteria[1], want)
+	}
+	if p.Tasks[1].Title != "Resolve 1 marker in the project root" {
+		t.Errorf("root task title = %q", p.Tasks[1].Title)
+	}
+}
+
+func TestApplyPriority(t *testing.T) {
+	markers := []verify.TodoMarker{
+		{File: "a/x.go", Line: 1, Type: "TODO", Text: "a"},
+		{File: "b/x.go", Line: 1, Type: "TODO", Text: "b"},
+		{File: "c/x.go", Line: 1, Type: "TODO", Text: "c"},
+	}
+
+	tests := []struct {
+		name   string
+		output string
+		ok     bool
+		want   []string // Areas in task order
+	}{
+		{"full ordering", "<priority>US-003, US-001, US-002</priority>", true, []string{"c", "a", "b"}},
+		{"partial ordering", "<priority>US-002</priority>", true, []string{"b", "a", "c"}},
+		{"no tag", "US-003 first", false, []string{"a", "b", "c"}},
+		{"unknown ids", "<priority>US-009</priority>", false, []string{"a", "b", "c"}},
+	}
+
+	for _, tt := range tests {
+		p := TodoPRD(ClusterMarkers(markers))
+		if ok := ApplyPriority(p, tt.output); ok != tt.ok {
+			t.Errorf("%s: ApplyPriority() = %v, want %v", tt.name, ok, tt.ok)
+		}
+		for i, area := range tt.want {
+			task := p.Tasks[i]
+			if task.ID != fmt.Sprintf("US-%03d", i+1) || task.Title != "Resolve 1 marker in "+area {
+				t.Errorf("%s: task %d = %s %q, want area %s", tt.name, i, task.ID, task.Title, area)
+			}
+		}
+	}
+}
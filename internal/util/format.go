@@ -0,0 +1,117 @@
+package util
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// NumberFormat is how a locale writes numbers.
+type NumberFormat struct {
+	Decimal string // Decimal separator
+	Group   string // Thousands separator
+}
+
+// Number formats by language. Languages not listed write 1,234.5.
+var numberFormats = map[string]NumberFormat{
+	"de": {",", "."}, "es": {",", "."}, "it": {",", "."}, "nl": {",", "."},
+	"pt": {",", "."}, "da": {",", "."}, "id": {",", "."}, "tr": {",", "."},
+	"fr": {",", " "}, "ru": {",", " "}, "pl": {",", " "}, "sv": {",", " "},
+	"fi": {",", " "}, "nb": {",", " "}, "cs": {",", " "}, "uk": {",", " "},
+}
+
+// Numbers is the number format human-readable output uses, from the
+// locale in LC_ALL, LC_NUMERIC or LANG.
+var Numbers = LocaleNumbers(localeName())
+
+func localeName() string {
+	for _, key := range []string{"LC_ALL", "LC_NUMERIC", "LANG"} {
+		if value := os.Getenv(key); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// LocaleNumbers returns the number format of a locale name such as
+// "de_DE.UTF-8". C, POSIX and unknown locales get 1,234.5.
+func LocaleNumbers(locale string) NumberFormat {
+	lang, _, _ := strings.Cut(locale, ".")
+	lang, _, _ = strings.Cut(lang, "_")
+	if nf, ok := numberFormats[strings.ToLower(lang)]; ok {
+		return nf
+	}
+	return NumberFormat{Decimal: ".", Group: ","}
+}
+
+// Format writes f with the given number of decimals, grouping thousands.
+func (nf NumberFormat) Format(f float64, decimals int) string {
+	s := fmt.Sprintf("%.*f", decimals, math.Abs(f))
+	whole, frac, _ := strings.Cut(s, ".")
+
+	var sb strings.Builder
+	if f < 0 && strings.Trim(s, "0.") != "" {
+		sb.WriteString("-")
+	}
+	for i, digit := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			sb.WriteString(nf.Group)
+		}
+		sb.WriteRune(digit)
+	}
+	if frac != "" {
+		sb.WriteString(nf.Decimal + frac)
+	}
+	return sb.String()
+}
+
+// FormatCost writes a USD amount to the cent, like "$1,234.50". A cost too
+// small to show as a cent is "<$0.01".
+func FormatCost(usd float64) string {
+	if usd > 0 && usd < 0.005 {
+		return "<$" + Numbers.Format(0.01, 2)
+	}
+	return "$" + Numbers.Format(usd, 2)
+}
+
+// FormatPercent writes part as a whole percentage of total, "0%" when total
+// is 0.
+func FormatPercent(part, total float64) string {
+	if total == 0 {
+		return "0%"
+	}
+	return Numbers.Format(math.Round(part*100/total), 0) + "%"
+}
+
+// FormatDuration writes d to the second, largest unit first: "45s",
+// "3m 05s", "1h 02m 05s".
+func FormatDuration(d time.Duration) string {
+	total := int(d.Round(time.Second).Seconds())
+	if total < 0 {
+		total = 0
+	}
+	h, m, s := total/3600, total/60%60, total%60
+	switch {
+	case h > 0:
+		return fmt.Sprintf("%dh %02dm %02ds", h, m, s)
+	case m > 0:
+		return fmt.Sprintf("%dm %02ds", m, s)
+	}
+	return fmt.Sprintf("%ds", s)
+}
+
+// Tail returns the last n bytes of s or fewer, starting on a whole rune so
+// multi-byte characters aren't cut in half.
+func Tail(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	i := len(s) - n
+	for i < len(s) && !utf8.RuneStart(s[i]) {
+		i++
+	}
+	return s[i:]
+}
@@ -0,0 +1,104 @@
+package util
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "0s"},
+		{1400 * time.Millisecond, "1s"},
+		{45 * time.Second, "45s"},
+		{3*time.Minute + 5*time.Second, "3m 05s"},
+		{time.Hour + 2*time.Minute + 5*time.Second, "1h 02m 05s"},
+		{26 * time.Hour, "26h 00m 00s"},
+		{-time.Second, "0s"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatDuration(tt.d); got != tt.want {
+			t.Errorf("FormatDuration(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestNumberFormat(t *testing.T) {
+	tests := []struct {
+		locale   string
+		f        float64
+		decimals int
+		want     string
+	}{
+		{"", 1234567.891, 2, "1,234,567.89"},
+		{"C", 999.5, 0, "1,000"},
+		{"en_US.UTF-8", -1234.5, 1, "-1,234.5"},
+		{"de_DE.UTF-8", 1234.5, 2, "1.234,50"},
+		{"fr_FR", 1234.5, 2, "1 234,50"},
+		{"en_GB", -0.001, 2, "0.00"},
+		{"en_GB", 12, 0, "12"},
+	}
+
+	for _, tt := range tests {
+		if got := LocaleNumbers(tt.locale).Format(tt.f, tt.decimals); got != tt.want {
+			t.Errorf("LocaleNumbers(%q).Format(%v, %d) = %q, want %q", tt.locale, tt.f, tt.decimals, got, tt.want)
+		}
+	}
+}
+
+func TestFormatCostAndPercent(t *testing.T) {
+	defer func(nf NumberFormat) { Numbers = nf }(Numbers)
+	Numbers = LocaleNumbers("")
+
+	costs := []struct {
+		usd  float64
+		want string
+	}{
+		{0, "$0.00"},
+		{0.002, "<$0.01"},
+		{1.5, "$1.50"},
+		{1234.567, "$1,234.57"},
+	}
+	for _, tt := range costs {
+		if got := FormatCost(tt.usd); got != tt.want {
+			t.Errorf("FormatCost(%v) = %q, want %q", tt.usd, got, tt.want)
+		}
+	}
+
+	percents := []struct {
+		part, total float64
+		want        string
+	}{
+		{1, 3, "33%"},
+		{2, 3, "67%"},
+		{5, 0, "0%"},
+	}
+	for _, tt := range percents {
+		if got := FormatPercent(tt.part, tt.total); got != tt.want {
+			t.Errorf("FormatPercent(%v, %v) = %q, want %q", tt.part, tt.total, got, tt.want)
+		}
+	}
+}
+
+func TestTail(t *testing.T) {
+	tests := []struct {
+		s    string
+		n    int
+		want string
+	}{
+		{"short", 10, "short"},
+		{"abcdef", 3, "def"},
+		{"a→b", 3, "b"},
+		{"a→b", 4, "→b"},
+		{"", 3, ""},
+	}
+
+	for _, tt := range tests {
+		if got := Tail(tt.s, tt.n); got != tt.want {
+			t.Errorf("Tail(%q, %d) = %q, want %q", tt.s, tt.n, got, tt.want)
+		}
+	}
+}
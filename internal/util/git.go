@@ -1,7 +1,13 @@
 package util
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
 	"os/exec"
+	"path/filepath"
+	"strconv"
 	"strings"
 )
 
@@ -15,3 +21,347 @@ func GetHeadCommit() string {
 	}
 	return strings.TrimSpace(string(output))
 }
+
+// GitDiffStat returns `git diff --stat` for the changes on HEAD since it
+// diverged from base. Returns "" if git is unavailable or base is unknown.
+func GitDiffStat(base string) string {
+	if base == "" {
+		return ""
+	}
+	output, err := exec.Command("git", "diff", "--stat", base+"...HEAD").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// GitDiffRange returns `git diff` (with stat, `git diff --stat`) from one
+// commit to another, or to the working tree when to is "", limited to
+// pathspecs if any are given. Returns "" if git is unavailable or a commit
+// is unknown.
+func GitDiffRange(from, to string, stat bool, pathspecs ...string) string {
+	if from == "" || from == "unknown" || to == "unknown" {
+		return ""
+	}
+	args := []string{"diff"}
+	if stat {
+		args = append(args, "--stat")
+	}
+	args = append(args, from)
+	if to != "" {
+		args = append(args, to)
+	}
+	if len(pathspecs) > 0 {
+		args = append(append(args, "--"), pathspecs...)
+	}
+	output, err := exec.Command("git", args...).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// GitRecentCommits returns the last n commit subjects in oneline format.
+func GitRecentCommits(n int) string {
+	output, err := exec.Command("git", "log", "--oneline", "-n", strconv.Itoa(n)).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// GitDiffLines returns the lines added plus removed between base and the
+// working tree, or -1 if git is unavailable or base is unknown.
+func GitDiffLines(base string) int {
+	if base == "" || base == "unknown" {
+		return -1
+	}
+	output, err := exec.Command("git", "diff", "--numstat", base).Output()
+	if err != nil {
+		return -1
+	}
+	lines := 0
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		// Binary files report "-" for both counts
+		added, _ := strconv.Atoi(fields[0])
+		removed, _ := strconv.Atoi(fields[1])
+		lines += added + removed
+	}
+	return lines
+}
+
+// GitLogPatch returns `git log -p` for the commits from base to HEAD,
+// oldest first, each headed by "commit <hash>". Returns "" if git is
+// unavailable, base is unknown, or there are no commits.
+func GitLogPatch(base string) string {
+	if base == "" || base == "unknown" {
+		return ""
+	}
+	output, err := exec.Command("git", "log", "-p", "--reverse", "--no-color", "--format=commit %H", base+"..HEAD").Output()
+	if err != nil {
+		return ""
+	}
+	return string(output)
+}
+
+// GitNewFiles returns the files added since base: committed ones, and ones
+// only in the working tree (untracked or staged). Returns nil if git is
+// unavailable; with base unknown, committed is empty.
+func GitNewFiles(base string) (committed, uncommitted []string) {
+	if base != "" && base != "unknown" {
+		output, err := exec.Command("git", "diff", "--name-only", "--diff-filter=A", base, "HEAD").Output()
+		if err == nil {
+			for _, f := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+				if f != "" {
+					committed = append(committed, f)
+				}
+			}
+		}
+	}
+
+	output, err := exec.Command("git", "status", "--porcelain", "--untracked-files=all").Output()
+	if err != nil {
+		return committed, nil
+	}
+	for _, line := range strings.Split(string(output), "\n") {
+		if len(line) < 4 || (line[:2] != "??" && line[0] != 'A') {
+			continue
+		}
+		uncommitted = append(uncommitted, strings.Trim(line[3:], `"`))
+	}
+	return committed, uncommitted
+}
+
+// GitChangedSince returns the files changed between base and the working
+// tree, committed or not, including untracked files but not deleted ones.
+// An unknown base means HEAD.
+func GitChangedSince(base string) []string {
+	if base == "" || base == "unknown" {
+		base = "HEAD"
+	}
+	var files []string
+	for _, args := range [][]string{
+		{"diff", "--name-only", "--diff-filter=d", base},
+		{"ls-files", "--others", "--exclude-standard"},
+	} {
+		output, err := exec.Command("git", args...).Output()
+		if err != nil {
+			continue
+		}
+		for _, f := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+			if f != "" {
+				files = append(files, f)
+			}
+		}
+	}
+	return files
+}
+
+// GitCommitPaths commits the working tree's state of paths, and nothing
+// else that's staged, with any extra git commit flags.
+func GitCommitPaths(message string, paths []string, flags ...string) error {
+	args := append([]string{"commit", "-q", "-m", message}, flags...)
+	args = append(append(args, "--"), paths...)
+	if output, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("git commit: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// GitStatusShort returns `git status --short` for uncommitted changes in
+// the working tree. Returns "" if git is unavailable or the tree is clean.
+func GitStatusShort() string {
+	output, err := exec.Command("git", "status", "--short").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// GitChangedFiles returns the paths with uncommitted changes, including
+// untracked files. Renames report the new path.
+func GitChangedFiles() []string {
+	output, err := exec.Command("git", "status", "--porcelain", "--untracked-files=all").Output()
+	if err != nil {
+		return nil
+	}
+	var files []string
+	for _, line := range strings.Split(string(output), "\n") {
+		if len(line) < 4 {
+			continue
+		}
+		path := line[3:]
+		if i := strings.Index(path, " -> "); i >= 0 {
+			path = path[i+4:]
+		}
+		files = append(files, strings.Trim(path, `"`))
+	}
+	return files
+}
+
+// GitDiff returns the uncommitted changes to paths as a unified diff
+// against HEAD, with untracked files shown as new files. Returns "" if git
+// is unavailable or nothing changed.
+func GitDiff(paths []string) string {
+	if len(paths) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	args := append([]string{"diff", "HEAD", "--"}, paths...)
+	if output, err := exec.Command("git", args...).Output(); err == nil {
+		sb.Write(output)
+	}
+
+	args = append([]string{"ls-files", "--others", "--exclude-standard", "--"}, paths...)
+	output, err := exec.Command("git", args...).Output()
+	if err != nil {
+		return sb.String()
+	}
+	for _, path := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if path == "" {
+			continue
+		}
+		// --no-index exits 1 when the files differ, which they always do
+		diff, _ := exec.Command("git", "diff", "--no-index", "--", "/dev/null", path).Output()
+		sb.Write(diff)
+	}
+	return sb.String()
+}
+
+// GitTreeState returns a hash of the code's state: HEAD's tree plus the
+// uncommitted changes to paths. Returns "" if git is unavailable.
+func GitTreeState(paths []string) string {
+	tree, err := exec.Command("git", "rev-parse", "HEAD^{tree}").Output()
+	if err != nil {
+		return ""
+	}
+	h := sha256.New()
+	h.Write(tree)
+	h.Write([]byte(GitDiff(paths)))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// gitChangedSince lists the files under the working directory changed
+// since base, committed or not, including untracked ones. Paths are
+// relative to the working directory, as ls-files gives them.
+func gitChangedSince(base string) ([]string, error) {
+	var paths []string
+	for _, args := range [][]string{
+		{"diff", "--name-only", "--relative", base},
+		{"ls-files", "--others", "--exclude-standard"},
+	} {
+		output, err := exec.Command("git", args...).Output()
+		if err != nil {
+			return nil, fmt.Errorf("git %s: %w", args[0], err)
+		}
+		for _, f := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+			if f != "" {
+				paths = append(paths, f)
+			}
+		}
+	}
+	return paths, nil
+}
+
+// GitSnapshot saves the uncommitted changes under the working directory,
+// untracked files included, which git stash create would leave out. It
+// maps each changed file to the blob holding its content, or to "" if it
+// was deleted; GitRestore puts them back.
+func GitSnapshot() (map[string]string, error) {
+	paths, err := gitChangedSince("HEAD")
+	if err != nil {
+		return nil, err
+	}
+	snapshot := make(map[string]string, len(paths))
+	var present, abs []string
+	for _, path := range paths {
+		snapshot[path] = ""
+		if _, err := os.Lstat(path); err == nil {
+			present = append(present, path)
+			// --stdin-paths reads paths from the top of the repository
+			p, _ := filepath.Abs(path)
+			abs = append(abs, p)
+		}
+	}
+	if len(present) == 0 {
+		return snapshot, nil
+	}
+
+	cmd := exec.Command("git", "hash-object", "-w", "--stdin-paths")
+	cmd.Stdin = strings.NewReader(strings.Join(abs, "\n") + "\n")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git hash-object: %w", err)
+	}
+	blobs := strings.Fields(string(output))
+	if len(blobs) != len(present) {
+		return nil, fmt.Errorf("git hash-object: %d blobs for %d files", len(blobs), len(present))
+	}
+	for i, path := range present {
+		snapshot[path] = blobs[i]
+	}
+	return snapshot, nil
+}
+
+// GitRestore moves HEAD back to base and returns every file under the
+// working directory changed since then, committed or not, to its state at
+// base, deleting the ones base doesn't have. Files in snapshot, from
+// GitSnapshot, get the content saved there instead. Files keep reports
+// true for are left as they are.
+func GitRestore(base string, snapshot map[string]string, keep func(path string) bool) error {
+	if base == "" || base == "unknown" {
+		return fmt.Errorf("no commit to restore to")
+	}
+	changed, err := gitChangedSince(base)
+	if err != nil {
+		return err
+	}
+	var paths []string
+	for _, f := range changed {
+		if _, saved := snapshot[f]; !saved && !keep(f) {
+			paths = append(paths, f)
+		}
+	}
+
+	if output, err := exec.Command("git", "reset", "-q", base).CombinedOutput(); err != nil {
+		return fmt.Errorf("git reset: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+	for _, path := range paths {
+		// ./ makes the path relative to the working directory, not the top
+		if exec.Command("git", "cat-file", "-e", base+":./"+path).Run() != nil {
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			continue
+		}
+		if output, err := exec.Command("git", "checkout", base, "--", path).CombinedOutput(); err != nil {
+			return fmt.Errorf("git checkout %s: %w: %s", path, err, strings.TrimSpace(string(output)))
+		}
+	}
+	for path, blob := range snapshot {
+		if keep(path) {
+			continue
+		}
+		if blob == "" {
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			continue
+		}
+		content, err := exec.Command("git", "cat-file", "blob", blob).Output()
+		if err != nil {
+			return fmt.Errorf("git cat-file %s: %w", path, err)
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, content, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package util
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGitRestore(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	root := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = root
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	write := func(file, content string) {
+		t.Helper()
+		path := filepath.Join(root, file)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	run("init", "-q", "-b", "main")
+	run("config", "user.email", "test@example.com")
+	run("config", "user.name", "test")
+	write("app/tracked.txt", "base\n")
+	write("app/edited.txt", "base\n")
+	write("outside.txt", "base\n")
+	run("add", "-A")
+	run("commit", "-qm", "base")
+
+	// Brigade runs in a subdirectory, with work of its user's in progress
+	t.Chdir(filepath.Join(root, "app"))
+	write("app/edited.txt", "before the attempt\n")
+	write("app/untracked.txt", "before the attempt\n")
+	base := GetHeadCommit()
+	snapshot, err := GitSnapshot()
+	if err != nil {
+		t.Fatalf("GitSnapshot() error = %v", err)
+	}
+
+	// The attempt commits some changes and leaves others
+	write("app/tracked.txt", "attempt\n")
+	write("app/edited.txt", "attempt\n")
+	run("commit", "-qam", "attempt")
+	write("app/untracked.txt", "attempt\n")
+	write("app/new.txt", "attempt\n")
+	write("app/brigade/state.json", "attempt\n")
+	write("outside.txt", "attempt\n")
+
+	if err := GitRestore(base, snapshot, func(path string) bool {
+		return strings.HasPrefix(path, "brigade/")
+	}); err != nil {
+		t.Fatalf("GitRestore() error = %v", err)
+	}
+
+	want := map[string]string{
+		"app/tracked.txt":        "base\n",
+		"app/edited.txt":         "before the attempt\n",
+		"app/untracked.txt":      "before the attempt\n",
+		"app/new.txt":            "",
+		"app/brigade/state.json": "attempt\n",
+		"outside.txt":            "attempt\n",
+	}
+	for file, content := range want {
+		data, err := os.ReadFile(filepath.Join(root, file))
+		if content == "" {
+			if err == nil {
+				t.Errorf("%s exists after GitRestore(), want it removed", file)
+			}
+			continue
+		}
+		if got := string(data); got != content {
+			t.Errorf("%s = %q after GitRestore(), want %q", file, got, content)
+		}
+	}
+	if got := GetHeadCommit(); got != base {
+		t.Errorf("HEAD = %s after GitRestore(), want %s", got, base)
+	}
+}
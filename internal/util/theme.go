@@ -0,0 +1,153 @@
+package util
+
+import (
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Output themes (BRIGADE_THEME).
+const (
+	ThemeDefault = "default"
+	ThemePlain   = "plain" // No ANSI codes or emoji
+	ThemeASCII   = "ascii" // Plain, with box drawing and symbols spelled in ASCII
+)
+
+// ansiPattern matches ANSI escape sequences: colors and cursor movement.
+var ansiPattern = regexp.MustCompile(`\x1b\[[0-9;?]*[A-Za-z]`)
+
+// asciiSymbols spells the symbols Brigade prints in ASCII. Other box
+// drawing characters become "+".
+var asciiSymbols = map[rune]string{
+	'═': "=", '─': "-", '━': "-", '║': "|", '│': "|", '┃': "|",
+	'✓': "+", '✔': "+", '✗': "x", '✘': "x", '×': "x",
+	'→': "->", '←': "<-", '↑': "^", '↓': "v", '⬆': "^", '▶': ">",
+	'•': "*", '·': "*", '●': "*", '○': "o", '◐': "~", '⊘': "-",
+	'█': "#", '▓': "#", '▒': ":", '░': ".",
+	'⚠': "!", '…': "...", '—': "-", '–': "-", '≈': "~",
+	'“': `"`, '”': `"`, '‘': "'", '’': "'",
+}
+
+// keptSymbols are dingbats that read as text, not emoji.
+var keptSymbols = map[rune]bool{'✓': true, '✔': true, '✗': true, '✘': true, '⚠': true}
+
+// isEmoji reports whether r is an emoji or joins emoji together.
+func isEmoji(r rune) bool {
+	switch {
+	case keptSymbols[r]:
+		return false
+	case r >= 0x1F000 && r <= 0x1FAFF, // Pictographs, emoticons, transport
+		r >= 0x2600 && r <= 0x27BF, // Miscellaneous symbols, dingbats
+		r >= 0x23E9 && r <= 0x23FA, // Media controls, timers
+		r == 0x2B50, r == 0x2B55,   // Star, circle
+		r == 0xFE0F, r == 0x200D: // Emoji presentation, zero-width joiner
+		return true
+	}
+	return false
+}
+
+// ApplyTheme rewrites text for a theme. Plain drops ANSI codes and emoji,
+// along with the space after an emoji; ASCII also spells symbols and box
+// drawing in ASCII and drops other non-ASCII symbols. Letters in any script
+// are kept.
+func ApplyTheme(text, theme string) string {
+	if theme != ThemePlain && theme != ThemeASCII {
+		return text
+	}
+	text = ansiPattern.ReplaceAllString(text, "")
+
+	var sb strings.Builder
+	skipSpace := false
+	for _, r := range text {
+		if skipSpace && r == ' ' {
+			skipSpace = false
+			continue
+		}
+		skipSpace = false
+		switch {
+		case isEmoji(r):
+			skipSpace = true
+		case r < utf8.RuneSelf || theme == ThemePlain:
+			sb.WriteRune(r)
+		case asciiSymbols[r] != "":
+			sb.WriteString(asciiSymbols[r])
+		case r >= 0x2500 && r <= 0x257F:
+			sb.WriteString("+")
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsSpace(r):
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// ThemeOutput passes everything written to os.Stdout and os.Stderr through
+// ApplyTheme, so output printed anywhere follows the theme. The returned
+// function flushes the output and restores the files; call it before
+// exiting.
+func ThemeOutput(theme string) (flush func(), err error) {
+	var done []chan struct{}
+	var restore []func()
+	for _, f := range []**os.File{&os.Stdout, &os.Stderr} {
+		r, w, err := os.Pipe()
+		if err != nil {
+			for _, undo := range restore {
+				undo()
+			}
+			return nil, err
+		}
+		original := *f
+		*f = w
+		finished := make(chan struct{})
+		go func() {
+			defer close(finished)
+			copyThemed(original, r, theme)
+		}()
+		done = append(done, finished)
+		restore = append(restore, func() {
+			w.Close()
+			*f = original
+		})
+	}
+	return func() {
+		for _, undo := range restore {
+			undo()
+		}
+		for _, finished := range done {
+			<-finished
+		}
+	}, nil
+}
+
+// copyThemed copies r to w through ApplyTheme as output arrives, so a
+// prompt shows before its line ends. A character split across reads waits
+// for the rest of it.
+func copyThemed(w io.Writer, r io.Reader, theme string) {
+	buf := make([]byte, 32*1024)
+	var pending []byte
+	for {
+		n, err := r.Read(buf)
+		pending = append(pending, buf[:n]...)
+		end := len(pending)
+		for i := len(pending) - 1; i >= 0 && i >= len(pending)-utf8.UTFMax; i-- {
+			if utf8.RuneStart(pending[i]) {
+				if !utf8.FullRune(pending[i:]) {
+					end = i
+				}
+				break
+			}
+		}
+		if end > 0 {
+			io.WriteString(w, ApplyTheme(string(pending[:end]), theme))
+			pending = pending[end:]
+		}
+		if err != nil {
+			if len(pending) > 0 {
+				io.WriteString(w, ApplyTheme(string(pending), theme))
+			}
+			return
+		}
+	}
+}
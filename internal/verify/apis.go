@@ -0,0 +1,83 @@
+package verify
+
+import (
+	"regexp"
+	"strings"
+)
+
+// API is a public declaration or route a diff adds.
+type API struct {
+	File string
+	Name string // e.g. "func ParseToken", "POST /api/login"
+}
+
+// apiPatterns match lines that declare something other code can use. The
+// first group is the kind, the last the name.
+var apiPatterns = []*regexp.Regexp{
+	// Go: exported funcs, methods and types
+	regexp.MustCompile(`^(func) (?:\([^)]*\) )?([A-Z]\w*)`),
+	regexp.MustCompile(`^(type) ([A-Z]\w*)`),
+	// JavaScript and TypeScript exports
+	regexp.MustCompile(`^export (?:default )?(?:async )?(function|class|const|interface|type|enum) (\w+)`),
+	// Python: top-level public defs and classes
+	regexp.MustCompile(`^(def|class) ([A-Za-z]\w*)`),
+	// Rust
+	regexp.MustCompile(`^pub (fn|struct|enum|trait) (\w+)`),
+}
+
+// routePattern matches HTTP route registrations such as
+// router.post("/api/login", ...), @app.get('/health') and
+// mux.HandleFunc("GET /users", ...).
+var routePattern = regexp.MustCompile(`(?i)\.(get|post|put|patch|delete|handlefunc|handle|route)\(\s*["']((?:(?:GET|POST|PUT|PATCH|DELETE) )?/[^"']*)["']`)
+
+// DeclaredAPIs lists the public declarations and routes a unified diff
+// adds, in diff order. Test files are skipped.
+func DeclaredAPIs(diff string) []API {
+	var apis []API
+	seen := make(map[API]bool)
+	file := ""
+	for _, line := range strings.Split(diff, "\n") {
+		if strings.HasPrefix(line, "+++ ") {
+			file = strings.TrimPrefix(strings.TrimPrefix(line, "+++ "), "b/")
+			if isTestFile(file) {
+				file = ""
+			}
+			continue
+		}
+		if file == "" || !strings.HasPrefix(line, "+") {
+			continue
+		}
+		code := line[1:]
+
+		name := ""
+		for _, p := range apiPatterns {
+			if m := p.FindStringSubmatch(code); m != nil {
+				name = m[1] + " " + m[2]
+				break
+			}
+		}
+		if m := routePattern.FindStringSubmatch(code); name == "" && m != nil {
+			name = m[2]
+			if method := strings.ToUpper(m[1]); !strings.Contains(name, " ") && method != "HANDLEFUNC" && method != "HANDLE" && method != "ROUTE" {
+				name = method + " " + name
+			}
+		}
+		if name == "" {
+			continue
+		}
+		api := API{File: file, Name: name}
+		if !seen[api] {
+			seen[api] = true
+			apis = append(apis, api)
+		}
+	}
+	return apis
+}
+
+// isTestFile reports whether a path looks like a test.
+func isTestFile(path string) bool {
+	base := path[strings.LastIndex(path, "/")+1:]
+	return strings.HasSuffix(base, "_test.go") || strings.HasPrefix(base, "test_") ||
+		strings.Contains(base, ".test.") || strings.Contains(base, ".spec.") ||
+		strings.HasPrefix(path, "tests/") || strings.Contains(path, "/tests/")
+}
@@ -0,0 +1,44 @@
+package verify
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDeclaredAPIs(t *testing.T) {
+	diff := `diff --git a/internal/auth/token.go b/internal/auth/token.go
+--- a/internal/auth/token.go
++++ b/internal/auth/token.go
+@@ -1,3 +1,12 @@
++type Claims struct {
++func ParseToken(s string) (*Claims, error) {
++func (c *Claims) Expired() bool {
++func parseHeader(s string) string {
+ func Existing() {}
+-func Removed() {}
+diff --git a/web/api.ts b/web/api.ts
+--- a/web/api.ts
++++ b/web/api.ts
+@@ -0,0 +1,4 @@
++export async function login(user: string) {
++router.post("/api/login", handler)
++mux.HandleFunc("GET /users", listUsers)
++const internal = 1
+diff --git a/internal/auth/token_test.go b/internal/auth/token_test.go
+--- a/internal/auth/token_test.go
++++ b/internal/auth/token_test.go
+@@ -0,0 +1 @@
++func TestParseToken(t *testing.T) {
+`
+	want := []API{
+		{"internal/auth/token.go", "type Claims"},
+		{"internal/auth/token.go", "func ParseToken"},
+		{"internal/auth/token.go", "func Expired"},
+		{"web/api.ts", "function login"},
+		{"web/api.ts", "POST /api/login"},
+		{"web/api.ts", "GET /users"},
+	}
+	if got := DeclaredAPIs(diff); !reflect.DeepEqual(got, want) {
+		t.Errorf("DeclaredAPIs() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,180 @@
+package verify
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"brigade/internal/prd"
+)
+
+// maxBrowserFailures caps the failed tests listed in a browser summary.
+const maxBrowserFailures = 10
+
+var (
+	// failedTestPattern matches a failed test's heading in Playwright's
+	// list and line reporters: "  1) [chromium] › login.spec.ts:5:5 › logs in ───"
+	failedTestPattern = regexp.MustCompile(`^\s*\d+\) (.+?)[\s─]*$`)
+
+	// testErrorPattern matches the first line of a test's error.
+	testErrorPattern = regexp.MustCompile(`^\s*(\w*Error:.*)$`)
+)
+
+// runBrowser runs a browser verification command: the verification's own
+// command, or BrowserCmd. The runner's HTML report goes to ArtifactsDir,
+// the screenshots and traces it leaves in BrowserResultsDir are moved
+// there, and a failure's output ends with a summary of the failed tests
+// and the artifacts to look at.
+func (r *Runner) runBrowser(ctx context.Context, command string, vType prd.VerificationType) CommandResult {
+	if command == "" {
+		command = r.BrowserCmd
+	}
+	if command == "" {
+		return CommandResult{Command: "(browser)", Type: vType, Error: "no cmd, and BROWSER_TEST_CMD is not set"}
+	}
+
+	runner := *r
+	if r.BrowserTimeout > 0 {
+		runner.Timeout = r.BrowserTimeout
+	}
+	// Never serve the report and wait for someone to close it
+	runner.Env = append(append([]string(nil), r.Env...), "PLAYWRIGHT_HTML_OPEN=never", "PW_TEST_HTML_REPORT_OPEN=never")
+
+	var dir string
+	if r.ArtifactsDir != "" {
+		abs, err := filepath.Abs(r.ArtifactsDir)
+		if err == nil && os.MkdirAll(abs, 0755) == nil {
+			dir = abs
+			runner.Env = append(runner.Env,
+				"BRIGADE_ARTIFACTS_DIR="+dir,
+				"PLAYWRIGHT_HTML_REPORT="+filepath.Join(dir, "report"))
+		}
+	}
+
+	start := time.Now()
+	result := runner.runCommand(ctx, command, vType)
+
+	var artifacts []string
+	if dir != "" {
+		if r.BrowserResultsDir != "" {
+			collectArtifacts(filepath.Join(r.WorkingDir, r.BrowserResultsDir), dir, start)
+		}
+		artifacts = ListArtifacts(dir)
+	}
+	if !result.Passed {
+		if summary := BrowserSummary(result.Output, r.ArtifactsDir, artifacts); summary != "" {
+			result.Output = strings.TrimRight(result.Output, "\n") + "\n\n" + summary
+		}
+	}
+	return result
+}
+
+// BrowserSummary summarizes a failed browser test run for the retry
+// prompt: each failed test with the first line of its error, then the
+// artifacts kept in dir. Returns "" when there's nothing to add.
+func BrowserSummary(output, dir string, artifacts []string) string {
+	var sb strings.Builder
+
+	seen := make(map[string]bool)
+	var current string
+	failures := 0
+	for _, line := range strings.Split(output, "\n") {
+		if m := failedTestPattern.FindStringSubmatch(line); m != nil {
+			current = ""
+			if seen[m[1]] || failures >= maxBrowserFailures {
+				continue
+			}
+			seen[m[1]] = true
+			failures++
+			if failures == 1 {
+				sb.WriteString("Failed browser tests:\n")
+			}
+			sb.WriteString("  " + m[1] + "\n")
+			current = m[1]
+			continue
+		}
+		if m := testErrorPattern.FindStringSubmatch(line); m != nil && current != "" {
+			sb.WriteString("    " + strings.TrimSpace(m[1]) + "\n")
+			current = ""
+		}
+	}
+
+	if len(artifacts) > 0 {
+		sb.WriteString(fmt.Sprintf("Artifacts in %s: %s\n", dir, strings.Join(artifacts, ", ")))
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+// collectArtifacts moves the files written to src since start into dst,
+// keeping their paths relative to src.
+func collectArtifacts(src, dst string, start time.Time) {
+	since := start.Truncate(time.Second)
+	filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil || info.ModTime().Before(since) {
+			return nil
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return nil
+		}
+		target := filepath.Join(dst, rel)
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return nil
+		}
+		if os.Rename(path, target) != nil {
+			copyFile(path, target)
+		}
+		return nil
+	})
+}
+
+// ListArtifacts lists the files in an artifacts directory, relative to it.
+// An HTML report is listed by its index page.
+func ListArtifacts(dir string) []string {
+	var files []string
+	filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		rel, _ := filepath.Rel(dir, path)
+		if d.IsDir() {
+			if rel == "report" {
+				if _, err := os.Stat(filepath.Join(path, "index.html")); err == nil {
+					files = append(files, filepath.ToSlash(filepath.Join(rel, "index.html")))
+				}
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		files = append(files, filepath.ToSlash(rel))
+		return nil
+	})
+	return files
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,50 @@
+package verify
+
+import (
+	"strings"
+	"testing"
+)
+
+const playwrightOutput = `Running 3 tests using 1 worker
+
+  ✓  1 [chromium] › home.spec.ts:3:5 › shows the title (512ms)
+  ✘  2 [chromium] › login.spec.ts:5:5 › logs in (5.1s)
+  ✘  3 [chromium] › login.spec.ts:20:5 › rejects a bad password (1.2s)
+
+  1) [chromium] › login.spec.ts:5:5 › logs in ──────────────────────────────────
+
+    Error: Timed out 5000ms waiting for expect(page).toHaveURL(expected)
+
+    Expected pattern: /dashboard/
+    Received string:  "http://localhost:3000/login"
+
+  2) [chromium] › login.spec.ts:20:5 › rejects a bad password ─────────────────
+
+    TimeoutError: locator.click: Timeout 1000ms exceeded.
+
+  2 failed
+  1 passed (7.4s)
+`
+
+func TestBrowserSummary(t *testing.T) {
+	got := BrowserSummary(playwrightOutput, "brigade/artifacts/r/US-001", []string{"login-logs-in/test-failed-1.png", "report/index.html"})
+	want := `Failed browser tests:
+  [chromium] › login.spec.ts:5:5 › logs in
+    Error: Timed out 5000ms waiting for expect(page).toHaveURL(expected)
+  [chromium] › login.spec.ts:20:5 › rejects a bad password
+    TimeoutError: locator.click: Timeout 1000ms exceeded.
+Artifacts in brigade/artifacts/r/US-001: login-logs-in/test-failed-1.png, report/index.html`
+	if got != want {
+		t.Errorf("BrowserSummary() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestBrowserSummaryOtherRunner(t *testing.T) {
+	if got := BrowserSummary("cypress run: 1 of 4 failed", "", nil); got != "" {
+		t.Errorf("BrowserSummary() = %q, want empty", got)
+	}
+	got := BrowserSummary("failed", "dir", []string{"shot.png"})
+	if !strings.HasPrefix(got, "Artifacts in dir: shot.png") {
+		t.Errorf("BrowserSummary() = %q, want the artifacts", got)
+	}
+}
@@ -0,0 +1,78 @@
+package verify
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Pre-commit hook managers DetectHooks recognizes.
+const (
+	HooksPreCommit = "pre-commit" // pre-commit.com
+	HooksLefthook  = "lefthook"
+	HooksHusky     = "husky"
+	HooksGit       = "git" // A plain hook in .git/hooks or core.hooksPath
+)
+
+// DetectHooks returns the pre-commit hook manager a repo uses, or "" if it
+// has no pre-commit hook. A manager's config wins over the shim it installs
+// in .git/hooks.
+func DetectHooks(dir string) string {
+	exists := func(name string) bool {
+		_, err := os.Stat(filepath.Join(dir, name))
+		return err == nil
+	}
+	switch {
+	case exists(".pre-commit-config.yaml"):
+		return HooksPreCommit
+	case exists("lefthook.yml") || exists(".lefthook.yml") || exists("lefthook.yaml") || exists(".lefthook.yaml"):
+		return HooksLefthook
+	case exists(filepath.Join(".husky", "pre-commit")):
+		return HooksHusky
+	}
+
+	hooksDir := filepath.Join(dir, ".git", "hooks")
+	cmd := exec.Command("git", "config", "core.hooksPath")
+	cmd.Dir = dir
+	if output, err := cmd.Output(); err == nil && strings.TrimSpace(string(output)) != "" {
+		hooksDir = strings.TrimSpace(string(output))
+		if !filepath.IsAbs(hooksDir) {
+			hooksDir = filepath.Join(dir, hooksDir)
+		}
+	}
+	if info, err := os.Stat(filepath.Join(hooksDir, "pre-commit")); err == nil && info.Mode()&0111 != 0 {
+		return HooksGit
+	}
+	return ""
+}
+
+// HooksCommand returns the command that runs a hook manager's pre-commit
+// hooks against files. Husky and plain git hooks can't be pointed at files,
+// so they run as they would on commit.
+func HooksCommand(manager string, files []string) string {
+	var quoted []string
+	for _, f := range files {
+		quoted = append(quoted, shellQuote(f))
+	}
+	switch manager {
+	case HooksPreCommit:
+		return "pre-commit run --color never --files " + strings.Join(quoted, " ")
+	case HooksLefthook:
+		var args []string
+		for _, f := range quoted {
+			args = append(args, "--file "+f)
+		}
+		return "lefthook run pre-commit " + strings.Join(args, " ")
+	case HooksHusky:
+		return "sh .husky/pre-commit"
+	case HooksGit:
+		return "git hook run --ignore-missing pre-commit"
+	}
+	return ""
+}
+
+// shellQuote quotes s for sh.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,52 @@
+package verify
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDetectHooks(t *testing.T) {
+	tests := []struct {
+		files []string
+		want  string
+	}{
+		{nil, ""},
+		{[]string{".pre-commit-config.yaml", ".husky/pre-commit"}, HooksPreCommit},
+		{[]string{"lefthook.yml"}, HooksLefthook},
+		{[]string{".husky/pre-commit"}, HooksHusky},
+		{[]string{".git/hooks/pre-commit"}, HooksGit},
+		{[]string{".git/hooks/pre-commit.sample"}, ""},
+	}
+
+	for _, tt := range tests {
+		dir := t.TempDir()
+		for _, f := range tt.files {
+			path := filepath.Join(dir, f)
+			os.MkdirAll(filepath.Dir(path), 0755)
+			os.WriteFile(path, []byte("#!/bin/sh\n"), 0755)
+		}
+		if got := DetectHooks(dir); got != tt.want {
+			t.Errorf("DetectHooks(%v) = %q, want %q", tt.files, got, tt.want)
+		}
+	}
+}
+
+func TestHooksCommand(t *testing.T) {
+	files := []string{"main.go", "it's.go"}
+	tests := []struct {
+		manager string
+		want    string
+	}{
+		{HooksPreCommit, `pre-commit run --color never --files 'main.go' 'it'\''s.go'`},
+		{HooksLefthook, `lefthook run pre-commit --file 'main.go' --file 'it'\''s.go'`},
+		{HooksHusky, "sh .husky/pre-commit"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := HooksCommand(tt.manager, files); got != tt.want {
+			t.Errorf("HooksCommand(%q) = %q, want %q", tt.manager, got, tt.want)
+		}
+	}
+}
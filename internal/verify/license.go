@@ -0,0 +1,150 @@
+package verify
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// licenseHeadLines is how far into a file its license header is looked for.
+const licenseHeadLines = 40
+
+// commentStyle is how a file type writes a comment block: each line
+// prefixed with Line, or wrapped in Open and Close.
+type commentStyle struct {
+	Open, Line, Close string
+}
+
+var (
+	slashComments = commentStyle{Line: "// "}
+	hashComments  = commentStyle{Line: "# "}
+	dashComments  = commentStyle{Line: "-- "}
+	blockComments = commentStyle{Open: "/*", Line: " * ", Close: " */"}
+	htmlComments  = commentStyle{Open: "<!--", Line: "  ", Close: "-->"}
+)
+
+// commentStyles maps file extensions to their comment style. Files of other
+// types, such as JSON and Markdown, aren't checked for a header.
+var commentStyles = map[string]commentStyle{
+	".go": slashComments, ".js": slashComments, ".jsx": slashComments, ".mjs": slashComments,
+	".cjs": slashComments, ".ts": slashComments, ".tsx": slashComments, ".java": slashComments,
+	".kt": slashComments, ".kts": slashComments, ".swift": slashComments, ".rs": slashComments,
+	".c": slashComments, ".h": slashComments, ".cc": slashComments, ".cpp": slashComments,
+	".hpp": slashComments, ".cs": slashComments, ".scala": slashComments, ".dart": slashComments,
+	".php": slashComments, ".proto": slashComments,
+	".py": hashComments, ".rb": hashComments, ".sh": hashComments, ".bash": hashComments,
+	".zsh": hashComments, ".pl": hashComments, ".r": hashComments, ".yaml": hashComments,
+	".yml": hashComments, ".toml": hashComments, ".tf": hashComments,
+	".sql": dashComments, ".lua": dashComments, ".hs": dashComments,
+	".css": blockComments, ".scss": blockComments, ".less": blockComments,
+	".html": htmlComments, ".xml": htmlComments, ".vue": htmlComments, ".svelte": htmlComments,
+}
+
+// commentStyleFor returns the comment style of a file, if it has one.
+func commentStyleFor(file string) (commentStyle, bool) {
+	switch path.Base(file) {
+	case "Dockerfile", "Makefile":
+		return hashComments, true
+	}
+	style, ok := commentStyles[strings.ToLower(path.Ext(file))]
+	return style, ok
+}
+
+// LoadLicenseHeader reads a LICENSE_HEADER_FILE: the header's text, without
+// comment markers. {{year}} stands for the year.
+func LoadLicenseHeader(file string) (string, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return "", err
+	}
+	header := strings.TrimSpace(string(data))
+	if header == "" {
+		return "", fmt.Errorf("%s is empty", file)
+	}
+	return header, nil
+}
+
+// LicenseHeaderApplies reports whether a file's type can carry a header.
+func LicenseHeaderApplies(file string) bool {
+	_, ok := commentStyleFor(file)
+	return ok
+}
+
+// HasLicenseHeader reports whether a file's content starts with the header,
+// in any comment style and with any year (or range of years) for {{year}}.
+func HasLicenseHeader(content, header string) bool {
+	head := strings.Split(content, "\n")
+	if len(head) > licenseHeadLines {
+		head = head[:licenseHeadLines]
+	}
+	return licensePattern(header).MatchString(stripComments(head))
+}
+
+// AddLicenseHeader returns a file's content with the header, commented in
+// the file's style for year, at the top: after a shebang or XML
+// declaration, and followed by a blank line so it isn't taken for a doc
+// comment.
+func AddLicenseHeader(file, content, header string, year int) (string, bool) {
+	style, ok := commentStyleFor(file)
+	if !ok {
+		return content, false
+	}
+
+	var sb strings.Builder
+	if style.Open != "" {
+		sb.WriteString(style.Open + "\n")
+	}
+	text := strings.ReplaceAll(header, "{{year}}", strconv.Itoa(year))
+	for _, line := range strings.Split(text, "\n") {
+		sb.WriteString(strings.TrimRight(style.Line+line, " ") + "\n")
+	}
+	if style.Close != "" {
+		sb.WriteString(style.Close + "\n")
+	}
+	sb.WriteString("\n")
+
+	var prolog string
+	if strings.HasPrefix(content, "#!") || strings.HasPrefix(content, "<?xml") || strings.HasPrefix(content, "<?php") {
+		i := strings.Index(content, "\n")
+		if i < 0 {
+			return content + "\n" + sb.String(), true
+		}
+		prolog, content = content[:i+1], content[i+1:]
+	}
+	return prolog + sb.String() + content, true
+}
+
+// commentMarkers are stripped from a file's first lines before they're
+// compared with a header.
+var commentMarkers = regexp.MustCompile(`^\s*(?://+|#+|--|/\*+|\*+/?|<!--|-->|;+)?\s*`)
+
+// stripComments joins lines without their comment markers, dropping the
+// lines left empty.
+func stripComments(lines []string) string {
+	var kept []string
+	for _, line := range lines {
+		line = strings.TrimSpace(commentMarkers.ReplaceAllString(line, ""))
+		line = strings.TrimSpace(strings.TrimSuffix(strings.TrimSuffix(line, "*/"), "-->"))
+		if line != "" {
+			kept = append(kept, line)
+		}
+	}
+	return strings.Join(kept, "\n")
+}
+
+// licensePattern matches a header's lines, stripped like a file's, with
+// {{year}} matching a year or a range of years.
+func licensePattern(header string) *regexp.Regexp {
+	lines := strings.Split(stripComments(strings.Split(header, "\n")), "\n")
+	for i, line := range lines {
+		parts := strings.Split(line, "{{year}}")
+		for j := range parts {
+			parts[j] = regexp.QuoteMeta(parts[j])
+		}
+		lines[i] = strings.Join(parts, `\d{4}(?:\s*[-,]\s*\d{4})*`)
+	}
+	return regexp.MustCompile(`(?m)^` + strings.Join(lines, `\n`))
+}
@@ -0,0 +1,56 @@
+package verify
+
+import "testing"
+
+const testHeader = "Copyright {{year}} Acme Corp.\nSPDX-License-Identifier: Apache-2.0"
+
+func TestHasLicenseHeader(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    bool
+	}{
+		{"go", "// Copyright 2024 Acme Corp.\n// SPDX-License-Identifier: Apache-2.0\n\npackage main\n", true},
+		{"year range", "# Copyright 2019-2024 Acme Corp.\n# SPDX-License-Identifier: Apache-2.0\nimport os\n", true},
+		{"block", "/*\n * Copyright 2024 Acme Corp.\n * SPDX-License-Identifier: Apache-2.0\n */\nbody {}\n", true},
+		{"after shebang", "#!/bin/sh\n# Copyright 2024 Acme Corp.\n# SPDX-License-Identifier: Apache-2.0\n", true},
+		{"missing", "package main\n", false},
+		{"other owner", "// Copyright 2024 Someone Else\n// SPDX-License-Identifier: Apache-2.0\n", false},
+		{"partial", "// Copyright 2024 Acme Corp.\n\npackage main\n", false},
+	}
+
+	for _, tt := range tests {
+		if got := HasLicenseHeader(tt.content, testHeader); got != tt.want {
+			t.Errorf("%s: HasLicenseHeader() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestAddLicenseHeader(t *testing.T) {
+	tests := []struct {
+		file    string
+		content string
+		want    string
+	}{
+		{"main.go", "package main\n",
+			"// Copyright 2026 Acme Corp.\n// SPDX-License-Identifier: Apache-2.0\n\npackage main\n"},
+		{"run.sh", "#!/bin/sh\necho hi\n",
+			"#!/bin/sh\n# Copyright 2026 Acme Corp.\n# SPDX-License-Identifier: Apache-2.0\n\necho hi\n"},
+		{"app.css", "body {}\n",
+			"/*\n * Copyright 2026 Acme Corp.\n * SPDX-License-Identifier: Apache-2.0\n */\n\nbody {}\n"},
+	}
+
+	for _, tt := range tests {
+		got, ok := AddLicenseHeader(tt.file, tt.content, testHeader, 2026)
+		if !ok || got != tt.want {
+			t.Errorf("AddLicenseHeader(%s) = %q, %v, want %q", tt.file, got, ok, tt.want)
+		}
+		if !HasLicenseHeader(got, testHeader) {
+			t.Errorf("HasLicenseHeader(AddLicenseHeader(%s)) = false", tt.file)
+		}
+	}
+
+	if _, ok := AddLicenseHeader("data.json", "{}\n", testHeader, 2026); ok {
+		t.Error("AddLicenseHeader(data.json) added a header to JSON")
+	}
+}
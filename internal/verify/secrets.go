@@ -0,0 +1,234 @@
+package verify
+
+import (
+	"math"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// SecretRule is a pattern for one kind of secret. When the pattern has a
+// group, the group is the secret. Rules with MinEntropy only match values
+// that look random, so placeholders and variable names don't trip them.
+type SecretRule struct {
+	Name       string
+	Pattern    *regexp.Regexp
+	MinEntropy float64
+}
+
+// DefaultSecretRules are the secrets looked for in diffs, after gitleaks'
+// rules for the most common providers plus a generic keyword rule.
+var DefaultSecretRules = []SecretRule{
+	{Name: "AWS access key", Pattern: regexp.MustCompile(`\b((?:A3T[A-Z0-9]|AKIA|ASIA|ABIA|ACCA)[A-Z2-7]{16})\b`)},
+	{Name: "GitHub token", Pattern: regexp.MustCompile(`\b((?:ghp|gho|ghu|ghs|ghr)_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{60,})\b`)},
+	{Name: "GitLab token", Pattern: regexp.MustCompile(`\b(glpat-[A-Za-z0-9_-]{20,})`)},
+	{Name: "Slack token", Pattern: regexp.MustCompile(`\b(xox[abposr]-[A-Za-z0-9-]{10,})`)},
+	{Name: "Slack webhook", Pattern: regexp.MustCompile(`(https://hooks\.slack\.com/services/[A-Za-z0-9+/]{40,})`)},
+	{Name: "Stripe key", Pattern: regexp.MustCompile(`\b((?:sk|rk)_(?:live|test)_[A-Za-z0-9]{20,})\b`)},
+	{Name: "OpenAI key", Pattern: regexp.MustCompile(`\b(sk-(?:proj-)?[A-Za-z0-9_-]{20,}T3BlbkFJ[A-Za-z0-9_-]{20,})`)},
+	{Name: "Anthropic key", Pattern: regexp.MustCompile(`\b(sk-ant-[a-z]+\d{2}-[A-Za-z0-9_-]{80,})`)},
+	{Name: "Google API key", Pattern: regexp.MustCompile(`\b(AIza[0-9A-Za-z_-]{35})\b`)},
+	{Name: "private key", Pattern: regexp.MustCompile(`-----BEGIN[ A-Z0-9_-]{0,100}PRIVATE KEY(?: BLOCK)?-----`)},
+	{Name: "JWT", Pattern: regexp.MustCompile(`\b(eyJ[A-Za-z0-9_-]{10,}\.eyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,})`)},
+	{Name: "password in URL", Pattern: regexp.MustCompile(`[a-z][a-z0-9+.-]*://[^/\s:@]+:([^/\s:@]{8,})@`), MinEntropy: 3},
+	{Name: "generic secret", Pattern: regexp.MustCompile(
+		`(?i)(?:api[_-]?key|secret|token|passw(?:or)?d|credentials?|access[_-]?key|auth[_-]?key)[\w.-]{0,20}["']?\s*(?::=|=>|:|=)\s*["'` + "`" + `]?([A-Za-z0-9+/_~.=-]{16,})`),
+		MinEntropy: 3.5},
+}
+
+// secretAllowMarkers on a line let a deliberate secret, such as a test
+// fixture, through.
+var secretAllowMarkers = []string{"brigade:allow-secret", "gitleaks:allow"}
+
+// secretPlaceholders are parts of values that are documentation, not secrets.
+var secretPlaceholders = []string{"example", "xxxx", "placeholder", "changeme", "dummy", "redacted", "your_", "your-"}
+
+// lockfiles hold integrity hashes that look like secrets.
+var lockfiles = map[string]bool{
+	"package-lock.json": true,
+	"yarn.lock":         true,
+	"pnpm-lock.yaml":    true,
+	"go.sum":            true,
+	"Cargo.lock":        true,
+	"poetry.lock":       true,
+	"Gemfile.lock":      true,
+	"composer.lock":     true,
+}
+
+// SecretFinding is a secret added by a diff.
+type SecretFinding struct {
+	File   string
+	Line   int
+	Rule   string
+	Secret string // Redacted
+	Commit string // Commit that added it, if the diff came from git log -p
+}
+
+// String describes the finding for a retry prompt, without the secret.
+func (f SecretFinding) String() string {
+	s := f.File + ":" + strconv.Itoa(f.Line) + ": " + f.Rule + " (" + f.Secret + ")"
+	if f.Commit != "" {
+		s += " in commit " + shortCommit(f.Commit)
+	}
+	return s
+}
+
+// SecretScanner looks for secrets in the lines a diff adds.
+type SecretScanner struct {
+	rules  []SecretRule
+	ignore []string
+}
+
+// NewSecretScanner creates a scanner with the default rules.
+func NewSecretScanner() *SecretScanner {
+	return &SecretScanner{rules: DefaultSecretRules}
+}
+
+// SetIgnore sets globs of files not to scan. A glob matches a file's path,
+// its name, or one of its directories, so "testdata" skips everything under
+// any testdata directory.
+func (s *SecretScanner) SetIgnore(globs []string) {
+	s.ignore = globs
+}
+
+// ScanDiff returns the secrets on added lines of a unified diff, or of the
+// output of git log -p. The same secret in the same file is reported once.
+func (s *SecretScanner) ScanDiff(diff string) []SecretFinding {
+	var findings []SecretFinding
+	seen := make(map[string]bool)
+	var commit, file string
+	line := 0
+	for _, text := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(text, "commit "):
+			commit = strings.TrimSpace(strings.TrimPrefix(text, "commit "))
+			file = ""
+		case strings.HasPrefix(text, "+++ "):
+			file = strings.TrimPrefix(strings.TrimPrefix(text, "+++ "), "b/")
+			if file == "/dev/null" || s.ignored(file) {
+				file = ""
+			}
+		case strings.HasPrefix(text, "@@"):
+			line = hunkStart(text)
+		case file == "" || strings.HasPrefix(text, "---"):
+		case strings.HasPrefix(text, "+"):
+			for _, f := range s.scanLine(text[1:]) {
+				key := file + "\x00" + f.Rule + "\x00" + f.Secret
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+				f.File, f.Line, f.Commit = file, line, commit
+				findings = append(findings, f)
+			}
+			line++
+		case strings.HasPrefix(text, " "):
+			line++
+		}
+	}
+	return findings
+}
+
+// scanLine returns the secrets in one added line.
+func (s *SecretScanner) scanLine(text string) []SecretFinding {
+	for _, marker := range secretAllowMarkers {
+		if strings.Contains(text, marker) {
+			return nil
+		}
+	}
+	var findings []SecretFinding
+	for _, rule := range s.rules {
+		for _, m := range rule.Pattern.FindAllStringSubmatch(text, -1) {
+			secret := m[0]
+			if len(m) > 1 {
+				secret = m[1]
+			}
+			if isPlaceholder(secret) {
+				continue
+			}
+			if rule.MinEntropy > 0 && (!strings.ContainsAny(secret, "0123456789") || shannonEntropy(secret) < rule.MinEntropy) {
+				continue
+			}
+			findings = append(findings, SecretFinding{Rule: rule.Name, Secret: redact(secret)})
+		}
+		if len(findings) > 0 {
+			// The first rule to match names the secret best
+			return findings
+		}
+	}
+	return nil
+}
+
+// ignored reports whether a file is skipped: lockfiles and the ignore globs.
+func (s *SecretScanner) ignored(file string) bool {
+	if lockfiles[path.Base(file)] {
+		return true
+	}
+	for _, glob := range s.ignore {
+		for p := file; p != "." && p != "/"; p = path.Dir(p) {
+			if ok, _ := path.Match(glob, p); ok {
+				return true
+			}
+			if ok, _ := path.Match(glob, path.Base(p)); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hunkStart returns the first new-file line of a hunk header
+// ("@@ -1,4 +1,6 @@").
+func hunkStart(header string) int {
+	i := strings.Index(header, "+")
+	if i < 0 {
+		return 0
+	}
+	rest := header[i+1:]
+	if j := strings.IndexAny(rest, ", "); j >= 0 {
+		rest = rest[:j]
+	}
+	n, _ := strconv.Atoi(rest)
+	return n
+}
+
+func isPlaceholder(secret string) bool {
+	lower := strings.ToLower(secret)
+	for _, p := range secretPlaceholders {
+		if strings.Contains(lower, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// shannonEntropy returns the bits of entropy per character of s.
+func shannonEntropy(s string) float64 {
+	counts := make(map[rune]int)
+	for _, r := range s {
+		counts[r]++
+	}
+	n := float64(len([]rune(s)))
+	var entropy float64
+	for _, c := range counts {
+		p := float64(c) / n
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
+
+// redact keeps enough of a secret to find it again.
+func redact(secret string) string {
+	if len(secret) <= 8 {
+		return "****"
+	}
+	return secret[:4] + "****"
+}
+
+func shortCommit(commit string) string {
+	if len(commit) > 7 {
+		return commit[:7]
+	}
+	return commit
+}
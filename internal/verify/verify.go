@@ -5,7 +5,9 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -22,6 +24,16 @@ type Result struct {
 
 	// Duration is the total verification time
 	Duration time.Duration
+
+	// Logs is output captured around the commands, such as the dev
+	// servers they ran against
+	Logs []Log
+}
+
+// Log is named output captured during verification.
+type Log struct {
+	Name   string
+	Output string
 }
 
 // CommandResult holds the result of a single verification command.
@@ -46,6 +58,12 @@ type CommandResult struct {
 
 	// ExitCode of the command
 	ExitCode int
+
+	// Flaky is true if the command failed and then passed on a retry
+	Flaky bool
+
+	// Retries is how many times the command was re-run after failing
+	Retries int
 }
 
 // Runner runs verification commands.
@@ -58,6 +76,27 @@ type Runner struct {
 
 	// Quiet suppresses output
 	Quiet bool
+
+	// FlakyRetries is how many times a failing command is re-run before it
+	// counts as failed
+	FlakyRetries int
+
+	// Env are additional environment variables for commands
+	Env []string
+
+	// BrowserCmd runs browser verification that has no command of its own
+	BrowserCmd string
+
+	// BrowserTimeout replaces Timeout for browser verification
+	BrowserTimeout time.Duration
+
+	// BrowserResultsDir is where the browser test runner leaves screenshots
+	// and traces, relative to WorkingDir
+	BrowserResultsDir string
+
+	// ArtifactsDir is where browser verification's screenshots, traces and
+	// report are kept; empty leaves them where the runner wrote them
+	ArtifactsDir string
 }
 
 // NewRunner creates a new verification runner.
@@ -68,6 +107,22 @@ func NewRunner(timeout time.Duration, workingDir string) *Runner {
 	}
 }
 
+// In returns a copy of the runner that runs commands in dir, relative to
+// the runner's own working directory.
+func (r *Runner) In(dir string) *Runner {
+	scoped := *r
+	scoped.WorkingDir = filepath.Join(r.WorkingDir, dir)
+	return &scoped
+}
+
+// WithEnv returns a copy of the runner that adds env to its commands'
+// environment.
+func (r *Runner) WithEnv(env ...string) *Runner {
+	scoped := *r
+	scoped.Env = append(append([]string(nil), r.Env...), env...)
+	return &scoped
+}
+
 // Run executes all verification commands for a task.
 func (r *Runner) Run(ctx context.Context, task *prd.Task) (*Result, error) {
 	if len(task.Verification) == 0 {
@@ -81,7 +136,20 @@ func (r *Runner) Run(ctx context.Context, task *prd.Task) (*Result, error) {
 	}
 
 	for _, v := range task.Verification {
-		cmdResult := r.runCommand(ctx, v.Cmd, v.Type)
+		run := r.runCommand
+		if v.Type == prd.VerificationBrowser {
+			run = r.runBrowser
+		}
+		cmdResult := run(ctx, v.Cmd, v.Type)
+
+		// Re-run failures; nothing changed in between, so a pass means the
+		// command is flaky rather than the work being wrong
+		for retry := 1; !cmdResult.Passed && retry <= r.FlakyRetries && ctx.Err() == nil; retry++ {
+			again := run(ctx, v.Cmd, v.Type)
+			again.Retries = retry
+			again.Flaky = again.Passed
+			cmdResult = again
+		}
 		result.Results = append(result.Results, cmdResult)
 
 		if !cmdResult.Passed {
@@ -111,6 +179,9 @@ func (r *Runner) runCommand(ctx context.Context, command string, vType prd.Verif
 	if r.WorkingDir != "" {
 		cmd.Dir = r.WorkingDir
 	}
+	if len(r.Env) > 0 {
+		cmd.Env = append(os.Environ(), r.Env...)
+	}
 
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
@@ -208,7 +279,8 @@ func isGrepCommand(cmd string) bool {
 // HasExecutionTests returns true if the task has execution-based tests.
 func HasExecutionTests(task *prd.Task) bool {
 	for _, v := range task.Verification {
-		if v.Type == prd.VerificationUnit || v.Type == prd.VerificationIntegration || v.Type == prd.VerificationSmoke {
+		if v.Type == prd.VerificationUnit || v.Type == prd.VerificationIntegration || v.Type == prd.VerificationSmoke ||
+			v.Type == prd.VerificationBrowser {
 			return true
 		}
 		// Also check for common test commands
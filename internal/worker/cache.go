@@ -0,0 +1,151 @@
+package worker
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"brigade/internal/state"
+)
+
+// CacheEntry is one cached response.
+type CacheEntry struct {
+	Tier       state.WorkerTier `json:"tier"`
+	Command    string           `json:"command"`
+	CreatedAt  string           `json:"createdAt"`
+	DurationMs int64            `json:"durationMs"`
+	Output     string           `json:"output"`
+}
+
+// Cache keeps the responses to prompts whose answer depends only on the
+// prompt and the state of the code, such as executive reviews and
+// decisions, so a retry that changed nothing or a resumed run doesn't pay
+// for the same answer twice. Entries are files in dir, keyed by a hash of
+// the command, the prompt and a scope naming the code's state.
+type Cache struct {
+	dir string
+	ttl time.Duration
+
+	mu     sync.Mutex
+	hits   int
+	misses int
+	saved  time.Duration
+}
+
+// NewCache returns a cache whose entries last ttl.
+func NewCache(dir string, ttl time.Duration) *Cache {
+	return &Cache{dir: dir, ttl: ttl}
+}
+
+// Wrap returns a worker that answers a prompt from the cache when command
+// answered it against the same scope within the TTL, and caches the
+// answers it does run for. An empty scope (the code's state is unknown)
+// turns caching off.
+func (c *Cache) Wrap(w Worker, command, scope string) Worker {
+	if scope == "" {
+		return w
+	}
+	return &cachingWorker{Worker: w, cache: c, command: command, scope: scope}
+}
+
+// Stats returns how many prompts were answered from the cache, how many
+// weren't, and the worker time the hits saved.
+func (c *Cache) Stats() (hits, misses int, saved time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.hits, c.misses, c.saved
+}
+
+// key hashes what an answer depends on.
+func (c *Cache) key(command, scope, prompt string) string {
+	h := sha256.New()
+	for _, part := range []string{command, scope, prompt} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func (c *Cache) path(key string) string {
+	return filepath.Join(c.dir, key+".json")
+}
+
+// get returns a fresh entry, removing it if it has expired.
+func (c *Cache) get(key string, now time.Time) *CacheEntry {
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return nil
+	}
+	var entry CacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		os.Remove(c.path(key))
+		return nil
+	}
+	created, err := time.Parse(time.RFC3339, entry.CreatedAt)
+	if err != nil || now.Sub(created) > c.ttl {
+		os.Remove(c.path(key))
+		return nil
+	}
+	return &entry
+}
+
+func (c *Cache) put(key string, entry CacheEntry) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return
+	}
+	tmp := c.path(key) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return
+	}
+	os.Rename(tmp, c.path(key))
+}
+
+// cachingWorker answers from the cache, or runs the wrapped worker.
+type cachingWorker struct {
+	Worker
+	cache   *Cache
+	command string
+	scope   string
+}
+
+func (w *cachingWorker) Execute(ctx context.Context, prompt string) (*Result, error) {
+	key := w.cache.key(w.command, w.scope, prompt)
+	if entry := w.cache.get(key, time.Now()); entry != nil {
+		w.cache.mu.Lock()
+		w.cache.hits++
+		w.cache.saved += time.Duration(entry.DurationMs) * time.Millisecond
+		w.cache.mu.Unlock()
+
+		result := ParseOutput(entry.Output)
+		result.Usage = Usage{} // Spent when the answer was first given
+		result.Cached = true
+		return result, nil
+	}
+
+	w.cache.mu.Lock()
+	w.cache.misses++
+	w.cache.mu.Unlock()
+
+	result, err := w.Worker.Execute(ctx, prompt)
+	if err != nil || result == nil || result.Error != nil ||
+		result.Timeout || result.Crashed || result.Stalled || result.RateLimited {
+		return result, err
+	}
+	w.cache.put(key, CacheEntry{
+		Tier:       w.Tier(),
+		Command:    w.command,
+		CreatedAt:  time.Now().Format(time.RFC3339),
+		DurationMs: result.Duration.Milliseconds(),
+		Output:     result.Output,
+	})
+	return result, nil
+}
@@ -0,0 +1,54 @@
+package worker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"brigade/internal/state"
+)
+
+func TestCache(t *testing.T) {
+	cache := NewCache(t.TempDir(), time.Hour)
+	exec := NewMockWorker(DefaultConfig(state.TierExecutive), NewMockScript(0))
+	review := "=== REVIEW REQUEST ===\nID: US-001\n"
+
+	tests := []struct {
+		name       string
+		command    string
+		scope      string
+		prompt     string
+		wantCached bool
+	}{
+		{"first answer runs", "claude --model opus", "tree-a", review, false},
+		{"same prompt and code", "claude --model opus", "tree-a", review, true},
+		{"code changed", "claude --model opus", "tree-b", review, false},
+		{"another model", "claude --model sonnet", "tree-a", review, false},
+		{"another prompt", "claude --model opus", "tree-a", review + "again", false},
+		{"code state unknown", "claude --model opus", "", review, false},
+	}
+	for _, tt := range tests {
+		result, err := cache.Wrap(exec, tt.command, tt.scope).Execute(context.Background(), tt.prompt)
+		if err != nil {
+			t.Fatalf("%s: Execute() error = %v", tt.name, err)
+		}
+		if result.Cached != tt.wantCached {
+			t.Errorf("%s: Cached = %v, want %v", tt.name, result.Cached, tt.wantCached)
+		}
+		if result.Output == "" {
+			t.Errorf("%s: Output is empty", tt.name)
+		}
+	}
+
+	if hits, misses, _ := cache.Stats(); hits != 1 || misses != 4 {
+		t.Errorf("Stats() = %d hits, %d misses, want 1, 4", hits, misses)
+	}
+
+	key := cache.key("claude --model opus", "tree-a", review)
+	if cache.get(key, time.Now().Add(2*time.Hour)) != nil {
+		t.Error("get() returned an expired entry")
+	}
+	if cache.get(key, time.Now()) != nil {
+		t.Error("get() kept an expired entry")
+	}
+}
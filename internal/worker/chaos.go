@@ -0,0 +1,103 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ChaosRates are the per-execution probabilities of each injected failure.
+type ChaosRates struct {
+	Crash        float64 // worker dies without output
+	Timeout      float64 // worker is reported as timed out
+	Malformed    float64 // worker output has broken tags
+	Verification float64 // verification fails after a completed task
+}
+
+// Chaos injects failures into worker executions so error-handling paths
+// (escalation, smart retry, walkaway decisions, cleanup) get exercised.
+// It is meant for testing Brigade itself, never for real work.
+type Chaos struct {
+	rates ChaosRates
+	mu    sync.Mutex
+	rng   *rand.Rand
+}
+
+// NewChaos creates a failure injector. A zero seed uses the current time.
+func NewChaos(rates ChaosRates, seed int64) *Chaos {
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return &Chaos{rates: rates, rng: rand.New(rand.NewSource(seed))}
+}
+
+// Wrap returns a worker that may fail instead of (or after) running w.
+func (c *Chaos) Wrap(w Worker) Worker {
+	return &chaosWorker{Worker: w, chaos: c}
+}
+
+// FailVerification reports whether to inject a verification failure.
+func (c *Chaos) FailVerification() bool {
+	return c.roll(c.rates.Verification)
+}
+
+func (c *Chaos) roll(rate float64) bool {
+	if rate <= 0 {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.rng.Float64() < rate
+}
+
+// chaosWorker injects failures around a real worker.
+type chaosWorker struct {
+	Worker
+	chaos *Chaos
+}
+
+func (w *chaosWorker) Execute(ctx context.Context, prompt string) (*Result, error) {
+	// Crashes and timeouts skip the real worker entirely
+	if w.chaos.roll(w.chaos.rates.Crash) {
+		return &Result{
+			Output:   "chaos: injected crash\n",
+			ExitCode: 137,
+			Crashed:  true,
+			Error:    fmt.Errorf("chaos: worker crashed"),
+		}, nil
+	}
+	if w.chaos.roll(w.chaos.rates.Timeout) {
+		return &Result{
+			Output:  "chaos: injected timeout\n",
+			Timeout: true,
+			Error:   fmt.Errorf("chaos: %w", ErrTimeout),
+		}, nil
+	}
+
+	result, err := w.Worker.Execute(ctx, prompt)
+	if err != nil || result == nil {
+		return result, err
+	}
+
+	if w.chaos.roll(w.chaos.rates.Malformed) {
+		malformed := ParseOutput(mangleTags(result.Output))
+		malformed.ExitCode = result.ExitCode
+		malformed.Duration = result.Duration
+		return malformed, nil
+	}
+	return result, nil
+}
+
+// mangleTags breaks closing tags the way truncated or sloppy output does.
+func mangleTags(output string) string {
+	replacer := strings.NewReplacer(
+		"</promise>", "",
+		"</approach>", "</aproach>",
+		"</review>", "",
+		"</decision>", "",
+	)
+	return replacer.Replace(output)
+}
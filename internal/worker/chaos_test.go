@@ -0,0 +1,58 @@
+package worker
+
+import (
+	"context"
+	"testing"
+)
+
+func TestChaosWrap(t *testing.T) {
+	prompt := "=== TASK ===\nID: US-001\n"
+
+	tests := []struct {
+		name        string
+		rates       ChaosRates
+		wantCrashed bool
+		wantTimeout bool
+		wantPromise Promise
+	}{
+		{
+			name:        "no chaos",
+			wantPromise: PromiseComplete,
+		},
+		{
+			name:        "crash",
+			rates:       ChaosRates{Crash: 1},
+			wantCrashed: true,
+		},
+		{
+			name:        "timeout",
+			rates:       ChaosRates{Timeout: 1},
+			wantTimeout: true,
+		},
+		{
+			name:        "malformed tags",
+			rates:       ChaosRates{Malformed: 1},
+			wantPromise: PromiseNeedsIteration,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chaos := NewChaos(tt.rates, 1)
+			f := NewMockFactory(NewMockScript(0)).Wrap(chaos.Wrap)
+			result, err := f.Line().Execute(context.Background(), prompt)
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if result.Crashed != tt.wantCrashed {
+				t.Errorf("Crashed = %v, want %v", result.Crashed, tt.wantCrashed)
+			}
+			if result.Timeout != tt.wantTimeout {
+				t.Errorf("Timeout = %v, want %v", result.Timeout, tt.wantTimeout)
+			}
+			if result.Promise != tt.wantPromise {
+				t.Errorf("Promise = %q, want %q", result.Promise, tt.wantPromise)
+			}
+		})
+	}
+}
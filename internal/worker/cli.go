@@ -64,6 +64,9 @@ func (w *CLIWorker) Execute(ctx context.Context, prompt string) (*Result, error)
 	switch {
 	case strings.Contains(toolName, "claude"):
 		// Claude CLI: use --dangerously-skip-permissions and -p for prompt
+		if w.config.MCPConfig != "" {
+			args = append(args, "--mcp-config", w.config.MCPConfig)
+		}
 		args = append(args, "--dangerously-skip-permissions", "-p", prompt)
 	case strings.Contains(toolName, "opencode"):
 		// OpenCode: prompt is the last argument after "run"
@@ -90,6 +93,22 @@ func (w *CLIWorker) Execute(ctx context.Context, prompt string) (*Result, error)
 
 	cmd := exec.CommandContext(timeoutCtx, cmdParts[0], args...)
 
+	// Run the worker in its own process group so everything it spawns is
+	// stopped with it
+	setProcessGroup(cmd)
+
+	// On cancellation or timeout, ask the worker to stop and give it a grace
+	// period to exit before it is killed
+	cmd.Cancel = func() error {
+		return signalGroup(cmd.Process.Pid, syscall.SIGKILL)
+	}
+	if w.config.ShutdownGrace > 0 {
+		cmd.Cancel = func() error {
+			return signalGroup(cmd.Process.Pid, syscall.SIGTERM)
+		}
+		cmd.WaitDelay = w.config.ShutdownGrace
+	}
+
 	// Set working directory
 	if w.config.WorkingDir != "" {
 		cmd.Dir = w.config.WorkingDir
@@ -112,22 +131,23 @@ func (w *CLIWorker) Execute(ctx context.Context, prompt string) (*Result, error)
 		defer logFile.Close()
 	}
 
-	// Set up output handling
+	// Set up output handling, tracking output activity for stall detection
+	activity := newActivityWriter()
 	if w.config.Quiet {
 		if logFile != nil {
-			cmd.Stdout = io.MultiWriter(&stdout, logFile)
-			cmd.Stderr = io.MultiWriter(&stderr, logFile)
+			cmd.Stdout = io.MultiWriter(&stdout, logFile, activity)
+			cmd.Stderr = io.MultiWriter(&stderr, logFile, activity)
 		} else {
-			cmd.Stdout = &stdout
-			cmd.Stderr = &stderr
+			cmd.Stdout = io.MultiWriter(&stdout, activity)
+			cmd.Stderr = io.MultiWriter(&stderr, activity)
 		}
 	} else {
 		if logFile != nil {
-			cmd.Stdout = io.MultiWriter(os.Stdout, &stdout, logFile)
-			cmd.Stderr = io.MultiWriter(os.Stderr, &stderr, logFile)
+			cmd.Stdout = io.MultiWriter(os.Stdout, &stdout, logFile, activity)
+			cmd.Stderr = io.MultiWriter(os.Stderr, &stderr, logFile, activity)
 		} else {
-			cmd.Stdout = io.MultiWriter(os.Stdout, &stdout)
-			cmd.Stderr = io.MultiWriter(os.Stderr, &stderr)
+			cmd.Stdout = io.MultiWriter(os.Stdout, &stdout, activity)
+			cmd.Stderr = io.MultiWriter(os.Stderr, &stderr, activity)
 		}
 	}
 
@@ -139,16 +159,19 @@ func (w *CLIWorker) Execute(ctx context.Context, prompt string) (*Result, error)
 		}, nil
 	}
 
+	activity.touch()
+
 	// Set up health check monitoring
 	var crashed bool
+	var stall stallInfo
 	var healthWg sync.WaitGroup
 	healthDone := make(chan struct{})
 
-	if w.config.HealthCheckInterval > 0 {
+	if w.config.HealthCheckInterval > 0 || w.config.StallTimeout > 0 {
 		healthWg.Add(1)
 		go func() {
 			defer healthWg.Done()
-			w.monitorHealth(cmd.Process, healthDone, &crashed)
+			w.monitorHealth(cmd.Process, healthDone, activity, &crashed, &stall)
 		}()
 	}
 
@@ -157,6 +180,12 @@ func (w *CLIWorker) Execute(ctx context.Context, prompt string) (*Result, error)
 	close(healthDone)
 	healthWg.Wait()
 
+	// Wait only kills the group leader once the grace period runs out; take
+	// down whatever else is left in the group
+	if timeoutCtx.Err() != nil || stall.stalled {
+		signalGroup(cmd.Process.Pid, syscall.SIGKILL)
+	}
+
 	duration := time.Since(start)
 	output := stdout.String() + stderr.String()
 
@@ -164,10 +193,27 @@ func (w *CLIWorker) Execute(ctx context.Context, prompt string) (*Result, error)
 	result := ParseOutput(output)
 	result.Duration = duration
 
+	// Check for cancellation (shutdown), which is not a worker failure
+	if ctx.Err() != nil {
+		result.Error = ctx.Err()
+		return result, ctx.Err()
+	}
+
 	// Check for timeout
 	if timeoutCtx.Err() == context.DeadlineExceeded {
 		result.Timeout = true
-		result.Error = fmt.Errorf("worker timed out after %v", w.config.Timeout)
+		result.Error = fmt.Errorf("%w after %v", ErrTimeout, w.config.Timeout)
+		return result, nil
+	}
+
+	// Check for a stall
+	if stall.stalled {
+		result.Stalled = true
+		result.StallDump = stall.dump
+		result.Error = fmt.Errorf("%w: no output for %v", ErrStalled, w.config.StallTimeout)
+		if logFile != nil && stall.dump != "" {
+			fmt.Fprintf(logFile, "\n--- brigade: worker stalled, process state ---\n%s", stall.dump)
+		}
 		return result, nil
 	}
 
@@ -178,6 +224,9 @@ func (w *CLIWorker) Execute(ctx context.Context, prompt string) (*Result, error)
 		return result, nil
 	}
 
+	// Report anything the worker started and left running
+	result.Leftover = groupProcesses(cmd.Process.Pid)
+
 	// Check exit code
 	if err != nil {
 		if exitErr, ok := err.(*exec.ExitError); ok {
@@ -201,12 +250,29 @@ func (w *CLIWorker) Execute(ctx context.Context, prompt string) (*Result, error)
 		}
 	}
 
+	// A failure without a signal may be the provider refusing the request
+	if result.Error != nil && result.Promise == PromiseNeedsIteration {
+		result.RateLimited, result.RetryAfter = DetectRateLimit(output, time.Now())
+		result.AuthFailed = !result.RateLimited && DetectAuthFailure(output)
+	}
+
 	return result, nil
 }
 
-// monitorHealth periodically checks if the process is still running.
-func (w *CLIWorker) monitorHealth(process *os.Process, done chan struct{}, crashed *bool) {
-	ticker := time.NewTicker(w.config.HealthCheckInterval)
+// stallInfo is filled in by monitorHealth when it interrupts a silent worker.
+type stallInfo struct {
+	stalled bool
+	dump    string
+}
+
+// monitorHealth periodically checks if the process is still running, and
+// interrupts it if it has written no output for the stall timeout.
+func (w *CLIWorker) monitorHealth(process *os.Process, done chan struct{}, activity *activityWriter, crashed *bool, stall *stallInfo) {
+	interval := w.config.HealthCheckInterval
+	if interval <= 0 || (w.config.StallTimeout > 0 && interval > w.config.StallTimeout) {
+		interval = w.config.StallTimeout
+	}
+	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
 	for {
@@ -221,6 +287,21 @@ func (w *CLIWorker) monitorHealth(process *os.Process, done chan struct{}, crash
 				// The Wait() call will determine which
 				return
 			}
+
+			if w.config.StallTimeout > 0 && activity.silence() >= w.config.StallTimeout {
+				// Capture state before interrupting, while it is still stuck
+				stall.stalled = true
+				stall.dump = processDump(process.Pid)
+				signalGroup(process.Pid, syscall.SIGINT)
+
+				// Kill it if it ignores the interrupt
+				select {
+				case <-done:
+				case <-time.After(w.config.ShutdownGrace):
+					signalGroup(process.Pid, syscall.SIGKILL)
+				}
+				return
+			}
 		}
 	}
 }
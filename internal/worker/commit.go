@@ -0,0 +1,134 @@
+package worker
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"brigade/internal/prd"
+)
+
+// CommitConvention is how commits for a task are written: the chef
+// prompts' "<task-id>: <description>", or Conventional Commits, optionally
+// signed off (DCO) and GPG-signed.
+type CommitConvention struct {
+	Conventional bool
+	SignOff      bool
+	GPGSign      bool
+}
+
+// commitTypes are the Conventional Commits types and the words in a task's
+// tags or title that suggest them. Tasks that match none are features.
+var commitTypes = []struct {
+	Type  string
+	Words []string
+}{
+	{"fix", []string{"fix", "fixes", "bug", "bugfix", "hotfix", "repair", "resolve", "correct", "patch"}},
+	{"docs", []string{"doc", "docs", "document", "documentation", "readme"}},
+	{"test", []string{"test", "tests", "testing", "spec", "specs", "coverage"}},
+	{"refactor", []string{"refactor", "rename", "restructure", "cleanup", "simplify", "extract"}},
+	{"perf", []string{"perf", "performance", "optimize", "optimise", "speed"}},
+	{"ci", []string{"ci", "pipeline", "workflow"}},
+	{"build", []string{"build", "deps", "dependency", "dependencies", "bump", "upgrade"}},
+	{"style", []string{"style", "format", "formatting", "lint"}},
+	{"chore", []string{"chore"}},
+}
+
+var commitWord = regexp.MustCompile(`[a-z]+`)
+
+// CommitType infers a task's Conventional Commits type from its tags, then
+// from the first telling word of its title.
+func CommitType(task *prd.Task) string {
+	for _, tag := range task.Tags {
+		if t := commitTypeOf(strings.ToLower(tag)); t != "" {
+			return t
+		}
+	}
+	for _, word := range commitWord.FindAllString(strings.ToLower(task.Title), -1) {
+		if t := commitTypeOf(word); t != "" {
+			return t
+		}
+	}
+	return "feat"
+}
+
+func commitTypeOf(word string) string {
+	for _, ct := range commitTypes {
+		for _, w := range ct.Words {
+			if word == w {
+				return ct.Type
+			}
+		}
+	}
+	return ""
+}
+
+// CommitScope returns a task's Conventional Commits scope: the last part of
+// its workspace, or "" for tasks without one.
+func CommitScope(task *prd.Task) string {
+	if task.Workspace == "" {
+		return ""
+	}
+	return path.Base(path.Clean(task.Workspace))
+}
+
+// Subject returns the subject line of a commit for a task.
+func (c CommitConvention) Subject(task *prd.Task, description string) string {
+	if !c.Conventional {
+		return fmt.Sprintf("%s: %s", task.ID, description)
+	}
+	if scope := CommitScope(task); scope != "" {
+		return fmt.Sprintf("%s(%s): %s", CommitType(task), scope, description)
+	}
+	return fmt.Sprintf("%s: %s", CommitType(task), description)
+}
+
+// Message returns a commit message for a task: the subject, and with
+// Conventional Commits a footer naming the task.
+func (c CommitConvention) Message(task *prd.Task, description string) string {
+	msg := c.Subject(task, description)
+	if c.Conventional {
+		msg += "\n\nTask: " + task.ID
+	}
+	return msg
+}
+
+// Args returns the git commit flags the convention needs.
+func (c CommitConvention) Args() []string {
+	var args []string
+	if c.SignOff {
+		args = append(args, "--signoff")
+	}
+	if c.GPGSign {
+		args = append(args, "--gpg-sign")
+	}
+	return args
+}
+
+// Env returns environment variables that make git GPG-sign every commit,
+// so a worker's commits are signed even if it forgets the flag.
+func (c CommitConvention) Env() []string {
+	if !c.GPGSign {
+		return nil
+	}
+	return []string{"GIT_CONFIG_COUNT=1", "GIT_CONFIG_KEY_0=commit.gpgsign", "GIT_CONFIG_VALUE_0=true"}
+}
+
+// Instructions tells a worker how to commit a task, or returns "" when the
+// chef prompt's default applies.
+func (c CommitConvention) Instructions(task *prd.Task) string {
+	if !c.Conventional && !c.SignOff && !c.GPGSign {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Commit: use the message `%s`", c.Subject(task, "<brief description>")))
+	if c.Conventional {
+		sb.WriteString(fmt.Sprintf(", with the footer `Task: %s`", task.ID))
+	}
+	sb.WriteString(" instead of the format above.")
+	if args := c.Args(); len(args) > 0 {
+		sb.WriteString(fmt.Sprintf(" Commit with `git commit %s`; hooks and branch protection reject commits without it.", strings.Join(args, " ")))
+	}
+	return sb.String()
+}
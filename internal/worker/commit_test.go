@@ -0,0 +1,59 @@
+package worker
+
+import (
+	"strings"
+	"testing"
+
+	"brigade/internal/prd"
+)
+
+func TestCommitType(t *testing.T) {
+	tests := []struct {
+		title string
+		tags  []string
+		want  string
+	}{
+		{"Add user login", nil, "feat"},
+		{"Fix token refresh race", nil, "fix"},
+		{"Add tests for the session store", nil, "test"},
+		{"Refactor the auth middleware", nil, "refactor"},
+		{"Update README with setup steps", nil, "docs"},
+		{"Add login page", []string{"frontend", "docs"}, "docs"},
+		{"Prefix routes with /api", nil, "feat"},
+	}
+
+	for _, tt := range tests {
+		if got := CommitType(&prd.Task{Title: tt.title, Tags: tt.tags}); got != tt.want {
+			t.Errorf("CommitType(%q, %v) = %q, want %q", tt.title, tt.tags, got, tt.want)
+		}
+	}
+}
+
+func TestCommitConventionMessage(t *testing.T) {
+	task := &prd.Task{ID: "US-004", Title: "Fix rate limiting", Workspace: "services/api/"}
+
+	if got, want := (CommitConvention{}).Message(task, "Fix rate limiting"), "US-004: Fix rate limiting"; got != want {
+		t.Errorf("Message() = %q, want %q", got, want)
+	}
+	conventional := CommitConvention{Conventional: true}
+	if got, want := conventional.Message(task, "handle bursts"), "fix(api): handle bursts\n\nTask: US-004"; got != want {
+		t.Errorf("Message() = %q, want %q", got, want)
+	}
+	task.Workspace = ""
+	if got, want := conventional.Subject(task, "handle bursts"), "fix: handle bursts"; got != want {
+		t.Errorf("Subject() = %q, want %q", got, want)
+	}
+}
+
+func TestCommitConventionInstructions(t *testing.T) {
+	task := &prd.Task{ID: "US-001", Title: "Add login"}
+	if got := (CommitConvention{}).Instructions(task); got != "" {
+		t.Errorf("Instructions() = %q, want empty for the default", got)
+	}
+	got := CommitConvention{Conventional: true, SignOff: true}.Instructions(task)
+	for _, want := range []string{"`feat: <brief description>`", "`Task: US-001`", "git commit --signoff"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Instructions() = %q, want it to contain %q", got, want)
+		}
+	}
+}
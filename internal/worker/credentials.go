@@ -0,0 +1,82 @@
+package worker
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Credential is a named set of environment variables a worker runs with:
+// an API key, or the settings that point a CLI at another provider.
+type Credential struct {
+	Name string
+	Env  []string // KEY=value
+}
+
+var envNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// ParseCredentials parses a *_CREDENTIALS value: credentials separated by
+// ";", each a name, a colon, and space-separated KEY=value settings.
+// $VAR and ${VAR} in values are read from Brigade's environment, so keys
+// needn't be written into the config:
+//
+//	primary: ANTHROPIC_API_KEY=$KEY_A; bedrock: CLAUDE_CODE_USE_BEDROCK=1 AWS_PROFILE=prod
+func ParseCredentials(spec string) ([]Credential, error) {
+	var creds []Credential
+	seen := make(map[string]bool)
+	for _, entry := range strings.Split(spec, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, settings, ok := strings.Cut(entry, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" || strings.ContainsAny(name, " =") {
+			return nil, fmt.Errorf("%q: want name: KEY=value ...", entry)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("credential %q is listed twice", name)
+		}
+		seen[name] = true
+
+		cred := Credential{Name: name}
+		for _, setting := range strings.Fields(settings) {
+			key, value, ok := strings.Cut(setting, "=")
+			if !ok || !envNamePattern.MatchString(key) {
+				return nil, fmt.Errorf("credential %q: %q isn't KEY=value", name, setting)
+			}
+			var unset []string
+			value = os.Expand(value, func(v string) string {
+				val, ok := os.LookupEnv(v)
+				if !ok {
+					unset = append(unset, "$"+v)
+				}
+				return val
+			})
+			if len(unset) > 0 {
+				return nil, fmt.Errorf("credential %q: %s references %s, which isn't set", name, key, strings.Join(unset, ", "))
+			}
+			cred.Env = append(cred.Env, key+"="+value)
+		}
+		if len(cred.Env) == 0 {
+			return nil, fmt.Errorf("credential %q sets nothing", name)
+		}
+		creds = append(creds, cred)
+	}
+	return creds, nil
+}
+
+// authFailurePattern matches the errors CLIs print when their credential is
+// rejected or out of funds.
+var authFailurePattern = regexp.MustCompile(`(?i)\b401\b|authentication_error|invalid (x-)?api[ -]?key|invalid bearer token|incorrect api key|credit balance is too low|insufficient_quota|expired ?token|security token included in the request is (invalid|expired)|UnrecognizedClientException|AccessDeniedException|invalid subscription key`)
+
+// DetectAuthFailure reports whether a failed worker's output says its
+// credential was rejected. Like DetectRateLimit, only the end of the
+// output is searched.
+func DetectAuthFailure(output string) bool {
+	if len(output) > rateLimitScanBytes {
+		output = output[len(output)-rateLimitScanBytes:]
+	}
+	return authFailurePattern.MatchString(output)
+}
@@ -0,0 +1,78 @@
+package worker
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseCredentials(t *testing.T) {
+	t.Setenv("BRIGADE_TEST_KEY_A", "sk-a")
+	t.Setenv("BRIGADE_TEST_KEY_B", "sk-b")
+
+	tests := []struct {
+		name    string
+		spec    string
+		want    []Credential
+		wantErr string
+	}{
+		{"empty", "", nil, ""},
+		{
+			"two keys",
+			"primary: ANTHROPIC_API_KEY=$BRIGADE_TEST_KEY_A; backup: ANTHROPIC_API_KEY=${BRIGADE_TEST_KEY_B}",
+			[]Credential{
+				{Name: "primary", Env: []string{"ANTHROPIC_API_KEY=sk-a"}},
+				{Name: "backup", Env: []string{"ANTHROPIC_API_KEY=sk-b"}},
+			},
+			"",
+		},
+		{
+			"several settings",
+			"bedrock: CLAUDE_CODE_USE_BEDROCK=1 AWS_PROFILE=prod;",
+			[]Credential{{Name: "bedrock", Env: []string{"CLAUDE_CODE_USE_BEDROCK=1", "AWS_PROFILE=prod"}}},
+			"",
+		},
+		{"missing name", "ANTHROPIC_API_KEY=x", nil, "want name"},
+		{"duplicate name", "a: K=1; a: K=2", nil, "listed twice"},
+		{"not an assignment", "a: sk-a", nil, "isn't KEY=value"},
+		{"unset variable", "a: ANTHROPIC_API_KEY=$BRIGADE_TEST_UNSET", nil, "$BRIGADE_TEST_UNSET, which isn't set"},
+		{"no settings", "a:", nil, "sets nothing"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseCredentials(tt.spec)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("ParseCredentials() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseCredentials() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseCredentials() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectAuthFailure(t *testing.T) {
+	tests := []struct {
+		output string
+		want   bool
+	}{
+		{"FAIL: TestLogin\nexit status 1", false},
+		{`API Error: 401 {"type":"error","error":{"type":"authentication_error","message":"invalid x-api-key"}}`, true},
+		{"Invalid API key · Please run /login", true},
+		{"Your credit balance is too low to access the Anthropic API.", true},
+		{"The security token included in the request is expired", true},
+		{"AccessDeniedException: You don't have access to the model with the specified model ID.", true},
+		{"Access denied due to invalid subscription key or wrong API endpoint.", true},
+	}
+	for _, tt := range tests {
+		if got := DetectAuthFailure(tt.output); got != tt.want {
+			t.Errorf("DetectAuthFailure(%q) = %v, want %v", tt.output, got, tt.want)
+		}
+	}
+}
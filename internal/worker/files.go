@@ -0,0 +1,119 @@
+package worker
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// buildFilesSection inlines the current contents of a task's file hints,
+// up to budget bytes. Files that don't fit are listed by name only.
+func (b *PromptBuilder) buildFilesSection(patterns []string, budget int) string {
+	files := ExpandFiles(patterns)
+	if len(files) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	var skipped []string
+	sb.WriteString("\n=== TASK FILES (current contents) ===\n")
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil || isBinary(data) {
+			continue
+		}
+		if len(data) > budget {
+			skipped = append(skipped, f)
+			continue
+		}
+		budget -= len(data)
+		sb.WriteString(fmt.Sprintf("--- %s ---\n", f))
+		sb.Write(data)
+		if !bytes.HasSuffix(data, []byte("\n")) {
+			sb.WriteString("\n")
+		}
+	}
+	if len(skipped) > 0 {
+		sb.WriteString(fmt.Sprintf("\nNot inlined (over the context budget), read as needed: %s\n", strings.Join(skipped, ", ")))
+	}
+	sb.WriteString("=== END TASK FILES ===")
+	return sb.String()
+}
+
+// ExpandFiles resolves file hints to existing files, in order and without
+// duplicates. Globs are expanded; "**" matches any number of directories.
+func ExpandFiles(patterns []string) []string {
+	seen := make(map[string]bool)
+	var files []string
+	add := func(f string) {
+		f = filepath.ToSlash(filepath.Clean(f))
+		if !seen[f] {
+			seen[f] = true
+			files = append(files, f)
+		}
+	}
+
+	for _, pattern := range patterns {
+		var matches []string
+		if strings.Contains(pattern, "**") {
+			matches = globRecursive(pattern)
+		} else {
+			matches, _ = filepath.Glob(pattern)
+		}
+		sort.Strings(matches)
+		for _, m := range matches {
+			if info, err := os.Stat(m); err == nil && !info.IsDir() {
+				add(m)
+			}
+		}
+	}
+	return files
+}
+
+// globRecursive expands a pattern such as "internal/**/*.go": the part
+// after "**" is matched against the trailing path components of every file
+// under the part before it.
+func globRecursive(pattern string) []string {
+	i := strings.Index(pattern, "**")
+	root := strings.TrimSuffix(pattern[:i], "/")
+	if root == "" {
+		root = "."
+	}
+	suffix := strings.TrimPrefix(pattern[i+2:], "/")
+	depth := strings.Count(suffix, "/") + 1
+
+	var matches []string
+	filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if path != root && (strings.HasPrefix(d.Name(), ".") || d.Name() == "node_modules") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		parts := strings.Split(filepath.ToSlash(path), "/")
+		if len(parts) < depth {
+			return nil
+		}
+		tail := strings.Join(parts[len(parts)-depth:], "/")
+		if ok, _ := filepath.Match(suffix, tail); ok || suffix == "" {
+			matches = append(matches, path)
+		}
+		return nil
+	})
+	return matches
+}
+
+// isBinary reports whether data looks like a binary file.
+func isBinary(data []byte) bool {
+	if len(data) > 8000 {
+		data = data[:8000]
+	}
+	return bytes.IndexByte(data, 0) >= 0
+}
@@ -0,0 +1,45 @@
+package worker
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBuildFilesSection(t *testing.T) {
+	dir := t.TempDir()
+	wd, _ := os.Getwd()
+	defer os.Chdir(wd)
+	os.Chdir(dir)
+
+	files := map[string]string{
+		"internal/auth/login.go":      "package auth\n\nfunc Login() {}\n",
+		"internal/auth/token/jwt.go":  "package token\n",
+		"internal/auth/login_test.go": "package auth\n",
+		"internal/auth/big.go":        strings.Repeat("x", 500),
+		"internal/auth/logo.png":      "\x89PNG\x00\x00",
+	}
+	for name, content := range files {
+		os.MkdirAll(filepath.Dir(name), 0755)
+		os.WriteFile(name, []byte(content), 0644)
+	}
+
+	got := ExpandFiles([]string{"internal/auth/login.go", "internal/auth/**/*.go"})
+	want := "internal/auth/login.go,internal/auth/big.go,internal/auth/login_test.go,internal/auth/token/jwt.go"
+	if strings.Join(got, ",") != want {
+		t.Errorf("ExpandFiles() = %v, want %s", got, want)
+	}
+
+	b := NewPromptBuilder("", "", "")
+	section := b.buildFilesSection([]string{"internal/auth/*"}, 200)
+	if !strings.Contains(section, "--- internal/auth/login.go ---\npackage auth\n\nfunc Login() {}\n") {
+		t.Errorf("section is missing login.go contents:\n%s", section)
+	}
+	if !strings.Contains(section, "read as needed: internal/auth/big.go") {
+		t.Errorf("section should list big.go as over budget:\n%s", section)
+	}
+	if strings.Contains(section, "logo.png") {
+		t.Errorf("section should skip binary files:\n%s", section)
+	}
+}
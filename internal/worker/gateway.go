@@ -0,0 +1,94 @@
+package worker
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Gateway is a proxy (LiteLLM and the like) every tier's requests are sent
+// through. Brigade tags each attempt's requests with its tier and task so
+// the proxy can route, log and budget them.
+type Gateway struct {
+	URL     string
+	APIKey  string
+	Headers []string // Extra "Name: value" headers sent with every request
+}
+
+// ParseGateway builds a gateway from GATEWAY_URL, GATEWAY_API_KEY and
+// GATEWAY_HEADERS. $VAR in the key and headers is read from Brigade's
+// environment. Returns nil when url is empty.
+func ParseGateway(rawURL, apiKey, headers string) (*Gateway, error) {
+	if rawURL == "" {
+		return nil, nil
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("GATEWAY_URL %q isn't an http(s) URL", rawURL)
+	}
+	g := &Gateway{URL: strings.TrimRight(rawURL, "/"), APIKey: os.ExpandEnv(apiKey)}
+	for _, h := range strings.Split(headers, ";") {
+		h = strings.TrimSpace(os.ExpandEnv(h))
+		if h == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(h, ":")
+		if !ok || strings.TrimSpace(name) == "" || strings.ContainsAny(strings.TrimSpace(name), " \t") {
+			return nil, fmt.Errorf("GATEWAY_HEADERS: %q isn't Name: value", h)
+		}
+		g.Headers = append(g.Headers, strings.TrimSpace(name)+": "+strings.TrimSpace(value))
+	}
+	return g, nil
+}
+
+// Env returns the environment that sends a tier's worker through the
+// gateway. The claude CLI speaks Anthropic's API, which LiteLLM serves
+// alongside OpenAI's; other agents are pointed at the OpenAI-compatible
+// endpoint. Agents with their own provider can't also use a gateway.
+func (g *Gateway) Env(agent string) ([]string, error) {
+	switch {
+	case agent == AgentBedrock || agent == AgentAzure:
+		return nil, fmt.Errorf("%s workers can't be sent through GATEWAY_URL", agent)
+	case UsesClaudeCLI(agent):
+		env := []string{"ANTHROPIC_BASE_URL=" + g.URL}
+		if g.APIKey != "" {
+			env = append(env, "ANTHROPIC_AUTH_TOKEN="+g.APIKey)
+		}
+		return env, nil
+	default:
+		env := []string{"OPENAI_BASE_URL=" + g.URL + "/v1"}
+		if g.APIKey != "" {
+			env = append(env, "OPENAI_API_KEY="+g.APIKey)
+		}
+		return env, nil
+	}
+}
+
+// AttemptTags identify an attempt to the gateway.
+type AttemptTags struct {
+	PRD     string
+	TaskID  string
+	Tier    string
+	Attempt int
+}
+
+// AttemptEnv returns the environment that tags an attempt's requests:
+// X-Brigade-* headers, plus x-litellm-tags for LiteLLM's spend tracking and
+// tag routing. Only the claude CLI takes extra headers from its environment
+// (ANTHROPIC_CUSTOM_HEADERS); other agents get none.
+func (g *Gateway) AttemptEnv(agent string, tags AttemptTags) []string {
+	if !UsesClaudeCLI(agent) {
+		return nil
+	}
+	headers := []string{
+		"X-Brigade-PRD: " + tags.PRD,
+		"X-Brigade-Task: " + tags.TaskID,
+		"X-Brigade-Tier: " + tags.Tier,
+		"X-Brigade-Attempt: " + strconv.Itoa(tags.Attempt),
+		fmt.Sprintf("x-litellm-tags: brigade,prd:%s,task:%s,tier:%s", tags.PRD, tags.TaskID, tags.Tier),
+	}
+	headers = append(headers, g.Headers...)
+	return []string{"ANTHROPIC_CUSTOM_HEADERS=" + strings.Join(headers, "\n")}
+}
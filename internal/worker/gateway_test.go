@@ -0,0 +1,81 @@
+package worker
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseGateway(t *testing.T) {
+	t.Setenv("BRIGADE_TEST_GATEWAY_KEY", "sk-proxy")
+
+	tests := []struct {
+		name    string
+		url     string
+		key     string
+		headers string
+		want    *Gateway
+		wantErr string
+	}{
+		{"no gateway", "", "", "", nil, ""},
+		{
+			"key and headers",
+			"http://litellm:4000/", "$BRIGADE_TEST_GATEWAY_KEY", "X-Team: payments; x-litellm-spend-logs-metadata: {}",
+			&Gateway{URL: "http://litellm:4000", APIKey: "sk-proxy", Headers: []string{"X-Team: payments", "x-litellm-spend-logs-metadata: {}"}},
+			"",
+		},
+		{"not a URL", "litellm:4000", "", "", nil, "isn't an http(s) URL"},
+		{"bad header", "https://proxy", "", "X-Team payments", nil, "isn't Name: value"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseGateway(tt.url, tt.key, tt.headers)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("ParseGateway() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseGateway() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseGateway() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGatewayEnv(t *testing.T) {
+	g := &Gateway{URL: "http://litellm:4000", APIKey: "sk-proxy", Headers: []string{"X-Team: payments"}}
+
+	tests := []struct {
+		agent   string
+		want    []string
+		wantErr bool
+	}{
+		{"claude", []string{"ANTHROPIC_BASE_URL=http://litellm:4000", "ANTHROPIC_AUTH_TOKEN=sk-proxy"}, false},
+		{"opencode", []string{"OPENAI_BASE_URL=http://litellm:4000/v1", "OPENAI_API_KEY=sk-proxy"}, false},
+		{"bedrock", nil, true},
+	}
+	for _, tt := range tests {
+		got, err := g.Env(tt.agent)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Env(%q) error = %v, wantErr %v", tt.agent, err, tt.wantErr)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Env(%q) = %v, want %v", tt.agent, got, tt.want)
+		}
+	}
+
+	tags := AttemptTags{PRD: "auth", TaskID: "US-003", Tier: "sous", Attempt: 2}
+	want := []string{"ANTHROPIC_CUSTOM_HEADERS=X-Brigade-PRD: auth\nX-Brigade-Task: US-003\nX-Brigade-Tier: sous\nX-Brigade-Attempt: 2\n" +
+		"x-litellm-tags: brigade,prd:auth,task:US-003,tier:sous\nX-Team: payments"}
+	if got := g.AttemptEnv("claude", tags); !reflect.DeepEqual(got, want) {
+		t.Errorf("AttemptEnv() = %q, want %q", got, want)
+	}
+	if got := g.AttemptEnv("opencode", tags); got != nil {
+		t.Errorf("AttemptEnv(opencode) = %q, want nil", got)
+	}
+}
@@ -0,0 +1,52 @@
+package worker
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// MCPServers maps MCP server names to their definitions, in the Claude
+// CLI's mcpServers format (command/args/env or type/url).
+type MCPServers map[string]json.RawMessage
+
+// LoadMCPServers reads the servers from a Claude CLI MCP config file
+// ({"mcpServers": {...}}).
+func LoadMCPServers(path string) (MCPServers, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var config struct {
+		MCPServers MCPServers `json:"mcpServers"`
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return config.MCPServers, nil
+}
+
+// WriteMCPConfig merges server sets into one MCP config file at path.
+// Later sets override servers of the same name in earlier ones. Returns
+// false without writing anything if there are no servers.
+func WriteMCPConfig(path string, sets ...MCPServers) (bool, error) {
+	merged := make(MCPServers)
+	for _, set := range sets {
+		for name, server := range set {
+			merged[name] = server
+		}
+	}
+	if len(merged) == 0 {
+		return false, nil
+	}
+
+	data, err := json.MarshalIndent(map[string]MCPServers{"mcpServers": merged}, "", "  ")
+	if err != nil {
+		return false, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return false, err
+	}
+	return true, os.WriteFile(path, data, 0600)
+}
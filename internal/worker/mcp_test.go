@@ -0,0 +1,50 @@
+package worker
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteMCPConfig(t *testing.T) {
+	dir := t.TempDir()
+	shared := filepath.Join(dir, "shared.json")
+	os.WriteFile(shared, []byte(`{"mcpServers": {
+		"db": {"command": "db-mcp", "args": ["--readonly"]},
+		"tracker": {"type": "http", "url": "https://tracker.example.com/mcp"}
+	}}`), 0644)
+
+	base, err := LoadMCPServers(shared)
+	if err != nil {
+		t.Fatalf("LoadMCPServers() error = %v", err)
+	}
+	prdServers := MCPServers{"db": json.RawMessage(`{"command":"db-mcp","args":["--write"]}`)}
+
+	out := filepath.Join(dir, "mcp", "line.json")
+	wrote, err := WriteMCPConfig(out, base, prdServers)
+	if err != nil || !wrote {
+		t.Fatalf("WriteMCPConfig() = %v, %v, want true, nil", wrote, err)
+	}
+
+	merged, err := LoadMCPServers(out)
+	if err != nil {
+		t.Fatalf("LoadMCPServers(merged) error = %v", err)
+	}
+	if len(merged) != 2 {
+		t.Errorf("merged %d servers, want 2", len(merged))
+	}
+	var db struct{ Args []string }
+	json.Unmarshal(merged["db"], &db)
+	if len(db.Args) != 1 || db.Args[0] != "--write" {
+		t.Errorf("db args = %v, want the PRD's definition to win", db.Args)
+	}
+
+	empty := filepath.Join(dir, "empty.json")
+	if wrote, err := WriteMCPConfig(empty, nil, MCPServers{}); wrote || err != nil {
+		t.Errorf("WriteMCPConfig(no servers) = %v, %v, want false, nil", wrote, err)
+	}
+	if _, err := os.Stat(empty); !os.IsNotExist(err) {
+		t.Error("WriteMCPConfig(no servers) should not create a file")
+	}
+}
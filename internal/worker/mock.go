@@ -0,0 +1,144 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"brigade/internal/state"
+)
+
+// taskIDPattern finds the task ID in task and review prompts.
+var taskIDPattern = regexp.MustCompile(`(?m)^\s*ID: (\S+)`)
+
+// MockScript controls how mock workers behave. It is shared by all workers
+// from a mock factory so behavior can depend on what happened earlier in the run.
+type MockScript struct {
+	// Delay is the typical simulated work time (actual time varies ±50%)
+	Delay time.Duration
+
+	// FailureRate is the chance a line cook attempt fails without completing
+	FailureRate float64
+
+	// EscalateTask fails at the line tier until it is escalated
+	EscalateTask string
+
+	// ReviewFailTask fails its first executive review
+	ReviewFailTask string
+
+	mu      sync.Mutex
+	rng     *rand.Rand
+	reviews map[string]int
+	attempt map[string]int
+}
+
+// NewMockScript creates a mock script with the given typical delay.
+func NewMockScript(delay time.Duration) *MockScript {
+	return &MockScript{
+		Delay:   delay,
+		rng:     rand.New(rand.NewSource(time.Now().UnixNano())),
+		reviews: make(map[string]int),
+		attempt: make(map[string]int),
+	}
+}
+
+// MockWorker simulates a worker without invoking a model. Responses are
+// driven by the prompt and the shared script so a run exercises retries,
+// escalation, review, and completion.
+type MockWorker struct {
+	config *Config
+	script *MockScript
+}
+
+// NewMockWorker creates a mock worker for the given tier configuration.
+func NewMockWorker(config *Config, script *MockScript) *MockWorker {
+	return &MockWorker{config: config, script: script}
+}
+
+// NewMockFactory creates a factory whose workers are all mocks.
+func NewMockFactory(script *MockScript) *Factory {
+	f := NewFactory(DefaultConfig(state.TierLine), DefaultConfig(state.TierSous), DefaultConfig(state.TierExecutive))
+	f.newWorker = func(config *Config) Worker {
+		return NewMockWorker(config, script)
+	}
+	return f
+}
+
+// Name returns the worker name.
+func (w *MockWorker) Name() string {
+	return "mock"
+}
+
+// Tier returns the worker's tier.
+func (w *MockWorker) Tier() state.WorkerTier {
+	return w.config.Tier
+}
+
+// Execute simulates work, then returns a scripted response.
+func (w *MockWorker) Execute(ctx context.Context, prompt string) (*Result, error) {
+	start := time.Now()
+
+	select {
+	case <-ctx.Done():
+		return &Result{Error: ctx.Err(), Duration: time.Since(start)}, nil
+	case <-time.After(w.script.jitter()):
+	}
+
+	result := ParseOutput(w.respond(prompt))
+	result.Duration = time.Since(start)
+	return result, nil
+}
+
+// respond picks the scripted output for a prompt.
+func (w *MockWorker) respond(prompt string) string {
+	s := w.script
+	taskID := ""
+	if m := taskIDPattern.FindStringSubmatch(prompt); len(m) > 1 {
+		taskID = m[1]
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case strings.Contains(prompt, "=== REVIEW REQUEST ==="):
+		s.reviews[taskID]++
+		if taskID == s.ReviewFailTask && s.reviews[taskID] == 1 {
+			return "<review>FAIL: edge cases from the acceptance criteria are not covered</review>"
+		}
+		return "<review>PASS</review>"
+
+	case strings.Contains(prompt, "=== DECISION REQUIRED ==="):
+		return "<decision>SKIP</decision>"
+
+	case strings.Contains(prompt, "=== SCOPE DECISION REQUIRED ==="):
+		return "<scope-decision>Keep to the simplest approach that meets the criteria.</scope-decision>"
+	}
+
+	s.attempt[taskID]++
+	n := s.attempt[taskID]
+	approach := fmt.Sprintf("<approach>%s attempt %d on %s</approach>", w.config.Tier, n, taskID)
+
+	if w.config.Tier == state.TierLine {
+		if taskID == s.EscalateTask || s.rng.Float64() < s.FailureRate {
+			return fmt.Sprintf("%s\nFAIL: TestAcceptance (%s)\n    expected criteria to hold, got assertion error\n", approach, taskID)
+		}
+	}
+
+	return fmt.Sprintf("%s\nImplemented %s.\n<learning>Mock %s worker finished %s</learning>\n<promise>COMPLETE</promise>",
+		approach, taskID, w.config.Tier, taskID)
+}
+
+// jitter returns the delay for one simulated execution.
+func (s *MockScript) jitter() time.Duration {
+	if s.Delay <= 0 {
+		return 0
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.Delay/2 + time.Duration(s.rng.Int63n(int64(s.Delay)))
+}
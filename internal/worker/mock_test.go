@@ -0,0 +1,69 @@
+package worker
+
+import (
+	"context"
+	"testing"
+
+	"brigade/internal/state"
+)
+
+func TestMockWorkerScript(t *testing.T) {
+	script := NewMockScript(0)
+	script.EscalateTask = "US-002"
+	script.ReviewFailTask = "US-001"
+	f := NewMockFactory(script)
+
+	tests := []struct {
+		name        string
+		tier        state.WorkerTier
+		prompt      string
+		wantPromise Promise
+		wantOutput  string
+	}{
+		{
+			name:        "line completes",
+			tier:        state.TierLine,
+			prompt:      "=== TASK ===\nID: US-001\n",
+			wantPromise: PromiseComplete,
+		},
+		{
+			name:        "escalation task fails at line",
+			tier:        state.TierLine,
+			prompt:      "=== TASK ===\nID: US-002\n",
+			wantPromise: PromiseNeedsIteration,
+		},
+		{
+			name:        "escalation task completes at sous",
+			tier:        state.TierSous,
+			prompt:      "=== TASK ===\nID: US-002\n",
+			wantPromise: PromiseComplete,
+		},
+		{
+			name:       "first review fails",
+			tier:       state.TierExecutive,
+			prompt:     "=== REVIEW REQUEST ===\n  ID: US-001\n",
+			wantOutput: "<review>FAIL: edge cases from the acceptance criteria are not covered</review>",
+		},
+		{
+			name:       "second review passes",
+			tier:       state.TierExecutive,
+			prompt:     "=== REVIEW REQUEST ===\n  ID: US-001\n",
+			wantOutput: "<review>PASS</review>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := f.ForTier(tt.tier).Execute(context.Background(), tt.prompt)
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if result.Promise != tt.wantPromise {
+				t.Errorf("Promise = %q, want %q", result.Promise, tt.wantPromise)
+			}
+			if tt.wantOutput != "" && result.Output != tt.wantOutput {
+				t.Errorf("Output = %q, want %q", result.Output, tt.wantOutput)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package worker
 
 import (
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -14,6 +15,7 @@ var (
 	approachPattern      = regexp.MustCompile(`(?s)<approach>(.*?)</approach>`)
 	scopeQuestionPattern = regexp.MustCompile(`(?s)<scope-question>(.*?)</scope-question>`)
 	absorbedByPattern    = regexp.MustCompile(`ABSORBED_BY:(\S+)`)
+	usageFieldPattern    = regexp.MustCompile(`"(input_tokens|cache_creation_input_tokens|cache_read_input_tokens|output_tokens)"\s*:\s*(\d+)`)
 )
 
 // ParseOutput extracts structured data from worker output.
@@ -73,9 +75,39 @@ func ParseOutput(output string) *Result {
 		result.ScopeQuestion = strings.TrimSpace(matches[1])
 	}
 
+	result.Usage = ExtractUsage(output)
+
 	return result
 }
 
+// ExtractUsage reads the token usage from the last "usage" object in the
+// output, as printed by `claude -p --output-format json`. Tokens written to
+// the prompt cache count as input. Returns a zero Usage if there is none.
+func ExtractUsage(output string) Usage {
+	var usage Usage
+	i := strings.LastIndex(output, `"usage"`)
+	if i < 0 {
+		return usage
+	}
+	seen := make(map[string]bool)
+	for _, match := range usageFieldPattern.FindAllStringSubmatch(output[i:], -1) {
+		if seen[match[1]] {
+			break // Past the usage object
+		}
+		seen[match[1]] = true
+		n, _ := strconv.Atoi(match[2])
+		switch match[1] {
+		case "input_tokens", "cache_creation_input_tokens":
+			usage.InputTokens += n
+		case "cache_read_input_tokens":
+			usage.CacheReadTokens = n
+		case "output_tokens":
+			usage.OutputTokens = n
+		}
+	}
+	return usage
+}
+
 // HasPromise returns true if the output contains any promise tag.
 func HasPromise(output string) bool {
 	return promisePattern.MatchString(output)
@@ -194,6 +226,9 @@ func MergeResults(results ...*Result) *Result {
 		// Accumulate learnings and backlog
 		merged.Learnings = append(merged.Learnings, r.Learnings...)
 		merged.Backlog = append(merged.Backlog, r.Backlog...)
+		merged.Usage.InputTokens += r.Usage.InputTokens
+		merged.Usage.CacheReadTokens += r.Usage.CacheReadTokens
+		merged.Usage.OutputTokens += r.Usage.OutputTokens
 
 		// Take last approach
 		if r.Approach != "" {
@@ -111,6 +111,36 @@ Waiting for decision.
 	}
 }
 
+func TestExtractUsage(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   Usage
+	}{
+		{
+			name:   "claude json result",
+			output: `{"type":"result","result":"Done <promise>COMPLETE</promise>","usage":{"input_tokens":12,"cache_creation_input_tokens":3000,"cache_read_input_tokens":90000,"output_tokens":2500,"server_tool_use":{"web_search_requests":0}},"total_cost_usd":0.1}`,
+			want:   Usage{InputTokens: 3012, CacheReadTokens: 90000, OutputTokens: 2500},
+		},
+		{
+			name:   "last usage wins",
+			output: "{\"usage\":{\"input_tokens\":1,\"output_tokens\":2}}\n{\"usage\":{\"input_tokens\":10,\"output_tokens\":20}}",
+			want:   Usage{InputTokens: 10, OutputTokens: 20},
+		},
+		{
+			name:   "plain text",
+			output: "Did the work\n<promise>COMPLETE</promise>",
+			want:   Usage{},
+		},
+	}
+
+	for _, tt := range tests {
+		if got := ExtractUsage(tt.output); got != tt.want {
+			t.Errorf("%s: ExtractUsage() = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
 func TestStripTags(t *testing.T) {
 	output := `
 <approach>Test approach</approach>
@@ -0,0 +1,29 @@
+//go:build !unix
+
+package worker
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// setProcessGroup is a no-op where process groups are unsupported.
+func setProcessGroup(cmd *exec.Cmd) {}
+
+// signalGroup signals just the process where process groups are unsupported.
+func signalGroup(pid int, sig syscall.Signal) error {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+	if sig == syscall.SIGKILL {
+		return p.Kill()
+	}
+	return p.Signal(sig)
+}
+
+// groupProcesses cannot list a process group here, so reports nothing.
+func groupProcesses(pid int) []string {
+	return nil
+}
@@ -0,0 +1,82 @@
+//go:build unix
+
+package worker
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestCLIWorkerProcessGroup(t *testing.T) {
+	dir := t.TempDir()
+	pidFile := filepath.Join(dir, "child.pid")
+
+	tests := []struct {
+		name         string
+		timeout      time.Duration
+		wantTimeout  bool
+		wantLeftover bool
+	}{
+		// The worker exits but leaves its background child running
+		{"leftover is reported", 20 * time.Second, false, true},
+		// The worker times out and its child goes down with it
+		{"timeout kills the group", 300 * time.Millisecond, true, false},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			script := filepath.Join(dir, "worker"+strconv.Itoa(i)+".sh")
+			body := "#!/bin/sh\nsleep 30 >/dev/null 2>&1 &\necho $! > " + pidFile + "\n"
+			if tt.wantTimeout {
+				body += "exec sleep 30\n"
+			}
+			os.WriteFile(script, []byte(body), 0755)
+
+			w := NewCLIWorker(&Config{
+				Command:       script,
+				Timeout:       tt.timeout,
+				Quiet:         true,
+				ShutdownGrace: time.Second,
+			})
+			result, err := w.Execute(context.Background(), "prompt")
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			data, _ := os.ReadFile(pidFile)
+			child, _ := strconv.Atoi(strings.TrimSpace(string(data)))
+			if child == 0 {
+				t.Fatal("worker did not record its child")
+			}
+			defer syscall.Kill(child, syscall.SIGKILL)
+
+			if result.Timeout != tt.wantTimeout {
+				t.Errorf("Timeout = %v, want %v", result.Timeout, tt.wantTimeout)
+			}
+			if got := len(result.Leftover) > 0; got != tt.wantLeftover {
+				t.Errorf("Leftover = %v, want leftover %v", result.Leftover, tt.wantLeftover)
+			}
+			if tt.wantTimeout && running(child) {
+				t.Errorf("child %d still running after the worker timed out", child)
+			}
+		})
+	}
+}
+
+// running reports whether pid is alive, counting an unreaped zombie as gone.
+func running(pid int) bool {
+	for i := 0; i < 10; i++ {
+		out, _ := exec.Command("ps", "-o", "stat=", "-p", strconv.Itoa(pid)).Output()
+		stat := strings.TrimSpace(string(out))
+		if stat == "" || strings.HasPrefix(stat, "Z") {
+			return false
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return true
+}
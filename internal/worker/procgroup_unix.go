@@ -0,0 +1,47 @@
+//go:build unix
+
+package worker
+
+import (
+	"context"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// setProcessGroup starts the command in its own process group, so the
+// shells, test runners and servers it spawns can be signalled with it.
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// signalGroup sends sig to every process in the group led by pid.
+func signalGroup(pid int, sig syscall.Signal) error {
+	return syscall.Kill(-pid, sig)
+}
+
+// groupProcesses lists the processes still in the group led by pid, as
+// "pid command" lines.
+func groupProcesses(pid int) []string {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, "ps", "-A", "-o", "pid=,pgid=,args=").Output()
+	if err != nil {
+		return nil
+	}
+
+	var procs []string
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			continue
+		}
+		if pgid, err := strconv.Atoi(fields[1]); err != nil || pgid != pid {
+			continue
+		}
+		procs = append(procs, fields[0]+" "+strings.Join(fields[2:], " "))
+	}
+	return procs
+}
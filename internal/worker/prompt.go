@@ -4,8 +4,13 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
+	"brigade/internal/chef"
+	"brigade/internal/classify"
 	"brigade/internal/prd"
 	"brigade/internal/state"
 )
@@ -15,6 +20,15 @@ type PromptBuilder struct {
 	chefDir      string
 	learningsPath string
 	backlogPath  string
+	retriever    Retriever
+	strategies   classify.Strategies
+	licenseHeader string
+	commits      CommitConvention
+}
+
+// Retriever finds code related to a task, such as from an embedding index.
+type Retriever interface {
+	Retrieve(task *prd.Task) string
 }
 
 // NewPromptBuilder creates a new prompt builder.
@@ -26,6 +40,28 @@ func NewPromptBuilder(chefDir, learningsPath, backlogPath string) *PromptBuilder
 	}
 }
 
+// SetRetriever adds related code found by r to task prompts.
+func (b *PromptBuilder) SetRetriever(r Retriever) {
+	b.retriever = r
+}
+
+// SetStrategies overrides the built-in retry suggestions for some error
+// categories.
+func (b *PromptBuilder) SetStrategies(s classify.Strategies) {
+	b.strategies = s
+}
+
+// SetLicenseHeader tells workers the license header new files must start
+// with.
+func (b *PromptBuilder) SetLicenseHeader(header string) {
+	b.licenseHeader = header
+}
+
+// SetCommitConvention tells workers how to write and sign their commits.
+func (b *PromptBuilder) SetCommitConvention(c CommitConvention) {
+	b.commits = c
+}
+
 // BuildTaskPrompt builds a prompt for task execution.
 func (b *PromptBuilder) BuildTaskPrompt(opts TaskPromptOptions) (string, error) {
 	var parts []string
@@ -41,6 +77,50 @@ func (b *PromptBuilder) BuildTaskPrompt(opts TaskPromptOptions) (string, error)
 	taskSection := b.buildTaskSection(opts.Task, opts.PRD)
 	parts = append(parts, taskSection)
 
+	// Add guidance a human sent while watching the task
+	if len(opts.Nudges) > 0 {
+		parts = append(parts, "\n=== GUIDANCE FROM THE OPERATOR ===\nA human watching this task sent these notes. They take priority over your previous approach:\n- "+strings.Join(opts.Nudges, "\n- ")+"\n=== END GUIDANCE ===")
+	}
+
+	// Inline the files the task names so workers don't have to find them
+	if len(opts.Task.Files) > 0 && opts.FilesBudget > 0 {
+		if files := b.buildFilesSection(opts.Task.Files, opts.FilesBudget); files != "" {
+			parts = append(parts, files)
+		}
+	}
+
+	// Add code the prep cook gathered for expensive tiers
+	if opts.PrepContext != "" {
+		parts = append(parts, "\n=== PREP COOK CONTEXT ===\nGathered automatically before this attempt; confirm before relying on it.\n"+opts.PrepContext+"\n=== END PREP COOK CONTEXT ===")
+	}
+
+	// Add indexed code related to the task
+	if b.retriever != nil {
+		if related := b.retriever.Retrieve(opts.Task); related != "" {
+			parts = append(parts, "\n=== RELATED CODE ===\nFrom the code index; may be out of date."+related+"=== END RELATED CODE ===")
+		}
+	}
+
+	// Add parent PRD context for iterations
+	if opts.ParentContext != "" {
+		parts = append(parts, "\n=== PARENT PRD CONTEXT ===\n"+opts.ParentContext+"\n=== END PARENT CONTEXT ===")
+	}
+
+	// Add what the prerequisite PRDs left behind
+	if opts.PrerequisiteContext != "" {
+		parts = append(parts, "\n=== PREREQUISITE PRDS ===\n"+opts.PrerequisiteContext+"\n=== END PREREQUISITE PRDS ===")
+	}
+
+	// Add the handoffs of the PRDs chained before this one
+	if opts.HandoffContext != "" {
+		parts = append(parts, "\n=== HANDOFF FROM EARLIER PRDS ===\n"+opts.HandoffContext+"\n=== END HANDOFF ===")
+	}
+
+	// Add recovery context after a crashed run
+	if opts.RecoveryContext != "" {
+		parts = append(parts, "\n=== INTERRUPTED ATTEMPT ===\n"+opts.RecoveryContext+"=== END INTERRUPTED ATTEMPT ===")
+	}
+
 	// Add learnings if available
 	if b.learningsPath != "" {
 		learnings, err := b.loadLearnings()
@@ -54,6 +134,11 @@ func (b *PromptBuilder) BuildTaskPrompt(opts TaskPromptOptions) (string, error)
 		parts = append(parts, fmt.Sprintf("\n⚠️ PREVIOUS ATTEMPT FAILED EXECUTIVE REVIEW: %s\n", opts.ReviewFeedback))
 	}
 
+	// Add the verification commands the previous attempt failed
+	if len(opts.VerificationFailures) > 0 {
+		parts = append(parts, b.buildVerificationFailures(opts.VerificationFailures))
+	}
+
 	// Add previous approaches for smart retry
 	if len(opts.PreviousApproaches) > 0 {
 		parts = append(parts, b.buildApproachHistory(opts.PreviousApproaches))
@@ -87,6 +172,16 @@ type TaskPromptOptions struct {
 	SessionFailures    []state.SessionFailure
 	EscalationContext  *EscalationContext
 	CodebaseMap        string
+	ParentContext      string // Summary of the parent PRD for iterations
+	PrerequisiteContext string // Outcome of the PRDs this one depends on
+	HandoffContext     string // Handoff docs of the PRDs before this one
+	RecoveryContext    string // What an interrupted previous attempt left behind
+	FilesBudget        int    // Max bytes of task file contents to inline
+	PrepContext        string // Code gathered by the prep cook
+	Nudges             []string // Guidance sent by a human with `brigade nudge`
+
+	// Verification commands that failed on the previous attempt
+	VerificationFailures []state.VerificationFailure
 }
 
 // EscalationContext holds context about an escalation.
@@ -94,6 +189,15 @@ type EscalationContext struct {
 	FromTier          state.WorkerTier
 	Attempts          []state.ApproachEntry
 	FailureCategories []string
+	Diffs             []AttemptDiff
+}
+
+// AttemptDiff is the code a lower tier's attempts on a task changed.
+type AttemptDiff struct {
+	Tier      state.WorkerTier
+	Diff      string // Unified diff of the last attempt that changed anything
+	Reverted  bool   // Later attempts undid it; the working tree no longer has it
+	Truncated bool
 }
 
 // buildTaskSection builds the task details section.
@@ -127,6 +231,38 @@ func (b *PromptBuilder) buildTaskSection(task *prd.Task, p *prd.PRD) string {
 		sb.WriteString(fmt.Sprintf("\nDepends on: %s (already completed)\n", strings.Join(task.DependsOn, ", ")))
 	}
 
+	if len(task.Files) > 0 {
+		sb.WriteString(fmt.Sprintf("\nFiles: %s\n", strings.Join(task.Files, ", ")))
+	}
+
+	if task.Workspace != "" {
+		sb.WriteString(fmt.Sprintf("\nWorkspace: %s\nYou are working in this package of a monorepo. Verification runs from this directory; keep your changes inside it.\n", task.Workspace))
+	}
+
+	if task.Setup != "" {
+		sb.WriteString(fmt.Sprintf("\nSetup: `%s` has already been run for this attempt and is torn down after verification. Don't start or stop what it manages yourself.\n", task.Setup))
+	}
+	if len(task.Env) > 0 {
+		names := make([]string, 0, len(task.Env))
+		for name := range task.Env {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		sb.WriteString(fmt.Sprintf("\nEnvironment: %s are set for you and for verification.\n", strings.Join(names, ", ")))
+	}
+
+	sb.WriteString("\nArtifacts: save screenshots, coverage reports or benchmark output worth keeping to $BRIGADE_ARTIFACTS_DIR.\n")
+
+	if commit := b.commits.Instructions(task); commit != "" {
+		sb.WriteString("\n" + commit + "\n")
+	}
+
+	if b.licenseHeader != "" {
+		header := strings.ReplaceAll(b.licenseHeader, "{{year}}", strconv.Itoa(time.Now().Year()))
+		sb.WriteString("\nLicense header: start every new source file with this header, as a comment:\n")
+		sb.WriteString(header + "\n")
+	}
+
 	sb.WriteString("\n=== END TASK ===")
 
 	return sb.String()
@@ -134,19 +270,7 @@ func (b *PromptBuilder) buildTaskSection(task *prd.Task, p *prd.PRD) string {
 
 // loadChefPrompt loads the base prompt for a worker tier.
 func (b *PromptBuilder) loadChefPrompt(tier state.WorkerTier) (string, error) {
-	var filename string
-	switch tier {
-	case state.TierLine:
-		filename = "line.md"
-	case state.TierSous:
-		filename = "sous.md"
-	case state.TierExecutive:
-		filename = "executive.md"
-	default:
-		filename = "line.md"
-	}
-
-	path := filepath.Join(b.chefDir, filename)
+	path := filepath.Join(b.chefDir, chef.File(tier))
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return "", fmt.Errorf("reading %s: %w", path, err)
@@ -178,17 +302,41 @@ func (b *PromptBuilder) buildApproachHistory(approaches []state.ApproachEntry) s
 
 	sb.WriteString("\n=== PREVIOUS APPROACHES (avoid repeating these) ===\n")
 	for _, a := range approaches {
-		if a.Category != "" {
-			sb.WriteString(fmt.Sprintf("- %s: %s → %s\n", a.Worker, a.Approach, a.Category))
-		} else {
-			sb.WriteString(fmt.Sprintf("- %s: %s\n", a.Worker, a.Approach))
-		}
+		sb.WriteString(formatApproach(a))
+	}
+	last := approaches[len(approaches)-1]
+	if last.RepeatOf > 0 {
+		sb.WriteString(fmt.Sprintf("\nYour last attempt repeated attempt %d. Rewording a failed approach won't change the result: change the strategy.\n", last.RepeatOf))
+	}
+	if last.Category != "" {
+		sb.WriteString("\n" + b.strategies.Suggestion(classify.Category(last.Category)) + "\n")
 	}
 	sb.WriteString("\nTry a DIFFERENT approach.\n=== END PREVIOUS APPROACHES ===")
 
 	return sb.String()
 }
 
+// formatApproach renders a previous approach as a list item, calling out
+// an approach that repeats an earlier attempt's.
+func formatApproach(a state.ApproachEntry) string {
+	line := fmt.Sprintf("- %s: %s", a.Worker, a.Approach)
+	if a.Attempt > 0 {
+		line = fmt.Sprintf("- %s (attempt %d): %s", a.Worker, a.Attempt, a.Approach)
+	}
+	if a.Category != "" {
+		line += " → " + a.Category
+	}
+	line += "\n"
+	if a.RepeatOf > 0 {
+		if a.RepeatCategory != "" {
+			line += fmt.Sprintf("  ↳ You already tried this (attempt %d), which failed with %s\n", a.RepeatOf, a.RepeatCategory)
+		} else {
+			line += fmt.Sprintf("  ↳ You already tried this (attempt %d)\n", a.RepeatOf)
+		}
+	}
+	return line
+}
+
 // buildSessionFailures builds the session failures section.
 func (b *PromptBuilder) buildSessionFailures(failures []state.SessionFailure) string {
 	var sb strings.Builder
@@ -202,6 +350,28 @@ func (b *PromptBuilder) buildSessionFailures(failures []state.SessionFailure) st
 	return sb.String()
 }
 
+// buildVerificationFailures builds the section listing failed verification
+// commands and their output.
+func (b *PromptBuilder) buildVerificationFailures(failures []state.VerificationFailure) string {
+	var sb strings.Builder
+
+	sb.WriteString("\n=== VERIFICATION FAILURES ===\n")
+	sb.WriteString("Your previous attempt signaled COMPLETE, but these verification commands failed:\n")
+	for _, f := range failures {
+		if f.Log {
+			sb.WriteString(fmt.Sprintf("\n--- %s log ---\n", f.Command))
+		} else {
+			sb.WriteString(fmt.Sprintf("\n$ %s (exit code %d)\n", f.Command, f.ExitCode))
+		}
+		if output := strings.TrimSpace(f.Output); output != "" {
+			sb.WriteString(output + "\n")
+		}
+	}
+	sb.WriteString("\nFix the cause of these failures before signaling COMPLETE again.\n=== END VERIFICATION FAILURES ===")
+
+	return sb.String()
+}
+
 // buildEscalationContext builds the escalation context section.
 func (b *PromptBuilder) buildEscalationContext(ctx *EscalationContext) string {
 	var sb strings.Builder
@@ -212,21 +382,31 @@ func (b *PromptBuilder) buildEscalationContext(ctx *EscalationContext) string {
 	if len(ctx.Attempts) > 0 {
 		sb.WriteString("\nAttempted approaches:\n")
 		for _, a := range ctx.Attempts {
-			if a.Category != "" {
-				sb.WriteString(fmt.Sprintf("- %s: %s → %s\n", a.Worker, a.Approach, a.Category))
-			} else {
-				sb.WriteString(fmt.Sprintf("- %s: %s\n", a.Worker, a.Approach))
-			}
+			sb.WriteString(formatApproach(a))
 		}
 	}
 
+	for _, d := range ctx.Diffs {
+		if d.Reverted {
+			sb.WriteString(fmt.Sprintf("\nCode the %s tier attempted (since reverted):\n", d.Tier))
+		} else {
+			sb.WriteString(fmt.Sprintf("\nCode the %s tier left in the working tree:\n", d.Tier))
+		}
+		sb.WriteString("```diff\n" + strings.TrimRight(d.Diff, "\n") + "\n")
+		if d.Truncated {
+			sb.WriteString("... (diff truncated)\n")
+		}
+		sb.WriteString("```\n")
+	}
+
 	sb.WriteString("\nDo NOT repeat these approaches.\n=== END ESCALATION CONTEXT ===")
 
 	return sb.String()
 }
 
-// BuildReviewPrompt builds a prompt for executive review.
-func (b *PromptBuilder) BuildReviewPrompt(task *prd.Task, workerOutput string) (string, error) {
+// BuildReviewPrompt builds a prompt for executive review. Policy lists the
+// outcome of checks such as license headers, for the reviewer to weigh.
+func (b *PromptBuilder) BuildReviewPrompt(task *prd.Task, workerOutput string, policy []string) (string, error) {
 	basePrompt, err := b.loadChefPrompt(state.TierExecutive)
 	if err != nil {
 		return "", err
@@ -240,9 +420,24 @@ func (b *PromptBuilder) BuildReviewPrompt(task *prd.Task, workerOutput string) (
 	sb.WriteString("Task:\n")
 	sb.WriteString(fmt.Sprintf("  ID: %s\n", task.ID))
 	sb.WriteString(fmt.Sprintf("  Title: %s\n", task.Title))
-	sb.WriteString("  Acceptance Criteria:\n")
-	for i, criterion := range task.AcceptanceCriteria {
-		sb.WriteString(fmt.Sprintf("    %d. %s\n", i+1, criterion))
+	sb.WriteString("  Acceptance Criteria (and what verifies each):\n")
+	for _, trace := range task.Trace() {
+		sb.WriteString(fmt.Sprintf("    %d. %s\n", trace.Index, trace.Criterion))
+		switch {
+		case len(trace.Commands) > 0:
+			sb.WriteString(fmt.Sprintf("       verified by: %s\n", strings.Join(trace.Commands, "; ")))
+		case trace.Manual != "":
+			sb.WriteString(fmt.Sprintf("       manual check: %s\n", trace.Manual))
+		default:
+			sb.WriteString("       NOT VERIFIED - check this one yourself against the changes\n")
+		}
+	}
+
+	if len(policy) > 0 {
+		sb.WriteString("\nPolicy Checks:\n")
+		for _, p := range policy {
+			sb.WriteString(fmt.Sprintf("  - %s\n", p))
+		}
 	}
 
 	sb.WriteString("\nWorker Output:\n")
@@ -251,13 +446,194 @@ func (b *PromptBuilder) BuildReviewPrompt(task *prd.Task, workerOutput string) (
 	sb.WriteString("\n\nRespond with:\n")
 	sb.WriteString("- <review>PASS</review> if all acceptance criteria are met\n")
 	sb.WriteString("- <review>FAIL: [reason]</review> if criteria are not met\n")
+	sb.WriteString("\nWith a PASS, also rate how sure you are the work is right, and name anything\n")
+	sb.WriteString("a human should double-check:\n")
+	sb.WriteString("<confidence>high|medium|low</confidence>\n")
+	sb.WriteString("<concerns>[what to double-check, if anything]</concerns>\n")
 	sb.WriteString("=== END REVIEW REQUEST ===")
 
 	return sb.String(), nil
 }
 
-// BuildWalkawayDecisionPrompt builds a prompt for autonomous failure decisions.
-func (b *PromptBuilder) BuildWalkawayDecisionPrompt(task *prd.Task, failureReason string, attempts int) (string, error) {
+// BuildPhaseReviewPrompt builds a prompt for an executive review of overall
+// progress: the end of a PRD phase, or every PHASE_REVIEW_AFTER tasks when
+// phase is 0. With remediate, the review may add corrective tasks.
+func (b *PromptBuilder) BuildPhaseReviewPrompt(p *prd.PRD, phase int, remediate bool) (string, error) {
+	basePrompt, err := b.loadChefPrompt(state.TierExecutive)
+	if err != nil {
+		return "", err
+	}
+
+	done, total := p.Progress()
+	var sb strings.Builder
+	sb.WriteString(basePrompt)
+	sb.WriteString("\n\n=== PHASE REVIEW ===\n")
+	if next := p.CurrentPhase(); phase > 0 && next > 0 {
+		sb.WriteString(fmt.Sprintf("Phase %d of %s is complete. Check the work so far against the PRD before phase %d starts.\n\n", phase, p.FeatureName, next))
+	} else if phase > 0 {
+		sb.WriteString(fmt.Sprintf("Phase %d of %s is complete. Check the work so far against the PRD.\n\n", phase, p.FeatureName))
+	} else {
+		sb.WriteString(fmt.Sprintf("Check the work so far on %s against the PRD.\n\n", p.FeatureName))
+	}
+	if p.Description != "" {
+		sb.WriteString(fmt.Sprintf("Description: %s\n", p.Description))
+	}
+	sb.WriteString(fmt.Sprintf("Progress: %d of %d tasks complete\n", done, total))
+
+	sb.WriteString("\nCompleted tasks:\n")
+	for _, task := range p.CompletedTasks() {
+		sb.WriteString(fmt.Sprintf("- %s: %s%s\n", task.ID, task.Title, phaseLabel(task)))
+	}
+	if pending := p.PendingTasks(); len(pending) > 0 {
+		sb.WriteString("\nRemaining tasks:\n")
+		for _, task := range pending {
+			sb.WriteString(fmt.Sprintf("- %s: %s [%s]%s\n", task.ID, task.Title, task.Complexity, phaseLabel(task)))
+		}
+	}
+
+	sb.WriteString("\nPlease review:\n")
+	sb.WriteString("1. Are completed tasks aligned with the PRD and their acceptance criteria?\n")
+	sb.WriteString("2. Is there any drift from the original requirements?\n")
+	sb.WriteString("3. Is the remaining work still the right work?\n\n")
+
+	sb.WriteString("Respond with:\n")
+	sb.WriteString("<phase_review>\n")
+	sb.WriteString("STATUS: on_track | minor_concerns | needs_attention\n")
+	sb.WriteString("ASSESSMENT: your analysis\n")
+	sb.WriteString("RECOMMENDATIONS: suggestions, or none\n")
+	sb.WriteString("</phase_review>\n")
+	if remediate {
+		sb.WriteString("\nIf STATUS is needs_attention, you may add corrective tasks in the PRD task format:\n")
+		sb.WriteString("<remediation_tasks>\n")
+		sb.WriteString(`[{"id": "FIX-001", "title": "Fix: ...", "acceptanceCriteria": ["..."], "complexity": "senior", "dependsOn": []}]`)
+		sb.WriteString("\n</remediation_tasks>\n")
+	}
+	sb.WriteString("=== END PHASE REVIEW ===")
+
+	return sb.String(), nil
+}
+
+// BuildChainReviewPrompt builds a prompt asking the executive chef whether
+// a completed PRD is sound enough for the next PRD of a chain to build on.
+// handoff is the PRD's handoff doc, if it wrote one.
+func (b *PromptBuilder) BuildChainReviewPrompt(p *prd.PRD, next, handoff string) (string, error) {
+	basePrompt, err := b.loadChefPrompt(state.TierExecutive)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	sb.WriteString(basePrompt)
+	sb.WriteString("\n\n=== PRD REVIEW ===\n")
+	sb.WriteString(fmt.Sprintf("%s is complete. %s runs next and builds on it.\n\n", p.FeatureName, next))
+	if p.Description != "" {
+		sb.WriteString(fmt.Sprintf("Description: %s\n", p.Description))
+	}
+	sb.WriteString("\nTasks:\n")
+	for _, task := range p.Tasks {
+		sb.WriteString(fmt.Sprintf("- %s: %s\n", task.ID, task.Title))
+		for _, criterion := range task.AcceptanceCriteria {
+			sb.WriteString(fmt.Sprintf("  - %s\n", criterion))
+		}
+	}
+	if handoff != "" {
+		sb.WriteString("\n" + handoff + "\n")
+	}
+
+	sb.WriteString("\nCheck the code against the PRD. Fail the review only for problems the next PRD shouldn't build on: ")
+	sb.WriteString("unmet acceptance criteria, broken builds or tests, or a design that will have to be redone.\n\n")
+
+	sb.WriteString("Respond with:\n")
+	sb.WriteString("<prd_review>\n")
+	sb.WriteString("RESULT: PASS | FAIL\n")
+	sb.WriteString("REASON: one or two sentences\n")
+	sb.WriteString("</prd_review>\n")
+	sb.WriteString("=== END PRD REVIEW ===")
+
+	return sb.String(), nil
+}
+
+// BuildUnblockPrompt builds a prompt asking the executive chef how to get a
+// blocked run moving again: the dependency graph of the remaining work, the
+// tasks that were skipped or failed, and why the run stopped.
+func (b *PromptBuilder) BuildUnblockPrompt(p *prd.PRD, st *state.State, reason string) (string, error) {
+	basePrompt, err := b.loadChefPrompt(state.TierExecutive)
+	if err != nil {
+		return "", err
+	}
+
+	done, total := p.Progress()
+	var sb strings.Builder
+	sb.WriteString(basePrompt)
+	sb.WriteString("\n\n=== UNBLOCK ANALYSIS ===\n")
+	sb.WriteString(fmt.Sprintf("The run of %s stopped: %s\n", p.FeatureName, reason))
+	sb.WriteString(fmt.Sprintf("Progress: %d of %d tasks complete\n", done, total))
+
+	completed := st.CompletedTaskIDs()
+	sb.WriteString("\nRemaining tasks and their dependencies:\n")
+	for i := range p.Tasks {
+		task := &p.Tasks[i]
+		if completed[task.ID] {
+			continue
+		}
+		line := fmt.Sprintf("- %s: %s [%s]%s", task.ID, task.Title, task.Complexity, phaseLabel(task))
+		if len(task.DependsOn) > 0 {
+			line += " depends on " + strings.Join(task.DependsOn, ", ")
+		}
+		sb.WriteString(line + "\n")
+	}
+
+	// Each unfinished task's attempts and how the last one ended
+	var history []string
+	for _, task := range p.Tasks {
+		if completed[task.ID] {
+			continue
+		}
+		last := st.LastAttempt(task.ID)
+		if last == nil {
+			continue
+		}
+		line := fmt.Sprintf("- %s: %s after %d attempts", task.ID, last.Status, st.TotalAttempts(task.ID))
+		if last.Error != "" {
+			line += ": " + last.Error
+		}
+		if last.Category != "" {
+			line += fmt.Sprintf(" (%s)", last.Category)
+		}
+		history = append(history, line)
+	}
+	if len(history) > 0 {
+		sb.WriteString("\nFailure history:\n")
+		sb.WriteString(strings.Join(history, "\n"))
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("\nPropose concrete ways to unblock the run, most useful first. Each is one of:\n")
+	sb.WriteString("- reorder: run tasks in a different order\n")
+	sb.WriteString("- relax_dependency: drop or change a dependency that isn't really needed\n")
+	sb.WriteString("- retry: retry a skipped or failed task, with what to do differently\n")
+	sb.WriteString("- human_action: something a person has to do (credentials, services, decisions)\n\n")
+
+	sb.WriteString("Respond with:\n")
+	sb.WriteString("<unblock>\n")
+	sb.WriteString("<suggestion kind=\"relax_dependency\">US-004 doesn't need US-003; drop the dependency</suggestion>\n")
+	sb.WriteString("</unblock>\n")
+	sb.WriteString("=== END UNBLOCK ANALYSIS ===")
+
+	return sb.String(), nil
+}
+
+// phaseLabel marks a task's phase in a task list.
+func phaseLabel(task *prd.Task) string {
+	if task.Phase == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (phase %d)", task.Phase)
+}
+
+// BuildWalkawayDecisionPrompt builds a prompt for autonomous failure
+// decisions. impact says what skipping the task would leave unreachable.
+func (b *PromptBuilder) BuildWalkawayDecisionPrompt(task *prd.Task, failureReason string, attempts int, impact string) (string, error) {
 	basePrompt, err := b.loadChefPrompt(state.TierExecutive)
 	if err != nil {
 		return "", err
@@ -268,7 +644,11 @@ func (b *PromptBuilder) BuildWalkawayDecisionPrompt(task *prd.Task, failureReaso
 	sb.WriteString("\n\n=== DECISION REQUIRED ===\n")
 	sb.WriteString(fmt.Sprintf("Task %s failed after %d attempts.\n\n", task.ID, attempts))
 	sb.WriteString(fmt.Sprintf("Task: %s\n", task.Title))
-	sb.WriteString(fmt.Sprintf("Failure: %s\n\n", failureReason))
+	sb.WriteString(fmt.Sprintf("Failure: %s\n", failureReason))
+	if impact != "" {
+		sb.WriteString(fmt.Sprintf("Impact: %s\n", impact))
+	}
+	sb.WriteString("\n")
 
 	sb.WriteString("Options:\n")
 	sb.WriteString("1. RETRY - Try the task again with a different approach\n")
@@ -309,6 +689,36 @@ func (b *PromptBuilder) BuildScopeDecisionPrompt(task *prd.Task, question string
 	return sb.String(), nil
 }
 
+// BuildMergePrompt builds a prompt asking the sous chef to resolve the
+// conflicts a rebase of the PRD's branch stopped on.
+func (b *PromptBuilder) BuildMergePrompt(p *prd.PRD, onto, commit string, files []string) (string, error) {
+	basePrompt, err := b.loadChefPrompt(state.TierSous)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	sb.WriteString(basePrompt)
+	sb.WriteString("\n\n=== MERGE CONFLICTS ===\n")
+	sb.WriteString(fmt.Sprintf("The %s branch is being rebased onto %s for %s.\n", p.BranchName, onto, p.FeatureName))
+	sb.WriteString(fmt.Sprintf("Replaying commit: %s\n\n", commit))
+	sb.WriteString("Conflicted files:\n")
+	for _, f := range files {
+		sb.WriteString(fmt.Sprintf("- %s\n", f))
+	}
+
+	sb.WriteString("\nResolve each conflict in place, keeping the intent of both sides: ")
+	sb.WriteString(fmt.Sprintf("the upstream change from %s and the PRD's change. ", onto))
+	sb.WriteString("Remove every conflict marker. Don't edit other files, stage, commit or run git rebase; Brigade continues the rebase.\n")
+	sb.WriteString("If a conflict can't be resolved without a judgment call, leave its markers in place.\n\n")
+
+	sb.WriteString("When done, respond with:\n")
+	sb.WriteString("<promise>COMPLETE</promise>\n")
+	sb.WriteString("=== END MERGE CONFLICTS ===")
+
+	return sb.String(), nil
+}
+
 // StrategySuggestions returns suggestions based on error category.
 func StrategySuggestions(category string) string {
 	switch category {
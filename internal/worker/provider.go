@@ -0,0 +1,133 @@
+package worker
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// Agents whose worker CLI is pointed at another endpoint instead of the
+// public API: Claude on AWS Bedrock through the claude CLI, Azure OpenAI
+// deployments through opencode, and a model server on this machine or the
+// local network (Ollama, llama.cpp) through any CLI that speaks to one.
+const (
+	AgentBedrock = "bedrock"
+	AgentAzure   = "azure"
+	AgentLocal   = "local"
+)
+
+// DefaultLocalModelURL is Ollama's address.
+const DefaultLocalModelURL = "http://localhost:11434"
+
+// ProviderSettings configure the provider adapters.
+type ProviderSettings struct {
+	BedrockRegion  string // AWS region (default: AWS_REGION)
+	BedrockProfile string // AWS profile (default: the AWS credential chain)
+	AzureResource  string // Azure OpenAI resource name or endpoint URL (default: AZURE_RESOURCE_NAME)
+	LocalModelURL  string // Local model server (default: DefaultLocalModelURL)
+}
+
+// UsesClaudeCLI reports whether an agent's workers run the claude CLI.
+func UsesClaudeCLI(agent string) bool {
+	return agent == "claude" || agent == AgentBedrock
+}
+
+// ProviderEnv returns the environment that points a tier's worker command
+// at its agent's provider and authenticates it there. Agents without an
+// adapter need none. credentialEnv is what each of the tier's credentials
+// sets (see ParseCredentials); a provider's key may come from there instead
+// of Brigade's environment, as long as every credential sets it.
+func ProviderEnv(agent, command string, s ProviderSettings, credentialEnv [][]string) ([]string, error) {
+	switch agent {
+	case AgentBedrock:
+		if !strings.Contains(command, "claude") {
+			return nil, fmt.Errorf("bedrock workers run the claude CLI, not %q", command)
+		}
+		region := firstNonEmpty(s.BedrockRegion, os.Getenv("AWS_REGION"), os.Getenv("AWS_DEFAULT_REGION"))
+		if region == "" && !setByAll(credentialEnv, "AWS_REGION") {
+			return nil, fmt.Errorf("bedrock needs a region: set BEDROCK_REGION or AWS_REGION")
+		}
+		env := []string{"CLAUDE_CODE_USE_BEDROCK=1"}
+		if region != "" {
+			env = append(env, "AWS_REGION="+region)
+		}
+		if s.BedrockProfile != "" {
+			env = append(env, "AWS_PROFILE="+s.BedrockProfile)
+		}
+		return env, nil
+
+	case AgentAzure:
+		if !strings.Contains(command, "opencode") || !strings.Contains(command, "azure/") {
+			return nil, fmt.Errorf("azure workers run opencode with an azure/<deployment> model, not %q", command)
+		}
+		resource, err := azureResourceName(firstNonEmpty(s.AzureResource, os.Getenv("AZURE_RESOURCE_NAME")))
+		if err != nil {
+			return nil, err
+		}
+		if resource == "" {
+			return nil, fmt.Errorf("azure needs a resource: set AZURE_OPENAI_RESOURCE or AZURE_RESOURCE_NAME")
+		}
+		if os.Getenv("AZURE_API_KEY") == "" && !setByAll(credentialEnv, "AZURE_API_KEY") {
+			return nil, fmt.Errorf("azure needs an API key: set AZURE_API_KEY, or set it in every one of the tier's credentials")
+		}
+		return []string{"AZURE_RESOURCE_NAME=" + resource}, nil
+
+	case AgentLocal:
+		base := strings.TrimRight(firstNonEmpty(s.LocalModelURL, DefaultLocalModelURL), "/")
+		// Ollama and llama.cpp's server both serve the OpenAI API under /v1,
+		// which wants a key even though neither checks it
+		env := []string{"OLLAMA_HOST=" + base, "OPENAI_BASE_URL=" + base + "/v1"}
+		if os.Getenv("OPENAI_API_KEY") == "" && !setByAll(credentialEnv, "OPENAI_API_KEY") {
+			env = append(env, "OPENAI_API_KEY=local")
+		}
+		return env, nil
+	}
+	return nil, nil
+}
+
+// azureResourceName accepts a resource name or its endpoint URL
+// (https://<resource>.openai.azure.com/).
+func azureResourceName(value string) (string, error) {
+	if !strings.Contains(value, "://") {
+		return value, nil
+	}
+	u, err := url.Parse(value)
+	if err != nil {
+		return "", fmt.Errorf("azure endpoint %q: %w", value, err)
+	}
+	name, _, ok := strings.Cut(u.Hostname(), ".")
+	if !ok || name == "" {
+		return "", fmt.Errorf("azure endpoint %q isn't https://<resource>.openai.azure.com", value)
+	}
+	return name, nil
+}
+
+// setByAll reports whether every credential sets key. False when there are
+// no credentials.
+func setByAll(credentialEnv [][]string, key string) bool {
+	if len(credentialEnv) == 0 {
+		return false
+	}
+	for _, env := range credentialEnv {
+		found := false
+		for _, kv := range env {
+			if strings.HasPrefix(kv, key+"=") && len(kv) > len(key)+1 {
+				found = true
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,80 @@
+package worker
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestProviderEnv(t *testing.T) {
+	t.Setenv("AWS_REGION", "")
+	t.Setenv("AWS_DEFAULT_REGION", "")
+	t.Setenv("AZURE_RESOURCE_NAME", "")
+	t.Setenv("AZURE_API_KEY", "")
+	t.Setenv("OPENAI_API_KEY", "")
+
+	tests := []struct {
+		name          string
+		agent         string
+		command       string
+		settings      ProviderSettings
+		credentialEnv [][]string
+		want          []string
+		wantErr       string
+	}{
+		{"claude needs nothing", "claude", "claude --model sonnet", ProviderSettings{}, nil, nil, ""},
+		{
+			"bedrock",
+			"bedrock", "claude --model opus", ProviderSettings{BedrockRegion: "us-east-1", BedrockProfile: "prod"}, nil,
+			[]string{"CLAUDE_CODE_USE_BEDROCK=1", "AWS_REGION=us-east-1", "AWS_PROFILE=prod"}, "",
+		},
+		{
+			"bedrock region from credentials",
+			"bedrock", "claude", ProviderSettings{}, [][]string{{"AWS_REGION=us-west-2"}, {"AWS_REGION=eu-west-1", "AWS_PROFILE=eu"}},
+			[]string{"CLAUDE_CODE_USE_BEDROCK=1"}, "",
+		},
+		{"bedrock without region", "bedrock", "claude", ProviderSettings{}, nil, nil, "needs a region"},
+		{"bedrock with another CLI", "bedrock", "opencode run", ProviderSettings{BedrockRegion: "us-east-1"}, nil, nil, "claude CLI"},
+		{
+			"azure endpoint URL",
+			"azure", "opencode run --model azure/gpt-4o", ProviderSettings{AzureResource: "https://contoso.openai.azure.com/"},
+			[][]string{{"AZURE_API_KEY=k1"}, {"AZURE_API_KEY=k2"}},
+			[]string{"AZURE_RESOURCE_NAME=contoso"}, "",
+		},
+		{
+			"azure key missing from a credential",
+			"azure", "opencode run --model azure/gpt-4o", ProviderSettings{AzureResource: "contoso"},
+			[][]string{{"AZURE_API_KEY=k1"}, {"OTHER=x"}},
+			nil, "needs an API key",
+		},
+		{"azure without resource", "azure", "opencode run --model azure/gpt-4o", ProviderSettings{}, nil, nil, "needs a resource"},
+		{
+			"local",
+			"local", "opencode run --model ollama/qwen3-coder", ProviderSettings{}, nil,
+			[]string{"OLLAMA_HOST=http://localhost:11434", "OPENAI_BASE_URL=http://localhost:11434/v1", "OPENAI_API_KEY=local"}, "",
+		},
+		{
+			"local llama.cpp server",
+			"local", "aider", ProviderSettings{LocalModelURL: "http://gpu-box:8080/"}, [][]string{{"OPENAI_API_KEY=x"}},
+			[]string{"OLLAMA_HOST=http://gpu-box:8080", "OPENAI_BASE_URL=http://gpu-box:8080/v1"}, "",
+		},
+		{"azure without deployment", "azure", "opencode run", ProviderSettings{AzureResource: "contoso"}, nil, nil, "azure/<deployment>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ProviderEnv(tt.agent, tt.command, tt.settings, tt.credentialEnv)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("ProviderEnv() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ProviderEnv() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ProviderEnv() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package worker
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// rateLimitScanBytes is how much of the end of a failed worker's output is
+// searched for a rate limit. The error is the last thing a CLI prints; the
+// rest is the worker's own work, which may well be about rate limits.
+const rateLimitScanBytes = 4000
+
+var (
+	rateLimitPattern = regexp.MustCompile(`(?i)\b429\b|too many requests|rate[_ -]?limit(ed)?\b|rate_limit_error|usage limit reached|quota exceeded|resource[_ ]exhausted|overloaded_error|ThrottlingException`)
+
+	// Claude Code prints "Claude AI usage limit reached|<unix reset time>"
+	usageLimitResetPattern = regexp.MustCompile(`(?i)usage limit reached\|(\d{10})\b`)
+	retryAfterPattern      = regexp.MustCompile(`(?i)retry[-_ ]after"?\s*[:=]\s*"?(\d+)`)
+	resetHeaderPattern     = regexp.MustCompile(`(?i)ratelimit-[a-z-]*reset"?\s*[:=]\s*"?([0-9TZ:.+-]+)`)
+	retryInPattern         = regexp.MustCompile(`(?i)(?:try again|retry|resets?) in (\d+(?:\.\d+)?)\s*(ms|s|sec|secs|seconds?|m|min|mins|minutes?|h|hours?)\b`)
+)
+
+// DetectRateLimit reports whether a failed worker's output says it hit a
+// provider rate limit, and how long until the limit resets, if the output
+// says (from a Retry-After or *-ratelimit-*-reset header, a Claude usage
+// limit, or "try again in 30s"). The wait is 0 when no reset is given.
+func DetectRateLimit(output string, now time.Time) (bool, time.Duration) {
+	if len(output) > rateLimitScanBytes {
+		output = output[len(output)-rateLimitScanBytes:]
+	}
+	if !rateLimitPattern.MatchString(output) {
+		return false, 0
+	}
+
+	if m := usageLimitResetPattern.FindStringSubmatch(output); m != nil {
+		epoch, _ := strconv.ParseInt(m[1], 10, 64)
+		return true, untilReset(time.Unix(epoch, 0), now)
+	}
+	if m := retryAfterPattern.FindStringSubmatch(output); m != nil {
+		seconds, _ := strconv.Atoi(m[1])
+		return true, time.Duration(seconds) * time.Second
+	}
+	if m := resetHeaderPattern.FindStringSubmatch(output); m != nil {
+		if reset, ok := parseReset(m[1], now); ok {
+			return true, untilReset(reset, now)
+		}
+	}
+	if m := retryInPattern.FindStringSubmatch(output); m != nil {
+		n, _ := strconv.ParseFloat(m[1], 64)
+		unit := time.Second
+		switch u := strings.ToLower(m[2]); {
+		case u == "ms":
+			unit = time.Millisecond
+		case strings.HasPrefix(u, "m"):
+			unit = time.Minute
+		case strings.HasPrefix(u, "h"):
+			unit = time.Hour
+		}
+		return true, time.Duration(n * float64(unit))
+	}
+	return true, 0
+}
+
+// parseReset reads a rate limit reset header value: an RFC 3339 time, a
+// unix time, or seconds from now.
+func parseReset(value string, now time.Time) (time.Time, bool) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, true
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	if n > 1_000_000_000 {
+		return time.Unix(n, 0), true
+	}
+	return now.Add(time.Duration(n) * time.Second), true
+}
+
+// untilReset returns the wait until reset, or 0 if it has passed.
+func untilReset(reset, now time.Time) time.Duration {
+	return max(reset.Sub(now), 0)
+}
@@ -0,0 +1,38 @@
+package worker
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDetectRateLimit(t *testing.T) {
+	now := time.Unix(1_800_000_000, 0)
+	tests := []struct {
+		name        string
+		output      string
+		wantLimited bool
+		wantWait    time.Duration
+	}{
+		{"ordinary failure", "FAIL: TestParse\nexit status 1", false, 0},
+		{"no reset given", "API Error: 429 Too Many Requests", true, 0},
+		{"retry-after header", `API Error: 429 {"type":"rate_limit_error"} retry-after: 30`, true, 30 * time.Second},
+		{"claude usage limit", "Claude AI usage limit reached|1800003600", true, time.Hour},
+		{"usage limit already reset", "Claude AI usage limit reached|1799999000", true, 0},
+		{"reset header as time", "rate limited; anthropic-ratelimit-requests-reset: 2027-01-15T08:02:00Z", true, 2 * time.Minute},
+		{"reset header as seconds", "Rate limit exceeded. x-ratelimit-reset: 90", true, 90 * time.Second},
+		{"try again in", "Rate limit reached. Please try again in 2.5s.", true, 2500 * time.Millisecond},
+		{"try again in minutes", "You exceeded your quota. Quota exceeded, try again in 3 minutes", true, 3 * time.Minute},
+		{"overloaded", `{"type":"error","error":{"type":"overloaded_error"}}`, true, 0},
+		{"bedrock throttling", "API Error: ThrottlingException: Too many tokens, please wait before trying again.", true, 0},
+		{"only the tail counts", "implemented the rate limiter\n" + strings.Repeat("x", rateLimitScanBytes) + "\nexit status 1", false, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limited, wait := DetectRateLimit(tt.output, now)
+			if limited != tt.wantLimited || wait != tt.wantWait {
+				t.Errorf("DetectRateLimit() = %v, %v, want %v, %v", limited, wait, tt.wantLimited, tt.wantWait)
+			}
+		})
+	}
+}
@@ -0,0 +1,247 @@
+package worker
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"brigade/internal/state"
+)
+
+// Recording is one captured worker execution.
+type Recording struct {
+	Seq          int              `json:"seq"`
+	Tier         state.WorkerTier `json:"tier"`
+	Timestamp    string           `json:"timestamp"`
+	PromptHash   string           `json:"promptHash"`
+	Prompt       string           `json:"prompt"`
+	Output       string           `json:"output,omitempty"`
+	ExitCode     int              `json:"exitCode,omitempty"`
+	DurationMs   int64            `json:"durationMs"`
+	Timeout      bool             `json:"timeout,omitempty"`
+	Crashed      bool             `json:"crashed,omitempty"`
+	RateLimited  bool             `json:"rateLimited,omitempty"`
+	RetryAfterMs int64            `json:"retryAfterMs,omitempty"`
+	AuthFailed   bool             `json:"authFailed,omitempty"`
+	Error        string           `json:"error,omitempty"`     // Result error
+	ExecError    string           `json:"execError,omitempty"` // Error returned by Execute
+}
+
+// hashPrompt returns a short stable hash identifying a prompt.
+func hashPrompt(prompt string) string {
+	sum := sha256.Sum256([]byte(prompt))
+	return hex.EncodeToString(sum[:8])
+}
+
+// Recorder appends every worker prompt/response pair to a JSONL file.
+type Recorder struct {
+	mu   sync.Mutex
+	file *os.File
+	seq  int
+}
+
+// NewRecorder opens (or creates) a recording file for appending.
+func NewRecorder(path string) (*Recorder, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("opening recording: %w", err)
+	}
+	return &Recorder{file: f}, nil
+}
+
+// Wrap returns a worker that records every execution of w.
+func (r *Recorder) Wrap(w Worker) Worker {
+	return &recordingWorker{Worker: w, recorder: r}
+}
+
+// Close closes the recording file.
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.file.Close()
+}
+
+func (r *Recorder) record(rec Recording) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.seq++
+	rec.Seq = r.seq
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return
+	}
+	r.file.Write(append(data, '\n'))
+}
+
+// recordingWorker passes executions through to the wrapped worker.
+type recordingWorker struct {
+	Worker
+	recorder *Recorder
+}
+
+func (w *recordingWorker) Execute(ctx context.Context, prompt string) (*Result, error) {
+	result, err := w.Worker.Execute(ctx, prompt)
+
+	rec := Recording{
+		Tier:       w.Tier(),
+		Timestamp:  time.Now().Format(time.RFC3339),
+		PromptHash: hashPrompt(prompt),
+		Prompt:     prompt,
+	}
+	if err != nil {
+		rec.ExecError = err.Error()
+	}
+	if result != nil {
+		rec.Output = result.Output
+		rec.ExitCode = result.ExitCode
+		rec.DurationMs = result.Duration.Milliseconds()
+		rec.Timeout = result.Timeout
+		rec.Crashed = result.Crashed
+		rec.RateLimited = result.RateLimited
+		rec.RetryAfterMs = result.RetryAfter.Milliseconds()
+		rec.AuthFailed = result.AuthFailed
+		if result.Error != nil {
+			rec.Error = result.Error.Error()
+		}
+	}
+	w.recorder.record(rec)
+
+	return result, err
+}
+
+// Replayer serves recorded responses back in place of real workers.
+// A prompt is matched by hash first; if the orchestrator now builds a
+// different prompt, the next unused recording for the tier is served instead.
+type Replayer struct {
+	mu         sync.Mutex
+	entries    []Recording
+	used       []bool
+	served     int
+	mismatched int
+}
+
+// LoadReplay reads a recording file.
+func LoadReplay(path string) (*Replayer, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening recording: %w", err)
+	}
+	defer f.Close()
+
+	r := &Replayer{}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 1024*1024), 64*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var rec Recording
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			return nil, fmt.Errorf("parsing recording line %d: %w", line, err)
+		}
+		r.entries = append(r.entries, rec)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading recording: %w", err)
+	}
+	r.used = make([]bool, len(r.entries))
+	return r, nil
+}
+
+// Factory returns a worker factory whose workers replay recordings.
+func (r *Replayer) Factory() *Factory {
+	f := NewFactory(DefaultConfig(state.TierLine), DefaultConfig(state.TierSous), DefaultConfig(state.TierExecutive))
+	f.newWorker = func(config *Config) Worker {
+		return &replayWorker{tier: config.Tier, replayer: r}
+	}
+	return f
+}
+
+// Stats returns how many recordings were served and how many of those did
+// not match the prompt exactly.
+func (r *Replayer) Stats() (served, mismatched int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.served, r.mismatched
+}
+
+// next returns the recording for a prompt, or nil if none remain for the tier.
+func (r *Replayer) next(tier state.WorkerTier, prompt string) *Recording {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	hash := hashPrompt(prompt)
+	fallback := -1
+	for i, rec := range r.entries {
+		if r.used[i] || rec.Tier != tier {
+			continue
+		}
+		if rec.PromptHash == hash {
+			r.used[i] = true
+			r.served++
+			return &r.entries[i]
+		}
+		if fallback < 0 {
+			fallback = i
+		}
+	}
+	if fallback < 0 {
+		return nil
+	}
+	r.used[fallback] = true
+	r.served++
+	r.mismatched++
+	return &r.entries[fallback]
+}
+
+// replayWorker serves recorded results for one tier.
+type replayWorker struct {
+	tier     state.WorkerTier
+	replayer *Replayer
+}
+
+func (w *replayWorker) Name() string {
+	return "replay"
+}
+
+func (w *replayWorker) Tier() state.WorkerTier {
+	return w.tier
+}
+
+func (w *replayWorker) Execute(ctx context.Context, prompt string) (*Result, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	rec := w.replayer.next(w.tier, prompt)
+	if rec == nil {
+		return nil, fmt.Errorf("replay exhausted: no recording left for %s tier", w.tier)
+	}
+	if rec.ExecError != "" {
+		return nil, errors.New(rec.ExecError)
+	}
+
+	result := ParseOutput(rec.Output)
+	result.ExitCode = rec.ExitCode
+	result.Duration = time.Duration(rec.DurationMs) * time.Millisecond
+	result.Timeout = rec.Timeout
+	result.Crashed = rec.Crashed
+	result.RateLimited = rec.RateLimited
+	result.RetryAfter = time.Duration(rec.RetryAfterMs) * time.Millisecond
+	result.AuthFailed = rec.AuthFailed
+	if rec.Error != "" {
+		result.Error = errors.New(rec.Error)
+	}
+	return result, nil
+}
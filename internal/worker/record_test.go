@@ -0,0 +1,61 @@
+package worker
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"brigade/internal/state"
+)
+
+func TestRecordReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+
+	recorder, err := NewRecorder(path)
+	if err != nil {
+		t.Fatalf("NewRecorder() error = %v", err)
+	}
+	script := NewMockScript(0)
+	script.EscalateTask = "US-002"
+	live := NewMockFactory(script).Wrap(recorder.Wrap)
+
+	prompts := []struct {
+		tier   state.WorkerTier
+		prompt string
+	}{
+		{state.TierLine, "=== TASK ===\nID: US-001\n"},
+		{state.TierLine, "=== TASK ===\nID: US-002\n"},
+		{state.TierSous, "=== TASK ===\nID: US-002\n"},
+	}
+	var want []*Result
+	for _, p := range prompts {
+		result, err := live.ForTier(p.tier).Execute(context.Background(), p.prompt)
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		want = append(want, result)
+	}
+	recorder.Close()
+
+	replayer, err := LoadReplay(path)
+	if err != nil {
+		t.Fatalf("LoadReplay() error = %v", err)
+	}
+	replay := replayer.Factory()
+	for i, p := range prompts {
+		got, err := replay.ForTier(p.tier).Execute(context.Background(), p.prompt)
+		if err != nil {
+			t.Fatalf("replay %d: Execute() error = %v", i, err)
+		}
+		if got.Output != want[i].Output || got.Promise != want[i].Promise {
+			t.Errorf("replay %d = %q/%q, want %q/%q", i, got.Output, got.Promise, want[i].Output, want[i].Promise)
+		}
+	}
+
+	if _, err := replay.Line().Execute(context.Background(), "anything"); err == nil {
+		t.Errorf("Execute() after exhaustion error = nil, want error")
+	}
+	if served, mismatched := replayer.Stats(); served != 3 || mismatched != 0 {
+		t.Errorf("Stats() = %d, %d, want 3, 0", served, mismatched)
+	}
+}
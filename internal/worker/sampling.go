@@ -0,0 +1,39 @@
+package worker
+
+import (
+	"strconv"
+)
+
+// Sampling is the sampling a deterministic run pins its workers to.
+type Sampling struct {
+	Seed        int64
+	Temperature float64
+}
+
+// Env returns the environment that passes the sampling to a worker, as
+// BRIGADE_SEED and BRIGADE_TEMPERATURE for worker scripts that call a
+// provider's API themselves.
+func (s Sampling) Env() []string {
+	return []string{
+		"BRIGADE_DETERMINISTIC=1",
+		"BRIGADE_SEED=" + strconv.FormatInt(s.Seed, 10),
+		"BRIGADE_TEMPERATURE=" + strconv.FormatFloat(s.Temperature, 'f', -1, 64),
+	}
+}
+
+// builtinAgents run CLIs that choose their own sampling and take no seed or
+// temperature.
+var builtinAgents = map[string]bool{
+	"claude":     true,
+	"opencode":   true,
+	AgentBedrock: true,
+	AgentAzure:   true,
+	AgentLocal:   true,
+}
+
+// SamplingPinned reports whether an agent's workers can be pinned to a
+// Sampling. Built-in agents can't; any other agent is taken to be a worker
+// script that reads Sampling.Env.
+func SamplingPinned(agent string) bool {
+	return !builtinAgents[agent]
+}
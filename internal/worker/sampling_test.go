@@ -0,0 +1,20 @@
+package worker
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSampling(t *testing.T) {
+	got := Sampling{Seed: 7, Temperature: 0}.Env()
+	want := []string{"BRIGADE_DETERMINISTIC=1", "BRIGADE_SEED=7", "BRIGADE_TEMPERATURE=0"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Env() = %v, want %v", got, want)
+	}
+
+	for agent, want := range map[string]bool{"claude": false, "opencode": false, "local": false, "api": true} {
+		if got := SamplingPinned(agent); got != want {
+			t.Errorf("SamplingPinned(%q) = %v, want %v", agent, got, want)
+		}
+	}
+}
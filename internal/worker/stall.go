@@ -0,0 +1,85 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// activityWriter records when output last passed through it.
+type activityWriter struct {
+	last atomic.Int64 // Unix nanoseconds
+}
+
+func newActivityWriter() *activityWriter {
+	a := &activityWriter{}
+	a.touch()
+	return a
+}
+
+func (a *activityWriter) touch() {
+	a.last.Store(time.Now().UnixNano())
+}
+
+func (a *activityWriter) Write(p []byte) (int, error) {
+	if len(p) > 0 {
+		a.touch()
+	}
+	return len(p), nil
+}
+
+// silence returns how long it has been since the last write.
+func (a *activityWriter) silence() time.Duration {
+	return time.Since(time.Unix(0, a.last.Load()))
+}
+
+// processDump lists a process and its descendants with their state, wait
+// channel and elapsed time, to show what a stalled worker was stuck on.
+// Returns "" if ps is unavailable.
+func processDump(pid int) string {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, "ps", "-A", "-o", "pid=,ppid=,stat=,wchan=,etime=,args=").Output()
+	if err != nil {
+		return ""
+	}
+
+	type proc struct {
+		ppid int
+		line string
+	}
+	procs := make(map[int]proc)
+	children := make(map[int][]int)
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		p, err1 := strconv.Atoi(fields[0])
+		pp, err2 := strconv.Atoi(fields[1])
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		procs[p] = proc{pp, strings.TrimSpace(line)}
+		children[pp] = append(children[pp], p)
+	}
+	if _, ok := procs[pid]; !ok {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("PID PPID STAT WCHAN ELAPSED COMMAND\n")
+	var walk func(p int, depth int)
+	walk = func(p int, depth int) {
+		fmt.Fprintf(&b, "%s%s\n", strings.Repeat("  ", depth), procs[p].line)
+		for _, c := range children[p] {
+			walk(c, depth+1)
+		}
+	}
+	walk(pid, 0)
+	return b.String()
+}
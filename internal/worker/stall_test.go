@@ -0,0 +1,59 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCLIWorkerStall(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		name        string
+		script      string
+		wantStalled bool
+	}{
+		{"silent worker is interrupted", "echo starting\nexec sleep 30\n", true},
+		{"chatty worker is left alone", "for i in 1 2 3 4 5 6; do echo working; sleep 0.1; done\necho '<promise>COMPLETE</promise>'\n", false},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			script := filepath.Join(dir, "worker"+string(rune('a'+i))+".sh")
+			os.WriteFile(script, []byte("#!/bin/sh\n"+tt.script), 0755)
+
+			w := NewCLIWorker(&Config{
+				Command:             script,
+				Timeout:             20 * time.Second,
+				Quiet:               true,
+				HealthCheckInterval: 50 * time.Millisecond,
+				ShutdownGrace:       time.Second,
+				StallTimeout:        400 * time.Millisecond,
+			})
+			start := time.Now()
+			result, err := w.Execute(context.Background(), "prompt")
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if result.Stalled != tt.wantStalled {
+				t.Errorf("Stalled = %v, want %v (error: %v)", result.Stalled, tt.wantStalled, result.Error)
+			}
+			if tt.wantStalled {
+				if !errors.Is(result.Error, ErrStalled) {
+					t.Errorf("Error = %v, want ErrStalled", result.Error)
+				}
+				if result.Success() {
+					t.Error("Success() = true for a stalled worker")
+				}
+				if elapsed := time.Since(start); elapsed > 10*time.Second {
+					t.Errorf("stall took %v to detect", elapsed)
+				}
+			} else if !result.IsComplete() {
+				t.Errorf("IsComplete() = false, output %q", result.Output)
+			}
+		})
+	}
+}
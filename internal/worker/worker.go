@@ -3,6 +3,7 @@ package worker
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"brigade/internal/state"
@@ -19,6 +20,13 @@ const (
 	PromiseNeedsIteration Promise = ""  // No explicit promise, needs another iteration
 )
 
+// ErrTimeout is wrapped by the Result error of a worker that ran out of time.
+var ErrTimeout = errors.New("worker timed out")
+
+// ErrStalled is wrapped by the Result error of a worker that was interrupted
+// after producing no output for its stall timeout.
+var ErrStalled = errors.New("worker stalled")
+
 // Result holds the output from a worker execution.
 type Result struct {
 	// Output is the full output from the worker
@@ -56,6 +64,46 @@ type Result struct {
 
 	// Crashed indicates unexpected process termination
 	Crashed bool
+
+	// Stalled indicates the worker went silent and was interrupted
+	Stalled bool
+
+	// StallDump is the worker's process state captured when it stalled
+	StallDump string
+
+	// Leftover lists processes the worker started that were still running
+	// after it exited, as "pid command"
+	Leftover []string
+
+	// Usage is the token count the worker reported, if it printed one
+	Usage Usage
+
+	// RateLimited indicates the worker failed on a provider rate limit
+	RateLimited bool
+
+	// RetryAfter is how long until the rate limit resets, if the worker's
+	// output said (0 when unknown)
+	RetryAfter time.Duration
+
+	// AuthFailed indicates the worker failed because its credential was
+	// rejected
+	AuthFailed bool
+
+	// Cached indicates the output is an earlier answer to the same prompt
+	// (see Cache); nothing was run and nothing was spent
+	Cached bool
+}
+
+// Usage counts the tokens a worker used.
+type Usage struct {
+	InputTokens     int
+	CacheReadTokens int
+	OutputTokens    int
+}
+
+// Reported returns true if the worker reported any token usage.
+func (u Usage) Reported() bool {
+	return u.InputTokens > 0 || u.CacheReadTokens > 0 || u.OutputTokens > 0
 }
 
 // IsComplete returns true if the worker signaled completion.
@@ -75,12 +123,12 @@ func (r *Result) IsAbsorbed() bool {
 
 // NeedsIteration returns true if another iteration is needed.
 func (r *Result) NeedsIteration() bool {
-	return r.Promise == PromiseNeedsIteration && r.Error == nil && !r.Timeout && !r.Crashed
+	return r.Promise == PromiseNeedsIteration && r.Error == nil && !r.Timeout && !r.Crashed && !r.Stalled
 }
 
 // Success returns true if the result represents successful completion.
 func (r *Result) Success() bool {
-	return r.Error == nil && !r.Timeout && !r.Crashed
+	return r.Error == nil && !r.Timeout && !r.Crashed && !r.Stalled
 }
 
 // Worker is the interface for AI workers.
@@ -115,6 +163,10 @@ type Config struct {
 	// Env are additional environment variables
 	Env []string
 
+	// MCPConfig is an MCP server config file passed to Claude CLI workers
+	// with --mcp-config (optional)
+	MCPConfig string
+
 	// LogPath is the path to write output logs (optional)
 	LogPath string
 
@@ -123,6 +175,14 @@ type Config struct {
 
 	// HealthCheckInterval is how often to check if the process is alive
 	HealthCheckInterval time.Duration
+
+	// ShutdownGrace is how long a cancelled worker gets after SIGTERM
+	// before it is killed (0 kills immediately)
+	ShutdownGrace time.Duration
+
+	// StallTimeout is how long the worker may go without writing any output
+	// before it is interrupted as stalled (0 disables)
+	StallTimeout time.Duration
 }
 
 // DefaultConfig returns a default worker configuration.
@@ -139,6 +199,7 @@ func DefaultConfig(tier state.WorkerTier) *Config {
 		Tier:                tier,
 		Timeout:             timeout,
 		HealthCheckInterval: 5 * time.Second,
+		ShutdownGrace:       10 * time.Second,
 	}
 }
 
@@ -147,6 +208,9 @@ type Factory struct {
 	lineConfig      *Config
 	sousConfig      *Config
 	executiveConfig *Config
+
+	// newWorker builds a worker from a tier config (CLI workers by default)
+	newWorker func(*Config) Worker
 }
 
 // NewFactory creates a worker factory.
@@ -155,22 +219,39 @@ func NewFactory(line, sous, exec *Config) *Factory {
 		lineConfig:      line,
 		sousConfig:      sous,
 		executiveConfig: exec,
+		newWorker: func(config *Config) Worker {
+			return NewCLIWorker(config)
+		},
+	}
+}
+
+// Wrap returns a factory whose workers are passed through wrap, e.g. to
+// record executions.
+func (f *Factory) Wrap(wrap func(Worker) Worker) *Factory {
+	inner := f.newWorker
+	return &Factory{
+		lineConfig:      f.lineConfig,
+		sousConfig:      f.sousConfig,
+		executiveConfig: f.executiveConfig,
+		newWorker: func(config *Config) Worker {
+			return wrap(inner(config))
+		},
 	}
 }
 
 // Line creates a line cook worker.
 func (f *Factory) Line() Worker {
-	return NewCLIWorker(f.lineConfig)
+	return f.newWorker(f.lineConfig)
 }
 
 // Sous creates a sous chef worker.
 func (f *Factory) Sous() Worker {
-	return NewCLIWorker(f.sousConfig)
+	return f.newWorker(f.sousConfig)
 }
 
 // Executive creates an executive chef worker.
 func (f *Factory) Executive() Worker {
-	return NewCLIWorker(f.executiveConfig)
+	return f.newWorker(f.executiveConfig)
 }
 
 // ForTier returns a worker for the given tier.
@@ -186,3 +267,26 @@ func (f *Factory) ForTier(tier state.WorkerTier) Worker {
 		return f.Line()
 	}
 }
+
+// ForAttempt returns a worker for the given tier that runs in dir (if set)
+// with env added to its environment, e.g. one of the tier's credentials.
+func (f *Factory) ForAttempt(tier state.WorkerTier, dir string, env []string) Worker {
+	if dir == "" && len(env) == 0 {
+		return f.ForTier(tier)
+	}
+
+	var config Config
+	switch tier {
+	case state.TierSous:
+		config = *f.sousConfig
+	case state.TierExecutive:
+		config = *f.executiveConfig
+	default:
+		config = *f.lineConfig
+	}
+	if dir != "" {
+		config.WorkingDir = dir
+	}
+	config.Env = append(append([]string(nil), config.Env...), env...)
+	return f.newWorker(&config)
+}
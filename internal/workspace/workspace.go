@@ -0,0 +1,195 @@
+// Package workspace detects monorepo workspaces and scopes tasks to one
+// package within them.
+package workspace
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"brigade/internal/util"
+)
+
+// Kind identifies the tool that defines a workspace.
+type Kind string
+
+const (
+	KindGoWork Kind = "go.work"
+	KindPnpm   Kind = "pnpm"
+	KindNx     Kind = "nx"
+)
+
+// maxNxDepth limits how deep Detect looks for Nx project.json files.
+const maxNxDepth = 4
+
+// Workspace is a monorepo and its member packages.
+type Workspace struct {
+	Kind    Kind
+	Members []string // Slash-separated paths relative to the repo root
+}
+
+// Detect returns the workspace defined in root, or nil if root isn't a
+// monorepo. go.work is checked first, then pnpm-workspace.yaml, then nx.json.
+func Detect(root string) *Workspace {
+	if members, err := goWorkMembers(filepath.Join(root, "go.work")); err == nil {
+		return &Workspace{Kind: KindGoWork, Members: members}
+	}
+	if patterns, err := pnpmPatterns(filepath.Join(root, "pnpm-workspace.yaml")); err == nil {
+		return &Workspace{Kind: KindPnpm, Members: expandPatterns(root, patterns)}
+	}
+	if _, err := os.Stat(filepath.Join(root, "nx.json")); err == nil {
+		return &Workspace{Kind: KindNx, Members: nxProjects(root)}
+	}
+	return nil
+}
+
+// Contains reports whether dir is a workspace member.
+func (w *Workspace) Contains(dir string) bool {
+	for _, m := range w.Members {
+		if m == dir {
+			return true
+		}
+	}
+	return false
+}
+
+// MapPath returns where the codebase map for a workspace is kept.
+func MapPath(dir string) string {
+	return filepath.Join("brigade", "codebase-map-"+util.Slugify(dir, 50)+".md")
+}
+
+// Outside returns the paths in changed that are not inside dir.
+func Outside(changed []string, dir string) []string {
+	prefix := strings.TrimSuffix(dir, "/") + "/"
+	var outside []string
+	for _, p := range changed {
+		if !strings.HasPrefix(filepath.ToSlash(p), prefix) {
+			outside = append(outside, p)
+		}
+	}
+	return outside
+}
+
+// goWorkMembers reads the use directives of a go.work file.
+func goWorkMembers(file string) ([]string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var members []string
+	inBlock := false
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = strings.TrimSpace(line[:i])
+		}
+		switch {
+		case line == "use (":
+			inBlock = true
+		case inBlock && line == ")":
+			inBlock = false
+		case inBlock && line != "":
+			members = append(members, cleanMember(line))
+		case strings.HasPrefix(line, "use "):
+			members = append(members, cleanMember(strings.TrimPrefix(line, "use ")))
+		}
+	}
+	return members, scanner.Err()
+}
+
+// pnpmPatterns reads the packages list of a pnpm-workspace.yaml file.
+func pnpmPatterns(file string) ([]string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var patterns []string
+	inPackages := false
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, "packages:"):
+			inPackages = true
+		case inPackages && strings.HasPrefix(trimmed, "- "):
+			pattern := strings.Trim(strings.TrimSpace(strings.TrimPrefix(trimmed, "- ")), `'"`)
+			if !strings.HasPrefix(pattern, "!") {
+				patterns = append(patterns, pattern)
+			}
+		case inPackages && trimmed != "" && !strings.HasPrefix(trimmed, "#") && !strings.HasPrefix(line, " "):
+			inPackages = false
+		}
+	}
+	return patterns, scanner.Err()
+}
+
+// expandPatterns expands package globs to the directories they match.
+// "**" is treated as "*"; deeper nesting isn't expanded.
+func expandPatterns(root string, patterns []string) []string {
+	seen := make(map[string]bool)
+	var members []string
+	for _, pattern := range patterns {
+		pattern = strings.ReplaceAll(pattern, "**", "*")
+		matches, _ := filepath.Glob(filepath.Join(root, pattern))
+		for _, m := range matches {
+			if info, err := os.Stat(m); err != nil || !info.IsDir() {
+				continue
+			}
+			rel, err := filepath.Rel(root, m)
+			if err != nil {
+				continue
+			}
+			rel = filepath.ToSlash(rel)
+			if !seen[rel] {
+				seen[rel] = true
+				members = append(members, rel)
+			}
+		}
+	}
+	sort.Strings(members)
+	return members
+}
+
+// nxProjects finds the directories holding an Nx project.json.
+func nxProjects(root string) []string {
+	var members []string
+	filepath.WalkDir(root, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		rel, _ := filepath.Rel(root, p)
+		if d.IsDir() {
+			name := d.Name()
+			if rel != "." && (strings.HasPrefix(name, ".") || name == "node_modules" || name == "dist") {
+				return filepath.SkipDir
+			}
+			if strings.Count(filepath.ToSlash(rel), "/") >= maxNxDepth {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.Name() == "project.json" {
+			if dir := filepath.ToSlash(filepath.Dir(rel)); dir != "." {
+				members = append(members, dir)
+			}
+		}
+		return nil
+	})
+	sort.Strings(members)
+	return members
+}
+
+// cleanMember normalizes a go.work use path.
+func cleanMember(p string) string {
+	p = strings.Trim(strings.TrimSpace(p), `"`)
+	return path.Clean(filepath.ToSlash(p))
+}
@@ -0,0 +1,89 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeFiles creates files (and their directories) under root.
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		kind  Kind
+		want  []string
+	}{
+		{
+			name: "go.work",
+			files: map[string]string{
+				"go.work": "go 1.22\n\nuse (\n\t./services/api // the API\n\t./tools\n)\nuse ./cli\n",
+			},
+			kind: KindGoWork,
+			want: []string{"services/api", "tools", "cli"},
+		},
+		{
+			name: "pnpm",
+			files: map[string]string{
+				"pnpm-workspace.yaml":     "packages:\n  - 'apps/*'\n  - \"libs/**\"\n  - '!apps/legacy'\ncatalog:\n  react: ^18\n",
+				"apps/web/package.json":   "{}",
+				"apps/admin/package.json": "{}",
+				"libs/ui/package.json":    "{}",
+			},
+			kind: KindPnpm,
+			want: []string{"apps/admin", "apps/web", "libs/ui"},
+		},
+		{
+			name: "nx",
+			files: map[string]string{
+				"nx.json":                         "{}",
+				"apps/shop/project.json":          "{}",
+				"libs/cart/project.json":          "{}",
+				"node_modules/x/project.json":     "{}",
+				"apps/shop/src/app/component.tsx": "",
+			},
+			kind: KindNx,
+			want: []string{"apps/shop", "libs/cart"},
+		},
+	}
+
+	for _, tt := range tests {
+		root := t.TempDir()
+		writeFiles(t, root, tt.files)
+		ws := Detect(root)
+		if ws == nil {
+			t.Errorf("%s: Detect() = nil", tt.name)
+			continue
+		}
+		if ws.Kind != tt.kind || strings.Join(ws.Members, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("%s: Detect() = %s %v, want %s %v", tt.name, ws.Kind, ws.Members, tt.kind, tt.want)
+		}
+	}
+
+	if ws := Detect(t.TempDir()); ws != nil {
+		t.Errorf("Detect() on a plain repo = %+v, want nil", ws)
+	}
+}
+
+func TestOutside(t *testing.T) {
+	changed := []string{"services/api/main.go", "services/api-gateway/main.go", "README.md"}
+	got := Outside(changed, "services/api")
+	want := "services/api-gateway/main.go,README.md"
+	if strings.Join(got, ",") != want {
+		t.Errorf("Outside() = %v, want %s", got, want)
+	}
+}
@@ -0,0 +1,191 @@
+// Package worktree runs a PRD in its own git worktree and branch, and brings
+// the branch up to date with the default branch when the PRD is done.
+package worktree
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Root returns the top directory of the git repository containing dir.
+func Root(dir string) (string, error) {
+	out, err := git(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", fmt.Errorf("not in a git repository: %w", err)
+	}
+	return out, nil
+}
+
+// Dir returns where a PRD's worktree goes: <parent>/<prefix>, where parent
+// defaults to "<repo>-worktrees" beside the repository.
+func Dir(root, parent, prefix string) string {
+	if parent == "" {
+		parent = root + "-worktrees"
+	} else if !filepath.IsAbs(parent) {
+		parent = filepath.Join(root, parent)
+	}
+	return filepath.Join(parent, prefix)
+}
+
+// Ensure creates a worktree at dir on branch, starting the branch from base
+// if it doesn't exist yet. A worktree an earlier run left at dir is reused.
+// Returns whether the worktree was created.
+func Ensure(root, dir, branch, base string) (bool, error) {
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+		current, err := git(dir, "rev-parse", "--abbrev-ref", "HEAD")
+		if err != nil {
+			return false, err
+		}
+		if current != branch {
+			return false, fmt.Errorf("%s is a worktree for %s, not %s", dir, current, branch)
+		}
+		return false, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dir), 0755); err != nil {
+		return false, err
+	}
+	args := []string{"worktree", "add", dir, branch}
+	if _, err := git(root, "rev-parse", "--verify", "--quiet", "refs/heads/"+branch); err != nil {
+		args = []string{"worktree", "add", "-b", branch, dir}
+		if base != "" {
+			args = append(args, base)
+		}
+	}
+	if _, err := git(root, args...); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// DefaultBranch returns the branch a PRD's branch is rebased onto: the
+// configured one, else origin's HEAD, else main or master.
+func DefaultBranch(dir, configured string) string {
+	if configured != "" {
+		return configured
+	}
+	if ref, err := git(dir, "symbolic-ref", "--short", "refs/remotes/origin/HEAD"); err == nil {
+		return strings.TrimPrefix(ref, "origin/")
+	}
+	for _, branch := range []string{"main", "master"} {
+		if _, err := git(dir, "rev-parse", "--verify", "--quiet", "refs/heads/"+branch); err == nil {
+			return branch
+		}
+	}
+	return ""
+}
+
+// Upstream fetches branch from origin and returns the ref to rebase onto:
+// origin's copy if there is one, else the local branch.
+func Upstream(dir, branch string) string {
+	if _, err := git(dir, "fetch", "--quiet", "origin", branch); err == nil {
+		if _, err := git(dir, "rev-parse", "--verify", "--quiet", "refs/remotes/origin/"+branch); err == nil {
+			return "origin/" + branch
+		}
+	}
+	return branch
+}
+
+// Behind returns how many commits onto has that dir's HEAD doesn't.
+func Behind(dir, onto string) int {
+	out, err := git(dir, "rev-list", "--count", "HEAD.."+onto)
+	if err != nil {
+		return 0
+	}
+	n, _ := strconv.Atoi(out)
+	return n
+}
+
+// Rebase starts rebasing dir's branch onto onto, setting uncommitted
+// changes aside until it's done. It stops at the first commit that
+// conflicts, returning the conflicted files; no conflicts means the rebase
+// is done.
+func Rebase(dir, onto string) ([]string, error) {
+	return step(dir, "rebase", "--autostash", onto)
+}
+
+// Replaying returns the short hash and subject of the commit a stopped
+// rebase is replaying.
+func Replaying(dir string) string {
+	out, _ := git(dir, "log", "-1", "--format=%h %s", "REBASE_HEAD")
+	return out
+}
+
+// Continue stages the resolved files and continues a stopped rebase,
+// returning the next commit's conflicts like Rebase.
+func Continue(dir string, resolved []string) ([]string, error) {
+	if _, err := git(dir, append([]string{"add", "--"}, resolved...)...); err != nil {
+		return nil, err
+	}
+	return step(dir, "-c", "core.editor=true", "rebase", "--continue")
+}
+
+// Abort abandons a stopped rebase, leaving the branch as it was.
+func Abort(dir string) {
+	git(dir, "rebase", "--abort")
+}
+
+func step(dir string, args ...string) ([]string, error) {
+	_, err := git(dir, args...)
+	conflicts := Conflicts(dir)
+	if len(conflicts) > 0 {
+		return conflicts, nil
+	}
+	return nil, err
+}
+
+// Conflicts returns the files with unresolved conflicts in dir.
+func Conflicts(dir string) []string {
+	out, err := git(dir, "diff", "--name-only", "--diff-filter=U")
+	if err != nil || out == "" {
+		return nil
+	}
+	return strings.Split(out, "\n")
+}
+
+// ConflictSize counts the lines inside conflict markers in a file's
+// content. ok is false if the file has no markers, as with conflicts over a
+// deleted or binary file, which can't be resolved by editing.
+func ConflictSize(content string) (lines int, ok bool) {
+	inside := false
+	for _, line := range strings.Split(content, "\n") {
+		switch {
+		case strings.HasPrefix(line, "<<<<<<< "):
+			inside, ok = true, true
+		case strings.HasPrefix(line, ">>>>>>> "):
+			inside = false
+		case inside && !strings.HasPrefix(line, "=======") && !strings.HasPrefix(line, "||||||| "):
+			lines++
+		}
+	}
+	return lines, ok
+}
+
+// HasMarkers reports whether content still has conflict markers.
+func HasMarkers(content string) bool {
+	for _, line := range strings.Split(content, "\n") {
+		if strings.HasPrefix(line, "<<<<<<< ") || strings.HasPrefix(line, ">>>>>>> ") {
+			return true
+		}
+	}
+	return false
+}
+
+func git(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		msg := strings.TrimSpace(string(out))
+		if msg == "" {
+			return "", err
+		}
+		return "", errors.New(msg)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
@@ -0,0 +1,99 @@
+package worktree
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestDir(t *testing.T) {
+	tests := []struct {
+		parent string
+		want   string
+	}{
+		{"", "/src/app-worktrees/auth"},
+		{"../trees", "/src/trees/auth"},
+		{"/tmp/trees", "/tmp/trees/auth"},
+	}
+
+	for _, tt := range tests {
+		if got := Dir("/src/app", tt.parent, "auth"); got != tt.want {
+			t.Errorf("Dir(%q) = %q, want %q", tt.parent, got, tt.want)
+		}
+	}
+}
+
+func TestConflictSize(t *testing.T) {
+	tests := []struct {
+		content string
+		lines   int
+		ok      bool
+	}{
+		{"no conflicts\n", 0, false},
+		{"a\n<<<<<<< HEAD\nb\n=======\nc\n>>>>>>> abc (US-001)\nd\n", 2, true},
+		{"<<<<<<< HEAD\nb\n||||||| base\nx\n=======\nc\nd\n>>>>>>> abc\n", 4, true},
+	}
+
+	for _, tt := range tests {
+		lines, ok := ConflictSize(tt.content)
+		if lines != tt.lines || ok != tt.ok {
+			t.Errorf("ConflictSize(%q) = %d, %v, want %d, %v", tt.content, lines, ok, tt.lines, tt.ok)
+		}
+	}
+}
+
+func TestRebase(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	root := t.TempDir()
+	run := func(dir string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	commit := func(dir, file, content string) {
+		t.Helper()
+		os.WriteFile(filepath.Join(dir, file), []byte(content), 0644)
+		run(dir, "add", file)
+		run(dir, "commit", "-qm", file)
+	}
+	run(root, "init", "-q", "-b", "main")
+	run(root, "config", "user.email", "test@example.com")
+	run(root, "config", "user.name", "test")
+	commit(root, "a.txt", "one\n")
+
+	dir := Dir(root, "", "auth")
+	if created, err := Ensure(root, dir, "feature/auth", "main"); err != nil || !created {
+		t.Fatalf("Ensure() = %v, %v, want true, nil", created, err)
+	}
+	if created, err := Ensure(root, dir, "feature/auth", "main"); err != nil || created {
+		t.Fatalf("Ensure() again = %v, %v, want false, nil", created, err)
+	}
+	if got := DefaultBranch(dir, ""); got != "main" {
+		t.Errorf("DefaultBranch() = %q, want main", got)
+	}
+
+	commit(dir, "a.txt", "two\n")
+	commit(root, "b.txt", "b\n")
+	commit(root, "a.txt", "three\n")
+	if got := Behind(dir, "main"); got != 2 {
+		t.Errorf("Behind() = %d, want 2", got)
+	}
+
+	conflicts, err := Rebase(dir, "main")
+	if err != nil || len(conflicts) != 1 || conflicts[0] != "a.txt" {
+		t.Fatalf("Rebase() = %v, %v, want [a.txt]", conflicts, err)
+	}
+	os.WriteFile(filepath.Join(dir, "a.txt"), []byte("two\nthree\n"), 0644)
+	if conflicts, err := Continue(dir, []string{"a.txt"}); err != nil || len(conflicts) > 0 {
+		t.Fatalf("Continue() = %v, %v, want no conflicts", conflicts, err)
+	}
+	if got := Behind(dir, "main"); got != 0 {
+		t.Errorf("Behind() after rebase = %d, want 0", got)
+	}
+}
@@ -3,10 +3,12 @@
 # Enable in brigade.config: MODULES="example"
 
 # REQUIRED: Declare which events this module handles
-# Available: service_start, task_start, task_complete, task_blocked,
-#            task_absorbed, task_already_done, task_slow, escalation, review,
-#            verification, attention, decision_needed, decision_received,
-#            scope_decision, service_complete
+# Available: service_start, cost_estimate, task_start, task_complete,
+#            task_blocked, task_iteration, task_absorbed, task_skipped,
+#            escalation, review, verification, artifacts, attention,
+#            decision_needed, decision_received, decision_fallback,
+#            scope_decision, rate_limited, throttled, heartbeat,
+#            service_complete
 module_example_events() {
   echo "task_complete service_complete"
 }